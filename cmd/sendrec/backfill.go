@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/video"
+)
+
+// parseBackfillSince reads the backfill-analytics flags. An empty --since
+// means the whole history.
+func parseBackfillSince(args []string) (time.Time, error) {
+	fs := flag.NewFlagSet("backfill-analytics", flag.ContinueOnError)
+	sinceFlag := fs.String("since", "", "only rebuild days from this date on (YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return time.Time{}, err
+	}
+	if *sinceFlag == "" {
+		return time.Time{}, nil
+	}
+	since, err := time.Parse("2006-01-02", *sinceFlag)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: expected YYYY-MM-DD", *sinceFlag)
+	}
+	return since, nil
+}
+
+// runBackfillAnalytics rebuilds the daily analytics rollups from the raw view,
+// milestone and CTA click rows, then exits.
+func runBackfillAnalytics(databaseURL string, args []string) {
+	since, err := parseBackfillSince(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	db, err := database.Connect(connectCtx, databaseURL)
+	if err != nil {
+		log.Fatalf("database connection failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Migrate(databaseURL); err != nil {
+		log.Fatalf("database migration failed: %v", err)
+	}
+
+	started := time.Now()
+	days, err := video.BackfillAnalyticsRollups(ctx, db.Pool, since)
+	if err != nil {
+		slog.Error("analytics backfill failed", "days", days, "error", err)
+		os.Exit(1)
+	}
+	slog.Info("analytics backfill complete", "days", days, "duration", time.Since(started).Round(time.Millisecond))
+}
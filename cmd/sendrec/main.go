@@ -40,6 +40,11 @@ func main() {
 		log.Fatal("DATABASE_URL is required")
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "backfill-analytics" {
+		runBackfillAnalytics(databaseURL, os.Args[2:])
+		return
+	}
+
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		log.Fatal("JWT_SECRET is required")
@@ -114,6 +119,8 @@ func main() {
 		OnboardingDay7TemplateID:   int(getEnvInt64("LISTMONK_ONBOARDING_DAY7_TEMPLATE_ID", 0)),
 		OrgInviteTemplateID:        int(getEnvInt64("LISTMONK_ORG_INVITE_TEMPLATE_ID", 0)),
 		RetentionWarningTemplateID: int(getEnvInt64("LISTMONK_RETENTION_WARNING_TEMPLATE_ID", 0)),
+		ViewerVerifyTemplateID:     int(getEnvInt64("LISTMONK_VIEWER_VERIFY_TEMPLATE_ID", 0)),
+		ReportTemplateID:           int(getEnvInt64("LISTMONK_REPORT_TEMPLATE_ID", 0)),
 		Allowlist:                  email.ParseAllowlist(os.Getenv("EMAIL_ALLOWLIST")),
 		DeveloperEmail:             os.Getenv("DEVELOPER_EMAIL"),
 		FromAddress:                getEnv("EMAIL_FROM_ADDRESS", "noreply@sendrec.eu"),
@@ -128,6 +135,33 @@ func main() {
 
 	aiEnabled := getEnv("AI_ENABLED", "false") == "true"
 
+	var aiClient *video.AIClient
+	if aiEnabled {
+		aiTimeout := 60 * time.Second
+		if v := os.Getenv("AI_TIMEOUT"); v != "" {
+			if d, err := time.ParseDuration(v); err == nil {
+				aiTimeout = d
+			}
+		}
+		aiClient = video.NewAIClient(
+			os.Getenv("AI_BASE_URL"),
+			os.Getenv("AI_API_KEY"),
+			getEnv("AI_MODEL", "mistral-small-latest"),
+			aiTimeout,
+		)
+		slog.Info("AI summaries enabled", "model", getEnv("AI_MODEL", "mistral-small-latest"), "timeout", aiTimeout.String())
+	}
+
+	// Comment spam scoring: links and blocklisted terms always, the AI model
+	// too when it is configured and opted into.
+	spamScorers := []video.SpamScorer{video.NewHeuristicSpamScorer(
+		int(getEnvInt64("COMMENT_SPAM_MAX_LINKS", 2)),
+		strings.Split(os.Getenv("COMMENT_SPAM_BLOCKLIST"), ","),
+	)}
+	if aiClient != nil && getEnv("COMMENT_SPAM_AI", "false") == "true" {
+		spamScorers = append(spamScorers, video.NewAISpamScorer(aiClient))
+	}
+
 	slackClient := slackpkg.New(db.Pool)
 	webhookClient := webhookpkg.New(db.Pool)
 
@@ -147,6 +181,7 @@ func main() {
 
 	registrationEnabled := getEnv("REGISTRATION_ENABLED", "true") == "true"
 	planBadgeEnabled := getEnv("PLAN_BADGE_ENABLED", "false") == "true"
+	liveHub := video.NewLiveHub()
 
 	srv := server.New(server.Config{
 		DB:                        db.Pool,
@@ -161,6 +196,8 @@ func main() {
 		MaxVideosPerMonth:         int(getEnvInt64("MAX_VIDEOS_PER_MONTH", int64(plans.Free.MaxVideosPerMonth))),
 		MaxVideoDurationSeconds:   int(getEnvInt64("MAX_VIDEO_DURATION_SECONDS", int64(plans.Free.MaxVideoDurationSeconds))),
 		MaxPlaylists:              int(getEnvInt64("MAX_PLAYLISTS", int64(plans.Free.MaxPlaylists))),
+		VideoReplyMaxBytes:        getEnvInt64("VIDEO_REPLY_MAX_BYTES", video.DefaultVideoReplyMaxBytes),
+		VideoReplyMaxSeconds:      int(getEnvInt64("VIDEO_REPLY_MAX_SECONDS", video.DefaultVideoReplyMaxSeconds)),
 		S3PublicEndpoint:          os.Getenv("S3_PUBLIC_ENDPOINT"),
 		EnableDocs:                getEnv("API_DOCS_ENABLED", "false") == "true",
 		BrandingEnabled:           getEnv("BRANDING_ENABLED", "false") == "true",
@@ -169,10 +206,15 @@ func main() {
 		NoiseReductionFilter:      os.Getenv("NOISE_REDUCTION_FILTER"),
 		AllowedFrameAncestors:     os.Getenv("ALLOWED_FRAME_ANCESTORS"),
 		AnalyticsScript:           strings.ReplaceAll(os.Getenv("ANALYTICS_SCRIPT"), `\"`, `"`),
+		AnalyticsPrivacyMode:      getEnv("ANALYTICS_PRIVACY_MODE", "false") == "true",
+		LiveHub:                   liveHub,
 		EmailSender:               emailClient,
 		CommentNotifier:           emailClient,
 		ViewNotifier:              emailClient,
+		ViewerVerifier:            emailClient,
+		ThreadReplyNotifier:       emailClient,
 		SlackNotifier:             slackClient,
+		CommentSpamScorer:         video.NewCombinedSpamScorer(spamScorers...),
 		WebhookClient:             webhookClient,
 		CreemAPIKey:               creemAPIKey,
 		CreemWebhookSecret:        creemWebhookSecret,
@@ -193,23 +235,6 @@ func main() {
 		slog.Info("Creem billing enabled")
 	}
 
-	var aiClient *video.AIClient
-	if aiEnabled {
-		aiTimeout := 60 * time.Second
-		if v := os.Getenv("AI_TIMEOUT"); v != "" {
-			if d, err := time.ParseDuration(v); err == nil {
-				aiTimeout = d
-			}
-		}
-		aiClient = video.NewAIClient(
-			os.Getenv("AI_BASE_URL"),
-			os.Getenv("AI_API_KEY"),
-			getEnv("AI_MODEL", "mistral-small-latest"),
-			aiTimeout,
-		)
-		slog.Info("AI summaries enabled", "model", getEnv("AI_MODEL", "mistral-small-latest"), "timeout", aiTimeout.String())
-	}
-
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
 	defer cleanupCancel()
 	video.StartCleanupLoop(cleanupCtx, db.Pool, store, 10*time.Minute)
@@ -228,6 +253,9 @@ func main() {
 	video.StartTranscodeWorker(cleanupCtx, db.Pool, store, 2*time.Minute)
 	video.StartOnboardingWorker(cleanupCtx, db.Pool, emailClient, baseURL)
 	video.StartRetentionWorker(cleanupCtx, db.Pool, emailClient, baseURL)
+	video.StartReportWorker(cleanupCtx, db.Pool, emailClient, slackClient, baseURL)
+	video.StartAnalyticsRollupWorker(cleanupCtx, db.Pool, time.Minute)
+	video.StartLiveListener(cleanupCtx, db.Pool, liveHub)
 
 	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%s", port),
@@ -237,6 +265,7 @@ func main() {
 		WriteTimeout:      120 * time.Second,
 		IdleTimeout:       120 * time.Second,
 	}
+	httpServer.RegisterOnShutdown(liveHub.Close)
 
 	shutdownCh := make(chan os.Signal, 1)
 	signal.Notify(shutdownCh, syscall.SIGINT, syscall.SIGTERM)
@@ -37,3 +37,22 @@ func TestGetEnvReturnsFallbackWhenEmpty(t *testing.T) {
 		t.Errorf("expected fallback %q for empty env var, got %q", fallback, result)
 	}
 }
+
+func TestParseBackfillSince(t *testing.T) {
+	since, err := parseBackfillSince(nil)
+	if err != nil || !since.IsZero() {
+		t.Errorf("expected zero time for no flags, got %v, %v", since, err)
+	}
+
+	since, err = parseBackfillSince([]string{"--since", "2025-03-01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if since.Format("2006-01-02") != "2025-03-01" {
+		t.Errorf("expected 2025-03-01, got %v", since)
+	}
+
+	if _, err := parseBackfillSince([]string{"--since", "March"}); err == nil {
+		t.Error("expected error for malformed date")
+	}
+}
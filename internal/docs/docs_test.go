@@ -65,6 +65,15 @@ func TestSpecContainsAllEndpoints(t *testing.T) {
 		"/api/videos",
 		"/api/videos/limits",
 		"/api/videos/{id}",
+		"/api/videos/{id}/analytics/sessions",
+		"/api/videos/{id}/analytics/sessions/{sessionId}",
+		"/api/videos/{id}/analytics/live",
+		"/api/analytics/dashboard/live",
+		"/api/analytics/org",
+		"/api/analytics/org/export",
+		"/api/analytics/query",
+		"/api/analytics/reports",
+		"/api/analytics/reports/{reportId}",
 		"/api/videos/{id}/extend",
 		"/api/videos/{id}/download",
 		"/api/videos/{id}/trim",
@@ -74,10 +83,39 @@ func TestSpecContainsAllEndpoints(t *testing.T) {
 		"/api/videos/{id}/comment-mode",
 		"/api/videos/{id}/comments",
 		"/api/videos/{id}/comments/{commentId}",
+		"/api/videos/{id}/comments/{commentId}/resolved",
+		"/api/videos/{id}/comments/{commentId}/approve",
+		"/api/videos/{id}/comments/{commentId}/reject",
+		"/api/videos/{id}/comments/{commentId}/ban",
+		"/api/videos/comments/moderation",
+		"/api/videos/comments/bans",
+		"/api/videos/comments/bans/{banId}",
+		"/api/videos/{id}/email-gate",
+		"/api/videos/{id}/embed-settings",
+		"/api/videos/{id}/embed-secret",
+		"/api/videos/{id}/slug",
+		"/api/videos/{id}/visibility",
+		"/api/folders/{id}/visibility",
+		"/api/organizations/{orgId}/domain",
+		"/api/organizations/{orgId}/domain/verify",
 		"/api/watch/{shareToken}",
 		"/api/watch/{shareToken}/download",
 		"/api/watch/{shareToken}/verify",
 		"/api/watch/{shareToken}/comments",
+		"/api/watch/{shareToken}/video-replies",
+		"/api/watch/{shareToken}/video-replies/{commentId}/complete",
+		"/api/videos/{id}/video-replies",
+		"/api/videos/{id}/ctas",
+		"/api/videos/{id}/ctas/{ctaId}",
+		"/api/videos/{id}/questions",
+		"/api/videos/{id}/questions/{questionId}",
+		"/api/watch/{shareToken}/answers",
+		"/api/watch/{shareToken}/identify/verify",
+		"/api/playlists/{id}/feed-tokens",
+		"/api/playlists/{id}/feed-tokens/{tokenId}",
+		"/feed/playlist/{shareToken}",
+		"/api/oembed",
+		"/api/watch/{shareToken}/segments",
 	}
 
 	for _, ep := range endpoints {
@@ -4,12 +4,18 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"html"
+	"io"
 	"log/slog"
+	"mime"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"net/smtp"
+	"net/textproto"
 	"net/url"
 	"os/exec"
 	"strconv"
@@ -30,6 +36,8 @@ type Config struct {
 	OnboardingDay7TemplateID   int
 	OrgInviteTemplateID        int
 	RetentionWarningTemplateID int
+	ViewerVerifyTemplateID     int
+	ReportTemplateID           int
 	Allowlist                  []string
 	DeveloperEmail             string
 	FromAddress                string
@@ -123,6 +131,14 @@ type txRequest struct {
 	Data            map[string]any `json:"data"`
 	ContentType     string         `json:"content_type"`
 	subject         string         // unexported; used only for sendmail fallback
+	attachments     []Attachment   // unexported; sent as multipart, not in the JSON body
+}
+
+// Attachment is a file sent along with an email.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
 }
 
 // DigestVideoSummary represents a single video in a digest email.
@@ -255,10 +271,10 @@ func (c *Client) sendTx(ctx context.Context, body txRequest) error {
 
 	if c.config.BaseURL == "" {
 		if c.config.SMTPHost != "" {
-			return c.sendViaSMTP(ctx, body.SubscriberEmail, body.subject, body.Body)
+			return c.sendViaSMTP(ctx, body.SubscriberEmail, body.subject, body.Body, body.attachments...)
 		}
 		if c.sendmailAvailable {
-			return c.sendViaSendmail(ctx, body.SubscriberEmail, body.subject, body.Body)
+			return c.sendViaSendmail(ctx, body.SubscriberEmail, body.subject, body.Body, body.attachments...)
 		}
 		slog.Warn("no email backend configured, dropping message", "to", body.SubscriberEmail, "subject", body.subject)
 		return nil
@@ -269,19 +285,46 @@ func (c *Client) sendTx(ctx context.Context, body txRequest) error {
 		return fmt.Errorf("marshal email request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/api/tx", bytes.NewReader(jsonBody))
+	// Listmonk takes attachments as a multipart upload, with the JSON
+	// request in the "data" field.
+	payload, contentType := io.Reader(bytes.NewReader(jsonBody)), "application/json"
+	if len(body.attachments) > 0 {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		if err := mw.WriteField("data", string(jsonBody)); err != nil {
+			return fmt.Errorf("encode email request: %w", err)
+		}
+		for _, a := range body.attachments {
+			part, err := mw.CreatePart(textproto.MIMEHeader{
+				"Content-Disposition": {mime.FormatMediaType("form-data", map[string]string{"name": "file", "filename": a.Filename})},
+				"Content-Type":        {a.ContentType},
+			})
+			if err != nil {
+				return fmt.Errorf("encode email attachment: %w", err)
+			}
+			if _, err := part.Write(a.Data); err != nil {
+				return fmt.Errorf("encode email attachment: %w", err)
+			}
+		}
+		if err := mw.Close(); err != nil {
+			return fmt.Errorf("encode email request: %w", err)
+		}
+		payload, contentType = &buf, mw.FormDataContentType()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/api/tx", payload)
 	if err != nil {
 		return fmt.Errorf("create email request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	req.SetBasicAuth(c.config.Username, c.config.Password)
 
 	resp, err := c.http.Do(req)
 	if err != nil {
 		if c.sendmailAvailable {
 			slog.Warn("listmonk request failed, falling back to sendmail", "error", err)
-			return c.sendViaSendmail(ctx, body.SubscriberEmail, body.subject, body.Body)
+			return c.sendViaSendmail(ctx, body.SubscriberEmail, body.subject, body.Body, body.attachments...)
 		}
 		return fmt.Errorf("listmonk send: %w", err)
 	}
@@ -290,7 +333,7 @@ func (c *Client) sendTx(ctx context.Context, body txRequest) error {
 	if resp.StatusCode != http.StatusOK {
 		if c.sendmailAvailable {
 			slog.Warn("listmonk returned error, falling back to sendmail", "status", resp.StatusCode)
-			return c.sendViaSendmail(ctx, body.SubscriberEmail, body.subject, body.Body)
+			return c.sendViaSendmail(ctx, body.SubscriberEmail, body.subject, body.Body, body.attachments...)
 		}
 		return fmt.Errorf("listmonk send: status %d", resp.StatusCode)
 	}
@@ -324,7 +367,36 @@ func validateMessageHeaders(from, to, subject string) error {
 	return nil
 }
 
-func (c *Client) sendViaSMTP(ctx context.Context, to, subject, htmlBody string) error {
+// buildMessage renders the full message for SMTP and sendmail: a plain HTML
+// message, or multipart/mixed when there are attachments.
+func buildMessage(from, to, subject, htmlBody string, attachments []Attachment) string {
+	headers := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n", from, to, subject)
+	if len(attachments) == 0 {
+		return headers + "Content-Type: text/html; charset=UTF-8\r\n\r\n" + htmlBody
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=UTF-8"}})
+	_, _ = part.Write([]byte(htmlBody))
+	for _, a := range attachments {
+		part, _ = mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(a.ContentType, map[string]string{"name": a.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			_, _ = part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		_, _ = part.Write([]byte(encoded + "\r\n"))
+	}
+	_ = mw.Close()
+	return headers + fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary()) + buf.String()
+}
+
+func (c *Client) sendViaSMTP(ctx context.Context, to, subject, htmlBody string, attachments ...Attachment) error {
 	// SMTPTLS, SMTPPort and FromAddress are normalised at construction; trust them here.
 	host := c.config.SMTPHost
 	port := c.config.SMTPPort
@@ -399,9 +471,7 @@ func (c *Client) sendViaSMTP(ctx context.Context, to, subject, htmlBody string)
 	if err != nil {
 		return fmt.Errorf("smtp data: %w", err)
 	}
-	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s",
-		from, to, subject, htmlBody)
-	if _, err := wc.Write([]byte(msg)); err != nil {
+	if _, err := wc.Write([]byte(buildMessage(from, to, subject, htmlBody, attachments))); err != nil {
 		return fmt.Errorf("smtp write: %w", err)
 	}
 	if err := wc.Close(); err != nil {
@@ -410,15 +480,14 @@ func (c *Client) sendViaSMTP(ctx context.Context, to, subject, htmlBody string)
 	return nil
 }
 
-func (c *Client) sendViaSendmail(ctx context.Context, to, subject, htmlBody string) error {
+func (c *Client) sendViaSendmail(ctx context.Context, to, subject, htmlBody string, attachments ...Attachment) error {
 	if _, err := exec.LookPath("sendmail"); err != nil {
 		slog.Warn("sendmail not available, skipping email", "to", to, "subject", subject)
 		return nil
 	}
 
 	// header CRLF rejection happens upstream in sendTx.
-	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s",
-		c.config.FromAddress, to, subject, htmlBody)
+	msg := buildMessage(c.config.FromAddress, to, subject, htmlBody, attachments)
 
 	cmd := exec.CommandContext(ctx, "sendmail", "-t")
 	cmd.Stdin = strings.NewReader(msg)
@@ -491,6 +560,45 @@ func (c *Client) SendCommentNotification(ctx context.Context, toEmail, toName, v
 	return c.sendTx(ctx, tx)
 }
 
+// SendThreadReplyNotification tells an earlier participant in a comment
+// thread about a new reply. Unlike owner notifications it carries a link to
+// stop emails for that thread.
+func (c *Client) SendThreadReplyNotification(ctx context.Context, toEmail, toName, videoTitle, commentAuthor, commentBody, watchURL, unsubscribeURL string) error {
+	if !c.isAllowed(toEmail) {
+		return nil
+	}
+
+	if c.config.BaseURL != "" {
+		c.ensureSubscriber(ctx, toEmail, toName)
+	}
+
+	tx := txRequest{
+		SubscriberEmail: toEmail,
+		Data: map[string]any{
+			"name":           toName,
+			"videoTitle":     videoTitle,
+			"commentAuthor":  commentAuthor,
+			"commentBody":    commentBody,
+			"watchURL":       watchURL,
+			"unsubscribeURL": unsubscribeURL,
+			"isThreadReply":  "true",
+		},
+		ContentType: "html",
+		subject:     "New reply in a comment thread",
+		Body: fmt.Sprintf(
+			`<p>Hi %s,</p><p><strong>%s</strong> replied in a thread you joined on <strong>%s</strong>:</p><blockquote>%s</blockquote><p><a href="%s">View video</a></p><p><a href="%s">Stop emails about this thread</a></p>`,
+			html.EscapeString(toName), html.EscapeString(commentAuthor), html.EscapeString(videoTitle), html.EscapeString(commentBody),
+			html.EscapeString(watchURL), html.EscapeString(unsubscribeURL),
+		),
+	}
+
+	if c.config.CommentTemplateID != 0 {
+		tx.TemplateID = c.config.CommentTemplateID
+	}
+
+	return c.sendTx(ctx, tx)
+}
+
 func (c *Client) SendViewNotification(ctx context.Context, toEmail, toName, videoTitle, watchURL string, viewCount int) error {
 	if !c.isAllowed(toEmail) {
 		return nil
@@ -733,3 +841,118 @@ func (c *Client) SendRetentionWarning(ctx context.Context, toEmail string, video
 
 	return c.sendTx(ctx, tx)
 }
+
+// SendViewerVerification sends a watch-page viewer the one-time code and
+// magic link that confirm their address for a verified email gate. Like
+// confirmation emails it bypasses the allowlist: the viewer asked for it.
+func (c *Client) SendViewerVerification(ctx context.Context, toEmail, videoTitle, code, verifyLink string) error {
+	if c.config.BaseURL != "" {
+		c.ensureSubscriber(ctx, toEmail, "")
+	}
+
+	tx := txRequest{
+		SubscriberEmail: toEmail,
+		Data: map[string]any{
+			"videoTitle": videoTitle,
+			"code":       code,
+			"verifyLink": verifyLink,
+		},
+		ContentType: "html",
+		subject:     "Your code to watch a video on SendRec",
+		Body: fmt.Sprintf(
+			`<p>Hi,</p><p>Your code to watch <strong>%s</strong> is:</p><p style="font-size:24px;letter-spacing:4px"><strong>%s</strong></p><p>Or <a href="%s">open the video</a> directly. The code expires in 15 minutes.</p><p>If you didn't request this, you can ignore this email.</p>`,
+			html.EscapeString(videoTitle), code, verifyLink,
+		),
+	}
+
+	if c.config.ViewerVerifyTemplateID != 0 {
+		tx.TemplateID = c.config.ViewerVerifyTemplateID
+	}
+
+	return c.sendTx(ctx, tx)
+}
+
+// ReportVideoSummary is one of the top videos in a scheduled report.
+type ReportVideoSummary struct {
+	Title       string `json:"title"`
+	Views       int    `json:"views"`
+	UniqueViews int    `json:"uniqueViews"`
+	Completion  int    `json:"completion"`
+	CtaClicks   int    `json:"ctaClicks"`
+	WatchURL    string `json:"watchURL"`
+}
+
+// ReportViewer is an email-gate viewer identified for the first time during
+// a report's period.
+type ReportViewer struct {
+	Email      string `json:"email"`
+	VideoTitle string `json:"videoTitle"`
+}
+
+// ScheduledReport is the content of one weekly or monthly analytics report.
+// Completion is the share of unique viewers who reached the end, and
+// CtaRate the CTA clicks per view, both as percentages.
+type ScheduledReport struct {
+	Name           string               `json:"name"`
+	Frequency      string               `json:"frequency"`
+	Period         string               `json:"period"`
+	TotalViews     int                  `json:"totalViews"`
+	UniqueViews    int                  `json:"uniqueViews"`
+	Completion     int                  `json:"completion"`
+	CtaClicks      int                  `json:"ctaClicks"`
+	CtaRate        float64              `json:"ctaRate"`
+	TopVideos      []ReportVideoSummary `json:"topVideos"`
+	NewViewers     []ReportViewer       `json:"newViewers"`
+	NewViewerCount int                  `json:"newViewerCount"`
+	DashboardURL   string               `json:"dashboardURL"`
+}
+
+func (c *Client) SendScheduledReport(ctx context.Context, toEmail string, report ScheduledReport, attachment *Attachment) error {
+	if !c.isAllowed(toEmail) {
+		return nil
+	}
+
+	if c.config.BaseURL != "" {
+		c.ensureSubscriber(ctx, toEmail, "")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<p><strong>%s</strong> · %s</p>`, html.EscapeString(report.Name), html.EscapeString(report.Period))
+	fmt.Fprintf(&b, `<p>%d views (%d unique) · %d%% completion · %d CTA clicks (%.1f%% of views) · %d new identified viewers</p>`,
+		report.TotalViews, report.UniqueViews, report.Completion, report.CtaClicks, report.CtaRate, report.NewViewerCount)
+	if len(report.TopVideos) > 0 {
+		b.WriteString(`<p><strong>Top videos</strong></p><ul>`)
+		for _, v := range report.TopVideos {
+			fmt.Fprintf(&b, `<li><a href="%s">%s</a> — %d views, %d%% completion, %d CTA clicks</li>`,
+				html.EscapeString(v.WatchURL), html.EscapeString(v.Title), v.Views, v.Completion, v.CtaClicks)
+		}
+		b.WriteString(`</ul>`)
+	}
+	if len(report.NewViewers) > 0 {
+		b.WriteString(`<p><strong>New identified viewers</strong></p><ul>`)
+		for _, v := range report.NewViewers {
+			fmt.Fprintf(&b, `<li>%s watched %s</li>`, html.EscapeString(v.Email), html.EscapeString(v.VideoTitle))
+		}
+		b.WriteString(`</ul>`)
+	}
+	fmt.Fprintf(&b, `<p><a href="%s">Open analytics</a></p>`, html.EscapeString(report.DashboardURL))
+
+	tx := txRequest{
+		SubscriberEmail: toEmail,
+		Data: map[string]any{
+			"report": report,
+		},
+		ContentType: "html",
+		subject:     fmt.Sprintf("%s: %s", report.Name, report.Period),
+		Body:        b.String(),
+	}
+	if attachment != nil {
+		tx.attachments = []Attachment{*attachment}
+	}
+
+	if c.config.ReportTemplateID != 0 {
+		tx.TemplateID = c.config.ReportTemplateID
+	}
+
+	return c.sendTx(ctx, tx)
+}
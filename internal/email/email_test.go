@@ -184,6 +184,41 @@ func TestSendCommentNotification_FallbackBodyWhenTemplateIDZero(t *testing.T) {
 	}
 }
 
+func TestSendThreadReplyNotification_IncludesUnsubscribeLink(t *testing.T) {
+	var received txRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handleExistingSubscriber(t, w, r) {
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := New(Config{
+		BaseURL:  srv.URL,
+		Username: "user",
+		Password: "pass",
+	})
+
+	unsubscribeURL := "https://example.com/watch/abc/comments/c1/unsubscribe?token=t"
+	err := client.SendThreadReplyNotification(context.Background(),
+		"alice@example.com", "Alice", "My Video", "Bob", "<b>hi</b>", "https://example.com/watch/abc", unsubscribeURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.Data["unsubscribeURL"] != unsubscribeURL {
+		t.Errorf("expected unsubscribeURL in template data, got %v", received.Data["unsubscribeURL"])
+	}
+	if !strings.Contains(received.Body, "Stop emails about this thread") {
+		t.Errorf("expected unsubscribe link in body, got %q", received.Body)
+	}
+	if strings.Contains(received.Body, "<b>hi</b>") {
+		t.Errorf("expected comment body to be escaped, got %q", received.Body)
+	}
+}
+
 func TestSendPasswordReset_StillUsesTemplateID(t *testing.T) {
 	var received txRequest
 
@@ -1403,3 +1438,98 @@ func TestDeveloperEmail_NotSetSendsToOriginal(t *testing.T) {
 		t.Errorf("expected original email user@real.com, got %q", received.SubscriberEmail)
 	}
 }
+
+func TestSendViewerVerification_BypassesAllowlist(t *testing.T) {
+	var received txRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handleExistingSubscriber(t, w, r) {
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := New(Config{
+		BaseURL:                srv.URL,
+		Username:               "user",
+		Password:               "pass",
+		ViewerVerifyTemplateID: 11,
+		Allowlist:              []string{"@sendrec.eu"},
+	})
+
+	err := client.SendViewerVerification(context.Background(),
+		"viewer@example.com", "Q3 <Roadmap>", "123456", "https://app.sendrec.eu/watch/abc/verify-email?token=xyz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.TemplateID != 11 {
+		t.Errorf("expected template_id=11, got %d", received.TemplateID)
+	}
+	if received.SubscriberEmail != "viewer@example.com" {
+		t.Errorf("expected subscriber email viewer@example.com, got %q", received.SubscriberEmail)
+	}
+	if received.Data["code"] != "123456" || received.Data["verifyLink"] != "https://app.sendrec.eu/watch/abc/verify-email?token=xyz" {
+		t.Errorf("expected code and verifyLink in data, got %v", received.Data)
+	}
+	if !strings.Contains(received.Body, "Q3 &lt;Roadmap&gt;") {
+		t.Errorf("expected escaped video title in fallback body, got %q", received.Body)
+	}
+}
+
+func TestSendScheduledReport_UploadsAttachmentToListmonk(t *testing.T) {
+	var data, file, filename string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handleExistingSubscriber(t, w, r) {
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("expected a multipart request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data = r.FormValue("data")
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("expected a file part: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(f)
+		file, filename = string(body), header.Filename
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := New(Config{BaseURL: srv.URL, Username: "user", Password: "pass", ReportTemplateID: 12})
+	report := ScheduledReport{
+		Name:       "Sales videos",
+		Frequency:  "weekly",
+		Period:     "2–8 Mar 2026",
+		TotalViews: 40,
+		TopVideos:  []ReportVideoSummary{{Title: "Demo", Views: 30, WatchURL: "https://example.com/watch/abc"}},
+	}
+	attachment := &Attachment{Filename: "report.csv", ContentType: "text/csv", Data: []byte("Date,Views\n2026-03-02,40\n")}
+
+	if err := client.SendScheduledReport(context.Background(), "alice@example.com", report, attachment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed struct {
+		TemplateID int `json:"template_id"`
+		Data       struct {
+			Report ScheduledReport `json:"report"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(data), &parsed); err != nil {
+		t.Fatalf("unmarshal data field: %v", err)
+	}
+	if parsed.TemplateID != 12 || parsed.Data.Report.TotalViews != 40 || parsed.Data.Report.TopVideos[0].Title != "Demo" {
+		t.Errorf("unexpected request data %+v", parsed)
+	}
+	if filename != "report.csv" || file != "Date,Views\n2026-03-02,40\n" {
+		t.Errorf("unexpected attachment %q: %q", filename, file)
+	}
+}
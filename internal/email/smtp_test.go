@@ -612,3 +612,33 @@ func waitForMessages(t *testing.T, s *fakeSMTPServer, n int) []capturedMessage {
 	}
 	return s.Captured()
 }
+
+func TestSendTx_SMTP_SendsAttachmentAsMIMEPart(t *testing.T) {
+	s := newFakeSMTPServer(t)
+	host, port := splitHostPort(t, s.Addr())
+
+	client := New(Config{SMTPHost: host, SMTPPort: port, SMTPTLS: "none", FromAddress: "noreply@sendrec.eu"})
+	report := ScheduledReport{Name: "Weekly <report>", Period: "2–8 Mar 2026", DashboardURL: "https://app.sendrec.eu/analytics"}
+	attachment := &Attachment{Filename: "report.csv", ContentType: "text/csv", Data: []byte("Date,Views\n")}
+
+	if err := client.SendScheduledReport(context.Background(), "alice@example.com", report, attachment); err != nil {
+		t.Fatalf("SendScheduledReport: %v", err)
+	}
+
+	msgs := waitForMessages(t, s, 1)
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	data := msgs[0].data
+	for _, want := range []string{
+		"Subject: Weekly <report>: 2–8 Mar 2026",
+		"Content-Type: multipart/mixed; boundary=",
+		"Weekly &lt;report&gt;",
+		`Content-Disposition: attachment; filename=report.csv`,
+		"RGF0ZSxWaWV3cwo=",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %q in message: %q", want, data)
+		}
+	}
+}
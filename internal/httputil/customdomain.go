@@ -0,0 +1,21 @@
+package httputil
+
+import "context"
+
+const customDomainKey contextKey = "custom-domain"
+
+// CustomDomain describes a request that arrived on an organization's verified
+// custom hostname rather than the instance's BASE_URL.
+type CustomDomain struct {
+	OrganizationID string
+	BaseURL        string
+}
+
+func ContextWithCustomDomain(ctx context.Context, d CustomDomain) context.Context {
+	return context.WithValue(ctx, customDomainKey, d)
+}
+
+func CustomDomainFromContext(ctx context.Context) (CustomDomain, bool) {
+	d, ok := ctx.Value(customDomainKey).(CustomDomain)
+	return d, ok
+}
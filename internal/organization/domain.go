@@ -0,0 +1,252 @@
+package organization
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	domainVerificationPrefix = "_sendrec-verify."
+	domainVerificationValue  = "sendrec-verify="
+	domainLookupTimeout      = 10 * time.Second
+)
+
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]([a-z0-9-]*[a-z0-9])?$`)
+
+type domainResponse struct {
+	Hostname   string  `json:"hostname"`
+	Verified   bool    `json:"verified"`
+	VerifiedAt *string `json:"verifiedAt"`
+	TXTName    string  `json:"txtName"`
+	TXTValue   string  `json:"txtValue"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+type setDomainRequest struct {
+	Hostname string `json:"hostname"`
+}
+
+func newDomainResponse(hostname, token string, verifiedAt *time.Time, createdAt time.Time) domainResponse {
+	resp := domainResponse{
+		Hostname:  hostname,
+		Verified:  verifiedAt != nil,
+		TXTName:   domainVerificationPrefix + hostname,
+		TXTValue:  domainVerificationValue + token,
+		CreatedAt: createdAt.Format(time.RFC3339),
+	}
+	if verifiedAt != nil {
+		formatted := verifiedAt.Format(time.RFC3339)
+		resp.VerifiedAt = &formatted
+	}
+	return resp
+}
+
+// normalizeHostname lowercases a hostname and rejects anything that is not a
+// plain multi-label DNS name: no scheme, port, path, IP address or the
+// instance's own host.
+func (h *Handler) normalizeHostname(raw string) (string, string) {
+	hostname := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(raw)), ".")
+	if hostname == "" {
+		return "", "hostname is required"
+	}
+	if len(hostname) > 253 || !hostnamePattern.MatchString(hostname) || net.ParseIP(hostname) != nil {
+		return "", "invalid hostname"
+	}
+	if base, err := url.Parse(h.baseURL); err == nil && strings.EqualFold(base.Hostname(), hostname) {
+		return "", "hostname is already the instance's own domain"
+	}
+	return hostname, ""
+}
+
+func generateDomainToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (h *Handler) GetDomain(w http.ResponseWriter, r *http.Request) {
+	orgID := auth.OrgIDFromContext(r.Context())
+	if RequireRole(w, r, "owner", "admin") == "" {
+		return
+	}
+
+	var hostname, token string
+	var verifiedAt *time.Time
+	var createdAt time.Time
+	err := h.db.QueryRow(r.Context(),
+		`SELECT hostname, verification_token, verified_at, created_at FROM organization_domains WHERE organization_id = $1`,
+		orgID,
+	).Scan(&hostname, &token, &verifiedAt, &createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			httputil.WriteError(w, http.StatusNotFound, "no custom domain configured")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to fetch custom domain")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, newDomainResponse(hostname, token, verifiedAt, createdAt))
+}
+
+// SetDomain registers or replaces the organization's custom hostname. A new
+// hostname always starts unverified with a fresh TXT token.
+func (h *Handler) SetDomain(w http.ResponseWriter, r *http.Request) {
+	orgID := auth.OrgIDFromContext(r.Context())
+	if RequireRole(w, r, "owner", "admin") == "" {
+		return
+	}
+
+	var req setDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	hostname, msg := h.normalizeHostname(req.Hostname)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	token, err := generateDomainToken()
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to generate verification token")
+		return
+	}
+
+	// Unverified claims don't hold a hostname, so only another workspace's
+	// verified domain blocks this one.
+	var createdAt time.Time
+	err = h.db.QueryRow(r.Context(),
+		`INSERT INTO organization_domains (organization_id, hostname, verification_token)
+		 SELECT $1, $2, $3
+		 WHERE NOT EXISTS (
+		   SELECT 1 FROM organization_domains
+		   WHERE hostname = $2 AND verified_at IS NOT NULL AND organization_id <> $1
+		 )
+		 ON CONFLICT (organization_id) DO UPDATE
+		 SET hostname = EXCLUDED.hostname, verification_token = EXCLUDED.verification_token,
+		     verified_at = NULL, created_at = now()
+		 RETURNING created_at`,
+		orgID, hostname, token,
+	).Scan(&createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			httputil.WriteError(w, http.StatusConflict, "hostname is already registered")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to save custom domain")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, newDomainResponse(hostname, token, nil, createdAt))
+}
+
+// VerifyDomain checks for the TXT record at _sendrec-verify.<hostname> and
+// marks the domain verified when it carries the expected token.
+func (h *Handler) VerifyDomain(w http.ResponseWriter, r *http.Request) {
+	orgID := auth.OrgIDFromContext(r.Context())
+	if RequireRole(w, r, "owner", "admin") == "" {
+		return
+	}
+
+	var hostname, token string
+	var verifiedAt *time.Time
+	var createdAt time.Time
+	err := h.db.QueryRow(r.Context(),
+		`SELECT hostname, verification_token, verified_at, created_at FROM organization_domains WHERE organization_id = $1`,
+		orgID,
+	).Scan(&hostname, &token, &verifiedAt, &createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			httputil.WriteError(w, http.StatusNotFound, "no custom domain configured")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to fetch custom domain")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), domainLookupTimeout)
+	defer cancel()
+	records, err := h.resolver.LookupTXT(ctx, domainVerificationPrefix+hostname)
+	if err != nil || !containsTXTValue(records, domainVerificationValue+token) {
+		httputil.WriteError(w, http.StatusUnprocessableEntity, "verification TXT record not found")
+		return
+	}
+
+	// Proving DNS control takes the hostname over from other workspaces'
+	// unverified claims on it.
+	err = database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(r.Context(),
+			`DELETE FROM organization_domains
+			 WHERE hostname = $1 AND organization_id <> $2 AND verified_at IS NULL`,
+			hostname, orgID,
+		); err != nil {
+			return err
+		}
+		return tx.QueryRow(r.Context(),
+			`UPDATE organization_domains SET verified_at = COALESCE(verified_at, now())
+			 WHERE organization_id = $1 AND verification_token = $2
+			 RETURNING verified_at`,
+			orgID, token,
+		).Scan(&verifiedAt)
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			httputil.WriteError(w, http.StatusConflict, "hostname is already verified by another organization")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to verify custom domain")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, newDomainResponse(hostname, token, verifiedAt, createdAt))
+}
+
+func (h *Handler) DeleteDomain(w http.ResponseWriter, r *http.Request) {
+	orgID := auth.OrgIDFromContext(r.Context())
+	if RequireRole(w, r, "owner", "admin") == "" {
+		return
+	}
+
+	tag, err := h.db.Exec(r.Context(),
+		`DELETE FROM organization_domains WHERE organization_id = $1`, orgID,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to delete custom domain")
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "no custom domain configured")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func containsTXTValue(records []string, want string) bool {
+	for _, rec := range records {
+		if strings.TrimSpace(rec) == want {
+			return true
+		}
+	}
+	return false
+}
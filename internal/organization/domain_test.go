@@ -0,0 +1,265 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+type stubTXTResolver struct {
+	records map[string][]string
+	lookups []string
+}
+
+func (s *stubTXTResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	s.lookups = append(s.lookups, name)
+	if recs, ok := s.records[name]; ok {
+		return recs, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func expectOrgRole(mock pgxmock.PgxPoolIface, role string) {
+	mock.ExpectQuery(`SELECT om\.role FROM organization_members om`).
+		WithArgs("org-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"role"}).AddRow(role))
+}
+
+func serveDomainRoute(db pgxmock.PgxPoolIface, handler http.HandlerFunc, method, pattern, target string, body []byte, t *testing.T) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware(), PathMiddleware(db)).MethodFunc(method, pattern, handler)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, method, target, body))
+	return rec
+}
+
+func TestNormalizeHostname(t *testing.T) {
+	handler := NewHandler(nil, testBaseURL)
+
+	got, msg := handler.normalizeHostname(" Video.TheirBrand.com. ")
+	if msg != "" || got != "video.theirbrand.com" {
+		t.Errorf("expected video.theirbrand.com, got %q (%s)", got, msg)
+	}
+
+	for _, bad := range []string{"", "localhost", "https://video.example.com", "video.example.com:8443", "192.168.1.10", "video.example.com/watch", "sendrec.eu"} {
+		if _, msg := handler.normalizeHostname(bad); msg == "" {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestSetDomain(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, testBaseURL)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	expectOrgRole(mock, "admin")
+	mock.ExpectQuery(`INSERT INTO organization_domains`).
+		WithArgs("org-1", "video.theirbrand.com", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(now))
+
+	rec := serveDomainRoute(mock, handler.SetDomain, http.MethodPut, "/api/organizations/{orgId}/domain",
+		"/api/organizations/org-1/domain", []byte(`{"hostname":"Video.TheirBrand.com"}`), t)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, want := range []string{`"hostname":"video.theirbrand.com"`, `"verified":false`, `"txtName":"_sendrec-verify.video.theirbrand.com"`, `"txtValue":"sendrec-verify=`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in body, got %s", want, body)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetDomain_HostnameTaken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, testBaseURL)
+
+	expectOrgRole(mock, "owner")
+	mock.ExpectQuery(`INSERT INTO organization_domains`).
+		WithArgs("org-1", "video.theirbrand.com", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"created_at"}))
+
+	rec := serveDomainRoute(mock, handler.SetDomain, http.MethodPut, "/api/organizations/{orgId}/domain",
+		"/api/organizations/org-1/domain", []byte(`{"hostname":"video.theirbrand.com"}`), t)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSetDomain_MemberForbidden(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, testBaseURL)
+
+	expectOrgRole(mock, "member")
+
+	rec := serveDomainRoute(mock, handler.SetDomain, http.MethodPut, "/api/organizations/{orgId}/domain",
+		"/api/organizations/org-1/domain", []byte(`{"hostname":"video.theirbrand.com"}`), t)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestVerifyDomain_RecordPresent(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	resolver := &stubTXTResolver{records: map[string][]string{
+		"_sendrec-verify.video.theirbrand.com": {"google-site-verification=x", "sendrec-verify=tok123"},
+	}}
+	handler := NewHandler(mock, testBaseURL)
+	handler.SetTXTResolver(resolver)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	expectOrgRole(mock, "admin")
+	mock.ExpectQuery(`SELECT hostname, verification_token, verified_at, created_at FROM organization_domains`).
+		WithArgs("org-1").
+		WillReturnRows(pgxmock.NewRows([]string{"hostname", "verification_token", "verified_at", "created_at"}).
+			AddRow("video.theirbrand.com", "tok123", (*time.Time)(nil), now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM organization_domains\s+WHERE hostname = \$1 AND organization_id <> \$2 AND verified_at IS NULL`).
+		WithArgs("video.theirbrand.com", "org-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectQuery(`UPDATE organization_domains SET verified_at = COALESCE\(verified_at, now\(\)\)`).
+		WithArgs("org-1", "tok123").
+		WillReturnRows(pgxmock.NewRows([]string{"verified_at"}).AddRow(&now))
+	mock.ExpectCommit()
+
+	rec := serveDomainRoute(mock, handler.VerifyDomain, http.MethodPost, "/api/organizations/{orgId}/domain/verify",
+		"/api/organizations/org-1/domain/verify", nil, t)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"verified":true`) {
+		t.Errorf("expected verified domain, got %s", rec.Body.String())
+	}
+	if len(resolver.lookups) != 1 || resolver.lookups[0] != "_sendrec-verify.video.theirbrand.com" {
+		t.Errorf("unexpected lookups: %v", resolver.lookups)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestVerifyDomain_HostnameVerifiedElsewhere(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	resolver := &stubTXTResolver{records: map[string][]string{
+		"_sendrec-verify.video.theirbrand.com": {"sendrec-verify=tok123"},
+	}}
+	handler := NewHandler(mock, testBaseURL)
+	handler.SetTXTResolver(resolver)
+
+	expectOrgRole(mock, "admin")
+	mock.ExpectQuery(`SELECT hostname, verification_token, verified_at, created_at FROM organization_domains`).
+		WithArgs("org-1").
+		WillReturnRows(pgxmock.NewRows([]string{"hostname", "verification_token", "verified_at", "created_at"}).
+			AddRow("video.theirbrand.com", "tok123", (*time.Time)(nil), time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM organization_domains`).
+		WithArgs("video.theirbrand.com", "org-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectQuery(`UPDATE organization_domains SET verified_at`).
+		WithArgs("org-1", "tok123").
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	rec := serveDomainRoute(mock, handler.VerifyDomain, http.MethodPost, "/api/organizations/{orgId}/domain/verify",
+		"/api/organizations/org-1/domain/verify", nil, t)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestVerifyDomain_RecordMissing(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	resolver := &stubTXTResolver{records: map[string][]string{
+		"_sendrec-verify.video.theirbrand.com": {"sendrec-verify=stale"},
+	}}
+	handler := NewHandler(mock, testBaseURL)
+	handler.SetTXTResolver(resolver)
+
+	expectOrgRole(mock, "admin")
+	mock.ExpectQuery(`SELECT hostname, verification_token, verified_at, created_at FROM organization_domains`).
+		WithArgs("org-1").
+		WillReturnRows(pgxmock.NewRows([]string{"hostname", "verification_token", "verified_at", "created_at"}).
+			AddRow("video.theirbrand.com", "tok123", (*time.Time)(nil), time.Now()))
+
+	rec := serveDomainRoute(mock, handler.VerifyDomain, http.MethodPost, "/api/organizations/{orgId}/domain/verify",
+		"/api/organizations/org-1/domain/verify", nil, t)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteDomain_NotConfigured(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, testBaseURL)
+
+	expectOrgRole(mock, "owner")
+	mock.ExpectExec(`DELETE FROM organization_domains WHERE organization_id = \$1`).
+		WithArgs("org-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	rec := serveDomainRoute(mock, handler.DeleteDomain, http.MethodDelete, "/api/organizations/{orgId}/domain",
+		"/api/organizations/org-1/domain", nil, t)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
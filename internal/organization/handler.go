@@ -2,6 +2,7 @@ package organization
 
 import (
 	"context"
+	"net"
 
 	"github.com/sendrec/sendrec/internal/database"
 )
@@ -10,16 +11,27 @@ type EmailSender interface {
 	SendOrgInvite(ctx context.Context, toEmail, orgName, inviterName, acceptLink string) error
 }
 
+// TXTResolver looks up DNS TXT records. *net.Resolver satisfies it; tests
+// substitute a stub.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
 type Handler struct {
 	db          database.DBTX
 	baseURL     string
 	emailSender EmailSender
+	resolver    TXTResolver
 }
 
 func NewHandler(db database.DBTX, baseURL string) *Handler {
-	return &Handler{db: db, baseURL: baseURL}
+	return &Handler{db: db, baseURL: baseURL, resolver: net.DefaultResolver}
 }
 
 func (h *Handler) SetEmailSender(sender EmailSender) {
 	h.emailSender = sender
 }
+
+func (h *Handler) SetTXTResolver(resolver TXTResolver) {
+	h.resolver = resolver
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
 	"github.com/sendrec/sendrec/internal/auth"
 	"github.com/sendrec/sendrec/internal/database"
@@ -21,33 +22,46 @@ func Middleware(db database.DBTX) func(http.Handler) http.Handler {
 				next.ServeHTTP(w, r)
 				return
 			}
+			serveAsMember(db, orgID, next, w, r)
+		})
+	}
+}
 
-			userID := auth.UserIDFromContext(r.Context())
-			if userID == "" {
-				httputil.WriteError(w, http.StatusUnauthorized, "authentication required")
-				return
-			}
+// PathMiddleware is Middleware for routes that name the organization in
+// their {orgId} path segment instead of the header.
+func PathMiddleware(db database.DBTX) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			serveAsMember(db, chi.URLParam(r, "orgId"), next, w, r)
+		})
+	}
+}
 
-			var role string
-			err := db.QueryRow(r.Context(),
-				`SELECT om.role FROM organization_members om
-				 JOIN organizations o ON o.id = om.organization_id
-				 WHERE om.organization_id = $1 AND om.user_id = $2`,
-				orgID, userID,
-			).Scan(&role)
-			if err != nil {
-				if errors.Is(err, pgx.ErrNoRows) {
-					httputil.WriteError(w, http.StatusForbidden, "not a member of this organization")
-					return
-				}
-				httputil.WriteError(w, http.StatusInternalServerError, "failed to verify organization membership")
-				return
-			}
+func serveAsMember(db database.DBTX, orgID string, next http.Handler, w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	if userID == "" {
+		httputil.WriteError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
 
-			ctx := auth.ContextWithOrg(r.Context(), orgID, role)
-			next.ServeHTTP(w, r.WithContext(ctx))
-		})
+	var role string
+	err := db.QueryRow(r.Context(),
+		`SELECT om.role FROM organization_members om
+		 JOIN organizations o ON o.id = om.organization_id
+		 WHERE om.organization_id = $1 AND om.user_id = $2`,
+		orgID, userID,
+	).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			httputil.WriteError(w, http.StatusForbidden, "not a member of this organization")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to verify organization membership")
+		return
 	}
+
+	ctx := auth.ContextWithOrg(r.Context(), orgID, role)
+	next.ServeHTTP(w, r.WithContext(ctx))
 }
 
 // RequireRole checks if the caller has one of the allowed roles in the current
@@ -49,6 +49,7 @@ type orgDetailResponse struct {
 	RetentionDays    int    `json:"retentionDays"`
 	Role             string `json:"role"`
 	MemberCount      int64  `json:"memberCount"`
+	AnalyticsPrivacy bool   `json:"analyticsPrivacyMode"`
 	CreatedAt        string `json:"createdAt"`
 	UpdatedAt        string `json:"updatedAt"`
 }
@@ -58,9 +59,10 @@ type createOrgRequest struct {
 }
 
 type updateOrgRequest struct {
-	Name          *string `json:"name"`
-	Slug          *string `json:"slug"`
-	RetentionDays *int    `json:"retentionDays"`
+	Name                 *string `json:"name"`
+	Slug                 *string `json:"slug"`
+	RetentionDays        *int    `json:"retentionDays"`
+	AnalyticsPrivacyMode *bool   `json:"analyticsPrivacyMode"`
 }
 
 func generateSlug(name string) string {
@@ -212,12 +214,13 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	var createdAt, updatedAt time.Time
 	err := h.db.QueryRow(r.Context(),
 		`SELECT o.id, o.name, o.slug, o.subscription_plan, o.retention_days, o.created_at, o.updated_at, om.role,
-		        (SELECT COUNT(*) FROM organization_members WHERE organization_id = o.id AND role != 'viewer') AS member_count
+		        (SELECT COUNT(*) FROM organization_members WHERE organization_id = o.id AND role != 'viewer') AS member_count,
+		        o.analytics_privacy_mode
 		 FROM organizations o
 		 JOIN organization_members om ON om.organization_id = o.id
 		 WHERE o.id = $1 AND om.user_id = $2`,
 		orgID, userID,
-	).Scan(&resp.ID, &resp.Name, &resp.Slug, &resp.SubscriptionPlan, &resp.RetentionDays, &createdAt, &updatedAt, &resp.Role, &resp.MemberCount)
+	).Scan(&resp.ID, &resp.Name, &resp.Slug, &resp.SubscriptionPlan, &resp.RetentionDays, &createdAt, &updatedAt, &resp.Role, &resp.MemberCount, &resp.AnalyticsPrivacy)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			httputil.WriteError(w, http.StatusNotFound, "organization not found")
@@ -261,7 +264,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Name == nil && req.Slug == nil && req.RetentionDays == nil {
+	if req.Name == nil && req.Slug == nil && req.RetentionDays == nil && req.AnalyticsPrivacyMode == nil {
 		httputil.WriteError(w, http.StatusBadRequest, "nothing to update")
 		return
 	}
@@ -318,6 +321,11 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		args = append(args, *req.RetentionDays)
 		paramIdx++
 	}
+	if req.AnalyticsPrivacyMode != nil {
+		setClauses = append(setClauses, fmt.Sprintf("analytics_privacy_mode = $%d", paramIdx))
+		args = append(args, *req.AnalyticsPrivacyMode)
+		paramIdx++
+	}
 
 	setClauses = append(setClauses, "updated_at = now()")
 
@@ -345,11 +353,12 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	err = h.db.QueryRow(r.Context(),
 		`SELECT o.id, o.name, o.slug, o.subscription_plan, o.retention_days, o.created_at, o.updated_at,
 		        (SELECT role FROM organization_members WHERE organization_id = o.id AND user_id = $2) AS role,
-		        (SELECT COUNT(*) FROM organization_members WHERE organization_id = o.id AND role != 'viewer') AS member_count
+		        (SELECT COUNT(*) FROM organization_members WHERE organization_id = o.id AND role != 'viewer') AS member_count,
+		        o.analytics_privacy_mode
 		 FROM organizations o
 		 WHERE o.id = $1`,
 		orgID, userID,
-	).Scan(&resp.ID, &resp.Name, &resp.Slug, &resp.SubscriptionPlan, &resp.RetentionDays, &createdAt, &updatedAt, &resp.Role, &resp.MemberCount)
+	).Scan(&resp.ID, &resp.Name, &resp.Slug, &resp.SubscriptionPlan, &resp.RetentionDays, &createdAt, &updatedAt, &resp.Role, &resp.MemberCount, &resp.AnalyticsPrivacy)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "failed to read updated organization")
 		return
@@ -235,8 +235,8 @@ func TestGet(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT o\.id, o\.name, o\.slug, o\.subscription_plan, o\.retention_days, o\.created_at, o\.updated_at, om\.role`).
 		WithArgs(orgID, testUserID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "slug", "subscription_plan", "retention_days", "created_at", "updated_at", "role", "member_count"}).
-			AddRow(orgID, "Acme Corp", "acme-corp", "free", 0, now, now, "owner", int64(3)))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "slug", "subscription_plan", "retention_days", "created_at", "updated_at", "role", "member_count", "analytics_privacy_mode"}).
+			AddRow(orgID, "Acme Corp", "acme-corp", "free", 0, now, now, "owner", int64(3), false))
 
 	r := chi.NewRouter()
 	r.With(newAuthMiddleware()).Get("/api/organizations/{orgId}", handler.Get)
@@ -326,8 +326,8 @@ func TestUpdate_AsOwner(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT o\.id, o\.name, o\.slug, o\.subscription_plan, o\.retention_days, o\.created_at, o\.updated_at`).
 		WithArgs(orgID, testUserID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "slug", "subscription_plan", "retention_days", "created_at", "updated_at", "role", "member_count"}).
-			AddRow(orgID, newName, "acme-corp", "free", 0, now, now, "owner", int64(1)))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "slug", "subscription_plan", "retention_days", "created_at", "updated_at", "role", "member_count", "analytics_privacy_mode"}).
+			AddRow(orgID, newName, "acme-corp", "free", 0, now, now, "owner", int64(1), false))
 
 	body, _ := json.Marshal(map[string]any{"name": newName})
 
@@ -479,8 +479,8 @@ func TestUpdate_RetentionDays_Valid(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT o\.id, o\.name, o\.slug, o\.subscription_plan, o\.retention_days, o\.created_at, o\.updated_at`).
 		WithArgs(orgID, testUserID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "slug", "subscription_plan", "retention_days", "created_at", "updated_at", "role", "member_count"}).
-			AddRow(orgID, "Acme Corp", "acme-corp", "free", retentionDays, now, now, "owner", int64(1)))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "slug", "subscription_plan", "retention_days", "created_at", "updated_at", "role", "member_count", "analytics_privacy_mode"}).
+			AddRow(orgID, "Acme Corp", "acme-corp", "free", retentionDays, now, now, "owner", int64(1), false))
 
 	body, _ := json.Marshal(map[string]any{"retentionDays": retentionDays})
 
@@ -507,6 +507,55 @@ func TestUpdate_RetentionDays_Valid(t *testing.T) {
 	}
 }
 
+func TestUpdate_AnalyticsPrivacyMode(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, testBaseURL)
+	orgID := "org-1"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	mock.ExpectQuery(`SELECT role FROM organization_members WHERE organization_id = \$1 AND user_id = \$2`).
+		WithArgs(orgID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"role"}).AddRow("admin"))
+
+	mock.ExpectExec(`UPDATE organizations SET analytics_privacy_mode = \$1, updated_at = now\(\) WHERE id = \$2`).
+		WithArgs(true, orgID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	mock.ExpectQuery(`SELECT o\.id, o\.name, o\.slug, o\.subscription_plan, o\.retention_days, o\.created_at, o\.updated_at`).
+		WithArgs(orgID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "slug", "subscription_plan", "retention_days", "created_at", "updated_at", "role", "member_count", "analytics_privacy_mode"}).
+			AddRow(orgID, "Acme Corp", "acme-corp", "free", 0, now, now, "admin", int64(1), true))
+
+	body, _ := json.Marshal(map[string]any{"analyticsPrivacyMode": true})
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/organizations/{orgId}", handler.Update)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/organizations/"+orgID, body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp orgDetailResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !resp.AnalyticsPrivacy {
+		t.Error("expected analyticsPrivacyMode to be enabled")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
 func TestUpdate_RetentionDays_Invalid(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	if err != nil {
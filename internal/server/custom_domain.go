@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	customDomainCacheTTL     = time.Minute
+	customDomainCacheMaxSize = 1024
+)
+
+type cachedCustomDomain struct {
+	orgID     string
+	expiresAt time.Time
+}
+
+// customDomainRouter serves an organization's public pages on its verified
+// custom hostname. Requests for any other host pass through untouched.
+type customDomainRouter struct {
+	db       database.DBTX
+	baseHost string
+	scheme   string
+
+	mu    sync.Mutex
+	cache map[string]cachedCustomDomain
+}
+
+func newCustomDomainRouter(db database.DBTX, baseURL string) *customDomainRouter {
+	c := &customDomainRouter{db: db, scheme: "https://", cache: make(map[string]cachedCustomDomain)}
+	if u, err := url.Parse(baseURL); err == nil {
+		c.baseHost = strings.ToLower(u.Hostname())
+		if u.Scheme == "http" {
+			c.scheme = "http://"
+		}
+	}
+	return c
+}
+
+// lookup returns the organization that owns a verified hostname, or "" when
+// none does. Both outcomes are cached briefly so stray Host headers don't
+// cost a query each.
+func (c *customDomainRouter) lookup(ctx context.Context, host string) string {
+	now := time.Now()
+	c.mu.Lock()
+	if entry, ok := c.cache[host]; ok && now.Before(entry.expiresAt) {
+		c.mu.Unlock()
+		return entry.orgID
+	}
+	c.mu.Unlock()
+
+	var orgID string
+	if err := c.db.QueryRow(ctx,
+		`SELECT organization_id FROM organization_domains WHERE hostname = $1 AND verified_at IS NOT NULL`,
+		host,
+	).Scan(&orgID); err != nil {
+		orgID = ""
+	}
+
+	c.mu.Lock()
+	if len(c.cache) >= customDomainCacheMaxSize {
+		c.cache = make(map[string]cachedCustomDomain)
+	}
+	c.cache[host] = cachedCustomDomain{orgID: orgID, expiresAt: now.Add(customDomainCacheTTL)}
+	c.mu.Unlock()
+	return orgID
+}
+
+// publicResource maps a path to the share token (or vanity slug) it serves
+// and whether that names a playlist. Only watch, embed, oEmbed and playlist
+// surfaces, including playlist feeds, are reachable on a custom domain.
+func publicResource(path string) (token string, playlist bool, ok bool) {
+	for _, prefix := range []string{"/watch/playlist/", "/embed/playlist/", "/api/watch/playlist/", "/feed/playlist/"} {
+		if rest, found := strings.CutPrefix(path, prefix); found {
+			return firstSegment(rest), true, firstSegment(rest) != ""
+		}
+	}
+	for _, prefix := range []string{"/watch/", "/embed/", "/api/watch/"} {
+		if rest, found := strings.CutPrefix(path, prefix); found {
+			return firstSegment(rest), false, firstSegment(rest) != ""
+		}
+	}
+	if rest, found := strings.CutPrefix(path, "/api/videos/"); found {
+		token, tail, _ := strings.Cut(rest, "/")
+		return token, false, token != "" && tail == "oembed"
+	}
+	return "", false, false
+}
+
+// resourcePath is the path publicResource should judge a request by. oEmbed
+// discovery requests name their resource in the url parameter instead.
+func resourcePath(r *http.Request) string {
+	if r.URL.Path != "/api/oembed" {
+		return r.URL.Path
+	}
+	target, err := url.Parse(r.URL.Query().Get("url"))
+	if err != nil {
+		return ""
+	}
+	return target.Path
+}
+
+func firstSegment(s string) string {
+	segment, _, _ := strings.Cut(s, "/")
+	return segment
+}
+
+func (c *customDomainRouter) ownsResource(ctx context.Context, orgID, token string, playlist bool) bool {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM videos v
+		WHERE v.organization_id = $2
+		  AND (v.share_token = $1 OR v.id IN (SELECT video_id FROM video_slugs WHERE organization_id = $2 AND slug = $1)))`
+	// Playlists belong to a user, not a workspace. One is published into a
+	// workspace by giving it a slug there, so that row is what ties it to the
+	// domain; a member's personal playlists have none.
+	if playlist {
+		query = `SELECT EXISTS (
+			SELECT 1 FROM playlists p
+			JOIN playlist_slugs ps ON ps.playlist_id = p.id AND ps.organization_id = $2
+			JOIN organization_members om ON om.user_id = p.user_id AND om.organization_id = $2
+			WHERE p.share_token = $1 OR ps.slug = $1)`
+	}
+	var owned bool
+	if err := c.db.QueryRow(ctx, query, token, orgID).Scan(&owned); err != nil {
+		return false
+	}
+	return owned
+}
+
+func (c *customDomainRouter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := strings.ToLower(r.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if host == "" || host == c.baseHost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		orgID := c.lookup(r.Context(), host)
+		if orgID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, playlist, ok := publicResource(resourcePath(r))
+		if !ok || !c.ownsResource(r.Context(), orgID, token, playlist) {
+			httputil.WriteError(w, http.StatusNotFound, "not found")
+			return
+		}
+
+		ctx := httputil.ContextWithCustomDomain(r.Context(), httputil.CustomDomain{
+			OrganizationID: orgID,
+			BaseURL:        c.scheme + r.Host,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+func TestPublicResource(t *testing.T) {
+	tests := []struct {
+		path     string
+		token    string
+		playlist bool
+		ok       bool
+	}{
+		{"/watch/abc123", "abc123", false, true},
+		{"/watch/abc123/verify-email", "abc123", false, true},
+		{"/embed/abc123", "abc123", false, true},
+		{"/api/watch/abc123/comments", "abc123", false, true},
+		{"/api/videos/abc123/oembed", "abc123", false, true},
+		{"/watch/playlist/pl123", "pl123", true, true},
+		{"/embed/playlist/pl123", "pl123", true, true},
+		{"/api/watch/playlist/pl123/verify", "pl123", true, true},
+		{"/feed/playlist/pl123", "pl123", true, true},
+		{"/feed/playlist/pl123/videos/vid-1", "pl123", true, true},
+		{"/api/videos/abc123", "", false, false},
+		{"/api/videos", "", false, false},
+		{"/login", "", false, false},
+		{"/watch/", "", false, false},
+	}
+	for _, tt := range tests {
+		token, playlist, ok := publicResource(tt.path)
+		if ok != tt.ok || (ok && (token != tt.token || playlist != tt.playlist)) {
+			t.Errorf("publicResource(%q) = (%q, %v, %v), want (%q, %v, %v)", tt.path, token, playlist, ok, tt.token, tt.playlist, tt.ok)
+		}
+	}
+}
+
+func serveThroughCustomDomain(t *testing.T, router *customDomainRouter, host, path string) (*httptest.ResponseRecorder, *httputil.CustomDomain) {
+	t.Helper()
+	var seen *httputil.CustomDomain
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d, ok := httputil.CustomDomainFromContext(r.Context()); ok {
+			seen = &d
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = host
+	rec := httptest.NewRecorder()
+	router.Middleware(inner).ServeHTTP(rec, req)
+	return rec, seen
+}
+
+func TestCustomDomain_BaseHostPassesThrough(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	router := newCustomDomainRouter(mock, "https://app.sendrec.eu")
+	rec, seen := serveThroughCustomDomain(t, router, "app.sendrec.eu", "/api/videos")
+
+	if rec.Code != http.StatusOK || seen != nil {
+		t.Fatalf("expected untouched pass-through, got %d (%v)", rec.Code, seen)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+func TestCustomDomain_UnknownHostPassesThroughAndIsCached(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT organization_id FROM organization_domains WHERE hostname = \$1 AND verified_at IS NOT NULL`).
+		WithArgs("10.0.0.5").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id"}))
+
+	router := newCustomDomainRouter(mock, "https://app.sendrec.eu")
+	for i := 0; i < 2; i++ {
+		rec, seen := serveThroughCustomDomain(t, router, "10.0.0.5:8080", "/api/health")
+		if rec.Code != http.StatusOK || seen != nil {
+			t.Fatalf("expected pass-through, got %d (%v)", rec.Code, seen)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCustomDomain_ServesOrgVideo(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT organization_id FROM organization_domains`).
+		WithArgs("video.theirbrand.com").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id"}).AddRow("org-1"))
+	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM videos v\s+WHERE v.organization_id = \$2\s+AND \(v.share_token = \$1 OR v.id IN \(SELECT video_id FROM video_slugs`).
+		WithArgs("abc123", "org-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	router := newCustomDomainRouter(mock, "https://app.sendrec.eu")
+	rec, seen := serveThroughCustomDomain(t, router, "Video.TheirBrand.com", "/watch/abc123")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if seen == nil || seen.OrganizationID != "org-1" || seen.BaseURL != "https://Video.TheirBrand.com" {
+		t.Errorf("unexpected custom domain context: %+v", seen)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCustomDomain_RejectsOtherOrgsVideo(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT organization_id FROM organization_domains`).
+		WithArgs("video.theirbrand.com").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id"}).AddRow("org-1"))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM videos`).
+		WithArgs("other-token", "org-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	router := newCustomDomainRouter(mock, "https://app.sendrec.eu")
+	rec, _ := serveThroughCustomDomain(t, router, "video.theirbrand.com", "/embed/other-token")
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestCustomDomain_BlocksAppRoutes(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT organization_id FROM organization_domains`).
+		WithArgs("video.theirbrand.com").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id"}).AddRow("org-1"))
+
+	router := newCustomDomainRouter(mock, "https://app.sendrec.eu")
+	rec, _ := serveThroughCustomDomain(t, router, "video.theirbrand.com", "/api/auth/login")
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCustomDomain_ServesOEmbedForOwnPlaylist(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT organization_id FROM organization_domains`).
+		WithArgs("video.theirbrand.com").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id"}).AddRow("org-1"))
+	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM playlists p\s+JOIN playlist_slugs ps ON ps.playlist_id = p.id AND ps.organization_id = \$2`).
+		WithArgs("onboarding", "org-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	router := newCustomDomainRouter(mock, "https://app.sendrec.eu")
+	rec, seen := serveThroughCustomDomain(t, router, "video.theirbrand.com",
+		"/api/oembed?url=https%3A%2F%2Fvideo.theirbrand.com%2Fwatch%2Fplaylist%2Fonboarding&format=json")
+
+	if rec.Code != http.StatusOK || seen == nil {
+		t.Fatalf("expected oEmbed request to reach the handler, got %d", rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCustomDomain_RejectsMembersPersonalPlaylistFeed(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT organization_id FROM organization_domains`).
+		WithArgs("video.theirbrand.com").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id"}).AddRow("org-1"))
+	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM playlists p\s+JOIN playlist_slugs ps`).
+		WithArgs("personal-token", "org-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	router := newCustomDomainRouter(mock, "https://app.sendrec.eu")
+	rec, seen := serveThroughCustomDomain(t, router, "video.theirbrand.com", "/feed/playlist/personal-token")
+
+	if rec.Code != http.StatusNotFound || seen != nil {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -43,7 +43,7 @@ func securityHeaders(cfg SecurityConfig) func(http.Handler) http.Handler {
 			}
 
 			csp := fmt.Sprintf(
-				"default-src 'self'; img-src 'self' data:%s; media-src 'self' data:%s; script-src 'self' 'nonce-%s'; style-src 'self' 'nonce-%s'; connect-src 'self'%s; frame-ancestors %s;",
+				"default-src 'self'; img-src 'self' data:%s; media-src 'self' data: blob:%s; script-src 'self' 'nonce-%s'; style-src 'self' 'nonce-%s'; connect-src 'self'%s; frame-ancestors %s;",
 				storageSuffix, storageSuffix, nonce, nonce, storageSuffix, cspFrameAncestors,
 			)
 			w.Header().Set("Content-Security-Policy", csp)
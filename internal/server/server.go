@@ -39,6 +39,8 @@ type Config struct {
 	MaxVideosPerMonth         int
 	MaxVideoDurationSeconds   int
 	MaxPlaylists              int
+	VideoReplyMaxBytes        int64
+	VideoReplyMaxSeconds      int
 	S3PublicEndpoint          string
 	EnableDocs                bool
 	BrandingEnabled           bool
@@ -47,10 +49,15 @@ type Config struct {
 	NoiseReductionFilter      string
 	AllowedFrameAncestors     string
 	AnalyticsScript           string
+	AnalyticsPrivacyMode      bool
+	LiveHub                   *video.LiveHub
 	EmailSender               auth.EmailSender
 	CommentNotifier           video.CommentNotifier
 	ViewNotifier              video.ViewNotifier
+	ViewerVerifier            video.ViewerVerifier
+	ThreadReplyNotifier       video.ThreadReplyNotifier
 	SlackNotifier             video.SlackNotifier
+	CommentSpamScorer         video.SpamScorer
 	WebhookClient             *webhook.Client
 	CreemAPIKey               string
 	CreemWebhookSecret        string
@@ -97,6 +104,9 @@ func New(cfg Config) *Server {
 		StorageEndpoint:       cfg.S3PublicEndpoint,
 		AllowedFrameAncestors: cfg.AllowedFrameAncestors,
 	}))
+	if cfg.DB != nil && cfg.BaseURL != "" {
+		r.Use(newCustomDomainRouter(cfg.DB, cfg.BaseURL).Middleware)
+	}
 
 	s := &Server{router: r, pinger: cfg.Pinger, db: cfg.DB, webFS: cfg.WebFS, enableDocs: cfg.EnableDocs, registrationEnabled: cfg.RegistrationEnabled, planBadgeEnabled: cfg.PlanBadgeEnabled, analyticsScript: cfg.AnalyticsScript}
 
@@ -124,12 +134,24 @@ func New(cfg Config) *Server {
 		if cfg.ViewNotifier != nil {
 			s.videoHandler.SetViewNotifier(cfg.ViewNotifier)
 		}
+		if cfg.ViewerVerifier != nil {
+			s.videoHandler.SetViewerVerifier(cfg.ViewerVerifier)
+		}
+		if cfg.ThreadReplyNotifier != nil {
+			s.videoHandler.SetThreadReplyNotifier(cfg.ThreadReplyNotifier)
+		}
 		if cfg.BrandingEnabled {
 			s.videoHandler.SetBrandingEnabled(true)
 		}
 		if cfg.AnalyticsScript != "" {
 			s.videoHandler.SetAnalyticsScript(cfg.AnalyticsScript)
 		}
+		if cfg.AnalyticsPrivacyMode {
+			s.videoHandler.SetAnalyticsPrivacyMode(true)
+		}
+		if cfg.LiveHub != nil {
+			s.videoHandler.SetLiveHub(cfg.LiveHub)
+		}
 		if cfg.AiEnabled {
 			s.videoHandler.SetAIEnabled(true)
 		}
@@ -145,6 +167,10 @@ func New(cfg Config) *Server {
 		if cfg.WebhookClient != nil {
 			s.videoHandler.SetWebhookClient(cfg.WebhookClient)
 		}
+		if cfg.CommentSpamScorer != nil {
+			s.videoHandler.SetSpamScorer(cfg.CommentSpamScorer)
+		}
+		s.videoHandler.SetVideoReplyLimits(cfg.VideoReplyMaxBytes, cfg.VideoReplyMaxSeconds)
 		if cfg.GeoIPDBPath != "" {
 			geoResolver, err := geoip.New(cfg.GeoIPDBPath)
 			if err == nil {
@@ -324,6 +350,13 @@ func (s *Server) routes() {
 				r.Post("/invites", s.orgHandler.SendInvite)
 				r.Get("/invites", s.orgHandler.ListInvites)
 				r.Delete("/invites/{inviteId}", s.orgHandler.RevokeInvite)
+				r.Group(func(r chi.Router) {
+					r.Use(organization.PathMiddleware(s.db))
+					r.Get("/domain", s.orgHandler.GetDomain)
+					r.Put("/domain", s.orgHandler.SetDomain)
+					r.Delete("/domain", s.orgHandler.DeleteDomain)
+					r.Post("/domain/verify", s.orgHandler.VerifyDomain)
+				})
 				if s.billingHandlers != nil {
 					r.Route("/billing", func(r chi.Router) {
 						r.Get("/", s.billingHandlers.GetOrgBilling)
@@ -396,7 +429,16 @@ func (s *Server) routes() {
 				r.Get("/{id}/comments", s.videoHandler.ListOwnerComments)
 				r.Get("/{id}/analytics", s.videoHandler.Analytics)
 				r.Get("/{id}/analytics/export", s.videoHandler.AnalyticsExport)
+				r.Get("/{id}/analytics/sessions", s.videoHandler.AnalyticsSessions)
+				r.Get("/{id}/analytics/sessions/{sessionId}", s.videoHandler.AnalyticsSessionTimeline)
+				r.Get("/{id}/analytics/live", s.videoHandler.AnalyticsLive)
 				r.Get("/{id}/branding", s.videoHandler.GetVideoBranding)
+				r.Get("/{id}/visibility", s.videoHandler.GetVideoVisibility)
+				r.Get("/{id}/email-gate", s.videoHandler.GetEmailGate)
+				r.Get("/{id}/embed-settings", s.videoHandler.GetEmbedSettings)
+				r.Get("/{id}/ctas", s.videoHandler.ListVideoCTAs)
+				r.Get("/{id}/questions", s.videoHandler.ListVideoQuestions)
+				r.Get("/{id}/slug", s.videoHandler.GetVideoSlug)
 
 				// Write routes (viewer blocked)
 				r.Group(func(r chi.Router) {
@@ -414,14 +456,34 @@ func (s *Server) routes() {
 					r.Put("/{id}/password", s.videoHandler.SetPassword)
 					r.Put("/{id}/comment-mode", s.videoHandler.SetCommentMode)
 					r.Delete("/{id}/comments/{commentId}", s.videoHandler.DeleteComment)
+					r.Put("/{id}/comments/{commentId}/resolved", s.videoHandler.SetCommentResolved)
+					r.Post("/{id}/comments/{commentId}/approve", s.videoHandler.ApproveComment)
+					r.Post("/{id}/comments/{commentId}/reject", s.videoHandler.RejectComment)
+					r.Post("/{id}/comments/{commentId}/ban", s.videoHandler.BanCommentAuthor)
+					// Held comments carry commenters' emails, so the moderation
+					// views are not open to viewers either.
+					r.Get("/comments/moderation", s.videoHandler.ModerationInbox)
+					r.Get("/comments/bans", s.videoHandler.ListCommentBans)
+					r.Delete("/comments/bans/{banId}", s.videoHandler.DeleteCommentBan)
 					r.Put("/{id}/notifications", s.videoHandler.SetVideoNotification)
 					r.Put("/{id}/download-enabled", s.videoHandler.SetDownloadEnabled)
+					r.Put("/{id}/video-replies", s.videoHandler.SetVideoReplies)
 					r.Put("/{id}/link-expiry", s.videoHandler.SetLinkExpiry)
 					r.Put("/{id}/branding", s.videoHandler.SetVideoBranding)
 					r.Post("/{id}/thumbnail", s.videoHandler.UploadThumbnail)
 					r.Delete("/{id}/thumbnail", s.videoHandler.ResetThumbnail)
 					r.Put("/{id}/cta", s.videoHandler.SetCTA)
+					r.Post("/{id}/ctas", s.videoHandler.CreateVideoCTA)
+					r.Put("/{id}/ctas/{ctaId}", s.videoHandler.UpdateVideoCTA)
+					r.Delete("/{id}/ctas/{ctaId}", s.videoHandler.DeleteVideoCTA)
+					r.Post("/{id}/questions", s.videoHandler.CreateVideoQuestion)
+					r.Put("/{id}/questions/{questionId}", s.videoHandler.UpdateVideoQuestion)
+					r.Delete("/{id}/questions/{questionId}", s.videoHandler.DeleteVideoQuestion)
 					r.Put("/{id}/email-gate", s.videoHandler.SetEmailGate)
+					r.Put("/{id}/visibility", s.videoHandler.SetVideoVisibility)
+					r.Put("/{id}/embed-settings", s.videoHandler.SetEmbedSettings)
+					r.Post("/{id}/embed-secret", s.videoHandler.RotateEmbedSecret)
+					r.Put("/{id}/slug", s.videoHandler.SetVideoSlug)
 					r.Post("/{id}/summarize", s.videoHandler.Summarize)
 					r.Post("/{id}/generate-document", s.videoHandler.GenerateDocument)
 					r.Put("/{id}/folder", s.videoHandler.SetVideoFolder)
@@ -456,11 +518,28 @@ func (s *Server) routes() {
 				Post("/", s.videoHandler.UploadTranscript)
 		})
 
+		analyticsQueryLimiter := ratelimit.NewLimiter(2, 10)
 		s.router.Route("/api/analytics", func(r chi.Router) {
-			r.Use(s.authHandler.Middleware)
-			r.Use(organization.Middleware(s.db))
-			r.Get("/dashboard", s.videoHandler.AnalyticsDashboard)
-			r.Get("/dashboard/export", s.videoHandler.DashboardExport)
+			// The query API accepts API key OR JWT
+			r.With(analyticsQueryLimiter.Middleware, apiKeyOrJWTMiddleware(s.db, s.authHandler.Middleware), organization.Middleware(s.db)).
+				Get("/query", s.videoHandler.AnalyticsQuery)
+			r.Group(func(r chi.Router) {
+				r.Use(s.authHandler.Middleware)
+				r.Use(organization.Middleware(s.db))
+				r.Get("/dashboard", s.videoHandler.AnalyticsDashboard)
+				r.Get("/dashboard/export", s.videoHandler.DashboardExport)
+				r.Get("/dashboard/live", s.videoHandler.DashboardLive)
+				r.Get("/org", s.videoHandler.OrgAnalyticsDashboard)
+				r.Get("/org/export", s.videoHandler.OrgAnalyticsExport)
+				r.Get("/reports", s.videoHandler.ListScheduledReports)
+				r.Group(func(r chi.Router) {
+					r.Use(organization.RequireWriter)
+					r.Use(maxBodySize(64 * 1024))
+					r.Post("/reports", s.videoHandler.CreateScheduledReport)
+					r.Put("/reports/{reportId}", s.videoHandler.UpdateScheduledReport)
+					r.Delete("/reports/{reportId}", s.videoHandler.DeleteScheduledReport)
+				})
+			})
 		})
 
 		s.router.Route("/api/folders", func(r chi.Router) {
@@ -468,11 +547,13 @@ func (s *Server) routes() {
 			r.Use(organization.Middleware(s.db))
 			r.Use(maxBodySize(64 * 1024))
 			r.Get("/", s.videoHandler.ListFolders)
+			r.Get("/{id}/visibility", s.videoHandler.GetFolderVisibility)
 			r.Group(func(r chi.Router) {
 				r.Use(organization.RequireWriter)
 				r.Post("/", s.videoHandler.CreateFolder)
 				r.Put("/{id}", s.videoHandler.UpdateFolder)
 				r.Delete("/{id}", s.videoHandler.DeleteFolder)
+				r.Put("/{id}/visibility", s.videoHandler.SetFolderVisibility)
 			})
 		})
 
@@ -494,6 +575,7 @@ func (s *Server) routes() {
 			r.Use(maxBodySize(64 * 1024))
 			r.Get("/", s.videoHandler.ListPlaylists)
 			r.Get("/{id}", s.videoHandler.GetPlaylist)
+			r.Get("/{id}/feed-tokens", s.videoHandler.ListPlaylistFeedTokens)
 			r.Group(func(r chi.Router) {
 				r.Use(organization.RequireWriter)
 				r.Post("/", s.videoHandler.CreatePlaylist)
@@ -502,12 +584,23 @@ func (s *Server) routes() {
 				r.Post("/{id}/videos", s.videoHandler.AddPlaylistVideos)
 				r.Delete("/{id}/videos/{videoId}", s.videoHandler.RemovePlaylistVideo)
 				r.Patch("/{id}/videos/reorder", s.videoHandler.ReorderPlaylistVideos)
+				r.Post("/{id}/feed-tokens", s.videoHandler.CreatePlaylistFeedToken)
+				r.Delete("/{id}/feed-tokens/{tokenId}", s.videoHandler.DeletePlaylistFeedToken)
+			})
+			// Slugs are namespaced by organization, so these routes need the
+			// workspace context the rest of the playlist API does without.
+			r.Group(func(r chi.Router) {
+				r.Use(organization.Middleware(s.db))
+				r.Get("/{id}/slug", s.videoHandler.GetPlaylistSlug)
+				r.With(organization.RequireWriter).Put("/{id}/slug", s.videoHandler.SetPlaylistSlug)
 			})
 		})
 
 		watchAuthLimiter := ratelimit.NewLimiter(0.5, 5)
 		commentLimiter := ratelimit.NewLimiter(0.2, 3)
 		commentReadLimiter := ratelimit.NewLimiter(5, 20)
+		videoReplyLimiter := ratelimit.NewLimiter(0.05, 3)
+		answerLimiter := ratelimit.NewLimiter(0.5, 10)
 		// Unauthenticated watch surface: every GET records a view and can notify
 		// the owner, and the beacons write analytics rows, so they need the same
 		// throttling and body caps their siblings already had (SR-03).
@@ -517,19 +610,31 @@ func (s *Server) routes() {
 		s.router.With(watchAuthLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/verify", s.videoHandler.VerifyWatchPassword)
 		s.router.With(commentReadLimiter.Middleware).Get("/api/watch/{shareToken}/comments", s.videoHandler.ListWatchComments)
 		s.router.With(commentLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/comments", s.videoHandler.PostWatchComment)
+		s.router.With(commentLimiter.Middleware, maxBodySize(64*1024)).Patch("/api/watch/{shareToken}/comments/{commentId}", s.videoHandler.EditWatchComment)
+		s.router.With(videoReplyLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/video-replies", s.videoHandler.CreateVideoReply)
+		s.router.With(commentLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/video-replies/{commentId}/complete", s.videoHandler.CompleteVideoReply)
 		s.router.With(watchAuthLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/identify", s.videoHandler.IdentifyViewer)
+		s.router.With(watchAuthLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/identify/verify", s.videoHandler.VerifyViewerEmail)
 		s.router.With(watchLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/cta-click", s.videoHandler.RecordCTAClick)
+		s.router.With(answerLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/answers", s.videoHandler.RecordAnswer)
 		s.router.With(watchLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/milestone", s.videoHandler.RecordMilestone)
 		s.router.With(watchLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/{shareToken}/segments", s.videoHandler.RecordSegments)
 		s.router.With(watchLimiter.Middleware).Get("/api/watch/{shareToken}/thumbnail", s.videoHandler.WatchThumbnail)
 		s.router.With(watchLimiter.Middleware).Get("/api/videos/{shareToken}/oembed", s.videoHandler.OEmbed)
+		s.router.With(watchLimiter.Middleware).Get("/api/oembed", s.videoHandler.OEmbedByURL)
 		s.router.Get("/watch/{shareToken}", s.videoHandler.WatchPage)
+		s.router.With(watchAuthLimiter.Middleware).Get("/watch/{shareToken}/verify-email", s.videoHandler.VerifyViewerEmailLink)
+		s.router.With(watchAuthLimiter.Middleware, maxBodySize(64*1024)).Post("/watch/{shareToken}/verify-email", s.videoHandler.ConfirmViewerEmailLink)
+		s.router.With(watchAuthLimiter.Middleware).Get("/watch/{shareToken}/comments/{commentId}/unsubscribe", s.videoHandler.ThreadUnsubscribePage)
+		s.router.With(watchAuthLimiter.Middleware, maxBodySize(64*1024)).Post("/watch/{shareToken}/comments/{commentId}/unsubscribe", s.videoHandler.UnsubscribeFromThread)
 		s.router.Get("/embed/{shareToken}", s.videoHandler.EmbedPage)
 
 		s.router.Get("/watch/playlist/{shareToken}", s.videoHandler.PlaylistWatchPage)
 		s.router.Get("/embed/playlist/{shareToken}", s.videoHandler.PlaylistEmbedPage)
 		s.router.With(watchAuthLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/playlist/{shareToken}/verify", s.videoHandler.VerifyPlaylistWatchPassword)
 		s.router.With(watchAuthLimiter.Middleware, maxBodySize(64*1024)).Post("/api/watch/playlist/{shareToken}/identify", s.videoHandler.IdentifyPlaylistViewer)
+		s.router.With(watchLimiter.Middleware).Get("/feed/playlist/{shareToken}", s.videoHandler.PlaylistFeed)
+		s.router.With(watchLimiter.Middleware).Get("/feed/playlist/{shareToken}/videos/{videoId}", s.videoHandler.PlaylistFeedEnclosure)
 
 		if s.billingHandlers != nil {
 			s.router.Post("/api/webhooks/creem", s.billingHandlers.Webhook)
@@ -185,6 +185,35 @@ func (c *Client) SendDigestNotification(ctx context.Context, toEmail, toName str
 	return nil
 }
 
+// SendScheduledReport posts a scheduled analytics report to the Slack
+// webhook of the report's owner. Unlike the notifications above it returns
+// delivery errors, so the report worker can log them against the report.
+func (c *Client) SendScheduledReport(ctx context.Context, ownerEmail string, report email.ScheduledReport) error {
+	webhookURL, err := c.lookupWebhookURL(ctx, ownerEmail)
+	if err != nil {
+		return fmt.Errorf("no slack webhook configured: %w", err)
+	}
+
+	summary := fmt.Sprintf(":bar_chart: *%s* \u2014 %s\n%d views (%d unique) \u00b7 %d%% completion \u00b7 %d CTA clicks (%.1f%%) \u00b7 %d new identified viewers",
+		report.Name, report.Period, report.TotalViews, report.UniqueViews, report.Completion,
+		report.CtaClicks, report.CtaRate, report.NewViewerCount)
+	blocks := []block{{Type: "section", Text: &text{Type: "mrkdwn", Text: summary}}}
+
+	if len(report.TopVideos) > 0 {
+		var lines []string
+		for _, v := range report.TopVideos {
+			lines = append(lines, fmt.Sprintf("\u2022 <%s|%s> \u2014 %d views, %d%% completion", v.WatchURL, v.Title, v.Views, v.Completion))
+		}
+		blocks = append(blocks, block{Type: "section", Text: &text{Type: "mrkdwn", Text: "*Top videos*\n" + strings.Join(lines, "\n")}})
+	}
+	blocks = append(blocks, block{
+		Type:     "context",
+		Elements: []text{{Type: "mrkdwn", Text: fmt.Sprintf("<%s|Open analytics> for the full breakdown and CSV export", report.DashboardURL)}},
+	})
+
+	return c.postMessage(ctx, webhookURL, payload{Blocks: blocks})
+}
+
 // SendTestMessage posts a test message directly to the given webhook URL without DB lookup.
 func SendTestMessage(ctx context.Context, webhookURL string) error {
 	p := payload{
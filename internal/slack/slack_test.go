@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 
@@ -339,3 +340,72 @@ func TestSendViewNotification_SlackError_ReturnsNil(t *testing.T) {
 		t.Errorf("unmet expectations: %v", err)
 	}
 }
+
+func TestSendScheduledReport_PostsSummaryAndTopVideos(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	var mu sync.Mutex
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p payload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		for _, b := range p.Blocks {
+			if b.Text != nil {
+				receivedBody += b.Text.Text + "\n"
+			}
+			for _, e := range b.Elements {
+				receivedBody += e.Text + "\n"
+			}
+		}
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	mock.ExpectQuery(`SELECT np\.slack_webhook_url FROM notification_preferences np`).
+		WithArgs("alice@example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"slack_webhook_url"}).AddRow(server.URL))
+
+	client := New(mock)
+	err = client.SendScheduledReport(context.Background(), "alice@example.com", email.ScheduledReport{
+		Name:         "Sales videos",
+		Period:       "March 2026",
+		TotalViews:   120,
+		UniqueViews:  80,
+		Completion:   45,
+		TopVideos:    []email.ReportVideoSummary{{Title: "Demo", Views: 90, Completion: 50, WatchURL: "https://app.sendrec.eu/watch/aaa"}},
+		DashboardURL: "https://app.sendrec.eu/analytics",
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, want := range []string{"*Sales videos*", "120 views (80 unique)", "<https://app.sendrec.eu/watch/aaa|Demo>", "Open analytics"} {
+		if !strings.Contains(receivedBody, want) {
+			t.Errorf("expected %q in payload: %s", want, receivedBody)
+		}
+	}
+}
+
+func TestSendScheduledReport_NoWebhookReturnsError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT np\.slack_webhook_url`).
+		WithArgs("alice@example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"slack_webhook_url"}))
+
+	if err := New(mock).SendScheduledReport(context.Background(), "alice@example.com", email.ScheduledReport{}); err == nil {
+		t.Error("expected an error when the owner has no Slack webhook")
+	}
+}
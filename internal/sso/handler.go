@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -136,6 +137,58 @@ func (h *Handler) redirectWithError(w http.ResponseWriter, r *http.Request, mess
 	http.Redirect(w, r, redirectURL, http.StatusFound)
 }
 
+// isSafeReturnPath reports whether p is a same-origin path that is safe to
+// send the user back to after login. Protocol-relative and backslash paths
+// are rejected because browsers treat them as absolute URLs.
+func isSafeReturnPath(p string) bool {
+	return strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "//") && !strings.Contains(p, "\\")
+}
+
+// returnPathCookie builds the sso_redirect cookie that remembers where to
+// send the user once workspace SSO finishes. crossSite must be true when the
+// IdP posts back cross-origin (SAML), matching the sso_state cookie.
+func (h *Handler) returnPathCookie(value string, maxAge int, crossSite bool) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     "sso_redirect",
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+	}
+	if crossSite {
+		cookie.Secure = true
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	return cookie
+}
+
+// consumeReturnPath reads and clears the sso_redirect cookie, returning an
+// empty string when it is missing or unsafe.
+func (h *Handler) consumeReturnPath(w http.ResponseWriter, r *http.Request, crossSite bool) string {
+	cookie, err := r.Cookie("sso_redirect")
+	if err != nil {
+		return ""
+	}
+	http.SetCookie(w, h.returnPathCookie("", -1, crossSite))
+	returnPath, err := url.QueryUnescape(cookie.Value)
+	if err != nil || !isSafeReturnPath(returnPath) {
+		return ""
+	}
+	return returnPath
+}
+
+// loginRedirectURL builds the frontend login URL that completes an SSO login,
+// carrying the post-login return path when one was requested.
+func (h *Handler) loginRedirectURL(accessToken, returnPath string) string {
+	redirectURL := h.baseURL + "/login?sso_token=" + url.QueryEscape(accessToken)
+	if returnPath != "" {
+		redirectURL += "&redirect=" + url.QueryEscape(returnPath)
+	}
+	return redirectURL
+}
+
 type ssoConfigRequest struct {
 	Provider        string `json:"provider"`
 	IssuerURL       string `json:"issuerUrl"`
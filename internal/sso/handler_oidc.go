@@ -172,10 +172,14 @@ func (h *Handler) saveOIDCConfig(w http.ResponseWriter, r *http.Request, orgID s
 // InitiateOrgSSO starts the SSO flow for a user based on their email's
 // organization membership. It looks up the SSO config for the organization
 // the user belongs to and redirects to the identity provider.
-// If orgId is provided, it scopes the lookup to that specific organization.
+// If orgId is provided, it scopes the lookup to that specific organization;
+// with an org and no email (e.g. a viewer sent here from a restricted watch
+// page) the organization's config is used directly. An optional redirect path
+// is carried through the flow and handed back to the login page.
 func (h *Handler) InitiateOrgSSO(w http.ResponseWriter, r *http.Request) {
 	email := r.URL.Query().Get("email")
-	if email == "" {
+	orgFilter := r.URL.Query().Get("org")
+	if email == "" && orgFilter == "" {
 		httputil.WriteError(w, http.StatusBadRequest, "email is required")
 		return
 	}
@@ -185,8 +189,21 @@ func (h *Handler) InitiateOrgSSO(w http.ResponseWriter, r *http.Request) {
 	var issuerURL, clientID, encryptedSecret *string
 	var samlEntityID, samlSSOURL, samlCertificate *string
 
-	orgFilter := r.URL.Query().Get("org")
-	if orgFilter != "" {
+	if email == "" {
+		err := h.db.QueryRow(r.Context(),
+			`SELECT o.id, c.provider, c.issuer_url, c.client_id, c.client_secret_encrypted,
+			        c.saml_entity_id, c.saml_sso_url, c.saml_certificate
+			 FROM organization_sso_configs c
+			 JOIN organizations o ON o.id = c.organization_id
+			 WHERE o.id = $1`,
+			orgFilter,
+		).Scan(&orgID, &configProvider, &issuerURL, &clientID, &encryptedSecret,
+			&samlEntityID, &samlSSOURL, &samlCertificate)
+		if err != nil {
+			httputil.WriteError(w, http.StatusNotFound, "no SSO configuration found for this workspace")
+			return
+		}
+	} else if orgFilter != "" {
 		err := h.db.QueryRow(r.Context(),
 			`SELECT o.id, c.provider, c.issuer_url, c.client_id, c.client_secret_encrypted,
 			        c.saml_entity_id, c.saml_sso_url, c.saml_certificate
@@ -220,6 +237,11 @@ func (h *Handler) InitiateOrgSSO(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	returnPath := r.URL.Query().Get("redirect")
+	if !isSafeReturnPath(returnPath) {
+		returnPath = ""
+	}
+
 	state, err := generateState()
 	if err != nil {
 		slog.Error("sso: failed to generate state", "error", err)
@@ -264,6 +286,9 @@ func (h *Handler) InitiateOrgSSO(w http.ResponseWriter, r *http.Request) {
 			SameSite: http.SameSiteNoneMode,
 			MaxAge:   300,
 		})
+		if returnPath != "" {
+			http.SetCookie(w, h.returnPathCookie(url.QueryEscape(returnPath), 300, true))
+		}
 
 		http.Redirect(w, r, redirectURL, http.StatusFound)
 		return
@@ -314,6 +339,9 @@ func (h *Handler) InitiateOrgSSO(w http.ResponseWriter, r *http.Request) {
 		SameSite: http.SameSiteLaxMode,
 		MaxAge:   300,
 	})
+	if returnPath != "" {
+		http.SetCookie(w, h.returnPathCookie(url.QueryEscape(returnPath), 300, false))
+	}
 
 	http.Redirect(w, r, provider.AuthURL(state), http.StatusFound)
 }
@@ -436,6 +464,5 @@ func (h *Handler) OrgCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	auth.SetRefreshTokenCookie(w, refreshToken, h.secureCookies)
-	redirectURL := h.baseURL + "/login?sso_token=" + url.QueryEscape(accessToken)
-	http.Redirect(w, r, redirectURL, http.StatusFound)
+	http.Redirect(w, r, h.loginRedirectURL(accessToken, h.consumeReturnPath(w, r, false)), http.StatusFound)
 }
@@ -4,7 +4,6 @@ import (
 	"encoding/xml"
 	"log/slog"
 	"net/http"
-	"net/url"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -178,8 +177,7 @@ func (h *Handler) OrgSAMLCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	auth.SetRefreshTokenCookie(w, refreshToken, h.secureCookies)
-	redirectURL := h.baseURL + "/login?sso_token=" + url.QueryEscape(accessToken)
-	http.Redirect(w, r, redirectURL, http.StatusFound)
+	http.Redirect(w, r, h.loginRedirectURL(accessToken, h.consumeReturnPath(w, r, true)), http.StatusFound)
 }
 
 // SPMetadata serves the SAML Service Provider metadata XML for the given
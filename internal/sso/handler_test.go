@@ -794,6 +794,86 @@ func TestInitiateOrgSSO_NoConfig(t *testing.T) {
 	}
 }
 
+func TestInitiateOrgSSO_OrgWithoutEmail_StoresReturnPath(t *testing.T) {
+	handler, mock := newTestHandlerWithKey(t)
+	defer mock.Close()
+
+	samlCfg, err := ParseSAMLMetadataFromXML([]byte(testSAMLMetadataXML))
+	if err != nil {
+		t.Fatalf("parse test metadata: %v", err)
+	}
+
+	entityID := samlCfg.EntityID
+	ssoURL := samlCfg.SSOURL
+	cert := samlCfg.Certificate
+	mock.ExpectQuery(`SELECT o.id, c.provider, c.issuer_url, c.client_id, c.client_secret_encrypted,\s+c.saml_entity_id, c.saml_sso_url, c.saml_certificate\s+FROM organization_sso_configs c\s+JOIN organizations o ON o.id = c.organization_id\s+WHERE o.id = \$1`).
+		WithArgs("org-1").
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "provider", "issuer_url", "client_id", "client_secret_encrypted",
+			"saml_entity_id", "saml_sso_url", "saml_certificate",
+		}).AddRow("org-1", "saml", (*string)(nil), (*string)(nil), (*string)(nil),
+			&entityID, &ssoURL, &cert))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/sso/org?org=org-1&redirect=%2Fwatch%2Fabc123", nil)
+	rec := httptest.NewRecorder()
+	handler.InitiateOrgSSO(rec, req)
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("status = %d, want %d; body = %s", rec.Code, http.StatusFound, rec.Body.String())
+	}
+
+	var returnPath string
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "sso_redirect" {
+			returnPath = c.Value
+			if c.SameSite != http.SameSiteNoneMode {
+				t.Errorf("sso_redirect cookie SameSite = %d, want SameSiteNoneMode", c.SameSite)
+			}
+		}
+	}
+	if returnPath != url.QueryEscape("/watch/abc123") {
+		t.Errorf("sso_redirect cookie = %q, want escaped /watch/abc123", returnPath)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestConsumeReturnPath(t *testing.T) {
+	handler, mock := newTestHandlerWithKey(t)
+	defer mock.Close()
+
+	tests := []struct {
+		name   string
+		cookie string
+		want   string
+	}{
+		{"local path", url.QueryEscape("/watch/abc123"), "/watch/abc123"},
+		{"protocol relative", url.QueryEscape("//evil.example.com"), ""},
+		{"absolute url", url.QueryEscape("https://evil.example.com"), ""},
+		{"backslash", url.QueryEscape("/\\evil.example.com"), ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/sso/org/callback", nil)
+			req.AddCookie(&http.Cookie{Name: "sso_redirect", Value: tc.cookie})
+			rec := httptest.NewRecorder()
+
+			if got := handler.consumeReturnPath(rec, req, false); got != tc.want {
+				t.Errorf("consumeReturnPath = %q, want %q", got, tc.want)
+			}
+			if got := rec.Result().Cookies(); len(got) != 1 || got[0].MaxAge >= 0 {
+				t.Errorf("expected sso_redirect cookie to be cleared, got %v", got)
+			}
+		})
+	}
+
+	if got := handler.loginRedirectURL("tok", "/watch/abc123"); !strings.HasSuffix(got, "/login?sso_token=tok&redirect=%2Fwatch%2Fabc123") {
+		t.Errorf("loginRedirectURL = %q", got)
+	}
+}
+
 func TestInitiateOrgSSO_SAML(t *testing.T) {
 	handler, mock := newTestHandlerWithKey(t)
 	defer mock.Close()
@@ -301,3 +301,71 @@ func stripMarkdownFences(content string) string {
 	}
 	return trimmed
 }
+
+const commentSpamSystemPrompt = `You moderate comments left on shared videos. Decide whether the comment is spam: advertising, scams, phishing, SEO links, or off-topic promotion. Ordinary feedback, questions, criticism and short reactions are not spam.
+Return ONLY a JSON object with "spam_probability" (number between 0 and 1) and "reason" (a few words), no markdown formatting.`
+
+type commentSpamResult struct {
+	SpamProbability float64 `json:"spam_probability"`
+	Reason          string  `json:"reason"`
+}
+
+func (c *AIClient) ClassifyComment(ctx context.Context, authorName, commentBody string) (float64, string, error) {
+	reqBody := chatRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: commentSpamSystemPrompt},
+			{Role: "user", Content: fmt.Sprintf("Author: %s\nComment: %s", authorName, commentBody)},
+		},
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return 0, "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", fmt.Errorf("AI API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var chatResp chatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return 0, "", fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return 0, "", fmt.Errorf("AI API returned empty choices")
+	}
+
+	var result commentSpamResult
+	content := stripMarkdownFences(chatResp.Choices[0].Message.Content)
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return 0, "", fmt.Errorf("parse spam classification JSON: %w", err)
+	}
+	if result.SpamProbability < 0 || result.SpamProbability > 1 {
+		return 0, "", fmt.Errorf("spam probability out of range: %v", result.SpamProbability)
+	}
+
+	return result.SpamProbability, result.Reason, nil
+}
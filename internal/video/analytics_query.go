@@ -0,0 +1,268 @@
+package video
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	defaultQueryDays  = 30
+	maxQueryDays      = 731
+	defaultQueryLimit = 100
+	maxQueryLimit     = 1000
+	maxQueryVideoIDs  = 100
+)
+
+var queryIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// queryGroup is one group_by value: the SQL for a row's key and label, and
+// whether rows are a time series (ordered by key) or a ranking (ordered by views).
+type queryGroup struct {
+	key    string
+	label  string
+	series bool
+}
+
+var queryGroups = map[string]queryGroup{
+	"day": {
+		key:    `to_char(vv.created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD')`,
+		label:  `to_char(vv.created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD')`,
+		series: true,
+	},
+	"week": {
+		key:    `to_char(date_trunc('week', vv.created_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD')`,
+		label:  `to_char(date_trunc('week', vv.created_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD')`,
+		series: true,
+	},
+	"video":    {key: `v.id::text`, label: `v.title`},
+	"country":  {key: `vv.country`, label: `vv.country`},
+	"device":   {key: `vv.device`, label: `vv.device`},
+	"referrer": {key: `vv.referrer`, label: `vv.referrer`},
+	"campaign": {key: `COALESCE(vv.utm_campaign, '')`, label: `COALESCE(vv.utm_campaign, '')`},
+}
+
+type analyticsQueryRow struct {
+	Key         string `json:"key"`
+	Label       string `json:"label"`
+	Views       int64  `json:"views"`
+	UniqueViews int64  `json:"uniqueViews"`
+}
+
+type analyticsQueryResponse struct {
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	GroupBy    string              `json:"groupBy"`
+	Rows       []analyticsQueryRow `json:"rows"`
+	NextCursor *string             `json:"nextCursor"`
+}
+
+// queryCursor is the last row of a page. Ranked groups need the view count as
+// well as the key, because that is what they are ordered by.
+type queryCursor struct {
+	GroupBy string `json:"g"`
+	Key     string `json:"k"`
+	Views   int64  `json:"v,omitempty"`
+}
+
+func encodeQueryCursor(c queryCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeQueryCursor(s string) (queryCursor, error) {
+	var c queryCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
+
+// queryDateRange reads the inclusive from/to dates, defaulting to the last 30
+// days. It returns the dates and the exclusive end of the range.
+func queryDateRange(fromParam, toParam string, now time.Time) (from, to time.Time, err error) {
+	to = now.UTC().Truncate(24 * time.Hour)
+	if toParam != "" {
+		if to, err = time.Parse("2006-01-02", toParam); err != nil {
+			return from, to, fmt.Errorf("invalid to date: use YYYY-MM-DD")
+		}
+	}
+	from = to.AddDate(0, 0, -(defaultQueryDays - 1))
+	if fromParam != "" {
+		if from, err = time.Parse("2006-01-02", fromParam); err != nil {
+			return from, to, fmt.Errorf("invalid from date: use YYYY-MM-DD")
+		}
+	}
+	if from.After(to) {
+		return from, to, fmt.Errorf("from must not be after to")
+	}
+	if to.Sub(from) >= maxQueryDays*24*time.Hour {
+		return from, to, fmt.Errorf("date range must not exceed %d days", maxQueryDays)
+	}
+	return from, to, nil
+}
+
+// queryVideoIDs accepts video_id repeated, comma-separated, or both.
+func queryVideoIDs(values []string) ([]string, error) {
+	var ids []string
+	for _, v := range values {
+		for _, id := range strings.Split(v, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			if !queryIDPattern.MatchString(id) {
+				return nil, fmt.Errorf("invalid video_id %q", id)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > maxQueryVideoIDs {
+		return nil, fmt.Errorf("at most %d video IDs", maxQueryVideoIDs)
+	}
+	return ids, nil
+}
+
+// AnalyticsQuery is the read-only analytics API for scripts and BI tools. It
+// aggregates raw views over any date range, grouped by time or by a dimension,
+// and pages through the result with an opaque cursor.
+func (h *Handler) AnalyticsQuery(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	from, to, err := queryDateRange(q.Get("from"), q.Get("to"), time.Now())
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	groupBy := q.Get("group_by")
+	if groupBy == "" {
+		groupBy = "day"
+	}
+	group, ok := queryGroups[groupBy]
+	if !ok {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid group_by: must be day, week, video, country, device, referrer, or campaign")
+		return
+	}
+
+	limit := defaultQueryLimit
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxQueryLimit {
+			httputil.WriteError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxQueryLimit))
+			return
+		}
+		limit = n
+	}
+
+	var cursor *queryCursor
+	if s := q.Get("cursor"); s != "" {
+		c, err := decodeQueryCursor(s)
+		if err != nil || c.GroupBy != groupBy {
+			httputil.WriteError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		cursor = &c
+	}
+
+	folderID, tagID := q.Get("folder_id"), q.Get("tag_id")
+	if (folderID != "" && !queryIDPattern.MatchString(folderID)) || (tagID != "" && !queryIDPattern.MatchString(tagID)) {
+		httputil.WriteError(w, http.StatusBadRequest, "folder_id and tag_id must be UUIDs")
+		return
+	}
+	videoIDs, err := queryVideoIDs(q["video_id"])
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ownerFilter, ownerArg := analyticsOwnerFilter(r.Context())
+
+	args := []any{ownerArg, from, to.AddDate(0, 0, 1)}
+	where := ownerFilter + ` AND v.status != 'deleted' AND vv.created_at >= $2 AND vv.created_at < $3` +
+		botSQL("vv.", q.Get("include_bots") == "true")
+	if folderID != "" {
+		args = append(args, folderID)
+		where += fmt.Sprintf(` AND v.folder_id = $%d`, len(args))
+	}
+	if tagID != "" {
+		args = append(args, tagID)
+		where += fmt.Sprintf(` AND EXISTS (SELECT 1 FROM video_tags vt WHERE vt.video_id = v.id AND vt.tag_id = $%d)`, len(args))
+	}
+	if len(videoIDs) > 0 {
+		args = append(args, videoIDs)
+		where += fmt.Sprintf(` AND v.id = ANY($%d::uuid[])`, len(args))
+	}
+
+	orderBy := `key`
+	if !group.series {
+		orderBy = `views DESC, key`
+	}
+	var after string
+	if cursor != nil {
+		args = append(args, cursor.Key)
+		if group.series {
+			after = fmt.Sprintf(`WHERE key > $%d`, len(args))
+		} else {
+			args = append(args, cursor.Views)
+			after = fmt.Sprintf(`WHERE views < $%[2]d OR (views = $%[2]d AND key > $%[1]d)`, len(args)-1, len(args))
+		}
+	}
+	args = append(args, limit+1)
+
+	rows, err := h.db.Query(r.Context(),
+		fmt.Sprintf(`SELECT key, label, views, unique_views FROM (
+		   SELECT %s AS key, MAX(%s) AS label, COUNT(*) AS views, COUNT(DISTINCT vv.viewer_hash) AS unique_views
+		   FROM video_views vv
+		   JOIN videos v ON v.id = vv.video_id
+		   WHERE %s
+		   GROUP BY 1
+		 ) q
+		 %s
+		 ORDER BY %s
+		 LIMIT $%d`, group.key, group.label, where, after, orderBy, len(args)),
+		args...,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to query analytics")
+		return
+	}
+	defer rows.Close()
+
+	result := make([]analyticsQueryRow, 0, min(limit, 64))
+	for rows.Next() {
+		var row analyticsQueryRow
+		if err := rows.Scan(&row.Key, &row.Label, &row.Views, &row.UniqueViews); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "failed to scan analytics")
+			return
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to read analytics")
+		return
+	}
+
+	resp := analyticsQueryResponse{
+		From:    from.Format("2006-01-02"),
+		To:      to.Format("2006-01-02"),
+		GroupBy: groupBy,
+	}
+	if len(result) > limit {
+		result = result[:limit]
+		last := result[limit-1]
+		next := encodeQueryCursor(queryCursor{GroupBy: groupBy, Key: last.Key, Views: last.Views})
+		resp.NextCursor = &next
+	}
+	resp.Rows = result
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,178 @@
+package video
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/auth"
+)
+
+var analyticsQueryColumns = []string{"key", "label", "views", "unique_views"}
+
+func analyticsQueryRouter(handler *Handler) *chi.Mux {
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/analytics/query", handler.AnalyticsQuery)
+	return r
+}
+
+func TestAnalyticsQuery_GroupsByDayWithinRange(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT key, label, views, unique_views FROM \(\s+SELECT to_char\(vv\.created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD'\) AS key`).
+		WithArgs(testUserID, from, from.AddDate(0, 0, 3), 101).
+		WillReturnRows(pgxmock.NewRows(analyticsQueryColumns).
+			AddRow("2026-01-01", "2026-01-01", int64(12), int64(9)).
+			AddRow("2026-01-03", "2026-01-03", int64(4), int64(4)))
+
+	rec := httptest.NewRecorder()
+	analyticsQueryRouter(handler).ServeHTTP(rec,
+		authenticatedRequest(t, http.MethodGet, "/api/analytics/query?from=2026-01-01&to=2026-01-03", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp analyticsQueryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.From != "2026-01-01" || resp.To != "2026-01-03" || resp.GroupBy != "day" {
+		t.Errorf("unexpected range %+v", resp)
+	}
+	if len(resp.Rows) != 2 || resp.Rows[0].Views != 12 || resp.Rows[1].UniqueViews != 4 {
+		t.Errorf("unexpected rows %+v", resp.Rows)
+	}
+	if resp.NextCursor != nil {
+		t.Errorf("expected no next cursor, got %q", *resp.NextCursor)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestAnalyticsQuery_ByVideoLeavesOutReplyClips(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	mock.ExpectQuery(`SELECT v\.id::text AS key(.|\n)*WHERE v\.user_id = \$1 AND v\.parent_video_id IS NULL AND`).
+		WithArgs(testUserID, pgxmock.AnyArg(), pgxmock.AnyArg(), 101).
+		WillReturnRows(pgxmock.NewRows(analyticsQueryColumns).AddRow("video-1", "Demo", int64(5), int64(3)))
+
+	rec := httptest.NewRecorder()
+	analyticsQueryRouter(handler).ServeHTTP(rec,
+		authenticatedRequest(t, http.MethodGet, "/api/analytics/query?group_by=video", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestAnalyticsQuery_PagesRankedGroupsWithCursor(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	folderID := "11111111-2222-3333-4444-555555555555"
+	videoA := "aaaaaaaa-0000-0000-0000-000000000001"
+	videoB := "aaaaaaaa-0000-0000-0000-000000000002"
+
+	mock.ExpectQuery(`AND v\.folder_id = \$4 AND v\.id = ANY\(\$5::uuid\[\]\)\s+GROUP BY 1\s+\) q\s+ORDER BY views DESC, key\s+LIMIT \$6`).
+		WithArgs(testOrgID, pgxmock.AnyArg(), pgxmock.AnyArg(), folderID, []string{videoA, videoB}, 2).
+		WillReturnRows(pgxmock.NewRows(analyticsQueryColumns).
+			AddRow("DE", "DE", int64(30), int64(20)).
+			AddRow("FR", "FR", int64(10), int64(8)))
+
+	path := "/api/analytics/query?group_by=country&limit=1&folder_id=" + folderID + "&video_id=" + videoA + "," + videoB
+	req := authenticatedRequest(t, http.MethodGet, path, nil)
+	req = req.WithContext(auth.ContextWithOrg(req.Context(), testOrgID, "member"))
+	rec := httptest.NewRecorder()
+	analyticsQueryRouter(handler).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp analyticsQueryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Rows) != 1 || resp.Rows[0].Key != "DE" || resp.NextCursor == nil {
+		t.Fatalf("expected one row and a next cursor, got %+v", resp)
+	}
+
+	mock.ExpectQuery(`WHERE views < \$5 OR \(views = \$5 AND key > \$4\)\s+ORDER BY views DESC, key\s+LIMIT \$6`).
+		WithArgs(testUserID, pgxmock.AnyArg(), pgxmock.AnyArg(), "DE", int64(30), 2).
+		WillReturnRows(pgxmock.NewRows(analyticsQueryColumns).
+			AddRow("FR", "FR", int64(10), int64(8)))
+
+	rec = httptest.NewRecorder()
+	analyticsQueryRouter(handler).ServeHTTP(rec, authenticatedRequest(t, http.MethodGet,
+		"/api/analytics/query?group_by=country&limit=1&cursor="+*resp.NextCursor, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	resp = analyticsQueryResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Rows) != 1 || resp.Rows[0].Key != "FR" || resp.NextCursor != nil {
+		t.Errorf("expected the last page, got %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestAnalyticsQuery_RejectsInvalidParameters(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	r := analyticsQueryRouter(handler)
+	dayCursor := encodeQueryCursor(queryCursor{GroupBy: "day", Key: "2026-01-01"})
+
+	for _, query := range []string{
+		"from=2026-02-01&to=2026-01-01",
+		"from=2020-01-01&to=2026-01-01",
+		"from=01/02/2026",
+		"group_by=browser",
+		"limit=0",
+		"limit=5000",
+		"folder_id=not-a-uuid",
+		"video_id=abc",
+		"cursor=bm90LWpzb24",
+		"group_by=video&cursor=" + dayCursor,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/analytics/query?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
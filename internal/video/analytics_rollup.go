@@ -0,0 +1,243 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sendrec/sendrec/internal/database"
+)
+
+const (
+	analyticsRollupName = "daily"
+	// Rows are stamped with now() when their transaction starts, so a slow
+	// insert can commit with a created_at just behind the watermark. Each pass
+	// re-reads this much history to catch them; recomputing a day is idempotent.
+	analyticsRollupOverlap = 2 * time.Minute
+)
+
+// rollupAnalyticsWindow folds the views, milestones and CTA clicks recorded in
+// [from, to) into video_daily_stats and video_viewer_days. Every video/day
+// touched by the window is recomputed in full from the raw tables, so windows
+// may overlap freely. Bot views are left out; a suspect view confirmed by
+// playback in the window counts again from the day it was recorded. Viewers'
+// reply clips are not videos the owner shares, so they get no rollups.
+func rollupAnalyticsWindow(ctx context.Context, db database.DBTX, from, to time.Time) error {
+	if _, err := db.Exec(ctx,
+		`INSERT INTO video_viewer_first_seen (video_id, viewer_hash, first_seen_at)
+		 SELECT vv.video_id, vv.viewer_hash, MIN(vv.created_at)
+		 FROM video_views vv
+		 JOIN videos v ON v.id = vv.video_id AND v.parent_video_id IS NULL
+		 WHERE NOT vv.is_bot
+		   AND ((vv.created_at >= $1 AND vv.created_at < $2) OR (vv.confirmed_at >= $1 AND vv.confirmed_at < $2))
+		 GROUP BY vv.video_id, vv.viewer_hash
+		 ON CONFLICT (video_id, viewer_hash)
+		 DO UPDATE SET first_seen_at = LEAST(video_viewer_first_seen.first_seen_at, EXCLUDED.first_seen_at)`,
+		from, to,
+	); err != nil {
+		return fmt.Errorf("record first-seen viewers: %w", err)
+	}
+
+	if _, err := db.Exec(ctx,
+		`INSERT INTO video_viewer_days (video_id, day, viewer_hash)
+		 SELECT DISTINCT vv.video_id, (vv.created_at AT TIME ZONE 'UTC')::date, vv.viewer_hash
+		 FROM video_views vv
+		 JOIN videos v ON v.id = vv.video_id AND v.parent_video_id IS NULL
+		 WHERE NOT vv.is_bot
+		   AND ((vv.created_at >= $1 AND vv.created_at < $2) OR (vv.confirmed_at >= $1 AND vv.confirmed_at < $2))
+		 ON CONFLICT DO NOTHING`,
+		from, to,
+	); err != nil {
+		return fmt.Errorf("record viewer days: %w", err)
+	}
+
+	// Milestones are recorded once per viewer, so ordering a viewer's by
+	// percentage and taking the step from the previous one credits each day
+	// with the new part of the video they reached. The steps add up to the
+	// viewer's highest milestone, however many days it took.
+	if _, err := db.Exec(ctx,
+		`WITH touched AS (
+		     SELECT video_id, viewer_hash FROM view_milestones
+		     WHERE created_at >= $1 AND created_at < $2
+		     UNION
+		     SELECT video_id, viewer_hash FROM video_views
+		     WHERE NOT is_bot
+		       AND ((created_at >= $1 AND created_at < $2) OR (confirmed_at >= $1 AND confirmed_at < $2))
+		 ), steps AS (
+		     SELECT m.video_id, m.viewer_hash, (m.created_at AT TIME ZONE 'UTC')::date AS day,
+		            m.milestone - LAG(m.milestone, 1, 0) OVER (PARTITION BY m.video_id, m.viewer_hash ORDER BY m.milestone) AS step
+		     FROM view_milestones m
+		     JOIN touched t ON t.video_id = m.video_id AND t.viewer_hash = m.viewer_hash
+		 )
+		 UPDATE video_viewer_days vd SET progress = p.progress
+		 FROM (
+		     SELECT video_id, viewer_hash, day, SUM(step) AS progress
+		     FROM steps GROUP BY video_id, viewer_hash, day
+		 ) p
+		 WHERE vd.video_id = p.video_id AND vd.viewer_hash = p.viewer_hash AND vd.day = p.day
+		   AND vd.progress <> p.progress`,
+		from, to,
+	); err != nil {
+		return fmt.Errorf("record viewer progress: %w", err)
+	}
+
+	if _, err := db.Exec(ctx,
+		`WITH dirty AS (
+		     SELECT video_id, (created_at AT TIME ZONE 'UTC')::date AS day FROM video_views
+		     WHERE created_at >= $1 AND created_at < $2
+		     UNION
+		     SELECT video_id, (created_at AT TIME ZONE 'UTC')::date FROM video_views
+		     WHERE confirmed_at >= $1 AND confirmed_at < $2
+		     UNION
+		     SELECT video_id, (created_at AT TIME ZONE 'UTC')::date FROM view_milestones
+		     WHERE created_at >= $1 AND created_at < $2
+		     UNION
+		     SELECT video_id, (created_at AT TIME ZONE 'UTC')::date FROM cta_clicks
+		     WHERE created_at >= $1 AND created_at < $2
+		 )
+		 INSERT INTO video_daily_stats (video_id, day, views, unique_viewers, new_viewers, watch_seconds,
+		                                milestone_25, milestone_50, milestone_75, milestone_100, cta_clicks, updated_at)
+		 SELECT d.video_id, d.day, vw.views, vw.unique_viewers, fs.new_viewers, wp.progress * v.duration / 100,
+		        ms.m25, ms.m50, ms.m75, ms.m100, cc.clicks, now()
+		 FROM dirty d
+		 JOIN videos v ON v.id = d.video_id AND v.parent_video_id IS NULL
+		 CROSS JOIN LATERAL (
+		     SELECT d.day::timestamp AT TIME ZONE 'UTC' AS day_start,
+		            (d.day + 1)::timestamp AT TIME ZONE 'UTC' AS day_end
+		 ) b
+		 CROSS JOIN LATERAL (
+		     SELECT COUNT(*) AS views, COUNT(DISTINCT viewer_hash) AS unique_viewers
+		     FROM video_views
+		     WHERE video_id = d.video_id AND created_at >= b.day_start AND created_at < b.day_end AND NOT is_bot
+		 ) vw
+		 CROSS JOIN LATERAL (
+		     SELECT COALESCE(SUM(progress), 0) AS progress
+		     FROM video_viewer_days
+		     WHERE video_id = d.video_id AND day = d.day
+		 ) wp
+		 CROSS JOIN LATERAL (
+		     SELECT COUNT(*) AS new_viewers
+		     FROM video_viewer_first_seen
+		     WHERE video_id = d.video_id AND first_seen_at >= b.day_start AND first_seen_at < b.day_end
+		 ) fs
+		 CROSS JOIN LATERAL (
+		     SELECT COUNT(DISTINCT viewer_hash) FILTER (WHERE milestone = 25) AS m25,
+		            COUNT(DISTINCT viewer_hash) FILTER (WHERE milestone = 50) AS m50,
+		            COUNT(DISTINCT viewer_hash) FILTER (WHERE milestone = 75) AS m75,
+		            COUNT(DISTINCT viewer_hash) FILTER (WHERE milestone = 100) AS m100
+		     FROM view_milestones
+		     WHERE video_id = d.video_id AND created_at >= b.day_start AND created_at < b.day_end
+		 ) ms
+		 CROSS JOIN LATERAL (
+		     SELECT COUNT(*) AS clicks
+		     FROM cta_clicks
+		     WHERE video_id = d.video_id AND created_at >= b.day_start AND created_at < b.day_end
+		 ) cc
+		 ON CONFLICT (video_id, day) DO UPDATE SET
+		     views = EXCLUDED.views,
+		     unique_viewers = EXCLUDED.unique_viewers,
+		     new_viewers = EXCLUDED.new_viewers,
+		     watch_seconds = EXCLUDED.watch_seconds,
+		     milestone_25 = EXCLUDED.milestone_25,
+		     milestone_50 = EXCLUDED.milestone_50,
+		     milestone_75 = EXCLUDED.milestone_75,
+		     milestone_100 = EXCLUDED.milestone_100,
+		     cta_clicks = EXCLUDED.cta_clicks,
+		     updated_at = now()`,
+		from, to,
+	); err != nil {
+		return fmt.Errorf("upsert daily stats: %w", err)
+	}
+	return nil
+}
+
+// processAnalyticsRollup rolls up everything recorded since the stored
+// watermark and advances it. Timestamps come from the database clock so the
+// window lines up with the created_at defaults of the raw tables.
+func processAnalyticsRollup(ctx context.Context, db database.DBTX) {
+	var watermark, now time.Time
+	err := db.QueryRow(ctx,
+		`SELECT watermark, now() FROM analytics_rollup_state WHERE name = $1`,
+		analyticsRollupName,
+	).Scan(&watermark, &now)
+	if err != nil {
+		slog.Error("analytics-rollup: failed to read watermark", "error", err)
+		return
+	}
+
+	if err := rollupAnalyticsWindow(ctx, db, watermark.Add(-analyticsRollupOverlap), now); err != nil {
+		slog.Error("analytics-rollup: rollup failed", "error", err)
+		return
+	}
+
+	if _, err := db.Exec(ctx,
+		`UPDATE analytics_rollup_state SET watermark = $2 WHERE name = $1`,
+		analyticsRollupName, now,
+	); err != nil {
+		slog.Error("analytics-rollup: failed to advance watermark", "error", err)
+	}
+}
+
+// StartAnalyticsRollupWorker keeps video_daily_stats current by rolling up new
+// analytics rows every interval, and compacts newly recorded watch coverage.
+func StartAnalyticsRollupWorker(ctx context.Context, db database.DBTX, interval time.Duration) {
+	go func() {
+		slog.Info("analytics-rollup: started", "interval", interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("analytics-rollup: shutting down")
+				return
+			case <-ticker.C:
+				processAnalyticsRollup(ctx, db)
+				compactWatchCoverage(ctx, db)
+			}
+		}
+	}()
+}
+
+// BackfillAnalyticsRollups rebuilds the rollups for rows recorded since the
+// given time, one UTC day at a time so no single statement scans the whole
+// history. A zero since starts from the oldest recorded view. It is safe to
+// run while the worker is active.
+func BackfillAnalyticsRollups(ctx context.Context, db database.DBTX, since time.Time) (int, error) {
+	var oldest *time.Time
+	var now time.Time
+	if err := db.QueryRow(ctx,
+		`SELECT LEAST(
+		     (SELECT MIN(created_at) FROM video_views),
+		     (SELECT MIN(created_at) FROM view_milestones),
+		     (SELECT MIN(created_at) FROM cta_clicks)
+		 ), now()`,
+	).Scan(&oldest, &now); err != nil {
+		return 0, fmt.Errorf("find oldest analytics row: %w", err)
+	}
+	if oldest == nil {
+		return 0, nil
+	}
+
+	start := since
+	if start.IsZero() || start.Before(*oldest) {
+		start = *oldest
+	}
+	start = start.UTC().Truncate(24 * time.Hour)
+
+	days := 0
+	for day := start; day.Before(now); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return days, err
+		}
+		end := day.AddDate(0, 0, 1)
+		if end.After(now) {
+			end = now
+		}
+		if err := rollupAnalyticsWindow(ctx, db, day, end); err != nil {
+			return days, fmt.Errorf("backfill %s: %w", day.Format("2006-01-02"), err)
+		}
+		days++
+	}
+	return days, nil
+}
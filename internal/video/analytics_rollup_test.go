@@ -0,0 +1,157 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func expectRollupWindow(mock pgxmock.PgxPoolIface, from, to time.Time) {
+	mock.ExpectExec(`INSERT INTO video_viewer_first_seen(.|\n)*JOIN videos v ON v\.id = vv\.video_id AND v\.parent_video_id IS NULL`).
+		WithArgs(from, to).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectExec(`INSERT INTO video_viewer_days(.|\n)*JOIN videos v ON v\.id = vv\.video_id AND v\.parent_video_id IS NULL`).
+		WithArgs(from, to).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectExec(`UPDATE video_viewer_days`).
+		WithArgs(from, to).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectExec(`INSERT INTO video_daily_stats(.|\n)*JOIN videos v ON v\.id = d\.video_id AND v\.parent_video_id IS NULL`).
+		WithArgs(from, to).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+}
+
+func TestProcessAnalyticsRollup_AdvancesWatermark(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	watermark := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := watermark.Add(time.Minute)
+
+	mock.ExpectQuery(`SELECT watermark, now\(\) FROM analytics_rollup_state`).
+		WithArgs(analyticsRollupName).
+		WillReturnRows(pgxmock.NewRows([]string{"watermark", "now"}).AddRow(watermark, now))
+	expectRollupWindow(mock, watermark.Add(-analyticsRollupOverlap), now)
+	mock.ExpectExec(`UPDATE analytics_rollup_state SET watermark = \$2`).
+		WithArgs(analyticsRollupName, now).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	processAnalyticsRollup(context.Background(), mock)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestProcessAnalyticsRollup_KeepsWatermarkOnFailure(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	watermark := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := watermark.Add(time.Minute)
+
+	mock.ExpectQuery(`SELECT watermark, now\(\) FROM analytics_rollup_state`).
+		WithArgs(analyticsRollupName).
+		WillReturnRows(pgxmock.NewRows([]string{"watermark", "now"}).AddRow(watermark, now))
+	mock.ExpectExec(`INSERT INTO video_viewer_first_seen`).
+		WithArgs(watermark.Add(-analyticsRollupOverlap), now).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectExec(`INSERT INTO video_viewer_days`).
+		WithArgs(watermark.Add(-analyticsRollupOverlap), now).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectExec(`UPDATE video_viewer_days`).
+		WithArgs(watermark.Add(-analyticsRollupOverlap), now).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectExec(`INSERT INTO video_daily_stats`).
+		WithArgs(watermark.Add(-analyticsRollupOverlap), now).
+		WillReturnError(errors.New("deadlock detected"))
+
+	processAnalyticsRollup(context.Background(), mock)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestBackfillAnalyticsRollups_ProcessesOneDayAtATime(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	oldest := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 3, 6, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT LEAST`).
+		WillReturnRows(pgxmock.NewRows([]string{"oldest", "now"}).AddRow(&oldest, now))
+	expectRollupWindow(mock, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	expectRollupWindow(mock, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC))
+	expectRollupWindow(mock, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), now)
+
+	days, err := BackfillAnalyticsRollups(context.Background(), mock, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if days != 3 {
+		t.Errorf("expected 3 days, got %d", days)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestBackfillAnalyticsRollups_StartsAtSince(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	oldest := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 3, 6, 0, 0, 0, time.UTC)
+	since := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT LEAST`).
+		WillReturnRows(pgxmock.NewRows([]string{"oldest", "now"}).AddRow(&oldest, now))
+	expectRollupWindow(mock, since, now)
+
+	days, err := BackfillAnalyticsRollups(context.Background(), mock, since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if days != 1 {
+		t.Errorf("expected 1 day, got %d", days)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestBackfillAnalyticsRollups_NoData(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT LEAST`).
+		WillReturnRows(pgxmock.NewRows([]string{"oldest", "now"}).AddRow((*time.Time)(nil), time.Now()))
+
+	days, err := BackfillAnalyticsRollups(context.Background(), mock, time.Time{})
+	if err != nil || days != 0 {
+		t.Errorf("expected no work, got %d days, err %v", days, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
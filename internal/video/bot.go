@@ -0,0 +1,150 @@
+package video
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mssola/useragent"
+)
+
+type viewClass int
+
+const (
+	viewHuman viewClass = iota
+	// viewSuspect looks like a browser but lacks headers real browsers send.
+	// It is stored as a bot until a playback beacon confirms it.
+	viewSuspect
+	viewBot
+)
+
+const (
+	botReasonUserAgent  = "user_agent"
+	botReasonUnfurler   = "unfurler"
+	botReasonHeadless   = "headless"
+	botReasonPrefetch   = "prefetch"
+	botReasonNoPlayback = "no_playback"
+
+	// botConfirmWindow is how long a view waits for a playback beacon. A
+	// suspect view then stays counted as bot traffic, and no view is
+	// announced to the owner after it.
+	botConfirmWindow = 10 * time.Minute
+)
+
+// unfurlerSignatures are lower-cased user agent fragments of link preview
+// fetchers and email security scanners, which open every link they see.
+var unfurlerSignatures = []string{
+	"slackbot", "slack-imgproxy", "twitterbot", "facebookexternalhit", "facebookcatalog",
+	"linkedinbot", "discordbot", "telegrambot", "whatsapp", "skypeuripreview",
+	"microsoftpreview", "bingpreview", "embedly", "iframely", "redditbot",
+	"pinterestbot", "mastodon", "google-pagerenderer", "googleother", "applebot",
+	"mimecast", "proofpoint", "barracuda", "safelinks", "forcepoint", "ironport",
+	"trendmicro", "symantec",
+}
+
+var headlessSignatures = []string{
+	"headlesschrome", "phantomjs", "puppeteer", "playwright", "selenium",
+}
+
+var automationSignatures = []string{
+	"bot", "crawler", "spider", "curl/", "wget/", "python-requests", "python-urllib",
+	"go-http-client", "java/", "okhttp", "axios/", "node-fetch", "libwww-perl", "httpclient",
+	"scanner", "preview",
+}
+
+// classifyViewRequest decides whether a watch or embed request is a person.
+// Link unfurlers and scripted clients are identified by their user agent;
+// browser-like requests without the headers every real browser sends on a
+// page load are only suspect, since some privacy tools strip them too.
+func classifyViewRequest(r *http.Request) (viewClass, string) {
+	purpose := strings.ToLower(r.Header.Get("Sec-Purpose") + r.Header.Get("Purpose") + r.Header.Get("X-Moz"))
+	if strings.Contains(purpose, "prefetch") || strings.Contains(purpose, "prerender") {
+		return viewBot, botReasonPrefetch
+	}
+
+	raw := r.UserAgent()
+	if strings.TrimSpace(raw) == "" {
+		return viewBot, botReasonUserAgent
+	}
+	ua := strings.ToLower(raw)
+	if containsAny(ua, unfurlerSignatures) {
+		return viewBot, botReasonUnfurler
+	}
+	if containsAny(ua, headlessSignatures) {
+		return viewBot, botReasonHeadless
+	}
+	if containsAny(ua, automationSignatures) || useragent.New(raw).Bot() {
+		return viewBot, botReasonUserAgent
+	}
+
+	if r.Header.Get("Accept-Language") == "" {
+		return viewSuspect, botReasonNoPlayback
+	}
+	return viewHuman, ""
+}
+
+func containsAny(s string, fragments []string) bool {
+	for _, f := range fragments {
+		if strings.Contains(s, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// botSQL excludes bot views unless the caller asked to see them.
+func botSQL(prefix string, includeBots bool) string {
+	if includeBots {
+		return ""
+	}
+	return " AND NOT " + prefix + "is_bot"
+}
+
+// confirmPlayback sends the view notification held back for the viewer's
+// recent views once they start playback, and clears the bot flag from any of
+// those views that were only suspect. A view whose viewer never plays within
+// botConfirmWindow is never announced.
+func (h *Handler) confirmPlayback(ctx context.Context, videoID, hash string) {
+	var confirmed int64
+	var suspect bool
+	if err := h.db.QueryRow(ctx,
+		`WITH pending AS (
+		     SELECT id, is_bot FROM video_views
+		     WHERE video_id = $1 AND viewer_hash = $2 AND notify_pending
+		       AND created_at >= now() - make_interval(secs => $3)
+		     FOR UPDATE
+		 ), confirmed AS (
+		     UPDATE video_views vv SET notify_pending = false, is_bot = false, bot_reason = NULL,
+		            confirmed_at = CASE WHEN p.is_bot THEN now() ELSE vv.confirmed_at END
+		     FROM pending p
+		     WHERE vv.id = p.id
+		     RETURNING p.is_bot AS suspect
+		 )
+		 SELECT COUNT(*), COALESCE(bool_or(suspect), false) FROM confirmed`,
+		videoID, hash, botConfirmWindow.Seconds(),
+	).Scan(&confirmed, &suspect); err != nil {
+		slog.Error("failed to confirm view playback", "video_id", videoID, "error", err)
+		return
+	}
+	if confirmed == 0 {
+		return
+	}
+	// A suspect view was not announced live when it was recorded.
+	if suspect {
+		h.publishLive(ctx, liveEventView, videoID, hash, nil)
+	}
+
+	var p viewParams
+	if err := h.db.QueryRow(ctx,
+		`SELECT v.user_id, u.email, u.name, v.title, v.share_token, v.view_notification
+		 FROM videos v JOIN users u ON u.id = v.user_id
+		 WHERE v.id = $1`,
+		videoID,
+	).Scan(&p.ownerID, &p.ownerEmail, &p.ownerName, &p.title, &p.shareToken, &p.viewNotification); err != nil {
+		slog.Error("failed to load video for confirmed view", "video_id", videoID, "error", err)
+		return
+	}
+	h.resolveAndNotify(ctx, videoID, p.ownerID, p.ownerEmail, p.ownerName, p.title, p.shareToken, "", p.viewNotification)
+}
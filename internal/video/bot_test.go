@@ -0,0 +1,190 @@
+package video
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+const testChromeUA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36"
+
+func TestClassifyViewRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		userAgent  string
+		headers    map[string]string
+		wantClass  viewClass
+		wantReason string
+	}{
+		{"browser", testChromeUA, map[string]string{"Accept-Language": "en-GB"}, viewHuman, ""},
+		{"missing user agent", "", nil, viewBot, botReasonUserAgent},
+		{"slack unfurler", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", nil, viewBot, botReasonUnfurler},
+		{"teams preview", "Mozilla/5.0 (Windows NT 6.1; WOW64) SkypeUriPreview Preview/0.5", nil, viewBot, botReasonUnfurler},
+		{"linkedin", "LinkedInBot/1.0 (compatible; Mozilla/5.0; Apache-HttpClient +http://www.linkedin.com)", nil, viewBot, botReasonUnfurler},
+		{"headless chrome", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/130.0.0.0 Safari/537.36", map[string]string{"Accept-Language": "en-US"}, viewBot, botReasonHeadless},
+		{"curl", "curl/8.4.0", nil, viewBot, botReasonUserAgent},
+		{"prefetch", testChromeUA, map[string]string{"Accept-Language": "en", "Sec-Purpose": "prefetch;prerender"}, viewBot, botReasonPrefetch},
+		{"browser without accept-language", testChromeUA, nil, viewSuspect, botReasonNoPlayback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/watch/abc123", nil)
+			r.Header.Set("User-Agent", tt.userAgent)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			class, reason := classifyViewRequest(r)
+			if class != tt.wantClass || reason != tt.wantReason {
+				t.Errorf("got (%d, %q), want (%d, %q)", class, reason, tt.wantClass, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRecordViewAsync_FlagsBotsWithoutNotifying(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	slack := &mockSlackNotifier{}
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetSlackNotifier(slack)
+
+	mock.ExpectExec(`INSERT INTO video_views`).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			nil, nil, nil, nil, nil, nil, true, botReasonUnfurler, false).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	req := httptest.NewRequest(http.MethodGet, "/watch/abc123", nil)
+	req.Header.Set("User-Agent", "facebookexternalhit/1.1")
+	handler.recordViewAsync(req, viewParams{videoID: "vid-1", ownerID: "owner-1", shareToken: "abc123"})
+
+	time.Sleep(100 * time.Millisecond)
+
+	if slack.viewCalled {
+		t.Error("expected no view notification for a bot")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestRecordViewAsync_HoldsNotificationUntilPlayback(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	slack := &mockSlackNotifier{}
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetSlackNotifier(slack)
+
+	// An email security scanner that sends full browser headers is counted
+	// as a view, but it never plays the video.
+	mock.ExpectExec(`INSERT INTO video_views`).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			nil, nil, nil, nil, nil, nil, false, nil, true).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	req := httptest.NewRequest(http.MethodGet, "/watch/abc123", nil)
+	req.Header.Set("User-Agent", testChromeUA)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	handler.recordViewAsync(req, viewParams{videoID: "vid-1", ownerID: "owner-1", shareToken: "abc123"})
+
+	time.Sleep(100 * time.Millisecond)
+
+	if slack.viewCalled {
+		t.Error("expected the notification to wait for a playback beacon")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestRecordViewAsync_NotifiesOwnViewImmediately(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectExec(`INSERT INTO video_views`).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			nil, nil, nil, nil, nil, nil, false, nil, false).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	req := httptest.NewRequest(http.MethodGet, "/watch/abc123", nil)
+	req.Header.Set("User-Agent", testChromeUA)
+	req.Header.Set("Accept-Language", "en")
+	handler.recordViewAsync(req, viewParams{videoID: "vid-1", ownerID: "owner-1", viewerUserID: "owner-1", shareToken: "abc123"})
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func expectConfirmPlayback(mock pgxmock.PgxPoolIface, confirmed int64, suspect bool) {
+	mock.ExpectQuery(`UPDATE video_views vv SET notify_pending = false, is_bot = false, bot_reason = NULL`).
+		WithArgs("vid-1", "viewer-1", botConfirmWindow.Seconds()).
+		WillReturnRows(pgxmock.NewRows([]string{"count", "suspect"}).AddRow(confirmed, suspect))
+}
+
+func TestConfirmPlayback_NotifiesHeldBackView(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	slack := &mockSlackNotifier{}
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetSlackNotifier(slack)
+
+	expectConfirmPlayback(mock, 1, false)
+	mock.ExpectQuery(`SELECT v.user_id, u.email, u.name, v.title, v.share_token, v.view_notification`).
+		WithArgs("vid-1").
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "email", "name", "title", "share_token", "view_notification"}).
+			AddRow("owner-1", "owner@example.com", "Owner", "Demo", "abc123", nil))
+
+	handler.confirmPlayback(context.Background(), "vid-1", "viewer-1")
+
+	if !slack.viewCalled {
+		t.Error("expected the held-back view notification")
+	}
+}
+
+func TestConfirmPlayback_NothingPending(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	slack := &mockSlackNotifier{}
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetSlackNotifier(slack)
+
+	// Nothing is pending once botConfirmWindow has passed, so a late beacon
+	// does not announce the view either.
+	expectConfirmPlayback(mock, 0, false)
+
+	handler.confirmPlayback(context.Background(), "vid-1", "viewer-1")
+
+	if slack.viewCalled {
+		t.Error("expected no notification when no view is pending")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
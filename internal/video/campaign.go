@@ -0,0 +1,197 @@
+package video
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sendrec/sendrec/internal/database"
+)
+
+// campaignFields are both the query parameters a share link can carry and the
+// video_views columns they are stored in, in campaignParams field order.
+var campaignFields = []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "ref"}
+
+const (
+	maxCampaignValueLength = 200
+	maxCampaignRows        = 50
+)
+
+// campaignParams is the attribution attached to a view. The same values, read
+// from the analytics query string, filter the analytics to matching views.
+type campaignParams struct {
+	Source   string `json:"source"`
+	Medium   string `json:"medium"`
+	Campaign string `json:"campaign"`
+	Term     string `json:"term"`
+	Content  string `json:"content"`
+	Ref      string `json:"ref"`
+}
+
+func parseCampaignParams(q url.Values) campaignParams {
+	v := make([]string, len(campaignFields))
+	for i, field := range campaignFields {
+		v[i] = cleanCampaignValue(q.Get(field))
+	}
+	return campaignParams{Source: v[0], Medium: v[1], Campaign: v[2], Term: v[3], Content: v[4], Ref: v[5]}
+}
+
+// cleanCampaignValue trims a parameter and drops control characters so a
+// crafted link cannot inject line breaks into CSV exports or logs.
+func cleanCampaignValue(s string) string {
+	s = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s))
+	if runes := []rune(s); len(runes) > maxCampaignValueLength {
+		s = string(runes[:maxCampaignValueLength])
+	}
+	return s
+}
+
+func (c campaignParams) values() []string {
+	return []string{c.Source, c.Medium, c.Campaign, c.Term, c.Content, c.Ref}
+}
+
+func (c campaignParams) isZero() bool {
+	return c == campaignParams{}
+}
+
+// insertArgs returns the values for the video_views columns, NULL when unset.
+func (c campaignParams) insertArgs() []any {
+	args := make([]any, 0, len(campaignFields))
+	for _, v := range c.values() {
+		if v == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, v)
+		}
+	}
+	return args
+}
+
+// filterSQL appends an equality condition for every set field, numbering the
+// placeholders after the args already collected.
+func (c campaignParams) filterSQL(prefix string, args []any) (string, []any) {
+	var b strings.Builder
+	for i, v := range c.values() {
+		if v == "" {
+			continue
+		}
+		args = append(args, v)
+		fmt.Fprintf(&b, " AND %s%s = $%d", prefix, campaignFields[i], len(args))
+	}
+	return b.String(), args
+}
+
+// query encodes the set fields back into a query string for links and exports.
+func (c campaignParams) query() string {
+	q := url.Values{}
+	for i, v := range c.values() {
+		if v != "" {
+			q.Set(campaignFields[i], v)
+		}
+	}
+	return q.Encode()
+}
+
+// viewStatsSource names the relation daily view metrics are read from. By
+// default that is the video_daily_stats rollup; the rollup has no campaign
+// dimension and leaves out bots, so a filtered request or one that includes
+// bot traffic aggregates the matching raw views into the same columns instead.
+// Watch time then counts the progress only the matching viewers made that day.
+func viewStatsSource(filter campaignParams, includeBots bool, args []any) (string, []any) {
+	if filter.isZero() && !includeBots {
+		return "video_daily_stats", args
+	}
+	cond, args := filter.filterSQL("vv.", args)
+	cond = botSQL("vv.", includeBots) + cond
+	return `(SELECT g.video_id, g.day, g.views, g.unique_viewers,
+		        (SELECT COALESCE(SUM(vd.progress), 0) FROM video_viewer_days vd
+		         WHERE vd.video_id = g.video_id AND vd.day = g.day AND vd.viewer_hash = ANY(g.viewers)) * fv.duration / 100 AS watch_seconds
+		 FROM (
+		     SELECT vv.video_id, (vv.created_at AT TIME ZONE 'UTC')::date AS day,
+		            COUNT(*) AS views, COUNT(DISTINCT vv.viewer_hash) AS unique_viewers,
+		            array_agg(DISTINCT vv.viewer_hash) AS viewers
+		     FROM video_views vv
+		     WHERE true` + cond + `
+		     GROUP BY vv.video_id, day
+		 ) g
+		 JOIN videos fv ON fv.id = g.video_id)`, args
+}
+
+// viewerDaysSource names the relation unique viewers over a range are counted
+// from: one row per viewer, video and day. Daily unique counts cannot simply
+// be added up, since a viewer who returns on three days is one viewer. Given
+// the same args it numbers its placeholders exactly as viewStatsSource does,
+// so both can appear in one query.
+func viewerDaysSource(filter campaignParams, includeBots bool, args []any) (string, []any) {
+	if filter.isZero() && !includeBots {
+		return "video_viewer_days", args
+	}
+	cond, args := filter.filterSQL("vv.", args)
+	cond = botSQL("vv.", includeBots) + cond
+	return `(SELECT DISTINCT vv.video_id, (vv.created_at AT TIME ZONE 'UTC')::date AS day, vv.viewer_hash
+		 FROM video_views vv
+		 WHERE true` + cond + `)`, args
+}
+
+type campaignStats struct {
+	campaignParams
+	Views       int64   `json:"views"`
+	UniqueViews int64   `json:"uniqueViews"`
+	Percentage  float64 `json:"percentage"`
+}
+
+// campaignBreakdown groups attributed views by their full campaign tuple.
+// scope is a condition on video_views vv joined to videos v, using args.
+func campaignBreakdown(ctx context.Context, db database.DBTX, scope string, args []any, totalViews int64) []campaignStats {
+	campaigns := make([]campaignStats, 0)
+	rows, err := db.Query(ctx,
+		fmt.Sprintf(`SELECT COALESCE(vv.utm_source, ''), COALESCE(vv.utm_medium, ''), COALESCE(vv.utm_campaign, ''),
+		        COALESCE(vv.utm_term, ''), COALESCE(vv.utm_content, ''), COALESCE(vv.ref, ''),
+		        COUNT(*) AS views, COUNT(DISTINCT vv.viewer_hash) AS unique_views
+		 FROM video_views vv
+		 JOIN videos v ON v.id = vv.video_id
+		 WHERE %s
+		   AND (vv.utm_source IS NOT NULL OR vv.utm_medium IS NOT NULL OR vv.utm_campaign IS NOT NULL
+		        OR vv.utm_term IS NOT NULL OR vv.utm_content IS NOT NULL OR vv.ref IS NOT NULL)
+		 GROUP BY 1, 2, 3, 4, 5, 6
+		 ORDER BY views DESC
+		 LIMIT %d`, scope, maxCampaignRows),
+		args...,
+	)
+	if err != nil {
+		return campaigns
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cs campaignStats
+		if err := rows.Scan(&cs.Source, &cs.Medium, &cs.Campaign, &cs.Term, &cs.Content, &cs.Ref,
+			&cs.Views, &cs.UniqueViews); err != nil {
+			continue
+		}
+		cs.Percentage = percentOf(cs.Views, totalViews)
+		campaigns = append(campaigns, cs)
+	}
+	return campaigns
+}
+
+func writeCampaignCSV(w io.Writer, cw *csv.Writer, campaigns []campaignStats) {
+	if len(campaigns) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	_ = cw.Write([]string{"UTM Source", "UTM Medium", "UTM Campaign", "UTM Term", "UTM Content", "Ref", "Views", "Unique Views"})
+	for _, c := range campaigns {
+		_ = cw.Write(append(c.values(), strconv.FormatInt(c.Views, 10), strconv.FormatInt(c.UniqueViews, 10)))
+	}
+	cw.Flush()
+}
@@ -0,0 +1,239 @@
+package video
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestParseCampaignParams(t *testing.T) {
+	q := url.Values{
+		"utm_source":   {"  newsletter "},
+		"utm_campaign": {"spring\r\nlaunch"},
+		"utm_content":  {strings.Repeat("é", maxCampaignValueLength+10)},
+		"ref":          {"alice"},
+		"utm_id":       {"ignored"},
+	}
+
+	got := parseCampaignParams(q)
+	want := campaignParams{
+		Source:   "newsletter",
+		Campaign: "springlaunch",
+		Content:  strings.Repeat("é", maxCampaignValueLength),
+		Ref:      "alice",
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if !parseCampaignParams(url.Values{"utm_source": {"  "}}).isZero() {
+		t.Error("expected blank values to parse as no campaign")
+	}
+}
+
+func TestCampaignParams_InsertArgs(t *testing.T) {
+	got := campaignParams{Source: "x", Ref: "y"}.insertArgs()
+	want := []any{"x", nil, nil, nil, nil, "y"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCampaignParams_FilterSQL(t *testing.T) {
+	cond, args := campaignParams{Medium: "email", Campaign: "launch"}.filterSQL("vv.", []any{"vid-1", "since"})
+	if cond != " AND vv.utm_medium = $3 AND vv.utm_campaign = $4" {
+		t.Errorf("unexpected condition %q", cond)
+	}
+	if !reflect.DeepEqual(args, []any{"vid-1", "since", "email", "launch"}) {
+		t.Errorf("unexpected args %v", args)
+	}
+}
+
+func TestViewStatsSource(t *testing.T) {
+	source, args := viewStatsSource(campaignParams{}, false, []any{"vid-1"})
+	if source != "video_daily_stats" || len(args) != 1 {
+		t.Errorf("expected the rollup without a filter, got %q %v", source, args)
+	}
+
+	source, args = viewStatsSource(campaignParams{Ref: "alice"}, false, []any{"vid-1"})
+	if !strings.Contains(source, "FROM video_views vv") || !strings.Contains(source, "AND vv.ref = $2") {
+		t.Errorf("expected an aggregation over filtered views, got %q", source)
+	}
+	if !reflect.DeepEqual(args, []any{"vid-1", "alice"}) {
+		t.Errorf("unexpected args %v", args)
+	}
+}
+
+func TestRecordViewAsync_StoresCampaign(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectExec(`INSERT INTO video_views`).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			"newsletter", "email", "launch", nil, nil, "alice", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	req := httptest.NewRequest(http.MethodGet, "/watch/abc123?utm_source=newsletter&utm_medium=email&utm_campaign=launch&ref=alice", nil)
+	handler.recordViewAsync(req, viewParams{videoID: "vid-1", ownerID: testUserID, viewerUserID: testUserID})
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestAnalytics_FiltersByCampaign(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-campaign-1"
+
+	mock.ExpectQuery("SELECT id, email_gate_enabled FROM videos").
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "email_gate_enabled"}).AddRow(videoID, false))
+	mock.ExpectQuery(`SELECT day, views, unique_viewers\s+FROM \(SELECT g\.video_id`).
+		WithArgs(videoID, pgxmock.AnyArg(), "launch").
+		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}).
+			AddRow(time.Now().UTC().Truncate(24*time.Hour), int64(8), int64(6)))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(cta_clicks\)`).
+		WithArgs(videoID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(0)))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(milestone_25\)`).
+		WithArgs(videoID, pgxmock.AnyArg()).
+		WillReturnRows(milestoneRollupRow(0, 0, 0, 0))
+	mock.ExpectQuery(`SELECT referrer, COUNT\(\*\) AS cnt\s+FROM video_views WHERE video_id = \$1 AND created_at >= \$2 AND NOT is_bot AND utm_campaign = \$3`).
+		WithArgs(videoID, pgxmock.AnyArg(), "launch").
+		WillReturnRows(pgxmock.NewRows([]string{"referrer", "cnt"}).AddRow("Email", int64(8)))
+	mock.ExpectQuery(`SELECT COALESCE\(vv.utm_source, ''\)`).
+		WithArgs(videoID, pgxmock.AnyArg(), "launch").
+		WillReturnRows(pgxmock.NewRows([]string{"source", "medium", "campaign", "term", "content", "ref", "views", "unique_views"}).
+			AddRow("newsletter", "email", "launch", "", "", "", int64(6), int64(5)).
+			AddRow("", "", "launch", "", "", "alice", int64(2), int64(1)))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics", handler.Analytics)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/"+videoID+"/analytics?range=7d&utm_campaign=launch", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp analyticsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Summary.TotalViews != 8 {
+		t.Errorf("expected filtered totalViews 8, got %d", resp.Summary.TotalViews)
+	}
+	if resp.Filter == nil || resp.Filter.Campaign != "launch" {
+		t.Errorf("expected the applied filter to be echoed, got %+v", resp.Filter)
+	}
+	if len(resp.Campaigns) != 2 {
+		t.Fatalf("expected 2 campaign rows, got %d", len(resp.Campaigns))
+	}
+	if resp.Campaigns[0].Source != "newsletter" || resp.Campaigns[0].Percentage != 75 {
+		t.Errorf("unexpected first campaign row: %+v", resp.Campaigns[0])
+	}
+	if resp.Campaigns[1].Ref != "alice" {
+		t.Errorf("expected ref on second campaign row, got %+v", resp.Campaigns[1])
+	}
+}
+
+func TestAnalyticsExport_IncludesCampaigns(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE id = \$1 AND user_id = \$2 AND status != 'deleted'`).
+		WithArgs("vid-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("vid-1"))
+	mock.ExpectQuery(`SELECT day, views, unique_viewers`).
+		WithArgs("vid-1", pgxmock.AnyArg(), "newsletter").
+		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}).
+			AddRow(time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), int64(4), int64(3)))
+	mock.ExpectQuery(`SELECT COALESCE\(vv.utm_source, ''\)`).
+		WithArgs("vid-1", pgxmock.AnyArg(), "newsletter").
+		WillReturnRows(pgxmock.NewRows([]string{"source", "medium", "campaign", "term", "content", "ref", "views", "unique_views"}).
+			AddRow("newsletter", "email", "launch, part 2", "", "", "", int64(4), int64(3)))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics/export", handler.AnalyticsExport)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/vid-1/analytics/export?range=7d&utm_source=newsletter", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "UTM Source,UTM Medium,UTM Campaign,UTM Term,UTM Content,Ref,Views,Unique Views\n") {
+		t.Errorf("expected campaign header in CSV, got:\n%s", body)
+	}
+	if !strings.Contains(body, `newsletter,email,"launch, part 2",,,,4,3`) {
+		t.Errorf("expected campaign row in CSV, got:\n%s", body)
+	}
+}
+
+func TestAnalyticsDashboard_CampaignBreakdown(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	expectDashboardQueries(t, mock, 10, 7, 2, 600, 50.0,
+		pgxmock.NewRows([]string{"day", "views", "unique_views"}),
+		pgxmock.NewRows([]string{"id", "title", "views", "unique_views", "share_token", "has_thumbnail", "completion"}))
+	mock.ExpectQuery(`SELECT COALESCE\(vv.utm_source, ''\)(.|\n)*WHERE v.user_id = \$1 AND v.parent_video_id IS NULL AND vv.created_at >= \$2`).
+		WithArgs(testUserID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"source", "medium", "campaign", "term", "content", "ref", "views", "unique_views"}).
+			AddRow("linkedin", "social", "", "", "", "", int64(4), int64(4)))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/analytics/dashboard", handler.AnalyticsDashboard)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/analytics/dashboard?range=7d", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp dashboardResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Campaigns) != 1 || resp.Campaigns[0].Source != "linkedin" || resp.Campaigns[0].Percentage != 40 {
+		t.Errorf("unexpected campaigns: %+v", resp.Campaigns)
+	}
+	if resp.Filter != nil {
+		t.Errorf("expected no filter, got %+v", resp.Filter)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
@@ -75,6 +75,33 @@ func AbandonStaleUploads(ctx context.Context, db database.DBTX) {
 	}
 }
 
+// RetireOrphanedVideoReplies deletes viewers' video replies that no longer
+// have anywhere to show up: the parent video was deleted or the reply's comment
+// was removed. It also drops comments left behind by replies whose upload was
+// abandoned.
+func RetireOrphanedVideoReplies(ctx context.Context, db database.DBTX) {
+	tag, err := db.Exec(ctx,
+		`UPDATE videos rv SET status = 'deleted', updated_at = now()
+		 WHERE rv.parent_video_id IS NOT NULL AND rv.status != 'deleted'
+		   AND (EXISTS (SELECT 1 FROM videos p WHERE p.id = rv.parent_video_id AND p.status = 'deleted')
+		        OR (rv.created_at < now() - interval '1 hour'
+		            AND NOT EXISTS (SELECT 1 FROM video_comments c WHERE c.reply_video_id = rv.id)))`)
+	if err != nil {
+		slog.Error("cleanup: failed to retire orphaned video replies", "error", err)
+		return
+	}
+	if n := tag.RowsAffected(); n > 0 {
+		slog.Info("cleanup: retired orphaned video replies", "count", n)
+	}
+
+	if _, err := db.Exec(ctx,
+		`DELETE FROM video_comments c USING videos rv
+		 WHERE rv.id = c.reply_video_id AND c.status = 'uploading' AND rv.status = 'deleted'`,
+	); err != nil {
+		slog.Error("cleanup: failed to remove abandoned video reply comments", "error", err)
+	}
+}
+
 func StartCleanupLoop(ctx context.Context, db database.DBTX, storage ObjectStorage, interval time.Duration) {
 	go func() {
 		ticker := time.NewTicker(interval)
@@ -86,6 +113,7 @@ func StartCleanupLoop(ctx context.Context, db database.DBTX, storage ObjectStora
 				return
 			case <-ticker.C:
 				AbandonStaleUploads(ctx, db)
+				RetireOrphanedVideoReplies(ctx, db)
 				PurgeOrphanedFiles(ctx, db, storage)
 			}
 		}
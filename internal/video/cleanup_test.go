@@ -191,3 +191,39 @@ func TestAbandonStaleUploads_HandlesDBError(t *testing.T) {
 		t.Errorf("unmet expectations: %v", err)
 	}
 }
+
+func TestRetireOrphanedVideoReplies(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec(`UPDATE videos rv SET status = 'deleted'`).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	mock.ExpectExec(`DELETE FROM video_comments c USING videos rv`).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	RetireOrphanedVideoReplies(context.Background(), mock)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRetireOrphanedVideoReplies_HandlesDBError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec(`UPDATE videos rv SET status = 'deleted'`).
+		WillReturnError(errors.New("connection refused"))
+
+	RetireOrphanedVideoReplies(context.Background(), mock)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -20,6 +20,7 @@ var validCommentModes = map[string]bool{
 	"anonymous":           true,
 	"name_required":       true,
 	"name_email_required": true,
+	"moderated":           true,
 }
 
 var quickReactionEmojis = []string{"👍", "👎", "❤️", "😂", "😮", "🎉"}
@@ -38,21 +39,32 @@ type setCommentModeRequest struct {
 }
 
 type postCommentRequest struct {
-	AuthorName     string   `json:"authorName"`
-	AuthorEmail    string   `json:"authorEmail"`
-	Body           string   `json:"body"`
-	IsPrivate      bool     `json:"isPrivate"`
-	VideoTimestamp *float64 `json:"videoTimestamp"`
+	AuthorName     string          `json:"authorName"`
+	AuthorEmail    string          `json:"authorEmail"`
+	Body           string          `json:"body"`
+	IsPrivate      bool            `json:"isPrivate"`
+	VideoTimestamp *float64        `json:"videoTimestamp"`
+	ParentID       *string         `json:"parentId"`
+	Annotation     json.RawMessage `json:"annotation"`
 }
 
 type commentResponse struct {
-	ID             string   `json:"id"`
-	AuthorName     string   `json:"authorName"`
-	Body           string   `json:"body"`
-	IsPrivate      bool     `json:"isPrivate"`
-	IsOwner        bool     `json:"isOwner"`
-	CreatedAt      string   `json:"createdAt"`
-	VideoTimestamp *float64 `json:"videoTimestamp,omitempty"`
+	ID             string              `json:"id"`
+	AuthorName     string              `json:"authorName"`
+	Body           string              `json:"body"`
+	IsPrivate      bool                `json:"isPrivate"`
+	IsOwner        bool                `json:"isOwner"`
+	CreatedAt      string              `json:"createdAt"`
+	VideoTimestamp *float64            `json:"videoTimestamp,omitempty"`
+	ParentID       *string             `json:"parentId"`
+	ReplyCount     int                 `json:"replyCount"`
+	EditedAt       *string             `json:"editedAt"`
+	Resolved       bool                `json:"resolved"`
+	CanEdit        bool                `json:"canEdit"`
+	Mentions       []commentMention    `json:"mentions,omitempty"`
+	Status         string              `json:"status,omitempty"`
+	Annotation     *commentAnnotation  `json:"annotation,omitempty"`
+	VideoReply     *videoReplyResponse `json:"videoReply,omitempty"`
 }
 
 func isQuickReactionBody(body string) bool {
@@ -112,12 +124,16 @@ func (h *Handler) PostWatchComment(w http.ResponseWriter, r *http.Request) {
 	var videoID, ownerID, commentMode string
 	var shareExpiresAt *time.Time
 	var sharePassword *string
+	var visibility string
 
 	err := h.db.QueryRow(r.Context(),
-		`SELECT v.id, v.user_id, v.comment_mode, v.share_expires_at, v.share_password
-		 FROM videos v WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
+		`SELECT v.id, v.user_id, v.comment_mode, v.share_expires_at, v.share_password,
+		        COALESCE(v.visibility, f.visibility, 'public')
+		 FROM videos v
+		 LEFT JOIN folders f ON f.id = v.folder_id
+		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
 		shareToken,
-	).Scan(&videoID, &ownerID, &commentMode, &shareExpiresAt, &sharePassword)
+	).Scan(&videoID, &ownerID, &commentMode, &shareExpiresAt, &sharePassword, &visibility)
 	if err != nil {
 		httputil.WriteError(w, http.StatusNotFound, "video not found")
 		return
@@ -128,6 +144,10 @@ func (h *Handler) PostWatchComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.enforceVisibility(w, r, shareToken, visibility) {
+		return
+	}
+
 	if sharePassword != nil {
 		if !hasValidWatchCookie(r, h.hmacSecret, shareToken, *sharePassword) {
 			httputil.WriteError(w, http.StatusForbidden, "password required")
@@ -175,6 +195,20 @@ func (h *Handler) PostWatchComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	annotation, msg := parseCommentAnnotation(req.Annotation)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+	var annotationJSON []byte
+	if annotation != nil {
+		if req.VideoTimestamp == nil {
+			httputil.WriteError(w, http.StatusBadRequest, "annotations need a video timestamp")
+			return
+		}
+		annotationJSON, _ = json.Marshal(annotation)
+	}
+
 	quickReaction := isQuickReactionBody(req.Body)
 
 	switch commentMode {
@@ -206,74 +240,106 @@ func (h *Handler) PostWatchComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var userIDArg *string
+	var parentID *string
+	if req.ParentID != nil && *req.ParentID != "" {
+		rootID, parentPrivate, err := h.resolveCommentParent(r.Context(), videoID, *req.ParentID)
+		if err != nil || (parentPrivate && callerUserID != ownerID) {
+			httputil.WriteError(w, http.StatusNotFound, "parent comment not found")
+			return
+		}
+		parentID = &rootID
+		// A reply to a private comment stays between the owner and its author.
+		req.IsPrivate = req.IsPrivate || parentPrivate
+	}
+
+	var userIDArg, authorKeyArg *string
 	if callerUserID != "" {
 		userIDArg = &callerUserID
+	} else {
+		key, err := h.ensureCommentAuthorKey(w, r, shareToken)
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not save comment")
+			return
+		}
+		authorKeyArg = &key
+	}
+
+	moderation := commentModeration{status: commentApproved, spamReasons: []string{}}
+	if callerUserID == "" || callerUserID != ownerID {
+		banned, err := h.commentAuthorBanned(r.Context(), videoID, userIDArg, req.AuthorEmail, authorKeyArg)
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not save comment")
+			return
+		}
+		if banned {
+			httputil.WriteError(w, http.StatusForbidden, "you can no longer comment on this video")
+			return
+		}
+		if !req.IsPrivate && !quickReaction {
+			moderation = h.moderateComment(r.Context(), commentMode, SpamCandidate{
+				Body:          req.Body,
+				AuthorName:    req.AuthorName,
+				AuthorEmail:   req.AuthorEmail,
+				Authenticated: callerUserID != "",
+			})
+		}
 	}
 
 	var commentID string
 	var createdAt time.Time
 	err = h.db.QueryRow(r.Context(),
-		`INSERT INTO video_comments (video_id, user_id, author_name, author_email, body, is_private, video_timestamp_seconds)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`INSERT INTO video_comments (video_id, user_id, author_name, author_email, body, is_private, video_timestamp_seconds, parent_id, author_key, status, spam_score, spam_reasons, annotation)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		 RETURNING id, created_at`,
-		videoID, userIDArg, req.AuthorName, req.AuthorEmail, req.Body, req.IsPrivate, req.VideoTimestamp,
+		videoID, userIDArg, req.AuthorName, req.AuthorEmail, req.Body, req.IsPrivate, req.VideoTimestamp, parentID, authorKeyArg,
+		moderation.status, moderation.spamScore, moderation.spamReasons, annotationJSON,
 	).Scan(&commentID, &createdAt)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "could not save comment")
 		return
 	}
 
-	shouldEmailComment := h.commentNotifier != nil && h.shouldSendImmediateCommentNotification(r.Context(), ownerID)
-	shouldSlackComment := h.slackNotifier != nil
-
-	if !req.IsPrivate && !quickReaction && callerUserID != ownerID && (shouldEmailComment || shouldSlackComment) {
-		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
-			var ownerEmail, ownerName, videoTitle string
-			err := h.db.QueryRow(ctx,
-				`SELECT u.email, u.name, v.title FROM users u JOIN videos v ON v.user_id = u.id WHERE v.id = $1`,
-				videoID,
-			).Scan(&ownerEmail, &ownerName, &videoTitle)
-			if err != nil {
-				slog.Error("comment: failed to fetch owner info for notification", "video_id", videoID, "error", err)
-				return
-			}
-			watchURL := h.baseURL + "/watch/" + shareToken
-			authorName := req.AuthorName
-			if authorName == "" {
-				authorName = "Anonymous"
+	var mentioned []mentionedMember
+	if callerUserID != "" && !req.IsPrivate && !quickReaction {
+		if handles := parseMentionHandles(req.Body); len(handles) > 0 {
+			mentioned, err = h.resolveMentions(r.Context(), videoID, callerUserID, handles)
+			if err == nil {
+				err = h.saveMentions(r.Context(), commentID, mentioned)
 			}
-			if h.webhookClient != nil {
-				wURL, wSecret, wErr := h.webhookClient.LookupConfigByUserID(ctx, ownerID)
-				if wErr == nil {
-					if err := h.webhookClient.Dispatch(ctx, ownerID, wURL, wSecret, webhook.Event{
-						Name:      "video.comment",
-						Timestamp: time.Now().UTC(),
-						Data: map[string]any{
-							"videoId":  videoID,
-							"title":    videoTitle,
-							"watchUrl": watchURL,
-							"author":   authorName,
-							"body":     req.Body,
-						},
-					}); err != nil {
-						slog.Error("webhook: dispatch failed for video.comment", "video_id", videoID, "error", err)
-					}
-				}
-			}
-			if shouldSlackComment {
-				if err := h.slackNotifier.SendCommentNotification(ctx, ownerEmail, ownerName, videoTitle, authorName, req.Body, watchURL); err != nil {
-					slog.Error("comment: failed to send Slack notification", "video_id", videoID, "error", err)
-				}
-			}
-			if shouldEmailComment {
-				if err := h.commentNotifier.SendCommentNotification(ctx, ownerEmail, ownerName, videoTitle, authorName, req.Body, watchURL); err != nil {
-					slog.Error("comment: failed to send email notification", "video_id", videoID, "error", err)
-				}
+			if err != nil {
+				slog.Error("comment: failed to record mentions", "comment_id", commentID, "error", err)
+				mentioned = nil
 			}
-		}()
+		}
+	}
+
+	if moderation.status == commentApproved && !req.IsPrivate {
+		h.publishLiveComment(r.Context(), videoID, commentID, req.AuthorName, req.Body)
+	}
+
+	// Held comments stay quiet until they are approved.
+	if moderation.status == commentApproved && !req.IsPrivate && !quickReaction {
+		shouldEmailComment := h.commentNotifier != nil && h.shouldSendImmediateCommentNotification(r.Context(), ownerID)
+		shouldSlackComment := h.slackNotifier != nil
+		notice := commentNotice{
+			videoID:        videoID,
+			shareToken:     shareToken,
+			ownerID:        ownerID,
+			commentID:      commentID,
+			authorUserID:   callerUserID,
+			authorName:     req.AuthorName,
+			authorEmail:    req.AuthorEmail,
+			body:           req.Body,
+			parentID:       parentID,
+			videoTimestamp: req.VideoTimestamp,
+			mentioned:      mentioned,
+			emailOwner:     callerUserID != ownerID && shouldEmailComment,
+			slackOwner:     callerUserID != ownerID && shouldSlackComment,
+		}
+		notice.webhookOwner = notice.emailOwner || notice.slackOwner
+		if notice.notifiesAnyone(h) {
+			go h.sendCommentNotifications(notice)
+		}
 	}
 
 	httputil.WriteJSON(w, http.StatusCreated, commentResponse{
@@ -284,20 +350,122 @@ func (h *Handler) PostWatchComment(w http.ResponseWriter, r *http.Request) {
 		IsOwner:        callerUserID == ownerID && callerUserID != "",
 		CreatedAt:      createdAt.Format(time.RFC3339),
 		VideoTimestamp: req.VideoTimestamp,
+		ParentID:       parentID,
+		CanEdit:        !quickReaction,
+		Mentions:       mentionsOf(mentioned),
+		Status:         moderation.status,
+		Annotation:     annotation,
 	})
 }
 
+// commentNotice carries what the notifications for a published comment need.
+type commentNotice struct {
+	videoID        string
+	shareToken     string
+	ownerID        string
+	commentID      string
+	authorUserID   string
+	authorName     string
+	authorEmail    string
+	body           string
+	parentID       *string
+	videoTimestamp *float64
+	mentioned      []mentionedMember
+	emailOwner     bool
+	slackOwner     bool
+	webhookOwner   bool
+}
+
+func (n commentNotice) notifiesAnyone(h *Handler) bool {
+	return n.emailOwner || n.slackOwner || (n.webhookOwner && h.webhookClient != nil) ||
+		(n.parentID != nil && h.threadReplyNotifier != nil) || len(n.mentioned) > 0
+}
+
+// sendCommentNotifications tells the owner, mentioned members and earlier
+// thread participants about a newly published comment.
+func (h *Handler) sendCommentNotifications(n commentNotice) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var ownerEmail, ownerName, videoTitle string
+	err := h.db.QueryRow(ctx,
+		`SELECT u.email, u.name, v.title FROM users u JOIN videos v ON v.user_id = u.id WHERE v.id = $1`,
+		n.videoID,
+	).Scan(&ownerEmail, &ownerName, &videoTitle)
+	if err != nil {
+		slog.Error("comment: failed to fetch owner info for notification", "video_id", n.videoID, "error", err)
+		return
+	}
+	watchURL := h.shareWatchURL(ctx, n.shareToken)
+	authorName := n.authorName
+	if authorName == "" {
+		authorName = "Anonymous"
+	}
+
+	// Each person hears about the comment once: a mention takes the place of
+	// a thread notification, and the owner's regular comment notification
+	// covers them being mentioned.
+	skipEmails := []string{ownerEmail, n.authorEmail}
+	var mentionNotices []mentionedMember
+	for _, m := range n.mentioned {
+		skipEmails = append(skipEmails, m.email)
+		if m.userID != n.ownerID || !(n.emailOwner || n.slackOwner) {
+			mentionNotices = append(mentionNotices, m)
+		}
+	}
+	if len(mentionNotices) > 0 {
+		link := commentDeepLink(watchURL, n.commentID, n.videoTimestamp)
+		h.notifyMentionedMembers(ctx, mentionNotices, n.videoID, n.commentID, videoTitle, authorName, n.body, link)
+	}
+	if n.parentID != nil && h.threadReplyNotifier != nil {
+		h.notifyThreadParticipants(ctx, n.shareToken, *n.parentID, n.commentID, n.authorUserID, skipEmails, videoTitle, authorName, n.body, watchURL)
+	}
+
+	if n.webhookOwner && h.webhookClient != nil {
+		wURL, wSecret, wErr := h.webhookClient.LookupConfigByUserID(ctx, n.ownerID)
+		if wErr == nil {
+			if err := h.webhookClient.Dispatch(ctx, n.ownerID, wURL, wSecret, webhook.Event{
+				Name:      "video.comment",
+				Timestamp: time.Now().UTC(),
+				Data: map[string]any{
+					"videoId":  n.videoID,
+					"title":    videoTitle,
+					"watchUrl": watchURL,
+					"author":   authorName,
+					"body":     n.body,
+					"parentId": n.parentID,
+				},
+			}); err != nil {
+				slog.Error("webhook: dispatch failed for video.comment", "video_id", n.videoID, "error", err)
+			}
+		}
+	}
+	if n.slackOwner {
+		if err := h.slackNotifier.SendCommentNotification(ctx, ownerEmail, ownerName, videoTitle, authorName, n.body, watchURL); err != nil {
+			slog.Error("comment: failed to send Slack notification", "video_id", n.videoID, "error", err)
+		}
+	}
+	if n.emailOwner {
+		if err := h.commentNotifier.SendCommentNotification(ctx, ownerEmail, ownerName, videoTitle, authorName, n.body, watchURL); err != nil {
+			slog.Error("comment: failed to send email notification", "video_id", n.videoID, "error", err)
+		}
+	}
+}
+
 func (h *Handler) lookupWatchVideo(w http.ResponseWriter, r *http.Request) (videoID, ownerID, commentMode string, ok bool) {
 	shareToken := chi.URLParam(r, "shareToken")
 
 	var shareExpiresAt *time.Time
 	var sharePassword *string
+	var visibility string
 
 	err := h.db.QueryRow(r.Context(),
-		`SELECT v.id, v.user_id, v.comment_mode, v.share_expires_at, v.share_password
-		 FROM videos v WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
+		`SELECT v.id, v.user_id, v.comment_mode, v.share_expires_at, v.share_password,
+		        COALESCE(v.visibility, f.visibility, 'public')
+		 FROM videos v
+		 LEFT JOIN folders f ON f.id = v.folder_id
+		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
 		shareToken,
-	).Scan(&videoID, &ownerID, &commentMode, &shareExpiresAt, &sharePassword)
+	).Scan(&videoID, &ownerID, &commentMode, &shareExpiresAt, &sharePassword, &visibility)
 	if err != nil {
 		httputil.WriteError(w, http.StatusNotFound, "video not found")
 		return "", "", "", false
@@ -308,6 +476,10 @@ func (h *Handler) lookupWatchVideo(w http.ResponseWriter, r *http.Request) (vide
 		return "", "", "", false
 	}
 
+	if !h.enforceVisibility(w, r, shareToken, visibility) {
+		return "", "", "", false
+	}
+
 	if sharePassword != nil {
 		if !hasValidWatchCookie(r, h.hmacSecret, shareToken, *sharePassword) {
 			httputil.WriteError(w, http.StatusForbidden, "password required")
@@ -318,9 +490,14 @@ func (h *Handler) lookupWatchVideo(w http.ResponseWriter, r *http.Request) (vide
 	return videoID, ownerID, commentMode, true
 }
 
-func (h *Handler) queryComments(ctx context.Context, videoID, ownerID string, includePrivate bool) ([]commentResponse, error) {
-	query := `SELECT c.id, c.user_id, c.author_name, c.body, c.is_private, c.created_at, c.video_timestamp_seconds
-		 FROM video_comments c WHERE c.video_id = $1`
+func (h *Handler) queryComments(ctx context.Context, videoID, ownerID string, includePrivate bool, viewer commentViewer) ([]commentResponse, error) {
+	query := `SELECT c.id, c.user_id, c.author_name, c.body, c.is_private, c.created_at, c.video_timestamp_seconds, c.parent_id, c.edited_at, c.resolved_at, c.author_key,
+		        (SELECT json_agg(json_build_object('handle', m.handle, 'name', u.name) ORDER BY m.created_at)
+		         FROM comment_mentions m JOIN users u ON u.id = m.user_id WHERE m.comment_id = c.id),
+		        c.annotation,
+		        (SELECT json_build_object('id', rv.id, 'duration', rv.duration, 'fileKey', rv.file_key, 'thumbnailKey', rv.thumbnail_key)
+		         FROM videos rv WHERE rv.id = c.reply_video_id AND rv.status != 'deleted')
+		 FROM video_comments c WHERE c.video_id = $1 AND c.status = 'approved'`
 	if !includePrivate {
 		query += ` AND c.is_private = false`
 	}
@@ -335,17 +512,19 @@ func (h *Handler) queryComments(ctx context.Context, videoID, ownerID string, in
 	var comments []commentResponse
 	for rows.Next() {
 		var id, authorName, body string
-		var userID *string
+		var userID, parentID, authorKey *string
 		var isPrivate bool
 		var createdAt time.Time
+		var editedAt, resolvedAt *time.Time
 		var videoTimestamp *float64
+		var mentionsJSON, annotationJSON, videoReplyJSON []byte
 
-		if err := rows.Scan(&id, &userID, &authorName, &body, &isPrivate, &createdAt, &videoTimestamp); err != nil {
+		if err := rows.Scan(&id, &userID, &authorName, &body, &isPrivate, &createdAt, &videoTimestamp, &parentID, &editedAt, &resolvedAt, &authorKey, &mentionsJSON, &annotationJSON, &videoReplyJSON); err != nil {
 			return nil, err
 		}
 
 		isOwner := userID != nil && *userID == ownerID
-		comments = append(comments, commentResponse{
+		c := commentResponse{
 			ID:             id,
 			AuthorName:     authorName,
 			Body:           body,
@@ -353,14 +532,38 @@ func (h *Handler) queryComments(ctx context.Context, videoID, ownerID string, in
 			IsOwner:        isOwner,
 			CreatedAt:      createdAt.Format(time.RFC3339),
 			VideoTimestamp: videoTimestamp,
-		})
+			ParentID:       parentID,
+			Resolved:       resolvedAt != nil,
+			CanEdit:        !isQuickReactionBody(body) && viewer.wrote(userID, authorKey),
+		}
+		if editedAt != nil {
+			edited := editedAt.Format(time.RFC3339)
+			c.EditedAt = &edited
+		}
+		if len(mentionsJSON) > 0 {
+			if err := json.Unmarshal(mentionsJSON, &c.Mentions); err != nil {
+				return nil, err
+			}
+		}
+		if len(annotationJSON) > 0 {
+			if err := json.Unmarshal(annotationJSON, &c.Annotation); err != nil {
+				return nil, err
+			}
+		}
+		if len(videoReplyJSON) > 0 {
+			reply, err := h.videoReplyFromRow(ctx, videoReplyJSON)
+			if err != nil {
+				return nil, err
+			}
+			c.VideoReply = reply
+		}
+		comments = append(comments, c)
 	}
-
-	if comments == nil {
-		comments = []commentResponse{}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return comments, nil
+	return threadComments(comments), nil
 }
 
 type listCommentsResponseBody struct {
@@ -384,8 +587,12 @@ func (h *Handler) ListWatchComments(w http.ResponseWriter, r *http.Request) {
 
 	callerUserID := h.optionalUserID(r)
 	includePrivate := callerUserID != "" && callerUserID == ownerID
+	viewer := commentViewer{
+		userID:    callerUserID,
+		authorKey: commentAuthorKey(r, h.hmacSecret, chi.URLParam(r, "shareToken")),
+	}
 
-	comments, err := h.queryComments(r.Context(), videoID, ownerID, includePrivate)
+	comments, err := h.queryComments(r.Context(), videoID, ownerID, includePrivate, viewer)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch comments")
 		return
@@ -411,7 +618,7 @@ func (h *Handler) ListOwnerComments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comments, err := h.queryComments(r.Context(), videoID, ownerID, true)
+	comments, err := h.queryComments(r.Context(), videoID, ownerID, true, commentViewer{userID: userID})
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch comments")
 		return
@@ -0,0 +1,98 @@
+package video
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// Limits on the markup a comment can draw over the video frame.
+const (
+	maxAnnotationBytes  = 32 * 1024
+	maxAnnotationShapes = 20
+	maxAnnotationPoints = 1000
+	maxPathPoints       = 500
+)
+
+const (
+	annotationRect  = "rect"
+	annotationArrow = "arrow"
+	annotationPath  = "path"
+)
+
+var annotationColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// commentAnnotation is markup drawn over the frame at a comment's timestamp.
+// Points are normalized to the video frame, so (0,0) is its top-left corner
+// and (1,1) its bottom-right, whatever size the player is shown at.
+type commentAnnotation struct {
+	Shapes []annotationShape `json:"shapes"`
+}
+
+// annotationShape is a rectangle given by two opposite corners, an arrow from
+// its tail to its head, or a freehand path through its points.
+type annotationShape struct {
+	Type   string       `json:"type"`
+	Color  string       `json:"color,omitempty"`
+	Points [][2]float64 `json:"points"`
+}
+
+// parseCommentAnnotation validates an annotation from a request body and
+// returns it with coordinates rounded for storage. A missing or null
+// annotation yields nil. The string result is a user-facing error message.
+func parseCommentAnnotation(raw json.RawMessage) (*commentAnnotation, string) {
+	if len(raw) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil, ""
+	}
+	if len(raw) > maxAnnotationBytes {
+		return nil, "annotation is too large"
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var a commentAnnotation
+	if err := dec.Decode(&a); err != nil {
+		return nil, "invalid annotation"
+	}
+	if len(a.Shapes) == 0 {
+		return nil, "annotation has no shapes"
+	}
+	if len(a.Shapes) > maxAnnotationShapes {
+		return nil, fmt.Sprintf("annotation can have at most %d shapes", maxAnnotationShapes)
+	}
+
+	total := 0
+	for i := range a.Shapes {
+		s := &a.Shapes[i]
+		switch s.Type {
+		case annotationRect, annotationArrow:
+			if len(s.Points) != 2 {
+				return nil, fmt.Sprintf("%s needs exactly 2 points", s.Type)
+			}
+		case annotationPath:
+			if len(s.Points) < 2 || len(s.Points) > maxPathPoints {
+				return nil, fmt.Sprintf("path needs between 2 and %d points", maxPathPoints)
+			}
+		default:
+			return nil, "unknown annotation shape"
+		}
+		if s.Color != "" && !annotationColorPattern.MatchString(s.Color) {
+			return nil, "annotation color must be a hex color like #ff0000"
+		}
+		for j, p := range s.Points {
+			for k, v := range p {
+				if math.IsNaN(v) || v < 0 || v > 1 {
+					return nil, "annotation points must be between 0 and 1"
+				}
+				s.Points[j][k] = math.Round(v*10000) / 10000
+			}
+		}
+		total += len(s.Points)
+	}
+	if total > maxAnnotationPoints {
+		return nil, fmt.Sprintf("annotation can have at most %d points", maxAnnotationPoints)
+	}
+	return &a, ""
+}
@@ -0,0 +1,132 @@
+package video
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestParseCommentAnnotation(t *testing.T) {
+	longPath := `{"shapes":[{"type":"path","points":[` + strings.Repeat(`[0.5,0.5],`, maxPathPoints) + `[0.5,0.5]]}]}`
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{"absent", ``, ""},
+		{"null", `null`, ""},
+		{"rectangle", `{"shapes":[{"type":"rect","color":"#ff0000","points":[[0.1,0.2],[0.4,0.5]]}]}`, ""},
+		{"arrow and path", `{"shapes":[{"type":"arrow","points":[[0,0],[1,1]]},{"type":"path","points":[[0.1,0.1],[0.2,0.2],[0.3,0.1]]}]}`, ""},
+		{"no shapes", `{"shapes":[]}`, "annotation has no shapes"},
+		{"unknown shape", `{"shapes":[{"type":"circle","points":[[0,0],[1,1]]}]}`, "unknown annotation shape"},
+		{"rect with three points", `{"shapes":[{"type":"rect","points":[[0,0],[1,1],[0.5,0.5]]}]}`, "rect needs exactly 2 points"},
+		{"out of frame", `{"shapes":[{"type":"arrow","points":[[0,0],[1.5,1]]}]}`, "annotation points must be between 0 and 1"},
+		{"bad color", `{"shapes":[{"type":"rect","color":"red","points":[[0,0],[1,1]]}]}`, "annotation color must be a hex color like #ff0000"},
+		{"unknown field", `{"shapes":[{"type":"rect","points":[[0,0],[1,1]],"onclick":"x"}]}`, "invalid annotation"},
+		{"path too long", longPath, "path needs between 2 and 500 points"},
+		{"too large", `{"shapes":[` + strings.Repeat(" ", maxAnnotationBytes) + `]}`, "annotation is too large"},
+	}
+	for _, tt := range tests {
+		a, msg := parseCommentAnnotation(json.RawMessage(tt.raw))
+		if msg != tt.wantErr {
+			t.Errorf("%s: got error %q, want %q", tt.name, msg, tt.wantErr)
+		}
+		if tt.wantErr == "" && (a == nil) != (tt.raw == "" || tt.raw == "null") {
+			t.Errorf("%s: unexpected annotation %+v", tt.name, a)
+		}
+	}
+
+	var shapes strings.Builder
+	for i := 0; i <= maxAnnotationShapes; i++ {
+		if i > 0 {
+			shapes.WriteString(",")
+		}
+		shapes.WriteString(`{"type":"rect","points":[[0,0],[1,1]]}`)
+	}
+	if _, msg := parseCommentAnnotation(json.RawMessage(`{"shapes":[` + shapes.String() + `]}`)); msg != "annotation can have at most 20 shapes" {
+		t.Errorf("unexpected error for too many shapes: %q", msg)
+	}
+}
+
+func TestParseCommentAnnotation_RoundsPoints(t *testing.T) {
+	a, msg := parseCommentAnnotation(json.RawMessage(`{"shapes":[{"type":"arrow","points":[[0.123456789,0.5],[1,0.99999]]}]}`))
+	if msg != "" {
+		t.Fatal(msg)
+	}
+	if got := a.Shapes[0].Points[0][0]; got != 0.1235 {
+		t.Errorf("expected 0.1235, got %v", got)
+	}
+}
+
+func TestPostWatchComment_WithAnnotation(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	timestamp := 12.0
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	expectNoCommentBan(mock)
+	mock.ExpectQuery(`INSERT INTO video_comments .*annotation\) VALUES \(.*\$13\)`).
+		WithArgs("video-123", (*string)(nil), "", "", "this button", false, &timestamp, (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(),
+			[]byte(`{"shapes":[{"type":"rect","color":"#ff0000","points":[[0.1,0.2],[0.3,0.4]]}]}`)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("c1", time.Now()))
+
+	rec := servePostComment(handler, "abc123defghi", map[string]any{
+		"body":           "this button",
+		"videoTimestamp": timestamp,
+		"annotation":     map[string]any{"shapes": []any{map[string]any{"type": "rect", "color": "#ff0000", "points": [][2]float64{{0.1, 0.2}, {0.3, 0.4}}}}},
+	})
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp commentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Annotation == nil || len(resp.Annotation.Shapes) != 1 || resp.Annotation.Shapes[0].Type != annotationRect {
+		t.Errorf("unexpected annotation %+v", resp.Annotation)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostWatchComment_AnnotationNeedsTimestamp(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+
+	rec := servePostComment(handler, "abc123defghi", map[string]any{
+		"body":       "look here",
+		"annotation": map[string]any{"shapes": []any{map[string]any{"type": "arrow", "points": [][2]float64{{0, 0}, {0.5, 0.5}}}}},
+	})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "annotations need a video timestamp") {
+		t.Errorf("unexpected error %s", rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sendrec/sendrec/internal/webhook"
+)
+
+// maxCommentMentions caps how many distinct handles one comment may mention.
+const maxCommentMentions = 10
+
+// mentionPattern matches "@handle" at the start of the body or after a
+// character that can't be part of a handle, so addresses like
+// "bob@example.com" are not read as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9._-])@([A-Za-z0-9._-]+)`)
+
+type commentMention struct {
+	Handle string `json:"handle"`
+	Name   string `json:"name"`
+}
+
+// mentionedMember is a workspace member a comment mentions, with what is
+// needed to notify them.
+type mentionedMember struct {
+	userID string
+	email  string
+	name   string
+	handle string
+}
+
+// parseMentionHandles returns the distinct lowercased handles mentioned in a
+// comment body, in order of first appearance.
+func parseMentionHandles(body string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		handle := strings.ToLower(strings.TrimRight(m[1], ".-_"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+		if len(handles) == maxCommentMentions {
+			break
+		}
+	}
+	return handles
+}
+
+// resolveMentions matches handles against the members of the video's
+// organization. A handle is a member's email local part or their name with
+// spaces removed, case-insensitively. Only members of that organization can
+// mention, and never themselves; personal-workspace videos have no mentions.
+func (h *Handler) resolveMentions(ctx context.Context, videoID, authorUserID string, handles []string) ([]mentionedMember, error) {
+	rows, err := h.db.Query(ctx,
+		`SELECT DISTINCT ON (u.id) u.id, u.email, u.name, t.handle
+		 FROM videos v
+		 JOIN organization_members author ON author.organization_id = v.organization_id AND author.user_id = $2
+		 JOIN organization_members om ON om.organization_id = v.organization_id
+		 JOIN users u ON u.id = om.user_id
+		 JOIN unnest($3::text[]) AS t(handle)
+		   ON t.handle = lower(split_part(u.email, '@', 1)) OR t.handle = lower(replace(u.name, ' ', ''))
+		 WHERE v.id = $1 AND u.id <> $2
+		 ORDER BY u.id, t.handle`,
+		videoID, authorUserID, handles,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []mentionedMember
+	for rows.Next() {
+		var m mentionedMember
+		if err := rows.Scan(&m.userID, &m.email, &m.name, &m.handle); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (h *Handler) saveMentions(ctx context.Context, commentID string, members []mentionedMember) error {
+	for _, m := range members {
+		if _, err := h.db.Exec(ctx,
+			`INSERT INTO comment_mentions (comment_id, user_id, handle) VALUES ($1, $2, $3)
+			 ON CONFLICT DO NOTHING`,
+			commentID, m.userID, m.handle,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncMentions makes an edited comment's stored mentions match members and
+// returns the ones that weren't stored before, so an edit only notifies the
+// people it newly mentions.
+func (h *Handler) syncMentions(ctx context.Context, commentID string, members []mentionedMember) ([]mentionedMember, error) {
+	userIDs := make([]string, len(members))
+	for i, m := range members {
+		userIDs[i] = m.userID
+	}
+	if _, err := h.db.Exec(ctx,
+		`DELETE FROM comment_mentions WHERE comment_id = $1 AND user_id <> ALL($2::uuid[])`,
+		commentID, userIDs,
+	); err != nil {
+		return nil, err
+	}
+
+	var added []mentionedMember
+	for _, m := range members {
+		tag, err := h.db.Exec(ctx,
+			`INSERT INTO comment_mentions (comment_id, user_id, handle) VALUES ($1, $2, $3)
+			 ON CONFLICT DO NOTHING`,
+			commentID, m.userID, m.handle,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if tag.RowsAffected() > 0 {
+			added = append(added, m)
+		}
+	}
+	return added, nil
+}
+
+// commentDeepLink points at a comment on the watch page, starting playback
+// at the comment's timestamp when it has one.
+func commentDeepLink(watchURL, commentID string, videoTimestamp *float64) string {
+	if videoTimestamp != nil {
+		watchURL += fmt.Sprintf("?t=%d", int(*videoTimestamp))
+	}
+	return watchURL + "#comment-" + commentID
+}
+
+func (h *Handler) notifyMentionedMembers(ctx context.Context, members []mentionedMember, videoID, commentID, videoTitle, authorName, body, link string) {
+	for _, m := range members {
+		if h.webhookClient != nil {
+			wURL, wSecret, wErr := h.webhookClient.LookupConfigByUserID(ctx, m.userID)
+			if wErr == nil {
+				if err := h.webhookClient.Dispatch(ctx, m.userID, wURL, wSecret, webhook.Event{
+					Name:      "video.comment.mention",
+					Timestamp: time.Now().UTC(),
+					Data: map[string]any{
+						"videoId":   videoID,
+						"commentId": commentID,
+						"title":     videoTitle,
+						"watchUrl":  link,
+						"author":    authorName,
+						"body":      body,
+						"handle":    m.handle,
+					},
+				}); err != nil {
+					slog.Error("webhook: dispatch failed for video.comment.mention", "video_id", videoID, "error", err)
+				}
+			}
+		}
+		if h.slackNotifier != nil {
+			if err := h.slackNotifier.SendCommentNotification(ctx, m.email, m.name, videoTitle, authorName, body, link); err != nil {
+				slog.Error("comment: failed to send Slack mention notification", "comment_id", commentID, "error", err)
+			}
+		}
+		if h.commentNotifier != nil {
+			if err := h.commentNotifier.SendCommentNotification(ctx, m.email, m.name, videoTitle, authorName, body, link); err != nil {
+				slog.Error("comment: failed to send mention notification", "comment_id", commentID, "error", err)
+			}
+		}
+	}
+}
+
+// announceEditMentions tells members newly mentioned by an edit about the
+// comment. Everyone else already heard about it when it was published.
+func (h *Handler) announceEditMentions(videoID, shareToken, commentID, authorName, body string, videoTimestamp *float64, members []mentionedMember) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var videoTitle string
+	if err := h.db.QueryRow(ctx, `SELECT title FROM videos WHERE id = $1`, videoID).Scan(&videoTitle); err != nil {
+		slog.Error("comment: failed to load video for edited mentions", "comment_id", commentID, "error", err)
+		return
+	}
+	if authorName == "" {
+		authorName = "Anonymous"
+	}
+	link := commentDeepLink(h.shareWatchURL(ctx, shareToken), commentID, videoTimestamp)
+	h.notifyMentionedMembers(ctx, members, videoID, commentID, videoTitle, authorName, body, link)
+}
+
+func mentionsOf(members []mentionedMember) []commentMention {
+	if len(members) == 0 {
+		return nil
+	}
+	mentions := make([]commentMention, len(members))
+	for i, m := range members {
+		mentions[i] = commentMention{Handle: m.handle, Name: m.name}
+	}
+	return mentions
+}
@@ -0,0 +1,303 @@
+package video
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestParseMentionHandles(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"@jo can you look?", "jo"},
+		{"thanks @Jo.Smith, and @jo.smith again", "jo.smith"},
+		{"cc @alex.", "alex"},
+		{"mail bob@example.com instead", ""},
+		{"(@sam) and @kim-lee", "sam,kim-lee"},
+		{"just an @ sign", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(parseMentionHandles(tt.body), ","); got != tt.want {
+			t.Errorf("parseMentionHandles(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+
+	var many strings.Builder
+	for i := 0; i < maxCommentMentions+5; i++ {
+		many.WriteString("@user" + string(rune('a'+i)) + " ")
+	}
+	if got := parseMentionHandles(many.String()); len(got) != maxCommentMentions {
+		t.Errorf("expected %d handles, got %d", maxCommentMentions, len(got))
+	}
+}
+
+func TestCommentDeepLink(t *testing.T) {
+	ts := 42.7
+	if got := commentDeepLink("https://app.sendrec.eu/watch/abc", "c1", &ts); got != "https://app.sendrec.eu/watch/abc?t=42#comment-c1" {
+		t.Errorf("unexpected link %q", got)
+	}
+	if got := commentDeepLink("https://app.sendrec.eu/watch/abc", "c1", nil); got != "https://app.sendrec.eu/watch/abc#comment-c1" {
+		t.Errorf("unexpected link %q", got)
+	}
+}
+
+func TestPostWatchComment_NotifiesMentionedMembers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	notifier := &recordingCommentNotifier{}
+	handler.SetCommentNotifier(notifier)
+
+	shareToken := "abc123defghi"
+	videoID := "video-123"
+	ownerID := "owner-user-1"
+	timestamp := 42.5
+	body := "@jo can you check this? cc @owner"
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs(shareToken).
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	expectNoCommentBan(mock)
+	mock.ExpectQuery(`INSERT INTO video_comments`).
+		WithArgs(videoID, strPtr(testUserID), "Sam", "", body, false, &timestamp, (*string)(nil), (*string)(nil), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("c1", time.Now()))
+	mock.ExpectQuery(`SELECT DISTINCT ON \(u\.id\) u\.id, u\.email, u\.name, t\.handle FROM videos v JOIN organization_members author`).
+		WithArgs(videoID, testUserID, []string{"jo", "owner"}).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "email", "name", "handle"}).
+			AddRow("user-jo", "jo@example.com", "Jo", "jo").
+			AddRow(ownerID, "owner@example.com", "Owner", "owner"))
+	mock.ExpectExec(`INSERT INTO comment_mentions`).
+		WithArgs("c1", "user-jo", "jo").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(`INSERT INTO comment_mentions`).
+		WithArgs("c1", ownerID, "owner").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery(`SELECT view_notification FROM notification_preferences WHERE user_id = \$1`).
+		WithArgs(ownerID).
+		WillReturnRows(pgxmock.NewRows([]string{"view_notification"}).AddRow("off"))
+	mock.ExpectQuery(`SELECT u\.email, u\.name, v\.title FROM users u JOIN videos v`).
+		WithArgs(videoID).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "name", "title"}).AddRow("owner@example.com", "Owner", "Demo"))
+
+	payload, _ := json.Marshal(map[string]any{"authorName": "Sam", "body": body, "videoTimestamp": timestamp})
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/comments", handler.PostWatchComment)
+	req := authenticatedRequest(t, http.MethodPost, "/api/watch/"+shareToken+"/comments", payload)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp commentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Mentions) != 2 || resp.Mentions[0].Handle != "jo" || resp.Mentions[0].Name != "Jo" {
+		t.Errorf("unexpected mentions %+v", resp.Mentions)
+	}
+	if strings.Contains(rec.Body.String(), "user-jo") || strings.Contains(rec.Body.String(), "jo@example.com") {
+		t.Errorf("mentions must not expose member ids or emails: %s", rec.Body.String())
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	// The owner has comment emails off, so the mention is how they hear of it.
+	if got := notifier.sent(); strings.Join(got, ",") != "jo@example.com,owner@example.com" {
+		t.Errorf("unexpected mention recipients %v", got)
+	}
+	if got := notifier.linkFor("jo@example.com"); got != testBaseURL+"/watch/"+shareToken+"?t=42#comment-c1" {
+		t.Errorf("unexpected deep link %q", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostWatchComment_AnonymousMentionsIgnored(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	expectNoCommentBan(mock)
+	mock.ExpectQuery(`INSERT INTO video_comments`).
+		WithArgs("video-123", (*string)(nil), "", "", "hey @jo", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("c1", time.Now()))
+
+	rec := servePostComment(handler, "abc123defghi", map[string]any{"body": "hey @jo"})
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), `"mentions"`) {
+		t.Errorf("anonymous comments must not resolve mentions: %s", rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestEditWatchComment_NotifiesOnlyNewlyMentionedMembers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	notifier := &recordingCommentNotifier{}
+	handler.SetCommentNotifier(notifier)
+
+	shareToken := "abc123defghi"
+	videoID := "video-123"
+	body := "@jo @kim can you both check this?"
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs(shareToken).
+		WillReturnRows(commentVideoRows().AddRow(videoID, "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`UPDATE video_comments SET body = \$1, edited_at = now\(\)`).
+		WithArgs(body, "c1", videoID, strPtr(testUserID), (*string)(nil), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "status"}).
+			AddRow("c1", strPtr(testUserID), "Sam", false, now, (*float64)(nil), (*string)(nil), now, (*time.Time)(nil), "approved"))
+	mock.ExpectQuery(`SELECT DISTINCT ON \(u\.id\) u\.id, u\.email, u\.name, t\.handle FROM videos v JOIN organization_members author`).
+		WithArgs(videoID, testUserID, []string{"jo", "kim"}).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "email", "name", "handle"}).
+			AddRow("user-jo", "jo@example.com", "Jo", "jo").
+			AddRow("user-kim", "kim@example.com", "Kim", "kim"))
+	mock.ExpectExec(`DELETE FROM comment_mentions WHERE comment_id = \$1 AND user_id <> ALL\(\$2::uuid\[\]\)`).
+		WithArgs("c1", []string{"user-jo", "user-kim"}).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec(`INSERT INTO comment_mentions`).
+		WithArgs("c1", "user-jo", "jo").
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectExec(`INSERT INTO comment_mentions`).
+		WithArgs("c1", "user-kim", "kim").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery(`SELECT title FROM videos WHERE id = \$1`).
+		WithArgs(videoID).
+		WillReturnRows(pgxmock.NewRows([]string{"title"}).AddRow("Demo"))
+
+	payload, _ := json.Marshal(map[string]any{"body": body})
+	r := chi.NewRouter()
+	r.Patch("/api/watch/{shareToken}/comments/{commentId}", handler.EditWatchComment)
+	req := authenticatedRequest(t, http.MethodPatch, "/api/watch/"+shareToken+"/comments/c1", payload)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp commentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Mentions) != 2 {
+		t.Errorf("expected both mentions in the response, got %+v", resp.Mentions)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	// Jo was mentioned before the edit and already heard about the comment.
+	if got := notifier.sent(); strings.Join(got, ",") != "kim@example.com" {
+		t.Errorf("unexpected mention recipients %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestEditWatchComment_DroppedMentionsAreRemoved(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	notifier := &recordingCommentNotifier{}
+	handler.SetCommentNotifier(notifier)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`UPDATE video_comments SET body = \$1, edited_at = now\(\)`).
+		WithArgs("No mentions now", "c1", "video-123", strPtr(testUserID), (*string)(nil), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "status"}).
+			AddRow("c1", strPtr(testUserID), "Sam", false, now, (*float64)(nil), (*string)(nil), now, (*time.Time)(nil), "approved"))
+	mock.ExpectExec(`DELETE FROM comment_mentions WHERE comment_id = \$1`).
+		WithArgs("c1", []string{}).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	payload, _ := json.Marshal(map[string]any{"body": "No mentions now"})
+	r := chi.NewRouter()
+	r.Patch("/api/watch/{shareToken}/comments/{commentId}", handler.EditWatchComment)
+	req := authenticatedRequest(t, http.MethodPatch, "/api/watch/abc123defghi/comments/c1", payload)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := notifier.sent(); len(got) != 0 {
+		t.Errorf("expected no notifications, got %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestListWatchComments_IncludesMentions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT c\.id, c\.user_id`).
+		WithArgs("video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}).
+			AddRow("c1", strPtr(testUserID), "Sam", "@jo look", false, time.Now(), (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), []byte(`[{"handle":"jo","name":"Jo"}]`), []byte(nil), []byte(nil)))
+
+	r := chi.NewRouter()
+	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
+	req := httptest.NewRequest(http.MethodGet, "/api/watch/abc123defghi/comments", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp listCommentsResponseBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Comments) != 1 || len(resp.Comments[0].Mentions) != 1 || resp.Comments[0].Mentions[0].Name != "Jo" {
+		t.Errorf("unexpected comments %+v", resp.Comments)
+	}
+}
@@ -0,0 +1,406 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/httputil"
+	"github.com/sendrec/sendrec/internal/organization"
+)
+
+const (
+	commentPending  = "pending"
+	commentApproved = "approved"
+	commentRejected = "rejected"
+
+	// spamScoreTimeout bounds how long posting a comment waits on the spam
+	// scorer, which may call out to an AI service.
+	spamScoreTimeout = 5 * time.Second
+)
+
+type commentModeration struct {
+	status      string
+	spamScore   *float64
+	spamReasons []string
+}
+
+// moderateComment decides whether a new comment is published straight away
+// or held for review: always on a "moderated" video, otherwise when the spam
+// scorer rates it at or above spamHoldThreshold.
+func (h *Handler) moderateComment(ctx context.Context, commentMode string, c SpamCandidate) commentModeration {
+	m := commentModeration{status: commentApproved, spamReasons: []string{}}
+	if h.spamScorer != nil {
+		scoreCtx, cancel := context.WithTimeout(ctx, spamScoreTimeout)
+		verdict, err := h.spamScorer.ScoreComment(scoreCtx, c)
+		cancel()
+		if err != nil {
+			slog.Warn("comment: spam scoring failed", "error", err)
+		} else {
+			m.spamScore = &verdict.Score
+			if len(verdict.Reasons) > 0 {
+				m.spamReasons = verdict.Reasons
+			}
+			if verdict.Score >= spamHoldThreshold {
+				m.status = commentPending
+			}
+		}
+	}
+	if commentMode == "moderated" {
+		m.status = commentPending
+	}
+	return m
+}
+
+// commentAuthorBanned reports whether the would-be author of a comment on the
+// video is banned in the video's workspace, by account, email or author key.
+func (h *Handler) commentAuthorBanned(ctx context.Context, videoID string, userID *string, authorEmail string, authorKey *string) (bool, error) {
+	var emailArg *string
+	if authorEmail != "" {
+		emailArg = &authorEmail
+	}
+	var banned bool
+	err := h.db.QueryRow(ctx,
+		`SELECT EXISTS (
+		   SELECT 1 FROM comment_bans b JOIN videos v ON v.id = $1
+		   WHERE (b.organization_id = v.organization_id
+		          OR (v.organization_id IS NULL AND b.organization_id IS NULL AND b.owner_id = v.user_id))
+		     AND (b.banned_user_id = $2 OR lower(b.author_email) = lower($3) OR b.author_key = $4))`,
+		videoID, userID, emailArg, authorKey,
+	).Scan(&banned)
+	return banned, err
+}
+
+// moderationScope restricts a query over videos aliased "v" to those the
+// caller moderates: every video in the organization for owners and admins,
+// their own videos for other members, their personal videos otherwise.
+func moderationScope(ctx context.Context, firstArg int) (string, []any) {
+	userID := auth.UserIDFromContext(ctx)
+	orgID := auth.OrgIDFromContext(ctx)
+	if orgID == "" {
+		return fmt.Sprintf("v.user_id = $%d AND v.organization_id IS NULL", firstArg), []any{userID}
+	}
+	if organization.IsAdminOrOwner(auth.OrgRoleFromContext(ctx)) {
+		return fmt.Sprintf("v.organization_id = $%d", firstArg), []any{orgID}
+	}
+	return fmt.Sprintf("v.organization_id = $%d AND v.user_id = $%d", firstArg, firstArg+1), []any{orgID, userID}
+}
+
+type moderationComment struct {
+	ID             string   `json:"id"`
+	VideoID        string   `json:"videoId"`
+	VideoTitle     string   `json:"videoTitle"`
+	AuthorName     string   `json:"authorName"`
+	AuthorEmail    string   `json:"authorEmail"`
+	Authenticated  bool     `json:"authenticated"`
+	Body           string   `json:"body"`
+	Status         string   `json:"status"`
+	SpamScore      *float64 `json:"spamScore"`
+	SpamReasons    []string `json:"spamReasons"`
+	ParentID       *string  `json:"parentId"`
+	VideoTimestamp *float64 `json:"videoTimestamp,omitempty"`
+	CreatedAt      string   `json:"createdAt"`
+}
+
+type moderationInboxResponse struct {
+	Comments []moderationComment `json:"comments"`
+}
+
+func (h *Handler) ModerationInbox(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = commentPending
+	}
+	if status != commentPending && status != commentRejected {
+		httputil.WriteError(w, http.StatusBadRequest, "status must be pending or rejected")
+		return
+	}
+	limit := defaultPageSize
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	offset := 0
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o > 0 {
+		offset = o
+	}
+
+	scope, args := moderationScope(r.Context(), 2)
+	args = append([]any{status}, args...)
+	args = append(args, limit, offset)
+	rows, err := h.db.Query(r.Context(),
+		`SELECT c.id, c.video_id, v.title, c.author_name, c.author_email, c.user_id IS NOT NULL, c.body, c.status,
+		        c.spam_score, c.spam_reasons, c.parent_id, c.video_timestamp_seconds, c.created_at
+		 FROM video_comments c
+		 JOIN videos v ON v.id = c.video_id
+		 WHERE c.status = $1 AND v.status != 'deleted' AND `+scope+`
+		 ORDER BY c.created_at DESC
+		 LIMIT $`+strconv.Itoa(len(args)-1)+` OFFSET $`+strconv.Itoa(len(args)),
+		args...,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch comments")
+		return
+	}
+	defer rows.Close()
+
+	comments := []moderationComment{}
+	for rows.Next() {
+		var c moderationComment
+		var createdAt time.Time
+		if err := rows.Scan(&c.ID, &c.VideoID, &c.VideoTitle, &c.AuthorName, &c.AuthorEmail, &c.Authenticated, &c.Body, &c.Status,
+			&c.SpamScore, &c.SpamReasons, &c.ParentID, &c.VideoTimestamp, &createdAt); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not fetch comments")
+			return
+		}
+		c.CreatedAt = createdAt.Format(time.RFC3339)
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch comments")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, moderationInboxResponse{Comments: comments})
+}
+
+func (h *Handler) ApproveComment(w http.ResponseWriter, r *http.Request) {
+	h.moderateExistingComment(w, r, commentApproved)
+}
+
+func (h *Handler) RejectComment(w http.ResponseWriter, r *http.Request) {
+	h.moderateExistingComment(w, r, commentRejected)
+}
+
+func (h *Handler) moderateExistingComment(w http.ResponseWriter, r *http.Request, status string) {
+	userID := auth.UserIDFromContext(r.Context())
+	videoID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentId")
+
+	filter, args := orgVideoFilter(r.Context(), videoID, []any{status, userID, commentID}, "")
+	var notice commentNotice
+	var authorUserID *string
+	var isPrivate bool
+	err := h.db.QueryRow(r.Context(),
+		`UPDATE video_comments SET status = $1, moderated_at = now(), moderated_by = $2
+		 WHERE id = $3 AND status <> $1 AND video_id IN (SELECT id FROM videos WHERE `+filter+`)
+		 RETURNING user_id, author_name, author_email, body, is_private, parent_id, video_timestamp_seconds`,
+		args...,
+	).Scan(&authorUserID, &notice.authorName, &notice.authorEmail, &notice.body, &isPrivate, &notice.parentID, &notice.videoTimestamp)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+
+	if status == commentApproved && !isPrivate {
+		notice.videoID = videoID
+		notice.commentID = commentID
+		if authorUserID != nil {
+			notice.authorUserID = *authorUserID
+		}
+		go h.announceApprovedComment(notice)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// announceApprovedComment sends the notifications a held comment skipped.
+// The owner's inbox is left out since they, or an admin, just approved it.
+func (h *Handler) announceApprovedComment(n commentNotice) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := h.db.QueryRow(ctx,
+		`SELECT share_token, user_id FROM videos WHERE id = $1`,
+		n.videoID,
+	).Scan(&n.shareToken, &n.ownerID); err != nil {
+		slog.Error("comment: failed to load video for approved comment", "comment_id", n.commentID, "error", err)
+		return
+	}
+
+	rows, err := h.db.Query(ctx,
+		`SELECT m.user_id, u.email, u.name, m.handle
+		 FROM comment_mentions m JOIN users u ON u.id = m.user_id
+		 WHERE m.comment_id = $1`,
+		n.commentID,
+	)
+	if err != nil {
+		slog.Error("comment: failed to load mentions for approved comment", "comment_id", n.commentID, "error", err)
+		return
+	}
+	for rows.Next() {
+		var m mentionedMember
+		if err := rows.Scan(&m.userID, &m.email, &m.name, &m.handle); err != nil {
+			rows.Close()
+			slog.Error("comment: failed to load mentions for approved comment", "comment_id", n.commentID, "error", err)
+			return
+		}
+		n.mentioned = append(n.mentioned, m)
+	}
+	rows.Close()
+
+	n.webhookOwner = true
+	if n.notifiesAnyone(h) {
+		h.sendCommentNotifications(n)
+	}
+}
+
+type commentBanResponse struct {
+	ID          string  `json:"id"`
+	AuthorName  string  `json:"authorName"`
+	AuthorEmail *string `json:"authorEmail"`
+	AccountBan  bool    `json:"accountBan"`
+	CreatedAt   string  `json:"createdAt"`
+}
+
+func (h *Handler) BanCommentAuthor(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	videoID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentId")
+
+	// A ban covers the whole workspace, so only its owners and admins may
+	// place one.
+	if auth.OrgIDFromContext(r.Context()) != "" && organization.RequireRole(w, r, "owner", "admin") == "" {
+		return
+	}
+
+	filter, args := orgVideoFilter(r.Context(), videoID, []any{commentID}, "")
+	var authorUserID, authorKey, orgID *string
+	var authorName, authorEmail, ownerID string
+	var authorIsMember bool
+	err := h.db.QueryRow(r.Context(),
+		`SELECT c.user_id, c.author_name, c.author_email, c.author_key, v.organization_id, v.user_id,
+		        EXISTS (SELECT 1 FROM organization_members om WHERE om.organization_id = v.organization_id AND om.user_id = c.user_id)
+		 FROM video_comments c JOIN videos v ON v.id = c.video_id
+		 WHERE c.id = $1 AND c.video_id IN (SELECT id FROM videos WHERE `+filter+`)`,
+		args...,
+	).Scan(&authorUserID, &authorName, &authorEmail, &authorKey, &orgID, &ownerID, &authorIsMember)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+	if authorUserID != nil && (*authorUserID == userID || *authorUserID == ownerID) {
+		httputil.WriteError(w, http.StatusBadRequest, "the video owner can't be banned")
+		return
+	}
+	if authorIsMember {
+		httputil.WriteError(w, http.StatusBadRequest, "workspace members can't be banned")
+		return
+	}
+	var emailArg *string
+	if authorEmail != "" {
+		emailArg = &authorEmail
+	}
+	if authorUserID == nil && emailArg == nil && authorKey == nil {
+		httputil.WriteError(w, http.StatusBadRequest, "this comment's author can't be identified")
+		return
+	}
+
+	var ownerArg *string
+	if orgID == nil {
+		ownerArg = &ownerID
+	}
+	var banID string
+	var createdAt time.Time
+	err = h.db.QueryRow(r.Context(),
+		`INSERT INTO comment_bans (organization_id, owner_id, banned_user_id, author_email, author_key, author_name, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING id, created_at`,
+		orgID, ownerArg, authorUserID, emailArg, authorKey, authorName, userID,
+	).Scan(&banID, &createdAt)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not ban author")
+		return
+	}
+
+	// Reject this comment along with anything else the author has waiting.
+	if _, err := h.db.Exec(r.Context(),
+		`UPDATE video_comments c SET status = 'rejected', moderated_at = now(), moderated_by = $1
+		 FROM videos v
+		 WHERE v.id = c.video_id AND (c.id = $2 OR c.status = 'pending')
+		   AND v.organization_id IS NOT DISTINCT FROM $3 AND ($3 IS NOT NULL OR v.user_id = $4)
+		   AND (c.user_id = $5 OR (c.author_email <> '' AND lower(c.author_email) = lower($6)) OR c.author_key = $7)`,
+		userID, commentID, orgID, ownerID, authorUserID, emailArg, authorKey,
+	); err != nil {
+		slog.Error("comment: failed to reject banned author's comments", "ban_id", banID, "error", err)
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, commentBanResponse{
+		ID:          banID,
+		AuthorName:  authorName,
+		AuthorEmail: emailArg,
+		AccountBan:  authorUserID != nil,
+		CreatedAt:   createdAt.Format(time.RFC3339),
+	})
+}
+
+// banScope restricts comment_bans to the caller's workspace.
+func banScope(ctx context.Context, firstArg int) (string, []any) {
+	if orgID := auth.OrgIDFromContext(ctx); orgID != "" {
+		return fmt.Sprintf("organization_id = $%d", firstArg), []any{orgID}
+	}
+	return fmt.Sprintf("owner_id = $%d AND organization_id IS NULL", firstArg), []any{auth.UserIDFromContext(ctx)}
+}
+
+func (h *Handler) ListCommentBans(w http.ResponseWriter, r *http.Request) {
+	scope, args := banScope(r.Context(), 1)
+	rows, err := h.db.Query(r.Context(),
+		`SELECT id, author_name, author_email, banned_user_id IS NOT NULL, created_at
+		 FROM comment_bans WHERE `+scope+` ORDER BY created_at DESC`,
+		args...,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch bans")
+		return
+	}
+	defer rows.Close()
+
+	bans := []commentBanResponse{}
+	for rows.Next() {
+		var b commentBanResponse
+		var createdAt time.Time
+		if err := rows.Scan(&b.ID, &b.AuthorName, &b.AuthorEmail, &b.AccountBan, &createdAt); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not fetch bans")
+			return
+		}
+		b.CreatedAt = createdAt.Format(time.RFC3339)
+		bans = append(bans, b)
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch bans")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, bans)
+}
+
+func (h *Handler) DeleteCommentBan(w http.ResponseWriter, r *http.Request) {
+	banID := chi.URLParam(r, "banId")
+	scope, args := banScope(r.Context(), 2)
+	if auth.OrgIDFromContext(r.Context()) != "" && !organization.IsAdminOrOwner(auth.OrgRoleFromContext(r.Context())) {
+		// Members may only lift bans they placed themselves.
+		scope += fmt.Sprintf(" AND created_by = $%d", len(args)+2)
+		args = append(args, auth.UserIDFromContext(r.Context()))
+	}
+
+	tag, err := h.db.Exec(r.Context(),
+		`DELETE FROM comment_bans WHERE id = $1 AND `+scope,
+		append([]any{banID}, args...)...,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not remove ban")
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "ban not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,460 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/auth"
+)
+
+func expectNoCommentBan(mock pgxmock.PgxPoolIface) {
+	mock.ExpectQuery(`SELECT EXISTS \( SELECT 1 FROM comment_bans b`).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+}
+
+type failingSpamScorer struct{}
+
+func (failingSpamScorer) ScoreComment(context.Context, SpamCandidate) (SpamVerdict, error) {
+	return SpamVerdict{}, errors.New("unavailable")
+}
+
+func TestHeuristicSpamScorer(t *testing.T) {
+	scorer := NewHeuristicSpamScorer(2, []string{" Casino ", ""})
+	tests := []struct {
+		name      string
+		candidate SpamCandidate
+		wantHold  bool
+	}{
+		{"plain comment", SpamCandidate{Body: "Great walkthrough, thanks!"}, false},
+		{"one link from a signed-in member", SpamCandidate{Body: "see https://example.com", Authenticated: true}, false},
+		{"one link from an anonymous viewer", SpamCandidate{Body: "see https://example.com"}, false},
+		{"too many links", SpamCandidate{Body: "http://a.io http://b.io www.c.io", Authenticated: true}, true},
+		{"blocklisted term in body", SpamCandidate{Body: "best CASINO bonus"}, true},
+		{"blocklisted term in name", SpamCandidate{Body: "hi", AuthorName: "casino king"}, true},
+	}
+	for _, tt := range tests {
+		v, err := scorer.ScoreComment(context.Background(), tt.candidate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := v.Score >= spamHoldThreshold; got != tt.wantHold {
+			t.Errorf("%s: score %v, want hold=%v", tt.name, v.Score, tt.wantHold)
+		}
+		if v.Score > 1 {
+			t.Errorf("%s: score %v exceeds 1", tt.name, v.Score)
+		}
+		if tt.wantHold && len(v.Reasons) == 0 {
+			t.Errorf("%s: expected reasons for a held comment", tt.name)
+		}
+	}
+}
+
+func TestCombinedSpamScorer_SkipsFailingScorers(t *testing.T) {
+	scorer := NewCombinedSpamScorer(failingSpamScorer{}, NewHeuristicSpamScorer(0, nil))
+	v, err := scorer.ScoreComment(context.Background(), SpamCandidate{Body: "visit https://spam.example"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Score != 0.6 || len(v.Reasons) != 1 {
+		t.Errorf("unexpected verdict %+v", v)
+	}
+}
+
+func TestAIClient_ClassifyComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(chatResponse{Choices: []chatChoice{
+			{Message: chatMessage{Role: "assistant", Content: "```json\n{\"spam_probability\":0.9,\"reason\":\"crypto promotion\"}\n```"}},
+		}})
+	}))
+	defer server.Close()
+
+	v, err := NewAISpamScorer(NewAIClient(server.URL, "", "gpt-4", 0)).ScoreComment(context.Background(), SpamCandidate{Body: "buy coins"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Score != 0.9 || len(v.Reasons) != 1 || v.Reasons[0] != "AI: crypto promotion" {
+		t.Errorf("unexpected verdict %+v", v)
+	}
+}
+
+func TestAIClient_ClassifyComment_OutOfRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(chatResponse{Choices: []chatChoice{
+			{Message: chatMessage{Role: "assistant", Content: `{"spam_probability":7}`}},
+		}})
+	}))
+	defer server.Close()
+
+	if _, _, err := NewAIClient(server.URL, "", "gpt-4", 0).ClassifyComment(context.Background(), "Sam", "hi"); err == nil {
+		t.Fatal("expected an error for an out-of-range probability")
+	}
+}
+
+func TestPostWatchComment_ModeratedModeHoldsComment(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	notifier := &recordingCommentNotifier{}
+	handler.SetCommentNotifier(notifier)
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "moderated", (*time.Time)(nil), (*string)(nil), "public"))
+	expectNoCommentBan(mock)
+	mock.ExpectQuery(`INSERT INTO video_comments`).
+		WithArgs("video-123", (*string)(nil), "", "", "first!", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "pending", (*float64)(nil), []string{}, []byte(nil)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("c1", time.Now()))
+
+	rec := servePostComment(handler, "abc123defghi", map[string]any{"body": "first!"})
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp commentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != commentPending {
+		t.Errorf("expected status pending, got %q", resp.Status)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := notifier.sent(); len(got) != 0 {
+		t.Errorf("held comments must not notify anyone, got %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostWatchComment_SpamIsHeld(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetSpamScorer(NewHeuristicSpamScorer(2, []string{"casino"}))
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	expectNoCommentBan(mock)
+	score := 0.8
+	mock.ExpectQuery(`INSERT INTO video_comments`).
+		WithArgs("video-123", (*string)(nil), "", "", "free casino spins", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "pending", &score, []string{`blocklisted term "casino"`}, []byte(nil)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("c1", time.Now()))
+
+	rec := servePostComment(handler, "abc123defghi", map[string]any{"body": "free casino spins"})
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostWatchComment_BannedAuthor(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "name_email_required", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT EXISTS \( SELECT 1 FROM comment_bans b`).
+		WithArgs("video-123", (*string)(nil), strPtr("spam@example.com"), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	rec := servePostComment(handler, "abc123defghi", map[string]any{"authorName": "Spammer", "authorEmail": "spam@example.com", "body": "hello"})
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRejectComment(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	returning := []string{"user_id", "author_name", "author_email", "body", "is_private", "parent_id", "video_timestamp_seconds"}
+	mock.ExpectQuery(`UPDATE video_comments SET status = \$1, moderated_at = now\(\), moderated_by = \$2 WHERE id = \$3 AND status <> \$1`).
+		WithArgs(commentRejected, testUserID, "c1", "video-123", testUserID).
+		WillReturnRows(pgxmock.NewRows(returning).AddRow((*string)(nil), "Spammer", "", "spam", false, (*string)(nil), (*float64)(nil)))
+	mock.ExpectQuery(`UPDATE video_comments SET status = \$1`).
+		WithArgs(commentRejected, testUserID, "c2", "video-123", testUserID).
+		WillReturnRows(pgxmock.NewRows(returning))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/comments/{commentId}/reject", handler.RejectComment)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/video-123/comments/c1/reject", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/video-123/comments/c2/reject", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestApproveComment_NotifiesMentionedMembers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	notifier := &recordingCommentNotifier{}
+	handler.SetCommentNotifier(notifier)
+
+	mock.ExpectQuery(`UPDATE video_comments SET status = \$1`).
+		WithArgs(commentApproved, testUserID, "c1", "video-123", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "author_name", "author_email", "body", "is_private", "parent_id", "video_timestamp_seconds"}).
+			AddRow(strPtr("member-1"), "Sam", "", "@jo look", false, (*string)(nil), (*float64)(nil)))
+	mock.ExpectQuery(`SELECT share_token, user_id FROM videos WHERE id = \$1`).
+		WithArgs("video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"share_token", "user_id"}).AddRow("abc123defghi", testUserID))
+	mock.ExpectQuery(`SELECT m\.user_id, u\.email, u\.name, m\.handle FROM comment_mentions m`).
+		WithArgs("c1").
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "email", "name", "handle"}).AddRow("user-jo", "jo@example.com", "Jo", "jo"))
+	mock.ExpectQuery(`SELECT u\.email, u\.name, v\.title FROM users u JOIN videos v`).
+		WithArgs("video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"email", "name", "title"}).AddRow("owner@example.com", "Owner", "Demo"))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/comments/{commentId}/approve", handler.ApproveComment)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/video-123/comments/c1/approve", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := notifier.sent(); len(got) != 1 || got[0] != "jo@example.com" {
+		t.Errorf("expected only the mentioned member to be notified, got %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestModerationInbox_OrgAdminSeesWholeOrganization(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	score := 0.8
+	mock.ExpectQuery(`WHERE c\.status = \$1 AND v\.status != 'deleted' AND v\.organization_id = \$2 ORDER BY c\.created_at DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs(commentPending, testOrgID, maxPageSize, 0).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "video_id", "title", "author_name", "author_email", "authenticated", "body", "status", "spam_score", "spam_reasons", "parent_id", "video_timestamp_seconds", "created_at"}).
+			AddRow("c1", "video-123", "Demo", "Spammer", "", false, "casino", commentPending, &score, []string{`blocklisted term "casino"`}, (*string)(nil), (*float64)(nil), time.Now()))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/comments/moderation", handler.ModerationInbox)
+	req := authenticatedRequest(t, http.MethodGet, "/api/videos/comments/moderation?limit=500", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(auth.ContextWithOrg(req.Context(), testOrgID, "admin")))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp moderationInboxResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Comments) != 1 || resp.Comments[0].SpamScore == nil || len(resp.Comments[0].SpamReasons) != 1 {
+		t.Errorf("unexpected inbox %+v", resp.Comments)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/comments/moderation?status=approved", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown status, got %d", rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestBanCommentAuthor(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	email := "spam@example.com"
+	mock.ExpectQuery(`SELECT c\.user_id, c\.author_name, c\.author_email, c\.author_key, v\.organization_id, v\.user_id`).
+		WithArgs("c1", "video-123", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "author_name", "author_email", "author_key", "organization_id", "user_id", "exists"}).
+			AddRow((*string)(nil), "Spammer", email, strPtr("key-1"), (*string)(nil), testUserID, false))
+	mock.ExpectQuery(`INSERT INTO comment_bans`).
+		WithArgs((*string)(nil), strPtr(testUserID), (*string)(nil), &email, strPtr("key-1"), "Spammer", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("ban-1", time.Now()))
+	mock.ExpectExec(`UPDATE video_comments c SET status = 'rejected'`).
+		WithArgs(testUserID, "c1", (*string)(nil), testUserID, (*string)(nil), &email, strPtr("key-1")).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/comments/{commentId}/ban", handler.BanCommentAuthor)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/video-123/comments/c1/ban", nil))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp commentBanResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != "ban-1" || resp.AccountBan || resp.AuthorEmail == nil || *resp.AuthorEmail != email {
+		t.Errorf("unexpected ban %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestBanCommentAuthor_RefusesOwner(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT c\.user_id, c\.author_name`).
+		WithArgs("c1", "video-123", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "author_name", "author_email", "author_key", "organization_id", "user_id", "exists"}).
+			AddRow(strPtr(testUserID), "Me", "", (*string)(nil), (*string)(nil), testUserID, false))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/comments/{commentId}/ban", handler.BanCommentAuthor)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/video-123/comments/c1/ban", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestBanCommentAuthor_OrgMemberCannotBan(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/comments/{commentId}/ban", handler.BanCommentAuthor)
+	req := authenticatedRequest(t, http.MethodPost, "/api/videos/video-123/comments/c1/ban", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(auth.ContextWithOrg(req.Context(), testOrgID, "member")))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestBanCommentAuthor_RefusesOrgMember(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT c\.user_id, c\.author_name`).
+		WithArgs("c1", "video-123", testOrgID).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "author_name", "author_email", "author_key", "organization_id", "user_id", "exists"}).
+			AddRow(strPtr("colleague-id"), "Colleague", "colleague@example.com", (*string)(nil), strPtr(testOrgID), "owner-id", true))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/comments/{commentId}/ban", handler.BanCommentAuthor)
+	req := authenticatedRequest(t, http.MethodPost, "/api/videos/video-123/comments/c1/ban", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(auth.ContextWithOrg(req.Context(), testOrgID, "admin")))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteCommentBan_MemberLimitedToOwnBans(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectExec(`DELETE FROM comment_bans WHERE id = \$1 AND organization_id = \$2 AND created_by = \$3`).
+		WithArgs("ban-1", testOrgID, testUserID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Delete("/api/videos/comments/bans/{banId}", handler.DeleteCommentBan)
+	req := authenticatedRequest(t, http.MethodDelete, "/api/videos/comments/bans/ban-1", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(auth.ContextWithOrg(req.Context(), testOrgID, "member")))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// spamHoldThreshold is the score at or above which a comment is held for the
+// owner's review instead of being published.
+const spamHoldThreshold = 0.5
+
+// SpamCandidate is a comment about to be published.
+type SpamCandidate struct {
+	Body          string
+	AuthorName    string
+	AuthorEmail   string
+	Authenticated bool
+}
+
+// SpamVerdict scores a comment from 0 (clean) to 1 (certain spam) and says why.
+type SpamVerdict struct {
+	Score   float64
+	Reasons []string
+}
+
+// SpamScorer rates how likely a comment is to be spam.
+type SpamScorer interface {
+	ScoreComment(ctx context.Context, c SpamCandidate) (SpamVerdict, error)
+}
+
+var commentLinkPattern = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.`)
+
+// HeuristicSpamScorer flags comments with many links or blocklisted terms.
+type HeuristicSpamScorer struct {
+	maxLinks  int
+	blocklist []string
+}
+
+// NewHeuristicSpamScorer builds a scorer that tolerates up to maxLinks links
+// and treats any of the given terms (matched case-insensitively) as spam.
+func NewHeuristicSpamScorer(maxLinks int, blocklist []string) *HeuristicSpamScorer {
+	terms := make([]string, 0, len(blocklist))
+	for _, term := range blocklist {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return &HeuristicSpamScorer{maxLinks: maxLinks, blocklist: terms}
+}
+
+func (s *HeuristicSpamScorer) ScoreComment(_ context.Context, c SpamCandidate) (SpamVerdict, error) {
+	var v SpamVerdict
+	if links := len(commentLinkPattern.FindAllStringIndex(c.Body, -1)); links > s.maxLinks {
+		v.Score += 0.6
+		v.Reasons = append(v.Reasons, fmt.Sprintf("contains %d links", links))
+	} else if links > 0 && !c.Authenticated {
+		v.Score += 0.2
+		v.Reasons = append(v.Reasons, "anonymous comment with a link")
+	}
+
+	text := strings.ToLower(c.AuthorName + " " + c.Body)
+	for _, term := range s.blocklist {
+		if strings.Contains(text, term) {
+			v.Score += 0.8
+			v.Reasons = append(v.Reasons, fmt.Sprintf("blocklisted term %q", term))
+			break
+		}
+	}
+
+	if v.Score > 1 {
+		v.Score = 1
+	}
+	return v, nil
+}
+
+// AISpamScorer asks the configured language model to classify a comment.
+type AISpamScorer struct {
+	client *AIClient
+}
+
+func NewAISpamScorer(client *AIClient) *AISpamScorer {
+	return &AISpamScorer{client: client}
+}
+
+func (s *AISpamScorer) ScoreComment(ctx context.Context, c SpamCandidate) (SpamVerdict, error) {
+	probability, reason, err := s.client.ClassifyComment(ctx, c.AuthorName, c.Body)
+	if err != nil {
+		return SpamVerdict{}, err
+	}
+	v := SpamVerdict{Score: probability}
+	if probability >= spamHoldThreshold {
+		if reason == "" {
+			reason = "classified as spam"
+		}
+		v.Reasons = []string{"AI: " + reason}
+	}
+	return v, nil
+}
+
+// CombinedSpamScorer takes the highest score of several scorers. A scorer
+// that fails is skipped, so an unreachable AI service never blocks comments.
+type CombinedSpamScorer struct {
+	scorers []SpamScorer
+}
+
+func NewCombinedSpamScorer(scorers ...SpamScorer) *CombinedSpamScorer {
+	return &CombinedSpamScorer{scorers: scorers}
+}
+
+func (s *CombinedSpamScorer) ScoreComment(ctx context.Context, c SpamCandidate) (SpamVerdict, error) {
+	var combined SpamVerdict
+	for _, scorer := range s.scorers {
+		v, err := scorer.ScoreComment(ctx, c)
+		if err != nil {
+			slog.Warn("comment: spam scorer failed", "error", err)
+			continue
+		}
+		if v.Score > combined.Score {
+			combined.Score = v.Score
+		}
+		combined.Reasons = append(combined.Reasons, v.Reasons...)
+	}
+	return combined, nil
+}
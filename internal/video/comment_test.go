@@ -162,7 +162,7 @@ func (m *mockCommentNotifier) SendCommentNotification(_ context.Context, toEmail
 // --- PostWatchComment Tests ---
 
 func commentVideoRows() *pgxmock.Rows {
-	return pgxmock.NewRows([]string{"id", "user_id", "comment_mode", "share_expires_at", "share_password"})
+	return pgxmock.NewRows([]string{"id", "user_id", "comment_mode", "share_expires_at", "share_password", "visibility"})
 }
 
 func TestPostWatchComment_NullShareExpiresAt_DoesNotReturn404(t *testing.T) {
@@ -179,12 +179,13 @@ func TestPostWatchComment_NullShareExpiresAt_DoesNotReturn404(t *testing.T) {
 	videoID := "video-123"
 	ownerID := "owner-user-1"
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", (*time.Time)(nil), (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "Someone", "", "Great video!", false, (*float64)(nil)).
+		WithArgs(videoID, (*string)(nil), "Someone", "", "Great video!", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-1", time.Now()))
 
 	body, _ := json.Marshal(postCommentRequest{AuthorName: "Someone", Body: "Great video!"})
@@ -220,13 +221,13 @@ func TestListWatchComments_NullShareExpiresAt_DoesNotReturn404(t *testing.T) {
 	videoID := "video-123"
 	ownerID := "owner-user-1"
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", (*time.Time)(nil), (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
 
 	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds`).
 		WithArgs(videoID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds"}))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}))
 
 	r := chi.NewRouter()
 	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
@@ -261,12 +262,13 @@ func TestPostWatchComment_AnonymousMode_Success(t *testing.T) {
 	ownerID := "owner-user-1"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "Someone", "", "Great video!", false, (*float64)(nil)).
+		WithArgs(videoID, (*string)(nil), "Someone", "", "Great video!", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-1", time.Now()))
 
 	mock.ExpectQuery(`SELECT view_notification FROM notification_preferences WHERE user_id = \$1`).
@@ -326,9 +328,9 @@ func TestPostWatchComment_DisabledMode_Returns403(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "disabled", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "disabled", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{Body: "Hello"})
 
@@ -362,9 +364,9 @@ func TestPostWatchComment_NameRequired_MissingName_Returns400(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_required", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_required", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{Body: "Hello"})
 
@@ -394,9 +396,9 @@ func TestPostWatchComment_NameEmailRequired_MissingEmail_Returns400(t *testing.T
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_email_required", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_email_required", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{AuthorName: "Alex", Body: "Hello"})
 
@@ -427,12 +429,13 @@ func TestPostWatchComment_NameRequired_ReactionAllowsAnonymous(t *testing.T) {
 	videoID := "video-123"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, "owner-1", "name_required", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, "owner-1", "name_required", &expiresAt, (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "", "", "👍", false, (*float64)(nil)).
+		WithArgs(videoID, (*string)(nil), "", "", "👍", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-1", time.Now()))
 
 	body, _ := json.Marshal(postCommentRequest{Body: "👍"})
@@ -464,12 +467,13 @@ func TestPostWatchComment_NameEmailRequired_ReactionAllowsAnonymous(t *testing.T
 	videoID := "video-123"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, "owner-1", "name_email_required", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, "owner-1", "name_email_required", &expiresAt, (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "", "", "🎉", false, (*float64)(nil)).
+		WithArgs(videoID, (*string)(nil), "", "", "🎉", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-1", time.Now()))
 
 	body, _ := json.Marshal(postCommentRequest{Body: "🎉"})
@@ -500,9 +504,9 @@ func TestPostWatchComment_NameRequired_NonQuickReactionEmojiStillRequiresName(t
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_required", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_required", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{Body: "🔥"})
 
@@ -536,12 +540,13 @@ func TestPostWatchComment_QuickReaction_DoesNotSendCommentNotification(t *testin
 	ownerID := "owner-user-1"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "", "", "🎉", false, (*float64)(nil)).
+		WithArgs(videoID, (*string)(nil), "", "", "🎉", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-1", time.Now()))
 
 	body, _ := json.Marshal(postCommentRequest{Body: "🎉"})
@@ -582,12 +587,13 @@ func TestPostWatchComment_NotificationModeViewsOnly_DoesNotSendCommentNotificati
 	ownerID := "owner-user-1"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "Someone", "", "Great video!", false, (*float64)(nil)).
+		WithArgs(videoID, (*string)(nil), "Someone", "", "Great video!", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-1", time.Now()))
 
 	mock.ExpectQuery(`SELECT view_notification FROM notification_preferences WHERE user_id = \$1`).
@@ -638,12 +644,13 @@ func TestPostWatchComment_NotificationModeCommentsOnly_SendsCommentNotification(
 	ownerID := "owner-user-1"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "Someone", "", "Great video!", false, (*float64)(nil)).
+		WithArgs(videoID, (*string)(nil), "Someone", "", "Great video!", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-1", time.Now()))
 
 	mock.ExpectQuery(`SELECT view_notification FROM notification_preferences WHERE user_id = \$1`).
@@ -694,9 +701,9 @@ func TestPostWatchComment_EmptyBody_Returns400(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{Body: ""})
 
@@ -726,9 +733,9 @@ func TestPostWatchComment_NameTooLong_Returns400(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_required", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_required", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{
 		AuthorName: strings.Repeat("a", 201),
@@ -770,9 +777,9 @@ func TestPostWatchComment_EmailTooLong_Returns400(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_email_required", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "name_email_required", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{
 		AuthorName:  "Alex",
@@ -815,9 +822,9 @@ func TestPostWatchComment_PrivateWithoutJWT_Returns401(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{Body: "Private note", IsPrivate: true})
 
@@ -847,9 +854,9 @@ func TestPostWatchComment_ExpiredVideo_Returns410(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(-1 * time.Hour) // expired
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{Body: "Hello"})
 
@@ -878,7 +885,7 @@ func TestPostWatchComment_VideoNotFound_Returns404(t *testing.T) {
 
 	shareToken := "nonexistent12"
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
 		WillReturnRows(commentVideoRows())
 
@@ -910,9 +917,9 @@ func TestPostWatchComment_BodyTooLong_Returns400(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil), "public"))
 
 	longBody := make([]byte, 5001)
 	for i := range longBody {
@@ -951,12 +958,13 @@ func TestPostWatchComment_WithValidTimestamp_Success(t *testing.T) {
 	expiresAt := time.Now().Add(24 * time.Hour)
 	timestamp := 83.5
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "Someone", "", "Great at 83.5s!", false, &timestamp).
+		WithArgs(videoID, (*string)(nil), "Someone", "", "Great at 83.5s!", false, &timestamp, (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-ts", time.Now()))
 
 	body, _ := json.Marshal(postCommentRequest{
@@ -1007,9 +1015,9 @@ func TestPostWatchComment_NegativeTimestamp_Returns400(t *testing.T) {
 	expiresAt := time.Now().Add(24 * time.Hour)
 	negativeTimestamp := -5.0
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil), "public"))
 
 	body, _ := json.Marshal(postCommentRequest{
 		Body:           "Bad timestamp",
@@ -1053,12 +1061,13 @@ func TestPostWatchComment_NilTimestamp_Success(t *testing.T) {
 	ownerID := "owner-user-1"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
+	expectNoCommentBan(mock)
 	mock.ExpectQuery(`INSERT INTO video_comments`).
-		WithArgs(videoID, (*string)(nil), "", "", "No timestamp here", false, (*float64)(nil)).
+		WithArgs(videoID, (*string)(nil), "", "", "No timestamp here", false, (*float64)(nil), (*string)(nil), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("comment-no-ts", time.Now()))
 
 	body, _ := json.Marshal(postCommentRequest{
@@ -1107,15 +1116,15 @@ func TestListWatchComments_IncludesTimestamp(t *testing.T) {
 	now := time.Now()
 	timestamp := 42.7
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
-	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds FROM video_comments c WHERE c\.video_id = \$1 AND c\.is_private = false ORDER BY c\.created_at ASC`).
+	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds, c\.parent_id, c\.edited_at, c\.resolved_at, c\.author_key, \(SELECT json_agg.* FROM video_comments c WHERE c\.video_id = \$1 AND c\.status = 'approved' AND c\.is_private = false ORDER BY c\.created_at ASC`).
 		WithArgs(videoID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds"}).
-			AddRow("c1", (*string)(nil), "Alex", "At 42.7s", false, now, &timestamp).
-			AddRow("c2", (*string)(nil), "Bob", "General comment", false, now, (*float64)(nil)))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}).
+			AddRow("c1", (*string)(nil), "Alex", "At 42.7s", false, now, &timestamp, (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)).
+			AddRow("c2", (*string)(nil), "Bob", "General comment", false, now, (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)))
 
 	r := chi.NewRouter()
 	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
@@ -1175,15 +1184,15 @@ func TestListWatchComments_PublicOnly(t *testing.T) {
 	expiresAt := time.Now().Add(24 * time.Hour)
 	now := time.Now()
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
-	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds FROM video_comments c WHERE c\.video_id = \$1 AND c\.is_private = false ORDER BY c\.created_at ASC`).
+	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds, c\.parent_id, c\.edited_at, c\.resolved_at, c\.author_key, \(SELECT json_agg.* FROM video_comments c WHERE c\.video_id = \$1 AND c\.status = 'approved' AND c\.is_private = false ORDER BY c\.created_at ASC`).
 		WithArgs(videoID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds"}).
-			AddRow("c1", (*string)(nil), "Alex", "Nice!", false, now, (*float64)(nil)).
-			AddRow("c2", &ownerID, "Owner", "Thanks!", false, now, (*float64)(nil)))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}).
+			AddRow("c1", (*string)(nil), "Alex", "Nice!", false, now, (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)).
+			AddRow("c2", &ownerID, "Owner", "Thanks!", false, now, (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)))
 
 	r := chi.NewRouter()
 	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
@@ -1228,9 +1237,9 @@ func TestListWatchComments_DisabledMode_ReturnsEmptyArray(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "disabled", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "disabled", &expiresAt, (*string)(nil), "public"))
 
 	r := chi.NewRouter()
 	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
@@ -1271,14 +1280,14 @@ func TestListWatchComments_WithJWT_NonOwner_ExcludesPrivate(t *testing.T) {
 	expiresAt := time.Now().Add(24 * time.Hour)
 	now := time.Now()
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
-	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds FROM video_comments c WHERE c\.video_id = \$1 AND c\.is_private = false ORDER BY c\.created_at ASC`).
+	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds, c\.parent_id, c\.edited_at, c\.resolved_at, c\.author_key, \(SELECT json_agg.* FROM video_comments c WHERE c\.video_id = \$1 AND c\.status = 'approved' AND c\.is_private = false ORDER BY c\.created_at ASC`).
 		WithArgs(videoID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds"}).
-			AddRow("c1", (*string)(nil), "Alex", "Public", false, now, (*float64)(nil)))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}).
+			AddRow("c1", (*string)(nil), "Alex", "Public", false, now, (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)))
 
 	r := chi.NewRouter()
 	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
@@ -1317,15 +1326,15 @@ func TestListWatchComments_WithJWT_Owner_IncludesPrivate(t *testing.T) {
 	now := time.Now()
 	commenterID := "commenter-1"
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", &expiresAt, (*string)(nil), "public"))
 
-	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds FROM video_comments c WHERE c\.video_id = \$1 ORDER BY c\.created_at ASC`).
+	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds, c\.parent_id, c\.edited_at, c\.resolved_at, c\.author_key, \(SELECT json_agg.* FROM video_comments c WHERE c\.video_id = \$1 AND c\.status = 'approved' ORDER BY c\.created_at ASC`).
 		WithArgs(videoID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds"}).
-			AddRow("c1", (*string)(nil), "Alex", "Public", false, now, (*float64)(nil)).
-			AddRow("c2", &commenterID, "Viewer", "Private note", true, now, (*float64)(nil)))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}).
+			AddRow("c1", (*string)(nil), "Alex", "Public", false, now, (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)).
+			AddRow("c2", &commenterID, "Viewer", "Private note", true, now, (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)))
 
 	r := chi.NewRouter()
 	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
@@ -1363,9 +1372,9 @@ func TestListWatchComments_ExpiredVideo_Returns410(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(-1 * time.Hour)
 
-	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password FROM videos v WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\)`).
 		WithArgs(shareToken).
-		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil)))
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "anonymous", &expiresAt, (*string)(nil), "public"))
 
 	r := chi.NewRouter()
 	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
@@ -1399,11 +1408,11 @@ func TestListOwnerComments_ReturnsAllComments(t *testing.T) {
 		WithArgs(videoID, testUserID, (*string)(nil)).
 		WillReturnRows(pgxmock.NewRows([]string{"user_id", "comment_mode"}).AddRow(testUserID, "anonymous"))
 
-	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds FROM video_comments c WHERE c\.video_id = \$1 ORDER BY c\.created_at ASC`).
+	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name, c\.body, c\.is_private, c\.created_at, c\.video_timestamp_seconds, c\.parent_id, c\.edited_at, c\.resolved_at, c\.author_key, \(SELECT json_agg.* FROM video_comments c WHERE c\.video_id = \$1 AND c\.status = 'approved' ORDER BY c\.created_at ASC`).
 		WithArgs(videoID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds"}).
-			AddRow("c1", (*string)(nil), "Alex", "Public comment", false, now, (*float64)(nil)).
-			AddRow("c2", &commenterID, "Viewer", "Private note", true, now, (*float64)(nil)))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}).
+			AddRow("c1", (*string)(nil), "Alex", "Public comment", false, now, (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)).
+			AddRow("c2", &commenterID, "Viewer", "Private note", true, now, (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), nil, []byte(nil), []byte(nil)))
 
 	r := chi.NewRouter()
 	r.With(newAuthMiddleware()).Get("/api/videos/{id}/comments", handler.ListOwnerComments)
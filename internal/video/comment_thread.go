@@ -0,0 +1,451 @@
+package video
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/httputil"
+	"github.com/sendrec/sendrec/internal/validate"
+)
+
+// maxThreadNotifications caps how many earlier participants one reply can
+// email, so a long thread can't be used to fan out mail.
+const maxThreadNotifications = 20
+
+func commentAuthorCookieName(shareToken string) string {
+	prefix := shareToken
+	if len(prefix) > 8 {
+		prefix = prefix[:8]
+	}
+	return "ca_" + prefix
+}
+
+func signCommentAuthorCookie(hmacSecret, shareToken, authorKey string) string {
+	mac := hmac.New(sha256.New, deriveCookieKey(hmacSecret, "comment-author-cookie"))
+	mac.Write([]byte(shareToken + "|" + authorKey))
+	return authorKey + "|" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// commentAuthorKey returns the anonymous author key carried by a valid
+// comment-author cookie, or "" when there is none.
+func commentAuthorKey(r *http.Request, hmacSecret, shareToken string) string {
+	cookie, err := r.Cookie(commentAuthorCookieName(shareToken))
+	if err != nil {
+		return ""
+	}
+	key, _, found := strings.Cut(cookie.Value, "|")
+	if !found || key == "" {
+		return ""
+	}
+	if !hmac.Equal([]byte(signCommentAuthorCookie(hmacSecret, shareToken, key)), []byte(cookie.Value)) {
+		return ""
+	}
+	return key
+}
+
+// ensureCommentAuthorKey reuses the viewer's author key or issues a new one.
+func (h *Handler) ensureCommentAuthorKey(w http.ResponseWriter, r *http.Request, shareToken string) (string, error) {
+	if key := commentAuthorKey(r, h.hmacSecret, shareToken); key != "" {
+		return key, nil
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	key := hex.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     commentAuthorCookieName(shareToken),
+		Value:    signCommentAuthorCookie(h.hmacSecret, shareToken, key),
+		Path:     "/",
+		MaxAge:   int(365 * 24 * time.Hour / time.Second),
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteNoneMode,
+	})
+	return key, nil
+}
+
+// commentViewer identifies who is reading a comment list, to mark the
+// comments they may edit.
+type commentViewer struct {
+	userID    string
+	authorKey string
+}
+
+func (v commentViewer) wrote(userID, authorKey *string) bool {
+	if v.userID != "" && userID != nil && *userID == v.userID {
+		return true
+	}
+	return v.authorKey != "" && authorKey != nil && *authorKey == v.authorKey
+}
+
+// threadComments orders comments so each reply follows its thread's root,
+// keeping created_at order within a thread. Replies whose root is not in the
+// list (e.g. a hidden private comment) go last.
+func threadComments(comments []commentResponse) []commentResponse {
+	replies := make(map[string][]commentResponse)
+	var roots, orphans []commentResponse
+	present := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		if c.ParentID == nil {
+			present[c.ID] = true
+		}
+	}
+	for _, c := range comments {
+		switch {
+		case c.ParentID == nil:
+			roots = append(roots, c)
+		case present[*c.ParentID]:
+			replies[*c.ParentID] = append(replies[*c.ParentID], c)
+		default:
+			orphans = append(orphans, c)
+		}
+	}
+	ordered := make([]commentResponse, 0, len(comments))
+	for _, root := range roots {
+		root.ReplyCount = len(replies[root.ID])
+		ordered = append(ordered, root)
+		ordered = append(ordered, replies[root.ID]...)
+	}
+	return append(ordered, orphans...)
+}
+
+// resolveCommentParent validates a reply target and returns the thread root
+// the reply attaches to, plus whether that thread is private. Threads are one
+// level deep: replying to a reply joins the same thread.
+func (h *Handler) resolveCommentParent(ctx context.Context, videoID, parentID string) (rootID string, private bool, err error) {
+	var grandparent *string
+	err = h.db.QueryRow(ctx,
+		`SELECT parent_id, is_private FROM video_comments WHERE id = $1 AND video_id = $2 AND status = 'approved'`,
+		parentID, videoID,
+	).Scan(&grandparent, &private)
+	if err != nil {
+		return "", false, err
+	}
+	if grandparent != nil {
+		return *grandparent, private, nil
+	}
+	return parentID, private, nil
+}
+
+type threadParticipant struct {
+	email string
+	name  string
+}
+
+// threadParticipants lists the distinct people who have written in a thread
+// and can be emailed about it, excluding the new comment's author and anyone
+// in skipEmails, such as the video owner, who is notified separately. Only
+// signed-in users and addresses proven through the video's email gate
+// qualify, since a typed-in author email may belong to someone else, and
+// anyone who unsubscribed from the thread is left out.
+func (h *Handler) threadParticipants(ctx context.Context, rootID, commentID, authorUserID string, skipEmails []string) ([]threadParticipant, error) {
+	rows, err := h.db.Query(ctx,
+		`SELECT c.user_id, c.author_name, c.author_email, COALESCE(u.name, ''), COALESCE(u.email, '')
+		 FROM video_comments c
+		 LEFT JOIN users u ON u.id = c.user_id
+		 WHERE (c.id = $1 OR c.parent_id = $1) AND c.id <> $2 AND c.is_private = false AND c.status = 'approved'
+		   AND (u.id IS NOT NULL OR EXISTS (
+		       SELECT 1 FROM viewer_email_verifications ev
+		       WHERE ev.video_id = c.video_id AND lower(ev.email) = lower(c.author_email) AND ev.used_at IS NOT NULL))
+		   AND NOT EXISTS (
+		       SELECT 1 FROM comment_thread_unsubscribes tu
+		       WHERE tu.comment_id = $1 AND tu.email = lower(COALESCE(u.email, c.author_email)))
+		 ORDER BY c.created_at ASC`,
+		rootID, commentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool, len(skipEmails))
+	for _, addr := range skipEmails {
+		if addr != "" {
+			seen[strings.ToLower(addr)] = true
+		}
+	}
+	var participants []threadParticipant
+	for rows.Next() {
+		var userID *string
+		var authorName, commentEmail, userName, userEmail string
+		if err := rows.Scan(&userID, &authorName, &commentEmail, &userName, &userEmail); err != nil {
+			return nil, err
+		}
+		if userID != nil && *userID == authorUserID {
+			continue
+		}
+		addr, name := commentEmail, authorName
+		if userID != nil {
+			addr = userEmail
+			if name == "" {
+				name = userName
+			}
+		}
+		key := strings.ToLower(addr)
+		if addr == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		participants = append(participants, threadParticipant{email: addr, name: name})
+		if len(participants) == maxThreadNotifications {
+			break
+		}
+	}
+	return participants, rows.Err()
+}
+
+func (h *Handler) notifyThreadParticipants(ctx context.Context, shareToken, rootID, commentID, authorUserID string, skipEmails []string, videoTitle, authorName, body, watchURL string) {
+	participants, err := h.threadParticipants(ctx, rootID, commentID, authorUserID, skipEmails)
+	if err != nil {
+		slog.Error("comment: failed to load thread participants", "comment_id", commentID, "error", err)
+		return
+	}
+	for _, p := range participants {
+		unsubscribeURL := watchURL + "/comments/" + rootID + "/unsubscribe?token=" +
+			url.QueryEscape(signThreadUnsubscribeToken(h.hmacSecret, shareToken, rootID, p.email))
+		if err := h.threadReplyNotifier.SendThreadReplyNotification(ctx, p.email, p.name, videoTitle, authorName, body, watchURL, unsubscribeURL); err != nil {
+			slog.Error("comment: failed to notify thread participant", "comment_id", commentID, "error", err)
+		}
+	}
+}
+
+// signThreadUnsubscribeToken binds a recipient's address to one thread, so an
+// unsubscribe link can't be edited to silence someone else.
+func signThreadUnsubscribeToken(hmacSecret, shareToken, rootID, email string) string {
+	email = strings.ToLower(email)
+	mac := hmac.New(sha256.New, deriveCookieKey(hmacSecret, "thread-unsubscribe"))
+	mac.Write([]byte(shareToken + "|" + rootID + "|" + email))
+	return base64.RawURLEncoding.EncodeToString([]byte(email)) + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// threadUnsubscribeEmail returns the address a valid unsubscribe token was
+// issued to, or "" when the token doesn't match the thread.
+func threadUnsubscribeEmail(hmacSecret, shareToken, rootID, token string) string {
+	encoded, _, found := strings.Cut(token, ".")
+	if !found {
+		return ""
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(raw) == 0 {
+		return ""
+	}
+	email := string(raw)
+	if !hmac.Equal([]byte(signThreadUnsubscribeToken(hmacSecret, shareToken, rootID, email)), []byte(token)) {
+		return ""
+	}
+	return email
+}
+
+// ThreadUnsubscribePage shows the confirm button for a thread's unsubscribe
+// link. Nothing changes on GET, so mail scanners that follow links can't
+// unsubscribe anyone.
+func (h *Handler) ThreadUnsubscribePage(w http.ResponseWriter, r *http.Request) {
+	shareToken := chi.URLParam(r, "shareToken")
+	rootID := chi.URLParam(r, "commentId")
+	token := r.URL.Query().Get("token")
+
+	email := threadUnsubscribeEmail(h.hmacSecret, shareToken, rootID, token)
+	if email == "" {
+		h.renderInvalidUnsubscribeLink(w, r, shareToken)
+		return
+	}
+	renderRestrictedPage(w, http.StatusOK, restrictedPageData{
+		Nonce:      httputil.NonceFromContext(r.Context()),
+		Heading:    "Stop emails about this thread",
+		Message:    "You won't hear about new replies in this thread at " + email + ".",
+		LinkText:   "Unsubscribe",
+		FormAction: "/watch/" + shareToken + "/comments/" + rootID + "/unsubscribe",
+		FormToken:  token,
+	})
+}
+
+// UnsubscribeFromThread records that the link's recipient no longer wants
+// emails about new replies in the thread.
+func (h *Handler) UnsubscribeFromThread(w http.ResponseWriter, r *http.Request) {
+	shareToken := chi.URLParam(r, "shareToken")
+	rootID := chi.URLParam(r, "commentId")
+
+	email := threadUnsubscribeEmail(h.hmacSecret, shareToken, rootID, r.FormValue("token"))
+	if email == "" {
+		h.renderInvalidUnsubscribeLink(w, r, shareToken)
+		return
+	}
+	if _, err := h.db.Exec(r.Context(),
+		`INSERT INTO comment_thread_unsubscribes (comment_id, email)
+		 SELECT c.id, $3 FROM video_comments c
+		 JOIN videos v ON v.id = c.video_id
+		 WHERE c.id = $1 AND v.share_token = $2
+		 ON CONFLICT (comment_id, email) DO NOTHING`,
+		rootID, shareToken, email,
+	); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not unsubscribe")
+		return
+	}
+	renderRestrictedPage(w, http.StatusOK, restrictedPageData{
+		Nonce:    httputil.NonceFromContext(r.Context()),
+		Heading:  "You're unsubscribed",
+		Message:  "We won't email " + email + " about new replies in this thread.",
+		LinkURL:  h.publicBaseURL(r) + "/watch/" + shareToken,
+		LinkText: "Back to video",
+	})
+}
+
+func (h *Handler) renderInvalidUnsubscribeLink(w http.ResponseWriter, r *http.Request, shareToken string) {
+	renderRestrictedPage(w, http.StatusBadRequest, restrictedPageData{
+		Nonce:    httputil.NonceFromContext(r.Context()),
+		Heading:  "This link isn't valid",
+		Message:  "Use the unsubscribe link from the most recent email about this thread.",
+		LinkURL:  h.publicBaseURL(r) + "/watch/" + shareToken,
+		LinkText: "Back to video",
+	})
+}
+
+type editCommentRequest struct {
+	Body string `json:"body"`
+}
+
+func (h *Handler) EditWatchComment(w http.ResponseWriter, r *http.Request) {
+	shareToken := chi.URLParam(r, "shareToken")
+	commentID := chi.URLParam(r, "commentId")
+
+	videoID, ownerID, commentMode, ok := h.lookupWatchVideo(w, r)
+	if !ok {
+		return
+	}
+	if commentMode == "disabled" {
+		httputil.WriteError(w, http.StatusForbidden, "comments are disabled")
+		return
+	}
+
+	var req editCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "comment body is required")
+		return
+	}
+	if msg := validate.CommentBody(req.Body); msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	var userIDArg, authorKeyArg *string
+	if callerUserID := h.optionalUserID(r); callerUserID != "" {
+		userIDArg = &callerUserID
+	}
+	if key := commentAuthorKey(r, h.hmacSecret, shareToken); key != "" {
+		authorKeyArg = &key
+	}
+	if userIDArg == nil && authorKeyArg == nil {
+		httputil.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+
+	// An edit is a new chance to slip spam past review, so everyone but the
+	// owner goes back through moderation.
+	moderation := commentModeration{status: commentApproved}
+	if userIDArg == nil || *userIDArg != ownerID {
+		moderation = h.moderateComment(r.Context(), commentMode, SpamCandidate{
+			Body:          req.Body,
+			Authenticated: userIDArg != nil,
+		})
+	}
+
+	var c commentResponse
+	var userID *string
+	var createdAt, editedAt time.Time
+	var resolvedAt *time.Time
+	err := h.db.QueryRow(r.Context(),
+		`UPDATE video_comments SET body = $1, edited_at = now(),
+		   status = CASE WHEN $6 AND NOT is_private AND status = 'approved' THEN 'pending' ELSE status END,
+		   spam_score = COALESCE($7, spam_score)
+		 WHERE id = $2 AND video_id = $3
+		   AND ((user_id IS NOT NULL AND user_id = $4) OR (author_key IS NOT NULL AND author_key = $5))
+		 RETURNING id, user_id, author_name, is_private, created_at, video_timestamp_seconds, parent_id, edited_at, resolved_at, status`,
+		req.Body, commentID, videoID, userIDArg, authorKeyArg, moderation.status == commentPending, moderation.spamScore,
+	).Scan(&c.ID, &userID, &c.AuthorName, &c.IsPrivate, &createdAt, &c.VideoTimestamp, &c.ParentID, &editedAt, &resolvedAt, &c.Status)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+
+	// Mentions follow the edited body. Only members the edit newly mentions
+	// are notified; a held edit notifies them once it is approved.
+	if userIDArg != nil && userID != nil && *userID == *userIDArg && !c.IsPrivate {
+		var mentioned, added []mentionedMember
+		if handles := parseMentionHandles(req.Body); len(handles) > 0 && !isQuickReactionBody(req.Body) {
+			mentioned, err = h.resolveMentions(r.Context(), videoID, *userIDArg, handles)
+		}
+		if err == nil {
+			added, err = h.syncMentions(r.Context(), c.ID, mentioned)
+		}
+		if err != nil {
+			slog.Error("comment: failed to record mentions", "comment_id", c.ID, "error", err)
+		} else {
+			c.Mentions = mentionsOf(mentioned)
+			if c.Status == commentApproved && len(added) > 0 {
+				go h.announceEditMentions(videoID, shareToken, c.ID, c.AuthorName, req.Body, c.VideoTimestamp, added)
+			}
+		}
+	}
+
+	edited := editedAt.Format(time.RFC3339)
+	c.Body = req.Body
+	c.IsOwner = userID != nil && *userID == ownerID
+	c.CreatedAt = createdAt.Format(time.RFC3339)
+	c.EditedAt = &edited
+	c.Resolved = resolvedAt != nil
+	c.CanEdit = true
+	httputil.WriteJSON(w, http.StatusOK, c)
+}
+
+type setCommentResolvedRequest struct {
+	Resolved bool `json:"resolved"`
+}
+
+func (h *Handler) SetCommentResolved(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	videoID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentId")
+
+	var req setCommentResolvedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	tag, err := h.db.Exec(r.Context(),
+		`UPDATE video_comments c
+		 SET resolved_at = CASE WHEN $1 THEN COALESCE(c.resolved_at, now()) ELSE NULL END
+		 FROM videos v
+		 WHERE c.id = $2 AND c.video_id = $3 AND c.parent_id IS NULL
+		   AND v.id = c.video_id AND v.user_id = $4 AND v.organization_id IS NOT DISTINCT FROM $5`,
+		req.Resolved, commentID, videoID, userID, orgScope(r.Context()),
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not update comment")
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
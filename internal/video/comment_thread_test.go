@@ -0,0 +1,467 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+type recordingCommentNotifier struct {
+	mu           sync.Mutex
+	recipients   []string
+	links        map[string]string
+	unsubscribes map[string]string
+}
+
+func (m *recordingCommentNotifier) SendCommentNotification(_ context.Context, toEmail, _, _, _, _, watchURL string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recipients = append(m.recipients, toEmail)
+	if m.links == nil {
+		m.links = make(map[string]string)
+	}
+	m.links[toEmail] = watchURL
+	return nil
+}
+
+func (m *recordingCommentNotifier) SendThreadReplyNotification(ctx context.Context, toEmail, toName, videoTitle, commentAuthor, commentBody, watchURL, unsubscribeURL string) error {
+	m.mu.Lock()
+	if m.unsubscribes == nil {
+		m.unsubscribes = make(map[string]string)
+	}
+	m.unsubscribes[toEmail] = unsubscribeURL
+	m.mu.Unlock()
+	return m.SendCommentNotification(ctx, toEmail, toName, videoTitle, commentAuthor, commentBody, watchURL)
+}
+
+func (m *recordingCommentNotifier) unsubscribeFor(toEmail string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.unsubscribes[toEmail]
+}
+
+func (m *recordingCommentNotifier) linkFor(toEmail string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.links[toEmail]
+}
+
+func (m *recordingCommentNotifier) sent() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := append([]string(nil), m.recipients...)
+	sort.Strings(out)
+	return out
+}
+
+func TestThreadComments(t *testing.T) {
+	root1, root2, hidden := "r1", "r2", "private-root"
+	comments := []commentResponse{
+		{ID: "r1"},
+		{ID: "r2"},
+		{ID: "a", ParentID: &root1},
+		{ID: "b", ParentID: &hidden},
+		{ID: "c", ParentID: &root2},
+		{ID: "d", ParentID: &root1},
+	}
+
+	got := threadComments(comments)
+
+	var ids []string
+	for _, c := range got {
+		ids = append(ids, c.ID)
+	}
+	if strings.Join(ids, ",") != "r1,a,d,r2,c,b" {
+		t.Errorf("unexpected order %v", ids)
+	}
+	if got[0].ReplyCount != 2 || got[3].ReplyCount != 1 {
+		t.Errorf("unexpected reply counts: %d, %d", got[0].ReplyCount, got[3].ReplyCount)
+	}
+}
+
+func TestCommentAuthorCookie_RoundTrip(t *testing.T) {
+	handler := NewHandler(nil, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	rec := httptest.NewRecorder()
+	key, err := handler.ensureCommentAuthorKey(rec, httptest.NewRequest(http.MethodPost, "/", nil), "abc123defghi")
+	if err != nil || key == "" {
+		t.Fatalf("expected a key, got %q (%v)", key, err)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("expected one HttpOnly cookie, got %+v", cookies)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	if got := commentAuthorKey(req, testJWTSecret, "abc123defghi"); got != key {
+		t.Errorf("expected key %q, got %q", key, got)
+	}
+	if got := commentAuthorKey(req, testJWTSecret, "otherToken12"); got != "" {
+		t.Errorf("cookie must not verify for another video, got %q", got)
+	}
+
+	tampered := httptest.NewRequest(http.MethodGet, "/", nil)
+	tampered.AddCookie(&http.Cookie{Name: cookies[0].Name, Value: "forged|" + strings.SplitN(cookies[0].Value, "|", 2)[1]})
+	if got := commentAuthorKey(tampered, testJWTSecret, "abc123defghi"); got != "" {
+		t.Errorf("tampered cookie accepted: %q", got)
+	}
+}
+
+func servePostComment(handler *Handler, shareToken string, body any) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/comments", handler.PostWatchComment)
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/"+shareToken+"/comments", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPostWatchComment_ReplyJoinsRootAndNotifiesThread(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	notifier := &recordingCommentNotifier{}
+	handler.SetCommentNotifier(notifier)
+	handler.SetThreadReplyNotifier(notifier)
+
+	shareToken := "abc123defghi"
+	videoID := "video-123"
+	ownerID := "owner-user-1"
+	participantID := "user-jo"
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs(shareToken).
+		WillReturnRows(commentVideoRows().AddRow(videoID, ownerID, "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT parent_id, is_private FROM video_comments WHERE id = \$1 AND video_id = \$2`).
+		WithArgs("c2", videoID).
+		WillReturnRows(pgxmock.NewRows([]string{"parent_id", "is_private"}).AddRow(strPtr("c1"), false))
+	expectNoCommentBan(mock)
+	mock.ExpectQuery(`INSERT INTO video_comments`).
+		WithArgs(videoID, (*string)(nil), "Sam", "sam@example.com", "Thanks!", false, (*float64)(nil), pgxmock.AnyArg(), pgxmock.AnyArg(), "approved", pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("c3", time.Now()))
+	mock.ExpectQuery(`SELECT view_notification FROM notification_preferences WHERE user_id = \$1`).
+		WithArgs(ownerID).
+		WillReturnRows(pgxmock.NewRows([]string{"view_notification"}).AddRow("off"))
+	mock.ExpectQuery(`SELECT u\.email, u\.name, v\.title FROM users u JOIN videos v`).
+		WithArgs(videoID).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "name", "title"}).AddRow("owner@example.com", "Owner", "Demo"))
+	mock.ExpectQuery(`SELECT c\.user_id, c\.author_name, c\.author_email, COALESCE\(u\.name, ''\), COALESCE\(u\.email, ''\)(.|\n)*`+
+		`u\.id IS NOT NULL OR EXISTS \((.|\n)*ev\.used_at IS NOT NULL(.|\n)*NOT EXISTS \((.|\n)*comment_thread_unsubscribes`).
+		WithArgs("c1", "c3").
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "author_name", "author_email", "name", "email"}).
+			AddRow((*string)(nil), "Alex", "alex@example.com", "", "").
+			AddRow(&participantID, "", "", "Jo", "jo@example.com").
+			AddRow((*string)(nil), "Sam", "SAM@example.com", "", "").
+			AddRow((*string)(nil), "Alex again", "alex@example.com", "", "").
+			AddRow(strPtr(ownerID), "", "", "Owner", "owner@example.com"))
+
+	rec := servePostComment(handler, shareToken, map[string]any{
+		"authorName": "Sam", "authorEmail": "sam@example.com", "body": "Thanks!", "parentId": "c2",
+	})
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp commentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ParentID == nil || *resp.ParentID != "c1" || !resp.CanEdit {
+		t.Errorf("expected editable reply attached to c1, got %+v", resp)
+	}
+	if len(rec.Result().Cookies()) != 1 {
+		t.Errorf("expected an anonymous author cookie to be issued")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := notifier.sent(); strings.Join(got, ",") != "alex@example.com,jo@example.com" {
+		t.Errorf("unexpected thread recipients %v", got)
+	}
+	wantUnsubscribe := testBaseURL + "/watch/" + shareToken + "/comments/c1/unsubscribe?token=" +
+		signThreadUnsubscribeToken(testJWTSecret, shareToken, "c1", "alex@example.com")
+	if got := notifier.unsubscribeFor("alex@example.com"); got != wantUnsubscribe {
+		t.Errorf("expected unsubscribe link %q, got %q", wantUnsubscribe, got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostWatchComment_ReplyToPrivateCommentHiddenFromViewers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT parent_id, is_private FROM video_comments`).
+		WithArgs("secret", "video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"parent_id", "is_private"}).AddRow((*string)(nil), true))
+
+	rec := servePostComment(handler, "abc123defghi", map[string]any{"body": "Hi", "parentId": "secret"})
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func serveEditComment(handler *Handler, cookie *http.Cookie, body string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Patch("/api/watch/{shareToken}/comments/{commentId}", handler.EditWatchComment)
+	req := httptest.NewRequest(http.MethodPatch, "/api/watch/abc123defghi/comments/c1", strings.NewReader(body))
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEditWatchComment_AnonymousAuthor(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	cookie := &http.Cookie{
+		Name:  commentAuthorCookieName("abc123defghi"),
+		Value: signCommentAuthorCookie(testJWTSecret, "abc123defghi", "author-key-1"),
+	}
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`UPDATE video_comments SET body = \$1, edited_at = now\(\)`).
+		WithArgs("Fixed typo", "c1", "video-123", (*string)(nil), strPtr("author-key-1"), false, (*float64)(nil)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "status"}).
+			AddRow("c1", (*string)(nil), "Sam", false, now, (*float64)(nil), (*string)(nil), now, (*time.Time)(nil), "approved"))
+
+	rec := serveEditComment(handler, cookie, `{"body":"  Fixed typo "}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp commentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "Fixed typo" || resp.EditedAt == nil {
+		t.Errorf("expected edited comment, got %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestEditWatchComment_NoAuthorIdentity(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+
+	rec := serveEditComment(handler, nil, `{"body":"Hijack"}`)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestListWatchComments_MarksViewerEditableComments(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT c\.id, c\.user_id, c\.author_name`).
+		WithArgs("video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}).
+			AddRow("c1", (*string)(nil), "Sam", "Question", false, now, (*float64)(nil), (*string)(nil), &now, &now, strPtr("mine"), nil, []byte(nil), []byte(nil)).
+			AddRow("c2", (*string)(nil), "Alex", "Answer", false, now, (*float64)(nil), strPtr("c1"), (*time.Time)(nil), (*time.Time)(nil), strPtr("theirs"), nil, []byte(nil), []byte(nil)))
+
+	r := chi.NewRouter()
+	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
+	req := httptest.NewRequest(http.MethodGet, "/api/watch/abc123defghi/comments", nil)
+	req.AddCookie(&http.Cookie{
+		Name:  commentAuthorCookieName("abc123defghi"),
+		Value: signCommentAuthorCookie(testJWTSecret, "abc123defghi", "mine"),
+	})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	var resp listCommentsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(resp.Comments))
+	}
+	first, second := resp.Comments[0], resp.Comments[1]
+	if !first.CanEdit || !first.Resolved || first.EditedAt == nil || first.ReplyCount != 1 {
+		t.Errorf("unexpected root comment %+v", first)
+	}
+	if second.CanEdit || second.ParentID == nil {
+		t.Errorf("unexpected reply %+v", second)
+	}
+}
+
+func TestSetCommentResolved(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectExec(`UPDATE video_comments c\s+SET resolved_at = CASE WHEN \$1 THEN COALESCE\(c\.resolved_at, now\(\)\) ELSE NULL END`).
+		WithArgs(true, "c1", "video-123", testUserID, (*string)(nil)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`UPDATE video_comments c`).
+		WithArgs(false, "reply-1", "video-123", testUserID, (*string)(nil)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/comments/{commentId}/resolved", handler.SetCommentResolved)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/video-123/comments/c1/resolved", []byte(`{"resolved":true}`)))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/video-123/comments/reply-1/resolved", []byte(`{"resolved":false}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a reply, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestThreadUnsubscribePage_ShowsConfirmWithoutChangingAnything(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	token := signThreadUnsubscribeToken(testHMACSecret, "abc123defghi", "c1", "Alex@Example.com")
+
+	r := chi.NewRouter()
+	r.Get("/watch/{shareToken}/comments/{commentId}/unsubscribe", handler.ThreadUnsubscribePage)
+	req := httptest.NewRequest(http.MethodGet, "/watch/abc123defghi/comments/c1/unsubscribe?token="+token, nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `action="/watch/abc123defghi/comments/c1/unsubscribe"`) || !strings.Contains(body, "alex@example.com") {
+		t.Errorf("expected confirm form for alex@example.com, got %s", body)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestUnsubscribeFromThread_RecordsRecipient(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	token := signThreadUnsubscribeToken(testHMACSecret, "abc123defghi", "c1", "alex@example.com")
+
+	mock.ExpectExec(`INSERT INTO comment_thread_unsubscribes \(comment_id, email\)(.|\n)*ON CONFLICT \(comment_id, email\) DO NOTHING`).
+		WithArgs("c1", "abc123defghi", "alex@example.com").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	r := chi.NewRouter()
+	r.Post("/watch/{shareToken}/comments/{commentId}/unsubscribe", handler.UnsubscribeFromThread)
+	req := httptest.NewRequest(http.MethodPost, "/watch/abc123defghi/comments/c1/unsubscribe", strings.NewReader("token="+token))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestUnsubscribeFromThread_RejectsTokenForAnotherThread(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	token := signThreadUnsubscribeToken(testHMACSecret, "abc123defghi", "c1", "alex@example.com")
+
+	r := chi.NewRouter()
+	r.Post("/watch/{shareToken}/comments/{commentId}/unsubscribe", handler.UnsubscribeFromThread)
+	req := httptest.NewRequest(http.MethodPost, "/watch/abc123defghi/comments/c2/unsubscribe", strings.NewReader("token="+token))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
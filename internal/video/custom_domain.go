@@ -0,0 +1,74 @@
+package video
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+// publicBaseURL is the origin the viewer reached us on: the organization's
+// custom domain when the request arrived on one, BASE_URL otherwise. Links on
+// public pages use it so viewers stay on the branded host.
+func (h *Handler) publicBaseURL(r *http.Request) string {
+	if d, ok := httputil.CustomDomainFromContext(r.Context()); ok {
+		return d.BaseURL
+	}
+	return h.baseURL
+}
+
+func customDomainBaseURL(baseURL, hostname string) string {
+	if strings.HasPrefix(baseURL, "http://") {
+		return "http://" + hostname
+	}
+	return "https://" + hostname
+}
+
+// orgShareBaseURL returns the base URL for share links of an organization's
+// videos, preferring its verified custom domain.
+func (h *Handler) orgShareBaseURL(ctx context.Context, orgID string) string {
+	if orgID == "" {
+		return h.baseURL
+	}
+	var hostname string
+	if err := h.db.QueryRow(ctx,
+		`SELECT hostname FROM organization_domains WHERE organization_id = $1 AND verified_at IS NOT NULL`,
+		orgID,
+	).Scan(&hostname); err != nil {
+		return h.baseURL
+	}
+	return customDomainBaseURL(h.baseURL, hostname)
+}
+
+// shareWatchURL builds the watch link for a video, on its organization's
+// custom domain when one is verified.
+func (h *Handler) shareWatchURL(ctx context.Context, shareToken string) string {
+	var hostname string
+	if err := h.db.QueryRow(ctx,
+		`SELECT od.hostname FROM videos v
+		 JOIN organization_domains od ON od.organization_id = v.organization_id
+		 WHERE v.share_token = $1 AND od.verified_at IS NOT NULL`,
+		shareToken,
+	).Scan(&hostname); err != nil {
+		return h.baseURL + "/watch/" + shareToken
+	}
+	return customDomainBaseURL(h.baseURL, hostname) + "/watch/" + shareToken
+}
+
+// playlistShareBaseURL returns the base URL for a playlist's links. Playlists
+// reach a workspace's custom domain through the slug set there, so that slug's
+// organization decides the host.
+func (h *Handler) playlistShareBaseURL(ctx context.Context, playlistID string) string {
+	var hostname string
+	if err := h.db.QueryRow(ctx,
+		`SELECT od.hostname FROM playlist_slugs ps
+		 JOIN organization_domains od ON od.organization_id = ps.organization_id
+		 WHERE ps.playlist_id = $1 AND od.verified_at IS NOT NULL
+		 LIMIT 1`,
+		playlistID,
+	).Scan(&hostname); err != nil {
+		return h.baseURL
+	}
+	return customDomainBaseURL(h.baseURL, hostname)
+}
@@ -0,0 +1,98 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+func TestPublicBaseURL(t *testing.T) {
+	handler := NewHandler(nil, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/watch/abc", nil)
+	if got := handler.publicBaseURL(req); got != testBaseURL {
+		t.Errorf("expected %s, got %s", testBaseURL, got)
+	}
+
+	ctx := httputil.ContextWithCustomDomain(req.Context(), httputil.CustomDomain{OrganizationID: "org-1", BaseURL: "https://video.theirbrand.com"})
+	if got := handler.publicBaseURL(req.WithContext(ctx)); got != "https://video.theirbrand.com" {
+		t.Errorf("expected custom domain, got %s", got)
+	}
+}
+
+func TestShareWatchURL(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT od.hostname FROM videos v`).
+		WithArgs("branded123").
+		WillReturnRows(pgxmock.NewRows([]string{"hostname"}).AddRow("video.theirbrand.com"))
+	mock.ExpectQuery(`SELECT od.hostname FROM videos v`).
+		WithArgs("plain123").
+		WillReturnRows(pgxmock.NewRows([]string{"hostname"}))
+
+	if got := handler.shareWatchURL(context.Background(), "branded123"); got != "https://video.theirbrand.com/watch/branded123" {
+		t.Errorf("unexpected branded URL %s", got)
+	}
+	if got := handler.shareWatchURL(context.Background(), "plain123"); got != testBaseURL+"/watch/plain123" {
+		t.Errorf("unexpected fallback URL %s", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestList_OrgShareURLUsesCustomDomain(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	createdAt := time.Date(2026, 2, 5, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT hostname FROM organization_domains WHERE organization_id = \$1 AND verified_at IS NOT NULL`).
+		WithArgs(testOrgID).
+		WillReturnRows(pgxmock.NewRows([]string{"hostname"}).AddRow("video.theirbrand.com"))
+	mock.ExpectQuery(`SELECT v.id, v.title, v.status, v.duration, v.share_token, v.created_at, v.share_expires_at`).
+		WithArgs(testOrgID, 50, 0).
+		WillReturnRows(
+			pgxmock.NewRows([]string{"id", "title", "status", "duration", "share_token", "created_at", "share_expires_at", "view_count", "unique_view_count", "thumbnail_key", "share_password", "comment_mode", "comment_count", "transcript_status", "view_notification", "download_enabled", "cta_text", "cta_url", "email_gate_enabled", "summary_status", "document_status", "suggested_title", "folder_id", "transcription_language", "noise_reduction", "pinned", "tags_json", "playlists_json", "video_replies_enabled"}).
+				AddRow("video-1", "My Video", "ready", 90, "abc123defghi", createdAt, (*time.Time)(nil), int64(0), int64(0), (*string)(nil), (*string)(nil), "disabled", int64(0), "none", (*string)(nil), true, (*string)(nil), (*string)(nil), false, "none", "none", (*string)(nil), (*string)(nil), (*string)(nil), false, false, "[]", "[]", false),
+		)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos", handler.List)
+
+	rec := httptest.NewRecorder()
+	req := authenticatedRequest(t, http.MethodGet, "/api/videos", nil)
+	r.ServeHTTP(rec, req.WithContext(auth.ContextWithOrg(req.Context(), testOrgID, "member")))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var items []listItem
+	if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(items) != 1 || items[0].ShareURL != "https://video.theirbrand.com/watch/abc123defghi" {
+		t.Errorf("expected custom domain share URL, got %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -30,7 +30,7 @@ func processDigest(ctx context.Context, db database.DBTX, notifier ViewNotifier,
 		`WITH recent_views AS (
 		     SELECT video_id, COUNT(*) AS view_count
 		     FROM video_views
-		     WHERE created_at >= NOW() - INTERVAL '24 hours'
+		     WHERE created_at >= NOW() - INTERVAL '24 hours' AND NOT is_bot
 		     GROUP BY video_id
 		 ),
 		 recent_comments AS (
@@ -38,6 +38,7 @@ func processDigest(ctx context.Context, db database.DBTX, notifier ViewNotifier,
 		     FROM video_comments
 		     WHERE created_at >= NOW() - INTERVAL '24 hours'
 		       AND is_private = false
+		       AND status = 'approved'
 		     GROUP BY video_id
 		 )
 		 SELECT v.id, v.title, v.share_token, v.user_id, u.email, u.name,
@@ -45,9 +46,11 @@ func processDigest(ctx context.Context, db database.DBTX, notifier ViewNotifier,
 		          WHEN COALESCE(v.view_notification, 'digest') = 'digest' THEN COALESCE(rv.view_count, 0)
 		          ELSE 0
 		        END AS view_count,
-		        COALESCE(rc.comment_count, 0) AS comment_count
+		        COALESCE(rc.comment_count, 0) AS comment_count,
+		        od.hostname
 		 FROM videos v
 		 JOIN users u ON u.id = v.user_id
+		 LEFT JOIN organization_domains od ON od.organization_id = v.organization_id AND od.verified_at IS NOT NULL
 		 LEFT JOIN notification_preferences np ON np.user_id = v.user_id
 		 LEFT JOIN recent_views rv ON rv.video_id = v.id
 		 LEFT JOIN recent_comments rc ON rc.video_id = v.id
@@ -71,7 +74,8 @@ func processDigest(ctx context.Context, db database.DBTX, notifier ViewNotifier,
 	for rows.Next() {
 		var videoID, title, shareToken, userID, ownerEmail, name string
 		var viewCount, commentCount int64
-		if err := rows.Scan(&videoID, &title, &shareToken, &userID, &ownerEmail, &name, &viewCount, &commentCount); err != nil {
+		var customHostname *string
+		if err := rows.Scan(&videoID, &title, &shareToken, &userID, &ownerEmail, &name, &viewCount, &commentCount, &customHostname); err != nil {
 			slog.Error("digest-worker: scan failed", "error", err)
 			continue
 		}
@@ -80,11 +84,15 @@ func processDigest(ctx context.Context, db database.DBTX, notifier ViewNotifier,
 			d = &userDigest{email: ownerEmail, name: name}
 			digests[userID] = d
 		}
+		watchBaseURL := baseURL
+		if customHostname != nil {
+			watchBaseURL = customDomainBaseURL(baseURL, *customHostname)
+		}
 		d.videos = append(d.videos, email.DigestVideoSummary{
 			Title:        title,
 			ViewCount:    int(viewCount),
 			CommentCount: int(commentCount),
-			WatchURL:     baseURL + "/watch/" + shareToken,
+			WatchURL:     watchBaseURL + "/watch/" + shareToken,
 		})
 	}
 	if err := rows.Err(); err != nil {
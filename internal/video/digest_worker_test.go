@@ -20,10 +20,13 @@ func TestProcessDigest_SendsSingleEmailPerUser(t *testing.T) {
 	mock.ExpectQuery(`SELECT v\.id, v\.title, v\.share_token, v\.user_id, u\.email, u\.name`).
 		WillReturnRows(pgxmock.NewRows([]string{
 			"id", "title", "share_token", "user_id", "email", "name", "view_count", "comment_count",
+			"hostname",
 		}).AddRow(
 			"vid-1", "Video One", "tok-1", "user-1", "alice@example.com", "Alice", int64(10), int64(2),
+			(*string)(nil),
 		).AddRow(
 			"vid-2", "Video Two", "tok-2", "user-1", "alice@example.com", "Alice", int64(3), int64(0),
+			(*string)(nil),
 		))
 
 	processDigest(context.Background(), mock, notifier, "https://app.sendrec.eu")
@@ -76,12 +79,16 @@ func TestProcessDigest_MultipleUsers(t *testing.T) {
 	mock.ExpectQuery(`SELECT v\.id, v\.title, v\.share_token, v\.user_id, u\.email, u\.name`).
 		WillReturnRows(pgxmock.NewRows([]string{
 			"id", "title", "share_token", "user_id", "email", "name", "view_count", "comment_count",
+			"hostname",
 		}).AddRow(
 			"vid-1", "Video One", "tok-1", "user-1", "alice@example.com", "Alice", int64(5), int64(1),
+			(*string)(nil),
 		).AddRow(
 			"vid-2", "Video Two", "tok-2", "user-2", "bob@example.com", "Bob", int64(8), int64(0),
+			(*string)(nil),
 		).AddRow(
 			"vid-3", "Video Three", "tok-3", "user-1", "alice@example.com", "Alice", int64(2), int64(3),
+			(*string)(nil),
 		))
 
 	processDigest(context.Background(), mock, notifier, "https://app.sendrec.eu")
@@ -103,6 +110,7 @@ func TestProcessDigest_NoActivityNoEmail(t *testing.T) {
 	mock.ExpectQuery(`SELECT v\.id, v\.title, v\.share_token, v\.user_id, u\.email, u\.name`).
 		WillReturnRows(pgxmock.NewRows([]string{
 			"id", "title", "share_token", "user_id", "email", "name", "view_count", "comment_count",
+			"hostname",
 		}))
 
 	processDigest(context.Background(), mock, notifier, "https://app.sendrec.eu")
@@ -124,8 +132,10 @@ func TestProcessDigest_CommentsOnlyStillSendsDigest(t *testing.T) {
 	mock.ExpectQuery(`SELECT v\.id, v\.title, v\.share_token, v\.user_id, u\.email, u\.name`).
 		WillReturnRows(pgxmock.NewRows([]string{
 			"id", "title", "share_token", "user_id", "email", "name", "view_count", "comment_count",
+			"hostname",
 		}).AddRow(
 			"vid-1", "Video One", "tok-1", "user-1", "alice@example.com", "Alice", int64(0), int64(4),
+			(*string)(nil),
 		))
 
 	processDigest(context.Background(), mock, notifier, "https://app.sendrec.eu")
@@ -0,0 +1,324 @@
+package video
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	viewerVerificationExpiry      = 15 * time.Minute
+	maxViewerVerificationAttempts = 5
+	// maxViewerVerificationsPerWindow caps how many codes one address can be
+	// sent for a video per expiry window, so the gate can't be used to spam.
+	maxViewerVerificationsPerWindow = 3
+	maxEmailGateDomains             = 50
+)
+
+// emailDomain returns the lowercased part after the last "@".
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// normalizeEmailGateDomains lowercases, strips a leading "@" and de-duplicates
+// the owner's allowed domain list. It returns an error message for entries
+// that are not plausible domain names.
+func normalizeEmailGateDomains(domains []string) ([]string, string) {
+	seen := make(map[string]bool, len(domains))
+	result := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "@")
+		if d == "" {
+			continue
+		}
+		if len(d) > 253 || !strings.Contains(d, ".") || strings.ContainsAny(d, "@ /\\") {
+			return nil, "invalid domain: " + d
+		}
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		result = append(result, d)
+	}
+	if len(result) > maxEmailGateDomains {
+		return nil, "too many allowed domains"
+	}
+	return result, ""
+}
+
+func generateViewerCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func generateViewerLinkToken() (raw string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	raw = base64.RawURLEncoding.EncodeToString(b)
+	return raw, hashViewerLinkToken(raw), nil
+}
+
+func hashViewerLinkToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashViewerCode binds a six-digit code to its verification row. Keying the
+// MAC keeps a leaked table from being brute-forced offline in a million tries.
+func hashViewerCode(hmacSecret, tokenHash, code string) string {
+	mac := hmac.New(sha256.New, deriveCookieKey(hmacSecret, "viewer-email-code"))
+	mac.Write([]byte(tokenHash + "|" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type viewerVerificationResponse struct {
+	VerificationRequired bool `json:"verificationRequired"`
+}
+
+// startViewerVerification issues a code and magic link for a verified email
+// gate. The gate cookie is only set once the viewer proves they received it,
+// so lead-form answers wait on the verification row until then.
+func (h *Handler) startViewerVerification(w http.ResponseWriter, r *http.Request, videoID, title, shareToken, email string, responses map[string]any) {
+	if h.viewerVerifier == nil {
+		httputil.WriteError(w, http.StatusServiceUnavailable, "email verification is not available")
+		return
+	}
+
+	var recent int
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT COUNT(*) FROM viewer_email_verifications
+		 WHERE video_id = $1 AND email = $2 AND created_at > $3`,
+		videoID, email, time.Now().Add(-viewerVerificationExpiry),
+	).Scan(&recent); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not send verification email")
+		return
+	}
+	if recent >= maxViewerVerificationsPerWindow {
+		httputil.WriteError(w, http.StatusTooManyRequests, "too many verification emails, try again later")
+		return
+	}
+
+	code, err := generateViewerCode()
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not send verification email")
+		return
+	}
+	rawToken, tokenHash, err := generateViewerLinkToken()
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not send verification email")
+		return
+	}
+
+	if responses == nil {
+		responses = map[string]any{}
+	}
+	responsesJSON, _ := json.Marshal(responses)
+	if _, err := h.db.Exec(r.Context(),
+		`INSERT INTO viewer_email_verifications (token_hash, video_id, email, code_hash, expires_at, form_responses)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		tokenHash, videoID, email, hashViewerCode(h.hmacSecret, tokenHash, code), time.Now().Add(viewerVerificationExpiry),
+		string(responsesJSON),
+	); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not send verification email")
+		return
+	}
+
+	verifyLink := h.publicBaseURL(r) + "/watch/" + shareToken + "/verify-email?token=" + url.QueryEscape(rawToken)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := h.viewerVerifier.SendViewerVerification(ctx, email, title, code, verifyLink); err != nil {
+			slog.Error("email-gate: failed to send viewer verification", "video_id", videoID, "error", err)
+		}
+	}()
+
+	httputil.WriteJSON(w, http.StatusAccepted, viewerVerificationResponse{VerificationRequired: true})
+}
+
+// consumeViewerVerification marks a verification used. It reports false when
+// another request consumed it first.
+func (h *Handler) consumeViewerVerification(ctx context.Context, tokenHash string) bool {
+	tag, err := h.db.Exec(ctx,
+		`UPDATE viewer_email_verifications SET used_at = now() WHERE token_hash = $1 AND used_at IS NULL`,
+		tokenHash,
+	)
+	return err == nil && tag.RowsAffected() == 1
+}
+
+type verifyViewerEmailRequest struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+func (h *Handler) VerifyViewerEmail(w http.ResponseWriter, r *http.Request) {
+	shareToken := chi.URLParam(r, "shareToken")
+
+	var req verifyViewerEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	code := strings.TrimSpace(req.Code)
+	if email == "" || code == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "email and code are required")
+		return
+	}
+
+	var tokenHash, codeHash, videoID, responsesJSON string
+	var attempts int
+	err := h.db.QueryRow(r.Context(),
+		`SELECT ev.token_hash, ev.code_hash, ev.attempts, v.id, ev.form_responses
+		 FROM viewer_email_verifications ev
+		 JOIN videos v ON v.id = ev.video_id
+		 WHERE v.share_token = $1 AND ev.email = $2 AND ev.used_at IS NULL AND ev.expires_at > now()
+		 ORDER BY ev.created_at DESC LIMIT 1`,
+		shareToken, email,
+	).Scan(&tokenHash, &codeHash, &attempts, &videoID, &responsesJSON)
+	if err != nil || attempts >= maxViewerVerificationAttempts {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid or expired code")
+		return
+	}
+
+	if !hmac.Equal([]byte(codeHash), []byte(hashViewerCode(h.hmacSecret, tokenHash, code))) {
+		if _, err := h.db.Exec(r.Context(),
+			`UPDATE viewer_email_verifications SET attempts = attempts + 1 WHERE token_hash = $1`,
+			tokenHash,
+		); err != nil {
+			slog.Error("email-gate: failed to count verification attempt", "video_id", videoID, "error", err)
+		}
+		httputil.WriteError(w, http.StatusBadRequest, "invalid or expired code")
+		return
+	}
+
+	if !h.consumeViewerVerification(r.Context(), tokenHash) {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid or expired code")
+		return
+	}
+
+	h.recordViewerIdentityAsync(r, videoID, email, decodeFormResponses(responsesJSON))
+	setEmailGateCookie(w, shareToken, signEmailGateCookie(h.hmacSecret, shareToken, email, true), h.secureCookies)
+	w.WriteHeader(http.StatusOK)
+}
+
+// VerifyViewerEmailLink is the magic link in the verification email. Mail
+// scanners prefetch links, so the GET only asks the viewer to confirm; the
+// verification is consumed by ConfirmViewerEmailLink when they do.
+func (h *Handler) VerifyViewerEmailLink(w http.ResponseWriter, r *http.Request) {
+	shareToken := chi.URLParam(r, "shareToken")
+	rawToken := r.URL.Query().Get("token")
+
+	var email string
+	err := h.db.QueryRow(r.Context(),
+		`SELECT ev.email
+		 FROM viewer_email_verifications ev
+		 JOIN videos v ON v.id = ev.video_id
+		 WHERE ev.token_hash = $1 AND v.share_token = $2 AND ev.used_at IS NULL AND ev.expires_at > now()`,
+		hashViewerLinkToken(rawToken), shareToken,
+	).Scan(&email)
+	if err != nil {
+		h.renderExpiredViewerLink(w, r, shareToken)
+		return
+	}
+
+	renderRestrictedPage(w, http.StatusOK, restrictedPageData{
+		Nonce:      httputil.NonceFromContext(r.Context()),
+		Heading:    "Confirm your email",
+		Message:    "Continue to the video as " + email + ".",
+		LinkText:   "Continue to video",
+		FormAction: "/watch/" + shareToken + "/verify-email",
+		FormToken:  rawToken,
+	})
+}
+
+// ConfirmViewerEmailLink completes a verified email gate from the magic
+// link's confirm page and sends the viewer on to the video.
+func (h *Handler) ConfirmViewerEmailLink(w http.ResponseWriter, r *http.Request) {
+	shareToken := chi.URLParam(r, "shareToken")
+
+	var email, videoID, responsesJSON string
+	tokenHash := hashViewerLinkToken(r.FormValue("token"))
+	err := h.db.QueryRow(r.Context(),
+		`SELECT ev.email, v.id, ev.form_responses
+		 FROM viewer_email_verifications ev
+		 JOIN videos v ON v.id = ev.video_id
+		 WHERE ev.token_hash = $1 AND v.share_token = $2 AND ev.used_at IS NULL AND ev.expires_at > now()`,
+		tokenHash, shareToken,
+	).Scan(&email, &videoID, &responsesJSON)
+	if err != nil || !h.consumeViewerVerification(r.Context(), tokenHash) {
+		h.renderExpiredViewerLink(w, r, shareToken)
+		return
+	}
+
+	h.recordViewerIdentityAsync(r, videoID, email, decodeFormResponses(responsesJSON))
+	setEmailGateCookie(w, shareToken, signEmailGateCookie(h.hmacSecret, shareToken, email, true), h.secureCookies)
+	http.Redirect(w, r, h.publicBaseURL(r)+"/watch/"+shareToken, http.StatusSeeOther)
+}
+
+func (h *Handler) renderExpiredViewerLink(w http.ResponseWriter, r *http.Request, shareToken string) {
+	renderRestrictedPage(w, http.StatusBadRequest, restrictedPageData{
+		Nonce:    httputil.NonceFromContext(r.Context()),
+		Heading:  "This link has expired",
+		Message:  "Verification links work once and expire after 15 minutes. Request a new one from the video page.",
+		LinkURL:  h.publicBaseURL(r) + "/watch/" + shareToken,
+		LinkText: "Back to video",
+	})
+}
+
+type emailGateSettingsResponse struct {
+	Enabled        bool        `json:"enabled"`
+	Verified       bool        `json:"verified"`
+	AllowedDomains []string    `json:"allowedDomains"`
+	Fields         []gateField `json:"fields"`
+}
+
+func (h *Handler) GetEmailGate(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var resp emailGateSettingsResponse
+	var fieldsJSON string
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT email_gate_enabled, email_gate_verified, email_gate_fields FROM videos WHERE `+where, args...,
+	).Scan(&resp.Enabled, &resp.Verified, &fieldsJSON); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	domains, err := h.queryStrings(r.Context(),
+		`SELECT domain FROM video_email_gate_domains WHERE video_id = $1 ORDER BY domain`, videoID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch email gate settings")
+		return
+	}
+	resp.AllowedDomains = domains
+	resp.Fields = decodeGateFields(fieldsJSON)
+	if resp.Fields == nil {
+		resp.Fields = []gateField{}
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
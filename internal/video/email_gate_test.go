@@ -0,0 +1,544 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+type mockViewerVerifier struct {
+	mu     sync.Mutex
+	called bool
+	email  string
+	code   string
+	link   string
+}
+
+func (m *mockViewerVerifier) SendViewerVerification(_ context.Context, toEmail, _, code, verifyLink string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.called = true
+	m.email = toEmail
+	m.code = code
+	m.link = verifyLink
+	return nil
+}
+
+func (m *mockViewerVerifier) snapshot() (bool, string, string, string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.called, m.email, m.code, m.link
+}
+
+func serveIdentify(handler *Handler, body string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/identify", handler.IdentifyViewer)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/watch/validtoken1/identify", strings.NewReader(body)))
+	return rec
+}
+
+func TestNormalizeEmailGateDomains(t *testing.T) {
+	got, msg := normalizeEmailGateDomains([]string{" Example.com ", "@acme.io", "example.com", ""})
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if len(got) != 2 || got[0] != "example.com" || got[1] != "acme.io" {
+		t.Errorf("unexpected domains: %v", got)
+	}
+
+	for _, bad := range []string{"localhost", "a@b.com", "foo bar.com", "x.com/path"} {
+		if _, msg := normalizeEmailGateDomains([]string{bad}); msg == "" {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestEmailDomain(t *testing.T) {
+	if got := emailDomain("Alice@Example.COM"); got != "example.com" {
+		t.Errorf("expected example.com, got %q", got)
+	}
+	if got := emailDomain("no-at-sign"); got != "" {
+		t.Errorf("expected empty domain, got %q", got)
+	}
+}
+
+func TestHashViewerCode_BindsToToken(t *testing.T) {
+	a := hashViewerCode(testHMACSecret, "token-a", "123456")
+	if a != hashViewerCode(testHMACSecret, "token-a", "123456") {
+		t.Error("expected deterministic hash")
+	}
+	if a == hashViewerCode(testHMACSecret, "token-b", "123456") {
+		t.Error("expected hash to differ per verification token")
+	}
+}
+
+func TestIdentifyViewer_DomainNotAllowed(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.email_gate_verified`).
+		WithArgs("validtoken1", "gmail.com").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "email_gate_verified", "domain_allowed", "email_gate_fields"}).AddRow("vid-1", "Demo", false, false, "[]"))
+
+	rec := serveIdentify(handler, `{"email":"alice@gmail.com"}`)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("expected no email gate cookie")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestIdentifyViewer_VerifiedSendsCode(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	verifier := &mockViewerVerifier{}
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	handler.SetViewerVerifier(verifier)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.email_gate_verified`).
+		WithArgs("validtoken1", "example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "email_gate_verified", "domain_allowed", "email_gate_fields"}).AddRow("vid-1", "Demo", true, true, "[]"))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM viewer_email_verifications`).
+		WithArgs("vid-1", "alice@example.com", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`INSERT INTO viewer_email_verifications`).
+		WithArgs(pgxmock.AnyArg(), "vid-1", "alice@example.com", pgxmock.AnyArg(), pgxmock.AnyArg(), "{}").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	rec := serveIdentify(handler, `{"email":"Alice@example.com"}`)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("expected no email gate cookie before verification")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	called, email, code, link := verifier.snapshot()
+	if !called {
+		t.Fatal("expected verification email to be sent")
+	}
+	if email != "alice@example.com" {
+		t.Errorf("expected lowercased email, got %q", email)
+	}
+	if len(code) != 6 {
+		t.Errorf("expected 6-digit code, got %q", code)
+	}
+	if !strings.HasPrefix(link, testBaseURL+"/watch/validtoken1/verify-email?token=") {
+		t.Errorf("unexpected verify link %q", link)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestIdentifyViewer_VerifiedRateLimited(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	handler.SetViewerVerifier(&mockViewerVerifier{})
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.email_gate_verified`).
+		WithArgs("validtoken1", "example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "email_gate_verified", "domain_allowed", "email_gate_fields"}).AddRow("vid-1", "Demo", true, true, "[]"))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM viewer_email_verifications`).
+		WithArgs("vid-1", "alice@example.com", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(maxViewerVerificationsPerWindow))
+
+	rec := serveIdentify(handler, `{"email":"alice@example.com"}`)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestIdentifyViewer_VerifiedWithoutVerifier(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.email_gate_verified`).
+		WithArgs("validtoken1", "example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "email_gate_verified", "domain_allowed", "email_gate_fields"}).AddRow("vid-1", "Demo", true, true, "[]"))
+
+	rec := serveIdentify(handler, `{"email":"alice@example.com"}`)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func serveVerifyViewerEmail(handler *Handler, body string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/identify/verify", handler.VerifyViewerEmail)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/watch/validtoken1/identify/verify", strings.NewReader(body)))
+	return rec
+}
+
+func TestVerifyViewerEmail_Success(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	codeHash := hashViewerCode(testHMACSecret, "hash-1", "123456")
+
+	mock.ExpectQuery(`SELECT ev.token_hash, ev.code_hash, ev.attempts, v.id`).
+		WithArgs("validtoken1", "alice@example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"token_hash", "code_hash", "attempts", "id", "form_responses"}).AddRow("hash-1", codeHash, 0, "vid-1", "{}"))
+	mock.ExpectExec(`UPDATE viewer_email_verifications SET used_at = now\(\)`).
+		WithArgs("hash-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`INSERT INTO video_viewers`).
+		WithArgs("vid-1", "alice@example.com", pgxmock.AnyArg(), "{}").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	rec := serveVerifyViewerEmail(handler, `{"email":"Alice@example.com","code":"123456"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	found := false
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "eg_validtok" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected email gate cookie to be set")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestVerifyViewerEmail_WrongCodeCountsAttempt(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	codeHash := hashViewerCode(testHMACSecret, "hash-1", "123456")
+
+	mock.ExpectQuery(`SELECT ev.token_hash, ev.code_hash, ev.attempts, v.id`).
+		WithArgs("validtoken1", "alice@example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"token_hash", "code_hash", "attempts", "id", "form_responses"}).AddRow("hash-1", codeHash, 1, "vid-1", "{}"))
+	mock.ExpectExec(`UPDATE viewer_email_verifications SET attempts = attempts \+ 1`).
+		WithArgs("hash-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	rec := serveVerifyViewerEmail(handler, `{"email":"alice@example.com","code":"000000"}`)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("expected no email gate cookie")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestVerifyViewerEmail_TooManyAttempts(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	codeHash := hashViewerCode(testHMACSecret, "hash-1", "123456")
+
+	mock.ExpectQuery(`SELECT ev.token_hash, ev.code_hash, ev.attempts, v.id`).
+		WithArgs("validtoken1", "alice@example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"token_hash", "code_hash", "attempts", "id", "form_responses"}).AddRow("hash-1", codeHash, maxViewerVerificationAttempts, "vid-1", "{}"))
+
+	rec := serveVerifyViewerEmail(handler, `{"email":"alice@example.com","code":"123456"}`)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestVerifyViewerEmailLink_RendersConfirmWithoutConsuming(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT ev.email\s+FROM viewer_email_verifications`).
+		WithArgs(hashViewerLinkToken("rawtoken"), "validtoken1").
+		WillReturnRows(pgxmock.NewRows([]string{"email"}).AddRow("alice@example.com"))
+
+	r := chi.NewRouter()
+	r.Get("/watch/{shareToken}/verify-email", handler.VerifyViewerEmailLink)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/watch/validtoken1/verify-email?token=rawtoken", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `method="post" action="/watch/validtoken1/verify-email"`) || !strings.Contains(body, `value="rawtoken"`) {
+		t.Errorf("expected a confirm form posting the token, got %s", body)
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("a prefetch must not set the email gate cookie")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestConfirmViewerEmailLink_Success(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	tokenHash := hashViewerLinkToken("rawtoken")
+
+	mock.ExpectQuery(`SELECT ev.email, v.id`).
+		WithArgs(tokenHash, "validtoken1").
+		WillReturnRows(pgxmock.NewRows([]string{"email", "id", "form_responses"}).AddRow("alice@example.com", "vid-1", "{}"))
+	mock.ExpectExec(`UPDATE viewer_email_verifications SET used_at = now\(\)`).
+		WithArgs(tokenHash).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`INSERT INTO video_viewers`).
+		WithArgs("vid-1", "alice@example.com", pgxmock.AnyArg(), "{}").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	r := chi.NewRouter()
+	r.Post("/watch/{shareToken}/verify-email", handler.ConfirmViewerEmailLink)
+	req := httptest.NewRequest(http.MethodPost, "/watch/validtoken1/verify-email", strings.NewReader("token=rawtoken"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if loc := rec.Header().Get("Location"); loc != testBaseURL+"/watch/validtoken1" {
+		t.Errorf("unexpected redirect %q", loc)
+	}
+	if len(rec.Result().Cookies()) != 1 {
+		t.Error("expected email gate cookie to be set")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestVerifyViewerEmailLink_Expired(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT ev.email\s+FROM viewer_email_verifications`).
+		WithArgs(hashViewerLinkToken("stale"), "validtoken1").
+		WillReturnRows(pgxmock.NewRows([]string{"email"}))
+
+	r := chi.NewRouter()
+	r.Get("/watch/{shareToken}/verify-email", handler.VerifyViewerEmailLink)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/watch/validtoken1/verify-email?token=stale", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "This link has expired") {
+		t.Error("expected expired link page")
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("expected no email gate cookie")
+	}
+}
+
+func TestSetEmailGate_VerifiedWithDomains(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE videos SET email_gate_enabled = \$1 WHERE id = \$2 AND user_id = \$3`).
+		WithArgs(true, videoID, testUserID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`UPDATE videos SET email_gate_verified = \$1 WHERE id = \$2`).
+		WithArgs(true, videoID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`DELETE FROM video_email_gate_domains WHERE video_id = \$1`).
+		WithArgs(videoID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`INSERT INTO video_email_gate_domains`).
+		WithArgs(videoID, "example.com").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/email-gate", handler.SetEmailGate)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"enabled":true,"verified":true,"allowedDomains":["@Example.com"]}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/"+videoID+"/email-gate", body))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetEmailGate_RollsBackWhenDomainInsertFails(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE videos SET email_gate_enabled = \$1`).
+		WithArgs(true, videoID, testUserID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`DELETE FROM video_email_gate_domains WHERE video_id = \$1`).
+		WithArgs(videoID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	mock.ExpectExec(`INSERT INTO video_email_gate_domains`).
+		WithArgs(videoID, "example.com").
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/email-gate", handler.SetEmailGate)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"enabled":true,"allowedDomains":["example.com"]}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/"+videoID+"/email-gate", body))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetEmailGate_InvalidDomain(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/email-gate", handler.SetEmailGate)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"enabled":true,"allowedDomains":["not a domain"]}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/video-123/email-gate", body))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetEmailGate(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectQuery(`SELECT email_gate_enabled, email_gate_verified, email_gate_fields FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"email_gate_enabled", "email_gate_verified", "email_gate_fields"}).AddRow(true, true, `[{"key":"company","type":"text","label":"Company","required":true}]`))
+	mock.ExpectQuery(`SELECT domain FROM video_email_gate_domains`).
+		WithArgs(videoID).
+		WillReturnRows(pgxmock.NewRows([]string{"domain"}).AddRow("example.com"))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/email-gate", handler.GetEmailGate)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/"+videoID+"/email-gate", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"verified":true`) || !strings.Contains(body, `"allowedDomains":["example.com"]`) ||
+		!strings.Contains(body, `"fields":[{"key":"company"`) {
+		t.Errorf("unexpected body: %s", body)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
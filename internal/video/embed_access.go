@@ -0,0 +1,289 @@
+package video
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	maxEmbedDomains = 20
+	// maxEmbedTokenLifetime bounds how far in the future an embed token may
+	// expire, so a leaked iframe URL stops working within a day at most.
+	maxEmbedTokenLifetime = 24 * time.Hour
+)
+
+// embedDomainPattern matches a CSP host-source: optional http(s) scheme, an
+// optional leading "*." wildcard, a hostname and an optional port.
+var embedDomainPattern = regexp.MustCompile(`^(https?://)?(\*\.)?[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*(:[0-9]{1,5})?$`)
+
+func normalizeEmbedDomains(domains []string) ([]string, string) {
+	seen := make(map[string]bool, len(domains))
+	result := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d)), "/")
+		if d == "" {
+			continue
+		}
+		if len(d) > 253 || !embedDomainPattern.MatchString(d) {
+			return nil, "invalid embed domain: " + d
+		}
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		result = append(result, d)
+	}
+	if len(result) > maxEmbedDomains {
+		return nil, "too many embed domains"
+	}
+	return result, ""
+}
+
+// restrictFrameAncestors narrows the frame-ancestors directive set by the
+// security middleware to the video's allowed embed domains.
+func restrictFrameAncestors(w http.ResponseWriter, domains []string) {
+	if len(domains) == 0 {
+		return
+	}
+	ancestors := "frame-ancestors 'self' " + strings.Join(domains, " ")
+	csp := w.Header().Get("Content-Security-Policy")
+	if csp == "" {
+		w.Header().Set("Content-Security-Policy", ancestors+";")
+		return
+	}
+	directives := strings.Split(csp, ";")
+	replaced := false
+	for i, d := range directives {
+		if strings.HasPrefix(strings.TrimSpace(d), "frame-ancestors") {
+			directives[i] = " " + ancestors
+			replaced = true
+		}
+	}
+	csp = strings.Join(directives, ";")
+	if !replaced {
+		csp = strings.TrimSuffix(csp, ";") + "; " + ancestors + ";"
+	}
+	w.Header().Set("Content-Security-Policy", strings.TrimSpace(csp))
+}
+
+// verifyEmbedToken checks a token minted by the owner's backend: HS256 with
+// the video's signing secret, subject equal to the share token, and an expiry
+// no further out than maxEmbedTokenLifetime.
+func verifyEmbedToken(secret, shareToken, tokenStr string) error {
+	if tokenStr == "" {
+		return fmt.Errorf("missing embed token")
+	}
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithSubject(shareToken),
+	)
+	if err != nil {
+		return fmt.Errorf("parse embed token: %w", err)
+	}
+	if claims.ExpiresAt.After(time.Now().Add(maxEmbedTokenLifetime)) {
+		return fmt.Errorf("embed token expiry too far in the future")
+	}
+	return nil
+}
+
+// embedOnly reports whether a share token belongs to a video that uses signed
+// embed tokens. Such a video only plays through EmbedPage: the watch page,
+// watch API and download refuse it, and playlists leave it out.
+func (h *Handler) embedOnly(ctx context.Context, shareToken string) bool {
+	var signed bool
+	if err := h.db.QueryRow(ctx,
+		`SELECT embed_signing_secret IS NOT NULL FROM videos
+		 WHERE share_token = $1 AND status IN ('ready', 'processing')`,
+		shareToken,
+	).Scan(&signed); err != nil {
+		return false
+	}
+	return signed
+}
+
+// writeWatchNotFound answers a watch API request for a video that could not
+// be loaded, explaining when it is only playable where it is embedded.
+func (h *Handler) writeWatchNotFound(w http.ResponseWriter, r *http.Request, shareToken string) {
+	if h.embedOnly(r.Context(), shareToken) {
+		httputil.WriteError(w, http.StatusForbidden, "this video can only be played where it is embedded")
+		return
+	}
+	httputil.WriteError(w, http.StatusNotFound, "video not found")
+}
+
+func generateEmbedSigningSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type embedSettingsResponse struct {
+	AllowedDomains []string `json:"allowedDomains"`
+	SignedTokens   bool     `json:"signedTokens"`
+	SigningSecret  string   `json:"signingSecret,omitempty"`
+}
+
+type setEmbedSettingsRequest struct {
+	AllowedDomains *[]string `json:"allowedDomains"`
+	SignedTokens   *bool     `json:"signedTokens"`
+}
+
+func (h *Handler) GetEmbedSettings(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var resp embedSettingsResponse
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT embed_signing_secret IS NOT NULL FROM videos WHERE `+where, args...,
+	).Scan(&resp.SignedTokens); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	domains, err := h.queryStrings(r.Context(),
+		`SELECT domain FROM video_embed_domains WHERE video_id = $1 ORDER BY domain`, videoID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch embed settings")
+		return
+	}
+	resp.AllowedDomains = domains
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// SetEmbedSettings updates allowed embed domains and toggles signed embed
+// tokens. The signing secret is only returned when it is first generated;
+// use RotateEmbedSecret to issue a new one.
+func (h *Handler) SetEmbedSettings(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	var req setEmbedSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var domains []string
+	if req.AllowedDomains != nil {
+		var msg string
+		if domains, msg = normalizeEmbedDomains(*req.AllowedDomains); msg != "" {
+			httputil.WriteError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var resp embedSettingsResponse
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT embed_signing_secret IS NOT NULL FROM videos WHERE `+where, args...,
+	).Scan(&resp.SignedTokens); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	var secret *string
+	changeSecret := req.SignedTokens != nil && *req.SignedTokens != resp.SignedTokens
+	if changeSecret && *req.SignedTokens {
+		generated, err := generateEmbedSigningSecret()
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not update embed settings")
+			return
+		}
+		secret = &generated
+	}
+
+	err := database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+		if changeSecret {
+			if _, err := tx.Exec(r.Context(),
+				`UPDATE videos SET embed_signing_secret = $1 WHERE id = $2`, secret, videoID,
+			); err != nil {
+				return err
+			}
+		}
+		if domains == nil {
+			return nil
+		}
+		if _, err := tx.Exec(r.Context(),
+			`DELETE FROM video_embed_domains WHERE video_id = $1`, videoID,
+		); err != nil {
+			return err
+		}
+		for _, domain := range domains {
+			if _, err := tx.Exec(r.Context(),
+				`INSERT INTO video_embed_domains (video_id, domain) VALUES ($1, $2)`,
+				videoID, domain,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not update embed settings")
+		return
+	}
+
+	if changeSecret {
+		resp.SignedTokens = *req.SignedTokens
+		if secret != nil {
+			resp.SigningSecret = *secret
+		}
+	}
+	if domains != nil {
+		resp.AllowedDomains = domains
+	} else {
+		existing, err := h.queryStrings(r.Context(),
+			`SELECT domain FROM video_embed_domains WHERE video_id = $1 ORDER BY domain`, videoID)
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not fetch embed settings")
+			return
+		}
+		resp.AllowedDomains = existing
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) RotateEmbedSecret(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	secret, err := generateEmbedSigningSecret()
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not rotate embed secret")
+		return
+	}
+
+	where, args := orgVideoFilter(r.Context(), videoID, []any{secret}, "AND status != 'deleted' AND embed_signing_secret IS NOT NULL")
+	tag, err := h.db.Exec(r.Context(),
+		`UPDATE videos SET embed_signing_secret = $1 WHERE `+where, args...,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not rotate embed secret")
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "video not found or signed embeds are disabled")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{"signingSecret": secret})
+}
@@ -0,0 +1,363 @@
+package video
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+const testEmbedSecret = "embed-secret-for-tests"
+
+func signTestEmbedToken(t *testing.T, secret, subject string, expiresIn time.Duration) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+	})
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func embedRowWithAccess(signingSecret *string, domains []string) *pgxmock.Rows {
+	return pgxmock.NewRows(embedPageColumns).AddRow(
+		"vid-1", "Training", "recordings/u1/abc.webm", "Bob", time.Now(), (*time.Time)(nil),
+		(*string)(nil), (*string)(nil), "video/webm",
+		"owner-user-id", "owner@example.com", (*string)(nil),
+		(*string)(nil), (*string)(nil), (*string)(nil),
+		false,
+		(*string)(nil),
+		"ready",
+		"public",
+		signingSecret,
+		domains,
+		"[]",
+		"[]",
+	)
+}
+
+func TestNormalizeEmbedDomains(t *testing.T) {
+	got, msg := normalizeEmbedDomains([]string{" LMS.example.com ", "https://*.acme.io/", "lms.example.com", "localhost:8080"})
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	want := []string{"lms.example.com", "https://*.acme.io", "localhost:8080"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	for _, bad := range []string{"*", "example.com; script-src *", "'self'", "ftp://example.com", "example.com/path"} {
+		if _, msg := normalizeEmbedDomains([]string{bad}); msg == "" {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestRestrictFrameAncestors_ReplacesDirective(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'self'; frame-ancestors *;")
+
+	restrictFrameAncestors(rec, []string{"lms.example.com", "https://*.acme.io"})
+
+	csp := rec.Header().Get("Content-Security-Policy")
+	if !strings.Contains(csp, "frame-ancestors 'self' lms.example.com https://*.acme.io;") {
+		t.Errorf("expected restricted frame-ancestors, got %q", csp)
+	}
+	if strings.Contains(csp, "frame-ancestors *") {
+		t.Errorf("expected wildcard frame-ancestors to be removed, got %q", csp)
+	}
+	if !strings.HasPrefix(csp, "default-src 'self'; script-src 'self';") {
+		t.Errorf("expected other directives to be kept, got %q", csp)
+	}
+}
+
+func TestRestrictFrameAncestors_NoDomainsLeavesHeader(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Security-Policy", "frame-ancestors *;")
+
+	restrictFrameAncestors(rec, nil)
+
+	if csp := rec.Header().Get("Content-Security-Policy"); csp != "frame-ancestors *;" {
+		t.Errorf("expected header unchanged, got %q", csp)
+	}
+}
+
+func TestVerifyEmbedToken(t *testing.T) {
+	valid := signTestEmbedToken(t, testEmbedSecret, "share123", 10*time.Minute)
+	if err := verifyEmbedToken(testEmbedSecret, "share123", valid); err != nil {
+		t.Errorf("expected valid token, got %v", err)
+	}
+
+	cases := map[string]string{
+		"missing":      "",
+		"wrong secret": signTestEmbedToken(t, "other-secret", "share123", 10*time.Minute),
+		"wrong video":  signTestEmbedToken(t, testEmbedSecret, "other-video", 10*time.Minute),
+		"expired":      signTestEmbedToken(t, testEmbedSecret, "share123", -time.Minute),
+		"too long":     signTestEmbedToken(t, testEmbedSecret, "share123", 30*24*time.Hour),
+	}
+	for name, token := range cases {
+		if err := verifyEmbedToken(testEmbedSecret, "share123", token); err == nil {
+			t.Errorf("%s: expected token to be rejected", name)
+		}
+	}
+
+	noExpiry, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "share123"}).SignedString([]byte(testEmbedSecret))
+	if err := verifyEmbedToken(testEmbedSecret, "share123", noExpiry); err == nil {
+		t.Error("expected token without expiry to be rejected")
+	}
+}
+
+func TestEmbedPage_SignedWithoutToken_Returns403(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	secret := testEmbedSecret
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("signed123456").
+		WillReturnRows(embedRowWithAccess(&secret, []string{}))
+
+	rec := serveEmbedPage(handler, embedPageRequest("signed123456"))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "recordings/u1/abc.webm") {
+		t.Error("expected video not to be served")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestEmbedPage_SignedWithValidToken_ServesVideoWithoutWatchLink(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	storage := &mockStorage{downloadURL: "https://s3.example.com/video"}
+	handler := NewHandler(mock, storage, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	secret := testEmbedSecret
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("signed123456").
+		WillReturnRows(embedRowWithAccess(&secret, []string{"lms.example.com"}))
+	mock.ExpectExec(`INSERT INTO video_views`).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	token := signTestEmbedToken(t, testEmbedSecret, "signed123456", 5*time.Minute)
+	rec := serveEmbedPage(handler, embedPageRequest("signed123456?token="+token))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "https://s3.example.com/video") {
+		t.Error("expected video URL in page")
+	}
+	if strings.Contains(body, "Watch on SendRec") {
+		t.Error("expected watch link to be hidden for signed embeds")
+	}
+	if csp := rec.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "frame-ancestors 'self' lms.example.com") {
+		t.Errorf("expected per-video frame-ancestors, got %q", csp)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestSetEmbedSettings_EnableSignedTokensReturnsSecret(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectQuery(`SELECT embed_signing_secret IS NOT NULL FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"signed"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE videos SET embed_signing_secret = \$1 WHERE id = \$2`).
+		WithArgs(pgxmock.AnyArg(), videoID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`DELETE FROM video_embed_domains WHERE video_id = \$1`).
+		WithArgs(videoID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`INSERT INTO video_embed_domains`).
+		WithArgs(videoID, "lms.example.com").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/embed-settings", handler.SetEmbedSettings)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"signedTokens":true,"allowedDomains":["LMS.example.com"]}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/"+videoID+"/embed-settings", body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	resp := rec.Body.String()
+	if !strings.Contains(resp, `"signedTokens":true`) || !strings.Contains(resp, `"signingSecret":"`) {
+		t.Errorf("expected generated signing secret, got %s", resp)
+	}
+	if !strings.Contains(resp, `"allowedDomains":["lms.example.com"]`) {
+		t.Errorf("expected normalized domains, got %s", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetEmbedSettings_RollsBackWhenDomainsFail(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectQuery(`SELECT embed_signing_secret IS NOT NULL FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"signed"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE videos SET embed_signing_secret = \$1 WHERE id = \$2`).
+		WithArgs(pgxmock.AnyArg(), videoID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`DELETE FROM video_embed_domains WHERE video_id = \$1`).
+		WithArgs(videoID).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/embed-settings", handler.SetEmbedSettings)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"signedTokens":true,"allowedDomains":["lms.example.com"]}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/"+videoID+"/embed-settings", body))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "signingSecret") {
+		t.Error("a secret that was rolled back must not be returned")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetEmbedSettings_InvalidDomain(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/embed-settings", handler.SetEmbedSettings)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"allowedDomains":["example.com; script-src *"]}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/video-123/embed-settings", body))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRotateEmbedSecret_NotEnabled(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectExec(`UPDATE videos SET embed_signing_secret = \$1 WHERE id = \$2 AND user_id = \$3 AND organization_id IS NULL AND status != 'deleted' AND embed_signing_secret IS NOT NULL`).
+		WithArgs(pgxmock.AnyArg(), videoID, testUserID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/embed-secret", handler.RotateEmbedSecret)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/"+videoID+"/embed-secret", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func expectSignedVideoRefused(mock pgxmock.PgxPoolIface, shareToken string) {
+	mock.ExpectQuery(`WHERE v\.share_token = \$1 AND v\.status IN \('ready', 'processing'\) AND v\.embed_signing_secret IS NULL`).
+		WithArgs(shareToken).
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery(`SELECT embed_signing_secret IS NOT NULL FROM videos`).
+		WithArgs(shareToken).
+		WillReturnRows(pgxmock.NewRows([]string{"signed"}).AddRow(true))
+}
+
+func TestSignedEmbedVideo_RefusedOutsideEmbed(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		pattern string
+		path    string
+		handle  func(h *Handler) http.HandlerFunc
+	}{
+		{"watch page", "/watch/{shareToken}", "/watch/signed123456", func(h *Handler) http.HandlerFunc { return h.WatchPage }},
+		{"watch api", "/api/watch/{shareToken}", "/api/watch/signed123456", func(h *Handler) http.HandlerFunc { return h.Watch }},
+		{"download", "/api/watch/{shareToken}/download", "/api/watch/signed123456/download", func(h *Handler) http.HandlerFunc { return h.WatchDownload }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+			expectSignedVideoRefused(mock, "signed123456")
+
+			r := chi.NewRouter()
+			r.Get(tc.pattern, tc.handle(handler))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			if rec.Code != http.StatusForbidden {
+				t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if strings.Contains(rec.Body.String(), "recordings/") {
+				t.Error("expected no video URL in the response")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -24,7 +24,12 @@ type embedPageData struct {
 	CtaUrl        string
 	Chapters      []Chapter
 	ChaptersJSON  template.JS
+	TimedCTAs     []videoCTA
+	TimedCTAsJSON template.JS
+	Questions     []playerQuestion
+	QuestionsJSON template.JS
 	VideoStatus   string
+	Signed        bool
 }
 
 type embedPasswordPageData struct {
@@ -37,6 +42,7 @@ type embedEmailGatePageData struct {
 	Title      string
 	ShareToken string
 	Nonce      string
+	GateFields []gateField
 }
 
 var embedPageTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
@@ -77,6 +83,8 @@ var embedPageTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE htm
             height: 100%;
         }
 ` + playerCSS + `
+` + timedCTACSS + `
+` + videoQuestionCSS + `
         .embed-processing {
             display: flex;
             flex-direction: column;
@@ -185,6 +193,8 @@ var embedPageTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE htm
 {{else}}
             <div class="player-container" id="player-container">
                 <video id="player" playsinline webkit-playsinline{{if .TranscriptURL}} crossorigin="anonymous"{{end}} controlsList="nodownload" src="{{.VideoURL}}"{{if .ThumbnailURL}} poster="{{.ThumbnailURL}}"{{end}}>{{if .TranscriptURL}}<track kind="subtitles" src="{{.TranscriptURL}}" srclang="en" label="Subtitles">{{end}}</video>
+                {{if .TimedCTAs}}` + timedCTAHTML + `{{end}}
+                {{if .Questions}}` + videoQuestionHTML + `{{end}}
 ` + playerControlsHTML + `
             </div>
 {{end}}
@@ -198,7 +208,7 @@ var embedPageTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE htm
 {{end}}
         <div class="footer">
             <span class="footer-title">{{.Title}}</span>
-            <a href="{{.BaseURL}}/watch/{{.ShareToken}}" target="_blank" rel="noopener">Watch on SendRec</a>
+            {{if not .Signed}}<a href="{{.BaseURL}}/watch/{{.ShareToken}}" target="_blank" rel="noopener">Watch on SendRec</a>{{end}}
         </div>
 ` + safariWarningHTML + `
     </div>
@@ -253,6 +263,22 @@ var embedPageTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE htm
             }
         })();
         {{end}}
+        {{if .TimedCTAs}}
+        (function() {
+            var player = document.getElementById('player');
+            var timedCTAs = {{.TimedCTAsJSON}};
+            var timedCTAClickURL = '/api/watch/{{.ShareToken}}/cta-click';
+` + timedCTAJS + `
+        })();
+        {{end}}
+        {{if .Questions}}
+        (function() {
+            var player = document.getElementById('player');
+            var videoQuestions = {{.QuestionsJSON}};
+            var questionAnswerURL = '/api/watch/{{.ShareToken}}/answers';
+` + videoQuestionJS + `
+        })();
+        {{end}}
         {{if .Chapters}}
         (function() {
             var chaptersLayer = document.getElementById('seek-chapters');
@@ -340,10 +366,41 @@ var embedPageTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE htm
             var reported = {};
             var pending = [];
             var lastSeg = -1;
+            var ranges = [];
+            var rangeStart = -1;
+            var rangeEnd = -1;
+            var sessionId = window.crypto && crypto.randomUUID
+                ? crypto.randomUUID()
+                : Date.now().toString(36) + Math.random().toString(36).slice(2);
+            var sessionStart = Date.now();
+            var events = [];
+            var lastPos = 0;
+            var seekFrom = -1;
+            function logEvent(type, position, extra) {
+                if (events.length >= 200) return;
+                var e = { type: type, t: Date.now() - sessionStart, position: position };
+                for (var k in extra) e[k] = extra[k];
+                events.push(e);
+            }
+            function closeRange() {
+                if (rangeStart >= 0 && rangeEnd > rangeStart) {
+                    ranges.push([rangeStart, rangeEnd]);
+                    logEvent('segment', rangeEnd, { from: rangeStart });
+                }
+                rangeStart = -1;
+            }
             function flush() {
-                if (pending.length === 0) return;
-                var data = JSON.stringify({ segments: pending });
+                var resumeAt = !player.paused && rangeStart >= 0 ? rangeEnd : -1;
+                closeRange();
+                if (resumeAt >= 0) {
+                    rangeStart = resumeAt;
+                    rangeEnd = resumeAt;
+                }
+                if (pending.length === 0 && ranges.length === 0 && events.length === 0) return;
+                var data = JSON.stringify({ segments: pending, ranges: ranges, sessionId: sessionId, events: events });
                 pending = [];
+                ranges = [];
+                events = [];
                 if (navigator.sendBeacon) {
                     navigator.sendBeacon('/api/watch/{{.ShareToken}}/segments',
                         new Blob([data], { type: 'application/json' }));
@@ -355,8 +412,33 @@ var embedPageTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE htm
                     }).catch(function() {});
                 }
             }
+            player.addEventListener('seeking', function() {
+                if (seekFrom < 0) seekFrom = lastPos;
+                closeRange();
+            });
+            player.addEventListener('seeked', function() {
+                if (seekFrom < 0) return;
+                logEvent('seek', player.currentTime, { from: seekFrom });
+                seekFrom = -1;
+                lastPos = player.currentTime;
+            });
+            player.addEventListener('play', function() { logEvent('play', player.currentTime); });
+            player.addEventListener('pause', function() {
+                if (!player.ended) logEvent('pause', player.currentTime);
+            });
+            player.addEventListener('ended', function() { logEvent('ended', player.currentTime); });
+            player.addEventListener('ratechange', function() {
+                logEvent('ratechange', player.currentTime, { rate: player.playbackRate });
+            });
             player.addEventListener('timeupdate', function() {
                 if (!player.duration || player.duration <= 0) return;
+                var t = player.currentTime;
+                if (seekFrom < 0) lastPos = t;
+                if (rangeStart < 0 || t < rangeEnd || t - rangeEnd > 2) {
+                    closeRange();
+                    rangeStart = t;
+                }
+                rangeEnd = t;
                 var seg = Math.min(Math.floor((player.currentTime / player.duration) * SEGMENTS), SEGMENTS - 1);
                 if (reported[seg]) return;
                 if (lastSeg >= 0 && Math.abs(seg - lastSeg) > 1) {
@@ -480,7 +562,7 @@ var embedEmailGatePageTemplate = template.Must(template.New("embed-emailgate").P
         h1 { font-size: 1.25rem; margin-bottom: 0.5rem; }
         p { color: #94a3b8; margin-bottom: 1rem; font-size: 0.875rem; }
         .error { color: #ef4444; font-size: 0.8rem; margin-bottom: 0.75rem; display: none; }
-        input[type="email"] {
+        input[type="email"], input[type="text"] {
             width: 100%;
             padding: 0.625rem 0.75rem;
             border-radius: 6px;
@@ -491,7 +573,7 @@ var embedEmailGatePageTemplate = template.Must(template.New("embed-emailgate").P
             margin-bottom: 0.75rem;
             outline: none;
         }
-        input[type="email"]:focus { border-color: #00b67a; }
+        input[type="email"]:focus, input[type="text"]:focus { border-color: #00b67a; }
         button {
             width: 100%;
             background: #00b67a;
@@ -504,7 +586,7 @@ var embedEmailGatePageTemplate = template.Must(template.New("embed-emailgate").P
             cursor: pointer;
         }
         button:hover { opacity: 0.9; }
-        button:disabled { opacity: 0.5; cursor: not-allowed; }
+        button:disabled { opacity: 0.5; cursor: not-allowed; }` + gateFieldsCSS + `
     </style>
 </head>
 <body>
@@ -514,22 +596,44 @@ var embedEmailGatePageTemplate = template.Must(template.New("embed-emailgate").P
         <p class="error" id="error-msg"></p>
         <form id="email-gate-form">
             <input type="email" id="email-input" placeholder="you@example.com" required maxlength="320" autofocus>
+            ` + gateFieldsHTML + `
+            <input type="text" id="code-input" placeholder="6-digit code" inputmode="numeric" autocomplete="one-time-code" maxlength="6" hidden>
             <button type="submit" id="submit-btn">Watch Video</button>
         </form>
     </div>
-    <script nonce="{{.Nonce}}">
+    <script nonce="{{.Nonce}}">` + gateFieldsJS + `
+        var awaitingCode = false;
         document.getElementById('email-gate-form').addEventListener('submit', function(e) {
             e.preventDefault();
             var btn = document.getElementById('submit-btn');
             var errEl = document.getElementById('error-msg');
-            var email = document.getElementById('email-input').value;
+            var emailEl = document.getElementById('email-input');
+            var codeEl = document.getElementById('code-input');
+            var email = emailEl.value;
             btn.disabled = true;
             errEl.style.display = 'none';
-            fetch('/api/watch/{{.ShareToken}}/identify', {
+            var url = awaitingCode ? '/api/watch/{{.ShareToken}}/identify/verify' : '/api/watch/{{.ShareToken}}/identify';
+            var payload = awaitingCode ? {email: email, code: codeEl.value} : {email: email, fields: collectGateFields()};
+            fetch(url, {
                 method: 'POST',
                 headers: {'Content-Type': 'application/json'},
-                body: JSON.stringify({email: email})
+                body: JSON.stringify(payload)
             }).then(function(r) {
+                if (r.status === 202) {
+                    awaitingCode = true;
+                    emailEl.hidden = true;
+                    document.getElementById('gate-fields').hidden = true;
+                    codeEl.hidden = false;
+                    codeEl.required = true;
+                    codeEl.focus();
+                    btn.textContent = 'Verify';
+                    btn.disabled = false;
+                    errEl.textContent = 'We sent a code to ' + email + '. Enter it below or use the link in the email.';
+                    errEl.style.color = '#94a3b8';
+                    errEl.style.display = 'block';
+                    return;
+                }
+                errEl.style.color = '';
                 if (r.ok) { window.location.reload(); }
                 else { return r.json().then(function(d) { errEl.textContent = d.error || 'Something went wrong'; errEl.style.display = 'block'; btn.disabled = false; }); }
             }).catch(function() {
@@ -560,22 +664,34 @@ func (h *Handler) EmbedPage(w http.ResponseWriter, r *http.Request) {
 	var emailGateEnabled bool
 	var chaptersJSON *string
 	var status string
+	var visibility string
+	var embedSigningSecret *string
+	var embedDomains []string
+	var timedCTAsJSON string
+	var questionsJSON string
 
 	err := h.db.QueryRow(r.Context(),
 		`SELECT v.id, v.title, v.file_key, u.name, v.created_at, v.share_expires_at,
 		        v.thumbnail_key, v.share_password, v.content_type,
 		        v.user_id, u.email, v.view_notification,
 		        v.cta_text, v.cta_url, v.transcript_key,
-		        v.email_gate_enabled, v.chapters, v.status
+		        v.email_gate_enabled, v.chapters, v.status,
+		        COALESCE(v.visibility, f.visibility, 'public'),
+		        v.embed_signing_secret,
+		        ARRAY(SELECT d.domain FROM video_embed_domains d WHERE d.video_id = v.id ORDER BY d.domain),
+		        `+videoCTAsJSONColumn+`,
+		        `+videoQuestionsJSONColumn+`
 		 FROM videos v
 		 JOIN users u ON u.id = v.user_id
+		 LEFT JOIN folders f ON f.id = v.folder_id
 		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
 		shareToken,
 	).Scan(&videoID, &title, &fileKey, &creator, &createdAt, &shareExpiresAt,
 		&thumbnailKey, &sharePassword, &contentType,
 		&ownerID, &ownerEmail, &viewNotification,
 		&ctaText, &ctaUrl, &transcriptKey,
-		&emailGateEnabled, &chaptersJSON, &status)
+		&emailGateEnabled, &chaptersJSON, &status, &visibility,
+		&embedSigningSecret, &embedDomains, &timedCTAsJSON, &questionsJSON)
 	if err != nil {
 		nonce := httputil.NonceFromContext(r.Context())
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -587,6 +703,7 @@ func (h *Handler) EmbedPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	nonce := httputil.NonceFromContext(r.Context())
+	restrictFrameAncestors(w, embedDomains)
 
 	if shareExpiresAt != nil && time.Now().After(*shareExpiresAt) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -597,6 +714,35 @@ func (h *Handler) EmbedPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if embedSigningSecret != nil {
+		if err := verifyEmbedToken(*embedSigningSecret, shareToken, r.URL.Query().Get("token")); err != nil {
+			renderRestrictedPage(w, http.StatusForbidden, restrictedPageData{
+				Nonce:   nonce,
+				Heading: "This embed link has expired",
+				Message: "This video can only be played from the page it is embedded on. Reload that page to continue watching.",
+			})
+			return
+		}
+	}
+
+	// Identity providers refuse to be framed, so a restricted embed links out
+	// to the watch page to sign in instead of redirecting inside the iframe.
+	switch h.decideVisibility(r, shareToken, visibility) {
+	case watchVisibilitySignIn:
+		renderRestrictedPage(w, http.StatusUnauthorized, restrictedPageData{
+			Nonce:    nonce,
+			Heading:  "Sign in to watch",
+			Message:  "This video is only available to signed-in viewers.",
+			LinkURL:  h.publicBaseURL(r) + "/watch/" + shareToken,
+			LinkText: "Sign in",
+			NewTab:   true,
+		})
+		return
+	case watchVisibilityDenied:
+		renderAccessDeniedPage(w, nonce)
+		return
+	}
+
 	if sharePassword != nil {
 		if !hasValidWatchCookie(r, h.hmacSecret, shareToken, *sharePassword) {
 			w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -612,12 +758,13 @@ func (h *Handler) EmbedPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if emailGateEnabled {
-		if _, ok := hasValidEmailGateCookie(r, h.hmacSecret, shareToken); !ok {
+		if !h.passesVideoEmailGate(r, shareToken) {
 			w.Header().Set("Content-Type", "text/html; charset=utf-8")
 			if err := embedEmailGatePageTemplate.Execute(w, embedEmailGatePageData{
 				Title:      title,
 				ShareToken: shareToken,
 				Nonce:      nonce,
+				GateFields: h.loadVideoGateFields(r.Context(), videoID),
 			}); err != nil {
 				slog.Error("embed-page: failed to render email gate page", "error", err)
 			}
@@ -645,8 +792,9 @@ func (h *Handler) EmbedPage(w http.ResponseWriter, r *http.Request) {
 			VideoStatus: "processing",
 			ShareToken:  shareToken,
 			Nonce:       nonce,
-			BaseURL:     h.baseURL,
+			BaseURL:     h.publicBaseURL(r),
 			Chapters:    make([]Chapter, 0),
+			Signed:      embedSigningSecret != nil,
 		}); err != nil {
 			slog.Error("embed-page: failed to render processing page", "error", err)
 		}
@@ -678,6 +826,10 @@ func (h *Handler) EmbedPage(w http.ResponseWriter, r *http.Request) {
 		_ = json.Unmarshal([]byte(*chaptersJSON), &chapterList)
 	}
 	chaptersJSONBytes, _ := json.Marshal(chapterList)
+	timedCTAs := decodeVideoCTAs(timedCTAsJSON)
+	timedCTAsJSONBytes, _ := json.Marshal(timedCTAs)
+	questions := decodePlayerQuestions(questionsJSON)
+	questionsJSONBytes, _ := json.Marshal(questions)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := embedPageTemplate.Execute(w, embedPageData{
@@ -687,12 +839,17 @@ func (h *Handler) EmbedPage(w http.ResponseWriter, r *http.Request) {
 		TranscriptURL: transcriptURL,
 		ShareToken:    shareToken,
 		Nonce:         nonce,
-		BaseURL:       h.baseURL,
+		BaseURL:       h.publicBaseURL(r),
 		ContentType:   contentType,
 		CtaText:       derefString(ctaText),
 		CtaUrl:        derefString(ctaUrl),
 		Chapters:      chapterList,
 		ChaptersJSON:  template.JS(chaptersJSONBytes),
+		TimedCTAs:     timedCTAs,
+		TimedCTAsJSON: template.JS(timedCTAsJSONBytes),
+		Questions:     questions,
+		QuestionsJSON: template.JS(questionsJSONBytes),
+		Signed:        embedSigningSecret != nil,
 		VideoStatus:   status,
 	}); err != nil {
 		slog.Error("embed-page: failed to render embed page", "error", err)
@@ -19,6 +19,11 @@ var embedPageColumns = []string{
 	"user_id", "email", "view_notification",
 	"cta_text", "cta_url", "transcript_key",
 	"email_gate_enabled", "chapters", "status",
+	"visibility",
+	"embed_signing_secret",
+	"embed_domains",
+	"timed_ctas",
+	"questions",
 }
 
 func embedPageRequest(shareToken string) *http.Request {
@@ -88,6 +93,11 @@ func TestEmbedPage_Expired_Returns410(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -127,6 +137,11 @@ func TestEmbedPage_Success_RendersVideoPlayer(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -183,6 +198,11 @@ func TestEmbedPage_WithThumbnail_RendersPoster(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -224,6 +244,11 @@ func TestEmbedPage_PasswordProtected_NoCookie_ShowsPasswordForm(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -267,10 +292,15 @@ func TestEmbedPage_RecordsView(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -310,6 +340,11 @@ func TestEmbedPage_CSPNonce(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -350,11 +385,16 @@ func TestEmbedPage_NeverExpires(t *testing.T) {
 				false,
 				(*string)(nil),
 				"ready",
+				"public",
+				(*string)(nil),
+				[]string{},
+				"[]",
+				"[]",
 			),
 		)
 
 	mock.ExpectExec("INSERT INTO video_views").
-		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	req := embedPageRequest("token-never")
@@ -399,6 +439,11 @@ func TestEmbedPage_ResponsiveLayout(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -445,10 +490,15 @@ func TestEmbedPage_RendersCtaOverlay(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -500,10 +550,15 @@ func TestEmbedPage_RendersSubtitleTrack(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -554,10 +609,15 @@ func TestEmbedPage_MilestoneTrackingScript(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -604,6 +664,11 @@ func TestEmbedPage_EmailGate_ShowsForm(t *testing.T) {
 			true,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -652,10 +717,15 @@ func TestEmbedPage_ChaptersBar(t *testing.T) {
 			false,
 			&chaptersJSON,
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -709,10 +779,15 @@ func TestEmbedPage_NoChaptersBar_WhenEmpty(t *testing.T) {
 			false,
 			(*string)(nil),
 			"ready",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -754,10 +829,15 @@ func TestEmbedPage_ProcessingStatus_ShowsProcessingOverlay(t *testing.T) {
 			false,
 			(*string)(nil),
 			"processing",
+			"public",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
 		))
 
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	rec := serveEmbedPage(handler, embedPageRequest(shareToken))
@@ -0,0 +1,320 @@
+package video
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	maxGateFields         = 10
+	maxGateFieldLabelLen  = 100
+	maxGateFieldOptions   = 20
+	maxGateFieldOptionLen = 100
+	maxGateFieldValueLen  = 500
+	maxGateFieldKeyLen    = 40
+)
+
+var (
+	validGateFieldTypes = map[string]bool{"text": true, "textarea": true, "select": true, "checkbox": true}
+	gateFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// gateField is one extra input on an email gate, shown below the email
+// address. Email itself is always collected and is not configurable.
+type gateField struct {
+	Key      string   `json:"key"`
+	Type     string   `json:"type"`
+	Label    string   `json:"label"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// gateFieldKey derives a response key from a label, e.g. "Company name"
+// becomes "company_name".
+func gateFieldKey(label string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(label) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	key := strings.TrimRight(b.String(), "_")
+	if key != "" && key[0] >= '0' && key[0] <= '9' {
+		key = "f_" + key
+	}
+	if len(key) > maxGateFieldKeyLen {
+		key = strings.TrimRight(key[:maxGateFieldKeyLen], "_")
+	}
+	return key
+}
+
+// normalizeGateFields validates the owner's gate form definition, filling in
+// default types and keys derived from labels.
+func normalizeGateFields(fields []gateField) ([]gateField, string) {
+	if len(fields) > maxGateFields {
+		return nil, "a gate form can have at most 10 fields"
+	}
+	seen := make(map[string]bool, len(fields))
+	result := make([]gateField, 0, len(fields))
+	for i, f := range fields {
+		f.Label = strings.TrimSpace(f.Label)
+		if f.Label == "" {
+			return nil, "field labels are required"
+		}
+		if len(f.Label) > maxGateFieldLabelLen {
+			return nil, "field labels must be 100 characters or less"
+		}
+		f.Type = strings.TrimSpace(f.Type)
+		if f.Type == "" {
+			f.Type = "text"
+		}
+		if !validGateFieldTypes[f.Type] {
+			return nil, "field type must be text, textarea, select or checkbox"
+		}
+		f.Key = strings.ToLower(strings.TrimSpace(f.Key))
+		if f.Key == "" {
+			f.Key = gateFieldKey(f.Label)
+		}
+		if f.Key == "" {
+			f.Key = fmt.Sprintf("field_%d", i+1)
+		}
+		if len(f.Key) > maxGateFieldKeyLen || !gateFieldKeyPattern.MatchString(f.Key) {
+			return nil, "invalid field key: " + f.Key
+		}
+		if f.Key == "email" {
+			return nil, "email is always collected and cannot be a custom field"
+		}
+		if seen[f.Key] {
+			return nil, "duplicate field key: " + f.Key
+		}
+		seen[f.Key] = true
+
+		if f.Type != "select" {
+			f.Options = nil
+			result = append(result, f)
+			continue
+		}
+		options := make([]string, 0, len(f.Options))
+		for _, o := range f.Options {
+			o = strings.TrimSpace(o)
+			if o == "" {
+				continue
+			}
+			if len(o) > maxGateFieldOptionLen {
+				return nil, "field options must be 100 characters or less"
+			}
+			options = append(options, o)
+		}
+		if len(options) == 0 || len(options) > maxGateFieldOptions {
+			return nil, "select fields need between 1 and 20 options"
+		}
+		f.Options = options
+		result = append(result, f)
+	}
+	return result, ""
+}
+
+// decodeGateFields parses a stored gate form. A malformed value yields no
+// extra fields so the gate still works with email alone.
+func decodeGateFields(raw string) []gateField {
+	var fields []gateField
+	if raw == "" || json.Unmarshal([]byte(raw), &fields) != nil {
+		return nil
+	}
+	return fields
+}
+
+// decodeFormResponses parses stored lead-form answers.
+func decodeFormResponses(raw string) map[string]any {
+	responses := map[string]any{}
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &responses)
+	}
+	return responses
+}
+
+// validateGateResponses checks a viewer's submission against the gate form
+// and returns the answers keyed by field. Values for unknown keys are dropped.
+func validateGateResponses(fields []gateField, values map[string]any) (map[string]any, string) {
+	responses := make(map[string]any, len(fields))
+	for _, f := range fields {
+		v, present := values[f.Key]
+		if f.Type == "checkbox" {
+			checked, ok := v.(bool)
+			if present && v != nil && !ok {
+				return nil, "invalid value for " + f.Label
+			}
+			if f.Required && !checked {
+				return nil, f.Label + " is required"
+			}
+			responses[f.Key] = checked
+			continue
+		}
+		s, ok := v.(string)
+		if present && v != nil && !ok {
+			return nil, "invalid value for " + f.Label
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			if f.Required {
+				return nil, f.Label + " is required"
+			}
+			continue
+		}
+		if len(s) > maxGateFieldValueLen {
+			return nil, f.Label + " must be 500 characters or less"
+		}
+		if f.Type == "select" && !slices.Contains(f.Options, s) {
+			return nil, "invalid option for " + f.Label
+		}
+		responses[f.Key] = s
+	}
+	return responses, ""
+}
+
+// loadGateFields fetches a gate form for rendering. The gate page falls back
+// to email only when the lookup fails.
+func (h *Handler) loadGateFields(ctx context.Context, query, id string) []gateField {
+	var raw string
+	if err := h.db.QueryRow(ctx, query, id).Scan(&raw); err != nil {
+		return nil
+	}
+	return decodeGateFields(raw)
+}
+
+func (h *Handler) loadVideoGateFields(ctx context.Context, videoID string) []gateField {
+	return h.loadGateFields(ctx, `SELECT email_gate_fields FROM videos WHERE id = $1`, videoID)
+}
+
+func (h *Handler) loadPlaylistGateFields(ctx context.Context, playlistID string) []gateField {
+	return h.loadGateFields(ctx, `SELECT email_gate_fields FROM playlists WHERE id = $1`, playlistID)
+}
+
+// leadSubmission is one identified viewer and their gate form answers.
+type leadSubmission struct {
+	Email        string
+	IdentifiedAt time.Time
+	Responses    map[string]any
+}
+
+// leadSubmissions returns the video's gate form and the viewers identified
+// since the start of the analytics range. Failures yield no rows.
+func (h *Handler) leadSubmissions(ctx context.Context, videoID string, since time.Time) ([]gateField, []leadSubmission) {
+	rows, err := h.db.Query(ctx,
+		`SELECT email, created_at, form_responses FROM video_viewers
+		 WHERE video_id = $1 AND created_at >= $2 ORDER BY created_at`,
+		videoID, since,
+	)
+	if err != nil {
+		return nil, nil
+	}
+	defer rows.Close()
+
+	var leads []leadSubmission
+	for rows.Next() {
+		var lead leadSubmission
+		var responsesJSON string
+		if err := rows.Scan(&lead.Email, &lead.IdentifiedAt, &responsesJSON); err != nil {
+			return nil, nil
+		}
+		lead.Responses = decodeFormResponses(responsesJSON)
+		leads = append(leads, lead)
+	}
+	if rows.Err() != nil || len(leads) == 0 {
+		return nil, nil
+	}
+	return h.loadVideoGateFields(ctx, videoID), leads
+}
+
+// writeLeadCSV appends identified viewers to an analytics export, with one
+// column per gate form field.
+func writeLeadCSV(w io.Writer, cw *csv.Writer, fields []gateField, leads []leadSubmission) {
+	if len(leads) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	header := []string{"Email", "Identified At"}
+	for _, f := range fields {
+		header = append(header, f.Label)
+	}
+	_ = cw.Write(header)
+	for _, lead := range leads {
+		record := []string{lead.Email, lead.IdentifiedAt.UTC().Format(time.RFC3339)}
+		for _, f := range fields {
+			switch v := lead.Responses[f.Key].(type) {
+			case bool:
+				if v {
+					record = append(record, "yes")
+				} else {
+					record = append(record, "no")
+				}
+			case string:
+				record = append(record, v)
+			default:
+				record = append(record, "")
+			}
+		}
+		_ = cw.Write(record)
+	}
+	cw.Flush()
+}
+
+// gateFieldsCSS styles the custom inputs on every email gate page.
+const gateFieldsCSS = `
+        .gate-field {
+            display: block;
+            width: 100%;
+            padding: 0.75rem 1rem;
+            border-radius: 8px;
+            border: 1px solid #334155;
+            background: #1e293b;
+            color: #fff;
+            font-size: 1rem;
+            margin-bottom: 1rem;
+            outline: none;
+            font-family: inherit;
+        }
+        .gate-field:focus { border-color: #00b67a; }
+        .gate-field::placeholder { color: #94a3b8; }
+        textarea.gate-field { resize: vertical; min-height: 4.5rem; }
+        .gate-check {
+            display: flex;
+            gap: 0.5rem;
+            align-items: flex-start;
+            text-align: left;
+            font-size: 0.875rem;
+            color: #cbd5e1;
+            margin-bottom: 1rem;
+        }
+        .gate-check input { margin-top: 0.2rem; accent-color: #00b67a; }`
+
+// gateFieldsHTML renders .GateFields inside a gate form.
+const gateFieldsHTML = `<div id="gate-fields">
+            {{range .GateFields}}{{if eq .Type "checkbox"}}<label class="gate-check"><input type="checkbox" data-gate-key="{{.Key}}"{{if .Required}} required{{end}}><span>{{.Label}}</span></label>
+            {{else if eq .Type "select"}}<select class="gate-field" data-gate-key="{{.Key}}" aria-label="{{.Label}}"{{if .Required}} required{{end}}><option value="">{{.Label}}{{if not .Required}} (optional){{end}}</option>{{range .Options}}<option>{{.}}</option>{{end}}</select>
+            {{else if eq .Type "textarea"}}<textarea class="gate-field" data-gate-key="{{.Key}}" placeholder="{{.Label}}{{if not .Required}} (optional){{end}}" aria-label="{{.Label}}" maxlength="500"{{if .Required}} required{{end}}></textarea>
+            {{else}}<input type="text" class="gate-field" data-gate-key="{{.Key}}" placeholder="{{.Label}}{{if not .Required}} (optional){{end}}" aria-label="{{.Label}}" maxlength="500"{{if .Required}} required{{end}}>
+            {{end}}{{end}}</div>`
+
+// gateFieldsJS collects the custom field answers for the identify request.
+const gateFieldsJS = `
+        function collectGateFields() {
+            var fields = {};
+            document.querySelectorAll('#gate-fields [data-gate-key]').forEach(function(el) {
+                fields[el.getAttribute('data-gate-key')] = el.type === 'checkbox' ? el.checked : el.value;
+            });
+            return fields;
+        }`
@@ -0,0 +1,240 @@
+package video
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestNormalizeGateFields(t *testing.T) {
+	fields, msg := normalizeGateFields([]gateField{
+		{Label: " Company name ", Required: true},
+		{Label: "Role", Type: "select", Options: []string{" Engineer ", "", "Manager"}},
+		{Label: "I agree to be contacted", Type: "checkbox", Options: []string{"ignored"}},
+		{Label: "2nd phone", Key: ""},
+	})
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if fields[0].Key != "company_name" || fields[0].Type != "text" || fields[0].Label != "Company name" {
+		t.Errorf("unexpected first field: %+v", fields[0])
+	}
+	if len(fields[1].Options) != 2 || fields[1].Options[0] != "Engineer" {
+		t.Errorf("expected trimmed select options, got %v", fields[1].Options)
+	}
+	if fields[2].Key != "i_agree_to_be_contacted" || fields[2].Options != nil {
+		t.Errorf("unexpected checkbox field: %+v", fields[2])
+	}
+	if fields[3].Key != "f_2nd_phone" {
+		t.Errorf("expected key f_2nd_phone, got %q", fields[3].Key)
+	}
+
+	tests := []struct {
+		name   string
+		fields []gateField
+	}{
+		{"missing label", []gateField{{Label: " "}}},
+		{"bad type", []gateField{{Label: "Phone", Type: "tel"}}},
+		{"reserved email key", []gateField{{Label: "Email"}}},
+		{"duplicate key", []gateField{{Label: "Company"}, {Label: "company"}}},
+		{"invalid key", []gateField{{Label: "Company", Key: "Company Name"}}},
+		{"select without options", []gateField{{Label: "Role", Type: "select"}}},
+		{"too many fields", make([]gateField, maxGateFields+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, msg := normalizeGateFields(tt.fields); msg == "" {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestValidateGateResponses(t *testing.T) {
+	fields := []gateField{
+		{Key: "name", Type: "text", Label: "Name", Required: true},
+		{Key: "role", Type: "select", Label: "Role", Options: []string{"Engineer", "Manager"}},
+		{Key: "consent", Type: "checkbox", Label: "Consent", Required: true},
+		{Key: "newsletter", Type: "checkbox", Label: "Newsletter"},
+	}
+
+	responses, msg := validateGateResponses(fields, map[string]any{
+		"name": " Ada ", "role": "Engineer", "consent": true, "extra": "dropped",
+	})
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if responses["name"] != "Ada" || responses["role"] != "Engineer" || responses["consent"] != true || responses["newsletter"] != false {
+		t.Errorf("unexpected responses: %v", responses)
+	}
+	if _, ok := responses["extra"]; ok {
+		t.Error("expected unknown keys to be dropped")
+	}
+
+	for name, values := range map[string]map[string]any{
+		"missing required text": {"consent": true},
+		"unchecked consent":     {"name": "Ada", "consent": false},
+		"unknown option":        {"name": "Ada", "consent": true, "role": "CEO"},
+		"wrong type":            {"name": 42, "consent": true},
+		"overlong value":        {"name": strings.Repeat("a", maxGateFieldValueLen+1), "consent": true},
+	} {
+		if _, msg := validateGateResponses(fields, values); msg == "" {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestIdentifyViewer_StoresGateFormResponses(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	fieldsJSON := `[{"key":"company","type":"text","label":"Company","required":true},{"key":"consent","type":"checkbox","label":"Consent","required":true}]`
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.email_gate_verified`).
+		WithArgs("validtoken1", "example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "email_gate_verified", "domain_allowed", "email_gate_fields"}).
+			AddRow("vid-1", "Demo", false, true, fieldsJSON))
+	mock.ExpectExec(`INSERT INTO video_viewers \(video_id, email, viewer_hash, form_responses\)`).
+		WithArgs("vid-1", "alice@example.com", pgxmock.AnyArg(), `{"company":"Acme","consent":true}`).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/identify", handler.IdentifyViewer)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/watch/validtoken1/identify",
+		strings.NewReader(`{"email":"alice@example.com","fields":{"company":"Acme","consent":true}}`)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestIdentifyViewer_RejectsIncompleteGateForm(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.email_gate_verified`).
+		WithArgs("validtoken1", "example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "email_gate_verified", "domain_allowed", "email_gate_fields"}).
+			AddRow("vid-1", "Demo", false, true, `[{"key":"consent","type":"checkbox","label":"Consent","required":true}]`))
+
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/identify", handler.IdentifyViewer)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/watch/validtoken1/identify",
+		strings.NewReader(`{"email":"alice@example.com"}`)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "Consent is required") {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("expected no gate cookie for a rejected submission")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestEmailGatePage_RendersGateFields(t *testing.T) {
+	var buf bytes.Buffer
+	err := emailGatePageTemplate.Execute(&buf, emailGatePageData{
+		Title:      "Demo",
+		ShareToken: "validtoken1",
+		Nonce:      "n",
+		GateFields: []gateField{
+			{Key: "company", Type: "text", Label: "Company", Required: true},
+			{Key: "role", Type: "select", Label: "Role", Options: []string{"Engineer", "Manager"}},
+			{Key: "consent", Type: "checkbox", Label: "I agree to be contacted", Required: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := buf.String()
+	for _, want := range []string{
+		`data-gate-key="company" placeholder="Company" aria-label="Company" maxlength="500" required`,
+		`<option value="">Role (optional)</option><option>Engineer</option><option>Manager</option>`,
+		`<input type="checkbox" data-gate-key="consent" required><span>I agree to be contacted</span>`,
+		`fields: collectGateFields()`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected gate page to contain %q", want)
+		}
+	}
+}
+
+func TestAnalyticsExport_IncludesLeadSubmissions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	identifiedAt := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE id = \$1 AND user_id = \$2 AND status != 'deleted'`).
+		WithArgs("vid-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("vid-1"))
+	mock.ExpectQuery(`SELECT day, views, unique_viewers`).
+		WithArgs("vid-1", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}))
+	mock.ExpectQuery(`SELECT q.id, q.prompt, q.options, q.correct_option, a.option_index`).
+		WithArgs("vid-1", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "prompt", "options", "correct_option", "option_index", "count", "correct"}))
+	mock.ExpectQuery(`SELECT email, created_at, form_responses FROM video_viewers`).
+		WithArgs("vid-1", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "created_at", "form_responses"}).
+			AddRow("alice@example.com", identifiedAt, `{"company":"Acme, Inc.","consent":true}`).
+			AddRow("bob@example.com", identifiedAt, `{}`))
+	mock.ExpectQuery(`SELECT email_gate_fields FROM videos WHERE id = \$1`).
+		WithArgs("vid-1").
+		WillReturnRows(pgxmock.NewRows([]string{"email_gate_fields"}).
+			AddRow(`[{"key":"company","type":"text","label":"Company"},{"key":"consent","type":"checkbox","label":"Consent"}]`))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics/export", handler.AnalyticsExport)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/vid-1/analytics/export?range=7d", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"Email,Identified At,Company,Consent\n",
+		"alice@example.com,2026-03-02T09:30:00Z,\"Acme, Inc.\",yes\n",
+		"bob@example.com,2026-03-02T09:30:00Z,,\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected CSV to contain %q, got:\n%s", want, body)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -35,6 +35,17 @@ type SlackNotifier interface {
 	SendCommentNotification(ctx context.Context, toEmail, toName, videoTitle, commentAuthor, commentBody, watchURL string) error
 }
 
+// ThreadReplyNotifier tells earlier participants in a comment thread about a
+// new reply, with a link that stops further emails for that thread.
+type ThreadReplyNotifier interface {
+	SendThreadReplyNotification(ctx context.Context, toEmail, toName, videoTitle, commentAuthor, commentBody, watchURL, unsubscribeURL string) error
+}
+
+// ViewerVerifier delivers the one-time code and magic link for verified email gates.
+type ViewerVerifier interface {
+	SendViewerVerification(ctx context.Context, toEmail, videoTitle, code, verifyLink string) error
+}
+
 type GeoResolver interface {
 	Lookup(ip string) (country, city string)
 }
@@ -54,11 +65,19 @@ type Handler struct {
 	slackNotifier           SlackNotifier
 	brandingEnabled         bool
 	analyticsScript         string
+	analyticsPrivacyMode    bool
+	salts                   saltCache
 	aiEnabled               bool
 	transcriptionEnabled    bool
 	noiseReductionFilter    string
 	webhookClient           *webhook.Client
 	geoResolver             GeoResolver
+	liveHub                 *LiveHub
+	viewerVerifier          ViewerVerifier
+	threadReplyNotifier     ThreadReplyNotifier
+	spamScorer              SpamScorer
+	videoReplyMaxBytes      int64
+	videoReplyMaxSeconds    int
 }
 
 func NewHandler(db database.DBTX, s ObjectStorage, baseURL string, maxUploadBytes int64, maxVideosPerMonth int, maxVideoDurationSeconds int, maxPlaylists int, hmacSecret string, secureCookies bool) *Handler {
@@ -95,6 +114,10 @@ func (h *Handler) SetAnalyticsScript(script string) {
 	h.analyticsScript = script
 }
 
+func (h *Handler) SetAnalyticsPrivacyMode(enabled bool) {
+	h.analyticsPrivacyMode = enabled
+}
+
 func (h *Handler) SetAIEnabled(enabled bool) {
 	h.aiEnabled = enabled
 }
@@ -115,6 +138,27 @@ func (h *Handler) SetGeoResolver(r GeoResolver) {
 	h.geoResolver = r
 }
 
+func (h *Handler) SetLiveHub(hub *LiveHub) {
+	h.liveHub = hub
+}
+
+func (h *Handler) SetViewerVerifier(v ViewerVerifier) {
+	h.viewerVerifier = v
+}
+
+func (h *Handler) SetThreadReplyNotifier(n ThreadReplyNotifier) {
+	h.threadReplyNotifier = n
+}
+
+func (h *Handler) SetSpamScorer(s SpamScorer) {
+	h.spamScorer = s
+}
+
+func (h *Handler) SetVideoReplyLimits(maxBytes int64, maxSeconds int) {
+	h.videoReplyMaxBytes = maxBytes
+	h.videoReplyMaxSeconds = maxSeconds
+}
+
 func extensionForContentType(ct string) string {
 	switch ct {
 	case "video/mp4":
@@ -53,10 +53,14 @@ func (h *Handler) EnqueueJob(ctx context.Context, jobType JobType, videoID strin
 		}()
 	case JobTypeProbe:
 		fileKey, _ := payload["fileKey"].(string)
+		maxDuration, _ := payload["maxDuration"].(int)
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 			defer cancel()
 			probeDuration(ctx, h.db, h.storage, videoID, fileKey)
+			if maxDuration > 0 {
+				rejectOverlongVideoReply(ctx, h.db, videoID, maxDuration)
+			}
 		}()
 	case JobTypeComposite:
 		fileKey, _ := payload["fileKey"].(string)
@@ -0,0 +1,431 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	liveChannel = "video_live"
+
+	liveEventView      = "view"
+	liveEventMilestone = "milestone"
+	liveEventCTAClick  = "cta_click"
+	liveEventComment   = "comment"
+	liveEventPresence  = "presence"
+	// liveEventHeartbeat is published for every segment beacon and only feeds
+	// presence; subscribers never see it.
+	liveEventHeartbeat = "heartbeat"
+
+	// livePresenceWindow is how long a viewer counts as watching after their
+	// last segment beacon. Players flush beacons every five seconds.
+	livePresenceWindow = 20 * time.Second
+	liveKeepAlive      = 15 * time.Second
+	liveBuffer         = 32
+	// liveCommentPreview keeps comment payloads well under the 8000 byte
+	// limit Postgres puts on notifications.
+	liveCommentPreview = 280
+)
+
+// liveNotification is the payload sent through Postgres, so events recorded
+// on one replica reach streams held open on any other.
+type liveNotification struct {
+	Type       string          `json:"type"`
+	VideoID    string          `json:"videoId"`
+	UserID     string          `json:"userId"`
+	OrgID      *string         `json:"orgId"`
+	Title      string          `json:"title"`
+	ViewerHash string          `json:"viewerHash"`
+	Data       json.RawMessage `json:"data"`
+	At         time.Time       `json:"at"`
+}
+
+type liveEvent struct {
+	Type     string          `json:"type"`
+	VideoID  string          `json:"videoId"`
+	Title    string          `json:"title,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+	Watching *int            `json:"watching,omitempty"`
+	At       time.Time       `json:"at"`
+}
+
+// liveScope is who may see a video's events: its owner, or its organization.
+type liveScope struct {
+	userID string
+	orgID  string
+}
+
+type liveSubscriber struct {
+	videoID string
+	userID  string
+	orgID   string
+	events  chan liveEvent
+}
+
+func (s *liveSubscriber) wants(videoID string, scope liveScope) bool {
+	if s.videoID != "" {
+		return s.videoID == videoID
+	}
+	if s.orgID != "" {
+		return scope.orgID == s.orgID
+	}
+	return scope.userID == s.userID
+}
+
+type livePresence struct {
+	scope   liveScope
+	title   string
+	viewers map[string]time.Time
+}
+
+// LiveHub fans live analytics events out to the streams open on this replica
+// and tracks who is currently watching each video.
+type LiveHub struct {
+	mu          sync.Mutex
+	subscribers map[*liveSubscriber]struct{}
+	presence    map[string]*livePresence
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewLiveHub() *LiveHub {
+	return &LiveHub{
+		subscribers: make(map[*liveSubscriber]struct{}),
+		presence:    make(map[string]*livePresence),
+		closed:      make(chan struct{}),
+	}
+}
+
+// Close ends every open stream so a graceful shutdown isn't held up by them.
+// Clients reconnect to another replica.
+func (hub *LiveHub) Close() {
+	hub.closeOnce.Do(func() { close(hub.closed) })
+}
+
+func (hub *LiveHub) subscribe(sub *liveSubscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.subscribers[sub] = struct{}{}
+}
+
+func (hub *LiveHub) unsubscribe(sub *liveSubscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	delete(hub.subscribers, sub)
+}
+
+// dispatch delivers a notification to matching subscribers. Heartbeats only
+// update presence, which is announced when the number of viewers changes.
+func (hub *LiveHub) dispatch(n liveNotification, now time.Time) {
+	scope := liveScope{userID: n.UserID}
+	if n.OrgID != nil {
+		scope.orgID = *n.OrgID
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if n.Type == liveEventHeartbeat {
+		if n.ViewerHash == "" {
+			return
+		}
+		p := hub.presence[n.VideoID]
+		if p == nil {
+			p = &livePresence{viewers: make(map[string]time.Time)}
+			hub.presence[n.VideoID] = p
+		}
+		p.scope = scope
+		p.title = n.Title
+		_, known := p.viewers[n.ViewerHash]
+		p.viewers[n.ViewerHash] = now
+		if !known {
+			hub.broadcast(n.VideoID, scope, presenceEvent(n.VideoID, p, now))
+		}
+		return
+	}
+
+	hub.broadcast(n.VideoID, scope, liveEvent{
+		Type:    n.Type,
+		VideoID: n.VideoID,
+		Title:   n.Title,
+		Data:    n.Data,
+		At:      n.At,
+	})
+}
+
+// prune forgets viewers whose beacons stopped and announces the new counts.
+func (hub *LiveHub) prune(now time.Time) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	for videoID, p := range hub.presence {
+		changed := false
+		for viewer, seen := range p.viewers {
+			if now.Sub(seen) > livePresenceWindow {
+				delete(p.viewers, viewer)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		hub.broadcast(videoID, p.scope, presenceEvent(videoID, p, now))
+		if len(p.viewers) == 0 {
+			delete(hub.presence, videoID)
+		}
+	}
+}
+
+// presenceFor returns the current presence of every video a new subscriber
+// can see, so the stream starts with an accurate count.
+func (hub *LiveHub) presenceFor(sub *liveSubscriber, now time.Time) []liveEvent {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	var events []liveEvent
+	for videoID, p := range hub.presence {
+		if len(p.viewers) > 0 && sub.wants(videoID, p.scope) {
+			events = append(events, presenceEvent(videoID, p, now))
+		}
+	}
+	if len(events) == 0 && sub.videoID != "" {
+		watching := 0
+		events = append(events, liveEvent{Type: liveEventPresence, VideoID: sub.videoID, Watching: &watching, At: now})
+	}
+	return events
+}
+
+// broadcast must be called with hub.mu held. Subscribers that fall behind
+// lose events rather than stall everyone else.
+func (hub *LiveHub) broadcast(videoID string, scope liveScope, e liveEvent) {
+	for sub := range hub.subscribers {
+		if !sub.wants(videoID, scope) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+		}
+	}
+}
+
+func presenceEvent(videoID string, p *livePresence, now time.Time) liveEvent {
+	watching := len(p.viewers)
+	return liveEvent{Type: liveEventPresence, VideoID: videoID, Title: p.title, Watching: &watching, At: now}
+}
+
+// StartLiveListener relays live analytics notifications from Postgres into
+// the hub and expires stale presence. It holds one pooled connection for
+// LISTEN and reconnects with backoff if that connection drops.
+func StartLiveListener(ctx context.Context, pool *pgxpool.Pool, hub *LiveHub) {
+	go func() {
+		slog.Info("live-analytics: started")
+		backoff := time.Second
+		for {
+			listening, err := listenLive(ctx, pool, hub)
+			if ctx.Err() != nil {
+				slog.Info("live-analytics: shutting down")
+				return
+			}
+			if listening {
+				backoff = time.Second
+			}
+			slog.Error("live-analytics: listener stopped", "error", err, "retry_in", backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 30*time.Second)
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				hub.prune(now)
+			}
+		}
+	}()
+}
+
+func listenLive(ctx context.Context, pool *pgxpool.Pool, hub *LiveHub) (bool, error) {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquire connection: %w", err)
+	}
+	// A listening connection must never go back to the pool.
+	conn := pooled.Hijack()
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+liveChannel); err != nil {
+		return false, fmt.Errorf("listen: %w", err)
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("wait for notification: %w", err)
+		}
+		var n liveNotification
+		if err := json.Unmarshal([]byte(notification.Payload), &n); err != nil {
+			slog.Error("live-analytics: invalid notification", "error", err)
+			continue
+		}
+		hub.dispatch(n, time.Now())
+	}
+}
+
+// publishLive announces an analytics event to live streams on every replica.
+// The owner and title are filled in by the same statement that sends it.
+func (h *Handler) publishLive(ctx context.Context, eventType, videoID, viewerHash string, data any) {
+	if h.liveHub == nil {
+		return
+	}
+	var dataArg any
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return
+		}
+		dataArg = string(raw)
+	}
+	if _, err := h.db.Exec(ctx,
+		`SELECT pg_notify($1, json_build_object(
+		     'type', $2::text, 'videoId', v.id, 'userId', v.user_id, 'orgId', v.organization_id,
+		     'title', v.title, 'viewerHash', $3::text, 'data', $4::jsonb, 'at', now())::text)
+		 FROM videos v WHERE v.id = $5`,
+		liveChannel, eventType, viewerHash, dataArg, videoID,
+	); err != nil {
+		slog.Error("live-analytics: failed to publish event", "type", eventType, "video_id", videoID, "error", err)
+	}
+}
+
+// publishLiveComment announces a published comment, trimming its body to a
+// preview.
+func (h *Handler) publishLiveComment(ctx context.Context, videoID, commentID, author, body string) {
+	if author == "" {
+		author = "Anonymous"
+	}
+	if runes := []rune(body); len(runes) > liveCommentPreview {
+		body = string(runes[:liveCommentPreview]) + "…"
+	}
+	h.publishLive(ctx, liveEventComment, videoID, "", map[string]string{
+		"commentId": commentID,
+		"author":    author,
+		"body":      body,
+	})
+}
+
+// AnalyticsLive streams a video's analytics events as they are recorded.
+func (h *Handler) AnalyticsLive(w http.ResponseWriter, r *http.Request) {
+	if h.liveHub == nil {
+		httputil.WriteError(w, http.StatusServiceUnavailable, "live analytics are not enabled")
+		return
+	}
+	userID := auth.UserIDFromContext(r.Context())
+	videoID := chi.URLParam(r, "id")
+
+	orgID := auth.OrgIDFromContext(r.Context())
+	var verifyQuery string
+	var verifyArgs []any
+	if orgID != "" {
+		verifyQuery = `SELECT id FROM videos WHERE id = $1 AND organization_id = $2 AND status != 'deleted'`
+		verifyArgs = []any{videoID, orgID}
+	} else {
+		verifyQuery = `SELECT id FROM videos WHERE id = $1 AND user_id = $2 AND status != 'deleted'`
+		verifyArgs = []any{videoID, userID}
+	}
+	var id string
+	if err := h.db.QueryRow(r.Context(), verifyQuery, verifyArgs...).Scan(&id); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	h.streamLive(w, r, &liveSubscriber{videoID: id, events: make(chan liveEvent, liveBuffer)})
+}
+
+// DashboardLive streams analytics events for every video in the caller's
+// current scope.
+func (h *Handler) DashboardLive(w http.ResponseWriter, r *http.Request) {
+	if h.liveHub == nil {
+		httputil.WriteError(w, http.StatusServiceUnavailable, "live analytics are not enabled")
+		return
+	}
+	h.streamLive(w, r, &liveSubscriber{
+		userID: auth.UserIDFromContext(r.Context()),
+		orgID:  auth.OrgIDFromContext(r.Context()),
+		events: make(chan liveEvent, liveBuffer),
+	})
+}
+
+func (h *Handler) streamLive(w http.ResponseWriter, r *http.Request, sub *liveSubscriber) {
+	rc := http.NewResponseController(w)
+	// Streams outlive the server's read and write timeouts.
+	for _, setDeadline := range []func(time.Time) error{rc.SetReadDeadline, rc.SetWriteDeadline} {
+		if err := setDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.Error("live-analytics: failed to clear connection deadline", "error", err)
+		}
+	}
+
+	h.liveHub.subscribe(sub)
+	defer h.liveHub.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, e := range h.liveHub.presenceFor(sub, time.Now()) {
+		if err := writeLiveEvent(w, e); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(liveKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.liveHub.closed:
+			return
+		case e := <-sub.events:
+			if err := writeLiveEvent(w, e); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeLiveEvent(w http.ResponseWriter, e liveEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, payload)
+	return err
+}
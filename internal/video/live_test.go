@@ -0,0 +1,261 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/auth"
+)
+
+func receiveLive(t *testing.T, sub *liveSubscriber) liveEvent {
+	t.Helper()
+	select {
+	case e := <-sub.events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("expected a live event")
+		return liveEvent{}
+	}
+}
+
+func expectNoLive(t *testing.T, sub *liveSubscriber) {
+	t.Helper()
+	select {
+	case e := <-sub.events:
+		t.Fatalf("expected no live event, got %+v", e)
+	default:
+	}
+}
+
+func TestLiveHub_RoutesEventsByScope(t *testing.T) {
+	hub := NewLiveHub()
+	videoSub := &liveSubscriber{videoID: "vid-1", events: make(chan liveEvent, liveBuffer)}
+	ownerSub := &liveSubscriber{userID: "owner-1", events: make(chan liveEvent, liveBuffer)}
+	orgSub := &liveSubscriber{userID: "member-1", orgID: "org-1", events: make(chan liveEvent, liveBuffer)}
+	otherSub := &liveSubscriber{userID: "owner-2", events: make(chan liveEvent, liveBuffer)}
+	for _, sub := range []*liveSubscriber{videoSub, ownerSub, orgSub, otherSub} {
+		hub.subscribe(sub)
+	}
+
+	orgID := "org-1"
+	hub.dispatch(liveNotification{
+		Type:    liveEventMilestone,
+		VideoID: "vid-1",
+		UserID:  "owner-1",
+		OrgID:   &orgID,
+		Title:   "Demo",
+		Data:    json.RawMessage(`{"milestone":50}`),
+	}, time.Now())
+
+	for _, sub := range []*liveSubscriber{videoSub, ownerSub, orgSub} {
+		e := receiveLive(t, sub)
+		if e.Type != liveEventMilestone || e.VideoID != "vid-1" || string(e.Data) != `{"milestone":50}` {
+			t.Errorf("unexpected event %+v", e)
+		}
+	}
+	expectNoLive(t, otherSub)
+}
+
+func TestLiveHub_PresenceFromHeartbeats(t *testing.T) {
+	hub := NewLiveHub()
+	sub := &liveSubscriber{videoID: "vid-1", events: make(chan liveEvent, liveBuffer)}
+	hub.subscribe(sub)
+
+	start := time.Now()
+	heartbeat := func(viewer string, at time.Time) {
+		hub.dispatch(liveNotification{Type: liveEventHeartbeat, VideoID: "vid-1", UserID: "owner-1", ViewerHash: viewer}, at)
+	}
+
+	heartbeat("viewer-1", start)
+	if e := receiveLive(t, sub); e.Type != liveEventPresence || *e.Watching != 1 {
+		t.Errorf("expected 1 watching, got %+v", e)
+	}
+	heartbeat("viewer-1", start.Add(5*time.Second))
+	expectNoLive(t, sub)
+
+	heartbeat("viewer-2", start.Add(10*time.Second))
+	if e := receiveLive(t, sub); *e.Watching != 2 {
+		t.Errorf("expected 2 watching, got %d", *e.Watching)
+	}
+
+	hub.prune(start.Add(5*time.Second + livePresenceWindow + time.Second))
+	if e := receiveLive(t, sub); *e.Watching != 1 {
+		t.Errorf("expected viewer-1 to expire, got %d watching", *e.Watching)
+	}
+
+	late := &liveSubscriber{userID: "owner-1", events: make(chan liveEvent, liveBuffer)}
+	snapshot := hub.presenceFor(late, start.Add(30*time.Second))
+	if len(snapshot) != 1 || *snapshot[0].Watching != 1 {
+		t.Errorf("expected a presence snapshot of 1 watching, got %+v", snapshot)
+	}
+}
+
+func TestPublishLive_NotifiesThroughPostgres(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetLiveHub(NewLiveHub())
+
+	mock.ExpectExec(`SELECT pg_notify\(\$1, json_build_object\(`).
+		WithArgs(liveChannel, liveEventMilestone, "viewer-1", `{"milestone":75}`, "vid-1").
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	handler.publishLive(context.Background(), liveEventMilestone, "vid-1", "viewer-1", map[string]int{"milestone": 75})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestPublishLive_DisabledWithoutHub(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.publishLive(context.Background(), liveEventView, "vid-1", "viewer-1", nil)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected no queries: %v", err)
+	}
+}
+
+func TestPublishLiveComment_TrimsBody(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetLiveHub(NewLiveHub())
+
+	preview, _ := json.Marshal(map[string]string{
+		"author":    "Anonymous",
+		"body":      strings.Repeat("a", liveCommentPreview) + "…",
+		"commentId": "c-1",
+	})
+	mock.ExpectExec(`SELECT pg_notify`).
+		WithArgs(liveChannel, liveEventComment, "", string(preview), "vid-1").
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	handler.publishLiveComment(context.Background(), "vid-1", "c-1", "", strings.Repeat("a", 1000))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestAnalyticsLive_NotEnabled(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics/live", handler.AnalyticsLive)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/vid-1/analytics/live", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+func TestAnalyticsLive_NotOwner(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetLiveHub(NewLiveHub())
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("vid-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics/live", handler.AnalyticsLive)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/vid-1/analytics/live", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestDashboardLive_StreamsOrgEvents(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	hub := NewLiveHub()
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetLiveHub(hub)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/analytics/dashboard/live", handler.DashboardLive)
+
+	req := authenticatedRequest(t, http.MethodGet, "/api/analytics/dashboard/live", nil)
+	req = req.WithContext(auth.ContextWithOrg(req.Context(), testOrgID, "member"))
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		r.ServeHTTP(rec, req)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		hub.mu.Lock()
+		subscribed := len(hub.subscribers) == 1
+		hub.mu.Unlock()
+		if subscribed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stream never subscribed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	orgID := testOrgID
+	hub.dispatch(liveNotification{Type: liveEventView, VideoID: "vid-1", UserID: "someone-else", OrgID: &orgID, Title: "Demo"}, time.Now())
+	hub.dispatch(liveNotification{Type: liveEventView, VideoID: "vid-2", UserID: testUserID, Title: "Personal"}, time.Now())
+	time.Sleep(50 * time.Millisecond)
+	hub.Close()
+	<-done
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "event: view\ndata: {\"type\":\"view\",\"videoId\":\"vid-1\",\"title\":\"Demo\"") {
+		t.Errorf("expected the org video's view in the stream, got %q", body)
+	}
+	if strings.Contains(body, "vid-2") {
+		t.Error("expected personal videos to stay out of the org stream")
+	}
+}
@@ -403,7 +403,7 @@ func (h *Handler) resolveAndNotify(ctx context.Context, videoID, ownerID, ownerE
 		return
 	}
 
-	watchURL := h.baseURL + "/watch/" + shareToken
+	watchURL := h.shareWatchURL(ctx, shareToken)
 
 	// Slack: always send (Slack client gates on webhook URL presence in DB)
 	if h.slackNotifier != nil {
@@ -1,30 +1,168 @@
 package video
 
 import (
+	"encoding/xml"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/sendrec/sendrec/internal/httputil"
 )
 
+const (
+	oEmbedProviderName = "SendRec"
+	oEmbedCacheAge     = 3600
+	// Thumbnails are rendered 640 pixels wide from 16:9 recordings.
+	oEmbedThumbnailWidth  = 640
+	oEmbedThumbnailHeight = 360
+)
+
+// oEmbedResponse carries the oEmbed 1.0 fields plus the camelCase fields the
+// original per-video endpoint returned, which existing integrations read.
 type oEmbedResponse struct {
-	Type         string `json:"type"`
-	Version      string `json:"version"`
-	Title        string `json:"title"`
-	Duration     int    `json:"duration"`
-	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
-	AuthorName   string `json:"authorName"`
-	WatchURL     string `json:"watchUrl"`
-	HTML         string `json:"html"`
-	Width        int    `json:"width"`
-	Height       int    `json:"height"`
-	CreatedAt    string `json:"createdAt"`
+	XMLName            xml.Name `json:"-" xml:"oembed"`
+	Type               string   `json:"type" xml:"type"`
+	Version            string   `json:"version" xml:"version"`
+	Title              string   `json:"title" xml:"title"`
+	OEmbedAuthorName   string   `json:"author_name" xml:"author_name"`
+	ProviderName       string   `json:"provider_name" xml:"provider_name"`
+	ProviderURL        string   `json:"provider_url" xml:"provider_url"`
+	CacheAge           int      `json:"cache_age" xml:"cache_age"`
+	OEmbedThumbnailURL string   `json:"thumbnail_url,omitempty" xml:"thumbnail_url,omitempty"`
+	ThumbnailWidth     int      `json:"thumbnail_width,omitempty" xml:"thumbnail_width,omitempty"`
+	ThumbnailHeight    int      `json:"thumbnail_height,omitempty" xml:"thumbnail_height,omitempty"`
+	HTML               string   `json:"html" xml:"html"`
+	Width              int      `json:"width" xml:"width"`
+	Height             int      `json:"height" xml:"height"`
+
+	Duration     int    `json:"duration" xml:"-"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty" xml:"-"`
+	AuthorName   string `json:"authorName" xml:"-"`
+	WatchURL     string `json:"watchUrl" xml:"-"`
+	CreatedAt    string `json:"createdAt" xml:"-"`
+}
+
+// oEmbedOptions are the consumer's format and size constraints.
+type oEmbedOptions struct {
+	Format    string
+	MaxWidth  int
+	MaxHeight int
+}
+
+// parseOEmbedOptions reads format, maxwidth and maxheight. It returns false
+// for a format other than json or xml, which the spec answers with 501.
+func parseOEmbedOptions(q url.Values) (oEmbedOptions, bool) {
+	opts := oEmbedOptions{Format: strings.ToLower(q.Get("format"))}
+	if opts.Format == "" {
+		opts.Format = "json"
+	}
+	if opts.Format != "json" && opts.Format != "xml" {
+		return opts, false
+	}
+	if n, err := strconv.Atoi(q.Get("maxwidth")); err == nil && n > 0 {
+		opts.MaxWidth = n
+	}
+	if n, err := strconv.Atoi(q.Get("maxheight")); err == nil && n > 0 {
+		opts.MaxHeight = n
+	}
+	return opts, true
+}
+
+// fitEmbedSize shrinks a width/height pair to the consumer's maximums while
+// keeping its aspect ratio.
+func fitEmbedSize(width, height int, opts oEmbedOptions) (int, int) {
+	if opts.MaxWidth > 0 && width > opts.MaxWidth {
+		height = height * opts.MaxWidth / width
+		width = opts.MaxWidth
+	}
+	if opts.MaxHeight > 0 && height > opts.MaxHeight {
+		width = width * opts.MaxHeight / height
+		height = opts.MaxHeight
+	}
+	return width, height
+}
+
+func writeOEmbed(w http.ResponseWriter, resp oEmbedResponse, opts oEmbedOptions) {
+	if opts.Format != "xml" {
+		httputil.WriteJSON(w, http.StatusOK, resp)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	if err := xml.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("oembed: failed to encode xml", "error", err)
+	}
+}
+
+// oEmbedTarget parses the url parameter of an oEmbed request into a share
+// reference. Only watch and embed links on the host serving the request are
+// recognised, so a custom domain answers for its own links alone.
+func (h *Handler) oEmbedTarget(r *http.Request, raw string) (ref string, playlist bool, ok bool) {
+	target, err := url.Parse(raw)
+	if err != nil || target.Host == "" {
+		return "", false, false
+	}
+	base, err := url.Parse(h.publicBaseURL(r))
+	if err != nil || !strings.EqualFold(target.Hostname(), base.Hostname()) {
+		return "", false, false
+	}
+
+	path := strings.TrimSuffix(target.Path, "/")
+	for _, prefix := range []string{"/watch/playlist/", "/embed/playlist/"} {
+		if rest, found := strings.CutPrefix(path, prefix); found {
+			return rest, true, rest != "" && !strings.Contains(rest, "/")
+		}
+	}
+	for _, prefix := range []string{"/watch/", "/embed/"} {
+		if rest, found := strings.CutPrefix(path, prefix); found {
+			return rest, false, rest != "" && !strings.Contains(rest, "/")
+		}
+	}
+	return "", false, false
+}
+
+// OEmbedByURL is the spec-style oEmbed endpoint: consumers pass the link being
+// unfurled as ?url= and it answers for both videos and playlists.
+func (h *Handler) OEmbedByURL(w http.ResponseWriter, r *http.Request) {
+	opts, ok := parseOEmbedOptions(r.URL.Query())
+	if !ok {
+		httputil.WriteError(w, http.StatusNotImplemented, "format must be json or xml")
+		return
+	}
+	rawURL := r.URL.Query().Get("url")
+	if rawURL == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+	ref, playlist, ok := h.oEmbedTarget(r, rawURL)
+	if !ok {
+		httputil.WriteError(w, http.StatusNotFound, "no embeddable content at that url")
+		return
+	}
+
+	shareToken, _ := h.resolveShareRefValue(r, ref, playlist)
+	if playlist {
+		h.writePlaylistOEmbed(w, r, shareToken, opts)
+		return
+	}
+	h.writeVideoOEmbed(w, r, shareToken, opts)
 }
 
 func (h *Handler) OEmbed(w http.ResponseWriter, r *http.Request) {
-	shareToken := chi.URLParam(r, "shareToken")
+	opts, ok := parseOEmbedOptions(r.URL.Query())
+	if !ok {
+		httputil.WriteError(w, http.StatusNotImplemented, "format must be json or xml")
+		return
+	}
+	shareToken, _ := h.resolveShareRef(r, false)
+	h.writeVideoOEmbed(w, r, shareToken, opts)
+}
 
+func (h *Handler) writeVideoOEmbed(w http.ResponseWriter, r *http.Request, shareToken string, opts oEmbedOptions) {
 	var title string
 	var duration int
 	var authorName string
@@ -33,14 +171,17 @@ func (h *Handler) OEmbed(w http.ResponseWriter, r *http.Request) {
 	var thumbnailKey *string
 	var sharePassword *string
 	var emailGateEnabled bool
+	var visibility string
 
 	err := h.db.QueryRow(r.Context(),
-		`SELECT v.title, v.duration, u.name, v.created_at, v.share_expires_at, v.thumbnail_key, v.share_password, v.email_gate_enabled
+		`SELECT v.title, v.duration, u.name, v.created_at, v.share_expires_at, v.thumbnail_key, v.share_password, v.email_gate_enabled,
+		        COALESCE(v.visibility, f.visibility, 'public')
 		 FROM videos v
 		 JOIN users u ON u.id = v.user_id
+		 LEFT JOIN folders f ON f.id = v.folder_id
 		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
 		shareToken,
-	).Scan(&title, &duration, &authorName, &createdAt, &shareExpiresAt, &thumbnailKey, &sharePassword, &emailGateEnabled)
+	).Scan(&title, &duration, &authorName, &createdAt, &shareExpiresAt, &thumbnailKey, &sharePassword, &emailGateEnabled, &visibility)
 	if err != nil {
 		httputil.WriteError(w, http.StatusNotFound, "video not found")
 		return
@@ -53,6 +194,9 @@ func (h *Handler) OEmbed(w http.ResponseWriter, r *http.Request) {
 
 	// Title, author and thumbnail are content too — unfurling a protected link
 	// must not reveal them (SR-08).
+	if !h.enforceVisibility(w, r, shareToken, visibility) {
+		return
+	}
 	if !h.enforceWatchAccess(w, r, shareToken, sharePassword, emailGateEnabled) {
 		return
 	}
@@ -64,20 +208,86 @@ func (h *Handler) OEmbed(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	embedURL := h.baseURL + "/embed/" + shareToken
-	iframeHTML := `<iframe src="` + embedURL + `" width="640" height="360" frameborder="0" allowfullscreen></iframe>`
-
-	httputil.WriteJSON(w, http.StatusOK, oEmbedResponse{
-		Type:         "video",
-		Version:      "1.0",
-		Title:        title,
-		Duration:     duration,
-		ThumbnailURL: thumbnailURL,
-		AuthorName:   authorName,
-		WatchURL:     h.baseURL + "/watch/" + shareToken,
-		HTML:         iframeHTML,
-		Width:        640,
-		Height:       360,
-		CreatedAt:    createdAt.Format(time.RFC3339),
-	})
+	baseURL := h.publicBaseURL(r)
+	width, height := fitEmbedSize(640, 360, opts)
+	resp := oEmbedResponse{
+		Type:             "video",
+		Version:          "1.0",
+		Title:            title,
+		OEmbedAuthorName: authorName,
+		ProviderName:     oEmbedProviderName,
+		ProviderURL:      baseURL,
+		CacheAge:         oEmbedCacheAge,
+		HTML:             embedIframe(baseURL+"/embed/"+shareToken, width, height),
+		Width:            width,
+		Height:           height,
+		Duration:         duration,
+		ThumbnailURL:     thumbnailURL,
+		AuthorName:       authorName,
+		WatchURL:         baseURL + "/watch/" + shareToken,
+		CreatedAt:        createdAt.Format(time.RFC3339),
+	}
+	if thumbnailURL != "" {
+		resp.OEmbedThumbnailURL = thumbnailURL
+		resp.ThumbnailWidth = oEmbedThumbnailWidth
+		resp.ThumbnailHeight = oEmbedThumbnailHeight
+	}
+	writeOEmbed(w, resp, opts)
+}
+
+func (h *Handler) writePlaylistOEmbed(w http.ResponseWriter, r *http.Request, shareToken string, opts oEmbedOptions) {
+	var title, authorName string
+	var createdAt time.Time
+	var sharePassword *string
+	var requireEmail bool
+	var thumbnailToken *string
+
+	err := h.db.QueryRow(r.Context(),
+		`SELECT p.title, u.name, p.created_at, p.share_password, p.require_email,
+		        (SELECT v.share_token FROM playlist_videos pv
+		         JOIN videos v ON v.id = pv.video_id AND v.status IN ('ready', 'processing') AND v.thumbnail_key IS NOT NULL
+		         LEFT JOIN folders f ON f.id = v.folder_id
+		         WHERE pv.playlist_id = p.id AND COALESCE(v.visibility, f.visibility, 'public') = 'public'
+		         ORDER BY pv.position, v.created_at LIMIT 1)
+		 FROM playlists p
+		 JOIN users u ON u.id = p.user_id
+		 WHERE p.share_token = $1 AND p.is_shared = true`,
+		shareToken,
+	).Scan(&title, &authorName, &createdAt, &sharePassword, &requireEmail, &thumbnailToken)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "playlist not found")
+		return
+	}
+	if !h.enforceWatchAccess(w, r, shareToken, sharePassword, requireEmail) {
+		return
+	}
+
+	baseURL := h.publicBaseURL(r)
+	width, height := fitEmbedSize(800, 450, opts)
+	resp := oEmbedResponse{
+		Type:             "video",
+		Version:          "1.0",
+		Title:            title,
+		OEmbedAuthorName: authorName,
+		ProviderName:     oEmbedProviderName,
+		ProviderURL:      baseURL,
+		CacheAge:         oEmbedCacheAge,
+		HTML:             embedIframe(baseURL+"/embed/playlist/"+shareToken, width, height),
+		Width:            width,
+		Height:           height,
+		AuthorName:       authorName,
+		WatchURL:         baseURL + "/watch/playlist/" + shareToken,
+		CreatedAt:        createdAt.Format(time.RFC3339),
+	}
+	if thumbnailToken != nil {
+		resp.OEmbedThumbnailURL = baseURL + "/api/watch/" + *thumbnailToken + "/thumbnail"
+		resp.ThumbnailURL = resp.OEmbedThumbnailURL
+		resp.ThumbnailWidth = oEmbedThumbnailWidth
+		resp.ThumbnailHeight = oEmbedThumbnailHeight
+	}
+	writeOEmbed(w, resp, opts)
+}
+
+func embedIframe(src string, width, height int) string {
+	return `<iframe src="` + src + `" width="` + strconv.Itoa(width) + `" height="` + strconv.Itoa(height) + `" frameborder="0" allowfullscreen></iframe>`
 }
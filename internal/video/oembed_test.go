@@ -0,0 +1,232 @@
+package video
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var oEmbedVideoColumns = []string{
+	"title", "duration", "name", "created_at", "share_expires_at", "thumbnail_key", "share_password", "email_gate_enabled", "visibility",
+}
+
+var oEmbedPlaylistColumns = []string{
+	"title", "name", "created_at", "share_password", "require_email", "thumbnail_token",
+}
+
+func serveOEmbedByURL(handler *Handler, query string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Get("/api/oembed", handler.OEmbedByURL)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/oembed?"+query, nil))
+	return rec
+}
+
+func TestFitEmbedSize(t *testing.T) {
+	tests := []struct {
+		opts          oEmbedOptions
+		width, height int
+	}{
+		{oEmbedOptions{}, 640, 360},
+		{oEmbedOptions{MaxWidth: 1000}, 640, 360},
+		{oEmbedOptions{MaxWidth: 320}, 320, 180},
+		{oEmbedOptions{MaxHeight: 180}, 320, 180},
+		{oEmbedOptions{MaxWidth: 480, MaxHeight: 90}, 160, 90},
+	}
+	for _, tt := range tests {
+		w, h := fitEmbedSize(640, 360, tt.opts)
+		if w != tt.width || h != tt.height {
+			t.Errorf("fitEmbedSize(640, 360, %+v) = %dx%d, want %dx%d", tt.opts, w, h, tt.width, tt.height)
+		}
+	}
+}
+
+func TestOEmbedByURL_Playlist(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	thumbToken := "vtoken1abcde"
+
+	mock.ExpectQuery(`SELECT p.title, u.name, p.created_at, p.share_password, p.require_email`).
+		WithArgs("pltoken12345").
+		WillReturnRows(pgxmock.NewRows(oEmbedPlaylistColumns).
+			AddRow("Onboarding", "Ada", time.Now(), (*string)(nil), false, &thumbToken))
+
+	target := url.QueryEscape(testBaseURL + "/watch/playlist/pltoken12345")
+	rec := serveOEmbedByURL(handler, "url="+target+"&maxwidth=400")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"type":          "video",
+		"version":       "1.0",
+		"title":         "Onboarding",
+		"author_name":   "Ada",
+		"provider_name": "SendRec",
+		"provider_url":  testBaseURL,
+		"thumbnail_url": testBaseURL + "/api/watch/vtoken1abcde/thumbnail",
+		"width":         float64(400),
+		"height":        float64(225),
+		"html":          `<iframe src="` + testBaseURL + `/embed/playlist/pltoken12345" width="400" height="225" frameborder="0" allowfullscreen></iframe>`,
+	}
+	for key, value := range want {
+		if resp[key] != value {
+			t.Errorf("%s = %v, want %v", key, resp[key], value)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestOEmbedByURL_VideoAsXML(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{downloadURL: "https://s3.example.com/thumb"}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.title, v.duration, u.name, v.created_at, v.share_expires_at, v.thumbnail_key`).
+		WithArgs("abc123defghi").
+		WillReturnRows(pgxmock.NewRows(oEmbedVideoColumns).
+			AddRow("Demo", 60, "Ada", time.Now(), (*time.Time)(nil), stringPtr("thumbnails/abc.jpg"), (*string)(nil), false, "public"))
+
+	target := url.QueryEscape(testBaseURL + "/embed/abc123defghi")
+	rec := serveOEmbedByURL(handler, "url="+target+"&format=xml&maxheight=180")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/xml; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	var resp struct {
+		XMLName      xml.Name `xml:"oembed"`
+		Type         string   `xml:"type"`
+		Title        string   `xml:"title"`
+		ThumbnailURL string   `xml:"thumbnail_url"`
+		Width        int      `xml:"width"`
+		Height       int      `xml:"height"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, rec.Body.String())
+	}
+	if resp.Type != "video" || resp.Title != "Demo" || resp.ThumbnailURL != "https://s3.example.com/thumb" || resp.Width != 320 || resp.Height != 180 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if strings.Contains(rec.Body.String(), "watchUrl") {
+		t.Error("legacy fields should not appear in xml")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestOEmbedByURL_ProtectedPlaylist(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	passwordHash, _ := hashSharePassword("secret123")
+
+	mock.ExpectQuery(`SELECT p.title, u.name, p.created_at, p.share_password, p.require_email`).
+		WithArgs("pwdtoken1234").
+		WillReturnRows(pgxmock.NewRows(oEmbedPlaylistColumns).
+			AddRow("Private", "Ada", time.Now(), &passwordHash, false, (*string)(nil)))
+
+	rec := serveOEmbedByURL(handler, "url="+url.QueryEscape(testBaseURL+"/watch/playlist/pwdtoken1234"))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "Private") {
+		t.Error("protected playlist title leaked")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestOEmbedByURL_RejectsUnknownURLs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"missing url", "", http.StatusBadRequest},
+		{"other host", "url=" + url.QueryEscape("https://example.com/watch/abc123defghi"), http.StatusNotFound},
+		{"not a share link", "url=" + url.QueryEscape(testBaseURL+"/settings"), http.StatusNotFound},
+		{"nested path", "url=" + url.QueryEscape(testBaseURL+"/watch/abc123defghi/verify-email"), http.StatusNotFound},
+		{"unsupported format", "url=" + url.QueryEscape(testBaseURL+"/watch/abc123defghi") + "&format=yaml", http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveOEmbedByURL(handler, tt.query)
+			if rec.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPlaylistWatchPage_EmitsOEmbedDiscovery(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT p.id, p.title, p.description, p.share_password, p.require_email`).
+		WithArgs("pltoken12345").
+		WillReturnRows(pgxmock.NewRows(playlistWatchColumns).
+			AddRow("playlist-1", "My Playlist", (*string)(nil), (*string)(nil), false))
+	mock.ExpectQuery(`SELECT v.id, v.title, v.duration, v.share_token, v.content_type, v.user_id`).
+		WithArgs("playlist-1").
+		WillReturnRows(pgxmock.NewRows(playlistVideosColumns))
+
+	rec := servePlaylistWatchPage(handler, playlistWatchRequest("pltoken12345"))
+
+	want := `<link rel="alternate" type="application/json+oembed" href="` + testBaseURL +
+		`/api/oembed?url=` + strings.ToLower(url.QueryEscape(testBaseURL)) + `/watch/playlist/pltoken12345&format=json"`
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("expected discovery link %q", want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,375 @@
+package video
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/httputil"
+	"github.com/sendrec/sendrec/internal/organization"
+)
+
+const orgTopVideoCount = 10
+
+// orgBreakdownRow is one member, folder or tag in the organization
+// dashboard. Email and Role are only set for members.
+type orgBreakdownRow struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Email            string  `json:"email,omitempty"`
+	Role             string  `json:"role,omitempty"`
+	Videos           int64   `json:"videos"`
+	NewVideos        int64   `json:"newVideos"`
+	Views            int64   `json:"views"`
+	UniqueViews      int64   `json:"uniqueViews"`
+	WatchTimeSeconds int64   `json:"watchTimeSeconds"`
+	CompletionRate   float64 `json:"completionRate"`
+	CtaClicks        int64   `json:"ctaClicks"`
+	completions      int64
+	videoViewers     int64
+}
+
+type orgAdoptionWeek struct {
+	WeekStart     string `json:"weekStart"`
+	UserID        string `json:"userId"`
+	VideosCreated int64  `json:"videosCreated"`
+}
+
+type orgTopVideo struct {
+	ID             string  `json:"id"`
+	Title          string  `json:"title"`
+	OwnerName      string  `json:"ownerName"`
+	Views          int64   `json:"views"`
+	UniqueViews    int64   `json:"uniqueViews"`
+	CompletionRate float64 `json:"completionRate"`
+	CtaClicks      int64   `json:"ctaClicks"`
+}
+
+type orgDashboardSummary struct {
+	TotalViews            int64   `json:"totalViews"`
+	UniqueViews           int64   `json:"uniqueViews"`
+	TotalWatchTimeSeconds int64   `json:"totalWatchTimeSeconds"`
+	TotalVideos           int64   `json:"totalVideos"`
+	NewVideos             int64   `json:"newVideos"`
+	Members               int     `json:"members"`
+	ActiveMembers         int     `json:"activeMembers"`
+	CompletionRate        float64 `json:"completionRate"`
+	CtaClicks             int64   `json:"ctaClicks"`
+}
+
+type orgDashboardResponse struct {
+	Summary   orgDashboardSummary `json:"summary"`
+	Members   []orgBreakdownRow   `json:"members"`
+	Folders   []orgBreakdownRow   `json:"folders"`
+	Tags      []orgBreakdownRow   `json:"tags"`
+	Adoption  []orgAdoptionWeek   `json:"adoption"`
+	TopVideos []orgTopVideo       `json:"topVideos"`
+}
+
+// orgVideoViewers lists each viewer of each organization video from $2
+// onwards once. Viewer hashes do not depend on the video, so a distinct count
+// over any set of these rows is that set's unique viewers.
+const orgVideoViewers = `viewers AS (
+		     SELECT DISTINCT vd.video_id, vd.viewer_hash
+		     FROM video_viewer_days vd
+		     JOIN videos sv ON sv.id = vd.video_id
+		     WHERE sv.organization_id = $1 AND sv.parent_video_id IS NULL AND vd.day >= $2
+		 )`
+
+// orgVideoStats totals the rollup per organization video from $2 onwards.
+const orgVideoStats = `WITH ` + orgVideoViewers + `, stats AS (
+		     SELECT ds.video_id, SUM(ds.views) AS views,
+		            (SELECT COUNT(*) FROM viewers w WHERE w.video_id = ds.video_id) AS unique_viewers,
+		            SUM(ds.watch_seconds) AS watch_seconds, SUM(ds.milestone_100) AS completions,
+		            SUM(ds.cta_clicks) AS cta_clicks
+		     FROM video_daily_stats ds
+		     JOIN videos sv ON sv.id = ds.video_id
+		     WHERE sv.organization_id = $1 AND sv.parent_video_id IS NULL AND ds.day >= $2
+		     GROUP BY ds.video_id
+		 )`
+
+// dashboardSince maps a dashboard range to its first day, or reports false
+// for an unknown range.
+func dashboardSince(rangeParam string, now time.Time) (time.Time, bool) {
+	switch rangeParam {
+	case "", "7d":
+		return now.AddDate(0, 0, -6), true
+	case "30d":
+		return now.AddDate(0, 0, -29), true
+	case "90d":
+		return now.AddDate(0, 0, -89), true
+	case "all":
+		return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// orgBreakdown groups organization videos by the columns in selectCols,
+// which must yield id, name, email and role. from joins videos v into the
+// grouping table. Unique viewers are counted across the group's videos;
+// completion rates are per video viewer, so they use the per-video counts.
+func orgBreakdown(ctx context.Context, db database.DBTX, selectCols, from, where, groupBy string, orgID string, since time.Time) ([]orgBreakdownRow, error) {
+	rows, err := db.Query(ctx,
+		orgVideoStats+fmt.Sprintf(`
+		 SELECT %s,
+		        COUNT(DISTINCT v.id), COUNT(DISTINCT v.id) FILTER (WHERE v.created_at >= $2),
+		        COALESCE(SUM(s.views), 0)::bigint,
+		        (SELECT COUNT(DISTINCT w.viewer_hash) FROM viewers w WHERE w.video_id = ANY(array_agg(v.id))),
+		        COALESCE(SUM(s.unique_viewers), 0)::bigint,
+		        COALESCE(SUM(s.watch_seconds), 0)::bigint, COALESCE(SUM(s.completions), 0)::bigint,
+		        COALESCE(SUM(s.cta_clicks), 0)::bigint
+		 FROM %s
+		 LEFT JOIN stats s ON s.video_id = v.id
+		 WHERE %s
+		 GROUP BY %s
+		 ORDER BY 7 DESC, 2`, selectCols, from, where, groupBy),
+		orgID, since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]orgBreakdownRow, 0)
+	for rows.Next() {
+		var row orgBreakdownRow
+		if err := rows.Scan(&row.ID, &row.Name, &row.Email, &row.Role, &row.Videos, &row.NewVideos,
+			&row.Views, &row.UniqueViews, &row.videoViewers, &row.WatchTimeSeconds, &row.completions, &row.CtaClicks); err != nil {
+			return nil, err
+		}
+		row.CompletionRate = min(percentOf(row.completions, row.videoViewers), 100)
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+func (h *Handler) orgDashboard(ctx context.Context, orgID string, since time.Time) (*orgDashboardResponse, error) {
+	members, err := orgBreakdown(ctx, h.db,
+		`u.id::text, u.name, u.email, om.role`,
+		`organization_members om
+		 JOIN users u ON u.id = om.user_id
+		 LEFT JOIN videos v ON v.user_id = om.user_id AND v.organization_id = $1 AND v.status != 'deleted'
+		     AND v.parent_video_id IS NULL`,
+		`om.organization_id = $1`,
+		`u.id, u.name, u.email, om.role`, orgID, since)
+	if err != nil {
+		return nil, fmt.Errorf("member breakdown: %w", err)
+	}
+
+	folders, err := orgBreakdown(ctx, h.db,
+		`f.id::text, f.name, '', ''`,
+		`folders f
+		 LEFT JOIN videos v ON v.folder_id = f.id AND v.status != 'deleted' AND v.parent_video_id IS NULL`,
+		`f.organization_id = $1`,
+		`f.id, f.name`, orgID, since)
+	if err != nil {
+		return nil, fmt.Errorf("folder breakdown: %w", err)
+	}
+
+	tags, err := orgBreakdown(ctx, h.db,
+		`t.id::text, t.name, '', ''`,
+		`tags t
+		 LEFT JOIN video_tags vt ON vt.tag_id = t.id
+		 LEFT JOIN videos v ON v.id = vt.video_id AND v.status != 'deleted' AND v.parent_video_id IS NULL`,
+		`t.organization_id = $1`,
+		`t.id, t.name`, orgID, since)
+	if err != nil {
+		return nil, fmt.Errorf("tag breakdown: %w", err)
+	}
+
+	// Adoption counts every video a member recorded, including ones they
+	// have since deleted, but not viewers' reply clips.
+	adoption := make([]orgAdoptionWeek, 0)
+	rows, err := h.db.Query(ctx,
+		`SELECT date_trunc('week', v.created_at AT TIME ZONE 'UTC')::date AS week, v.user_id::text, COUNT(*)
+		 FROM videos v
+		 WHERE v.organization_id = $1 AND v.created_at >= $2 AND v.parent_video_id IS NULL
+		 GROUP BY week, v.user_id
+		 ORDER BY week, v.user_id`,
+		orgID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("adoption: %w", err)
+	}
+	for rows.Next() {
+		var week time.Time
+		var a orgAdoptionWeek
+		if err := rows.Scan(&week, &a.UserID, &a.VideosCreated); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("adoption: %w", err)
+		}
+		a.WeekStart = week.Format("2006-01-02")
+		adoption = append(adoption, a)
+	}
+	rows.Close()
+
+	topVideos := make([]orgTopVideo, 0, orgTopVideoCount)
+	rows, err = h.db.Query(ctx,
+		orgVideoStats+fmt.Sprintf(`
+		 SELECT v.id::text, v.title, u.name, s.views::bigint, s.unique_viewers::bigint,
+		        s.completions::bigint, s.cta_clicks::bigint
+		 FROM stats s
+		 JOIN videos v ON v.id = s.video_id
+		 JOIN users u ON u.id = v.user_id
+		 WHERE v.status != 'deleted' AND s.views > 0
+		 ORDER BY s.views DESC, v.title
+		 LIMIT %d`, orgTopVideoCount),
+		orgID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("top videos: %w", err)
+	}
+	for rows.Next() {
+		var v orgTopVideo
+		var completions int64
+		if err := rows.Scan(&v.ID, &v.Title, &v.OwnerName, &v.Views, &v.UniqueViews, &completions, &v.CtaClicks); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("top videos: %w", err)
+		}
+		v.CompletionRate = min(percentOf(completions, v.UniqueViews), 100)
+		topVideos = append(topVideos, v)
+	}
+	rows.Close()
+
+	// Each video belongs to one member, so the member rows add up to the
+	// organization totals (videos of people who have left are not counted).
+	// Unique viewers are the exception: one person may watch several members'
+	// videos, so they are counted across the organization instead.
+	summary := orgDashboardSummary{Members: len(members)}
+	if err := h.db.QueryRow(ctx,
+		`WITH `+orgVideoViewers+`
+		 SELECT COUNT(DISTINCT w.viewer_hash)
+		 FROM viewers w
+		 JOIN videos v ON v.id = w.video_id
+		 JOIN organization_members om ON om.user_id = v.user_id AND om.organization_id = $1
+		 WHERE v.status != 'deleted'`,
+		orgID, since,
+	).Scan(&summary.UniqueViews); err != nil {
+		return nil, fmt.Errorf("unique viewers: %w", err)
+	}
+	var completions, videoViewers int64
+	for _, m := range members {
+		summary.TotalViews += m.Views
+		videoViewers += m.videoViewers
+		summary.TotalWatchTimeSeconds += m.WatchTimeSeconds
+		summary.TotalVideos += m.Videos
+		summary.NewVideos += m.NewVideos
+		summary.CtaClicks += m.CtaClicks
+		completions += m.completions
+		if m.NewVideos > 0 {
+			summary.ActiveMembers++
+		}
+	}
+	summary.CompletionRate = min(percentOf(completions, videoViewers), 100)
+
+	return &orgDashboardResponse{
+		Summary:   summary,
+		Members:   members,
+		Folders:   folders,
+		Tags:      tags,
+		Adoption:  adoption,
+		TopVideos: topVideos,
+	}, nil
+}
+
+// requireOrgAdmin resolves the organization in context and checks that the
+// caller administers it. It writes the error response and returns "" otherwise.
+func requireOrgAdmin(w http.ResponseWriter, r *http.Request) string {
+	orgID := auth.OrgIDFromContext(r.Context())
+	if orgID == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "X-Organization-Id header is required")
+		return ""
+	}
+	if organization.RequireRole(w, r, "owner", "admin") == "" {
+		return ""
+	}
+	return orgID
+}
+
+func (h *Handler) OrgAnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
+	orgID := requireOrgAdmin(w, r)
+	if orgID == "" {
+		return
+	}
+	since, ok := dashboardSince(r.URL.Query().Get("range"), time.Now().UTC().Truncate(24*time.Hour))
+	if !ok {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid range: must be 7d, 30d, 90d, or all")
+		return
+	}
+
+	resp, err := h.orgDashboard(r.Context(), orgID, since)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to query organization analytics")
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) OrgAnalyticsExport(w http.ResponseWriter, r *http.Request) {
+	orgID := requireOrgAdmin(w, r)
+	if orgID == "" {
+		return
+	}
+	since, ok := dashboardSince(r.URL.Query().Get("range"), time.Now().UTC().Truncate(24*time.Hour))
+	if !ok {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid range")
+		return
+	}
+
+	resp, err := h.orgDashboard(r.Context(), orgID, since)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to query")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=organization-analytics.csv")
+	cw := csv.NewWriter(w)
+
+	itoa := func(n int64) string { return strconv.FormatInt(n, 10) }
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', 1, 64) }
+	writeRows := func(label string, rows []orgBreakdownRow, members bool) {
+		header := []string{label}
+		if members {
+			header = append(header, "Email", "Role")
+		}
+		header = append(header, "Videos", "New Videos", "Views", "Unique Views", "Watch Time (s)", "Completion %", "CTA Clicks")
+		_ = cw.Write(header)
+		for _, row := range rows {
+			record := []string{row.Name}
+			if members {
+				record = append(record, row.Email, row.Role)
+			}
+			record = append(record, itoa(row.Videos), itoa(row.NewVideos), itoa(row.Views), itoa(row.UniqueViews),
+				itoa(row.WatchTimeSeconds), ftoa(row.CompletionRate), itoa(row.CtaClicks))
+			_ = cw.Write(record)
+		}
+		_ = cw.Write(nil)
+	}
+
+	writeRows("Member", resp.Members, true)
+	writeRows("Folder", resp.Folders, false)
+	writeRows("Tag", resp.Tags, false)
+
+	names := make(map[string]string, len(resp.Members))
+	for _, m := range resp.Members {
+		names[m.ID] = m.Name
+	}
+	_ = cw.Write([]string{"Week", "Member", "Videos Created"})
+	for _, a := range resp.Adoption {
+		_ = cw.Write([]string{a.WeekStart, names[a.UserID], itoa(a.VideosCreated)})
+	}
+	_ = cw.Write(nil)
+
+	_ = cw.Write([]string{"Top Video", "Owner", "Views", "Unique Views", "Completion %", "CTA Clicks"})
+	for _, v := range resp.TopVideos {
+		_ = cw.Write([]string{v.Title, v.OwnerName, itoa(v.Views), itoa(v.UniqueViews), ftoa(v.CompletionRate), itoa(v.CtaClicks)})
+	}
+	cw.Flush()
+}
@@ -0,0 +1,171 @@
+package video
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/auth"
+)
+
+var orgBreakdownColumns = []string{
+	"id", "name", "email", "role", "videos", "new_videos",
+	"views", "unique_views", "video_viewers", "watch_seconds", "completions", "cta_clicks",
+}
+
+func expectOrgDashboardQueries(mock pgxmock.PgxPoolIface) {
+	mock.ExpectQuery(`FROM organization_members om(.|\n)*AND v\.parent_video_id IS NULL`).
+		WithArgs(testOrgID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows(orgBreakdownColumns).
+			AddRow("user-1", "Alice", "alice@example.com", "owner", int64(4), int64(2), int64(120), int64(70), int64(80), int64(3600), int64(40), int64(6)).
+			AddRow("user-2", "Bob", "bob@example.com", "member", int64(1), int64(0), int64(30), int64(20), int64(20), int64(600), int64(5), int64(0)))
+	mock.ExpectQuery(`FROM folders f\s+LEFT JOIN videos v ON .* AND v\.parent_video_id IS NULL`).
+		WithArgs(testOrgID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows(orgBreakdownColumns).
+			AddRow("folder-1", "Sales", "", "", int64(3), int64(1), int64(100), int64(60), int64(60), int64(3000), int64(30), int64(6)))
+	mock.ExpectQuery(`FROM tags t(.|\n)*AND v\.parent_video_id IS NULL`).
+		WithArgs(testOrgID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows(orgBreakdownColumns).
+			AddRow("tag-1", "Onboarding", "", "", int64(2), int64(0), int64(50), int64(25), int64(25), int64(900), int64(10), int64(0)))
+	mock.ExpectQuery(`SELECT date_trunc\('week', v\.created_at AT TIME ZONE 'UTC'\)::date AS week(.|\n)*AND v\.parent_video_id IS NULL`).
+		WithArgs(testOrgID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"week", "user_id", "count"}).
+			AddRow(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), "user-1", int64(2)))
+	mock.ExpectQuery(`FROM stats s\s+JOIN videos v ON v\.id = s\.video_id`).
+		WithArgs(testOrgID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "owner", "views", "unique", "completions", "cta"}).
+			AddRow("vid-1", "Quarterly update", "Alice", int64(90), int64(60), int64(30), int64(4)))
+	// Fifteen people watched both Alice's and Bob's videos.
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT w\.viewer_hash\)\s+FROM viewers w`).
+		WithArgs(testOrgID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(75)))
+}
+
+func orgAnalyticsRequest(t *testing.T, path, role string) *http.Request {
+	t.Helper()
+	req := authenticatedRequest(t, http.MethodGet, path, nil)
+	if role == "" {
+		return req
+	}
+	return req.WithContext(auth.ContextWithOrg(req.Context(), testOrgID, role))
+}
+
+func TestOrgAnalyticsDashboard_ReturnsBreakdowns(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	expectOrgDashboardQueries(mock)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/analytics/org", handler.OrgAnalyticsDashboard)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, orgAnalyticsRequest(t, "/api/analytics/org?range=30d", "admin"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp orgDashboardResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	s := resp.Summary
+	if s.TotalViews != 150 || s.UniqueViews != 75 || s.TotalVideos != 5 || s.Members != 2 || s.ActiveMembers != 1 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+	if s.CompletionRate != 45 {
+		t.Errorf("expected 45%% completion, got %v", s.CompletionRate)
+	}
+	if len(resp.Members) != 2 || resp.Members[0].CompletionRate != 50 || resp.Members[0].Email != "alice@example.com" {
+		t.Errorf("unexpected members %+v", resp.Members)
+	}
+	if len(resp.Folders) != 1 || resp.Folders[0].Name != "Sales" || len(resp.Tags) != 1 {
+		t.Errorf("unexpected folder and tag breakdowns %+v %+v", resp.Folders, resp.Tags)
+	}
+	if len(resp.Adoption) != 1 || resp.Adoption[0].WeekStart != "2026-03-02" || resp.Adoption[0].VideosCreated != 2 {
+		t.Errorf("unexpected adoption %+v", resp.Adoption)
+	}
+	if len(resp.TopVideos) != 1 || resp.TopVideos[0].OwnerName != "Alice" || resp.TopVideos[0].CompletionRate != 50 {
+		t.Errorf("unexpected top videos %+v", resp.TopVideos)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestOrgAnalyticsDashboard_RequiresOwnerOrAdmin(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/analytics/org", handler.OrgAnalyticsDashboard)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, orgAnalyticsRequest(t, "/api/analytics/org", "member"))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a member, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, orgAnalyticsRequest(t, "/api/analytics/org", ""))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without an organization, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, orgAnalyticsRequest(t, "/api/analytics/org?range=1y", "owner"))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid range, got %d", rec.Code)
+	}
+}
+
+func TestOrgAnalyticsExport_ReturnsCSV(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	expectOrgDashboardQueries(mock)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/analytics/org/export", handler.OrgAnalyticsExport)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, orgAnalyticsRequest(t, "/api/analytics/org/export?range=7d", "owner"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("expected text/csv, got %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"Member,Email,Role,Videos,New Videos,Views,Unique Views,Watch Time (s),Completion %,CTA Clicks\n",
+		"Alice,alice@example.com,owner,4,2,120,70,3600,50.0,6\n",
+		"Folder,Videos,New Videos,Views,Unique Views,Watch Time (s),Completion %,CTA Clicks\nSales,3,1,100,60,3000,50.0,6\n",
+		"Tag,Videos,",
+		"Week,Member,Videos Created\n2026-03-02,Alice,2\n",
+		"Top Video,Owner,Views,Unique Views,Completion %,CTA Clicks\nQuarterly update,Alice,90,60,50.0,4\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected CSV to contain %q, got:\n%s", want, body)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
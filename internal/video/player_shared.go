@@ -1044,3 +1044,283 @@ const safariWarningJS = `
                 playerEl.addEventListener('loadstart', checkWebM);
             }
 `
+
+// timedCTACSS styles the timed CTA layer: corner buttons and hotspots drawn
+// over the visible video frame.
+const timedCTACSS = `
+        .timed-cta-layer {
+            position: absolute;
+            z-index: 3;
+            pointer-events: none;
+        }
+        .timed-cta {
+            position: absolute;
+            display: none;
+            pointer-events: auto;
+            text-decoration: none;
+        }
+        .timed-cta.visible { display: block; }
+        .timed-cta-button {
+            max-width: 45%;
+            padding: 8px 16px;
+            border-radius: 6px;
+            font-size: 14px;
+            font-weight: 600;
+            white-space: nowrap;
+            overflow: hidden;
+            text-overflow: ellipsis;
+            box-shadow: 0 2px 8px rgba(0, 0, 0, 0.35);
+        }
+        .timed-cta-primary { background: var(--player-accent, #00b67a); color: #fff; }
+        .timed-cta-secondary { background: rgba(255, 255, 255, 0.92); color: #0f172a; }
+        .timed-cta-outline { background: rgba(0, 0, 0, 0.45); color: #fff; border: 2px solid #fff; }
+        .timed-cta-top-left { top: 12px; left: 12px; }
+        .timed-cta-top-right { top: 12px; right: 12px; }
+        .timed-cta-bottom-left { bottom: 64px; left: 12px; }
+        .timed-cta-bottom-right { bottom: 64px; right: 12px; }
+        .timed-cta-center { top: 50%; left: 50%; transform: translate(-50%, -50%); }
+        .timed-cta-hotspot {
+            border: 2px dashed rgba(255, 255, 255, 0.7);
+            border-radius: 6px;
+            background: rgba(255, 255, 255, 0.08);
+            transition: background 0.2s;
+        }
+        .timed-cta-hotspot:hover, .timed-cta-hotspot:focus-visible { background: rgba(255, 255, 255, 0.2); }
+        .timed-cta-hotspot.timed-cta-primary { border-color: var(--player-accent, #00b67a); }
+`
+
+// timedCTAHTML is the container the timed CTA script fills. It belongs inside
+// the player container, next to playerControlsHTML.
+const timedCTAHTML = `
+                <div class="timed-cta-layer" id="timed-cta-layer"></div>
+`
+
+// timedCTAJS renders a video's timed CTAs. It expects: player, timedCTAs
+// (the JSON from videoCTAsJSONColumn) and timedCTAClickURL to be declared
+// before this code runs.
+const timedCTAJS = `
+            var ctaLayer = document.getElementById('timed-cta-layer');
+            if (player && ctaLayer && timedCTAs.length) {
+                var ctaEls = timedCTAs.map(function(cta) {
+                    var a = document.createElement('a');
+                    a.href = cta.url;
+                    a.target = '_blank';
+                    a.rel = 'noopener noreferrer';
+                    a.className = 'timed-cta timed-cta-' + cta.style;
+                    if (cta.kind === 'hotspot' && cta.region) {
+                        a.classList.add('timed-cta-hotspot');
+                        a.style.left = (cta.region.x * 100) + '%';
+                        a.style.top = (cta.region.y * 100) + '%';
+                        a.style.width = (cta.region.width * 100) + '%';
+                        a.style.height = (cta.region.height * 100) + '%';
+                        a.setAttribute('aria-label', cta.text || 'Open link');
+                        if (cta.text) a.title = cta.text;
+                    } else {
+                        a.classList.add('timed-cta-button', 'timed-cta-' + cta.position);
+                        a.textContent = cta.text;
+                    }
+                    a.addEventListener('click', function() {
+                        fetch(timedCTAClickURL, {
+                            method: 'POST',
+                            headers: { 'Content-Type': 'application/json' },
+                            body: JSON.stringify({ ctaId: cta.id }),
+                            keepalive: true
+                        }).catch(function() {});
+                        if (!player.paused) player.pause();
+                    });
+                    ctaLayer.appendChild(a);
+                    return a;
+                });
+                // Hotspot regions are fractions of the frame, so the layer tracks
+                // the letterboxed picture rather than the whole element.
+                var layoutCTALayer = function() {
+                    var w = player.clientWidth, h = player.clientHeight;
+                    var fw = w, fh = h;
+                    if (player.videoWidth && player.videoHeight && w && h) {
+                        var ratio = player.videoWidth / player.videoHeight;
+                        if (w / h > ratio) fw = h * ratio; else fh = w / ratio;
+                    }
+                    ctaLayer.style.left = (player.offsetLeft + (w - fw) / 2) + 'px';
+                    ctaLayer.style.top = (player.offsetTop + (h - fh) / 2) + 'px';
+                    ctaLayer.style.width = fw + 'px';
+                    ctaLayer.style.height = fh + 'px';
+                };
+                var updateCTAs = function() {
+                    var t = player.currentTime;
+                    for (var i = 0; i < timedCTAs.length; i++) {
+                        var cta = timedCTAs[i];
+                        var on = t >= cta.startSeconds && (cta.endSeconds === null || t < cta.endSeconds);
+                        ctaEls[i].classList.toggle('visible', on);
+                    }
+                };
+                player.addEventListener('loadedmetadata', layoutCTALayer);
+                window.addEventListener('resize', layoutCTALayer);
+                document.addEventListener('fullscreenchange', layoutCTALayer);
+                player.addEventListener('timeupdate', updateCTAs);
+                player.addEventListener('seeked', updateCTAs);
+                layoutCTALayer();
+                updateCTAs();
+            }
+`
+
+// videoQuestionCSS styles the in-video question card, which covers the player
+// (controls included) until the viewer answers or skips.
+const videoQuestionCSS = `
+        .question-overlay {
+            position: absolute;
+            inset: 0;
+            z-index: 5;
+            display: none;
+            align-items: center;
+            justify-content: center;
+            background: rgba(0, 0, 0, 0.7);
+            padding: 16px;
+        }
+        .question-overlay.visible { display: flex; }
+        .question-card {
+            width: 100%;
+            max-width: 440px;
+            max-height: 100%;
+            overflow-y: auto;
+            background: #0f172a;
+            border: 1px solid rgba(255, 255, 255, 0.12);
+            border-radius: 10px;
+            padding: 16px;
+            color: #e2e8f0;
+        }
+        .question-prompt { font-size: 15px; font-weight: 600; margin: 0 0 12px; line-height: 1.4; }
+        .question-option {
+            display: block;
+            width: 100%;
+            text-align: left;
+            margin-bottom: 8px;
+            padding: 9px 12px;
+            border-radius: 6px;
+            border: 1px solid rgba(255, 255, 255, 0.18);
+            background: rgba(255, 255, 255, 0.04);
+            color: inherit;
+            font-size: 14px;
+            cursor: pointer;
+        }
+        .question-option:hover:not(:disabled) { border-color: var(--player-accent, #00b67a); }
+        .question-option:disabled { cursor: default; }
+        .question-option.chosen { border-color: #e2e8f0; }
+        .question-option.correct { border-color: #22c55e; background: rgba(34, 197, 94, 0.15); }
+        .question-option.incorrect { border-color: #ef4444; background: rgba(239, 68, 68, 0.15); }
+        .question-feedback { font-size: 13px; min-height: 18px; margin: 4px 0 10px; }
+        .question-actions { display: flex; justify-content: flex-end; gap: 8px; }
+        .question-actions button {
+            padding: 7px 14px;
+            border-radius: 6px;
+            border: none;
+            font-size: 13px;
+            font-weight: 600;
+            cursor: pointer;
+        }
+        .question-skip { background: transparent; color: #94a3b8; }
+        .question-continue { background: var(--player-accent, #00b67a); color: #fff; display: none; }
+        .question-continue.visible { display: inline-block; }
+`
+
+// videoQuestionHTML is the question card filled in by videoQuestionJS. It
+// belongs inside the player container.
+const videoQuestionHTML = `
+                <div class="question-overlay" id="question-overlay" role="dialog" aria-modal="true" aria-labelledby="question-prompt">
+                    <div class="question-card">
+                        <p class="question-prompt" id="question-prompt"></p>
+                        <div id="question-options"></div>
+                        <p class="question-feedback" id="question-feedback" aria-live="polite"></p>
+                        <div class="question-actions">
+                            <button type="button" class="question-skip" id="question-skip">Skip</button>
+                            <button type="button" class="question-continue" id="question-continue">Continue</button>
+                        </div>
+                    </div>
+                </div>
+`
+
+// videoQuestionJS pauses playback at each question's timestamp and posts the
+// viewer's answer. It expects: player, videoQuestions (the JSON from
+// videoQuestionsJSONColumn) and questionAnswerURL to be declared before this
+// code runs.
+const videoQuestionJS = `
+            var qOverlay = document.getElementById('question-overlay');
+            if (player && qOverlay && videoQuestions.length) {
+                var qPrompt = document.getElementById('question-prompt');
+                var qOptions = document.getElementById('question-options');
+                var qFeedback = document.getElementById('question-feedback');
+                var qSkip = document.getElementById('question-skip');
+                var qContinue = document.getElementById('question-continue');
+                var asked = {};
+                var lastTime = 0;
+                var closeQuestion = function() {
+                    qOverlay.classList.remove('visible');
+                    player.play().catch(function() {});
+                };
+                var showQuestion = function(q) {
+                    asked[q.id] = true;
+                    player.pause();
+                    qPrompt.textContent = q.prompt;
+                    qFeedback.textContent = '';
+                    qContinue.classList.remove('visible');
+                    qSkip.style.display = '';
+                    qOptions.innerHTML = '';
+                    var buttons = q.options.map(function(text, index) {
+                        var b = document.createElement('button');
+                        b.type = 'button';
+                        b.className = 'question-option';
+                        b.textContent = text;
+                        b.addEventListener('click', function() {
+                            buttons.forEach(function(other) { other.disabled = true; });
+                            b.classList.add('chosen');
+                            qSkip.style.display = 'none';
+                            fetch(questionAnswerURL, {
+                                method: 'POST',
+                                headers: { 'Content-Type': 'application/json' },
+                                body: JSON.stringify({ questionId: q.id, optionIndex: index })
+                            }).then(function(r) {
+                                if (!r.ok) throw new Error('answer failed');
+                                return r.json();
+                            }).then(function(result) {
+                                if (result.correct === true) {
+                                    b.classList.add('correct');
+                                    qFeedback.textContent = 'Correct!';
+                                } else if (result.correct === false) {
+                                    b.classList.add('incorrect');
+                                    if (buttons[result.correctOption]) buttons[result.correctOption].classList.add('correct');
+                                    qFeedback.textContent = 'Not quite.';
+                                } else {
+                                    qFeedback.textContent = 'Thanks for your answer.';
+                                }
+                            }).catch(function() {
+                                qFeedback.textContent = 'Your answer could not be saved.';
+                            }).then(function() {
+                                qContinue.classList.add('visible');
+                                qContinue.focus();
+                            });
+                        });
+                        qOptions.appendChild(b);
+                        return b;
+                    });
+                    qOverlay.classList.add('visible');
+                    if (buttons[0]) buttons[0].focus();
+                };
+                qSkip.addEventListener('click', closeQuestion);
+                qContinue.addEventListener('click', closeQuestion);
+                // Only questions crossed during normal playback are asked, so
+                // seeking far ahead does not stack several prompts at once.
+                player.addEventListener('timeupdate', function() {
+                    var t = player.currentTime;
+                    if (!player.paused && t > lastTime && t - lastTime < 2) {
+                        for (var i = 0; i < videoQuestions.length; i++) {
+                            var q = videoQuestions[i];
+                            if (!asked[q.id] && q.timeSeconds > lastTime - 0.01 && q.timeSeconds <= t) {
+                                lastTime = t;
+                                showQuestion(q);
+                                return;
+                            }
+                        }
+                    }
+                    lastTime = t;
+                });
+            }
+`
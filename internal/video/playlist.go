@@ -44,6 +44,8 @@ type playlistDetail struct {
 	playlistItem
 	RequireEmail bool            `json:"requireEmail"`
 	HasPassword  bool            `json:"hasPassword"`
+	GateFields   []gateField     `json:"gateFields"`
+	FeedURL      *string         `json:"feedUrl,omitempty"`
 	Videos       []playlistVideo `json:"videos"`
 }
 
@@ -175,12 +177,13 @@ func (h *Handler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
 	var detail playlistDetail
 	var createdAt, updatedAt time.Time
 	var shareToken *string
+	var gateFieldsJSON string
 	err := h.db.QueryRow(r.Context(),
-		`SELECT p.id, p.title, p.description, p.is_shared, p.share_token, p.require_email, p.share_password IS NOT NULL, p.position, p.created_at, p.updated_at
+		`SELECT p.id, p.title, p.description, p.is_shared, p.share_token, p.require_email, p.share_password IS NOT NULL, p.position, p.created_at, p.updated_at, p.email_gate_fields
 		 FROM playlists p
 		 WHERE p.id = $1 AND p.user_id = $2`,
 		playlistID, userID,
-	).Scan(&detail.ID, &detail.Title, &detail.Description, &detail.IsShared, &shareToken, &detail.RequireEmail, &detail.HasPassword, &detail.Position, &createdAt, &updatedAt)
+	).Scan(&detail.ID, &detail.Title, &detail.Description, &detail.IsShared, &shareToken, &detail.RequireEmail, &detail.HasPassword, &detail.Position, &createdAt, &updatedAt, &gateFieldsJSON)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			httputil.WriteError(w, http.StatusNotFound, "playlist not found")
@@ -193,9 +196,15 @@ func (h *Handler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
 	detail.CreatedAt = createdAt.Format(time.RFC3339)
 	detail.UpdatedAt = updatedAt.Format(time.RFC3339)
 	detail.ShareToken = shareToken
+	detail.GateFields = decodeGateFields(gateFieldsJSON)
+	if detail.GateFields == nil {
+		detail.GateFields = []gateField{}
+	}
 	if shareToken != nil {
 		shareURL := h.baseURL + "/watch/playlist/" + *shareToken
 		detail.ShareURL = &shareURL
+		feedURL := h.baseURL + "/feed/playlist/" + *shareToken
+		detail.FeedURL = &feedURL
 	}
 
 	rows, err := h.db.Query(r.Context(),
@@ -240,12 +249,13 @@ func (h *Handler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
 }
 
 type updatePlaylistRequest struct {
-	Title         *string `json:"title"`
-	Description   *string `json:"description"`
-	Position      *int    `json:"position"`
-	IsShared      *bool   `json:"isShared"`
-	SharePassword *string `json:"sharePassword"`
-	RequireEmail  *bool   `json:"requireEmail"`
+	Title         *string      `json:"title"`
+	Description   *string      `json:"description"`
+	Position      *int         `json:"position"`
+	IsShared      *bool        `json:"isShared"`
+	SharePassword *string      `json:"sharePassword"`
+	RequireEmail  *bool        `json:"requireEmail"`
+	GateFields    *[]gateField `json:"gateFields"`
 }
 
 func (h *Handler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
@@ -258,7 +268,7 @@ func (h *Handler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Title == nil && req.Description == nil && req.Position == nil && req.IsShared == nil && req.SharePassword == nil && req.RequireEmail == nil {
+	if req.Title == nil && req.Description == nil && req.Position == nil && req.IsShared == nil && req.SharePassword == nil && req.RequireEmail == nil && req.GateFields == nil {
 		httputil.WriteError(w, http.StatusBadRequest, "nothing to update")
 		return
 	}
@@ -340,6 +350,17 @@ func (h *Handler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
 		args = append(args, *req.RequireEmail)
 		paramIdx++
 	}
+	if req.GateFields != nil {
+		fields, msg := normalizeGateFields(*req.GateFields)
+		if msg != "" {
+			httputil.WriteError(w, http.StatusBadRequest, msg)
+			return
+		}
+		fieldsJSON, _ := json.Marshal(fields)
+		setClauses = append(setClauses, fmt.Sprintf("email_gate_fields = $%d", paramIdx))
+		args = append(args, string(fieldsJSON))
+		paramIdx++
+	}
 
 	query := fmt.Sprintf("UPDATE playlists SET %s WHERE id = $%d AND user_id = $%d",
 		strings.Join(setClauses, ", "), paramIdx, paramIdx+1)
@@ -23,6 +23,7 @@ type playlistEmbedGateData struct {
 	Title      string
 	ShareToken string
 	Nonce      string
+	GateFields []gateField
 }
 
 var playlistEmbedTemplate = template.Must(template.New("playlist-embed").Funcs(template.FuncMap{
@@ -592,7 +593,7 @@ var playlistEmbedEmailGateTemplate = template.Must(template.New("playlist-embed-
             cursor: pointer; transition: background 0.15s;
         }
         button:hover { background: #00a06b; }
-        button:disabled { opacity: 0.5; cursor: not-allowed; }
+        button:disabled { opacity: 0.5; cursor: not-allowed; }` + gateFieldsCSS + `
     </style>
 </head>
 <body>
@@ -603,10 +604,11 @@ var playlistEmbedEmailGateTemplate = template.Must(template.New("playlist-embed-
         <p class="error" id="error-msg"></p>
         <form id="email-gate-form">
             <input type="email" id="email-input" placeholder="you@example.com" required maxlength="320" autofocus>
+            ` + gateFieldsHTML + `
             <button type="submit" id="submit-btn">Watch Playlist</button>
         </form>
     </div>
-    <script nonce="{{.Nonce}}">
+    <script nonce="{{.Nonce}}">` + gateFieldsJS + `
         document.getElementById('email-gate-form').addEventListener('submit', function(e) {
             e.preventDefault();
             var btn = document.getElementById('submit-btn');
@@ -617,7 +619,7 @@ var playlistEmbedEmailGateTemplate = template.Must(template.New("playlist-embed-
             fetch('/api/watch/playlist/{{.ShareToken}}/identify', {
                 method: 'POST',
                 headers: {'Content-Type': 'application/json'},
-                body: JSON.stringify({email: email})
+                body: JSON.stringify({email: email, fields: collectGateFields()})
             }).then(function(r) {
                 if (r.ok) { window.location.reload(); }
                 else { return r.json().then(function(d) { errEl.textContent = d.error || 'Something went wrong'; errEl.style.display = 'block'; btn.disabled = false; }); }
@@ -669,6 +671,7 @@ func (h *Handler) PlaylistEmbedPage(w http.ResponseWriter, r *http.Request) {
 				Title:      title,
 				ShareToken: shareToken,
 				Nonce:      nonce,
+				GateFields: h.loadPlaylistGateFields(r.Context(), playlistID),
 			}); err != nil {
 				slog.Error("playlist-embed: failed to render email gate page", "error", err)
 			}
@@ -688,7 +691,7 @@ func (h *Handler) PlaylistEmbedPage(w http.ResponseWriter, r *http.Request) {
 	if err := playlistEmbedTemplate.Execute(w, playlistEmbedData{
 		Title:      title,
 		Nonce:      nonce,
-		BaseURL:    h.baseURL,
+		BaseURL:    h.publicBaseURL(r),
 		ShareToken: shareToken,
 		Videos:     videoItems,
 		VideosJSON: template.JS(videosJSONBytes),
@@ -0,0 +1,425 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	maxPlaylistFeedTokens      = 100
+	maxPlaylistFeedTokenLabel  = 200
+	playlistFeedEnclosureTTL   = 6 * time.Hour
+	playlistFeedCacheSeconds   = 300
+	itunesNamespace            = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+	atomNamespace              = "http://www.w3.org/2005/Atom"
+	playlistFeedTokenQueryName = "token"
+)
+
+type rssFeed struct {
+	XMLName  xml.Name   `xml:"rss"`
+	Version  string     `xml:"version,attr"`
+	ItunesNS string     `xml:"xmlns:itunes,attr"`
+	AtomNS   string     `xml:"xmlns:atom,attr"`
+	Channel  rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	Description string       `xml:"description"`
+	AtomLink    rssAtomLink  `xml:"atom:link"`
+	Generator   string       `xml:"generator"`
+	Author      string       `xml:"itunes:author,omitempty"`
+	Summary     string       `xml:"itunes:summary,omitempty"`
+	Type        string       `xml:"itunes:type"`
+	Explicit    string       `xml:"itunes:explicit"`
+	Image       *itunesImage `xml:"itunes:image,omitempty"`
+	Block       string       `xml:"itunes:block,omitempty"`
+	Items       []rssItem    `xml:"item"`
+}
+
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type rssItem struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	GUID        rssGUID      `xml:"guid"`
+	Description string       `xml:"description,omitempty"`
+	PubDate     string       `xml:"pubDate"`
+	Enclosure   rssEnclosure `xml:"enclosure"`
+	Duration    int          `xml:"itunes:duration"`
+	Episode     int          `xml:"itunes:episode"`
+	Image       *itunesImage `xml:"itunes:image,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type playlistFeedInfo struct {
+	ID          string
+	Title       string
+	Description *string
+	Author      string
+	Gated       bool
+}
+
+// authorizePlaylistFeed resolves a shared playlist for its feed. Playlists
+// behind a password or email gate can only be followed with a subscriber
+// token, since podcast apps cannot fill in either gate.
+func (h *Handler) authorizePlaylistFeed(w http.ResponseWriter, r *http.Request) (playlistFeedInfo, bool) {
+	shareToken := chi.URLParam(r, "shareToken")
+
+	var info playlistFeedInfo
+	err := h.db.QueryRow(r.Context(),
+		`SELECT p.id, p.title, p.description, p.share_password IS NOT NULL OR p.require_email, COALESCE(u.name, '')
+		 FROM playlists p
+		 JOIN users u ON u.id = p.user_id
+		 WHERE p.share_token = $1 AND p.is_shared = true`,
+		shareToken,
+	).Scan(&info.ID, &info.Title, &info.Description, &info.Gated, &info.Author)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "playlist not found")
+		return info, false
+	}
+	if !info.Gated {
+		return info, true
+	}
+
+	token := r.URL.Query().Get(playlistFeedTokenQueryName)
+	if token == "" {
+		httputil.WriteError(w, http.StatusUnauthorized, "feed token required")
+		return info, false
+	}
+	var tokenID string
+	if err := h.db.QueryRow(r.Context(),
+		`UPDATE playlist_feed_tokens SET last_used_at = now()
+		 WHERE playlist_id = $1 AND token_hash = $2 RETURNING id`,
+		info.ID, hashViewerLinkToken(token),
+	).Scan(&tokenID); err != nil {
+		httputil.WriteError(w, http.StatusUnauthorized, "invalid feed token")
+		return info, false
+	}
+	return info, true
+}
+
+type playlistFeedVideo struct {
+	ID           string
+	Title        string
+	Summary      string
+	Duration     int
+	ShareToken   string
+	ContentType  string
+	FileSize     int64
+	CreatedAt    time.Time
+	HasThumbnail bool
+}
+
+// loadPlaylistFeedVideos lists the playlist's public videos like
+// loadPlaylistVideos, but only ready ones: podcast apps download episodes
+// once, so a file still being processed must not be offered.
+func (h *Handler) loadPlaylistFeedVideos(ctx context.Context, playlistID string) ([]playlistFeedVideo, error) {
+	rows, err := h.db.Query(ctx,
+		`SELECT v.id, v.title, COALESCE(v.summary, ''), v.duration, v.share_token, v.content_type,
+		        v.file_size, v.created_at, v.thumbnail_key IS NOT NULL
+		 FROM playlist_videos pv
+		 JOIN videos v ON v.id = pv.video_id AND v.status = 'ready' AND v.embed_signing_secret IS NULL
+		 LEFT JOIN folders f ON f.id = v.folder_id
+		 WHERE pv.playlist_id = $1 AND COALESCE(v.visibility, f.visibility, 'public') = 'public'
+		 ORDER BY pv.position, v.created_at`,
+		playlistID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := make([]playlistFeedVideo, 0)
+	for rows.Next() {
+		var v playlistFeedVideo
+		if err := rows.Scan(&v.ID, &v.Title, &v.Summary, &v.Duration, &v.ShareToken, &v.ContentType,
+			&v.FileSize, &v.CreatedAt, &v.HasThumbnail); err != nil {
+			return nil, err
+		}
+		videos = append(videos, v)
+	}
+	return videos, rows.Err()
+}
+
+// PlaylistFeed serves a shared playlist as an RSS 2.0 podcast feed.
+// Enclosures point back at PlaylistFeedEnclosure, which redirects to a fresh
+// signed URL, so episodes stay downloadable long after the feed was fetched.
+func (h *Handler) PlaylistFeed(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.authorizePlaylistFeed(w, r)
+	if !ok {
+		return
+	}
+
+	videos, err := h.loadPlaylistFeedVideos(r.Context(), info.ID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not load playlist")
+		return
+	}
+
+	shareToken := chi.URLParam(r, "shareToken")
+	baseURL := h.publicBaseURL(r)
+	feedURL := baseURL + "/feed/playlist/" + shareToken
+	var tokenQuery string
+	if info.Gated {
+		tokenQuery = "?" + playlistFeedTokenQueryName + "=" + url.QueryEscape(r.URL.Query().Get(playlistFeedTokenQueryName))
+	}
+
+	description := info.Title
+	if info.Description != nil && strings.TrimSpace(*info.Description) != "" {
+		description = *info.Description
+	}
+	channel := rssChannel{
+		Title:       info.Title,
+		Link:        baseURL + "/watch/playlist/" + shareToken,
+		Description: description,
+		AtomLink:    rssAtomLink{Href: feedURL + tokenQuery, Rel: "self", Type: "application/rss+xml"},
+		Generator:   "SendRec",
+		Author:      info.Author,
+		Summary:     description,
+		Type:        "serial",
+		Explicit:    "false",
+		Items:       make([]rssItem, 0, len(videos)),
+	}
+	if info.Gated {
+		// Keep private series out of podcast directories.
+		channel.Block = "Yes"
+	}
+
+	for i, v := range videos {
+		item := rssItem{
+			Title:       v.Title,
+			Link:        baseURL + "/watch/" + v.ShareToken,
+			GUID:        rssGUID{IsPermaLink: "false", Value: v.ID},
+			Description: v.Summary,
+			PubDate:     v.CreatedAt.UTC().Format(time.RFC1123Z),
+			Enclosure: rssEnclosure{
+				URL:    feedURL + "/videos/" + v.ID + tokenQuery,
+				Length: v.FileSize,
+				Type:   v.ContentType,
+			},
+			Duration: v.Duration,
+			Episode:  i + 1,
+		}
+		if v.HasThumbnail {
+			item.Image = &itunesImage{Href: baseURL + "/api/watch/" + v.ShareToken + "/thumbnail"}
+			if channel.Image == nil {
+				channel.Image = item.Image
+			}
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(playlistFeedCacheSeconds))
+	_, _ = w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(rssFeed{Version: "2.0", ItunesNS: itunesNamespace, AtomNS: atomNamespace, Channel: channel}); err != nil {
+		slog.Error("playlist-feed: failed to encode feed", "playlist_id", info.ID, "error", err)
+	}
+}
+
+// PlaylistFeedEnclosure redirects a podcast app to a signed download URL for
+// one episode of a playlist feed.
+func (h *Handler) PlaylistFeedEnclosure(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.authorizePlaylistFeed(w, r)
+	if !ok {
+		return
+	}
+
+	var title, fileKey, contentType string
+	err := h.db.QueryRow(r.Context(),
+		`SELECT v.title, v.file_key, v.content_type
+		 FROM playlist_videos pv
+		 JOIN videos v ON v.id = pv.video_id AND v.status = 'ready' AND v.embed_signing_secret IS NULL
+		 LEFT JOIN folders f ON f.id = v.folder_id
+		 WHERE pv.playlist_id = $1 AND v.id = $2 AND COALESCE(v.visibility, f.visibility, 'public') = 'public'`,
+		info.ID, chi.URLParam(r, "videoId"),
+	).Scan(&title, &fileKey, &contentType)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	downloadURL, err := h.storage.GenerateDownloadURLWithDisposition(r.Context(), fileKey,
+		title+extensionForContentType(contentType), playlistFeedEnclosureTTL)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to generate download URL")
+		return
+	}
+	http.Redirect(w, r, downloadURL, http.StatusFound)
+}
+
+type playlistFeedToken struct {
+	ID         string     `json:"id"`
+	Label      string     `json:"label"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+type createPlaylistFeedTokenRequest struct {
+	Label string `json:"label"`
+}
+
+type createPlaylistFeedTokenResponse struct {
+	playlistFeedToken
+	FeedURL string `json:"feedUrl"`
+}
+
+// playlistShareTokenForOwner returns the playlist's share token, or ok=false
+// when the user does not own it.
+func (h *Handler) playlistShareTokenForOwner(ctx context.Context, playlistID, userID string) (*string, bool) {
+	var shareToken *string
+	if err := h.db.QueryRow(ctx,
+		`SELECT share_token FROM playlists WHERE id = $1 AND user_id = $2`,
+		playlistID, userID,
+	).Scan(&shareToken); err != nil {
+		return nil, false
+	}
+	return shareToken, true
+}
+
+func (h *Handler) ListPlaylistFeedTokens(w http.ResponseWriter, r *http.Request) {
+	playlistID := chi.URLParam(r, "id")
+	if _, ok := h.playlistShareTokenForOwner(r.Context(), playlistID, auth.UserIDFromContext(r.Context())); !ok {
+		httputil.WriteError(w, http.StatusNotFound, "playlist not found")
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(),
+		`SELECT id, label, created_at, last_used_at FROM playlist_feed_tokens
+		 WHERE playlist_id = $1 ORDER BY created_at`, playlistID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch feed tokens")
+		return
+	}
+	defer rows.Close()
+
+	tokens := make([]playlistFeedToken, 0)
+	for rows.Next() {
+		var t playlistFeedToken
+		if err := rows.Scan(&t.ID, &t.Label, &t.CreatedAt, &t.LastUsedAt); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not fetch feed tokens")
+			return
+		}
+		tokens = append(tokens, t)
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch feed tokens")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, tokens)
+}
+
+// CreatePlaylistFeedToken issues a subscriber token for a gated playlist's
+// feed. The raw token is only returned here, embedded in the feed URL.
+func (h *Handler) CreatePlaylistFeedToken(w http.ResponseWriter, r *http.Request) {
+	playlistID := chi.URLParam(r, "id")
+
+	var req createPlaylistFeedTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Label = strings.TrimSpace(req.Label)
+	if req.Label == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "subscriber label is required")
+		return
+	}
+	if len(req.Label) > maxPlaylistFeedTokenLabel {
+		httputil.WriteError(w, http.StatusBadRequest, "subscriber label must be 200 characters or less")
+		return
+	}
+
+	shareToken, ok := h.playlistShareTokenForOwner(r.Context(), playlistID, auth.UserIDFromContext(r.Context()))
+	if !ok {
+		httputil.WriteError(w, http.StatusNotFound, "playlist not found")
+		return
+	}
+	if shareToken == nil {
+		httputil.WriteError(w, http.StatusBadRequest, "share the playlist before adding feed subscribers")
+		return
+	}
+
+	var count int
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT COUNT(*) FROM playlist_feed_tokens WHERE playlist_id = $1`, playlistID,
+	).Scan(&count); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not create feed token")
+		return
+	}
+	if count >= maxPlaylistFeedTokens {
+		httputil.WriteError(w, http.StatusBadRequest, "too many feed subscribers for this playlist")
+		return
+	}
+
+	rawToken, tokenHash, err := generateViewerLinkToken()
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not create feed token")
+		return
+	}
+
+	resp := createPlaylistFeedTokenResponse{playlistFeedToken: playlistFeedToken{Label: req.Label}}
+	if err := h.db.QueryRow(r.Context(),
+		`INSERT INTO playlist_feed_tokens (playlist_id, token_hash, label) VALUES ($1, $2, $3)
+		 RETURNING id, created_at`,
+		playlistID, tokenHash, req.Label,
+	).Scan(&resp.ID, &resp.CreatedAt); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not create feed token")
+		return
+	}
+	resp.FeedURL = h.playlistShareBaseURL(r.Context(), playlistID) + "/feed/playlist/" + *shareToken + "?" + playlistFeedTokenQueryName + "=" + url.QueryEscape(rawToken)
+
+	httputil.WriteJSON(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) DeletePlaylistFeedToken(w http.ResponseWriter, r *http.Request) {
+	playlistID := chi.URLParam(r, "id")
+	if _, ok := h.playlistShareTokenForOwner(r.Context(), playlistID, auth.UserIDFromContext(r.Context())); !ok {
+		httputil.WriteError(w, http.StatusNotFound, "playlist not found")
+		return
+	}
+
+	tag, err := h.db.Exec(r.Context(),
+		`DELETE FROM playlist_feed_tokens WHERE id = $1 AND playlist_id = $2`,
+		chi.URLParam(r, "tokenId"), playlistID,
+	)
+	if err != nil || tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "feed token not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
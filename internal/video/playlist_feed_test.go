@@ -0,0 +1,405 @@
+package video
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var playlistFeedColumns = []string{"id", "title", "description", "gated", "author"}
+
+var playlistFeedVideoColumns = []string{
+	"id", "title", "summary", "duration", "share_token", "content_type", "file_size", "created_at", "has_thumbnail",
+}
+
+func servePlaylistFeed(handler *Handler, target string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Get("/feed/playlist/{shareToken}", handler.PlaylistFeed)
+	r.Get("/feed/playlist/{shareToken}/videos/{videoId}", handler.PlaylistFeedEnclosure)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestPlaylistFeed_PublicPlaylist(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	published := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	description := "Weekly product updates"
+
+	mock.ExpectQuery(`SELECT p.id, p.title, p.description, p.share_password IS NOT NULL OR p.require_email`).
+		WithArgs("pltoken12345").
+		WillReturnRows(pgxmock.NewRows(playlistFeedColumns).
+			AddRow("playlist-1", "Product Updates", &description, false, "Ada"))
+	mock.ExpectQuery(`SELECT v.id, v.title, COALESCE\(v.summary, ''\)`).
+		WithArgs("playlist-1").
+		WillReturnRows(pgxmock.NewRows(playlistFeedVideoColumns).
+			AddRow("vid-1", "Episode <one>", "", 95, "vtoken1abcde", "video/mp4", int64(1048576), published, true).
+			AddRow("vid-2", "Episode two", "Recap", 300, "vtoken2abcde", "video/webm", int64(2048), published, false))
+
+	rec := servePlaylistFeed(handler, "/feed/playlist/pltoken12345")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/rss+xml; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	var feed struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title     string `xml:"title"`
+				GUID      string `xml:"guid"`
+				Enclosure struct {
+					URL    string `xml:"url,attr"`
+					Length int64  `xml:"length,attr"`
+					Type   string `xml:"type,attr"`
+				} `xml:"enclosure"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("feed is not valid XML: %v\n%s", err, rec.Body.String())
+	}
+	if feed.Channel.Title != "Product Updates" || len(feed.Channel.Items) != 2 {
+		t.Fatalf("unexpected channel: %+v", feed.Channel)
+	}
+	first := feed.Channel.Items[0]
+	if first.Title != "Episode <one>" || first.GUID != "vid-1" {
+		t.Errorf("unexpected first item: %+v", first)
+	}
+	if first.Enclosure.URL != testBaseURL+"/feed/playlist/pltoken12345/videos/vid-1" || first.Enclosure.Length != 1048576 || first.Enclosure.Type != "video/mp4" {
+		t.Errorf("unexpected enclosure: %+v", first.Enclosure)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"`,
+		`<itunes:author>Ada</itunes:author>`,
+		`<itunes:image href="` + testBaseURL + `/api/watch/vtoken1abcde/thumbnail"></itunes:image>`,
+		`<itunes:duration>95</itunes:duration>`,
+		`<pubDate>Mon, 02 Mar 2026 09:30:00 +0000</pubDate>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected feed to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "itunes:block") {
+		t.Error("public feeds should not be blocked from directories")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPlaylistFeed_GatedRequiresToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT p.id, p.title, p.description, p.share_password IS NOT NULL OR p.require_email`).
+		WithArgs("pwdtoken1234").
+		WillReturnRows(pgxmock.NewRows(playlistFeedColumns).
+			AddRow("playlist-2", "Private", (*string)(nil), true, "Ada"))
+
+	rec := servePlaylistFeed(handler, "/feed/playlist/pwdtoken1234")
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPlaylistFeed_GatedRejectsUnknownToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT p.id, p.title, p.description, p.share_password IS NOT NULL OR p.require_email`).
+		WithArgs("pwdtoken1234").
+		WillReturnRows(pgxmock.NewRows(playlistFeedColumns).
+			AddRow("playlist-2", "Private", (*string)(nil), true, "Ada"))
+	mock.ExpectQuery(`UPDATE playlist_feed_tokens SET last_used_at = now\(\)`).
+		WithArgs("playlist-2", hashViewerLinkToken("guessed")).
+		WillReturnError(errors.New("no rows"))
+
+	rec := servePlaylistFeed(handler, "/feed/playlist/pwdtoken1234?token=guessed")
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPlaylistFeed_GatedWithTokenBlocksDirectoriesAndKeepsToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT p.id, p.title, p.description, p.share_password IS NOT NULL OR p.require_email`).
+		WithArgs("pwdtoken1234").
+		WillReturnRows(pgxmock.NewRows(playlistFeedColumns).
+			AddRow("playlist-2", "Private", (*string)(nil), true, "Ada"))
+	mock.ExpectQuery(`UPDATE playlist_feed_tokens SET last_used_at = now\(\)`).
+		WithArgs("playlist-2", hashViewerLinkToken("subscriber-token")).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("token-1"))
+	mock.ExpectQuery(`SELECT v.id, v.title, COALESCE\(v.summary, ''\)`).
+		WithArgs("playlist-2").
+		WillReturnRows(pgxmock.NewRows(playlistFeedVideoColumns).
+			AddRow("vid-1", "Episode", "", 60, "vtoken1abcde", "video/mp4", int64(10), time.Now(), false))
+
+	rec := servePlaylistFeed(handler, "/feed/playlist/pwdtoken1234?token=subscriber-token")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`<itunes:block>Yes</itunes:block>`,
+		`url="` + testBaseURL + `/feed/playlist/pwdtoken1234/videos/vid-1?token=subscriber-token"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected feed to contain %q", want)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPlaylistFeedEnclosure_RedirectsToSignedURL(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	storage := &mockStorage{downloadDispositionURL: "https://storage.example.com/signed"}
+	handler := NewHandler(mock, storage, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT p.id, p.title, p.description, p.share_password IS NOT NULL OR p.require_email`).
+		WithArgs("pltoken12345").
+		WillReturnRows(pgxmock.NewRows(playlistFeedColumns).
+			AddRow("playlist-1", "Product Updates", (*string)(nil), false, "Ada"))
+	mock.ExpectQuery(`SELECT v.title, v.file_key, v.content_type`).
+		WithArgs("playlist-1", "vid-1").
+		WillReturnRows(pgxmock.NewRows([]string{"title", "file_key", "content_type"}).
+			AddRow("Episode", "recordings/user-1/vtoken1abcde.mp4", "video/mp4"))
+
+	rec := servePlaylistFeed(handler, "/feed/playlist/pltoken12345/videos/vid-1")
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected 302, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if loc := rec.Header().Get("Location"); loc != "https://storage.example.com/signed" {
+		t.Errorf("unexpected redirect %q", loc)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPlaylistFeedEnclosure_VideoNotInPlaylist(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT p.id, p.title, p.description, p.share_password IS NOT NULL OR p.require_email`).
+		WithArgs("pltoken12345").
+		WillReturnRows(pgxmock.NewRows(playlistFeedColumns).
+			AddRow("playlist-1", "Product Updates", (*string)(nil), false, "Ada"))
+	mock.ExpectQuery(`SELECT v.title, v.file_key, v.content_type`).
+		WithArgs("playlist-1", "other-video").
+		WillReturnError(errors.New("no rows"))
+
+	rec := servePlaylistFeed(handler, "/feed/playlist/pltoken12345/videos/other-video")
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreatePlaylistFeedToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	shareToken := "pwdtoken1234"
+
+	mock.ExpectQuery(`SELECT share_token FROM playlists WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("playlist-2", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"share_token"}).AddRow(&shareToken))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM playlist_feed_tokens WHERE playlist_id = \$1`).
+		WithArgs("playlist-2").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`INSERT INTO playlist_feed_tokens \(playlist_id, token_hash, label\)`).
+		WithArgs("playlist-2", pgxmock.AnyArg(), "Ada's phone").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("token-1", time.Now()))
+	mock.ExpectQuery(`SELECT od\.hostname FROM playlist_slugs ps`).
+		WithArgs("playlist-2").
+		WillReturnError(pgx.ErrNoRows)
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/playlists/{id}/feed-tokens", handler.CreatePlaylistFeedToken)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/playlists/playlist-2/feed-tokens",
+		[]byte(`{"label":" Ada's phone "}`)))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp createPlaylistFeedTokenResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	prefix := testBaseURL + "/feed/playlist/" + shareToken + "?token="
+	if resp.ID != "token-1" || resp.Label != "Ada's phone" || !strings.HasPrefix(resp.FeedURL, prefix) || len(resp.FeedURL) == len(prefix) {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreatePlaylistFeedToken_UsesWorkspaceCustomDomain(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	shareToken := "pwdtoken1234"
+
+	mock.ExpectQuery(`SELECT share_token FROM playlists WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("playlist-2", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"share_token"}).AddRow(&shareToken))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM playlist_feed_tokens WHERE playlist_id = \$1`).
+		WithArgs("playlist-2").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO playlist_feed_tokens \(playlist_id, token_hash, label\)`).
+		WithArgs("playlist-2", pgxmock.AnyArg(), "Ada").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("token-1", time.Now()))
+	mock.ExpectQuery(`SELECT od\.hostname FROM playlist_slugs ps`).
+		WithArgs("playlist-2").
+		WillReturnRows(pgxmock.NewRows([]string{"hostname"}).AddRow("video.theirbrand.com"))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/playlists/{id}/feed-tokens", handler.CreatePlaylistFeedToken)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/playlists/playlist-2/feed-tokens",
+		[]byte(`{"label":"Ada"}`)))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp createPlaylistFeedTokenResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp.FeedURL, "https://video.theirbrand.com/feed/playlist/"+shareToken+"?token=") {
+		t.Errorf("expected feed URL on the custom domain, got %s", resp.FeedURL)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreatePlaylistFeedToken_UnsharedPlaylist(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT share_token FROM playlists WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("playlist-2", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"share_token"}).AddRow((*string)(nil)))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/playlists/{id}/feed-tokens", handler.CreatePlaylistFeedToken)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/playlists/playlist-2/feed-tokens",
+		[]byte(`{"label":"Ada"}`)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeletePlaylistFeedToken_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	shareToken := "pwdtoken1234"
+
+	mock.ExpectQuery(`SELECT share_token FROM playlists WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("playlist-2", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"share_token"}).AddRow(&shareToken))
+	mock.ExpectExec(`DELETE FROM playlist_feed_tokens WHERE id = \$1 AND playlist_id = \$2`).
+		WithArgs("token-9", "playlist-2").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Delete("/api/playlists/{id}/feed-tokens/{tokenId}", handler.DeletePlaylistFeedToken)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodDelete, "/api/playlists/playlist-2/feed-tokens/token-9", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -11,8 +11,9 @@ func (h *Handler) loadPlaylistVideos(ctx context.Context, playlistID string) ([]
 		`SELECT v.id, v.title, v.duration, v.share_token, v.content_type, v.user_id,
 		        v.thumbnail_key
 		 FROM playlist_videos pv
-		 JOIN videos v ON v.id = pv.video_id AND v.status IN ('ready', 'processing')
-		 WHERE pv.playlist_id = $1
+		 JOIN videos v ON v.id = pv.video_id AND v.status IN ('ready', 'processing') AND v.embed_signing_secret IS NULL
+		 LEFT JOIN folders f ON f.id = v.folder_id
+		 WHERE pv.playlist_id = $1 AND COALESCE(v.visibility, f.visibility, 'public') = 'public'
 		 ORDER BY pv.position, v.created_at`,
 		playlistID,
 	)
@@ -341,8 +341,8 @@ func TestGetPlaylist_Success(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT p\.id, p\.title, p\.description, p\.is_shared, p\.share_token, p\.require_email, p\.share_password IS NOT NULL, p\.position, p\.created_at, p\.updated_at`).
 		WithArgs("playlist-1", testUserID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "description", "is_shared", "share_token", "require_email", "has_password", "position", "created_at", "updated_at"}).
-			AddRow("playlist-1", "My Playlist", (*string)(nil), true, &shareToken, false, false, 0, now, now))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "description", "is_shared", "share_token", "require_email", "has_password", "position", "created_at", "updated_at", "email_gate_fields"}).
+			AddRow("playlist-1", "My Playlist", (*string)(nil), true, &shareToken, false, false, 0, now, now, "[]"))
 
 	thumbKey := "recordings/user1/thumb.jpg"
 	mock.ExpectQuery(`SELECT v\.id, v\.title, v\.duration, v\.share_token, v\.status, v\.created_at`).
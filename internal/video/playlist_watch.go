@@ -1,14 +1,17 @@
 package video
 
 import (
+	"context"
 	"encoding/json"
 	"html/template"
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/sendrec/sendrec/internal/httputil"
+	"github.com/sendrec/sendrec/internal/webhook"
 )
 
 type playlistWatchData struct {
@@ -21,6 +24,7 @@ type playlistWatchData struct {
 	VideosJSON    template.JS
 	NeedsPassword bool
 	NeedsEmail    bool
+	GateFields    []gateField
 }
 
 type playlistWatchVideoItem struct {
@@ -44,6 +48,8 @@ var playlistWatchTemplate = template.Must(template.New("playlist-watch").Funcs(t
     <meta charset="utf-8">
     <meta name="viewport" content="width=device-width, initial-scale=1">
     <title>{{.Title}} — SendRec</title>
+    {{if not (or .NeedsPassword .NeedsEmail)}}<link rel="alternate" type="application/json+oembed" href="{{.BaseURL}}/api/oembed?url={{.BaseURL}}/watch/playlist/{{.ShareToken}}&format=json" title="{{.Title}}">
+    <link rel="alternate" type="text/xml+oembed" href="{{.BaseURL}}/api/oembed?url={{.BaseURL}}/watch/playlist/{{.ShareToken}}&format=xml" title="{{.Title}}">{{end}}
     <style nonce="{{.Nonce}}">
         * { margin: 0; padding: 0; box-sizing: border-box; }
         :focus-visible { outline: 2px solid #00b67a; outline-offset: 2px; }
@@ -104,7 +110,7 @@ var playlistWatchTemplate = template.Must(template.New("playlist-watch").Funcs(t
         .gate-container button:disabled { opacity: 0.5; cursor: not-allowed; }
         .gate-branding { margin-top: 24px; font-size: 12px; color: #8892a4; }
         .gate-branding a { color: #00b67a; text-decoration: none; }
-        .gate-branding a:hover { text-decoration: underline; }
+        .gate-branding a:hover { text-decoration: underline; }` + gateFieldsCSS + `
         {{else}}
 ` + playerCSS + safariWarningCSS + `
         .playlist-layout {
@@ -523,11 +529,12 @@ var playlistWatchTemplate = template.Must(template.New("playlist-watch").Funcs(t
         <p class="gate-error" id="error-msg"></p>
         <form id="email-gate-form">
             <input type="email" id="email-input" placeholder="you@example.com" required maxlength="320" autofocus>
+            ` + gateFieldsHTML + `
             <button type="submit" id="submit-btn">Watch Playlist</button>
         </form>
         <div class="gate-branding">Powered by <a href="https://sendrec.eu" target="_blank" rel="noopener">SendRec</a></div>
     </div>
-    <script nonce="{{.Nonce}}">
+    <script nonce="{{.Nonce}}">` + gateFieldsJS + `
         document.getElementById('email-gate-form').addEventListener('submit', function(e) {
             e.preventDefault();
             var btn = document.getElementById('submit-btn');
@@ -538,7 +545,7 @@ var playlistWatchTemplate = template.Must(template.New("playlist-watch").Funcs(t
             fetch('/api/watch/playlist/{{.ShareToken}}/identify', {
                 method: 'POST',
                 headers: {'Content-Type': 'application/json'},
-                body: JSON.stringify({email: email})
+                body: JSON.stringify({email: email, fields: collectGateFields()})
             }).then(function(r) {
                 if (r.ok) { window.location.reload(); }
                 else { return r.json().then(function(d) { errEl.textContent = d.error || 'Something went wrong'; errEl.classList.add('visible'); btn.disabled = false; }); }
@@ -762,7 +769,11 @@ var playlistWatchTemplate = template.Must(template.New("playlist-watch").Funcs(t
 </html>`))
 
 func (h *Handler) PlaylistWatchPage(w http.ResponseWriter, r *http.Request) {
-	shareToken := chi.URLParam(r, "shareToken")
+	shareToken, renamed := h.resolveShareRef(r, true)
+	if renamed != "" {
+		redirectRenamedSlug(w, r, "/watch/playlist/"+renamed)
+		return
+	}
 	nonce := httputil.NonceFromContext(r.Context())
 
 	var playlistID, title string
@@ -787,7 +798,7 @@ func (h *Handler) PlaylistWatchPage(w http.ResponseWriter, r *http.Request) {
 			if err := playlistWatchTemplate.Execute(w, playlistWatchData{
 				Title:         title,
 				Nonce:         nonce,
-				BaseURL:       h.baseURL,
+				BaseURL:       h.publicBaseURL(r),
 				ShareToken:    shareToken,
 				NeedsPassword: true,
 			}); err != nil {
@@ -803,9 +814,10 @@ func (h *Handler) PlaylistWatchPage(w http.ResponseWriter, r *http.Request) {
 			if err := playlistWatchTemplate.Execute(w, playlistWatchData{
 				Title:      title,
 				Nonce:      nonce,
-				BaseURL:    h.baseURL,
+				BaseURL:    h.publicBaseURL(r),
 				ShareToken: shareToken,
 				NeedsEmail: true,
+				GateFields: h.loadPlaylistGateFields(r.Context(), playlistID),
 			}); err != nil {
 				slog.Error("playlist-watch: failed to render email gate page", "error", err)
 			}
@@ -828,7 +840,7 @@ func (h *Handler) PlaylistWatchPage(w http.ResponseWriter, r *http.Request) {
 		Title:       title,
 		Description: descriptionText,
 		Nonce:       nonce,
-		BaseURL:     h.baseURL,
+		BaseURL:     h.publicBaseURL(r),
 		ShareToken:  shareToken,
 		Videos:      videoItems,
 		VideosJSON:  template.JS(videosJSONBytes),
@@ -891,17 +903,65 @@ func (h *Handler) IdentifyPlaylistViewer(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var playlistID string
+	var playlistID, title, fieldsJSON string
 	err := h.db.QueryRow(r.Context(),
-		`SELECT id FROM playlists WHERE share_token = $1 AND is_shared = true`,
+		`SELECT id, title, email_gate_fields FROM playlists WHERE share_token = $1 AND is_shared = true`,
 		shareToken,
-	).Scan(&playlistID)
+	).Scan(&playlistID, &title, &fieldsJSON)
 	if err != nil {
 		httputil.WriteError(w, http.StatusNotFound, "playlist not found")
 		return
 	}
 
-	sig := signEmailGateCookie(h.hmacSecret, shareToken, req.Email)
+	responses, msg := validateGateResponses(decodeGateFields(fieldsJSON), req.Fields)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	h.recordPlaylistViewerAsync(r, playlistID, title, req.Email, responses)
+
+	sig := signEmailGateCookie(h.hmacSecret, shareToken, req.Email, false)
 	setEmailGateCookie(w, shareToken, sig, h.secureCookies)
 	w.WriteHeader(http.StatusOK)
 }
+
+// recordPlaylistViewerAsync is the playlist counterpart of
+// recordViewerIdentityAsync.
+func (h *Handler) recordPlaylistViewerAsync(r *http.Request, playlistID, title, email string, responses map[string]any) {
+	hash, _ := h.viewerHashFor(r.Context(), r, h.playlistAnalyticsPrivacy(r.Context(), playlistID))
+	responsesJSON, _ := json.Marshal(responses)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := h.db.Exec(ctx,
+			`INSERT INTO playlist_viewers (playlist_id, email, viewer_hash, form_responses) VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (playlist_id, email) DO UPDATE SET form_responses = playlist_viewers.form_responses || EXCLUDED.form_responses`,
+			playlistID, email, hash, string(responsesJSON),
+		); err != nil {
+			slog.Error("playlist-watch: failed to record viewer identity", "playlist_id", playlistID, "error", err)
+			return
+		}
+		if h.webhookClient == nil {
+			return
+		}
+		var ownerID string
+		if err := h.db.QueryRow(ctx,
+			`SELECT user_id FROM playlists WHERE id = $1`, playlistID,
+		).Scan(&ownerID); err != nil {
+			return
+		}
+		h.dispatchWebhook(ownerID, webhook.Event{
+			Name:      "viewer.identified",
+			Timestamp: time.Now().UTC(),
+			Data: map[string]any{
+				"playlistId": playlistID,
+				"title":      title,
+				"email":      email,
+				"fields":     responses,
+				"viewerHash": hash,
+			},
+		})
+	}()
+}
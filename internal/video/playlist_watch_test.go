@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/pashagolub/pgxmock/v4"
@@ -268,11 +269,18 @@ func TestIdentifyPlaylistViewer_Success(t *testing.T) {
 	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
 	shareToken := "plidtoken123"
 
-	mock.ExpectQuery(`SELECT id FROM playlists WHERE share_token = \$1 AND is_shared = true`).
+	mock.ExpectQuery(`SELECT id, title, email_gate_fields FROM playlists WHERE share_token = \$1 AND is_shared = true`).
 		WithArgs(shareToken).
-		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("playlist-3"))
-
-	body := strings.NewReader(`{"email":"viewer@example.com"}`)
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "email_gate_fields"}).
+			AddRow("playlist-3", "Onboarding", `[{"key":"company","type":"text","label":"Company","required":true}]`))
+	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM organizations o`).
+		WithArgs("playlist-3").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`INSERT INTO playlist_viewers`).
+		WithArgs("playlist-3", "viewer@example.com", pgxmock.AnyArg(), `{"company":"Acme"}`).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	body := strings.NewReader(`{"email":"viewer@example.com","fields":{"company":" Acme ","unknown":"x"}}`)
 	r := chi.NewRouter()
 	r.Post("/api/watch/playlist/{shareToken}/identify", handler.IdentifyPlaylistViewer)
 
@@ -295,6 +303,8 @@ func TestIdentifyPlaylistViewer_Success(t *testing.T) {
 		t.Error("expected email gate cookie to be set")
 	}
 
+	time.Sleep(100 * time.Millisecond)
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("unmet expectations: %v", err)
 	}
@@ -0,0 +1,134 @@
+package video
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+// saltCache holds the current day's viewer hash salt so only the first
+// request of each UTC day touches the database.
+type saltCache struct {
+	mu   sync.Mutex
+	day  time.Time
+	salt []byte
+}
+
+// analyticsPrivacy reports whether views of a video are recorded in privacy
+// mode, either instance-wide or because the owning organization opted in.
+func (h *Handler) analyticsPrivacy(ctx context.Context, videoID string) bool {
+	if h.analyticsPrivacyMode {
+		return true
+	}
+	var enabled bool
+	if err := h.db.QueryRow(ctx,
+		`SELECT COALESCE(o.analytics_privacy_mode, false)
+		 FROM videos v LEFT JOIN organizations o ON o.id = v.organization_id
+		 WHERE v.id = $1`,
+		videoID,
+	).Scan(&enabled); err != nil {
+		return false
+	}
+	return enabled
+}
+
+// playlistAnalyticsPrivacy is analyticsPrivacy for a playlist. A playlist has
+// no organization of its own, so it is private when the organization its slug
+// is registered in, or one owning any of its videos, opted in; the viewer's
+// hash then matches the one their views of those videos record.
+func (h *Handler) playlistAnalyticsPrivacy(ctx context.Context, playlistID string) bool {
+	if h.analyticsPrivacyMode {
+		return true
+	}
+	var enabled bool
+	if err := h.db.QueryRow(ctx,
+		`SELECT EXISTS (
+		     SELECT 1 FROM organizations o
+		     WHERE o.analytics_privacy_mode
+		       AND (o.id IN (SELECT organization_id FROM playlist_slugs WHERE playlist_id = $1)
+		            OR o.id IN (SELECT v.organization_id FROM playlist_videos pv
+		                        JOIN videos v ON v.id = pv.video_id
+		                        WHERE pv.playlist_id = $1))
+		 )`,
+		playlistID,
+	).Scan(&enabled); err != nil {
+		return false
+	}
+	return enabled
+}
+
+// doNotTrack reports whether the browser sent a Do Not Track or Global
+// Privacy Control signal.
+func doNotTrack(r *http.Request) bool {
+	return r.Header.Get("DNT") == "1" || r.Header.Get("Sec-GPC") == "1"
+}
+
+// viewerHashFor returns the hash that ties a viewer's view and beacons
+// together. In privacy mode it is salted with a secret that rotates every UTC
+// day, so the same person cannot be linked across days. Viewers who send DNT
+// or GPC in privacy mode get a random hash and linked is false.
+func (h *Handler) viewerHashFor(ctx context.Context, r *http.Request, private bool) (hash string, linked bool) {
+	ip := httputil.ClientIP(r)
+	if !private {
+		return viewerHash(ip, r.UserAgent()), true
+	}
+	if doNotTrack(r) {
+		return randomViewerHash(), false
+	}
+	salt, err := h.dailySalt(ctx)
+	if err != nil {
+		slog.Error("video: failed to load analytics salt", "error", err)
+		return randomViewerHash(), false
+	}
+	sum := sha256.New()
+	sum.Write(salt)
+	sum.Write([]byte(ip + "|" + r.UserAgent()))
+	return fmt.Sprintf("%x", sum.Sum(nil)[:8]), true
+}
+
+func randomViewerHash() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%x", b)
+}
+
+// dailySalt returns the salt for the current UTC day, creating it on first
+// use. Earlier salts are deleted as soon as a new one exists so old hashes
+// can never be recomputed.
+func (h *Handler) dailySalt(ctx context.Context) ([]byte, error) {
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+
+	h.salts.mu.Lock()
+	defer h.salts.mu.Unlock()
+	if h.salts.day.Equal(day) && h.salts.salt != nil {
+		return h.salts.salt, nil
+	}
+
+	fresh := make([]byte, 32)
+	if _, err := rand.Read(fresh); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	var salt []byte
+	if err := h.db.QueryRow(ctx,
+		`INSERT INTO analytics_salts (day, salt) VALUES ($1, $2)
+		 ON CONFLICT (day) DO UPDATE SET day = EXCLUDED.day
+		 RETURNING salt`,
+		day, fresh,
+	).Scan(&salt); err != nil {
+		return nil, fmt.Errorf("store salt: %w", err)
+	}
+	if _, err := h.db.Exec(ctx, `DELETE FROM analytics_salts WHERE day < $1`, day); err != nil {
+		slog.Error("video: failed to delete expired analytics salts", "error", err)
+	}
+
+	h.salts.day = day
+	h.salts.salt = salt
+	return salt, nil
+}
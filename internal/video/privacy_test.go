@@ -0,0 +1,234 @@
+package video
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+type stubGeoResolver struct{}
+
+func (stubGeoResolver) Lookup(string) (string, string) { return "DE", "Berlin" }
+
+func newPrivacyRequest(headers map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/watch/abc123", nil)
+	r.RemoteAddr = "203.0.113.7:4000"
+	r.Header.Set("User-Agent", testChromeUA)
+	r.Header.Set("Accept-Language", "en")
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestViewerHashFor_StandardModeIsStable(t *testing.T) {
+	handler := NewHandler(nil, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	r := newPrivacyRequest(nil)
+
+	hash, linked := handler.viewerHashFor(context.Background(), r, false)
+	if !linked {
+		t.Error("expected standard mode hashes to be linked")
+	}
+	if hash != viewerHash("203.0.113.7", testChromeUA) {
+		t.Errorf("expected the unsalted hash, got %q", hash)
+	}
+}
+
+func TestViewerHashFor_PrivateModeUsesDailySalt(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	mock.ExpectQuery(`INSERT INTO analytics_salts \(day, salt\) VALUES \(\$1, \$2\)`).
+		WithArgs(today, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"salt"}).AddRow([]byte("salt-one")))
+	mock.ExpectExec(`DELETE FROM analytics_salts WHERE day < \$1`).
+		WithArgs(today).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	r := newPrivacyRequest(nil)
+	first, linked := handler.viewerHashFor(context.Background(), r, true)
+	if !linked {
+		t.Error("expected salted hashes to be linked within the day")
+	}
+	if first == viewerHash("203.0.113.7", testChromeUA) {
+		t.Error("expected the private hash to differ from the unsalted one")
+	}
+
+	second, _ := handler.viewerHashFor(context.Background(), r, true)
+	if second != first {
+		t.Errorf("expected the cached salt to give the same hash, got %q and %q", first, second)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestViewerHashFor_SaltRotatesDaily(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.salts.day = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	handler.salts.salt = []byte("yesterday")
+
+	mock.ExpectQuery(`INSERT INTO analytics_salts`).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"salt"}).AddRow([]byte("today")))
+	mock.ExpectExec(`DELETE FROM analytics_salts WHERE day < \$1`).
+		WithArgs(time.Now().UTC().Truncate(24 * time.Hour)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	r := newPrivacyRequest(nil)
+	stale := func() string {
+		sum := sha256.Sum256([]byte("yesterday203.0.113.7|" + testChromeUA))
+		return fmt.Sprintf("%x", sum[:8])
+	}()
+	hash, _ := handler.viewerHashFor(context.Background(), r, true)
+
+	if string(handler.salts.salt) != "today" {
+		t.Errorf("expected the salt to rotate, got %q", handler.salts.salt)
+	}
+	if hash == stale {
+		t.Error("expected a different hash after rotation")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestViewerHashFor_DoNotTrackIsUnlinked(t *testing.T) {
+	for _, header := range []string{"DNT", "Sec-GPC"} {
+		t.Run(header, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+			r := newPrivacyRequest(map[string]string{header: "1"})
+
+			first, linked := handler.viewerHashFor(context.Background(), r, true)
+			second, _ := handler.viewerHashFor(context.Background(), r, true)
+			if linked {
+				t.Error("expected DNT viewers to be unlinked")
+			}
+			if first == second {
+				t.Error("expected a fresh hash per request")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("expected no salt lookup: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordViewAsync_PrivacyModeDropsCity(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetGeoResolver(stubGeoResolver{})
+
+	mock.ExpectQuery(`SELECT COALESCE\(o.analytics_privacy_mode, false\)`).
+		WithArgs("vid-1").
+		WillReturnRows(pgxmock.NewRows([]string{"analytics_privacy_mode"}).AddRow(true))
+	mock.ExpectQuery(`INSERT INTO analytics_salts`).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"salt"}).AddRow([]byte("salt")))
+	mock.ExpectExec(`DELETE FROM analytics_salts`).
+		WithArgs(pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`INSERT INTO video_views`).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), "DE", "",
+			nil, nil, nil, nil, nil, nil, false, nil, true).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	handler.recordViewAsync(newPrivacyRequest(nil), viewParams{videoID: "vid-1", ownerID: "owner-1", shareToken: "abc123"})
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestRecordViewAsync_PrivacyModeHonorsDoNotTrack(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetAnalyticsPrivacyMode(true)
+	handler.SetGeoResolver(stubGeoResolver{})
+
+	mock.ExpectExec(`INSERT INTO video_views`).
+		WithArgs("vid-1", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), "", "",
+			nil, nil, nil, nil, nil, nil, false, nil, false).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	handler.recordViewAsync(newPrivacyRequest(map[string]string{"Sec-GPC": "1"}), viewParams{videoID: "vid-1", ownerID: "owner-1", shareToken: "abc123"})
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+// notArg matches any value other than the given one.
+type notArg string
+
+func (n notArg) Match(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && s != string(n)
+}
+
+func TestRecordPlaylistViewerAsync_UsesOrganizationPrivacyMode(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	r := newPrivacyRequest(nil)
+
+	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM organizations o\s+WHERE o\.analytics_privacy_mode`).
+		WithArgs("playlist-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`INSERT INTO analytics_salts`).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"salt"}).AddRow([]byte("salt")))
+	mock.ExpectExec(`DELETE FROM analytics_salts`).
+		WithArgs(pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`INSERT INTO playlist_viewers`).
+		WithArgs("playlist-1", "viewer@example.com", notArg(viewerHash("203.0.113.7", testChromeUA)), `{}`).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	handler.recordPlaylistViewerAsync(r, "playlist-1", "Onboarding", "viewer@example.com", map[string]any{})
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
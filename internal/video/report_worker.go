@@ -0,0 +1,363 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/email"
+)
+
+const (
+	reportWorkerInterval = 5 * time.Minute
+	reportBatchSize      = 100
+	reportTopVideoCount  = 5
+	reportNewViewerLimit = 20
+	reportClaimDuration  = 15 * time.Minute
+)
+
+// ReportSender emails scheduled analytics reports with a CSV attachment.
+type ReportSender interface {
+	SendScheduledReport(ctx context.Context, toEmail string, report email.ScheduledReport, attachment *email.Attachment) error
+}
+
+// SlackReportSender posts scheduled analytics reports to the owner's Slack webhook.
+type SlackReportSender interface {
+	SendScheduledReport(ctx context.Context, ownerEmail string, report email.ScheduledReport) error
+}
+
+type dueReportRecipient struct {
+	id         string
+	channel    string
+	email      string
+	timezone   string
+	nextSendAt time.Time
+	reportID   string
+	name       string
+	frequency  string
+	userID     string
+	orgID      *string
+	folderID   *string
+	tagID      *string
+	ownerEmail string
+}
+
+// scope returns the condition on videos v a report covers, with $1 as its
+// argument. Without a folder or tag it matches the analytics dashboard.
+// Viewers' reply clips are never part of a report.
+func (d dueReportRecipient) scope() (string, any) {
+	switch {
+	case d.folderID != nil:
+		return "v.folder_id = $1 AND v.parent_video_id IS NULL", *d.folderID
+	case d.tagID != nil:
+		return "v.id IN (SELECT video_id FROM video_tags WHERE tag_id = $1) AND v.parent_video_id IS NULL", *d.tagID
+	case d.orgID != nil:
+		return "v.organization_id = $1 AND v.parent_video_id IS NULL", *d.orgID
+	default:
+		return "v.user_id = $1 AND v.parent_video_id IS NULL", d.userID
+	}
+}
+
+type builtReport struct {
+	report email.ScheduledReport
+	csv    email.Attachment
+}
+
+func completionPercent(finished, viewers int64) int {
+	if viewers == 0 {
+		return 0
+	}
+	return int(min(finished*100/viewers, 100))
+}
+
+func buildScheduledReport(ctx context.Context, db database.DBTX, d dueReportRecipient, start, end time.Time, loc *time.Location, baseURL string) (*builtReport, error) {
+	scope, scopeArg := d.scope()
+	report := email.ScheduledReport{
+		Name:         d.name,
+		Frequency:    d.frequency,
+		Period:       reportPeriodLabel(d.frequency, start, end),
+		TopVideos:    make([]email.ReportVideoSummary, 0),
+		NewViewers:   make([]email.ReportViewer, 0),
+		DashboardURL: baseURL + "/analytics",
+	}
+
+	var views, uniqueViews, viewerVideos, finished, ctaClicks int64
+	err := db.QueryRow(ctx,
+		fmt.Sprintf(`SELECT COUNT(*), COUNT(DISTINCT vv.viewer_hash), COUNT(DISTINCT (vv.video_id, vv.viewer_hash)),
+		        (SELECT COUNT(*) FROM view_milestones m JOIN videos v ON v.id = m.video_id
+		         WHERE %[1]s AND v.status != 'deleted' AND m.milestone = 100
+		           AND m.created_at >= $2 AND m.created_at < $3),
+		        (SELECT COUNT(*) FROM cta_clicks c JOIN videos v ON v.id = c.video_id
+		         WHERE %[1]s AND v.status != 'deleted' AND c.created_at >= $2 AND c.created_at < $3)
+		 FROM video_views vv
+		 JOIN videos v ON v.id = vv.video_id
+		 WHERE %[1]s AND v.status != 'deleted' AND NOT vv.is_bot
+		   AND vv.created_at >= $2 AND vv.created_at < $3`, scope),
+		scopeArg, start, end,
+	).Scan(&views, &uniqueViews, &viewerVideos, &finished, &ctaClicks)
+	if err != nil {
+		return nil, fmt.Errorf("query totals: %w", err)
+	}
+	report.TotalViews = int(views)
+	report.UniqueViews = int(uniqueViews)
+	report.Completion = completionPercent(finished, viewerVideos)
+	report.CtaClicks = int(ctaClicks)
+	report.CtaRate = percentOf(ctaClicks, views)
+
+	rows, err := db.Query(ctx,
+		fmt.Sprintf(`SELECT v.title, v.share_token, od.hostname, COUNT(*) AS views, COUNT(DISTINCT vv.viewer_hash),
+		        (SELECT COUNT(*) FROM view_milestones m WHERE m.video_id = v.id AND m.milestone = 100
+		           AND m.created_at >= $2 AND m.created_at < $3),
+		        (SELECT COUNT(*) FROM cta_clicks c WHERE c.video_id = v.id
+		           AND c.created_at >= $2 AND c.created_at < $3)
+		 FROM video_views vv
+		 JOIN videos v ON v.id = vv.video_id
+		 LEFT JOIN organization_domains od ON od.organization_id = v.organization_id AND od.verified_at IS NOT NULL
+		 WHERE %s AND v.status != 'deleted' AND NOT vv.is_bot
+		   AND vv.created_at >= $2 AND vv.created_at < $3
+		 GROUP BY v.id, v.title, v.share_token, od.hostname
+		 ORDER BY views DESC, v.title
+		 LIMIT %d`, scope, reportTopVideoCount),
+		scopeArg, start, end,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query top videos: %w", err)
+	}
+	for rows.Next() {
+		var title, shareToken string
+		var customHostname *string
+		var videoViews, videoUnique, videoFinished, videoClicks int64
+		if err := rows.Scan(&title, &shareToken, &customHostname, &videoViews, &videoUnique, &videoFinished, &videoClicks); err != nil {
+			continue
+		}
+		watchBaseURL := baseURL
+		if customHostname != nil {
+			watchBaseURL = customDomainBaseURL(baseURL, *customHostname)
+		}
+		report.TopVideos = append(report.TopVideos, email.ReportVideoSummary{
+			Title:       title,
+			Views:       int(videoViews),
+			UniqueViews: int(videoUnique),
+			Completion:  completionPercent(videoFinished, videoUnique),
+			CtaClicks:   int(videoClicks),
+			WatchURL:    watchBaseURL + "/watch/" + shareToken,
+		})
+	}
+	rows.Close()
+
+	// A viewer is new when their first identification anywhere in scope
+	// falls inside the period.
+	rows, err = db.Query(ctx,
+		fmt.Sprintf(`SELECT f.email, f.title, COUNT(*) OVER ()
+		 FROM (
+		     SELECT DISTINCT ON (lower(vw.email)) vw.email, v.title, vw.created_at
+		     FROM video_viewers vw
+		     JOIN videos v ON v.id = vw.video_id
+		     WHERE %s AND v.status != 'deleted'
+		     ORDER BY lower(vw.email), vw.created_at
+		 ) f
+		 WHERE f.created_at >= $2 AND f.created_at < $3
+		 ORDER BY f.created_at
+		 LIMIT %d`, scope, reportNewViewerLimit),
+		scopeArg, start, end,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query new viewers: %w", err)
+	}
+	for rows.Next() {
+		var viewer email.ReportViewer
+		var total int64
+		if err := rows.Scan(&viewer.Email, &viewer.VideoTitle, &total); err != nil {
+			continue
+		}
+		report.NewViewers = append(report.NewViewers, viewer)
+		report.NewViewerCount = int(total)
+	}
+	rows.Close()
+
+	var buf bytes.Buffer
+	if err := writeReportCSV(ctx, db, &buf, scope, scopeArg, start, end, loc, report.TotalViews); err != nil {
+		return nil, fmt.Errorf("build csv: %w", err)
+	}
+
+	return &builtReport{
+		report: report,
+		csv: email.Attachment{
+			Filename:    "analytics-report-" + start.Format("2006-01-02") + ".csv",
+			ContentType: "text/csv",
+			Data:        buf.Bytes(),
+		},
+	}, nil
+}
+
+// writeReportCSV writes the report's daily views, bucketed by the recipient's
+// local date so the rows cover exactly the report period, followed by its
+// campaign breakdown. It reads raw views: the rollup's days are UTC.
+func writeReportCSV(ctx context.Context, db database.DBTX, w io.Writer, scope string, scopeArg any,
+	start, end time.Time, loc *time.Location, totalViews int) error {
+	rows, err := db.Query(ctx,
+		fmt.Sprintf(`SELECT (vv.created_at AT TIME ZONE $4)::date AS day, COUNT(*), COUNT(DISTINCT vv.viewer_hash)
+		 FROM video_views vv
+		 JOIN videos v ON v.id = vv.video_id
+		 WHERE %s AND v.status != 'deleted' AND NOT vv.is_bot
+		   AND vv.created_at >= $2 AND vv.created_at < $3
+		 GROUP BY 1 ORDER BY 1`, scope),
+		scopeArg, start, end, loc.String(),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	_, _ = fmt.Fprintln(w, "Date,Views,Unique Views")
+	for rows.Next() {
+		var day time.Time
+		var views, uv int64
+		if err := rows.Scan(&day, &views, &uv); err == nil {
+			_, _ = fmt.Fprintf(w, "%s,%d,%d\n", day.Format("2006-01-02"), views, uv)
+		}
+	}
+	rows.Close()
+
+	writeCampaignCSV(w, csv.NewWriter(w), campaignBreakdown(ctx, db,
+		scope+" AND v.status != 'deleted' AND NOT vv.is_bot AND vv.created_at >= $2 AND vv.created_at < $3",
+		[]any{scopeArg, start, end}, int64(totalViews)))
+	return nil
+}
+
+type reportKey struct {
+	reportID   string
+	start, end time.Time
+}
+
+// deliverScheduledReport builds the recipient's report, reusing one already
+// built for the same report and period, and sends it. It reports false
+// without an error when the recipient's channel is not configured here.
+func deliverScheduledReport(ctx context.Context, db database.DBTX, sender ReportSender, slack SlackReportSender,
+	d dueReportRecipient, loc *time.Location, baseURL string, built map[reportKey]*builtReport) (bool, error) {
+	start, end := reportPeriod(d.frequency, d.nextSendAt, loc)
+	key := reportKey{d.reportID, start, end}
+	b, ok := built[key]
+	if !ok {
+		var err error
+		b, err = buildScheduledReport(ctx, db, d, start, end, loc, baseURL)
+		if err != nil {
+			return false, fmt.Errorf("build report: %w", err)
+		}
+		built[key] = b
+	}
+
+	switch {
+	case d.channel == "slack" && slack != nil:
+		return true, slack.SendScheduledReport(ctx, d.ownerEmail, b.report)
+	case d.channel == "email" && sender != nil:
+		attachment := b.csv
+		return true, sender.SendScheduledReport(ctx, d.email, b.report, &attachment)
+	default:
+		return false, nil
+	}
+}
+
+func processScheduledReports(ctx context.Context, db database.DBTX, sender ReportSender, slack SlackReportSender, baseURL string, now time.Time) {
+	rows, err := db.Query(ctx,
+		`SELECT rr.id, rr.channel, COALESCE(rr.email, ''), rr.timezone, rr.next_send_at,
+		        r.id, r.name, r.frequency, r.user_id, r.organization_id, r.folder_id, r.tag_id, u.email
+		 FROM scheduled_report_recipients rr
+		 JOIN scheduled_reports r ON r.id = rr.report_id
+		 JOIN users u ON u.id = r.user_id
+		 WHERE rr.next_send_at <= $1 AND (rr.claimed_until IS NULL OR rr.claimed_until < $1)
+		 ORDER BY rr.next_send_at
+		 LIMIT $2`,
+		now, reportBatchSize,
+	)
+	if err != nil {
+		slog.Error("report-worker: query failed", "error", err)
+		return
+	}
+	var due []dueReportRecipient
+	for rows.Next() {
+		var d dueReportRecipient
+		if err := rows.Scan(&d.id, &d.channel, &d.email, &d.timezone, &d.nextSendAt,
+			&d.reportID, &d.name, &d.frequency, &d.userID, &d.orgID, &d.folderID, &d.tagID, &d.ownerEmail); err != nil {
+			slog.Error("report-worker: scan failed", "error", err)
+			continue
+		}
+		due = append(due, d)
+	}
+	rows.Close()
+
+	built := make(map[reportKey]*builtReport)
+	sent := 0
+	for _, d := range due {
+		loc, err := time.LoadLocation(d.timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		// Lease the slot while the report is built and sent so a second
+		// instance skips it. The slot only advances once the report is out;
+		// a failure releases the lease and the next run retries the period.
+		tag, err := db.Exec(ctx,
+			`UPDATE scheduled_report_recipients SET claimed_until = $1
+			 WHERE id = $2 AND next_send_at = $3 AND (claimed_until IS NULL OR claimed_until < $4)`,
+			now.Add(reportClaimDuration), d.id, d.nextSendAt, now,
+		)
+		if err != nil {
+			slog.Error("report-worker: failed to claim recipient", "recipient_id", d.id, "error", err)
+			continue
+		}
+		if tag.RowsAffected() == 0 {
+			continue
+		}
+
+		delivered, err := deliverScheduledReport(ctx, db, sender, slack, d, loc, baseURL, built)
+		if err != nil {
+			slog.Error("report-worker: failed to send report", "report_id", d.reportID, "channel", d.channel, "error", err)
+			if _, err := db.Exec(ctx,
+				`UPDATE scheduled_report_recipients SET claimed_until = NULL WHERE id = $1`, d.id,
+			); err != nil {
+				slog.Error("report-worker: failed to release recipient", "recipient_id", d.id, "error", err)
+			}
+			continue
+		}
+
+		if _, err := db.Exec(ctx,
+			`UPDATE scheduled_report_recipients SET next_send_at = $1, last_sent_at = $2, claimed_until = NULL
+			 WHERE id = $3 AND next_send_at = $4`,
+			nextReportSend(d.frequency, now, loc), now, d.id, d.nextSendAt,
+		); err != nil {
+			slog.Error("report-worker: failed to advance recipient", "recipient_id", d.id, "error", err)
+		}
+		if delivered {
+			sent++
+		}
+	}
+	if sent > 0 {
+		slog.Info("report-worker: sent scheduled reports", "count", sent)
+	}
+}
+
+func StartReportWorker(ctx context.Context, db database.DBTX, sender ReportSender, slack SlackReportSender, baseURL string) {
+	if sender == nil && slack == nil {
+		return
+	}
+	go func() {
+		slog.Info("report-worker: started")
+		ticker := time.NewTicker(reportWorkerInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("report-worker: shutting down")
+				return
+			case <-ticker.C:
+				processScheduledReports(ctx, db, sender, slack, baseURL, time.Now().UTC())
+			}
+		}
+	}()
+}
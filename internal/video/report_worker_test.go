@@ -0,0 +1,240 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/email"
+)
+
+type sentReport struct {
+	to         string
+	report     email.ScheduledReport
+	attachment *email.Attachment
+}
+
+type mockReportSender struct {
+	sent []sentReport
+}
+
+func (m *mockReportSender) SendScheduledReport(_ context.Context, toEmail string, report email.ScheduledReport, attachment *email.Attachment) error {
+	m.sent = append(m.sent, sentReport{to: toEmail, report: report, attachment: attachment})
+	return nil
+}
+
+type failingSlackReportSender struct{}
+
+func (failingSlackReportSender) SendScheduledReport(context.Context, string, email.ScheduledReport) error {
+	return errors.New("slack unavailable")
+}
+
+type mockSlackReportSender struct {
+	owners []string
+}
+
+func (m *mockSlackReportSender) SendScheduledReport(_ context.Context, ownerEmail string, _ email.ScheduledReport) error {
+	m.owners = append(m.owners, ownerEmail)
+	return nil
+}
+
+var dueRecipientColumns = []string{
+	"id", "channel", "email", "timezone", "next_send_at",
+	"report_id", "name", "frequency", "user_id", "organization_id", "folder_id", "tag_id", "owner_email",
+}
+
+func TestProcessScheduledReports_BuildsOnceAndSendsToEachChannel(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	now := time.Date(2026, 3, 9, 9, 2, 0, 0, time.UTC)
+	slot := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	orgID := "org-1"
+	sender := &mockReportSender{}
+	slack := &mockSlackReportSender{}
+
+	mock.ExpectQuery(`FROM scheduled_report_recipients rr`).
+		WithArgs(now, reportBatchSize).
+		WillReturnRows(pgxmock.NewRows(dueRecipientColumns).
+			AddRow("rec-1", "email", "boss@example.com", "UTC", slot,
+				"report-1", "Team weekly", "weekly", "user-1", &orgID, (*string)(nil), (*string)(nil), "owner@example.com").
+			AddRow("rec-2", "slack", "", "UTC", slot,
+				"report-1", "Team weekly", "weekly", "user-1", &orgID, (*string)(nil), (*string)(nil), "owner@example.com"))
+
+	periodStart := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	nextSlot := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE scheduled_report_recipients SET claimed_until = \$1`).
+		WithArgs(now.Add(reportClaimDuration), "rec-1", slot, now).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectQuery(`SELECT COUNT\(\*\), COUNT\(DISTINCT vv\.viewer_hash\)`).
+		WithArgs(orgID, periodStart, slot.Truncate(24*time.Hour)).
+		WillReturnRows(pgxmock.NewRows([]string{"views", "unique", "viewer_videos", "finished", "clicks"}).
+			AddRow(int64(40), int64(20), int64(25), int64(10), int64(4)))
+	mock.ExpectQuery(`SELECT v\.title, v\.share_token, od\.hostname`).
+		WithArgs(orgID, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"title", "share_token", "hostname", "views", "unique", "finished", "clicks"}).
+			AddRow("Demo", "tok-1", (*string)(nil), int64(30), int64(15), int64(9), int64(3)))
+	mock.ExpectQuery(`SELECT DISTINCT ON \(lower\(vw\.email\)\)`).
+		WithArgs(orgID, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "title", "total"}).
+			AddRow("lead@example.com", "Demo", int64(3)))
+	mock.ExpectQuery(`SELECT \(vv\.created_at AT TIME ZONE \$4\)::date AS day`).
+		WithArgs(orgID, periodStart, slot.Truncate(24*time.Hour), "UTC").
+		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}).
+			AddRow(periodStart, int64(40), int64(20)))
+	mock.ExpectQuery(`SELECT COALESCE\(vv\.utm_source`).
+		WithArgs(orgID, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"s", "m", "c", "t", "co", "r", "views", "unique_views"}))
+	mock.ExpectExec(`UPDATE scheduled_report_recipients SET next_send_at = \$1, last_sent_at = \$2, claimed_until = NULL`).
+		WithArgs(nextSlot, now, "rec-1", slot).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`UPDATE scheduled_report_recipients SET claimed_until = \$1`).
+		WithArgs(now.Add(reportClaimDuration), "rec-2", slot, now).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`UPDATE scheduled_report_recipients SET next_send_at = \$1, last_sent_at = \$2, claimed_until = NULL`).
+		WithArgs(nextSlot, now, "rec-2", slot).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	processScheduledReports(context.Background(), mock, sender, slack, "https://app.sendrec.eu", now)
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected 1 email report, got %d", len(sender.sent))
+	}
+	got := sender.sent[0]
+	if got.to != "boss@example.com" {
+		t.Errorf("expected report to boss@example.com, got %s", got.to)
+	}
+	r := got.report
+	if r.Period != "2–8 Mar 2026" || r.TotalViews != 40 || r.Completion != 40 || r.CtaRate != 10 || r.NewViewerCount != 3 {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if len(r.TopVideos) != 1 || r.TopVideos[0].Completion != 60 || r.TopVideos[0].WatchURL != "https://app.sendrec.eu/watch/tok-1" {
+		t.Errorf("unexpected top videos %+v", r.TopVideos)
+	}
+	if got.attachment == nil || !strings.HasPrefix(string(got.attachment.Data), "Date,Views,Unique Views\n2026-03-02,40,20") {
+		t.Errorf("unexpected attachment %+v", got.attachment)
+	}
+	if len(slack.owners) != 1 || slack.owners[0] != "owner@example.com" {
+		t.Errorf("expected slack report for owner, got %v", slack.owners)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestProcessScheduledReports_SkipsRecipientClaimedElsewhere(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	now := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	sender := &mockReportSender{}
+
+	mock.ExpectQuery(`FROM scheduled_report_recipients rr`).
+		WithArgs(now, reportBatchSize).
+		WillReturnRows(pgxmock.NewRows(dueRecipientColumns).
+			AddRow("rec-1", "email", "boss@example.com", "UTC", now,
+				"report-1", "Monthly", "monthly", "user-1", (*string)(nil), (*string)(nil), (*string)(nil), "owner@example.com"))
+	mock.ExpectExec(`UPDATE scheduled_report_recipients SET claimed_until = \$1`).
+		WithArgs(now.Add(reportClaimDuration), "rec-1", now, now).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	processScheduledReports(context.Background(), mock, sender, nil, "https://app.sendrec.eu", now)
+
+	if len(sender.sent) != 0 {
+		t.Errorf("expected no report to be sent, got %d", len(sender.sent))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestProcessScheduledReports_KeepsSlotWhenSendFails(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	now := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM scheduled_report_recipients rr`).
+		WithArgs(now, reportBatchSize).
+		WillReturnRows(pgxmock.NewRows(dueRecipientColumns).
+			AddRow("rec-1", "slack", "", "UTC", now,
+				"report-1", "Monthly", "monthly", "user-1", (*string)(nil), (*string)(nil), (*string)(nil), "owner@example.com"))
+	mock.ExpectExec(`UPDATE scheduled_report_recipients SET claimed_until = \$1`).
+		WithArgs(now.Add(reportClaimDuration), "rec-1", now, now).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectQuery(`SELECT COUNT\(\*\), COUNT\(DISTINCT vv\.viewer_hash\)`).
+		WithArgs("user-1", pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"views", "unique", "viewer_videos", "finished", "clicks"}).
+			AddRow(int64(0), int64(0), int64(0), int64(0), int64(0)))
+	mock.ExpectQuery(`SELECT v\.title, v\.share_token, od\.hostname`).
+		WithArgs("user-1", pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"title", "share_token", "hostname", "views", "unique", "finished", "clicks"}))
+	mock.ExpectQuery(`SELECT DISTINCT ON \(lower\(vw\.email\)\)`).
+		WithArgs("user-1", pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "title", "total"}))
+	mock.ExpectQuery(`SELECT \(vv\.created_at AT TIME ZONE \$4\)::date AS day`).
+		WithArgs("user-1", pgxmock.AnyArg(), pgxmock.AnyArg(), "UTC").
+		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}))
+	mock.ExpectQuery(`SELECT COALESCE\(vv\.utm_source`).
+		WithArgs("user-1", pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"s", "m", "c", "t", "co", "r", "views", "unique_views"}))
+	// The lease is released and next_send_at is left alone, so the next run
+	// sends the same period again.
+	mock.ExpectExec(`UPDATE scheduled_report_recipients SET claimed_until = NULL WHERE id = \$1`).
+		WithArgs("rec-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	processScheduledReports(context.Background(), mock, nil, failingSlackReportSender{}, "https://app.sendrec.eu", now)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestWriteReportCSV_UsesRecipientLocalDates(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 7)
+
+	// A view at 23:30 UTC on 1 March is on 2 March in Berlin, so it belongs
+	// to the period and is reported on the local date.
+	mock.ExpectQuery(`SELECT \(vv\.created_at AT TIME ZONE \$4\)::date AS day`).
+		WithArgs("user-1", start, end, "Europe/Berlin").
+		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}).
+			AddRow(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), int64(3), int64(2)))
+	mock.ExpectQuery(`SELECT COALESCE\(vv\.utm_source`).
+		WithArgs("user-1", start, end).
+		WillReturnRows(pgxmock.NewRows([]string{"s", "m", "c", "t", "co", "r", "views", "unique_views"}))
+
+	var buf strings.Builder
+	if err := writeReportCSV(context.Background(), mock, &buf, "v.user_id = $1", "user-1", start, end, loc, 3); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "Date,Views,Unique Views\n2026-03-02,3,2\n") {
+		t.Errorf("unexpected csv %q", buf.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
@@ -0,0 +1,428 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/sendrec/sendrec/internal/database"
+)
+
+const (
+	// maxBeaconRanges bounds one coverage beacon; the player flushes every
+	// few seconds, so a legitimate beacon carries a handful of ranges.
+	maxBeaconRanges = 100
+	// maxBeaconCoverageSeconds caps how much playback one beacon can claim.
+	// Background tabs throttle the flush timer to about once a minute, so
+	// this leaves room for fast playback without letting a client inflate
+	// the curve.
+	maxBeaconCoverageSeconds = 600
+	// maxCoverageSecond clips ranges for videos whose duration is not known
+	// yet (still processing).
+	maxCoverageSecond = 24 * 60 * 60
+	// maxRetentionPoints is the curve resolution ceiling. Videos up to ten
+	// minutes get one point per second; longer ones widen the bucket.
+	maxRetentionPoints      = 600
+	maxRetentionHighlights  = 3
+	coverageCompactionBatch = 500
+)
+
+// coverageSpan is a half-open range of whole seconds [Start, End) that one
+// viewer watched Plays times.
+type coverageSpan struct {
+	Start int
+	End   int
+	Plays int
+}
+
+// normalizeCoverageRanges validates the [start, end] second pairs sent by the
+// player: values are rounded to whole seconds, clipped to the video, and empty
+// or inverted ranges are dropped. Adjacent flushes round to touching ranges,
+// so continuous playback never double-counts a second. Overlaps are kept —
+// they are rewatches.
+func normalizeCoverageRanges(raw [][2]float64, duration int) []coverageSpan {
+	limit := duration
+	if limit <= 0 {
+		limit = maxCoverageSecond
+	}
+	budget := maxBeaconCoverageSeconds
+	spans := make([]coverageSpan, 0, len(raw))
+	for _, r := range raw {
+		if math.IsNaN(r[0]) || math.IsNaN(r[1]) {
+			continue
+		}
+		start := int(math.Round(math.Max(r[0], 0)))
+		end := int(math.Round(math.Min(r[1], float64(limit))))
+		if end <= start {
+			continue
+		}
+		if end-start > budget {
+			end = start + budget
+		}
+		budget -= end - start
+		spans = append(spans, coverageSpan{Start: start, End: end, Plays: 1})
+		if budget == 0 {
+			break
+		}
+	}
+	return spans
+}
+
+// compactCoverage flattens one viewer's spans into disjoint, sorted spans,
+// summing plays where ranges overlap and merging neighbours with equal plays.
+func compactCoverage(spans []coverageSpan) []coverageSpan {
+	type edge struct{ at, delta int }
+	edges := make([]edge, 0, 2*len(spans))
+	for _, s := range spans {
+		if s.End > s.Start && s.Plays > 0 {
+			edges = append(edges, edge{s.Start, s.Plays}, edge{s.End, -s.Plays})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].at < edges[j].at })
+
+	var out []coverageSpan
+	plays := 0
+	for i := 0; i < len(edges); {
+		at := edges[i].at
+		for i < len(edges) && edges[i].at == at {
+			plays += edges[i].delta
+			i++
+		}
+		if i == len(edges) || plays <= 0 {
+			continue
+		}
+		next := edges[i].at
+		if n := len(out); n > 0 && out[n-1].End == at && out[n-1].Plays == plays {
+			out[n-1].End = next
+		} else {
+			out = append(out, coverageSpan{Start: at, End: next, Plays: plays})
+		}
+	}
+	return out
+}
+
+// recordWatchCoverage stores the validated ranges of one beacon. Rows land
+// uncompacted; compactWatchCoverage folds them into the viewer's spans later.
+func (h *Handler) recordWatchCoverage(ctx context.Context, videoID, viewerHash string, raw [][2]float64, duration int) {
+	spans := normalizeCoverageRanges(raw, duration)
+	if len(spans) == 0 {
+		return
+	}
+	starts := make([]int32, len(spans))
+	ends := make([]int32, len(spans))
+	for i, s := range spans {
+		starts[i], ends[i] = int32(s.Start), int32(s.End)
+	}
+	if _, err := h.db.Exec(ctx,
+		`INSERT INTO watch_coverage (video_id, viewer_hash, start_second, end_second)
+		 SELECT $1, $2, s, e FROM unnest($3::int[], $4::int[]) AS t(s, e)`,
+		videoID, viewerHash, starts, ends,
+	); err != nil {
+		slog.Error("video: failed to record watch coverage", "video_id", videoID, "error", err)
+	}
+}
+
+// compactWatchCoverage merges the raw coverage rows of viewers with new
+// beacons into disjoint spans, so a full playback ends up as a single row no
+// matter how many beacons it took.
+func compactWatchCoverage(ctx context.Context, db database.DBTX) {
+	rows, err := db.Query(ctx,
+		`SELECT DISTINCT video_id, viewer_hash FROM watch_coverage WHERE NOT compacted LIMIT $1`,
+		coverageCompactionBatch,
+	)
+	if err != nil {
+		slog.Error("analytics-rollup: coverage compaction query failed", "error", err)
+		return
+	}
+	type viewerKey struct{ videoID, viewerHash string }
+	var pending []viewerKey
+	for rows.Next() {
+		var k viewerKey
+		if err := rows.Scan(&k.videoID, &k.viewerHash); err != nil {
+			slog.Error("analytics-rollup: coverage compaction scan failed", "error", err)
+			continue
+		}
+		pending = append(pending, k)
+	}
+	rows.Close()
+
+	for _, k := range pending {
+		if err := compactViewerCoverage(ctx, db, k.videoID, k.viewerHash); err != nil {
+			slog.Error("analytics-rollup: coverage compaction failed", "video_id", k.videoID, "error", err)
+		}
+	}
+}
+
+func compactViewerCoverage(ctx context.Context, db database.DBTX, videoID, viewerHash string) error {
+	rows, err := db.Query(ctx,
+		`SELECT id, start_second, end_second, plays FROM watch_coverage WHERE video_id = $1 AND viewer_hash = $2`,
+		videoID, viewerHash,
+	)
+	if err != nil {
+		return fmt.Errorf("load coverage: %w", err)
+	}
+	var ids []int64
+	var spans []coverageSpan
+	for rows.Next() {
+		var id int64
+		var s coverageSpan
+		if err := rows.Scan(&id, &s.Start, &s.End, &s.Plays); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan coverage: %w", err)
+		}
+		ids = append(ids, id)
+		spans = append(spans, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read coverage: %w", err)
+	}
+
+	merged := compactCoverage(spans)
+	starts := make([]int32, len(merged))
+	ends := make([]int32, len(merged))
+	plays := make([]int32, len(merged))
+	for i, s := range merged {
+		starts[i], ends[i], plays[i] = int32(s.Start), int32(s.End), int32(s.Plays)
+	}
+	// One statement, so the old rows disappear only if the merged ones land.
+	// Rows inserted by a beacon in the meantime are not in ids and stay
+	// pending for the next pass.
+	if _, err := db.Exec(ctx,
+		`WITH removed AS (DELETE FROM watch_coverage WHERE id = ANY($3))
+		 INSERT INTO watch_coverage (video_id, viewer_hash, start_second, end_second, plays, compacted)
+		 SELECT $1, $2, s, e, p, true FROM unnest($4::int[], $5::int[], $6::int[]) AS t(s, e, p)`,
+		videoID, viewerHash, ids, starts, ends, plays,
+	); err != nil {
+		return fmt.Errorf("replace coverage: %w", err)
+	}
+	return nil
+}
+
+type retentionPoint struct {
+	Second    int     `json:"second"`
+	Viewers   int64   `json:"viewers"`
+	Retention float64 `json:"retention"`
+	Rewatches int64   `json:"rewatches"`
+}
+
+type retentionHotspot struct {
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	Rewatches int64  `json:"rewatches"`
+	Chapter   string `json:"chapter,omitempty"`
+}
+
+type retentionDropOff struct {
+	Second     int     `json:"second"`
+	Lost       int64   `json:"lost"`
+	Percentage float64 `json:"percentage"`
+	Chapter    string  `json:"chapter,omitempty"`
+}
+
+type chapterRetention struct {
+	Title          string  `json:"title"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	StartRetention float64 `json:"startRetention"`
+	EndRetention   float64 `json:"endRetention"`
+	Rewatches      int64   `json:"rewatches"`
+}
+
+type retentionCurve struct {
+	Viewers       int64              `json:"viewers"`
+	Duration      int                `json:"duration"`
+	BucketSeconds int                `json:"bucketSeconds"`
+	Points        []retentionPoint   `json:"points"`
+	Hotspots      []retentionHotspot `json:"hotspots"`
+	DropOffs      []retentionDropOff `json:"dropOffs"`
+	Chapters      []chapterRetention `json:"chapters"`
+}
+
+// retentionAnalytics builds the audience-retention curve from all recorded
+// watch coverage of a video. It returns nil when nothing has been recorded.
+func (h *Handler) retentionAnalytics(ctx context.Context, videoID string) *retentionCurve {
+	var duration int
+	var chaptersJSON *string
+	if err := h.db.QueryRow(ctx,
+		`SELECT duration, chapters FROM videos WHERE id = $1`, videoID,
+	).Scan(&duration, &chaptersJSON); err != nil {
+		return nil
+	}
+	var chapters []Chapter
+	if chaptersJSON != nil {
+		_ = json.Unmarshal([]byte(*chaptersJSON), &chapters)
+	}
+
+	rows, err := h.db.Query(ctx,
+		`SELECT viewer_hash, start_second, end_second, plays FROM watch_coverage WHERE video_id = $1`,
+		videoID,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	coverage := make(map[string][]coverageSpan)
+	for rows.Next() {
+		var viewer string
+		var s coverageSpan
+		if err := rows.Scan(&viewer, &s.Start, &s.End, &s.Plays); err != nil {
+			return nil
+		}
+		coverage[viewer] = append(coverage[viewer], s)
+	}
+	if rows.Err() != nil {
+		return nil
+	}
+	return buildRetentionCurve(coverage, duration, chapters)
+}
+
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*1000) / 10
+}
+
+// buildRetentionCurve turns per-viewer coverage into per-second viewer and
+// play counts, then samples them into at most maxRetentionPoints points. Each
+// point reports the peak within its bucket.
+func buildRetentionCurve(coverage map[string][]coverageSpan, duration int, chapters []Chapter) *retentionCurve {
+	if len(coverage) == 0 {
+		return nil
+	}
+	length := duration
+	if length <= 0 {
+		for _, spans := range coverage {
+			for _, s := range spans {
+				length = max(length, s.End)
+			}
+		}
+	}
+	if length <= 0 {
+		return nil
+	}
+
+	viewersDiff := make([]int64, length+1)
+	playsDiff := make([]int64, length+1)
+	for _, spans := range coverage {
+		// Compacted spans are disjoint, so each second counts a viewer once;
+		// compacting again folds in rows the worker has not reached yet.
+		for _, s := range compactCoverage(spans) {
+			start, end := min(s.Start, length), min(s.End, length)
+			if end <= start {
+				continue
+			}
+			viewersDiff[start]++
+			viewersDiff[end]--
+			playsDiff[start] += int64(s.Plays)
+			playsDiff[end] -= int64(s.Plays)
+		}
+	}
+	viewersAt := make([]int64, length)
+	rewatchesAt := make([]int64, length)
+	var viewers, plays int64
+	for sec := 0; sec < length; sec++ {
+		viewers += viewersDiff[sec]
+		plays += playsDiff[sec]
+		viewersAt[sec] = viewers
+		rewatchesAt[sec] = plays - viewers
+	}
+
+	total := int64(len(coverage))
+	bucket := max(1, (length+maxRetentionPoints-1)/maxRetentionPoints)
+	curve := &retentionCurve{
+		Viewers:       total,
+		Duration:      length,
+		BucketSeconds: bucket,
+		Points:        make([]retentionPoint, 0, (length+bucket-1)/bucket),
+		Hotspots:      make([]retentionHotspot, 0),
+		DropOffs:      make([]retentionDropOff, 0),
+		Chapters:      make([]chapterRetention, 0),
+	}
+	for start := 0; start < length; start += bucket {
+		p := retentionPoint{Second: start}
+		for sec := start; sec < min(start+bucket, length); sec++ {
+			p.Viewers = max(p.Viewers, viewersAt[sec])
+			p.Rewatches = max(p.Rewatches, rewatchesAt[sec])
+		}
+		p.Retention = percentOf(p.Viewers, total)
+		curve.Points = append(curve.Points, p)
+	}
+
+	sort.SliceStable(chapters, func(i, j int) bool { return chapters[i].Start < chapters[j].Start })
+	chapterAt := func(sec int) string {
+		title := ""
+		for _, ch := range chapters {
+			if float64(sec) >= ch.Start {
+				title = ch.Title
+			}
+		}
+		return title
+	}
+
+	for i := 1; i < len(curve.Points); i++ {
+		lost := curve.Points[i-1].Viewers - curve.Points[i].Viewers
+		if lost > 0 {
+			sec := curve.Points[i].Second
+			curve.DropOffs = append(curve.DropOffs, retentionDropOff{
+				Second: sec, Lost: lost, Percentage: percentOf(lost, total), Chapter: chapterAt(sec),
+			})
+		}
+	}
+	sort.SliceStable(curve.DropOffs, func(i, j int) bool { return curve.DropOffs[i].Lost > curve.DropOffs[j].Lost })
+	if len(curve.DropOffs) > maxRetentionHighlights {
+		curve.DropOffs = curve.DropOffs[:maxRetentionHighlights]
+	}
+	sort.Slice(curve.DropOffs, func(i, j int) bool { return curve.DropOffs[i].Second < curve.DropOffs[j].Second })
+
+	var peakRewatches int64
+	for _, p := range curve.Points {
+		peakRewatches = max(peakRewatches, p.Rewatches)
+	}
+	if peakRewatches > 0 {
+		threshold := max(1, peakRewatches/2)
+		for i := 0; i < len(curve.Points); i++ {
+			if curve.Points[i].Rewatches < threshold {
+				continue
+			}
+			spot := retentionHotspot{Start: curve.Points[i].Second, Chapter: chapterAt(curve.Points[i].Second)}
+			for ; i < len(curve.Points) && curve.Points[i].Rewatches >= threshold; i++ {
+				spot.Rewatches = max(spot.Rewatches, curve.Points[i].Rewatches)
+				spot.End = min(curve.Points[i].Second+bucket, length)
+			}
+			curve.Hotspots = append(curve.Hotspots, spot)
+		}
+		sort.SliceStable(curve.Hotspots, func(i, j int) bool { return curve.Hotspots[i].Rewatches > curve.Hotspots[j].Rewatches })
+		if len(curve.Hotspots) > maxRetentionHighlights {
+			curve.Hotspots = curve.Hotspots[:maxRetentionHighlights]
+		}
+		sort.Slice(curve.Hotspots, func(i, j int) bool { return curve.Hotspots[i].Start < curve.Hotspots[j].Start })
+	}
+
+	for i, ch := range chapters {
+		start := max(0, int(ch.Start))
+		end := length
+		if i+1 < len(chapters) {
+			end = min(int(chapters[i+1].Start), length)
+		}
+		if start >= end {
+			continue
+		}
+		cr := chapterRetention{
+			Title:          ch.Title,
+			Start:          start,
+			End:            end,
+			StartRetention: percentOf(viewersAt[start], total),
+			EndRetention:   percentOf(viewersAt[end-1], total),
+		}
+		for sec := start; sec < end; sec++ {
+			cr.Rewatches = max(cr.Rewatches, rewatchesAt[sec])
+		}
+		curve.Chapters = append(curve.Chapters, cr)
+	}
+	return curve
+}
@@ -0,0 +1,257 @@
+package video
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestNormalizeCoverageRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      [][2]float64
+		duration int
+		want     []coverageSpan
+	}{
+		{
+			name:     "rounds flush boundaries to touching ranges",
+			raw:      [][2]float64{{0, 4.8}, {4.8, 9.7}},
+			duration: 60,
+			want:     []coverageSpan{{0, 5, 1}, {5, 10, 1}},
+		},
+		{
+			name:     "clips to the video and drops empty ranges",
+			raw:      [][2]float64{{-3, 2}, {58, 75}, {9, 9.2}, {20, 10}},
+			duration: 60,
+			want:     []coverageSpan{{0, 2, 1}, {58, 60, 1}},
+		},
+		{
+			name:     "keeps overlapping ranges as rewatches",
+			raw:      [][2]float64{{0, 10}, {4, 8}},
+			duration: 60,
+			want:     []coverageSpan{{0, 10, 1}, {4, 8, 1}},
+		},
+		{
+			name:     "caps the seconds one beacon can claim",
+			raw:      [][2]float64{{0, 500}, {500, 1000}, {1000, 1100}},
+			duration: 3600,
+			want:     []coverageSpan{{0, 500, 1}, {500, 600, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeCoverageRanges(tt.raw, tt.duration)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactCoverage(t *testing.T) {
+	got := compactCoverage([]coverageSpan{
+		{10, 20, 1},
+		{0, 5, 1},
+		{5, 10, 1},
+		{12, 15, 1},
+		{30, 40, 2},
+	})
+	want := []coverageSpan{
+		{0, 12, 1},
+		{12, 15, 2},
+		{15, 20, 1},
+		{30, 40, 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if again := compactCoverage(got); !reflect.DeepEqual(again, want) {
+		t.Errorf("compaction is not idempotent: %v", again)
+	}
+}
+
+func TestBuildRetentionCurve(t *testing.T) {
+	coverage := map[string][]coverageSpan{
+		"a": {{0, 10, 1}},
+		"b": {{0, 6, 1}, {4, 6, 1}},
+		"c": {{0, 3, 1}},
+		"d": {{0, 3, 1}},
+	}
+	chapters := []Chapter{{Title: "Outro", Start: 5}, {Title: "Intro", Start: 0}}
+
+	curve := buildRetentionCurve(coverage, 10, chapters)
+	if curve == nil {
+		t.Fatal("expected a curve")
+	}
+	if curve.Viewers != 4 || curve.BucketSeconds != 1 || len(curve.Points) != 10 {
+		t.Fatalf("unexpected curve shape: viewers=%d bucket=%d points=%d", curve.Viewers, curve.BucketSeconds, len(curve.Points))
+	}
+	if curve.Points[0].Retention != 100 || curve.Points[3].Retention != 50 || curve.Points[9].Retention != 25 {
+		t.Errorf("unexpected retention: %+v", curve.Points)
+	}
+
+	wantDrops := []retentionDropOff{
+		{Second: 3, Lost: 2, Percentage: 50, Chapter: "Intro"},
+		{Second: 6, Lost: 1, Percentage: 25, Chapter: "Outro"},
+	}
+	if !reflect.DeepEqual(curve.DropOffs, wantDrops) {
+		t.Errorf("drop-offs = %+v, want %+v", curve.DropOffs, wantDrops)
+	}
+
+	wantHotspots := []retentionHotspot{{Start: 4, End: 6, Rewatches: 1, Chapter: "Intro"}}
+	if !reflect.DeepEqual(curve.Hotspots, wantHotspots) {
+		t.Errorf("hotspots = %+v, want %+v", curve.Hotspots, wantHotspots)
+	}
+
+	wantChapters := []chapterRetention{
+		{Title: "Intro", Start: 0, End: 5, StartRetention: 100, EndRetention: 50, Rewatches: 1},
+		{Title: "Outro", Start: 5, End: 10, StartRetention: 50, EndRetention: 25, Rewatches: 1},
+	}
+	if !reflect.DeepEqual(curve.Chapters, wantChapters) {
+		t.Errorf("chapters = %+v, want %+v", curve.Chapters, wantChapters)
+	}
+}
+
+func TestBuildRetentionCurve_AdaptiveBuckets(t *testing.T) {
+	coverage := map[string][]coverageSpan{"a": {{0, 2400, 1}}}
+
+	curve := buildRetentionCurve(coverage, 2400, nil)
+	if curve.BucketSeconds != 4 {
+		t.Errorf("expected 4-second buckets for a 40-minute video, got %d", curve.BucketSeconds)
+	}
+	if len(curve.Points) != maxRetentionPoints {
+		t.Errorf("expected %d points, got %d", maxRetentionPoints, len(curve.Points))
+	}
+	if buildRetentionCurve(nil, 2400, nil) != nil {
+		t.Error("expected nil curve without coverage")
+	}
+}
+
+func TestRecordSegments_StoresCoverageRanges(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE share_token = \$1 AND status IN`).
+		WithArgs("abc123").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("vid-1"))
+	mock.ExpectQuery(`SELECT duration FROM videos WHERE id = \$1`).
+		WithArgs("vid-1").
+		WillReturnRows(pgxmock.NewRows([]string{"duration"}).AddRow(30))
+	mock.ExpectExec(`INSERT INTO watch_coverage`).
+		WithArgs("vid-1", pgxmock.AnyArg(), []int32{0, 25}, []int32{5, 30}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/segments", handler.RecordSegments)
+
+	body := `{"ranges":[[0,4.9],[25.2,41],[12,12.1]]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/abc123/segments", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestRecordSegments_TooManyRanges(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/segments", handler.RecordSegments)
+
+	body := `{"ranges":[` + strings.Repeat(`[0,1],`, maxBeaconRanges) + `[0,1]]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/abc123/segments", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestCompactViewerCoverage_ReplacesRowsAtomically(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT id, start_second, end_second, plays FROM watch_coverage`).
+		WithArgs("vid-1", "viewer-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "start_second", "end_second", "plays"}).
+			AddRow(int64(1), 0, 5, 1).
+			AddRow(int64(2), 5, 10, 1).
+			AddRow(int64(3), 3, 7, 1))
+	mock.ExpectExec(`WITH removed AS \(DELETE FROM watch_coverage WHERE id = ANY\(\$3\)\)`).
+		WithArgs("vid-1", "viewer-1", []int64{1, 2, 3}, []int32{0, 3, 7}, []int32{3, 7, 10}, []int32{1, 2, 1}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 3))
+
+	if err := compactViewerCoverage(context.Background(), mock, "vid-1", "viewer-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestRetentionAnalytics_ReadsCoverageAndChapters(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	chapters := `[{"title":"Intro","start":0},{"title":"Demo","start":4}]`
+
+	mock.ExpectQuery(`SELECT duration, chapters FROM videos WHERE id = \$1`).
+		WithArgs("vid-1").
+		WillReturnRows(pgxmock.NewRows([]string{"duration", "chapters"}).AddRow(8, &chapters))
+	mock.ExpectQuery(`SELECT viewer_hash, start_second, end_second, plays FROM watch_coverage`).
+		WithArgs("vid-1").
+		WillReturnRows(pgxmock.NewRows([]string{"viewer_hash", "start_second", "end_second", "plays"}).
+			AddRow("a", 0, 8, 1).
+			AddRow("b", 0, 4, 1))
+
+	curve := handler.retentionAnalytics(context.Background(), "vid-1")
+	if curve == nil {
+		t.Fatal("expected a curve")
+	}
+	if curve.Viewers != 2 || len(curve.Chapters) != 2 || curve.Chapters[1].StartRetention != 50 {
+		t.Errorf("unexpected curve: %+v", curve)
+	}
+	if len(curve.DropOffs) != 1 || curve.DropOffs[0].Chapter != "Demo" {
+		t.Errorf("expected one drop-off in Demo, got %+v", curve.DropOffs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
@@ -0,0 +1,426 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	maxScheduledReports     = 20
+	maxReportRecipients     = 20
+	maxReportNameLength     = 100
+	maxReportRecipientEmail = 254
+	reportSendHour          = 9
+)
+
+type reportRecipient struct {
+	Channel    string `json:"channel"`
+	Email      string `json:"email,omitempty"`
+	Timezone   string `json:"timezone"`
+	NextSendAt string `json:"nextSendAt,omitempty"`
+}
+
+type scheduledReportItem struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Frequency  string            `json:"frequency"`
+	FolderID   *string           `json:"folderId"`
+	TagID      *string           `json:"tagId"`
+	Recipients []reportRecipient `json:"recipients"`
+	CreatedAt  string            `json:"createdAt"`
+}
+
+type scheduledReportRequest struct {
+	Name       string            `json:"name"`
+	Frequency  string            `json:"frequency"`
+	FolderID   *string           `json:"folderId"`
+	TagID      *string           `json:"tagId"`
+	Recipients []reportRecipient `json:"recipients"`
+}
+
+// nextReportSend returns the first send slot after now: 09:00 in loc on a
+// Monday for weekly reports, or on the first of the month for monthly ones.
+func nextReportSend(frequency string, now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	var next time.Time
+	if frequency == "monthly" {
+		next = time.Date(local.Year(), local.Month(), 1, reportSendHour, 0, 0, 0, loc)
+		if !next.After(now) {
+			next = next.AddDate(0, 1, 0)
+		}
+		return next
+	}
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+	next = time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, reportSendHour, 0, 0, 0, loc)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+// reportPeriod returns the last full local week (Monday to Monday) or month
+// before sendAt as a half-open [start, end) range.
+func reportPeriod(frequency string, sendAt time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := sendAt.In(loc)
+	if frequency == "monthly" {
+		end := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+		return end.AddDate(0, -1, 0), end
+	}
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+	end := time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+	return end.AddDate(0, 0, -7), end
+}
+
+func reportPeriodLabel(frequency string, start, end time.Time) string {
+	if frequency == "monthly" {
+		return start.Format("January 2006")
+	}
+	last := end.AddDate(0, 0, -1)
+	switch {
+	case start.Year() != last.Year():
+		return start.Format("2 Jan 2006") + " – " + last.Format("2 Jan 2006")
+	case start.Month() != last.Month():
+		return start.Format("2 Jan") + " – " + last.Format("2 Jan 2006")
+	default:
+		return fmt.Sprintf("%d–%s", start.Day(), last.Format("2 Jan 2006"))
+	}
+}
+
+// normalizeReportRecipients validates recipients, fills in the default
+// timezone and rejects duplicates. It returns an error message on failure.
+func normalizeReportRecipients(in []reportRecipient) ([]reportRecipient, string) {
+	if len(in) == 0 {
+		return nil, "at least one recipient is required"
+	}
+	if len(in) > maxReportRecipients {
+		return nil, fmt.Sprintf("a report can have at most %d recipients", maxReportRecipients)
+	}
+	seen := make(map[string]bool, len(in))
+	out := make([]reportRecipient, 0, len(in))
+	for _, rec := range in {
+		tz := strings.TrimSpace(rec.Timezone)
+		if tz == "" {
+			tz = "UTC"
+		}
+		loc, err := time.LoadLocation(tz)
+		if err != nil || tz == "Local" {
+			return nil, "invalid timezone: " + tz
+		}
+		rec.Timezone = loc.String()
+		rec.NextSendAt = ""
+
+		switch rec.Channel {
+		case "email":
+			rec.Email = strings.TrimSpace(rec.Email)
+			addr, err := mail.ParseAddress(rec.Email)
+			if err != nil || addr.Address != rec.Email || len(rec.Email) > maxReportRecipientEmail {
+				return nil, "invalid recipient email: " + rec.Email
+			}
+		case "slack":
+			rec.Email = ""
+		default:
+			return nil, "recipient channel must be email or slack"
+		}
+
+		key := rec.Channel + ":" + strings.ToLower(rec.Email)
+		if seen[key] {
+			if rec.Channel == "slack" {
+				return nil, "a report can post to Slack only once"
+			}
+			return nil, "duplicate recipient: " + rec.Email
+		}
+		seen[key] = true
+		out = append(out, rec)
+	}
+	return out, ""
+}
+
+// validateScheduledReport checks the request and normalizes it in place.
+func (h *Handler) validateScheduledReport(ctx context.Context, req *scheduledReportRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "report name is required"
+	}
+	if len(req.Name) > maxReportNameLength || strings.ContainsAny(req.Name, "\r\n") {
+		return fmt.Sprintf("report name must be a single line of at most %d characters", maxReportNameLength)
+	}
+	if req.Frequency != "weekly" && req.Frequency != "monthly" {
+		return "frequency must be weekly or monthly"
+	}
+	if req.FolderID != nil && *req.FolderID == "" {
+		req.FolderID = nil
+	}
+	if req.TagID != nil && *req.TagID == "" {
+		req.TagID = nil
+	}
+	if req.FolderID != nil && req.TagID != nil {
+		return "a report can be scoped to a folder or a tag, not both"
+	}
+
+	recipients, msg := normalizeReportRecipients(req.Recipients)
+	if msg != "" {
+		return msg
+	}
+	req.Recipients = recipients
+
+	if req.FolderID != nil && !h.ownsScopeRow(ctx, "folders", *req.FolderID) {
+		return "folder not found"
+	}
+	if req.TagID != nil && !h.ownsScopeRow(ctx, "tags", *req.TagID) {
+		return "tag not found"
+	}
+	return ""
+}
+
+// ownsScopeRow reports whether the folder or tag id belongs to the caller's
+// workspace.
+func (h *Handler) ownsScopeRow(ctx context.Context, table, id string) bool {
+	owner := "user_id"
+	ownerArg := auth.UserIDFromContext(ctx)
+	if orgID := auth.OrgIDFromContext(ctx); orgID != "" {
+		owner = "organization_id"
+		ownerArg = orgID
+	}
+	var exists bool
+	err := h.db.QueryRow(ctx,
+		fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1 AND %s = $2)`, table, owner),
+		id, ownerArg,
+	).Scan(&exists)
+	return err == nil && exists
+}
+
+// reportOwnerFilter scopes scheduled_reports r to the organization in
+// context, or to the caller's personal reports, as $n.
+func reportOwnerFilter(ctx context.Context, n int) (string, any) {
+	if orgID := auth.OrgIDFromContext(ctx); orgID != "" {
+		return fmt.Sprintf("r.organization_id = $%d", n), orgID
+	}
+	return fmt.Sprintf("r.user_id = $%d AND r.organization_id IS NULL", n), auth.UserIDFromContext(ctx)
+}
+
+// replaceReportRecipients swaps the report's recipients for the given ones,
+// each scheduled for its next send slot in its own timezone.
+func (h *Handler) replaceReportRecipients(ctx context.Context, reportID, frequency string, recipients []reportRecipient) ([]reportRecipient, error) {
+	if _, err := h.db.Exec(ctx, `DELETE FROM scheduled_report_recipients WHERE report_id = $1`, reportID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	channels := make([]string, len(recipients))
+	emails := make([]string, len(recipients))
+	timezones := make([]string, len(recipients))
+	nextSends := make([]time.Time, len(recipients))
+	out := make([]reportRecipient, len(recipients))
+	for i, rec := range recipients {
+		loc, _ := time.LoadLocation(rec.Timezone)
+		channels[i] = rec.Channel
+		emails[i] = rec.Email
+		timezones[i] = rec.Timezone
+		nextSends[i] = nextReportSend(frequency, now, loc)
+		rec.NextSendAt = nextSends[i].UTC().Format(time.RFC3339)
+		out[i] = rec
+	}
+
+	_, err := h.db.Exec(ctx,
+		`INSERT INTO scheduled_report_recipients (report_id, channel, email, timezone, next_send_at)
+		 SELECT $1, t.channel, NULLIF(t.email, ''), t.timezone, t.next_send_at
+		 FROM unnest($2::text[], $3::text[], $4::text[], $5::timestamptz[]) AS t(channel, email, timezone, next_send_at)`,
+		reportID, channels, emails, timezones, nextSends,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (h *Handler) ListScheduledReports(w http.ResponseWriter, r *http.Request) {
+	ownerFilter, ownerArg := reportOwnerFilter(r.Context(), 1)
+	rows, err := h.db.Query(r.Context(),
+		`SELECT r.id, r.name, r.frequency, r.folder_id, r.tag_id, r.created_at
+		 FROM scheduled_reports r
+		 WHERE `+ownerFilter+`
+		 ORDER BY r.created_at`,
+		ownerArg,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to list reports")
+		return
+	}
+	defer rows.Close()
+
+	items := make([]scheduledReportItem, 0)
+	index := make(map[string]int)
+	ids := make([]string, 0)
+	for rows.Next() {
+		var item scheduledReportItem
+		var createdAt time.Time
+		if err := rows.Scan(&item.ID, &item.Name, &item.Frequency, &item.FolderID, &item.TagID, &createdAt); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "failed to scan report")
+			return
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.Recipients = make([]reportRecipient, 0)
+		index[item.ID] = len(items)
+		ids = append(ids, item.ID)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to list reports")
+		return
+	}
+	rows.Close()
+
+	if len(ids) > 0 {
+		recRows, err := h.db.Query(r.Context(),
+			`SELECT report_id, channel, COALESCE(email, ''), timezone, next_send_at
+			 FROM scheduled_report_recipients
+			 WHERE report_id = ANY($1)
+			 ORDER BY channel, lower(COALESCE(email, ''))`,
+			ids,
+		)
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "failed to list reports")
+			return
+		}
+		defer recRows.Close()
+		for recRows.Next() {
+			var reportID string
+			var rec reportRecipient
+			var nextSendAt time.Time
+			if err := recRows.Scan(&reportID, &rec.Channel, &rec.Email, &rec.Timezone, &nextSendAt); err != nil {
+				continue
+			}
+			rec.NextSendAt = nextSendAt.UTC().Format(time.RFC3339)
+			if i, ok := index[reportID]; ok {
+				items[i].Recipients = append(items[i].Recipients, rec)
+			}
+		}
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, items)
+}
+
+func (h *Handler) CreateScheduledReport(w http.ResponseWriter, r *http.Request) {
+	var req scheduledReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := h.validateScheduledReport(r.Context(), &req); msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	ownerFilter, ownerArg := reportOwnerFilter(r.Context(), 1)
+	var count int
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT COUNT(*) FROM scheduled_reports r WHERE `+ownerFilter, ownerArg,
+	).Scan(&count); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to check report count")
+		return
+	}
+	if count >= maxScheduledReports {
+		httputil.WriteError(w, http.StatusForbidden, fmt.Sprintf("report limit reached (%d)", maxScheduledReports))
+		return
+	}
+
+	var orgID *string
+	if id := auth.OrgIDFromContext(r.Context()); id != "" {
+		orgID = &id
+	}
+	item := scheduledReportItem{Name: req.Name, Frequency: req.Frequency, FolderID: req.FolderID, TagID: req.TagID}
+	var createdAt time.Time
+	err := h.db.QueryRow(r.Context(),
+		`INSERT INTO scheduled_reports (user_id, organization_id, name, frequency, folder_id, tag_id)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		auth.UserIDFromContext(r.Context()), orgID, req.Name, req.Frequency, req.FolderID, req.TagID,
+	).Scan(&item.ID, &createdAt)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to create report")
+		return
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	item.Recipients, err = h.replaceReportRecipients(r.Context(), item.ID, req.Frequency, req.Recipients)
+	if err != nil {
+		_, _ = h.db.Exec(r.Context(), `DELETE FROM scheduled_reports WHERE id = $1`, item.ID)
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to create report")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, item)
+}
+
+func (h *Handler) UpdateScheduledReport(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "reportId")
+
+	var req scheduledReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := h.validateScheduledReport(r.Context(), &req); msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	ownerFilter, ownerArg := reportOwnerFilter(r.Context(), 6)
+	item := scheduledReportItem{ID: reportID, Name: req.Name, Frequency: req.Frequency, FolderID: req.FolderID, TagID: req.TagID}
+	var createdAt time.Time
+	err := h.db.QueryRow(r.Context(),
+		`UPDATE scheduled_reports r
+		 SET name = $1, frequency = $2, folder_id = $3, tag_id = $4, updated_at = now()
+		 WHERE r.id = $5 AND `+ownerFilter+`
+		 RETURNING r.created_at`,
+		req.Name, req.Frequency, req.FolderID, req.TagID, reportID, ownerArg,
+	).Scan(&createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		httputil.WriteError(w, http.StatusNotFound, "report not found")
+		return
+	}
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to update report")
+		return
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	item.Recipients, err = h.replaceReportRecipients(r.Context(), reportID, req.Frequency, req.Recipients)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to update report recipients")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, item)
+}
+
+func (h *Handler) DeleteScheduledReport(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "reportId")
+	ownerFilter, ownerArg := reportOwnerFilter(r.Context(), 2)
+
+	tag, err := h.db.Exec(r.Context(),
+		`DELETE FROM scheduled_reports r WHERE r.id = $1 AND `+ownerFilter,
+		reportID, ownerArg,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to delete report")
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "report not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
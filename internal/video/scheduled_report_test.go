@@ -0,0 +1,262 @@
+package video
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestNextReportSend(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		frequency string
+		now       time.Time
+		loc       *time.Location
+		want      time.Time
+	}{
+		{"weekly mid-week", "weekly", time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), time.UTC,
+			time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"weekly monday before nine", "weekly", time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC), time.UTC,
+			time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"weekly monday at nine", "weekly", time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), time.UTC,
+			time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)},
+		{"weekly local timezone", "weekly", time.Date(2026, 3, 9, 7, 30, 0, 0, time.UTC), berlin,
+			time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)},
+		{"monthly", "monthly", time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), time.UTC,
+			time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)},
+		{"monthly across year", "monthly", time.Date(2026, 12, 1, 10, 0, 0, 0, time.UTC), time.UTC,
+			time.Date(2027, 1, 1, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextReportSend(tt.frequency, tt.now, tt.loc)
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got.UTC())
+			}
+		})
+	}
+}
+
+func TestReportPeriod(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start, end := reportPeriod("weekly", time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC), berlin)
+	if !start.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, berlin)) || !end.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, berlin)) {
+		t.Errorf("unexpected weekly period %v – %v", start, end)
+	}
+	if got := reportPeriodLabel("weekly", start, end); got != "2–8 Mar 2026" {
+		t.Errorf("expected label %q, got %q", "2–8 Mar 2026", got)
+	}
+
+	start, end = reportPeriod("monthly", time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), time.UTC)
+	if !start.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected monthly period %v – %v", start, end)
+	}
+	if got := reportPeriodLabel("monthly", start, end); got != "February 2026" {
+		t.Errorf("expected label %q, got %q", "February 2026", got)
+	}
+
+	start, end = reportPeriod("weekly", time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC), time.UTC)
+	if got := reportPeriodLabel("weekly", start, end); got != "26 Jan – 1 Feb 2026" {
+		t.Errorf("expected label %q, got %q", "26 Jan – 1 Feb 2026", got)
+	}
+}
+
+func TestNormalizeReportRecipients(t *testing.T) {
+	tests := []struct {
+		name       string
+		recipients []reportRecipient
+		wantErr    string
+	}{
+		{"empty", nil, "at least one recipient is required"},
+		{"bad channel", []reportRecipient{{Channel: "sms"}}, "recipient channel must be email or slack"},
+		{"bad email", []reportRecipient{{Channel: "email", Email: "Alice <a@example.com>"}}, "invalid recipient email"},
+		{"bad timezone", []reportRecipient{{Channel: "slack", Timezone: "Mars/Olympus"}}, "invalid timezone"},
+		{"duplicate email", []reportRecipient{
+			{Channel: "email", Email: "a@example.com"}, {Channel: "email", Email: "A@example.com"},
+		}, "duplicate recipient"},
+		{"two slack", []reportRecipient{{Channel: "slack"}, {Channel: "slack"}}, "Slack only once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, msg := normalizeReportRecipients(tt.recipients)
+			if !strings.Contains(msg, tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, msg)
+			}
+		})
+	}
+
+	out, msg := normalizeReportRecipients([]reportRecipient{{Channel: "slack", Email: "ignored@example.com"}})
+	if msg != "" {
+		t.Fatalf("unexpected error %q", msg)
+	}
+	if out[0].Timezone != "UTC" || out[0].Email != "" {
+		t.Errorf("expected slack recipient in UTC without email, got %+v", out[0])
+	}
+}
+
+func TestCreateScheduledReport_Success(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	now := time.Now().UTC().Truncate(time.Second)
+	folderID := "folder-1"
+
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM folders WHERE id = \$1 AND user_id = \$2\)`).
+		WithArgs(folderID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM scheduled_reports r WHERE r\.user_id = \$1 AND r\.organization_id IS NULL`).
+		WithArgs(testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO scheduled_reports`).
+		WithArgs(testUserID, pgxmock.AnyArg(), "Sales weekly", "weekly", &folderID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow("report-1", now))
+	mock.ExpectExec(`DELETE FROM scheduled_report_recipients WHERE report_id = \$1`).
+		WithArgs("report-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`INSERT INTO scheduled_report_recipients`).
+		WithArgs("report-1", []string{"email", "slack"}, []string{"boss@example.com", ""},
+			[]string{"Europe/Berlin", "UTC"}, pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	body, _ := json.Marshal(scheduledReportRequest{
+		Name:      "  Sales weekly ",
+		Frequency: "weekly",
+		FolderID:  &folderID,
+		Recipients: []reportRecipient{
+			{Channel: "email", Email: "boss@example.com", Timezone: "Europe/Berlin"},
+			{Channel: "slack"},
+		},
+	})
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/analytics/reports", handler.CreateScheduledReport)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/analytics/reports", body))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var resp scheduledReportItem
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.ID != "report-1" || resp.Name != "Sales weekly" {
+		t.Errorf("unexpected report %+v", resp)
+	}
+	if len(resp.Recipients) != 2 || resp.Recipients[0].NextSendAt == "" {
+		t.Fatalf("expected 2 scheduled recipients, got %+v", resp.Recipients)
+	}
+	next, err := time.Parse(time.RFC3339, resp.Recipients[0].NextSendAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	if local := next.In(berlin); local.Weekday() != time.Monday || local.Hour() != reportSendHour {
+		t.Errorf("expected next send on Monday 09:00 Berlin time, got %v", local)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestCreateScheduledReport_FolderAndTagRejected(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	folderID, tagID := "folder-1", "tag-1"
+	body, _ := json.Marshal(scheduledReportRequest{
+		Name: "Both", Frequency: "monthly", FolderID: &folderID, TagID: &tagID,
+		Recipients: []reportRecipient{{Channel: "slack"}},
+	})
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/analytics/reports", handler.CreateScheduledReport)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/analytics/reports", body))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "not both") {
+		t.Errorf("unexpected error body %s", rec.Body.String())
+	}
+}
+
+func TestCreateScheduledReport_ForeignTagRejected(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	tagID := "tag-other"
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM tags WHERE id = \$1 AND user_id = \$2\)`).
+		WithArgs(tagID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	body, _ := json.Marshal(scheduledReportRequest{
+		Name: "Tagged", Frequency: "weekly", TagID: &tagID,
+		Recipients: []reportRecipient{{Channel: "email", Email: "a@example.com"}},
+	})
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/analytics/reports", handler.CreateScheduledReport)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/analytics/reports", body))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestDeleteScheduledReport_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	mock.ExpectExec(`DELETE FROM scheduled_reports r WHERE r\.id = \$1 AND r\.user_id = \$2`).
+		WithArgs("report-1", testUserID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Delete("/api/analytics/reports/{reportId}", handler.DeleteScheduledReport)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodDelete, "/api/analytics/reports/report-1", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
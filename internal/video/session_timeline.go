@@ -0,0 +1,421 @@
+package video
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	// maxBeaconEvents bounds the player events in one beacon. A viewer
+	// scrubbing hard produces a few per second; beacons flush every five.
+	maxBeaconEvents    = 200
+	maxSessionOffsetMs = 24 * 60 * 60 * 1000
+	maxSessionsListed  = 50
+	// ctaClickSessionSlack widens a session when matching CTA clicks to it,
+	// since clicks arrive through their own request rather than the beacon.
+	ctaClickSessionSlack = time.Minute
+)
+
+var sessionEventTypes = map[string]bool{
+	"play": true, "pause": true, "seek": true, "ratechange": true, "segment": true, "ended": true,
+}
+
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+// sessionEventInput is one player event in a segments beacon. T is
+// milliseconds since the player page loaded; From is the seek origin or the
+// start of a watched segment.
+type sessionEventInput struct {
+	Type     string   `json:"type"`
+	T        int64    `json:"t"`
+	Position float64  `json:"position"`
+	From     *float64 `json:"from"`
+	Rate     *float64 `json:"rate"`
+}
+
+type sessionEventRow struct {
+	eventType string
+	offsetMs  int32
+	position  float32
+	from      *float32
+	rate      *float32
+}
+
+// normalizeSessionEvents drops unknown or malformed events and clips
+// positions to the video, the same way coverage ranges are clipped.
+func normalizeSessionEvents(raw []sessionEventInput, duration int) []sessionEventRow {
+	limit := float64(duration)
+	if limit <= 0 {
+		limit = maxCoverageSecond
+	}
+	clip := func(v float64) float32 {
+		return float32(math.Min(math.Max(v, 0), limit))
+	}
+	rows := make([]sessionEventRow, 0, len(raw))
+	for _, e := range raw {
+		if !sessionEventTypes[e.Type] || e.T < 0 || e.T > maxSessionOffsetMs ||
+			math.IsNaN(e.Position) || math.IsInf(e.Position, 0) {
+			continue
+		}
+		row := sessionEventRow{eventType: e.Type, offsetMs: int32(e.T), position: clip(e.Position)}
+		switch e.Type {
+		case "seek", "segment":
+			if e.From == nil || math.IsNaN(*e.From) || math.IsInf(*e.From, 0) {
+				continue
+			}
+			from := clip(*e.From)
+			if e.Type == "segment" && from >= row.position {
+				continue
+			}
+			row.from = &from
+		case "ratechange":
+			if e.Rate == nil || !(*e.Rate > 0 && *e.Rate <= 16) {
+				continue
+			}
+			rate := float32(*e.Rate)
+			row.rate = &rate
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// recordSessionEvents stores the player events of one beacon against the
+// viewer's session.
+func (h *Handler) recordSessionEvents(ctx context.Context, videoID, viewerHash, sessionID string, raw []sessionEventInput, duration int) {
+	rows := normalizeSessionEvents(raw, duration)
+	if len(rows) == 0 {
+		return
+	}
+	types := make([]string, len(rows))
+	offsets := make([]int32, len(rows))
+	positions := make([]float32, len(rows))
+	froms := make([]*float32, len(rows))
+	rates := make([]*float32, len(rows))
+	for i, row := range rows {
+		types[i], offsets[i], positions[i], froms[i], rates[i] = row.eventType, row.offsetMs, row.position, row.from, row.rate
+	}
+	if _, err := h.db.Exec(ctx,
+		`INSERT INTO session_events (video_id, session_id, viewer_hash, event, offset_ms, position, from_position, playback_rate)
+		 SELECT $1, $2, $3, e, o, p, f, r
+		 FROM unnest($4::text[], $5::int[], $6::real[], $7::real[], $8::real[]) AS t(e, o, p, f, r)`,
+		videoID, sessionID, viewerHash, types, offsets, positions, froms, rates,
+	); err != nil {
+		slog.Error("video: failed to record session events", "video_id", videoID, "error", err)
+	}
+}
+
+type sessionSummary struct {
+	SessionID      string  `json:"sessionId"`
+	ViewerHash     string  `json:"viewerHash"`
+	Email          *string `json:"email"`
+	StartedAt      string  `json:"startedAt"`
+	LastEventAt    string  `json:"lastEventAt"`
+	WatchedSeconds int     `json:"watchedSeconds"`
+	Seeks          int     `json:"seeks"`
+	Pauses         int     `json:"pauses"`
+	Completed      bool    `json:"completed"`
+}
+
+type sessionListResponse struct {
+	Sessions []sessionSummary `json:"sessions"`
+}
+
+type timelineEvent struct {
+	Type     string   `json:"type"`
+	OffsetMs int64    `json:"offsetMs"`
+	Position *float64 `json:"position"`
+	From     *float64 `json:"from,omitempty"`
+	Rate     *float64 `json:"rate,omitempty"`
+	CtaID    *string  `json:"ctaId,omitempty"`
+	CtaText  string   `json:"ctaText,omitempty"`
+	At       string   `json:"at"`
+}
+
+type timelineSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+	Plays int `json:"plays,omitempty"`
+}
+
+type timelineSeek struct {
+	From      float64 `json:"from"`
+	To        float64 `json:"to"`
+	OffsetMs  int64   `json:"offsetMs"`
+	Direction string  `json:"direction"`
+}
+
+type timelinePause struct {
+	Position   float64 `json:"position"`
+	OffsetMs   int64   `json:"offsetMs"`
+	DurationMs *int64  `json:"durationMs"`
+}
+
+type sessionTimelineResponse struct {
+	SessionID        string          `json:"sessionId"`
+	ViewerHash       string          `json:"viewerHash"`
+	Email            *string         `json:"email"`
+	StartedAt        string          `json:"startedAt"`
+	VideoDuration    int             `json:"videoDuration"`
+	WatchedSeconds   int             `json:"watchedSeconds"`
+	RewatchedSeconds int             `json:"rewatchedSeconds"`
+	Events           []timelineEvent `json:"events"`
+	Watched          []timelineSpan  `json:"watched"`
+	Skipped          []timelineSpan  `json:"skipped"`
+	Seeks            []timelineSeek  `json:"seeks"`
+	Pauses           []timelinePause `json:"pauses"`
+}
+
+// analyticsVideoDuration confirms the caller may read the video's analytics
+// and returns its duration.
+func (h *Handler) analyticsVideoDuration(r *http.Request, videoID string) (int, error) {
+	var query string
+	var args []any
+	if orgID := auth.OrgIDFromContext(r.Context()); orgID != "" {
+		query = `SELECT duration FROM videos WHERE id = $1 AND organization_id = $2 AND status != 'deleted'`
+		args = []any{videoID, orgID}
+	} else {
+		query = `SELECT duration FROM videos WHERE id = $1 AND user_id = $2 AND status != 'deleted'`
+		args = []any{videoID, auth.UserIDFromContext(r.Context())}
+	}
+	var duration int
+	err := h.db.QueryRow(r.Context(), query, args...).Scan(&duration)
+	return duration, err
+}
+
+// AnalyticsSessions lists a video's most recent playback sessions, optionally
+// only those of one email-gate viewer.
+func (h *Handler) AnalyticsSessions(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+	if _, err := h.analyticsVideoDuration(r, videoID); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	args := []any{videoID, maxSessionsListed}
+	emailFilter := ""
+	if email := strings.TrimSpace(r.URL.Query().Get("email")); email != "" {
+		args = append(args, strings.ToLower(email))
+		emailFilter = ` WHERE lower(vw.email) = $3`
+	}
+
+	rows, err := h.db.Query(r.Context(),
+		`SELECT s.session_id, s.viewer_hash, vw.email, s.started_at, s.last_event_at,
+		        s.watched_seconds, s.seeks, s.pauses, s.completed
+		 FROM (
+		     SELECT session_id, viewer_hash, MIN(created_at) AS started_at, MAX(created_at) AS last_event_at,
+		            COALESCE(SUM(position - from_position) FILTER (WHERE event = 'segment'), 0)::float8 AS watched_seconds,
+		            COUNT(*) FILTER (WHERE event = 'seek') AS seeks,
+		            COUNT(*) FILTER (WHERE event = 'pause') AS pauses,
+		            bool_or(event = 'ended') AS completed
+		     FROM session_events WHERE video_id = $1
+		     GROUP BY session_id, viewer_hash
+		 ) s
+		 LEFT JOIN LATERAL (
+		     SELECT email FROM video_viewers
+		     WHERE video_id = $1 AND viewer_hash = s.viewer_hash
+		     ORDER BY created_at DESC LIMIT 1
+		 ) vw ON true`+emailFilter+`
+		 ORDER BY s.started_at DESC
+		 LIMIT $2`,
+		args...,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to load sessions")
+		return
+	}
+	defer rows.Close()
+
+	sessions := []sessionSummary{}
+	for rows.Next() {
+		var s sessionSummary
+		var startedAt, lastEventAt time.Time
+		var watched float64
+		if err := rows.Scan(&s.SessionID, &s.ViewerHash, &s.Email, &startedAt, &lastEventAt,
+			&watched, &s.Seeks, &s.Pauses, &s.Completed); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "failed to load sessions")
+			return
+		}
+		s.StartedAt = startedAt.UTC().Format(time.RFC3339)
+		s.LastEventAt = lastEventAt.UTC().Format(time.RFC3339)
+		s.WatchedSeconds = int(math.Round(watched))
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to load sessions")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, sessionListResponse{Sessions: sessions})
+}
+
+// AnalyticsSessionTimeline returns one session's events in playback order,
+// with the CTA clicks the same viewer made during it, and the watched,
+// rewatched and skipped parts of the video for drawing over the seek bar.
+func (h *Handler) AnalyticsSessionTimeline(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+	sessionID := chi.URLParam(r, "sessionId")
+	duration, err := h.analyticsVideoDuration(r, videoID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(),
+		`SELECT viewer_hash, event, offset_ms, position, from_position, playback_rate, created_at
+		 FROM session_events
+		 WHERE video_id = $1 AND session_id = $2
+		 ORDER BY offset_ms, id`,
+		videoID, sessionID,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to load session")
+		return
+	}
+	defer rows.Close()
+
+	resp := sessionTimelineResponse{SessionID: sessionID, VideoDuration: duration}
+	var start, last time.Time
+	for rows.Next() {
+		var e timelineEvent
+		var position float32
+		var from, rate *float32
+		var createdAt time.Time
+		if err := rows.Scan(&resp.ViewerHash, &e.Type, &e.OffsetMs, &position, &from, &rate, &createdAt); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "failed to load session")
+			return
+		}
+		e.Position = roundedPosition(&position)
+		e.From = roundedPosition(from)
+		e.Rate = roundedPosition(rate)
+		e.At = createdAt.UTC().Format(time.RFC3339)
+		resp.Events = append(resp.Events, e)
+
+		// Beacons arrive after the events they carry, so the earliest
+		// arrival minus its offset is the best estimate of when the page
+		// loaded.
+		if loaded := createdAt.Add(-time.Duration(e.OffsetMs) * time.Millisecond); start.IsZero() || loaded.Before(start) {
+			start = loaded
+		}
+		if createdAt.After(last) {
+			last = createdAt
+		}
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to load session")
+		return
+	}
+	if len(resp.Events) == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	resp.StartedAt = start.UTC().Format(time.RFC3339)
+
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT email FROM video_viewers WHERE video_id = $1 AND viewer_hash = $2 ORDER BY created_at DESC LIMIT 1`,
+		videoID, resp.ViewerHash,
+	).Scan(&resp.Email); err != nil {
+		resp.Email = nil
+	}
+
+	clicks, err := h.db.Query(r.Context(),
+		`SELECT c.cta_id, COALESCE(vc.text, v.cta_text, ''), c.created_at
+		 FROM cta_clicks c
+		 JOIN videos v ON v.id = c.video_id
+		 LEFT JOIN video_ctas vc ON vc.id = c.cta_id
+		 WHERE c.video_id = $1 AND c.viewer_hash = $2 AND c.created_at BETWEEN $3 AND $4
+		 ORDER BY c.created_at`,
+		videoID, resp.ViewerHash, start.Add(-ctaClickSessionSlack), last.Add(ctaClickSessionSlack),
+	)
+	if err == nil {
+		for clicks.Next() {
+			e := timelineEvent{Type: "cta_click"}
+			var clickedAt time.Time
+			if err := clicks.Scan(&e.CtaID, &e.CtaText, &clickedAt); err != nil {
+				break
+			}
+			e.OffsetMs = max(clickedAt.Sub(start).Milliseconds(), 0)
+			e.At = clickedAt.UTC().Format(time.RFC3339)
+			resp.Events = append(resp.Events, e)
+		}
+		clicks.Close()
+		sort.SliceStable(resp.Events, func(i, j int) bool { return resp.Events[i].OffsetMs < resp.Events[j].OffsetMs })
+	}
+
+	buildSessionVisualization(&resp)
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+func roundedPosition(v *float32) *float64 {
+	if v == nil {
+		return nil
+	}
+	rounded := math.Round(float64(*v)*100) / 100
+	return &rounded
+}
+
+// buildSessionVisualization derives the seek-bar overlay from the ordered
+// events: watched spans with their play counts, the gaps never watched,
+// every seek, and how long each pause lasted.
+func buildSessionVisualization(resp *sessionTimelineResponse) {
+	var spans []coverageSpan
+	var openPause = -1
+	resp.Seeks = []timelineSeek{}
+	resp.Pauses = []timelinePause{}
+	for _, e := range resp.Events {
+		switch e.Type {
+		case "segment":
+			spans = append(spans, coverageSpan{
+				Start: int(math.Round(*e.From)),
+				End:   int(math.Round(*e.Position)),
+				Plays: 1,
+			})
+		case "seek":
+			direction := "forward"
+			if *e.Position < *e.From {
+				direction = "back"
+			}
+			resp.Seeks = append(resp.Seeks, timelineSeek{From: *e.From, To: *e.Position, OffsetMs: e.OffsetMs, Direction: direction})
+		case "pause":
+			resp.Pauses = append(resp.Pauses, timelinePause{Position: *e.Position, OffsetMs: e.OffsetMs})
+			openPause = len(resp.Pauses) - 1
+		case "play":
+			if openPause >= 0 {
+				d := e.OffsetMs - resp.Pauses[openPause].OffsetMs
+				resp.Pauses[openPause].DurationMs = &d
+				openPause = -1
+			}
+		}
+	}
+
+	resp.Watched = []timelineSpan{}
+	for _, s := range compactCoverage(spans) {
+		resp.Watched = append(resp.Watched, timelineSpan{Start: s.Start, End: s.End, Plays: s.Plays})
+		resp.WatchedSeconds += s.End - s.Start
+		resp.RewatchedSeconds += (s.End - s.Start) * (s.Plays - 1)
+	}
+
+	resp.Skipped = []timelineSpan{}
+	if resp.VideoDuration > 0 {
+		cursor := 0
+		for _, s := range resp.Watched {
+			if s.Start > cursor {
+				resp.Skipped = append(resp.Skipped, timelineSpan{Start: cursor, End: s.Start})
+			}
+			cursor = max(cursor, s.End)
+		}
+		if cursor < resp.VideoDuration {
+			resp.Skipped = append(resp.Skipped, timelineSpan{Start: cursor, End: resp.VideoDuration})
+		}
+	}
+}
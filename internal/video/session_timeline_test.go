@@ -0,0 +1,256 @@
+package video
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func f32(v float32) *float32 { return &v }
+
+func f64(v float64) *float64 { return &v }
+
+func TestNormalizeSessionEvents(t *testing.T) {
+	rows := normalizeSessionEvents([]sessionEventInput{
+		{Type: "play", T: 0, Position: 0},
+		{Type: "seek", T: 100, Position: 90, From: f64(4)},
+		{Type: "segment", T: 200, Position: 3, From: f64(8)},
+		{Type: "ratechange", T: 300, Position: 5, Rate: f64(1.5)},
+		{Type: "ratechange", T: 300, Position: 5, Rate: f64(40)},
+		{Type: "seek", T: 400, Position: 10},
+		{Type: "scrub", T: 500, Position: 1},
+		{Type: "pause", T: -1, Position: 1},
+	}, 60)
+
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 valid events, got %d: %+v", len(rows), rows)
+	}
+	if rows[1].eventType != "seek" || rows[1].position != 60 || *rows[1].from != 4 {
+		t.Errorf("expected the seek target clipped to the duration, got %+v", rows[1])
+	}
+	if rows[2].eventType != "ratechange" || *rows[2].rate != 1.5 {
+		t.Errorf("expected the valid rate change, got %+v", rows[2])
+	}
+}
+
+func TestRecordSegments_StoresSessionEvents(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE share_token = \$1 AND status IN`).
+		WithArgs("abc123").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("vid-1"))
+	mock.ExpectQuery(`SELECT duration FROM videos WHERE id = \$1`).
+		WithArgs("vid-1").
+		WillReturnRows(pgxmock.NewRows([]string{"duration"}).AddRow(30))
+	mock.ExpectExec(`INSERT INTO session_events`).
+		WithArgs("vid-1", "session-abc123", pgxmock.AnyArg(),
+			[]string{"play", "seek"}, []int32{0, 1500}, []float32{0, 20},
+			[]*float32{nil, f32(2)}, []*float32{nil, nil}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/segments", handler.RecordSegments)
+
+	body := `{"sessionId":"session-abc123","events":[{"type":"play","t":0,"position":0},{"type":"seek","t":1500,"position":20,"from":2}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/abc123/segments", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestRecordSegments_InvalidSessionID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/segments", handler.RecordSegments)
+
+	body := `{"sessionId":"no spaces!","events":[{"type":"play","t":0,"position":0}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/abc123/segments", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestAnalyticsSessions_FiltersByEmail(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	email := "ada@example.com"
+
+	mock.ExpectQuery(`SELECT duration FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("vid-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"duration"}).AddRow(60))
+	mock.ExpectQuery(`FROM session_events WHERE video_id = \$1`).
+		WithArgs("vid-1", maxSessionsListed, "ada@example.com").
+		WillReturnRows(pgxmock.NewRows([]string{"session_id", "viewer_hash", "email", "started_at", "last_event_at", "watched_seconds", "seeks", "pauses", "completed"}).
+			AddRow("session-1", "viewer-1", &email, started, started.Add(2*time.Minute), 41.6, 3, 1, true))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics/sessions", handler.AnalyticsSessions)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/vid-1/analytics/sessions?email=Ada@example.com", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp sessionListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(resp.Sessions))
+	}
+	s := resp.Sessions[0]
+	if s.SessionID != "session-1" || *s.Email != email || s.WatchedSeconds != 42 || s.Seeks != 3 || !s.Completed {
+		t.Errorf("unexpected session summary %+v", s)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestAnalyticsSessionTimeline_BuildsVisualization(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	loaded := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	// Every event arrived in one beacon 30s after the page loaded, so the
+	// latest event (27s) places the load 3s late.
+	arrived := loaded.Add(30 * time.Second)
+	start := arrived.Add(-27 * time.Second)
+
+	mock.ExpectQuery(`SELECT duration FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("vid-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"duration"}).AddRow(60))
+	mock.ExpectQuery(`FROM session_events\s+WHERE video_id = \$1 AND session_id = \$2`).
+		WithArgs("vid-1", "session-1").
+		WillReturnRows(pgxmock.NewRows([]string{"viewer_hash", "event", "offset_ms", "position", "from_position", "playback_rate", "created_at"}).
+			AddRow("viewer-1", "play", int64(0), float32(0), nil, nil, arrived).
+			AddRow("viewer-1", "segment", int64(10000), float32(10), f32(0), nil, arrived).
+			AddRow("viewer-1", "seek", int64(10000), float32(30), f32(10), nil, arrived).
+			AddRow("viewer-1", "segment", int64(15000), float32(35), f32(30), nil, arrived).
+			AddRow("viewer-1", "pause", int64(15000), float32(35), nil, nil, arrived).
+			AddRow("viewer-1", "play", int64(20000), float32(35), nil, nil, arrived).
+			AddRow("viewer-1", "seek", int64(21000), float32(5), f32(35), nil, arrived).
+			AddRow("viewer-1", "segment", int64(27000), float32(11), f32(5), nil, arrived))
+	mock.ExpectQuery(`SELECT email FROM video_viewers`).
+		WithArgs("vid-1", "viewer-1").
+		WillReturnRows(pgxmock.NewRows([]string{"email"}))
+	ctaID := "cta-1"
+	mock.ExpectQuery(`FROM cta_clicks c`).
+		WithArgs("vid-1", "viewer-1", start.Add(-ctaClickSessionSlack), arrived.Add(ctaClickSessionSlack)).
+		WillReturnRows(pgxmock.NewRows([]string{"cta_id", "text", "created_at"}).
+			AddRow(&ctaID, "Book a demo", start.Add(12*time.Second)))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics/sessions/{sessionId}", handler.AnalyticsSessionTimeline)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/vid-1/analytics/sessions/session-1", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp sessionTimelineResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Email != nil || resp.StartedAt != start.Format(time.RFC3339) {
+		t.Errorf("unexpected session header %+v", resp)
+	}
+	if len(resp.Events) != 9 || resp.Events[3].Type != "cta_click" || resp.Events[3].CtaText != "Book a demo" || resp.Events[3].OffsetMs != 12000 {
+		t.Errorf("expected the CTA click merged in by offset, got %+v", resp.Events)
+	}
+	if resp.WatchedSeconds != 16 || resp.RewatchedSeconds != 5 {
+		t.Errorf("expected 16s watched and 5s rewatched, got %d and %d", resp.WatchedSeconds, resp.RewatchedSeconds)
+	}
+	wantWatched := []timelineSpan{{0, 5, 1}, {5, 10, 2}, {10, 11, 1}, {30, 35, 1}}
+	if len(resp.Watched) != len(wantWatched) {
+		t.Fatalf("expected watched spans %v, got %v", wantWatched, resp.Watched)
+	}
+	for i, s := range wantWatched {
+		if resp.Watched[i] != s {
+			t.Errorf("watched[%d]: expected %v, got %v", i, s, resp.Watched[i])
+		}
+	}
+	if len(resp.Skipped) != 2 || resp.Skipped[0] != (timelineSpan{Start: 11, End: 30}) || resp.Skipped[1] != (timelineSpan{Start: 35, End: 60}) {
+		t.Errorf("unexpected skipped spans %v", resp.Skipped)
+	}
+	if len(resp.Seeks) != 2 || resp.Seeks[0].Direction != "forward" || resp.Seeks[1].Direction != "back" {
+		t.Errorf("unexpected seeks %+v", resp.Seeks)
+	}
+	if len(resp.Pauses) != 1 || resp.Pauses[0].DurationMs == nil || *resp.Pauses[0].DurationMs != 5000 {
+		t.Errorf("expected one 5s pause, got %+v", resp.Pauses)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet pgxmock expectations: %v", err)
+	}
+}
+
+func TestAnalyticsSessionTimeline_UnknownSession(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT duration FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("vid-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"duration"}).AddRow(60))
+	mock.ExpectQuery(`FROM session_events`).
+		WithArgs("vid-1", "missing-session").
+		WillReturnRows(pgxmock.NewRows([]string{"viewer_hash", "event", "offset_ms", "position", "from_position", "playback_rate", "created_at"}))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics/sessions/{sessionId}", handler.AnalyticsSessionTimeline)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/vid-1/analytics/sessions/missing-session", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
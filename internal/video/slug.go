@@ -0,0 +1,348 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	minSlugLength = 3
+	maxSlugLength = 64
+
+	// slugSeparator joins an organization slug and a vanity slug into a single
+	// path segment. Share tokens are base64url and never contain it.
+	slugSeparator = "."
+)
+
+// errSlugTaken aborts a slug change whose slug already belongs to another
+// video or playlist in the organization.
+var errSlugTaken = errors.New("slug is already in use")
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*[a-z0-9]$`)
+
+func normalizeSlug(raw string) (string, string) {
+	slug := strings.ToLower(strings.TrimSpace(raw))
+	if len(slug) < minSlugLength || len(slug) > maxSlugLength {
+		return "", fmt.Sprintf("slug must be between %d and %d characters", minSlugLength, maxSlugLength)
+	}
+	if !slugPattern.MatchString(slug) {
+		return "", "slug may only contain lowercase letters, numbers and hyphens, and must start and end with a letter or number"
+	}
+	return slug, ""
+}
+
+type slugResponse struct {
+	Slug *string `json:"slug"`
+	URL  *string `json:"url"`
+}
+
+type setSlugRequest struct {
+	Slug *string `json:"slug"`
+}
+
+// resolveShareRef maps the {shareToken} path segment to a share token. Besides
+// the token itself it accepts a vanity slug, written "<org-slug>.<slug>" on
+// the base domain or bare on the organization's custom domain. When the slug
+// has since been renamed, renamedRef is the segment to redirect to.
+func (h *Handler) resolveShareRef(r *http.Request, playlist bool) (shareToken, renamedRef string) {
+	return h.resolveShareRefValue(r, chi.URLParam(r, "shareToken"), playlist)
+}
+
+// resolveShareRefValue is resolveShareRef for a reference taken from
+// somewhere other than the route, such as the url parameter of an oEmbed
+// request.
+func (h *Handler) resolveShareRefValue(r *http.Request, ref string, playlist bool) (shareToken, renamedRef string) {
+	orgSlug, slug, dotted := strings.Cut(ref, slugSeparator)
+	domain, onCustomDomain := httputil.CustomDomainFromContext(r.Context())
+	if !dotted && !onCustomDomain {
+		return ref, ""
+	}
+
+	query := `SELECT v.share_token, v.slug FROM video_slugs s
+		 JOIN videos v ON v.id = s.video_id AND v.organization_id = s.organization_id`
+	if playlist {
+		query = `SELECT p.share_token, p.slug FROM playlist_slugs s
+		 JOIN playlists p ON p.id = s.playlist_id AND p.is_shared = true`
+	}
+	scope := domain.OrganizationID
+	if dotted {
+		query += ` JOIN organizations o ON o.id = s.organization_id WHERE s.slug = $1 AND o.slug = $2`
+		scope = orgSlug
+	} else {
+		query += ` WHERE s.slug = $1 AND s.organization_id = $2`
+		slug = ref
+	}
+
+	var token, current *string
+	if err := h.db.QueryRow(r.Context(), query, slug, scope).Scan(&token, &current); err != nil || token == nil {
+		return ref, ""
+	}
+	if current == nil || *current == slug {
+		return *token, ""
+	}
+	if dotted {
+		return *token, orgSlug + slugSeparator + *current
+	}
+	return *token, *current
+}
+
+// redirectRenamedSlug permanently redirects a retired slug to its replacement,
+// staying on whichever host the viewer used.
+func redirectRenamedSlug(w http.ResponseWriter, r *http.Request, path string) {
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, path, http.StatusMovedPermanently)
+}
+
+// vanityURL is the public link for a slug: on the organization's verified
+// custom domain when it has one, namespaced by the organization slug otherwise.
+func (h *Handler) vanityURL(ctx context.Context, orgID, pathPrefix, slug string) *string {
+	var orgSlug string
+	var hostname *string
+	err := h.db.QueryRow(ctx,
+		`SELECT o.slug, od.hostname FROM organizations o
+		 LEFT JOIN organization_domains od ON od.organization_id = o.id AND od.verified_at IS NOT NULL
+		 WHERE o.id = $1`,
+		orgID,
+	).Scan(&orgSlug, &hostname)
+	if err != nil {
+		return nil
+	}
+	link := h.baseURL + pathPrefix + orgSlug + slugSeparator + slug
+	if hostname != nil {
+		link = customDomainBaseURL(h.baseURL, *hostname) + pathPrefix + slug
+	}
+	return &link
+}
+
+func (h *Handler) GetVideoSlug(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+	filter, args := orgVideoFilter(r.Context(), videoID, nil, "")
+
+	var orgID, slug *string
+	err := h.db.QueryRow(r.Context(),
+		`SELECT organization_id, slug FROM videos WHERE `+filter,
+		args...,
+	).Scan(&orgID, &slug)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	resp := slugResponse{Slug: slug}
+	if slug != nil && orgID != nil {
+		resp.URL = h.vanityURL(r.Context(), *orgID, "/watch/", *slug)
+	}
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) SetVideoSlug(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+	orgID := auth.OrgIDFromContext(r.Context())
+
+	var req setSlugRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	filter, args := orgVideoFilter(r.Context(), videoID, nil, "")
+	var current *string
+	err := h.db.QueryRow(r.Context(),
+		`SELECT slug FROM videos WHERE `+filter,
+		args...,
+	).Scan(&current)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+	if orgID == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "vanity slugs are only available in an organization workspace")
+		return
+	}
+
+	if req.Slug == nil || strings.TrimSpace(*req.Slug) == "" {
+		err := database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(r.Context(), `DELETE FROM video_slugs WHERE video_id = $1`, videoID); err != nil {
+				return err
+			}
+			_, err := tx.Exec(r.Context(), `UPDATE videos SET slug = NULL WHERE id = $1`, videoID)
+			return err
+		})
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "failed to remove slug")
+			return
+		}
+		httputil.WriteJSON(w, http.StatusOK, slugResponse{})
+		return
+	}
+
+	slug, msg := normalizeSlug(*req.Slug)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if current == nil || *current != slug {
+		err := database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+			var owner string
+			if err := tx.QueryRow(r.Context(),
+				`INSERT INTO video_slugs (organization_id, slug, video_id) VALUES ($1, $2, $3)
+				 ON CONFLICT (organization_id, slug) DO UPDATE SET slug = EXCLUDED.slug
+				 RETURNING video_id`,
+				orgID, slug, videoID,
+			).Scan(&owner); err != nil {
+				return err
+			}
+			if owner != videoID {
+				return errSlugTaken
+			}
+			_, err := tx.Exec(r.Context(), `UPDATE videos SET slug = $1 WHERE id = $2`, slug, videoID)
+			return err
+		})
+		if errors.Is(err, errSlugTaken) {
+			httputil.WriteError(w, http.StatusConflict, "slug is already in use in this organization")
+			return
+		}
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "failed to set slug")
+			return
+		}
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, slugResponse{
+		Slug: &slug,
+		URL:  h.vanityURL(r.Context(), orgID, "/watch/", slug),
+	})
+}
+
+func (h *Handler) GetPlaylistSlug(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	playlistID := chi.URLParam(r, "id")
+
+	var slug, orgID *string
+	err := h.db.QueryRow(r.Context(),
+		`SELECT p.slug, (SELECT s.organization_id FROM playlist_slugs s WHERE s.playlist_id = p.id AND s.slug = p.slug)
+		 FROM playlists p WHERE p.id = $1 AND p.user_id = $2`,
+		playlistID, userID,
+	).Scan(&slug, &orgID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			httputil.WriteError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to get playlist")
+		return
+	}
+
+	resp := slugResponse{Slug: slug}
+	if slug != nil && orgID != nil {
+		resp.URL = h.vanityURL(r.Context(), *orgID, "/watch/playlist/", *slug)
+	}
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) SetPlaylistSlug(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	orgID := auth.OrgIDFromContext(r.Context())
+	playlistID := chi.URLParam(r, "id")
+
+	var req setSlugRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var current *string
+	err := h.db.QueryRow(r.Context(),
+		`SELECT slug FROM playlists WHERE id = $1 AND user_id = $2`,
+		playlistID, userID,
+	).Scan(&current)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			httputil.WriteError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to get playlist")
+		return
+	}
+	if orgID == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "vanity slugs are only available in an organization workspace")
+		return
+	}
+
+	if req.Slug == nil || strings.TrimSpace(*req.Slug) == "" {
+		err := database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(r.Context(), `DELETE FROM playlist_slugs WHERE playlist_id = $1`, playlistID); err != nil {
+				return err
+			}
+			_, err := tx.Exec(r.Context(), `UPDATE playlists SET slug = NULL WHERE id = $1`, playlistID)
+			return err
+		})
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "failed to remove slug")
+			return
+		}
+		httputil.WriteJSON(w, http.StatusOK, slugResponse{})
+		return
+	}
+
+	slug, msg := normalizeSlug(*req.Slug)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	err = database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+		// A playlist's slugs live in the workspace they were set from; moving
+		// it to another workspace drops the old redirects.
+		if _, err := tx.Exec(r.Context(),
+			`DELETE FROM playlist_slugs WHERE playlist_id = $1 AND organization_id <> $2`,
+			playlistID, orgID,
+		); err != nil {
+			return err
+		}
+		var owner string
+		if err := tx.QueryRow(r.Context(),
+			`INSERT INTO playlist_slugs (organization_id, slug, playlist_id) VALUES ($1, $2, $3)
+			 ON CONFLICT (organization_id, slug) DO UPDATE SET slug = EXCLUDED.slug
+			 RETURNING playlist_id`,
+			orgID, slug, playlistID,
+		).Scan(&owner); err != nil {
+			return err
+		}
+		if owner != playlistID {
+			return errSlugTaken
+		}
+		if current != nil && *current == slug {
+			return nil
+		}
+		_, err := tx.Exec(r.Context(), `UPDATE playlists SET slug = $1 WHERE id = $2`, slug, playlistID)
+		return err
+	})
+	if errors.Is(err, errSlugTaken) {
+		httputil.WriteError(w, http.StatusConflict, "slug is already in use in this organization")
+		return
+	}
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to set slug")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, slugResponse{
+		Slug: &slug,
+		URL:  h.vanityURL(r.Context(), orgID, "/watch/playlist/", slug),
+	})
+}
@@ -0,0 +1,317 @@
+package video
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+func TestNormalizeSlug(t *testing.T) {
+	got, msg := normalizeSlug("  Product-Launch-2026 ")
+	if msg != "" || got != "product-launch-2026" {
+		t.Errorf("expected product-launch-2026, got %q (%s)", got, msg)
+	}
+	for _, bad := range []string{"", "ab", "-launch", "launch-", "launch.video", "launch video", "launch_video", string(bytes.Repeat([]byte("a"), maxSlugLength+1))} {
+		if _, msg := normalizeSlug(bad); msg == "" {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func serveSlugRoute(handler http.HandlerFunc, method, pattern, target, orgID string, body []byte, t *testing.T) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).MethodFunc(method, pattern, handler)
+	req := authenticatedRequest(t, method, target, body)
+	if orgID != "" {
+		req = req.WithContext(auth.ContextWithOrg(req.Context(), orgID, "admin"))
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSetVideoSlug_Renames(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	old := "launch"
+
+	mock.ExpectQuery(`SELECT slug FROM videos WHERE id = \$1 AND organization_id = \$2`).
+		WithArgs("video-1", testOrgID).
+		WillReturnRows(pgxmock.NewRows([]string{"slug"}).AddRow(&old))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO video_slugs \(organization_id, slug, video_id\)`).
+		WithArgs(testOrgID, "product-launch", "video-1").
+		WillReturnRows(pgxmock.NewRows([]string{"video_id"}).AddRow("video-1"))
+	mock.ExpectExec(`UPDATE videos SET slug = \$1 WHERE id = \$2`).
+		WithArgs("product-launch", "video-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT o.slug, od.hostname FROM organizations o`).
+		WithArgs(testOrgID).
+		WillReturnRows(pgxmock.NewRows([]string{"slug", "hostname"}).AddRow("acme", (*string)(nil)))
+
+	rec := serveSlugRoute(handler.SetVideoSlug, http.MethodPut, "/api/videos/{id}/slug",
+		"/api/videos/video-1/slug", testOrgID, []byte(`{"slug":"Product-Launch"}`), t)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp slugResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Slug == nil || *resp.Slug != "product-launch" || resp.URL == nil || *resp.URL != testBaseURL+"/watch/acme.product-launch" {
+		t.Errorf("unexpected response: %s", rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetVideoSlug_TakenByAnotherVideo(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT slug FROM videos WHERE`).
+		WithArgs("video-1", testOrgID).
+		WillReturnRows(pgxmock.NewRows([]string{"slug"}).AddRow((*string)(nil)))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO video_slugs`).
+		WithArgs(testOrgID, "launch", "video-1").
+		WillReturnRows(pgxmock.NewRows([]string{"video_id"}).AddRow("video-2"))
+	mock.ExpectRollback()
+
+	rec := serveSlugRoute(handler.SetVideoSlug, http.MethodPut, "/api/videos/{id}/slug",
+		"/api/videos/video-1/slug", testOrgID, []byte(`{"slug":"launch"}`), t)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetVideoSlug_RollsBackHistoryWhenUpdateFails(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT slug FROM videos WHERE`).
+		WithArgs("video-1", testOrgID).
+		WillReturnRows(pgxmock.NewRows([]string{"slug"}).AddRow((*string)(nil)))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO video_slugs`).
+		WithArgs(testOrgID, "launch", "video-1").
+		WillReturnRows(pgxmock.NewRows([]string{"video_id"}).AddRow("video-1"))
+	mock.ExpectExec(`UPDATE videos SET slug = \$1 WHERE id = \$2`).
+		WithArgs("launch", "video-1").
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	rec := serveSlugRoute(handler.SetVideoSlug, http.MethodPut, "/api/videos/{id}/slug",
+		"/api/videos/video-1/slug", testOrgID, []byte(`{"slug":"launch"}`), t)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetVideoSlug_PersonalWorkspace(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT slug FROM videos WHERE id = \$1 AND user_id = \$2 AND organization_id IS NULL`).
+		WithArgs("video-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"slug"}).AddRow((*string)(nil)))
+
+	rec := serveSlugRoute(handler.SetVideoSlug, http.MethodPut, "/api/videos/{id}/slug",
+		"/api/videos/video-1/slug", "", []byte(`{"slug":"launch"}`), t)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSetVideoSlug_Clear(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	old := "launch"
+
+	mock.ExpectQuery(`SELECT slug FROM videos WHERE`).
+		WithArgs("video-1", testOrgID).
+		WillReturnRows(pgxmock.NewRows([]string{"slug"}).AddRow(&old))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM video_slugs WHERE video_id = \$1`).
+		WithArgs("video-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	mock.ExpectExec(`UPDATE videos SET slug = NULL WHERE id = \$1`).
+		WithArgs("video-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	rec := serveSlugRoute(handler.SetVideoSlug, http.MethodPut, "/api/videos/{id}/slug",
+		"/api/videos/video-1/slug", testOrgID, []byte(`{"slug":null}`), t)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetPlaylistSlug(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT slug FROM playlists WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("pl-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"slug"}).AddRow((*string)(nil)))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM playlist_slugs WHERE playlist_id = \$1 AND organization_id <> \$2`).
+		WithArgs("pl-1", testOrgID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectQuery(`INSERT INTO playlist_slugs \(organization_id, slug, playlist_id\)`).
+		WithArgs(testOrgID, "onboarding", "pl-1").
+		WillReturnRows(pgxmock.NewRows([]string{"playlist_id"}).AddRow("pl-1"))
+	mock.ExpectExec(`UPDATE playlists SET slug = \$1 WHERE id = \$2`).
+		WithArgs("onboarding", "pl-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT o.slug, od.hostname FROM organizations o`).
+		WithArgs(testOrgID).
+		WillReturnRows(pgxmock.NewRows([]string{"slug", "hostname"}).AddRow("acme", strPtr("video.acme.com")))
+
+	rec := serveSlugRoute(handler.SetPlaylistSlug, http.MethodPut, "/api/playlists/{id}/slug",
+		"/api/playlists/pl-1/slug", testOrgID, []byte(`{"slug":"onboarding"}`), t)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp slugResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.URL == nil || *resp.URL != "https://video.acme.com/watch/playlist/onboarding" {
+		t.Errorf("expected custom domain URL, got %s", rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestWatchPage_RenamedSlugRedirects(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.share_token, v.slug FROM video_slugs s`).
+		WithArgs("launch", "acme").
+		WillReturnRows(pgxmock.NewRows([]string{"share_token", "slug"}).AddRow(strPtr("abc123defghi"), strPtr("product-launch")))
+
+	req := httptest.NewRequest(http.MethodGet, "/watch/acme.launch?t=30", nil)
+	rec := serveWatchPage(handler, req)
+
+	if rec.Code != http.StatusMovedPermanently {
+		t.Fatalf("expected 301, got %d", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/watch/acme.product-launch?t=30" {
+		t.Errorf("unexpected redirect %q", loc)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestWatchPage_UnknownSlugNotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.share_token, v.slug FROM video_slugs s`).
+		WithArgs("launch", "acme").
+		WillReturnError(errors.New("no rows"))
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("acme.launch").
+		WillReturnError(errors.New("no rows"))
+
+	rec := serveWatchPage(handler, watchPageRequest("acme.launch"))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestPlaylistWatchPage_BareSlugOnCustomDomain(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT p.share_token, p.slug FROM playlist_slugs s`).
+		WithArgs("onboarding-old", "org-1").
+		WillReturnRows(pgxmock.NewRows([]string{"share_token", "slug"}).AddRow(strPtr("pltoken123"), strPtr("onboarding")))
+
+	req := playlistWatchRequest("onboarding-old")
+	req = req.WithContext(httputil.ContextWithCustomDomain(req.Context(), httputil.CustomDomain{OrganizationID: "org-1", BaseURL: "https://video.acme.com"}))
+	rec := servePlaylistWatchPage(handler, req)
+
+	if rec.Code != http.StatusMovedPermanently {
+		t.Fatalf("expected 301, got %d", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/watch/playlist/onboarding" {
+		t.Errorf("unexpected redirect %q", loc)
+	}
+}
@@ -2,11 +2,15 @@ package video
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -24,6 +28,7 @@ type analyticsSummary struct {
 	PeakDayViews      int64   `json:"peakDayViews"`
 	TotalCtaClicks    int64   `json:"totalCtaClicks"`
 	CtaClickRate      float64 `json:"ctaClickRate"`
+	BotViews          int64   `json:"botViews"`
 }
 
 type dailyViews struct {
@@ -73,6 +78,14 @@ type breakdownItem struct {
 	Percentage float64 `json:"percentage"`
 }
 
+type ctaClickStats struct {
+	ID           string `json:"id"`
+	Kind         string `json:"kind"`
+	Text         string `json:"text"`
+	Clicks       int64  `json:"clicks"`
+	UniqueClicks int64  `json:"uniqueClicks"`
+}
+
 type analyticsResponse struct {
 	Summary    analyticsSummary `json:"summary"`
 	Daily      []dailyViews     `json:"daily"`
@@ -83,6 +96,12 @@ type analyticsResponse struct {
 	Referrers  []referrerData   `json:"referrers"`
 	Browsers   []breakdownItem  `json:"browsers"`
 	Devices    []breakdownItem  `json:"devices"`
+	Ctas       []ctaClickStats  `json:"ctas"`
+	Questions  []questionResult `json:"questions"`
+	QuizScores []quizScore      `json:"quizScores"`
+	Retention  *retentionCurve  `json:"retention,omitempty"`
+	Campaigns  []campaignStats  `json:"campaigns"`
+	Filter     *campaignParams  `json:"filter,omitempty"`
 }
 
 type milestoneRequest struct {
@@ -90,7 +109,10 @@ type milestoneRequest struct {
 }
 
 type segmentsRequest struct {
-	Segments []int `json:"segments"`
+	Segments  []int               `json:"segments"`
+	Ranges    [][2]float64        `json:"ranges"`
+	SessionID string              `json:"sessionId"`
+	Events    []sessionEventInput `json:"events"`
 }
 
 func (h *Handler) lookupVideoByShareToken(ctx context.Context, shareToken string) (string, error) {
@@ -102,7 +124,19 @@ func (h *Handler) lookupVideoByShareToken(ctx context.Context, shareToken string
 	return videoID, err
 }
 
+type ctaClickRequest struct {
+	CtaID string `json:"ctaId"`
+}
+
+// RecordCTAClick logs a click on the video's end-screen CTA, or on one of its
+// timed CTAs when the body names a ctaId.
 func (h *Handler) RecordCTAClick(w http.ResponseWriter, r *http.Request) {
+	var req ctaClickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
 	shareToken := chi.URLParam(r, "shareToken")
 
 	videoID, err := h.lookupVideoByShareToken(r.Context(), shareToken)
@@ -111,16 +145,29 @@ func (h *Handler) RecordCTAClick(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var ctaID *string
+	if req.CtaID != "" {
+		var id string
+		if err := h.db.QueryRow(r.Context(),
+			`SELECT id FROM video_ctas WHERE id = $1 AND video_id = $2`, req.CtaID, videoID,
+		).Scan(&id); err != nil {
+			httputil.WriteError(w, http.StatusNotFound, "CTA not found")
+			return
+		}
+		ctaID = &id
+	}
+
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		ip := httputil.ClientIP(r)
-		hash := viewerHash(ip, r.UserAgent())
+		hash, _ := h.viewerHashFor(ctx, r, h.analyticsPrivacy(ctx, videoID))
 		if _, err := h.db.Exec(ctx,
-			`INSERT INTO cta_clicks (video_id, viewer_hash) VALUES ($1, $2)`,
-			videoID, hash,
+			`INSERT INTO cta_clicks (video_id, viewer_hash, cta_id) VALUES ($1, $2, $3)`,
+			videoID, hash, ctaID,
 		); err != nil {
 			slog.Error("video: failed to record CTA click", "video_id", videoID, "error", err)
+		} else {
+			h.publishLive(ctx, liveEventCTAClick, videoID, hash, map[string]any{"ctaId": ctaID})
 		}
 		if h.webhookClient != nil {
 			var ownerID, ctaTitle string
@@ -129,14 +176,18 @@ func (h *Handler) RecordCTAClick(w http.ResponseWriter, r *http.Request) {
 			).Scan(&ownerID, &ctaTitle); err == nil {
 				wURL, wSecret, wErr := h.webhookClient.LookupConfigByUserID(ctx, ownerID)
 				if wErr == nil {
+					data := map[string]any{
+						"videoId":    videoID,
+						"title":      ctaTitle,
+						"viewerHash": hash,
+					}
+					if ctaID != nil {
+						data["ctaId"] = *ctaID
+					}
 					if err := h.webhookClient.Dispatch(ctx, ownerID, wURL, wSecret, webhook.Event{
 						Name:      "video.cta_click",
 						Timestamp: time.Now().UTC(),
-						Data: map[string]any{
-							"videoId":    videoID,
-							"title":      ctaTitle,
-							"viewerHash": hash,
-						},
+						Data:      data,
 					}); err != nil {
 						slog.Error("webhook: dispatch failed for video.cta_click", "video_id", videoID, "error", err)
 					}
@@ -171,13 +222,14 @@ func (h *Handler) RecordMilestone(w http.ResponseWriter, r *http.Request) {
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		ip := httputil.ClientIP(r)
-		hash := viewerHash(ip, r.UserAgent())
+		hash, linked := h.viewerHashFor(ctx, r, h.analyticsPrivacy(ctx, videoID))
 		if _, err := h.db.Exec(ctx,
 			`INSERT INTO view_milestones (video_id, viewer_hash, milestone) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
 			videoID, hash, req.Milestone,
 		); err != nil {
 			slog.Error("video: failed to record milestone", "video_id", videoID, "error", err)
+		} else {
+			h.publishLive(ctx, liveEventMilestone, videoID, hash, map[string]int{"milestone": req.Milestone})
 		}
 		if h.webhookClient != nil {
 			var ownerID, milestoneTitle string
@@ -201,6 +253,9 @@ func (h *Handler) RecordMilestone(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 		}
+		if linked {
+			h.confirmPlayback(ctx, videoID, hash)
+		}
 	}()
 
 	w.WriteHeader(http.StatusNoContent)
@@ -213,7 +268,23 @@ func (h *Handler) RecordSegments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(req.Segments) == 0 || len(req.Segments) > 50 {
+	if len(req.Ranges) > maxBeaconRanges {
+		httputil.WriteError(w, http.StatusBadRequest, fmt.Sprintf("too many ranges (max %d)", maxBeaconRanges))
+		return
+	}
+	if len(req.Events) > maxBeaconEvents {
+		httputil.WriteError(w, http.StatusBadRequest, fmt.Sprintf("too many events (max %d)", maxBeaconEvents))
+		return
+	}
+	if len(req.Events) > 0 && !sessionIDPattern.MatchString(req.SessionID) {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid session id")
+		return
+	}
+	segments := req.Segments
+	if len(segments) > 50 {
+		segments = nil
+	}
+	if len(segments) == 0 && len(req.Ranges) == 0 && len(req.Events) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
@@ -229,7 +300,26 @@ func (h *Handler) RecordSegments(w http.ResponseWriter, r *http.Request) {
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		for _, seg := range req.Segments {
+		// Coverage and session events are stored per viewer, so unlinked
+		// viewers only count towards the aggregate segment heatmap.
+		hash, linked := h.viewerHashFor(ctx, r, h.analyticsPrivacy(ctx, videoID))
+		if linked {
+			h.publishLive(ctx, liveEventHeartbeat, videoID, hash, nil)
+		}
+		if linked && (len(req.Ranges) > 0 || len(req.Events) > 0) {
+			var duration int
+			if err := h.db.QueryRow(ctx, `SELECT duration FROM videos WHERE id = $1`, videoID).Scan(&duration); err != nil {
+				slog.Error("video: failed to load duration for coverage", "video_id", videoID, "error", err)
+			} else {
+				if len(req.Ranges) > 0 {
+					h.recordWatchCoverage(ctx, videoID, hash, req.Ranges, duration)
+				}
+				if len(req.Events) > 0 {
+					h.recordSessionEvents(ctx, videoID, hash, req.SessionID, req.Events, duration)
+				}
+			}
+		}
+		for _, seg := range segments {
 			if seg < 0 || seg >= 50 {
 				continue
 			}
@@ -243,6 +333,9 @@ func (h *Handler) RecordSegments(w http.ResponseWriter, r *http.Request) {
 				slog.Error("video: failed to record segment", "video_id", videoID, "segment", seg, "error", err)
 			}
 		}
+		if linked {
+			h.confirmPlayback(ctx, videoID, hash)
+		}
 	}()
 
 	w.WriteHeader(http.StatusNoContent)
@@ -299,11 +392,17 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 		since = time.Time{}
 	}
 
+	// Campaign filters narrow the view counts and breakdowns. Milestones, CTA
+	// clicks, quiz answers and retention are not attributed to a view, so they
+	// always cover every viewer.
+	filter := parseCampaignParams(r.URL.Query())
+	includeBots := r.URL.Query().Get("include_bots") == "true"
+	source, dailyArgs := viewStatsSource(filter, includeBots, []any{videoID, since})
 	rows, err := h.db.Query(r.Context(),
-		`SELECT date_trunc('day', created_at) AS day, COUNT(*) AS views, COUNT(DISTINCT viewer_hash) AS unique_views
-		 FROM video_views WHERE video_id = $1 AND created_at >= $2
-		 GROUP BY day ORDER BY day`,
-		videoID, since,
+		fmt.Sprintf(`SELECT day, views, unique_viewers
+		 FROM %s ds WHERE video_id = $1 AND day >= $2
+		 ORDER BY day`, source),
+		dailyArgs...,
 	)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "failed to query analytics")
@@ -351,38 +450,44 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 
 	var totalCtaClicks int64
 	err = h.db.QueryRow(r.Context(),
-		`SELECT COUNT(*) FROM cta_clicks WHERE video_id = $1 AND created_at >= $2`,
+		`SELECT COALESCE(SUM(cta_clicks), 0) FROM video_daily_stats WHERE video_id = $1 AND day >= $2`,
 		videoID, since,
 	).Scan(&totalCtaClicks)
 	if err != nil {
 		totalCtaClicks = 0
 	}
 
-	var milestones milestoneCounts
-	milestoneRows, err := h.db.Query(r.Context(),
-		`SELECT milestone, COUNT(DISTINCT viewer_hash) FROM view_milestones WHERE video_id = $1 AND created_at >= $2 GROUP BY milestone`,
+	ctaStats := make([]ctaClickStats, 0)
+	ctaRows, err := h.db.Query(r.Context(),
+		`SELECT c.id, c.kind, c.text, COUNT(k.id), COUNT(DISTINCT k.viewer_hash)
+		 FROM video_ctas c
+		 LEFT JOIN cta_clicks k ON k.cta_id = c.id AND k.created_at >= $2
+		 WHERE c.video_id = $1
+		 GROUP BY c.id, c.kind, c.text, c.start_seconds, c.created_at
+		 ORDER BY c.start_seconds, c.created_at`,
 		videoID, since,
 	)
 	if err == nil {
-		defer milestoneRows.Close()
-		for milestoneRows.Next() {
-			var m int
-			var count int64
-			if err := milestoneRows.Scan(&m, &count); err == nil {
-				switch m {
-				case 25:
-					milestones.Reached25 = count
-				case 50:
-					milestones.Reached50 = count
-				case 75:
-					milestones.Reached75 = count
-				case 100:
-					milestones.Reached100 = count
-				}
+		defer ctaRows.Close()
+		for ctaRows.Next() {
+			var cs ctaClickStats
+			if err := ctaRows.Scan(&cs.ID, &cs.Kind, &cs.Text, &cs.Clicks, &cs.UniqueClicks); err == nil {
+				ctaStats = append(ctaStats, cs)
 			}
 		}
 	}
 
+	questions, quizScores := h.quizAnalytics(r.Context(), videoID, since)
+
+	// A viewer reaches each milestone once, so daily counts sum to distinct viewers.
+	var milestones milestoneCounts
+	_ = h.db.QueryRow(r.Context(),
+		`SELECT COALESCE(SUM(milestone_25), 0), COALESCE(SUM(milestone_50), 0),
+		        COALESCE(SUM(milestone_75), 0), COALESCE(SUM(milestone_100), 0)
+		 FROM video_daily_stats WHERE video_id = $1 AND day >= $2`,
+		videoID, since,
+	).Scan(&milestones.Reached25, &milestones.Reached50, &milestones.Reached75, &milestones.Reached100)
+
 	viewers := make([]viewerInfo, 0)
 	if emailGateEnabled {
 		viewerRows, err := h.db.Query(r.Context(),
@@ -413,6 +518,15 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 	}
 
 	summary := computeSummary(daily, now.Format("2006-01-02"))
+	// Daily unique counts cannot be added up across the range: a viewer who
+	// comes back on three days is still one viewer.
+	if summary.TotalViews > 0 {
+		viewerDays, viewerArgs := viewerDaysSource(filter, includeBots, []any{videoID, since})
+		_ = h.db.QueryRow(r.Context(),
+			fmt.Sprintf(`SELECT COUNT(DISTINCT viewer_hash) FROM %s vd WHERE video_id = $1 AND day >= $2`, viewerDays),
+			viewerArgs...,
+		).Scan(&summary.UniqueViews)
+	}
 	summary.TotalCtaClicks = totalCtaClicks
 	if summary.TotalViews > 0 {
 		summary.CtaClickRate = float64(totalCtaClicks) / float64(summary.TotalViews)
@@ -445,12 +559,15 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 		heatmap = segments
 	}
 
+	filterCond, filterArgs := filter.filterSQL("", []any{videoID, since})
+	viewCond := botSQL("", includeBots) + filterCond
+
 	referrers := make([]referrerData, 0)
 	refRows, err := h.db.Query(r.Context(),
 		`SELECT referrer, COUNT(*) AS cnt
-		 FROM video_views WHERE video_id = $1 AND created_at >= $2
+		 FROM video_views WHERE video_id = $1 AND created_at >= $2`+viewCond+`
 		 GROUP BY referrer ORDER BY cnt DESC`,
-		videoID, since,
+		filterArgs...,
 	)
 	if err == nil {
 		defer refRows.Close()
@@ -474,9 +591,9 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 	browsers := make([]breakdownItem, 0)
 	browserRows, err := h.db.Query(r.Context(),
 		`SELECT browser, COUNT(*) AS cnt
-		 FROM video_views WHERE video_id = $1 AND created_at >= $2
+		 FROM video_views WHERE video_id = $1 AND created_at >= $2`+viewCond+`
 		 GROUP BY browser ORDER BY cnt DESC`,
-		videoID, since,
+		filterArgs...,
 	)
 	if err == nil {
 		defer browserRows.Close()
@@ -512,9 +629,9 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 	devices := make([]breakdownItem, 0)
 	deviceRows, err := h.db.Query(r.Context(),
 		`SELECT device, COUNT(*) AS cnt
-		 FROM video_views WHERE video_id = $1 AND created_at >= $2
+		 FROM video_views WHERE video_id = $1 AND created_at >= $2`+viewCond+`
 		 GROUP BY device ORDER BY cnt DESC`,
-		videoID, since,
+		filterArgs...,
 	)
 	if err == nil {
 		defer deviceRows.Close()
@@ -551,10 +668,14 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 	if days > 0 && summary.TotalViews > 0 {
 		prevSince := since.AddDate(0, 0, -days)
 		var prevViews, prevUnique int64
+		prevSource, prevArgs := viewStatsSource(filter, includeBots, []any{videoID, prevSince, since})
+		prevViewerDays, _ := viewerDaysSource(filter, includeBots, []any{videoID, prevSince, since})
 		_ = h.db.QueryRow(r.Context(),
-			`SELECT COUNT(*), COUNT(DISTINCT viewer_hash)
-			 FROM video_views WHERE video_id = $1 AND created_at >= $2 AND created_at < $3`,
-			videoID, prevSince, since,
+			fmt.Sprintf(`SELECT COALESCE(SUM(views), 0),
+			        (SELECT COUNT(DISTINCT viewer_hash) FROM %s vd
+			         WHERE video_id = $1 AND day >= $2 AND day < $3)
+			 FROM %s ds WHERE video_id = $1 AND day >= $2 AND day < $3`, prevViewerDays, prevSource),
+			prevArgs...,
 		).Scan(&prevViews, &prevUnique)
 
 		trends = &trendData{}
@@ -568,6 +689,22 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	retention := h.retentionAnalytics(r.Context(), videoID)
+
+	campaignScope, campaignArgs := filter.filterSQL("vv.", []any{videoID, since})
+	campaigns := campaignBreakdown(r.Context(), h.db,
+		"vv.video_id = $1 AND vv.created_at >= $2"+botSQL("vv.", includeBots)+campaignScope, campaignArgs, summary.TotalViews)
+
+	_ = h.db.QueryRow(r.Context(),
+		`SELECT COUNT(*) FROM video_views WHERE video_id = $1 AND created_at >= $2 AND is_bot`+filterCond,
+		filterArgs...,
+	).Scan(&summary.BotViews)
+
+	var appliedFilter *campaignParams
+	if !filter.isZero() {
+		appliedFilter = &filter
+	}
+
 	httputil.WriteJSON(w, http.StatusOK, analyticsResponse{
 		Summary:    summary,
 		Daily:      daily,
@@ -578,6 +715,12 @@ func (h *Handler) Analytics(w http.ResponseWriter, r *http.Request) {
 		Referrers:  referrers,
 		Browsers:   browsers,
 		Devices:    devices,
+		Ctas:       ctaStats,
+		Questions:  questions,
+		QuizScores: quizScores,
+		Retention:  retention,
+		Campaigns:  campaigns,
+		Filter:     appliedFilter,
 	})
 }
 
@@ -630,11 +773,14 @@ func (h *Handler) AnalyticsExport(w http.ResponseWriter, r *http.Request) {
 		since = time.Time{}
 	}
 
+	filter := parseCampaignParams(r.URL.Query())
+	includeBots := r.URL.Query().Get("include_bots") == "true"
+	source, dailyArgs := viewStatsSource(filter, includeBots, []any{videoID, since})
 	rows, err := h.db.Query(r.Context(),
-		`SELECT date_trunc('day', created_at)::date AS day, COUNT(*) AS views, COUNT(DISTINCT viewer_hash) AS unique_views
-		 FROM video_views WHERE video_id = $1 AND created_at >= $2
-		 GROUP BY day ORDER BY day`,
-		videoID, since,
+		fmt.Sprintf(`SELECT day, views, unique_viewers
+		 FROM %s ds WHERE video_id = $1 AND day >= $2
+		 ORDER BY day`, source),
+		dailyArgs...,
 	)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "failed to query")
@@ -645,11 +791,55 @@ func (h *Handler) AnalyticsExport(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/csv")
 	w.Header().Set("Content-Disposition", "attachment; filename=analytics.csv")
 	_, _ = fmt.Fprintln(w, "Date,Views,Unique Views")
+	var totalViews int64
 	for rows.Next() {
 		var day time.Time
 		var views, uv int64
 		if err := rows.Scan(&day, &views, &uv); err == nil {
 			_, _ = fmt.Fprintf(w, "%s,%d,%d\n", day.Format("2006-01-02"), views, uv)
+			totalViews += views
+		}
+	}
+	rows.Close()
+
+	cw := csv.NewWriter(w)
+	campaignScope, campaignArgs := filter.filterSQL("vv.", []any{videoID, since})
+	writeCampaignCSV(w, cw, campaignBreakdown(r.Context(), h.db,
+		"vv.video_id = $1 AND vv.created_at >= $2"+botSQL("vv.", includeBots)+campaignScope, campaignArgs, totalViews))
+	questions, quizScores := h.quizAnalytics(r.Context(), videoID, since)
+	writeQuizCSV(w, cw, questions, quizScores)
+	fields, leads := h.leadSubmissions(r.Context(), videoID, since)
+	writeLeadCSV(w, cw, fields, leads)
+}
+
+func writeQuizCSV(w io.Writer, cw *csv.Writer, questions []questionResult, quizScores []quizScore) {
+	if len(questions) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	_ = cw.Write([]string{"Question", "Option", "Responses", "Correct Answer"})
+	for _, q := range questions {
+		for i, option := range q.Options {
+			correct := ""
+			if q.CorrectOption != nil && *q.CorrectOption == i {
+				correct = "yes"
+			}
+			_ = cw.Write([]string{q.Prompt, option, strconv.FormatInt(q.OptionCounts[i], 10), correct})
+		}
+	}
+	cw.Flush()
+	if len(quizScores) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	_ = cw.Write([]string{"Viewer", "Answered", "Graded", "Correct", "Score %"})
+	for _, qs := range quizScores {
+		viewer := qs.Email
+		if viewer == "" {
+			viewer = "anonymous-" + qs.ViewerHash
 		}
+		_ = cw.Write([]string{viewer, strconv.FormatInt(qs.Answered, 10), strconv.FormatInt(qs.Graded, 10),
+			strconv.FormatInt(qs.Correct, 10), strconv.FormatFloat(qs.Score, 'f', 1, 64)})
 	}
+	cw.Flush()
 }
@@ -1,13 +1,18 @@
 package video
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"sort"
 	"time"
 
 	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/database"
 	"github.com/sendrec/sendrec/internal/httputil"
 )
 
@@ -18,6 +23,7 @@ type dashboardSummary struct {
 	TotalVideos           int64   `json:"totalVideos"`
 	TotalWatchTimeSeconds int64   `json:"totalWatchTimeSeconds"`
 	AvgCompletion         float64 `json:"avgCompletion"`
+	BotViews              int64   `json:"botViews"`
 }
 
 type dashboardDaily struct {
@@ -39,11 +45,11 @@ type dashboardResponse struct {
 	Summary   dashboardSummary    `json:"summary"`
 	Daily     []dashboardDaily    `json:"daily"`
 	TopVideos []dashboardTopVideo `json:"topVideos"`
+	Campaigns []campaignStats     `json:"campaigns"`
+	Filter    *campaignParams     `json:"filter,omitempty"`
 }
 
 func (h *Handler) AnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
-	userID := auth.UserIDFromContext(r.Context())
-
 	rangeParam := r.URL.Query().Get("range")
 	if rangeParam == "" {
 		rangeParam = "7d"
@@ -72,25 +78,23 @@ func (h *Handler) AnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
 		since = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
 	}
 
-	orgID := auth.OrgIDFromContext(r.Context())
+	ownerFilter, ownerArg := analyticsOwnerFilter(r.Context())
 
-	var ownerFilter string
-	var ownerArg any
-	if orgID != "" {
-		ownerFilter = `v.organization_id = $1`
-		ownerArg = orgID
-	} else {
-		ownerFilter = `v.user_id = $1`
-		ownerArg = userID
-	}
+	filter := parseCampaignParams(r.URL.Query())
+	includeBots := r.URL.Query().Get("include_bots") == "true"
+	source, sourceArgs := viewStatsSource(filter, includeBots, []any{ownerArg, since})
+	viewerDays, _ := viewerDaysSource(filter, includeBots, []any{ownerArg, since})
 
 	var totalViews, uniqueViews int64
 	err := h.db.QueryRow(r.Context(),
-		fmt.Sprintf(`SELECT COUNT(*) AS views, COUNT(DISTINCT viewer_hash) AS unique_views
-		 FROM video_views vv
-		 JOIN videos v ON v.id = vv.video_id
-		 WHERE %s AND vv.created_at >= $2`, ownerFilter),
-		ownerArg, since,
+		fmt.Sprintf(`SELECT COALESCE(SUM(ds.views), 0) AS views,
+		        (SELECT COUNT(DISTINCT vd.viewer_hash) FROM %s vd
+		         JOIN videos v ON v.id = vd.video_id
+		         WHERE %s AND vd.day >= $2) AS unique_views
+		 FROM %s ds
+		 JOIN videos v ON v.id = ds.video_id
+		 WHERE %s AND ds.day >= $2`, viewerDays, ownerFilter, source, ownerFilter),
+		sourceArgs...,
 	).Scan(&totalViews, &uniqueViews)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "failed to query view summary")
@@ -109,11 +113,11 @@ func (h *Handler) AnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
 
 	var totalWatchTimeSeconds int64
 	err = h.db.QueryRow(r.Context(),
-		fmt.Sprintf(`SELECT COALESCE(SUM(v.duration), 0)
-		 FROM video_views vv
-		 JOIN videos v ON v.id = vv.video_id
-		 WHERE %s AND vv.created_at >= $2`, ownerFilter),
-		ownerArg, since,
+		fmt.Sprintf(`SELECT COALESCE(SUM(ds.watch_seconds), 0)::bigint
+		 FROM %s ds
+		 JOIN videos v ON v.id = ds.video_id
+		 WHERE %s AND ds.day >= $2`, source, ownerFilter),
+		sourceArgs...,
 	).Scan(&totalWatchTimeSeconds)
 	if err != nil {
 		totalWatchTimeSeconds = 0
@@ -126,8 +130,13 @@ func (h *Handler) AnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
 		), 0)
 		 FROM videos v
 		 LEFT JOIN (
-			 SELECT video_id, MAX(milestone) AS max_milestone
-			 FROM view_milestones
+			 SELECT video_id, MAX(CASE
+				 WHEN milestone_100 > 0 THEN 100
+				 WHEN milestone_75 > 0 THEN 75
+				 WHEN milestone_50 > 0 THEN 50
+				 WHEN milestone_25 > 0 THEN 25
+			 END) AS max_milestone
+			 FROM video_daily_stats
 			 GROUP BY video_id
 		 ) m ON m.video_id = v.id
 		 WHERE %s AND v.status != 'deleted'`, ownerFilter),
@@ -144,14 +153,16 @@ func (h *Handler) AnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
 	avgDailyViews := math.Round(float64(totalViews)/float64(daysInRange)*10) / 10
 
 	rows, err := h.db.Query(r.Context(),
-		fmt.Sprintf(`SELECT date_trunc('day', vv.created_at)::date AS day,
-		        COUNT(*) AS views,
-		        COUNT(DISTINCT vv.viewer_hash) AS unique_views
-		 FROM video_views vv
-		 JOIN videos v ON v.id = vv.video_id
-		 WHERE %s AND vv.created_at >= $2
-		 GROUP BY day ORDER BY day`, ownerFilter),
-		ownerArg, since,
+		fmt.Sprintf(`SELECT ds.day,
+		        SUM(ds.views) AS views,
+		        (SELECT COUNT(DISTINCT vd.viewer_hash) FROM %s vd
+		         JOIN videos v ON v.id = vd.video_id
+		         WHERE %s AND vd.day = ds.day) AS unique_views
+		 FROM %s ds
+		 JOIN videos v ON v.id = ds.video_id
+		 WHERE %s AND ds.day >= $2
+		 GROUP BY ds.day ORDER BY ds.day`, viewerDays, ownerFilter, source, ownerFilter),
+		sourceArgs...,
 	)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "failed to query daily views")
@@ -198,19 +209,28 @@ func (h *Handler) AnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	topRows, err := h.db.Query(r.Context(),
-		fmt.Sprintf(`SELECT v.id, v.title, COUNT(vv.id) AS views,
-		        COUNT(DISTINCT vv.viewer_hash) AS unique_views,
+		fmt.Sprintf(`SELECT v.id, v.title, COALESCE(SUM(ds.views), 0) AS views,
+		        (SELECT COUNT(DISTINCT vd.viewer_hash) FROM %s vd
+		         WHERE vd.video_id = v.id AND vd.day >= $2) AS unique_views,
 		        v.share_token,
 		        CASE WHEN v.thumbnail_key IS NOT NULL AND v.thumbnail_key != '' THEN true ELSE false END AS has_thumbnail,
-		        COALESCE(MAX(vm.milestone), 0) AS completion
+		        COALESCE((
+		            SELECT CASE
+		                WHEN SUM(m.milestone_100) > 0 THEN 100
+		                WHEN SUM(m.milestone_75) > 0 THEN 75
+		                WHEN SUM(m.milestone_50) > 0 THEN 50
+		                WHEN SUM(m.milestone_25) > 0 THEN 25
+		                ELSE 0
+		            END
+		            FROM video_daily_stats m WHERE m.video_id = v.id
+		        ), 0) AS completion
 		 FROM videos v
-		 LEFT JOIN video_views vv ON vv.video_id = v.id AND vv.created_at >= $2
-		 LEFT JOIN view_milestones vm ON vm.video_id = v.id
+		 LEFT JOIN %s ds ON ds.video_id = v.id AND ds.day >= $2
 		 WHERE %s AND v.status != 'deleted'
 		 GROUP BY v.id, v.title, v.share_token, v.thumbnail_key
 		 ORDER BY views DESC
-		 LIMIT 10`, ownerFilter),
-		ownerArg, since,
+		 LIMIT 10`, viewerDays, source, ownerFilter),
+		sourceArgs...,
 	)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "failed to query top videos")
@@ -246,6 +266,23 @@ func (h *Handler) AnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	campaignScope, campaignArgs := filter.filterSQL("vv.", []any{ownerArg, since})
+	campaigns := campaignBreakdown(r.Context(), h.db,
+		ownerFilter+" AND vv.created_at >= $2"+botSQL("vv.", includeBots)+campaignScope, campaignArgs, totalViews)
+
+	var botViews int64
+	_ = h.db.QueryRow(r.Context(),
+		fmt.Sprintf(`SELECT COUNT(*) FROM video_views vv
+		 JOIN videos v ON v.id = vv.video_id
+		 WHERE %s AND vv.created_at >= $2 AND vv.is_bot`, ownerFilter)+campaignScope,
+		campaignArgs...,
+	).Scan(&botViews)
+
+	var appliedFilter *campaignParams
+	if !filter.isZero() {
+		appliedFilter = &filter
+	}
+
 	httputil.WriteJSON(w, http.StatusOK, dashboardResponse{
 		Summary: dashboardSummary{
 			TotalViews:            totalViews,
@@ -254,9 +291,12 @@ func (h *Handler) AnalyticsDashboard(w http.ResponseWriter, r *http.Request) {
 			TotalVideos:           totalVideos,
 			TotalWatchTimeSeconds: totalWatchTimeSeconds,
 			AvgCompletion:         avgCompletion,
+			BotViews:              botViews,
 		},
 		Daily:     daily,
 		TopVideos: topVideos,
+		Campaigns: campaigns,
+		Filter:    appliedFilter,
 	})
 }
 
@@ -267,8 +307,6 @@ func sortDashboardDaily(daily []dashboardDaily) {
 }
 
 func (h *Handler) DashboardExport(w http.ResponseWriter, r *http.Request) {
-	userID := auth.UserIDFromContext(r.Context())
-
 	rangeParam := r.URL.Query().Get("range")
 	if rangeParam == "" {
 		rangeParam = "7d"
@@ -296,42 +334,69 @@ func (h *Handler) DashboardExport(w http.ResponseWriter, r *http.Request) {
 		since = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
 	}
 
-	orgID := auth.OrgIDFromContext(r.Context())
+	exportOwnerFilter, exportOwnerArg := analyticsOwnerFilter(r.Context())
 
-	var exportOwnerFilter string
-	var exportOwnerArg any
-	if orgID != "" {
-		exportOwnerFilter = `v.organization_id = $1`
-		exportOwnerArg = orgID
-	} else {
-		exportOwnerFilter = `v.user_id = $1`
-		exportOwnerArg = userID
+	var buf bytes.Buffer
+	filter := parseCampaignParams(r.URL.Query())
+	includeBots := r.URL.Query().Get("include_bots") == "true"
+	if err := writeDashboardCSV(r.Context(), h.db, &buf, exportOwnerFilter, exportOwnerArg, since, filter, includeBots); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to query")
+		return
 	}
 
-	rows, err := h.db.Query(r.Context(),
-		fmt.Sprintf(`SELECT date_trunc('day', vv.created_at)::date AS day,
-		        COUNT(*) AS views,
-		        COUNT(DISTINCT vv.viewer_hash) AS unique_views
-		 FROM video_views vv
-		 JOIN videos v ON v.id = vv.video_id
-		 WHERE %s AND vv.created_at >= $2
-		 GROUP BY day ORDER BY day`, exportOwnerFilter),
-		exportOwnerArg, since,
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=analytics-dashboard.csv")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// analyticsOwnerFilter restricts videos v to the caller's workspace, with $1
+// as its argument. Viewers' reply clips are left out of workspace analytics.
+func analyticsOwnerFilter(ctx context.Context) (string, any) {
+	if orgID := auth.OrgIDFromContext(ctx); orgID != "" {
+		return `v.organization_id = $1 AND v.parent_video_id IS NULL`, orgID
+	}
+	return `v.user_id = $1 AND v.parent_video_id IS NULL`, auth.UserIDFromContext(ctx)
+}
+
+// writeDashboardCSV writes daily views for the videos matched by ownerFilter
+// (a condition on videos v using $1 = ownerArg) followed by the campaign
+// breakdown for the same window.
+func writeDashboardCSV(ctx context.Context, db database.DBTX, w io.Writer, ownerFilter string, ownerArg any,
+	since time.Time, filter campaignParams, includeBots bool) error {
+	args := []any{ownerArg, since}
+	source, sourceArgs := viewStatsSource(filter, includeBots, args)
+	viewerDays, _ := viewerDaysSource(filter, includeBots, args)
+	rows, err := db.Query(ctx,
+		fmt.Sprintf(`SELECT ds.day,
+		        SUM(ds.views) AS views,
+		        (SELECT COUNT(DISTINCT vd.viewer_hash) FROM %s vd
+		         JOIN videos v ON v.id = vd.video_id
+		         WHERE %s AND vd.day = ds.day) AS unique_views
+		 FROM %s ds
+		 JOIN videos v ON v.id = ds.video_id
+		 WHERE %s AND ds.day >= $2
+		 GROUP BY ds.day ORDER BY ds.day`, viewerDays, ownerFilter, source, ownerFilter),
+		sourceArgs...,
 	)
 	if err != nil {
-		httputil.WriteError(w, http.StatusInternalServerError, "failed to query")
-		return
+		return err
 	}
 	defer rows.Close()
 
-	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", "attachment; filename=analytics-dashboard.csv")
 	_, _ = fmt.Fprintln(w, "Date,Views,Unique Views")
+	var totalViews int64
 	for rows.Next() {
 		var day time.Time
 		var views, uv int64
 		if err := rows.Scan(&day, &views, &uv); err == nil {
 			_, _ = fmt.Fprintf(w, "%s,%d,%d\n", day.Format("2006-01-02"), views, uv)
+			totalViews += views
 		}
 	}
+	rows.Close()
+
+	campaignScope, campaignArgs := filter.filterSQL("vv.", args)
+	writeCampaignCSV(w, csv.NewWriter(w), campaignBreakdown(ctx, db,
+		ownerFilter+" AND vv.created_at >= $2"+botSQL("vv.", includeBots)+campaignScope, campaignArgs, totalViews))
+	return nil
 }
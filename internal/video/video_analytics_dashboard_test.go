@@ -15,7 +15,7 @@ import (
 func expectDashboardQueries(t *testing.T, mock pgxmock.PgxPoolIface, totalViews, uniqueViews, totalVideos, watchTime int64, avgCompletion float64, dailyRows *pgxmock.Rows, topVideoRows *pgxmock.Rows) {
 	t.Helper()
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\) AS views, COUNT\(DISTINCT viewer_hash\)`).
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(ds.views\), 0\) AS views,\s+\(SELECT COUNT\(DISTINCT vd\.viewer_hash\) FROM video_viewer_days vd`).
 		WithArgs(testUserID, pgxmock.AnyArg()).
 		WillReturnRows(pgxmock.NewRows([]string{"views", "unique_views"}).AddRow(totalViews, uniqueViews))
 
@@ -23,7 +23,7 @@ func expectDashboardQueries(t *testing.T, mock pgxmock.PgxPoolIface, totalViews,
 		WithArgs(testUserID).
 		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(totalVideos))
 
-	mock.ExpectQuery(`SELECT COALESCE\(SUM\(v.duration\), 0\)`).
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(ds.watch_seconds\), 0\)`).
 		WithArgs(testUserID, pgxmock.AnyArg()).
 		WillReturnRows(pgxmock.NewRows([]string{"total_watch_time"}).AddRow(watchTime))
 
@@ -31,11 +31,11 @@ func expectDashboardQueries(t *testing.T, mock pgxmock.PgxPoolIface, totalViews,
 		WithArgs(testUserID).
 		WillReturnRows(pgxmock.NewRows([]string{"avg_completion"}).AddRow(avgCompletion))
 
-	mock.ExpectQuery(`date_trunc\('day', vv.created_at\)::date AS day`).
+	mock.ExpectQuery(`SELECT ds.day`).
 		WithArgs(testUserID, pgxmock.AnyArg()).
 		WillReturnRows(dailyRows)
 
-	mock.ExpectQuery(`v\.share_token`).
+	mock.ExpectQuery(`v\.share_token(.|\n)*WHERE v\.user_id = \$1 AND v\.parent_video_id IS NULL`).
 		WithArgs(testUserID, pgxmock.AnyArg()).
 		WillReturnRows(topVideoRows)
 }
@@ -279,7 +279,7 @@ func TestDashboardExport_ReturnsCSV(t *testing.T) {
 	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
 
 	exportDay := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
-	mock.ExpectQuery(`date_trunc\('day', vv.created_at\)::date AS day`).
+	mock.ExpectQuery(`SELECT ds.day`).
 		WithArgs(testUserID, pgxmock.AnyArg()).
 		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}).
 			AddRow(exportDay, int64(25), int64(12)))
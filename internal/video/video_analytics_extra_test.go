@@ -161,7 +161,7 @@ func TestAnalyticsExport_ReturnsCSV(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("vid-1"))
 
 	exportDay := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
-	mock.ExpectQuery(`date_trunc\('day', created_at\)::date AS day`).
+	mock.ExpectQuery(`SELECT day, views, unique_viewers`).
 		WithArgs("vid-1", pgxmock.AnyArg()).
 		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}).
 			AddRow(exportDay, int64(25), int64(12)))
@@ -377,7 +377,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 				"videoId":    videoID,
 				"duration":   duration,
 				"shareToken": shareToken,
-				"watchUrl":   h.baseURL + "/watch/" + shareToken,
+				"watchUrl":   h.orgShareBaseURL(r.Context(), auth.OrgIDFromContext(r.Context())) + "/watch/" + shareToken,
 			},
 		})
 
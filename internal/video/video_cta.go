@@ -0,0 +1,299 @@
+package video
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	maxVideoCTAs       = 20
+	maxVideoCTATextLen = 100
+	maxVideoCTAURLLen  = 2000
+	// minHotspotSize keeps hotspots large enough to hit on a phone screen.
+	minHotspotSize = 0.02
+)
+
+var (
+	validCTAKinds     = map[string]bool{"button": true, "hotspot": true}
+	validCTAPositions = map[string]bool{"top-left": true, "top-right": true, "bottom-left": true, "bottom-right": true, "center": true}
+	validCTAStyles    = map[string]bool{"primary": true, "secondary": true, "outline": true}
+)
+
+// videoCTAsJSONColumn aggregates a video's timed CTAs for the watch and embed
+// page queries, which alias the video as v.
+const videoCTAsJSONColumn = `COALESCE((SELECT json_agg(json_build_object(
+		'id', c.id, 'kind', c.kind, 'text', c.text, 'url', c.url,
+		'startSeconds', c.start_seconds, 'endSeconds', c.end_seconds,
+		'position', c.position, 'style', c.style,
+		'region', CASE WHEN c.kind = 'hotspot' THEN json_build_object(
+			'x', c.region_x, 'y', c.region_y, 'width', c.region_width, 'height', c.region_height) END
+	) ORDER BY c.start_seconds, c.created_at) FROM video_ctas c WHERE c.video_id = v.id), '[]')`
+
+type ctaRegion struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type videoCTA struct {
+	ID           string     `json:"id"`
+	Kind         string     `json:"kind"`
+	Text         string     `json:"text"`
+	URL          string     `json:"url"`
+	StartSeconds float64    `json:"startSeconds"`
+	EndSeconds   *float64   `json:"endSeconds"`
+	Position     string     `json:"position"`
+	Style        string     `json:"style"`
+	Region       *ctaRegion `json:"region"`
+}
+
+type videoCTARequest struct {
+	Kind         string     `json:"kind"`
+	Text         string     `json:"text"`
+	URL          string     `json:"url"`
+	StartSeconds float64    `json:"startSeconds"`
+	EndSeconds   *float64   `json:"endSeconds"`
+	Position     string     `json:"position"`
+	Style        string     `json:"style"`
+	Region       *ctaRegion `json:"region"`
+}
+
+// normalizeVideoCTA fills defaults and validates a CTA. Buttons need text and
+// ignore any region; hotspots need a region inside the frame and use text
+// only as an accessible label.
+func normalizeVideoCTA(req videoCTARequest) (videoCTARequest, string) {
+	req.Kind = strings.TrimSpace(req.Kind)
+	if req.Kind == "" {
+		req.Kind = "button"
+	}
+	if !validCTAKinds[req.Kind] {
+		return req, "kind must be button or hotspot"
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	req.URL = strings.TrimSpace(req.URL)
+	if req.Kind == "button" && req.Text == "" {
+		return req, "button text is required"
+	}
+	if len(req.Text) > maxVideoCTATextLen {
+		return req, "CTA text must be 100 characters or less"
+	}
+	if req.URL == "" {
+		return req, "CTA URL is required"
+	}
+	if len(req.URL) > maxVideoCTAURLLen {
+		return req, "CTA URL must be 2000 characters or less"
+	}
+	if !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") {
+		return req, "CTA URL must start with http:// or https://"
+	}
+	if req.StartSeconds < 0 || math.IsNaN(req.StartSeconds) || math.IsInf(req.StartSeconds, 0) {
+		return req, "startSeconds must be zero or greater"
+	}
+	if req.EndSeconds != nil && !(*req.EndSeconds > req.StartSeconds) {
+		return req, "endSeconds must be after startSeconds"
+	}
+	if req.Position == "" {
+		req.Position = "bottom-right"
+	}
+	if !validCTAPositions[req.Position] {
+		return req, "invalid CTA position"
+	}
+	if req.Style == "" {
+		req.Style = "primary"
+	}
+	if !validCTAStyles[req.Style] {
+		return req, "style must be primary, secondary or outline"
+	}
+	if req.Kind == "button" {
+		req.Region = nil
+		return req, ""
+	}
+	g := req.Region
+	if g == nil {
+		return req, "hotspot region is required"
+	}
+	if g.X < 0 || g.Y < 0 || g.Width < minHotspotSize || g.Height < minHotspotSize ||
+		g.X+g.Width > 1 || g.Y+g.Height > 1 {
+		return req, "hotspot region must lie within the video frame"
+	}
+	return req, ""
+}
+
+func regionArgs(g *ctaRegion) (x, y, width, height *float64) {
+	if g == nil {
+		return nil, nil, nil, nil
+	}
+	return &g.X, &g.Y, &g.Width, &g.Height
+}
+
+// decodeVideoCTAs parses the JSON produced by videoCTAsJSONColumn. A
+// malformed value yields no CTAs rather than breaking the page.
+func decodeVideoCTAs(raw string) []videoCTA {
+	ctas := make([]videoCTA, 0)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &ctas); err != nil {
+			return make([]videoCTA, 0)
+		}
+	}
+	return ctas
+}
+
+func (h *Handler) ListVideoCTAs(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var id string
+	if err := h.db.QueryRow(r.Context(), `SELECT id FROM videos WHERE `+where, args...).Scan(&id); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(),
+		`SELECT id, kind, text, url, start_seconds, end_seconds, position, style,
+		        region_x, region_y, region_width, region_height
+		 FROM video_ctas WHERE video_id = $1 ORDER BY start_seconds, created_at`, videoID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch CTAs")
+		return
+	}
+	defer rows.Close()
+
+	ctas := make([]videoCTA, 0)
+	for rows.Next() {
+		var c videoCTA
+		var x, y, width, height *float64
+		if err := rows.Scan(&c.ID, &c.Kind, &c.Text, &c.URL, &c.StartSeconds, &c.EndSeconds,
+			&c.Position, &c.Style, &x, &y, &width, &height); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not fetch CTAs")
+			return
+		}
+		if x != nil && y != nil && width != nil && height != nil {
+			c.Region = &ctaRegion{X: *x, Y: *y, Width: *width, Height: *height}
+		}
+		ctas = append(ctas, c)
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch CTAs")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, ctas)
+}
+
+func (h *Handler) CreateVideoCTA(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	var req videoCTARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req, msg := normalizeVideoCTA(req)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var count int
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT (SELECT COUNT(*) FROM video_ctas WHERE video_id = videos.id) FROM videos WHERE `+where, args...,
+	).Scan(&count); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+	if count >= maxVideoCTAs {
+		httputil.WriteError(w, http.StatusBadRequest, "too many CTAs for this video")
+		return
+	}
+
+	x, y, width, height := regionArgs(req.Region)
+	resp := videoCTA{
+		Kind: req.Kind, Text: req.Text, URL: req.URL,
+		StartSeconds: req.StartSeconds, EndSeconds: req.EndSeconds,
+		Position: req.Position, Style: req.Style, Region: req.Region,
+	}
+	if err := h.db.QueryRow(r.Context(),
+		`INSERT INTO video_ctas (video_id, kind, text, url, start_seconds, end_seconds, position, style,
+		                         region_x, region_y, region_width, region_height)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		 RETURNING id`,
+		videoID, req.Kind, req.Text, req.URL, req.StartSeconds, req.EndSeconds, req.Position, req.Style,
+		x, y, width, height,
+	).Scan(&resp.ID); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not create CTA")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) UpdateVideoCTA(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+	ctaID := chi.URLParam(r, "ctaId")
+
+	var req videoCTARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req, msg := normalizeVideoCTA(req)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var id string
+	if err := h.db.QueryRow(r.Context(), `SELECT id FROM videos WHERE `+where, args...).Scan(&id); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	x, y, width, height := regionArgs(req.Region)
+	tag, err := h.db.Exec(r.Context(),
+		`UPDATE video_ctas SET kind = $3, text = $4, url = $5, start_seconds = $6, end_seconds = $7,
+		        position = $8, style = $9, region_x = $10, region_y = $11, region_width = $12, region_height = $13,
+		        updated_at = now()
+		 WHERE id = $1 AND video_id = $2`,
+		ctaID, videoID, req.Kind, req.Text, req.URL, req.StartSeconds, req.EndSeconds, req.Position, req.Style,
+		x, y, width, height,
+	)
+	if err != nil || tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "CTA not found")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, videoCTA{
+		ID: ctaID, Kind: req.Kind, Text: req.Text, URL: req.URL,
+		StartSeconds: req.StartSeconds, EndSeconds: req.EndSeconds,
+		Position: req.Position, Style: req.Style, Region: req.Region,
+	})
+}
+
+func (h *Handler) DeleteVideoCTA(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+	ctaID := chi.URLParam(r, "ctaId")
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var id string
+	if err := h.db.QueryRow(r.Context(), `SELECT id FROM videos WHERE `+where, args...).Scan(&id); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	tag, err := h.db.Exec(r.Context(),
+		`DELETE FROM video_ctas WHERE id = $1 AND video_id = $2`, ctaID, videoID)
+	if err != nil || tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "CTA not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
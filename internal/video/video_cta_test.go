@@ -0,0 +1,329 @@
+package video
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestNormalizeVideoCTA(t *testing.T) {
+	end := 30.0
+	got, msg := normalizeVideoCTA(videoCTARequest{Text: " Book a demo ", URL: "https://example.com", StartSeconds: 5, EndSeconds: &end,
+		Region: &ctaRegion{X: 0.1, Y: 0.1, Width: 0.2, Height: 0.2}})
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if got.Kind != "button" || got.Position != "bottom-right" || got.Style != "primary" || got.Text != "Book a demo" {
+		t.Errorf("expected button defaults, got %+v", got)
+	}
+	if got.Region != nil {
+		t.Error("expected region to be dropped for buttons")
+	}
+
+	before := 3.0
+	bad := map[string]videoCTARequest{
+		"unknown kind":      {Kind: "popup", Text: "Go", URL: "https://example.com"},
+		"missing text":      {URL: "https://example.com"},
+		"javascript url":    {Text: "Go", URL: "javascript:alert(1)"},
+		"negative start":    {Text: "Go", URL: "https://example.com", StartSeconds: -1},
+		"end before start":  {Text: "Go", URL: "https://example.com", StartSeconds: 5, EndSeconds: &before},
+		"unknown position":  {Text: "Go", URL: "https://example.com", Position: "middle"},
+		"unknown style":     {Text: "Go", URL: "https://example.com", Style: "neon"},
+		"hotspot no region": {Kind: "hotspot", URL: "https://example.com"},
+		"hotspot off frame": {Kind: "hotspot", URL: "https://example.com", Region: &ctaRegion{X: 0.9, Y: 0.5, Width: 0.2, Height: 0.2}},
+		"hotspot too small": {Kind: "hotspot", URL: "https://example.com", Region: &ctaRegion{X: 0.5, Y: 0.5, Width: 0.001, Height: 0.2}},
+	}
+	for name, req := range bad {
+		if _, msg := normalizeVideoCTA(req); msg == "" {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+
+	if _, msg := normalizeVideoCTA(videoCTARequest{Kind: "hotspot", URL: "https://example.com/product",
+		Region: &ctaRegion{X: 0.6, Y: 0.2, Width: 0.3, Height: 0.4}}); msg != "" {
+		t.Errorf("expected hotspot without text to be valid, got %s", msg)
+	}
+}
+
+func TestCreateVideoCTA_Hotspot(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM video_ctas WHERE video_id = videos.id\) FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`INSERT INTO video_ctas`).
+		WithArgs(videoID, "hotspot", "Shop the look", "https://example.com/product", 12.5, (*float64)(nil), "bottom-right", "outline",
+			pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("cta-1"))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/ctas", handler.CreateVideoCTA)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"kind":"hotspot","text":"Shop the look","url":"https://example.com/product","startSeconds":12.5,"style":"outline","region":{"x":0.6,"y":0.2,"width":0.3,"height":0.4}}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/"+videoID+"/ctas", body))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	resp := rec.Body.String()
+	if !strings.Contains(resp, `"id":"cta-1"`) || !strings.Contains(resp, `"region":{"x":0.6,"y":0.2,"width":0.3,"height":0.4}`) {
+		t.Errorf("expected created hotspot, got %s", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreateVideoCTA_LimitReached(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM video_ctas`).
+		WithArgs("video-123", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(maxVideoCTAs))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/ctas", handler.CreateVideoCTA)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"text":"Book a demo","url":"https://example.com"}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/video-123/ctas", body))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestListVideoCTAs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+	end := 20.0
+	x, y, w, h := 0.1, 0.2, 0.3, 0.4
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(videoID))
+	mock.ExpectQuery(`SELECT id, kind, text, url, start_seconds, end_seconds, position, style, region_x, region_y, region_width, region_height FROM video_ctas WHERE video_id = \$1`).
+		WithArgs(videoID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "kind", "text", "url", "start_seconds", "end_seconds", "position", "style", "region_x", "region_y", "region_width", "region_height"}).
+			AddRow("cta-1", "button", "Book a demo", "https://example.com", 0.0, &end, "top-right", "primary", (*float64)(nil), (*float64)(nil), (*float64)(nil), (*float64)(nil)).
+			AddRow("cta-2", "hotspot", "", "https://example.com/p", 10.0, (*float64)(nil), "bottom-right", "outline", &x, &y, &w, &h))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/ctas", handler.ListVideoCTAs)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/"+videoID+"/ctas", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	resp := rec.Body.String()
+	if !strings.Contains(resp, `"endSeconds":20,"position":"top-right","style":"primary","region":null`) {
+		t.Errorf("expected button CTA, got %s", resp)
+	}
+	if !strings.Contains(resp, `"endSeconds":null,"position":"bottom-right","style":"outline","region":{"x":0.1,"y":0.2,"width":0.3,"height":0.4}`) {
+		t.Errorf("expected hotspot CTA, got %s", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestUpdateVideoCTA_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(videoID))
+	mock.ExpectExec(`UPDATE video_ctas SET kind = \$3`).
+		WithArgs("cta-9", videoID, "button", "Go", "https://example.com", 0.0, (*float64)(nil), "center", "secondary",
+			(*float64)(nil), (*float64)(nil), (*float64)(nil), (*float64)(nil)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/ctas/{ctaId}", handler.UpdateVideoCTA)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"text":"Go","url":"https://example.com","position":"center","style":"secondary"}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/"+videoID+"/ctas/cta-9", body))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteVideoCTA(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(videoID))
+	mock.ExpectExec(`DELETE FROM video_ctas WHERE id = \$1 AND video_id = \$2`).
+		WithArgs("cta-1", videoID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Delete("/api/videos/{id}/ctas/{ctaId}", handler.DeleteVideoCTA)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodDelete, "/api/videos/"+videoID+"/ctas/cta-1", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRecordCTAClick_WithCtaID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE share_token`).
+		WithArgs("abc123defghi").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("video-001"))
+	mock.ExpectQuery(`SELECT id FROM video_ctas WHERE id = \$1 AND video_id = \$2`).
+		WithArgs("cta-1", "video-001").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("cta-1"))
+	ctaID := "cta-1"
+	mock.ExpectExec(`INSERT INTO cta_clicks \(video_id, viewer_hash, cta_id\)`).
+		WithArgs("video-001", pgxmock.AnyArg(), &ctaID).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/cta-click", handler.RecordCTAClick)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/abc123defghi/cta-click", strings.NewReader(`{"ctaId":"cta-1"}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRecordCTAClick_CtaFromAnotherVideo(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE share_token`).
+		WithArgs("abc123defghi").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("video-001"))
+	mock.ExpectQuery(`SELECT id FROM video_ctas WHERE id = \$1 AND video_id = \$2`).
+		WithArgs("cta-other", "video-001").
+		WillReturnError(pgx.ErrNoRows)
+
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/cta-click", handler.RecordCTAClick)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/abc123defghi/cta-click", strings.NewReader(`{"ctaId":"cta-other"}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestEmbedPage_RendersTimedCTAs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	storage := &mockStorage{downloadURL: "https://s3.example.com/video"}
+	handler := NewHandler(mock, storage, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("abc123defghi").
+		WillReturnRows(pgxmock.NewRows(embedPageColumns).AddRow(
+			"vid-1", "Training", "recordings/u1/abc.webm", "Bob", time.Now(), (*time.Time)(nil),
+			(*string)(nil), (*string)(nil), "video/webm",
+			"owner-user-id", "owner@example.com", (*string)(nil),
+			(*string)(nil), (*string)(nil), (*string)(nil),
+			false, (*string)(nil), "ready", "public", (*string)(nil), []string{},
+			`[{"id":"cta-1","kind":"button","text":"</script>Book","url":"https://example.com","startSeconds":3,"endSeconds":9,"position":"top-left","style":"primary","region":null}]`,
+			"[]",
+		))
+	expectViewRecording(mock, "vid-1")
+
+	rec := serveEmbedPage(handler, embedPageRequest("abc123defghi"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `id="timed-cta-layer"`) {
+		t.Error("expected timed CTA layer in embed page")
+	}
+	if !strings.Contains(body, `"id":"cta-1"`) {
+		t.Error("expected timed CTAs JSON in embed page")
+	}
+	if strings.Contains(body, `"</script>Book"`) {
+		t.Error("expected CTA text to be escaped inside the script block")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+}
@@ -54,6 +54,7 @@ type listItem struct {
 	Pinned                bool               `json:"pinned"`
 	Tags                  []listItemTag      `json:"tags"`
 	Playlists             []listItemPlaylist `json:"playlists"`
+	VideoRepliesEnabled   bool               `json:"videoRepliesEnabled"`
 }
 
 type listItemTag struct {
@@ -236,10 +237,10 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	query := strings.TrimSpace(r.URL.Query().Get("q"))
 
 	baseQuery := `SELECT v.id, v.title, v.status, v.duration, v.share_token, v.created_at, v.share_expires_at,
-		    (SELECT COUNT(*) FROM video_views vv WHERE vv.video_id = v.id) AS view_count,
-		    (SELECT COUNT(DISTINCT vv.viewer_hash) FROM video_views vv WHERE vv.video_id = v.id) AS unique_view_count,
+		    (SELECT COALESCE(SUM(ds.views), 0) FROM video_daily_stats ds WHERE ds.video_id = v.id) AS view_count,
+		    (SELECT COALESCE(SUM(ds.new_viewers), 0) FROM video_daily_stats ds WHERE ds.video_id = v.id) AS unique_view_count,
 		    v.thumbnail_key, v.share_password, v.comment_mode,
-		    (SELECT COUNT(*) FROM video_comments vc WHERE vc.video_id = v.id) AS comment_count,
+		    (SELECT COUNT(*) FROM video_comments vc WHERE vc.video_id = v.id AND vc.status = 'approved') AS comment_count,
 		    v.transcript_status, v.view_notification, v.download_enabled, v.cta_text, v.cta_url, v.email_gate_enabled, v.summary_status, v.document_status,
 		    v.suggested_title, v.folder_id, v.transcription_language, v.noise_reduction, v.pinned,
 		    COALESCE((SELECT json_agg(json_build_object('id', t.id, 'name', t.name, 'color', t.color) ORDER BY t.name)
@@ -247,9 +248,10 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 		      WHERE vt.video_id = v.id), '[]'::json) AS tags_json,
 		    COALESCE((SELECT json_agg(json_build_object('id', p.id, 'title', p.title) ORDER BY p.title)
 		      FROM playlist_videos pv JOIN playlists p ON p.id = pv.playlist_id
-		      WHERE pv.video_id = v.id), '[]'::json) AS playlists_json
+		      WHERE pv.video_id = v.id), '[]'::json) AS playlists_json,
+		    v.video_replies_enabled
 		 FROM videos v
-		 WHERE v.status != 'deleted'`
+		 WHERE v.status != 'deleted' AND v.parent_video_id IS NULL`
 
 	var args []any
 	paramIdx := 1
@@ -293,6 +295,8 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	baseQuery += fmt.Sprintf(` ORDER BY v.created_at DESC LIMIT $%d OFFSET $%d`, paramIdx, paramIdx+1)
 	args = append(args, limit, offset)
 
+	shareBaseURL := h.orgShareBaseURL(r.Context(), orgID)
+
 	rows, err := h.db.Query(r.Context(), baseQuery, args...)
 	if err != nil {
 		httputil.WriteError(w, http.StatusInternalServerError, "failed to list videos")
@@ -309,7 +313,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 		var sharePassword *string
 		var tagsJSON string
 		var playlistsJSON string
-		if err := rows.Scan(&item.ID, &item.Title, &item.Status, &item.Duration, &item.ShareToken, &createdAt, &shareExpiresAt, &item.ViewCount, &item.UniqueViewCount, &thumbnailKey, &sharePassword, &item.CommentMode, &item.CommentCount, &item.TranscriptStatus, &item.ViewNotification, &item.DownloadEnabled, &item.CtaText, &item.CtaUrl, &item.EmailGateEnabled, &item.SummaryStatus, &item.DocumentStatus, &item.SuggestedTitle, &item.FolderID, &item.TranscriptionLanguage, &item.NoiseReduction, &item.Pinned, &tagsJSON, &playlistsJSON); err != nil {
+		if err := rows.Scan(&item.ID, &item.Title, &item.Status, &item.Duration, &item.ShareToken, &createdAt, &shareExpiresAt, &item.ViewCount, &item.UniqueViewCount, &thumbnailKey, &sharePassword, &item.CommentMode, &item.CommentCount, &item.TranscriptStatus, &item.ViewNotification, &item.DownloadEnabled, &item.CtaText, &item.CtaUrl, &item.EmailGateEnabled, &item.SummaryStatus, &item.DocumentStatus, &item.SuggestedTitle, &item.FolderID, &item.TranscriptionLanguage, &item.NoiseReduction, &item.Pinned, &tagsJSON, &playlistsJSON, &item.VideoRepliesEnabled); err != nil {
 			httputil.WriteError(w, http.StatusInternalServerError, "failed to scan video")
 			return
 		}
@@ -330,7 +334,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 			formatted := shareExpiresAt.Format(time.RFC3339)
 			item.ShareExpiresAt = &formatted
 		}
-		item.ShareURL = h.baseURL + "/watch/" + item.ShareToken
+		item.ShareURL = shareBaseURL + "/watch/" + item.ShareToken
 		item.HasPassword = sharePassword != nil
 		if thumbnailKey != nil {
 			thumbURL, err := h.storage.GenerateDownloadURL(r.Context(), *thumbnailKey, 1*time.Hour)
@@ -345,7 +349,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) Watch(w http.ResponseWriter, r *http.Request) {
-	shareToken := chi.URLParam(r, "shareToken")
+	shareToken, _ := h.resolveShareRef(r, false)
 
 	var videoID string
 	var title string
@@ -374,6 +378,7 @@ func (h *Handler) Watch(w http.ResponseWriter, r *http.Request) {
 	var obCompanyName, obLogoKey, obColorBg, obColorSurface, obColorText, obColorAccent, obFooterText, obCustomCSS *string
 	var vbCompanyName, vbLogoKey, vbColorBg, vbColorSurface, vbColorText, vbColorAccent, vbFooterText *string
 	var videoOrgID *string
+	var visibility string
 
 	err := h.db.QueryRow(r.Context(),
 		`SELECT v.id, v.title, v.duration, v.file_key, u.name, v.created_at, v.share_expires_at, v.thumbnail_key, v.share_password,
@@ -385,12 +390,14 @@ func (h *Handler) Watch(w http.ResponseWriter, r *http.Request) {
 		        v.cta_text, v.cta_url,
 		        v.summary, v.chapters, v.summary_status,
 		        v.document, v.document_status,
-		        v.organization_id, v.email_gate_enabled
+		        v.organization_id, v.email_gate_enabled,
+		        COALESCE(v.visibility, f.visibility, 'public')
 		 FROM videos v
 		 JOIN users u ON u.id = v.user_id
 		 LEFT JOIN user_branding ub ON ub.user_id = v.user_id AND ub.organization_id IS NULL
 		 LEFT JOIN user_branding ob ON ob.organization_id = v.organization_id
-		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
+		 LEFT JOIN folders f ON f.id = v.folder_id
+		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing') AND v.embed_signing_secret IS NULL`,
 		shareToken,
 	).Scan(&videoID, &title, &duration, &fileKey, &creator, &createdAt, &shareExpiresAt, &thumbnailKey, &sharePassword,
 		&transcriptKey, &transcriptJSON, &transcriptStatus,
@@ -401,9 +408,9 @@ func (h *Handler) Watch(w http.ResponseWriter, r *http.Request) {
 		&ctaText, &ctaUrl,
 		&summaryText, &chaptersJSON, &summaryStatus,
 		&documentText, &documentStatus,
-		&videoOrgID, &emailGateEnabled)
+		&videoOrgID, &emailGateEnabled, &visibility)
 	if err != nil {
-		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		h.writeWatchNotFound(w, r, shareToken)
 		return
 	}
 
@@ -412,6 +419,10 @@ func (h *Handler) Watch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.enforceVisibility(w, r, shareToken, visibility) {
+		return
+	}
+
 	if !h.enforceWatchAccess(w, r, shareToken, sharePassword, emailGateEnabled) {
 		return
 	}
@@ -526,7 +537,7 @@ func (h *Handler) getUserPlan(ctx context.Context, userID string) (string, error
 func (h *Handler) countVideosThisMonth(ctx context.Context, userID string) (int, error) {
 	var count int
 	err := h.db.QueryRow(ctx,
-		`SELECT COUNT(*) FROM videos WHERE user_id = $1 AND created_at >= date_trunc('month', now()) AND status != 'deleted'`,
+		`SELECT COUNT(*) FROM videos WHERE user_id = $1 AND created_at >= date_trunc('month', now()) AND status != 'deleted' AND parent_video_id IS NULL`,
 		userID,
 	).Scan(&count)
 	return count, err
@@ -586,7 +597,7 @@ func orgVideoFilter(ctx context.Context, videoID string, baseArgs []any, suffix
 func (h *Handler) countOrgVideosThisMonth(ctx context.Context, orgID string) (int, error) {
 	var count int
 	err := h.db.QueryRow(ctx,
-		`SELECT COUNT(*) FROM videos WHERE organization_id = $1 AND status != 'deleted' AND created_at >= date_trunc('month', now())`,
+		`SELECT COUNT(*) FROM videos WHERE organization_id = $1 AND status != 'deleted' AND created_at >= date_trunc('month', now()) AND parent_video_id IS NULL`,
 		orgID,
 	).Scan(&count)
 	return count, err
@@ -678,13 +689,18 @@ func (h *Handler) WatchDownload(w http.ResponseWriter, r *http.Request) {
 	var contentType string
 	var downloadEnabled bool
 	var emailGateEnabled bool
+	var visibility string
 
 	err := h.db.QueryRow(r.Context(),
-		`SELECT title, file_key, share_expires_at, share_password, content_type, download_enabled, email_gate_enabled FROM videos WHERE share_token = $1 AND status IN ('ready', 'processing')`,
+		`SELECT v.title, v.file_key, v.share_expires_at, v.share_password, v.content_type, v.download_enabled, v.email_gate_enabled,
+		        COALESCE(v.visibility, f.visibility, 'public')
+		 FROM videos v
+		 LEFT JOIN folders f ON f.id = v.folder_id
+		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing') AND v.embed_signing_secret IS NULL`,
 		shareToken,
-	).Scan(&title, &fileKey, &shareExpiresAt, &sharePassword, &contentType, &downloadEnabled, &emailGateEnabled)
+	).Scan(&title, &fileKey, &shareExpiresAt, &sharePassword, &contentType, &downloadEnabled, &emailGateEnabled, &visibility)
 	if err != nil {
-		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		h.writeWatchNotFound(w, r, shareToken)
 		return
 	}
 
@@ -698,6 +714,10 @@ func (h *Handler) WatchDownload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.enforceVisibility(w, r, shareToken, visibility) {
+		return
+	}
+
 	if !h.enforceWatchAccess(w, r, shareToken, sharePassword, emailGateEnabled) {
 		return
 	}
@@ -0,0 +1,390 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	maxVideoQuestions    = 50
+	maxQuestionPromptLen = 500
+	maxQuestionOptionLen = 200
+	minQuestionOptions   = 2
+	maxQuestionOptions   = 6
+	maxQuizScoreRows     = 500
+)
+
+// videoQuestionsJSONColumn aggregates a video's questions for the watch and
+// embed page queries, which alias the video as v. Correct answers stay on the
+// server; the page only learns whether a question is graded.
+const videoQuestionsJSONColumn = `COALESCE((SELECT json_agg(json_build_object(
+		'id', q.id, 'timeSeconds', q.time_seconds, 'prompt', q.prompt, 'options', q.options,
+		'graded', q.correct_option IS NOT NULL
+	) ORDER BY q.time_seconds, q.created_at) FROM video_questions q WHERE q.video_id = v.id), '[]')`
+
+type videoQuestion struct {
+	ID            string   `json:"id"`
+	TimeSeconds   float64  `json:"timeSeconds"`
+	Prompt        string   `json:"prompt"`
+	Options       []string `json:"options"`
+	CorrectOption *int     `json:"correctOption"`
+}
+
+// playerQuestion is the viewer-facing form of a question.
+type playerQuestion struct {
+	ID          string   `json:"id"`
+	TimeSeconds float64  `json:"timeSeconds"`
+	Prompt      string   `json:"prompt"`
+	Options     []string `json:"options"`
+	Graded      bool     `json:"graded"`
+}
+
+type videoQuestionRequest struct {
+	TimeSeconds   float64  `json:"timeSeconds"`
+	Prompt        string   `json:"prompt"`
+	Options       []string `json:"options"`
+	CorrectOption *int     `json:"correctOption"`
+}
+
+type answerRequest struct {
+	QuestionID  string `json:"questionId"`
+	OptionIndex int    `json:"optionIndex"`
+}
+
+type answerResponse struct {
+	Correct       *bool `json:"correct"`
+	CorrectOption *int  `json:"correctOption"`
+}
+
+func normalizeVideoQuestion(req videoQuestionRequest) (videoQuestionRequest, string) {
+	if req.TimeSeconds < 0 || math.IsNaN(req.TimeSeconds) || math.IsInf(req.TimeSeconds, 0) {
+		return req, "timeSeconds must be zero or greater"
+	}
+	req.Prompt = strings.TrimSpace(req.Prompt)
+	if req.Prompt == "" {
+		return req, "question prompt is required"
+	}
+	if len(req.Prompt) > maxQuestionPromptLen {
+		return req, "question prompt must be 500 characters or less"
+	}
+	options := make([]string, 0, len(req.Options))
+	for _, o := range req.Options {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			return req, "answer options cannot be empty"
+		}
+		if len(o) > maxQuestionOptionLen {
+			return req, "answer options must be 200 characters or less"
+		}
+		options = append(options, o)
+	}
+	if len(options) < minQuestionOptions || len(options) > maxQuestionOptions {
+		return req, "questions need between 2 and 6 answer options"
+	}
+	req.Options = options
+	if req.CorrectOption != nil && (*req.CorrectOption < 0 || *req.CorrectOption >= len(options)) {
+		return req, "correctOption must refer to one of the options"
+	}
+	return req, ""
+}
+
+// decodePlayerQuestions parses the JSON produced by videoQuestionsJSONColumn.
+func decodePlayerQuestions(raw string) []playerQuestion {
+	questions := make([]playerQuestion, 0)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &questions); err != nil {
+			return make([]playerQuestion, 0)
+		}
+	}
+	return questions
+}
+
+func (h *Handler) ListVideoQuestions(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var id string
+	if err := h.db.QueryRow(r.Context(), `SELECT id FROM videos WHERE `+where, args...).Scan(&id); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	rows, err := h.db.Query(r.Context(),
+		`SELECT id, time_seconds, prompt, options, correct_option
+		 FROM video_questions WHERE video_id = $1 ORDER BY time_seconds, created_at`, videoID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch questions")
+		return
+	}
+	defer rows.Close()
+
+	questions := make([]videoQuestion, 0)
+	for rows.Next() {
+		var q videoQuestion
+		if err := rows.Scan(&q.ID, &q.TimeSeconds, &q.Prompt, &q.Options, &q.CorrectOption); err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not fetch questions")
+			return
+		}
+		questions = append(questions, q)
+	}
+	if err := rows.Err(); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch questions")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, questions)
+}
+
+func (h *Handler) CreateVideoQuestion(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	var req videoQuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req, msg := normalizeVideoQuestion(req)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var count int
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT (SELECT COUNT(*) FROM video_questions WHERE video_id = videos.id) FROM videos WHERE `+where, args...,
+	).Scan(&count); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+	if count >= maxVideoQuestions {
+		httputil.WriteError(w, http.StatusBadRequest, "too many questions for this video")
+		return
+	}
+
+	resp := videoQuestion{
+		TimeSeconds: req.TimeSeconds, Prompt: req.Prompt,
+		Options: req.Options, CorrectOption: req.CorrectOption,
+	}
+	if err := h.db.QueryRow(r.Context(),
+		`INSERT INTO video_questions (video_id, time_seconds, prompt, options, correct_option)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id`,
+		videoID, req.TimeSeconds, req.Prompt, req.Options, req.CorrectOption,
+	).Scan(&resp.ID); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not create question")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, resp)
+}
+
+// UpdateVideoQuestion replaces a question. Answers already given keep the
+// score they were recorded with.
+func (h *Handler) UpdateVideoQuestion(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+	questionID := chi.URLParam(r, "questionId")
+
+	var req videoQuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req, msg := normalizeVideoQuestion(req)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var id string
+	if err := h.db.QueryRow(r.Context(), `SELECT id FROM videos WHERE `+where, args...).Scan(&id); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	tag, err := h.db.Exec(r.Context(),
+		`UPDATE video_questions SET time_seconds = $3, prompt = $4, options = $5, correct_option = $6, updated_at = now()
+		 WHERE id = $1 AND video_id = $2`,
+		questionID, videoID, req.TimeSeconds, req.Prompt, req.Options, req.CorrectOption,
+	)
+	if err != nil || tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "question not found")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, videoQuestion{
+		ID: questionID, TimeSeconds: req.TimeSeconds, Prompt: req.Prompt,
+		Options: req.Options, CorrectOption: req.CorrectOption,
+	})
+}
+
+func (h *Handler) DeleteVideoQuestion(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+	questionID := chi.URLParam(r, "questionId")
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var id string
+	if err := h.db.QueryRow(r.Context(), `SELECT id FROM videos WHERE `+where, args...).Scan(&id); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	tag, err := h.db.Exec(r.Context(),
+		`DELETE FROM video_questions WHERE id = $1 AND video_id = $2`, questionID, videoID)
+	if err != nil || tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "question not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RecordAnswer stores a viewer's answer to an in-video question and tells the
+// player whether it was right. Only the first answer per viewer is kept, so
+// retrying after seeing the correct option does not change the score.
+func (h *Handler) RecordAnswer(w http.ResponseWriter, r *http.Request) {
+	var req answerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.QuestionID == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	videoID, _, _, ok := h.lookupWatchVideo(w, r)
+	if !ok {
+		return
+	}
+
+	var optionCount int
+	var correctOption *int
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT cardinality(options), correct_option FROM video_questions WHERE id = $1 AND video_id = $2`,
+		req.QuestionID, videoID,
+	).Scan(&optionCount, &correctOption); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "question not found")
+		return
+	}
+	if req.OptionIndex < 0 || req.OptionIndex >= optionCount {
+		httputil.WriteError(w, http.StatusBadRequest, "optionIndex must refer to one of the options")
+		return
+	}
+
+	var resp answerResponse
+	if correctOption != nil {
+		correct := req.OptionIndex == *correctOption
+		resp.Correct = &correct
+		resp.CorrectOption = correctOption
+	}
+
+	hash, _ := h.viewerHashFor(r.Context(), r, h.analyticsPrivacy(r.Context(), videoID))
+	viewerKey := hash
+	var email *string
+	if e, ok := hasValidEmailGateCookie(r, h.hmacSecret, chi.URLParam(r, "shareToken")); ok {
+		viewerKey = strings.ToLower(e)
+		email = &e
+	}
+
+	if _, err := h.db.Exec(r.Context(),
+		`INSERT INTO question_answers (question_id, video_id, viewer_key, viewer_hash, email, option_index, is_correct)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (question_id, viewer_key) DO NOTHING`,
+		req.QuestionID, videoID, viewerKey, hash, email, req.OptionIndex, resp.Correct,
+	); err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not record answer")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+type questionResult struct {
+	ID               string   `json:"id"`
+	Prompt           string   `json:"prompt"`
+	Options          []string `json:"options"`
+	CorrectOption    *int     `json:"correctOption"`
+	Responses        int64    `json:"responses"`
+	OptionCounts     []int64  `json:"optionCounts"`
+	CorrectResponses int64    `json:"correctResponses"`
+}
+
+type quizScore struct {
+	Email      string  `json:"email"`
+	ViewerHash string  `json:"viewerHash"`
+	Answered   int64   `json:"answered"`
+	Graded     int64   `json:"graded"`
+	Correct    int64   `json:"correct"`
+	Score      float64 `json:"score"`
+}
+
+// quizAnalytics returns per-question answer counts and per-viewer scores for
+// answers given since the start of the analytics range. Failures yield empty
+// results, like the other optional analytics sections.
+func (h *Handler) quizAnalytics(ctx context.Context, videoID string, since time.Time) ([]questionResult, []quizScore) {
+	results := make([]questionResult, 0)
+	rows, err := h.db.Query(ctx,
+		`SELECT q.id, q.prompt, q.options, q.correct_option, a.option_index, COUNT(a.id), COUNT(a.id) FILTER (WHERE a.is_correct)
+		 FROM video_questions q
+		 LEFT JOIN question_answers a ON a.question_id = q.id AND a.created_at >= $2
+		 WHERE q.video_id = $1
+		 GROUP BY q.id, a.option_index
+		 ORDER BY q.time_seconds, q.created_at, q.id`,
+		videoID, since,
+	)
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var qr questionResult
+			var optionIndex *int
+			var count, correct int64
+			if err := rows.Scan(&qr.ID, &qr.Prompt, &qr.Options, &qr.CorrectOption, &optionIndex, &count, &correct); err != nil {
+				continue
+			}
+			if len(results) == 0 || results[len(results)-1].ID != qr.ID {
+				qr.OptionCounts = make([]int64, len(qr.Options))
+				results = append(results, qr)
+			}
+			cur := &results[len(results)-1]
+			if optionIndex == nil || *optionIndex < 0 || *optionIndex >= len(cur.OptionCounts) {
+				continue
+			}
+			cur.OptionCounts[*optionIndex] += count
+			cur.Responses += count
+			cur.CorrectResponses += correct
+		}
+	}
+
+	scores := make([]quizScore, 0)
+	scoreRows, err := h.db.Query(ctx,
+		`SELECT COALESCE(MAX(email), ''), MAX(viewer_hash), COUNT(*),
+		        COUNT(*) FILTER (WHERE is_correct IS NOT NULL), COUNT(*) FILTER (WHERE is_correct)
+		 FROM question_answers
+		 WHERE video_id = $1 AND created_at >= $2
+		 GROUP BY viewer_key
+		 ORDER BY 5 DESC, 3 DESC
+		 LIMIT `+strconv.Itoa(maxQuizScoreRows),
+		videoID, since,
+	)
+	if err == nil {
+		defer scoreRows.Close()
+		for scoreRows.Next() {
+			var qs quizScore
+			if err := scoreRows.Scan(&qs.Email, &qs.ViewerHash, &qs.Answered, &qs.Graded, &qs.Correct); err != nil {
+				continue
+			}
+			if qs.Graded > 0 {
+				qs.Score = math.Round(float64(qs.Correct)/float64(qs.Graded)*1000) / 10
+			}
+			scores = append(scores, qs)
+		}
+	}
+
+	return results, scores
+}
@@ -0,0 +1,286 @@
+package video
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+const watchVideoLookupQuery = `SELECT v\.id, v\.user_id, v\.comment_mode, v\.share_expires_at, v\.share_password, COALESCE\(v\.visibility, f\.visibility, 'public'\) FROM videos v LEFT JOIN folders f ON f\.id = v\.folder_id WHERE v\.share_token = \$1`
+
+func intPtr(i int) *int { return &i }
+
+func TestNormalizeVideoQuestion(t *testing.T) {
+	got, msg := normalizeVideoQuestion(videoQuestionRequest{
+		TimeSeconds: 42, Prompt: "  Which port does HTTPS use? ", Options: []string{" 80", "443 "}, CorrectOption: intPtr(1),
+	})
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if got.Prompt != "Which port does HTTPS use?" || got.Options[0] != "80" || got.Options[1] != "443" {
+		t.Errorf("expected trimmed question, got %+v", got)
+	}
+
+	bad := map[string]videoQuestionRequest{
+		"negative time":    {TimeSeconds: -1, Prompt: "Q", Options: []string{"a", "b"}},
+		"missing prompt":   {Prompt: " ", Options: []string{"a", "b"}},
+		"one option":       {Prompt: "Q", Options: []string{"a"}},
+		"too many options": {Prompt: "Q", Options: []string{"a", "b", "c", "d", "e", "f", "g"}},
+		"blank option":     {Prompt: "Q", Options: []string{"a", " "}},
+		"correct too high": {Prompt: "Q", Options: []string{"a", "b"}, CorrectOption: intPtr(2)},
+		"correct negative": {Prompt: "Q", Options: []string{"a", "b"}, CorrectOption: intPtr(-1)},
+	}
+	for name, req := range bad {
+		if _, msg := normalizeVideoQuestion(req); msg == "" {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+
+	if _, msg := normalizeVideoQuestion(videoQuestionRequest{Prompt: "Favourite feature?", Options: []string{"Comments", "CTAs"}}); msg != "" {
+		t.Errorf("expected ungraded poll to be valid, got %s", msg)
+	}
+}
+
+func TestCreateVideoQuestion(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-123"
+
+	mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM video_questions WHERE video_id = videos.id\) FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(videoID, testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO video_questions`).
+		WithArgs(videoID, 42.0, "Which port does HTTPS use?", []string{"80", "443"}, intPtr(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("q-1"))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Post("/api/videos/{id}/questions", handler.CreateVideoQuestion)
+	rec := httptest.NewRecorder()
+	body := []byte(`{"timeSeconds":42,"prompt":"Which port does HTTPS use?","options":["80","443"],"correctOption":1}`)
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPost, "/api/videos/"+videoID+"/questions", body))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"id":"q-1"`) || !strings.Contains(rec.Body.String(), `"correctOption":1`) {
+		t.Errorf("expected created question, got %s", rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteVideoQuestion_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("video-123", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("video-123"))
+	mock.ExpectExec(`DELETE FROM video_questions WHERE id = \$1 AND video_id = \$2`).
+		WithArgs("q-404", "video-123").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Delete("/api/videos/{id}/questions/{questionId}", handler.DeleteVideoQuestion)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodDelete, "/api/videos/video-123/questions/q-404", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func serveAnswer(handler *Handler, shareToken, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/answers", handler.RecordAnswer)
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/"+shareToken+"/answers", strings.NewReader(body))
+	req.RemoteAddr = "192.168.1.1:12345"
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRecordAnswer_GradedWithEmailGateIdentity(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	shareToken := "abc123defghi"
+
+	mock.ExpectQuery(watchVideoLookupQuery).
+		WithArgs(shareToken).
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "disabled", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT cardinality\(options\), correct_option FROM video_questions WHERE id = \$1 AND video_id = \$2`).
+		WithArgs("q-1", "video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"cardinality", "correct_option"}).AddRow(3, intPtr(2)))
+	email := "Learner@Example.com"
+	correct := false
+	mock.ExpectExec(`INSERT INTO question_answers .* ON CONFLICT \(question_id, viewer_key\) DO NOTHING`).
+		WithArgs("q-1", "video-123", "learner@example.com", pgxmock.AnyArg(), &email, 0, &correct).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	rec := serveAnswer(handler, shareToken, `{"questionId":"q-1","optionIndex":0}`, gateCookie(t, shareToken, email))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp answerResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Correct == nil || *resp.Correct || resp.CorrectOption == nil || *resp.CorrectOption != 2 {
+		t.Errorf("expected incorrect answer revealing option 2, got %s", rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRecordAnswer_PollIsUngraded(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	shareToken := "abc123defghi"
+
+	mock.ExpectQuery(watchVideoLookupQuery).
+		WithArgs(shareToken).
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "disabled", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT cardinality\(options\), correct_option FROM video_questions`).
+		WithArgs("q-2", "video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"cardinality", "correct_option"}).AddRow(2, (*int)(nil)))
+	mock.ExpectExec(`INSERT INTO question_answers`).
+		WithArgs("q-2", "video-123", pgxmock.AnyArg(), pgxmock.AnyArg(), (*string)(nil), 1, (*bool)(nil)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	rec := serveAnswer(handler, shareToken, `{"questionId":"q-2","optionIndex":1}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Body.String() != "{\"correct\":null,\"correctOption\":null}\n" {
+		t.Errorf("expected ungraded response, got %s", rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRecordAnswer_RejectsUnknownOptionAndQuestion(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	shareToken := "abc123defghi"
+
+	mock.ExpectQuery(watchVideoLookupQuery).
+		WithArgs(shareToken).
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "disabled", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT cardinality\(options\), correct_option FROM video_questions`).
+		WithArgs("q-1", "video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"cardinality", "correct_option"}).AddRow(2, intPtr(0)))
+
+	if rec := serveAnswer(handler, shareToken, `{"questionId":"q-1","optionIndex":5}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for out-of-range option, got %d", rec.Code)
+	}
+
+	mock.ExpectQuery(watchVideoLookupQuery).
+		WithArgs(shareToken).
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-1", "disabled", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT cardinality\(options\), correct_option FROM video_questions`).
+		WithArgs("q-other", "video-123").
+		WillReturnError(pgx.ErrNoRows)
+
+	if rec := serveAnswer(handler, shareToken, `{"questionId":"q-other","optionIndex":0}`); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for question from another video, got %d", rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestAnalyticsExport_IncludesQuizResults(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT id FROM videos WHERE id = \$1 AND user_id = \$2 AND status != 'deleted'`).
+		WithArgs("vid-1", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("vid-1"))
+	mock.ExpectQuery(`SELECT day, views, unique_viewers`).
+		WithArgs("vid-1", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"day", "views", "unique_views"}))
+	mock.ExpectQuery(`SELECT q.id, q.prompt, q.options, q.correct_option, a.option_index`).
+		WithArgs("vid-1", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "prompt", "options", "correct_option", "option_index", "count", "correct"}).
+			AddRow("q-1", "Which port, exactly?", []string{"80", "443"}, intPtr(1), intPtr(0), int64(1), int64(0)).
+			AddRow("q-1", "Which port, exactly?", []string{"80", "443"}, intPtr(1), intPtr(1), int64(3), int64(3)).
+			AddRow("q-2", "Favourite feature?", []string{"Comments", "CTAs"}, (*int)(nil), (*int)(nil), int64(0), int64(0)))
+	mock.ExpectQuery(`FROM question_answers WHERE video_id = \$1 AND created_at >= \$2 GROUP BY viewer_key`).
+		WithArgs("vid-1", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "viewer_hash", "answered", "graded", "correct"}).
+			AddRow("learner@example.com", "abcd", int64(2), int64(1), int64(1)).
+			AddRow("", "ef01", int64(1), int64(1), int64(0)))
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Get("/api/videos/{id}/analytics/export", handler.AnalyticsExport)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodGet, "/api/videos/vid-1/analytics/export?range=7d", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"Question,Option,Responses,Correct Answer\n",
+		"\"Which port, exactly?\",443,3,yes\n",
+		"Favourite feature?,Comments,0,\n",
+		"Viewer,Answered,Graded,Correct,Score %\n",
+		"learner@example.com,2,1,1,100.0\n",
+		"anonymous-ef01,1,1,0,0.0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected CSV to contain %q, got:\n%s", want, body)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
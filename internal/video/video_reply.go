@@ -0,0 +1,410 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/httputil"
+	"github.com/sendrec/sendrec/internal/validate"
+)
+
+// Defaults for the clips viewers can send back, used unless the server is
+// configured otherwise.
+const (
+	DefaultVideoReplyMaxBytes   = 100 * 1024 * 1024
+	DefaultVideoReplyMaxSeconds = 120
+
+	commentUploading = "uploading"
+	videoReplyLabel  = "sent a video reply"
+)
+
+type setVideoRepliesRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type createVideoReplyRequest struct {
+	AuthorName  string `json:"authorName"`
+	AuthorEmail string `json:"authorEmail"`
+	Body        string `json:"body"`
+	Duration    int    `json:"duration"`
+	FileSize    int64  `json:"fileSize"`
+	ContentType string `json:"contentType"`
+}
+
+type createVideoReplyResponse struct {
+	CommentID string `json:"commentId"`
+	UploadURL string `json:"uploadUrl"`
+}
+
+// videoReplyResponse is the clip attached to a video reply comment.
+type videoReplyResponse struct {
+	ID           string `json:"id"`
+	Duration     int    `json:"duration"`
+	VideoURL     string `json:"videoUrl,omitempty"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+}
+
+// videoReplyRow is how queryComments selects a reply's clip.
+type videoReplyRow struct {
+	ID           string  `json:"id"`
+	Duration     int     `json:"duration"`
+	FileKey      string  `json:"fileKey"`
+	ThumbnailKey *string `json:"thumbnailKey"`
+}
+
+func (h *Handler) SetVideoReplies(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	var req setVideoRepliesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	where, args := orgVideoFilter(r.Context(), videoID, []any{req.Enabled}, "AND status != 'deleted' AND parent_video_id IS NULL")
+	tag, err := h.db.Exec(r.Context(),
+		`UPDATE videos SET video_replies_enabled = $1 WHERE `+where, args...,
+	)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not update video replies setting")
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateVideoReply starts a viewer's video reply: it creates the clip's
+// recording and a comment that stays hidden until CompleteVideoReply, and
+// returns where to upload the clip.
+func (h *Handler) CreateVideoReply(w http.ResponseWriter, r *http.Request) {
+	shareToken := chi.URLParam(r, "shareToken")
+	videoID, ownerID, commentMode, ok := h.lookupWatchVideo(w, r)
+	if !ok {
+		return
+	}
+
+	var orgID *string
+	var enabled bool
+	if err := h.db.QueryRow(r.Context(),
+		`SELECT organization_id, video_replies_enabled FROM videos WHERE id = $1`,
+		videoID,
+	).Scan(&orgID, &enabled); err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+	if commentMode == "disabled" || !enabled {
+		httputil.WriteError(w, http.StatusForbidden, "video replies are disabled")
+		return
+	}
+
+	var req createVideoReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.AuthorName = strings.TrimSpace(req.AuthorName)
+	req.AuthorEmail = strings.TrimSpace(req.AuthorEmail)
+	req.Body = strings.TrimSpace(req.Body)
+
+	if len(req.AuthorName) > 200 {
+		httputil.WriteError(w, http.StatusBadRequest, "name is too long")
+		return
+	}
+	if len(req.AuthorEmail) > 320 {
+		httputil.WriteError(w, http.StatusBadRequest, "email is too long")
+		return
+	}
+	if msg := validate.CommentBody(req.Body); msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if (commentMode == "name_required" || commentMode == "name_email_required") && req.AuthorName == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if commentMode == "name_email_required" && req.AuthorEmail == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+	if req.ContentType != "video/webm" && req.ContentType != "video/mp4" {
+		httputil.WriteError(w, http.StatusBadRequest, "only video/webm and video/mp4 replies are supported")
+		return
+	}
+	if req.FileSize <= 0 {
+		httputil.WriteError(w, http.StatusBadRequest, "fileSize must be positive")
+		return
+	}
+	if h.videoReplyMaxBytes > 0 && req.FileSize > h.videoReplyMaxBytes {
+		httputil.WriteError(w, http.StatusBadRequest, fmt.Sprintf("video replies can be at most %d MB", h.videoReplyMaxBytes/(1024*1024)))
+		return
+	}
+	if req.Duration < 1 {
+		httputil.WriteError(w, http.StatusBadRequest, "video duration must be at least 1 second")
+		return
+	}
+	if h.videoReplyMaxSeconds > 0 && req.Duration > h.videoReplyMaxSeconds {
+		httputil.WriteError(w, http.StatusBadRequest, fmt.Sprintf("video replies can be at most %d seconds long", h.videoReplyMaxSeconds))
+		return
+	}
+
+	callerUserID := h.optionalUserID(r)
+	var userIDArg, authorKeyArg *string
+	if callerUserID != "" {
+		userIDArg = &callerUserID
+	} else {
+		key, err := h.ensureCommentAuthorKey(w, r, shareToken)
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not start video reply")
+			return
+		}
+		authorKeyArg = &key
+	}
+
+	if callerUserID != ownerID {
+		banned, err := h.commentAuthorBanned(r.Context(), videoID, userIDArg, req.AuthorEmail, authorKeyArg)
+		if err != nil {
+			httputil.WriteError(w, http.StatusInternalServerError, "could not start video reply")
+			return
+		}
+		if banned {
+			httputil.WriteError(w, http.StatusForbidden, "you can no longer comment on this video")
+			return
+		}
+	}
+
+	replyToken, err := generateShareToken()
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not start video reply")
+		return
+	}
+	// The clip belongs to the video's owner: it lives under their recordings
+	// and is processed like any of their videos, but stays out of the library.
+	fileKey := videoFileKey(ownerID, replyToken, req.ContentType)
+	author := req.AuthorName
+	if author == "" {
+		author = "Anonymous"
+	}
+
+	// The clip and its comment are created together: a reply video without
+	// its comment is hidden from the library and would never be cleaned up.
+	var commentID string
+	err = database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+		var replyVideoID string
+		if err := tx.QueryRow(r.Context(),
+			`INSERT INTO videos (user_id, organization_id, title, duration, file_size, file_key, share_token, content_type, parent_video_id, comment_mode)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 'disabled') RETURNING id`,
+			ownerID, orgID, "Video reply from "+author, req.Duration, req.FileSize, fileKey, replyToken, req.ContentType, videoID,
+		).Scan(&replyVideoID); err != nil {
+			return err
+		}
+		return tx.QueryRow(r.Context(),
+			`INSERT INTO video_comments (video_id, user_id, author_name, author_email, body, is_private, author_key, status, reply_video_id)
+			 VALUES ($1, $2, $3, $4, $5, false, $6, $7, $8) RETURNING id`,
+			videoID, userIDArg, req.AuthorName, req.AuthorEmail, req.Body, authorKeyArg, commentUploading, replyVideoID,
+		).Scan(&commentID)
+	})
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not start video reply")
+		return
+	}
+
+	uploadURL, err := h.storage.GenerateUploadURL(r.Context(), fileKey, req.ContentType, req.FileSize, 30*time.Minute)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to generate upload URL")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, createVideoReplyResponse{
+		CommentID: commentID,
+		UploadURL: uploadURL,
+	})
+}
+
+// CompleteVideoReply verifies an uploaded clip, hands it to the processing
+// pipeline and publishes (or holds) the reply's comment. Only the viewer who
+// started the reply can complete it.
+func (h *Handler) CompleteVideoReply(w http.ResponseWriter, r *http.Request) {
+	shareToken := chi.URLParam(r, "shareToken")
+	commentID := chi.URLParam(r, "commentId")
+	videoID, ownerID, commentMode, ok := h.lookupWatchVideo(w, r)
+	if !ok {
+		return
+	}
+
+	var userID, authorKey *string
+	var authorName, authorEmail, body string
+	var replyVideoID, fileKey, replyToken, expectedContentType string
+	var fileSize int64
+	err := h.db.QueryRow(r.Context(),
+		`SELECT c.user_id, c.author_key, c.author_name, c.author_email, c.body,
+		        rv.id, rv.file_key, rv.file_size, rv.content_type, rv.share_token
+		 FROM video_comments c JOIN videos rv ON rv.id = c.reply_video_id
+		 WHERE c.id = $1 AND c.video_id = $2 AND c.status = 'uploading' AND rv.status = 'uploading'`,
+		commentID, videoID,
+	).Scan(&userID, &authorKey, &authorName, &authorEmail, &body,
+		&replyVideoID, &fileKey, &fileSize, &expectedContentType, &replyToken)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video reply not found")
+		return
+	}
+
+	callerUserID := h.optionalUserID(r)
+	viewer := commentViewer{userID: callerUserID, authorKey: commentAuthorKey(r, h.hmacSecret, shareToken)}
+	if !viewer.wrote(userID, authorKey) {
+		httputil.WriteError(w, http.StatusNotFound, "video reply not found")
+		return
+	}
+
+	size, contentType, err := h.storage.HeadObject(r.Context(), fileKey)
+	if err != nil {
+		slog.Warn("video-reply: could not verify upload", "video_id", replyVideoID, "error", err)
+		httputil.WriteError(w, http.StatusBadRequest, "could not verify upload")
+		return
+	}
+	if size <= 0 || size != fileSize || (h.videoReplyMaxBytes > 0 && size > h.videoReplyMaxBytes) {
+		httputil.WriteError(w, http.StatusBadRequest, "uploaded file invalid size")
+		return
+	}
+	if contentType != expectedContentType {
+		httputil.WriteError(w, http.StatusBadRequest, "uploaded file invalid type")
+		return
+	}
+
+	tag, err := h.db.Exec(r.Context(),
+		`UPDATE videos SET status = 'ready', updated_at = now() WHERE id = $1 AND status = 'uploading'`,
+		replyVideoID,
+	)
+	if err != nil || tag.RowsAffected() == 0 {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not complete video reply")
+		return
+	}
+
+	h.EnqueueJob(r.Context(), JobTypeThumbnail, replyVideoID, map[string]any{
+		"fileKey":      fileKey,
+		"thumbnailKey": thumbnailFileKey(ownerID, replyToken),
+	})
+	// The declared duration can't be trusted, so always probe the clip.
+	h.EnqueueJob(r.Context(), JobTypeProbe, replyVideoID, map[string]any{
+		"fileKey":     fileKey,
+		"maxDuration": h.videoReplyMaxSeconds,
+	})
+	if expectedContentType == "video/webm" {
+		h.EnqueueJob(r.Context(), JobTypeTranscode, replyVideoID, map[string]any{"fileKey": fileKey})
+	} else {
+		h.EnqueueJob(r.Context(), JobTypeNormalize, replyVideoID, map[string]any{"fileKey": fileKey})
+	}
+
+	moderation := commentModeration{status: commentApproved, spamReasons: []string{}}
+	if callerUserID == "" || callerUserID != ownerID {
+		moderation = h.moderateComment(r.Context(), commentMode, SpamCandidate{
+			Body:          body,
+			AuthorName:    authorName,
+			AuthorEmail:   authorEmail,
+			Authenticated: callerUserID != "",
+		})
+	}
+
+	var createdAt time.Time
+	err = h.db.QueryRow(r.Context(),
+		`UPDATE video_comments SET status = $1, spam_score = $2, spam_reasons = $3, created_at = now()
+		 WHERE id = $4 AND status = 'uploading'
+		 RETURNING created_at`,
+		moderation.status, moderation.spamScore, moderation.spamReasons, commentID,
+	).Scan(&createdAt)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not complete video reply")
+		return
+	}
+
+	if moderation.status == commentApproved {
+		notifyBody := body
+		if notifyBody == "" {
+			notifyBody = videoReplyLabel
+		}
+		h.publishLiveComment(r.Context(), videoID, commentID, authorName, notifyBody)
+		shouldEmailComment := h.commentNotifier != nil && h.shouldSendImmediateCommentNotification(r.Context(), ownerID)
+		notice := commentNotice{
+			videoID:      videoID,
+			shareToken:   shareToken,
+			ownerID:      ownerID,
+			commentID:    commentID,
+			authorUserID: callerUserID,
+			authorName:   authorName,
+			authorEmail:  authorEmail,
+			body:         notifyBody,
+			emailOwner:   callerUserID != ownerID && shouldEmailComment,
+			slackOwner:   callerUserID != ownerID && h.slackNotifier != nil,
+		}
+		notice.webhookOwner = notice.emailOwner || notice.slackOwner
+		if notice.notifiesAnyone(h) {
+			go h.sendCommentNotifications(notice)
+		}
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, commentResponse{
+		ID:         commentID,
+		AuthorName: authorName,
+		Body:       body,
+		IsOwner:    callerUserID != "" && callerUserID == ownerID,
+		CreatedAt:  createdAt.Format(time.RFC3339),
+		CanEdit:    true,
+		Status:     moderation.status,
+		VideoReply: &videoReplyResponse{ID: replyVideoID},
+	})
+}
+
+// videoReplyFromRow presigns the URLs of a reply's clip for a comment list.
+func (h *Handler) videoReplyFromRow(ctx context.Context, raw []byte) (*videoReplyResponse, error) {
+	var row videoReplyRow
+	if err := json.Unmarshal(raw, &row); err != nil {
+		return nil, err
+	}
+	reply := &videoReplyResponse{ID: row.ID, Duration: row.Duration}
+	if u, err := h.storage.GenerateDownloadURL(ctx, row.FileKey, 1*time.Hour); err == nil {
+		reply.VideoURL = u
+	}
+	if row.ThumbnailKey != nil {
+		if u, err := h.storage.GenerateDownloadURL(ctx, *row.ThumbnailKey, 1*time.Hour); err == nil {
+			reply.ThumbnailURL = u
+		}
+	}
+	return reply, nil
+}
+
+// rejectOverlongVideoReply retires a video reply whose probed length is over
+// the cap, since the length declared when the upload started is only the
+// viewer's word.
+func rejectOverlongVideoReply(ctx context.Context, db database.DBTX, videoID string, maxSeconds int) {
+	tag, err := db.Exec(ctx,
+		`UPDATE videos SET status = 'deleted', updated_at = now()
+		 WHERE id = $1 AND parent_video_id IS NOT NULL AND duration > $2 AND status != 'deleted'`,
+		videoID, maxSeconds,
+	)
+	if err != nil {
+		slog.Error("video-reply: failed to check duration", "video_id", videoID, "error", err)
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		return
+	}
+	if _, err := db.Exec(ctx,
+		`UPDATE video_comments SET status = 'rejected', moderated_at = now() WHERE reply_video_id = $1`,
+		videoID,
+	); err != nil {
+		slog.Error("video-reply: failed to reject overlong reply", "video_id", videoID, "error", err)
+		return
+	}
+	slog.Warn("video-reply: rejected reply over the duration cap", "video_id", videoID, "max_seconds", maxSeconds)
+}
@@ -0,0 +1,363 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func serveCreateVideoReply(handler *Handler, body any) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/video-replies", handler.CreateVideoReply)
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/abc123defghi/video-replies", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func serveCompleteVideoReply(handler *Handler, cookie *http.Cookie) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Post("/api/watch/{shareToken}/video-replies/{commentId}/complete", handler.CompleteVideoReply)
+	req := httptest.NewRequest(http.MethodPost, "/api/watch/abc123defghi/video-replies/c1/complete", nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func expectVideoReplyTarget(mock pgxmock.PgxPoolIface, enabled bool) {
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT organization_id, video_replies_enabled FROM videos WHERE id = \$1`).
+		WithArgs("video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id", "video_replies_enabled"}).AddRow((*string)(nil), enabled))
+}
+
+func expectPendingVideoReply(mock pgxmock.PgxPoolIface, authorKey string) {
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT c\.user_id, c\.author_key, c\.author_name, c\.author_email, c\.body,\s+rv\.id`).
+		WithArgs("c1", "video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "author_key", "author_name", "author_email", "body", "id", "file_key", "file_size", "content_type", "share_token"}).
+			AddRow((*string)(nil), &authorKey, "Sam", "", "", "reply-1", "recordings/owner-user-1/reply.webm", int64(5000), "video/webm", "replytoken12"))
+}
+
+func TestSetVideoReplies(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectExec(`UPDATE videos SET video_replies_enabled = \$1 WHERE id = \$2 AND user_id = \$3`).
+		WithArgs(true, "video-123", testUserID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	body, _ := json.Marshal(setVideoRepliesRequest{Enabled: true})
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/video-replies", handler.SetVideoReplies)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/video-123/video-replies", body))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreateVideoReply_Disabled(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	expectVideoReplyTarget(mock, false)
+
+	rec := serveCreateVideoReply(handler, map[string]any{"duration": 10, "fileSize": 5000, "contentType": "video/webm"})
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateVideoReply_EnforcesCaps(t *testing.T) {
+	tests := []struct {
+		name string
+		body map[string]any
+	}{
+		{"too long", map[string]any{"duration": 90, "fileSize": 5000, "contentType": "video/webm"}},
+		{"too large", map[string]any{"duration": 10, "fileSize": 2 * 1024 * 1024, "contentType": "video/webm"}},
+		{"wrong type", map[string]any{"duration": 10, "fileSize": 5000, "contentType": "video/quicktime"}},
+		{"no duration", map[string]any{"duration": 0, "fileSize": 5000, "contentType": "video/webm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+			handler.SetVideoReplyLimits(1024*1024, 60)
+			expectVideoReplyTarget(mock, true)
+
+			rec := serveCreateVideoReply(handler, tt.body)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateVideoReply_CreatesChildRecording(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	storage := &mockStorage{uploadURL: "https://s3.example.com/upload?signed=abc"}
+	handler := NewHandler(mock, storage, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetVideoReplyLimits(DefaultVideoReplyMaxBytes, DefaultVideoReplyMaxSeconds)
+	expectVideoReplyTarget(mock, true)
+	expectNoCommentBan(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO videos \(user_id, organization_id, title, duration, file_size, file_key, share_token, content_type, parent_video_id, comment_mode\)`).
+		WithArgs("owner-user-1", (*string)(nil), "Video reply from Sam", 30, int64(5000), pgxmock.AnyArg(), pgxmock.AnyArg(), "video/webm", "video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("reply-1"))
+	mock.ExpectQuery(`INSERT INTO video_comments \(video_id, user_id, author_name, author_email, body, is_private, author_key, status, reply_video_id\)`).
+		WithArgs("video-123", (*string)(nil), "Sam", "", "Here's what I mean", pgxmock.AnyArg(), "uploading", "reply-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("c1"))
+	mock.ExpectCommit()
+
+	rec := serveCreateVideoReply(handler, map[string]any{
+		"authorName":  "Sam",
+		"body":        "Here's what I mean",
+		"duration":    30,
+		"fileSize":    5000,
+		"contentType": "video/webm",
+	})
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp createVideoReplyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.CommentID != "c1" || resp.UploadURL != storage.uploadURL {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if len(rec.Result().Cookies()) != 1 {
+		t.Errorf("expected an author cookie so the viewer can complete the reply")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreateVideoReply_RollsBackClipWhenCommentFails(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	handler.SetVideoReplyLimits(DefaultVideoReplyMaxBytes, DefaultVideoReplyMaxSeconds)
+	expectVideoReplyTarget(mock, true)
+	expectNoCommentBan(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO videos`).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("reply-1"))
+	mock.ExpectQuery(`INSERT INTO video_comments`).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), "reply-1").
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	rec := serveCreateVideoReply(handler, map[string]any{
+		"body":        "Here's what I mean",
+		"duration":    30,
+		"fileSize":    5000,
+		"contentType": "video/webm",
+	})
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCompleteVideoReply_OtherViewerGets404(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	expectPendingVideoReply(mock, "key-1")
+
+	rec := serveCompleteVideoReply(handler, &http.Cookie{
+		Name:  commentAuthorCookieName("abc123defghi"),
+		Value: signCommentAuthorCookie(testJWTSecret, "abc123defghi", "key-2"),
+	})
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCompleteVideoReply_RejectsSizeMismatch(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{headSize: 9000, headType: "video/webm"}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	expectPendingVideoReply(mock, "key-1")
+
+	rec := serveCompleteVideoReply(handler, &http.Cookie{
+		Name:  commentAuthorCookieName("abc123defghi"),
+		Value: signCommentAuthorCookie(testJWTSecret, "abc123defghi", "key-1"),
+	})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCompleteVideoReply_PublishesComment(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{headSize: 5000, headType: "video/webm"}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	expectPendingVideoReply(mock, "key-1")
+	mock.ExpectExec(`UPDATE videos SET status = 'ready', updated_at = now\(\) WHERE id = \$1 AND status = 'uploading'`).
+		WithArgs("reply-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectQuery(`UPDATE video_comments SET status = \$1, spam_score = \$2, spam_reasons = \$3, created_at = now\(\)`).
+		WithArgs("approved", (*float64)(nil), []string{}, "c1").
+		WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+
+	rec := serveCompleteVideoReply(handler, &http.Cookie{
+		Name:  commentAuthorCookieName("abc123defghi"),
+		Value: signCommentAuthorCookie(testJWTSecret, "abc123defghi", "key-1"),
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp commentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != commentApproved || resp.VideoReply == nil || resp.VideoReply.ID != "reply-1" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRejectOverlongVideoReply(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec(`UPDATE videos SET status = 'deleted', updated_at = now\(\)\s+WHERE id = \$1 AND parent_video_id IS NOT NULL AND duration > \$2`).
+		WithArgs("reply-1", 120).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`UPDATE video_comments SET status = 'rejected'`).
+		WithArgs("reply-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	rejectOverlongVideoReply(context.Background(), mock, "reply-1", 120)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestListWatchComments_IncludesVideoReply(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	storage := &mockStorage{downloadURL: "https://s3.example.com/download?signed=xyz"}
+	handler := NewHandler(mock, storage, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectQuery(`SELECT v\.id, v\.user_id, v\.comment_mode`).
+		WithArgs("abc123defghi").
+		WillReturnRows(commentVideoRows().AddRow("video-123", "owner-user-1", "anonymous", (*time.Time)(nil), (*string)(nil), "public"))
+	mock.ExpectQuery(`SELECT c\.id, c\.user_id`).
+		WithArgs("video-123").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "author_name", "body", "is_private", "created_at", "video_timestamp_seconds", "parent_id", "edited_at", "resolved_at", "author_key", "mentions", "annotation", "video_reply"}).
+			AddRow("c1", (*string)(nil), "Sam", "", false, time.Now(), (*float64)(nil), (*string)(nil), (*time.Time)(nil), (*time.Time)(nil), (*string)(nil), []byte(nil), []byte(nil),
+				[]byte(`{"id":"reply-1","duration":30,"fileKey":"recordings/owner-user-1/reply.webm","thumbnailKey":null}`)))
+
+	r := chi.NewRouter()
+	r.Get("/api/watch/{shareToken}/comments", handler.ListWatchComments)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/watch/abc123defghi/comments", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp listCommentsResponseBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Comments) != 1 || resp.Comments[0].VideoReply == nil {
+		t.Fatalf("expected a video reply, got %+v", resp.Comments)
+	}
+	reply := resp.Comments[0].VideoReply
+	if reply.Duration != 30 || reply.VideoURL != storage.downloadURL || reply.ThumbnailURL != "" {
+		t.Errorf("unexpected video reply %+v", reply)
+	}
+	if bytes.Contains(rec.Body.Bytes(), []byte("recordings/")) {
+		t.Errorf("storage keys must not be exposed: %s", rec.Body.String())
+	}
+}
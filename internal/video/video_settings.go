@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sendrec/sendrec/internal/database"
 	"github.com/sendrec/sendrec/internal/httputil"
 	"github.com/sendrec/sendrec/internal/languages"
 )
@@ -20,7 +22,10 @@ type setDownloadEnabledRequest struct {
 }
 
 type setEmailGateRequest struct {
-	Enabled bool `json:"enabled"`
+	Enabled        bool         `json:"enabled"`
+	Verified       *bool        `json:"verified"`
+	AllowedDomains *[]string    `json:"allowedDomains"`
+	Fields         *[]gateField `json:"fields"`
 }
 
 type setLinkExpiryRequest struct {
@@ -74,16 +79,76 @@ func (h *Handler) SetEmailGate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var domains []string
+	if req.AllowedDomains != nil {
+		var msg string
+		if domains, msg = normalizeEmailGateDomains(*req.AllowedDomains); msg != "" {
+			httputil.WriteError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
+	var fields []gateField
+	if req.Fields != nil {
+		var msg string
+		if fields, msg = normalizeGateFields(*req.Fields); msg != "" {
+			httputil.WriteError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
 	where, args := orgVideoFilter(r.Context(), videoID, []any{req.Enabled}, "AND status != 'deleted'")
-	tag, err := h.db.Exec(r.Context(),
-		`UPDATE videos SET email_gate_enabled = $1 WHERE `+where, args...,
-	)
-	if err != nil {
-		httputil.WriteError(w, http.StatusInternalServerError, "could not update email gate setting")
+	err := database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(r.Context(),
+			`UPDATE videos SET email_gate_enabled = $1 WHERE `+where, args...,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+
+		if req.Verified != nil {
+			if _, err := tx.Exec(r.Context(),
+				`UPDATE videos SET email_gate_verified = $1 WHERE id = $2`, *req.Verified, videoID,
+			); err != nil {
+				return err
+			}
+		}
+
+		if fields != nil {
+			fieldsJSON, _ := json.Marshal(fields)
+			if _, err := tx.Exec(r.Context(),
+				`UPDATE videos SET email_gate_fields = $1 WHERE id = $2`, string(fieldsJSON), videoID,
+			); err != nil {
+				return err
+			}
+		}
+
+		if domains != nil {
+			if _, err := tx.Exec(r.Context(),
+				`DELETE FROM video_email_gate_domains WHERE video_id = $1`, videoID,
+			); err != nil {
+				return err
+			}
+			for _, domain := range domains {
+				if _, err := tx.Exec(r.Context(),
+					`INSERT INTO video_email_gate_domains (video_id, domain) VALUES ($1, $2)`,
+					videoID, domain,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
 		return
 	}
-	if tag.RowsAffected() == 0 {
-		httputil.WriteError(w, http.StatusNotFound, "video not found")
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not update email gate setting")
 		return
 	}
 
@@ -21,25 +21,55 @@ type viewParams struct {
 }
 
 func (h *Handler) recordViewAsync(r *http.Request, p viewParams) {
+	campaign := parseCampaignParams(r.URL.Query())
+	class, botReason := classifyViewRequest(r)
+	var botReasonArg any
+	if botReason != "" {
+		botReasonArg = botReason
+	}
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		ip := httputil.ClientIP(r)
-		hash := viewerHash(ip, r.UserAgent())
+		private := h.analyticsPrivacy(ctx, p.videoID)
+		hash, linked := h.viewerHashFor(ctx, r, private)
 		ref := categorizeReferrer(r.Header.Get("Referer"))
 		browser := parseBrowser(r.UserAgent())
 		device := parseDevice(r.UserAgent())
 		var country, city string
-		if h.geoResolver != nil {
-			country, city = h.geoResolver.Lookup(ip)
+		if h.geoResolver != nil && !(private && doNotTrack(r)) {
+			country, city = h.geoResolver.Lookup(httputil.ClientIP(r))
+			if private {
+				city = ""
+			}
 		}
+		// Link scanners often look like browsers, so the owner is only told
+		// about a view once the viewer starts playback (see confirmPlayback).
+		// A view that cannot be tied to its beacons, or the owner's own view,
+		// is not held back.
+		ownView := p.viewerUserID != "" && p.viewerUserID == p.ownerID
+		pending := class == viewSuspect || (class == viewHuman && linked && !ownView)
 		if _, err := h.db.Exec(ctx,
-			`INSERT INTO video_views (video_id, viewer_hash, referrer, browser, device, country, city)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-			p.videoID, hash, ref, browser, device, country, city,
+			`INSERT INTO video_views (video_id, viewer_hash, referrer, browser, device, country, city,
+			                          utm_source, utm_medium, utm_campaign, utm_term, utm_content, ref, is_bot, bot_reason,
+			                          notify_pending)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+			append(append([]any{p.videoID, hash, ref, browser, device, country, city}, campaign.insertArgs()...),
+				class != viewHuman, botReasonArg, pending)...,
 		); err != nil {
 			slog.Error("failed to record view", "video_id", p.videoID, "error", err)
 		}
+		if class != viewHuman {
+			return
+		}
+		h.publishLive(ctx, liveEventView, p.videoID, hash, map[string]string{
+			"referrer": ref,
+			"browser":  browser,
+			"device":   device,
+			"country":  country,
+		})
+		if pending {
+			return
+		}
 		h.resolveAndNotify(ctx, p.videoID, p.ownerID, p.ownerEmail, p.ownerName, p.title, p.shareToken, p.viewerUserID, p.viewNotification)
 	}()
 }
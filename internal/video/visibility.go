@@ -0,0 +1,473 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sendrec/sendrec/internal/auth"
+	"github.com/sendrec/sendrec/internal/database"
+	"github.com/sendrec/sendrec/internal/httputil"
+)
+
+const (
+	visibilityPublic = "public"
+	visibilityOrg    = "org"
+	visibilityUsers  = "users"
+)
+
+var validVisibilities = map[string]bool{
+	visibilityPublic: true,
+	visibilityOrg:    true,
+	visibilityUsers:  true,
+}
+
+const maxAllowedViewers = 100
+
+type watchVisibilityDecision int
+
+const (
+	watchVisibilityAllowed watchVisibilityDecision = iota
+	watchVisibilitySignIn
+	watchVisibilityDenied
+)
+
+// watchViewerID identifies a signed-in viewer from either the SPA's bearer
+// token or the refresh-token cookie that a top-level navigation carries.
+// The cookie outlives logout and password resets, so it only counts while
+// its refresh_tokens row is unrevoked and unexpired.
+func (h *Handler) watchViewerID(r *http.Request) string {
+	if userID := h.optionalUserID(r); userID != "" {
+		return userID
+	}
+	cookie, err := r.Cookie("refresh_token")
+	if err != nil {
+		return ""
+	}
+	claims, err := auth.ValidateToken(h.hmacSecret, cookie.Value)
+	if err != nil || claims.TokenType != "refresh" || claims.TokenID == "" {
+		return ""
+	}
+	var active bool
+	err = h.db.QueryRow(r.Context(),
+		`SELECT EXISTS (SELECT 1 FROM refresh_tokens
+		   WHERE token_id = $1 AND user_id = $2 AND revoked = false AND expires_at > now())`,
+		claims.TokenID, claims.UserID,
+	).Scan(&active)
+	if err != nil {
+		slog.Error("visibility: failed to check refresh token", "error", err)
+		return ""
+	}
+	if !active {
+		return ""
+	}
+	return claims.UserID
+}
+
+// decideVisibility applies a video's effective visibility (its own, or its
+// folder's when unset) to the current viewer. The owner can always watch;
+// "org" admits members of the video's organization and "users" admits the
+// named email addresses. Lookup errors deny rather than fall open.
+func (h *Handler) decideVisibility(r *http.Request, shareToken, visibility string) watchVisibilityDecision {
+	if visibility == "" || visibility == visibilityPublic {
+		return watchVisibilityAllowed
+	}
+
+	viewerID := h.watchViewerID(r)
+	if viewerID == "" {
+		return watchVisibilitySignIn
+	}
+
+	var allowed bool
+	err := h.db.QueryRow(r.Context(),
+		`SELECT EXISTS (
+		   SELECT 1 FROM videos v
+		   JOIN users u ON u.id = $2
+		   LEFT JOIN folders f ON f.id = v.folder_id
+		   WHERE v.share_token = $1 AND (
+		     v.user_id = u.id
+		     OR (COALESCE(v.visibility, f.visibility, 'public') = 'org'
+		         AND EXISTS (SELECT 1 FROM organization_members om WHERE om.organization_id = v.organization_id AND om.user_id = u.id))
+		     OR (v.visibility = 'users'
+		         AND EXISTS (SELECT 1 FROM video_allowed_viewers a WHERE a.video_id = v.id AND a.email = lower(u.email)))
+		     OR (v.visibility IS NULL AND f.visibility = 'users'
+		         AND EXISTS (SELECT 1 FROM folder_allowed_viewers a WHERE a.folder_id = f.id AND a.email = lower(u.email)))
+		   )
+		 )`,
+		shareToken, viewerID,
+	).Scan(&allowed)
+	if err != nil {
+		slog.Error("visibility: failed to check viewer access", "share_token", shareToken, "error", err)
+		return watchVisibilityDenied
+	}
+	if !allowed {
+		return watchVisibilityDenied
+	}
+	return watchVisibilityAllowed
+}
+
+// enforceVisibility is the JSON-surface counterpart of enforceWatchAccess for
+// restricted videos. It writes the error response and returns false when the
+// viewer may not watch.
+func (h *Handler) enforceVisibility(w http.ResponseWriter, r *http.Request, shareToken, visibility string) bool {
+	switch h.decideVisibility(r, shareToken, visibility) {
+	case watchVisibilitySignIn:
+		httputil.WriteError(w, http.StatusUnauthorized, "sign in required")
+		return false
+	case watchVisibilityDenied:
+		httputil.WriteError(w, http.StatusForbidden, "you do not have access to this video")
+		return false
+	}
+	return true
+}
+
+// watchSignInURL sends the viewer through their workspace's SSO when one is
+// configured and to the regular login page otherwise. Both return to
+// returnTo once a session exists.
+func (h *Handler) watchSignInURL(ctx context.Context, orgID *string, returnTo string) string {
+	if orgID != nil {
+		var hasSSO bool
+		err := h.db.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM organization_sso_configs WHERE organization_id = $1)`,
+			*orgID,
+		).Scan(&hasSSO)
+		if err == nil && hasSSO {
+			return h.baseURL + "/api/auth/sso/org?org=" + url.QueryEscape(*orgID) + "&redirect=" + url.QueryEscape(returnTo)
+		}
+	}
+	return h.baseURL + "/login?redirect=" + url.QueryEscape(returnTo)
+}
+
+type restrictedPageData struct {
+	Nonce    string
+	Heading  string
+	Message  string
+	LinkURL  string
+	LinkText string
+	NewTab   bool
+	// FormAction, when set, replaces the link with a button that POSTs
+	// FormToken back to it.
+	FormAction string
+	FormToken  string
+}
+
+var restrictedPageTemplate = template.Must(template.New("restricted").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.Heading}} — SendRec</title>
+    <style nonce="{{.Nonce}}">
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            background: #0a1628;
+            color: #ffffff;
+            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+            min-height: 100vh;
+            display: flex;
+            align-items: center;
+            justify-content: center;
+        }
+        .container { text-align: center; padding: 2rem; }
+        h1 { font-size: 1.5rem; margin-bottom: 0.75rem; }
+        p { color: #94a3b8; margin-bottom: 1.5rem; }
+        a, button {
+            display: inline-block;
+            border: none;
+            cursor: pointer;
+            font-size: 1rem;
+            background: #00b67a;
+            color: #fff;
+            padding: 0.625rem 1.5rem;
+            border-radius: 8px;
+            text-decoration: none;
+            font-weight: 600;
+        }
+        a:hover, button:hover { opacity: 0.9; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{.Heading}}</h1>
+        <p>{{.Message}}</p>
+        {{if .FormAction}}<form method="post" action="{{.FormAction}}">
+            <input type="hidden" name="token" value="{{.FormToken}}">
+            <button type="submit">{{.LinkText}}</button>
+        </form>{{else}}<a href="{{.LinkURL}}"{{if .NewTab}} target="_blank" rel="noopener"{{end}}>{{.LinkText}}</a>{{end}}
+    </div>
+</body>
+</html>`))
+
+func renderRestrictedPage(w http.ResponseWriter, status int, data restrictedPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := restrictedPageTemplate.Execute(w, data); err != nil {
+		slog.Error("watch-page: failed to render restricted page", "error", err)
+	}
+}
+
+func renderAccessDeniedPage(w http.ResponseWriter, nonce string) {
+	renderRestrictedPage(w, http.StatusForbidden, restrictedPageData{
+		Nonce:    nonce,
+		Heading:  "You don't have access to this video",
+		Message:  "Ask the video owner to share it with your account.",
+		LinkURL:  "https://sendrec.eu",
+		LinkText: "Go to SendRec",
+	})
+}
+
+// normalizeAllowedEmails lowercases, trims and de-duplicates the named-viewer
+// list. It returns an error message when an entry is not a plausible address.
+func normalizeAllowedEmails(emails []string) ([]string, string) {
+	seen := make(map[string]bool, len(emails))
+	result := make([]string, 0, len(emails))
+	for _, e := range emails {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" {
+			continue
+		}
+		if len(e) > 320 || !strings.Contains(e, "@") {
+			return nil, "invalid email: " + e
+		}
+		if seen[e] {
+			continue
+		}
+		seen[e] = true
+		result = append(result, e)
+	}
+	if len(result) > maxAllowedViewers {
+		return nil, "too many allowed viewers"
+	}
+	sort.Strings(result)
+	return result, ""
+}
+
+type setVisibilityRequest struct {
+	Visibility    *string  `json:"visibility"`
+	AllowedEmails []string `json:"allowedEmails"`
+}
+
+type videoVisibilityResponse struct {
+	Visibility          *string  `json:"visibility"`
+	EffectiveVisibility string   `json:"effectiveVisibility"`
+	AllowedEmails       []string `json:"allowedEmails"`
+}
+
+type folderVisibilityResponse struct {
+	Visibility    string   `json:"visibility"`
+	AllowedEmails []string `json:"allowedEmails"`
+}
+
+// validateVisibilityRequest checks the mode and named-viewer list. A nil mode
+// is only meaningful for videos, where it means "inherit from the folder".
+func validateVisibilityRequest(ctx context.Context, req *setVisibilityRequest, allowInherit bool) string {
+	if req.Visibility == nil {
+		if !allowInherit {
+			return "visibility is required"
+		}
+		req.AllowedEmails = nil
+		return ""
+	}
+	if !validVisibilities[*req.Visibility] {
+		return "invalid visibility"
+	}
+	if *req.Visibility == visibilityOrg && orgScope(ctx) == nil {
+		return "organization visibility is only available in a workspace"
+	}
+	if *req.Visibility != visibilityUsers {
+		req.AllowedEmails = nil
+		return ""
+	}
+	emails, msg := normalizeAllowedEmails(req.AllowedEmails)
+	if msg != "" {
+		return msg
+	}
+	if len(emails) == 0 {
+		return "at least one allowed email is required"
+	}
+	req.AllowedEmails = emails
+	return ""
+}
+
+func (h *Handler) queryStrings(ctx context.Context, query, id string) ([]string, error) {
+	rows, err := h.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := make([]string, 0)
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
+
+func (h *Handler) GetVideoVisibility(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	where, args := orgVideoFilter(r.Context(), videoID, nil, "AND status != 'deleted'")
+	var resp videoVisibilityResponse
+	err := h.db.QueryRow(r.Context(),
+		`SELECT visibility, COALESCE(visibility, (SELECT f.visibility FROM folders f WHERE f.id = videos.folder_id), 'public')
+		 FROM videos WHERE `+where, args...,
+	).Scan(&resp.Visibility, &resp.EffectiveVisibility)
+	if err != nil {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	emails, err := h.queryStrings(r.Context(),
+		`SELECT email FROM video_allowed_viewers WHERE video_id = $1 ORDER BY email`, videoID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch allowed viewers")
+		return
+	}
+	resp.AllowedEmails = emails
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) SetVideoVisibility(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "id")
+
+	var req setVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := validateVisibilityRequest(r.Context(), &req, true); msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	where, args := orgVideoFilter(r.Context(), videoID, []any{req.Visibility}, "AND status != 'deleted'")
+	err := database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(r.Context(),
+			`UPDATE videos SET visibility = $1 WHERE `+where, args...,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+		return replaceAllowedViewers(r.Context(), tx, "video_allowed_viewers", "video_id", videoID, req.AllowedEmails)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		httputil.WriteError(w, http.StatusNotFound, "video not found")
+		return
+	}
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not update visibility")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// replaceAllowedViewers swaps the allow-list for a video or folder. It runs in
+// the caller's transaction so a failed insert cannot leave a partial list.
+func replaceAllowedViewers(ctx context.Context, tx pgx.Tx, table, column, id string, emails []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE `+column+` = $1`, id); err != nil {
+		return err
+	}
+	for _, email := range emails {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO `+table+` (`+column+`, email) VALUES ($1, $2)`,
+			id, email,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// folderScopeFilter mirrors the ownership check the other folder handlers use:
+// by organization in a workspace, by user otherwise.
+func folderScopeFilter(ctx context.Context, folderID string, baseArgs []any) (string, []any) {
+	n := len(baseArgs)
+	if orgID := auth.OrgIDFromContext(ctx); orgID != "" {
+		return fmt.Sprintf("id = $%d AND organization_id = $%d", n+1, n+2), append(baseArgs, folderID, orgID)
+	}
+	return fmt.Sprintf("id = $%d AND user_id = $%d", n+1, n+2), append(baseArgs, folderID, auth.UserIDFromContext(ctx))
+}
+
+func (h *Handler) GetFolderVisibility(w http.ResponseWriter, r *http.Request) {
+	folderID := chi.URLParam(r, "id")
+
+	where, args := folderScopeFilter(r.Context(), folderID, nil)
+	var resp folderVisibilityResponse
+	err := h.db.QueryRow(r.Context(),
+		`SELECT visibility FROM folders WHERE `+where, args...,
+	).Scan(&resp.Visibility)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			httputil.WriteError(w, http.StatusNotFound, "folder not found")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to fetch folder")
+		return
+	}
+
+	emails, err := h.queryStrings(r.Context(),
+		`SELECT email FROM folder_allowed_viewers WHERE folder_id = $1 ORDER BY email`, folderID)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "could not fetch allowed viewers")
+		return
+	}
+	resp.AllowedEmails = emails
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) SetFolderVisibility(w http.ResponseWriter, r *http.Request) {
+	folderID := chi.URLParam(r, "id")
+
+	var req setVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := validateVisibilityRequest(r.Context(), &req, false); msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	where, args := folderScopeFilter(r.Context(), folderID, []any{*req.Visibility})
+	err := database.WithTx(r.Context(), h.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(r.Context(),
+			`UPDATE folders SET visibility = $1 WHERE `+where, args...,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+		return replaceAllowedViewers(r.Context(), tx, "folder_allowed_viewers", "folder_id", folderID, req.AllowedEmails)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		httputil.WriteError(w, http.StatusNotFound, "folder not found")
+		return
+	}
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "failed to update folder")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
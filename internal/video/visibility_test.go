@@ -0,0 +1,446 @@
+package video
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/sendrec/sendrec/internal/auth"
+)
+
+func restrictedWatchPageRow(orgID *string, visibility string) *pgxmock.Rows {
+	return pgxmock.NewRows(watchPageColumns).AddRow(
+		"vid-1", "Restricted Video", "recordings/u1/abc.webm", "Bob", time.Now(), (*time.Time)(nil),
+		(*string)(nil), (*string)(nil), "disabled",
+		(*string)(nil), (*string)(nil), "none",
+		"owner-user-id", "owner@example.com", (*string)(nil), "video/webm",
+		(*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil),
+		(*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil),
+		(*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil),
+		true, (*string)(nil), (*string)(nil),
+		false,
+		(*string)(nil), (*string)(nil), "none",
+		0,
+		"free",
+		"ready",
+		orgID,
+		visibility,
+		false,
+		"[]",
+		"[]",
+	)
+}
+
+func refreshCookie(t *testing.T, userID string) *http.Cookie {
+	t.Helper()
+	token, err := auth.GenerateRefreshToken(testHMACSecret, userID, "token-id")
+	if err != nil {
+		t.Fatalf("failed to generate refresh token: %v", err)
+	}
+	return &http.Cookie{Name: "refresh_token", Value: token}
+}
+
+func expectActiveRefreshToken(mock pgxmock.PgxPoolIface, userID string, active bool) {
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM refresh_tokens`).
+		WithArgs("token-id", userID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(active))
+}
+
+func TestWatchPage_OrgVisibility_SignedOut_RedirectsToOrgSSO(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	orgID := "org-1"
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("restricted12").
+		WillReturnRows(restrictedWatchPageRow(&orgID, "org"))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM organization_sso_configs WHERE organization_id = \$1\)`).
+		WithArgs(orgID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	rec := serveWatchPage(handler, watchPageRequest("restricted12"))
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected 302, got %d: %s", rec.Code, rec.Body.String())
+	}
+	want := testBaseURL + "/api/auth/sso/org?org=org-1&redirect=%2Fwatch%2Frestricted12"
+	if got := rec.Header().Get("Location"); got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestWatchPage_UsersVisibility_SignedOut_WithoutSSO_RedirectsToLogin(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("restricted12").
+		WillReturnRows(restrictedWatchPageRow(nil, "users"))
+
+	rec := serveWatchPage(handler, watchPageRequest("restricted12"))
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected 302, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Location"); got != testBaseURL+"/login?redirect=%2Fwatch%2Frestricted12" {
+		t.Errorf("unexpected Location %q", got)
+	}
+}
+
+func TestWatchPage_UsersVisibility_RevokedRefreshCookie_RedirectsToLogin(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("restricted12").
+		WillReturnRows(restrictedWatchPageRow(nil, "users"))
+	expectActiveRefreshToken(mock, "viewer-user-id", false)
+
+	req := watchPageRequest("restricted12")
+	req.AddCookie(refreshCookie(t, "viewer-user-id"))
+	rec := serveWatchPage(handler, req)
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected 302, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestWatchPage_UsersVisibility_NotAllowed_Returns403(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("restricted12").
+		WillReturnRows(restrictedWatchPageRow(nil, "users"))
+	expectActiveRefreshToken(mock, "viewer-user-id", true)
+	mock.ExpectQuery(`SELECT EXISTS \(\s*SELECT 1 FROM videos v`).
+		WithArgs("restricted12", "viewer-user-id").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	req := watchPageRequest("restricted12")
+	req.AddCookie(refreshCookie(t, "viewer-user-id"))
+	rec := serveWatchPage(handler, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "recordings/u1/abc.webm") {
+		t.Error("restricted page must not reference the video file")
+	}
+}
+
+func TestWatchPage_UsersVisibility_AllowedViewer_Renders(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{downloadURL: "https://s3.example.com/video"}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("restricted12").
+		WillReturnRows(restrictedWatchPageRow(nil, "users"))
+	expectActiveRefreshToken(mock, "viewer-user-id", true)
+	mock.ExpectQuery(`SELECT EXISTS \(\s*SELECT 1 FROM videos v`).
+		WithArgs("restricted12", "viewer-user-id").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	expectViewRecording(mock, "vid-1")
+
+	req := watchPageRequest("restricted12")
+	req.AddCookie(refreshCookie(t, "viewer-user-id"))
+	rec := serveWatchPage(handler, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	waitAndCheckExpectations(t, mock)
+}
+
+func TestEmbedPage_OrgVisibility_SignedOut_LinksToWatchPage(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.file_key`).
+		WithArgs("restricted12").
+		WillReturnRows(pgxmock.NewRows(embedPageColumns).AddRow(
+			"vid-1", "Restricted Video", "recordings/u1/abc.webm", "Bob", time.Now(), (*time.Time)(nil),
+			(*string)(nil), (*string)(nil), "video/webm",
+			"owner-user-id", "owner@example.com", (*string)(nil),
+			(*string)(nil), (*string)(nil), (*string)(nil),
+			false,
+			(*string)(nil),
+			"ready",
+			"org",
+			(*string)(nil),
+			[]string{},
+			"[]",
+			"[]",
+		))
+
+	rec := serveEmbedPage(handler, embedPageRequest("restricted12"))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, testBaseURL+"/watch/restricted12") || !strings.Contains(body, `target="_blank"`) {
+		t.Errorf("expected a new-tab link to the watch page, got: %s", body)
+	}
+}
+
+func TestWatchAPI_OrgVisibility_SignedOut_Returns401(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{downloadURL: "https://s3.example.com/video"}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.duration, v.file_key`).
+		WithArgs("restricted12").
+		WillReturnRows(watchAPIRowWithVisibility("video-001", nil, false, nil, "org"))
+
+	rec := serveWatchAPI(handler, httptest.NewRequest(http.MethodGet, "/api/watch/restricted12", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "s3.example.com") {
+		t.Error("presigned video URL leaked to a signed-out viewer")
+	}
+}
+
+func TestWatchAPI_OrgVisibility_NonMember_Returns403(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{downloadURL: "https://s3.example.com/video"}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+
+	mock.ExpectQuery(`SELECT v.id, v.title, v.duration, v.file_key`).
+		WithArgs("restricted12").
+		WillReturnRows(watchAPIRowWithVisibility("video-001", nil, false, nil, "org"))
+	mock.ExpectQuery(`SELECT EXISTS \(\s*SELECT 1 FROM videos v`).
+		WithArgs("restricted12", testUserID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	token, err := auth.GenerateAccessToken(testHMACSecret, testUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/watch/restricted12", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := serveWatchAPI(handler, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNormalizeAllowedEmails(t *testing.T) {
+	got, msg := normalizeAllowedEmails([]string{" Bob@Example.com ", "alice@example.com", "bob@example.com", ""})
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if strings.Join(got, ",") != "alice@example.com,bob@example.com" {
+		t.Errorf("got %v", got)
+	}
+
+	if _, msg := normalizeAllowedEmails([]string{"not-an-email"}); msg == "" {
+		t.Error("expected an error for an invalid address")
+	}
+
+	tooMany := make([]string, maxAllowedViewers+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("a", i+1) + "@example.com"
+	}
+	if _, msg := normalizeAllowedEmails(tooMany); msg == "" {
+		t.Error("expected an error when exceeding the allowed viewer limit")
+	}
+}
+
+func TestSetVideoVisibility_Users_ReplacesAllowedViewers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-001"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE videos SET visibility = \$1 WHERE id = \$2 AND user_id = \$3`).
+		WithArgs(pgxmock.AnyArg(), videoID, testUserID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`DELETE FROM video_allowed_viewers WHERE video_id = \$1`).
+		WithArgs(videoID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	mock.ExpectExec(`INSERT INTO video_allowed_viewers \(video_id, email\) VALUES \(\$1, \$2\)`).
+		WithArgs(videoID, "alice@example.com").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(`INSERT INTO video_allowed_viewers \(video_id, email\) VALUES \(\$1, \$2\)`).
+		WithArgs(videoID, "bob@example.com").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/visibility", handler.SetVideoVisibility)
+
+	body, _ := json.Marshal(map[string]any{"visibility": "users", "allowedEmails": []string{"Bob@example.com", "alice@example.com"}})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/"+videoID+"/visibility", body))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetVideoVisibility_RollsBackWhenAllowListFails(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+	videoID := "video-001"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE videos SET visibility = \$1`).
+		WithArgs(pgxmock.AnyArg(), videoID, testUserID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`DELETE FROM video_allowed_viewers`).
+		WithArgs(videoID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec(`INSERT INTO video_allowed_viewers`).
+		WithArgs(videoID, "alice@example.com").
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	r := chi.NewRouter()
+	r.With(newAuthMiddleware()).Put("/api/videos/{id}/visibility", handler.SetVideoVisibility)
+
+	body, _ := json.Marshal(map[string]any{"visibility": "users", "allowedEmails": []string{"alice@example.com"}})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/"+videoID+"/visibility", body))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSetVideoVisibility_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown mode", `{"visibility":"secret"}`},
+		{"users without emails", `{"visibility":"users","allowedEmails":[]}`},
+		{"org outside a workspace", `{"visibility":"org"}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+			r := chi.NewRouter()
+			r.With(newAuthMiddleware()).Put("/api/videos/{id}/visibility", handler.SetVideoVisibility)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, authenticatedRequest(t, http.MethodPut, "/api/videos/video-001/visibility", []byte(tc.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSetFolderVisibility_OrgScoped(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{}, testBaseURL, 0, 0, 0, 0, testJWTSecret, false)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE folders SET visibility = \$1 WHERE id = \$2 AND organization_id = \$3`).
+		WithArgs("org", "folder-1", testOrgID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`DELETE FROM folder_allowed_viewers WHERE folder_id = \$1`).
+		WithArgs("folder-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectCommit()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/folders/folder-1/visibility", bytes.NewReader([]byte(`{"visibility":"org"}`)))
+	ctx := auth.ContextWithUserID(req.Context(), testUserID)
+	ctx = auth.ContextWithOrg(ctx, testOrgID, "member")
+	req = req.WithContext(ctx)
+
+	r := chi.NewRouter()
+	r.Put("/api/folders/{id}/visibility", handler.SetFolderVisibility)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
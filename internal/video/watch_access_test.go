@@ -24,11 +24,15 @@ var watchAPIColumns = []string{
 	"ob_company_name", "ob_logo_key", "ob_color_background", "ob_color_surface", "ob_color_text", "ob_color_accent", "ob_footer_text", "ob_custom_css",
 	"vb_company_name", "vb_logo_key", "vb_color_background", "vb_color_surface", "vb_color_text", "vb_color_accent", "vb_footer_text",
 	"cta_text", "cta_url", "summary", "chapters", "summary_status",
-	"document", "document_status", "organization_id", "email_gate_enabled",
+	"document", "document_status", "organization_id", "email_gate_enabled", "visibility",
 }
 
-// watchAPIRow builds a Watch row with the gate flags under test.
+// watchAPIRow builds a public Watch row with the gate flags under test.
 func watchAPIRow(videoID string, sharePassword *string, emailGateEnabled bool, shareExpiresAt *time.Time) *pgxmock.Rows {
+	return watchAPIRowWithVisibility(videoID, sharePassword, emailGateEnabled, shareExpiresAt, "public")
+}
+
+func watchAPIRowWithVisibility(videoID string, sharePassword *string, emailGateEnabled bool, shareExpiresAt *time.Time, visibility string) *pgxmock.Rows {
 	return pgxmock.NewRows(watchAPIColumns).AddRow(
 		videoID, "Demo Recording", 180, "recordings/user-1/abc.webm", "Alex Neamtu",
 		time.Date(2026, 2, 5, 14, 0, 0, 0, time.UTC), shareExpiresAt,
@@ -38,7 +42,7 @@ func watchAPIRow(videoID string, sharePassword *string, emailGateEnabled bool, s
 		(*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil),
 		(*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil),
 		(*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), "none",
-		(*string)(nil), "none", (*string)(nil), emailGateEnabled,
+		(*string)(nil), "none", (*string)(nil), emailGateEnabled, visibility,
 	)
 }
 
@@ -54,10 +58,16 @@ func gateCookie(t *testing.T, shareToken, email string) *http.Cookie {
 	t.Helper()
 	return &http.Cookie{
 		Name:  emailGateCookieName(shareToken),
-		Value: signEmailGateCookie(testHMACSecret, shareToken, email),
+		Value: signEmailGateCookie(testHMACSecret, shareToken, email, false),
 	}
 }
 
+func expectEmailGateCheck(mock pgxmock.PgxPoolIface, shareToken string, verified bool, domain string, allowed bool) {
+	mock.ExpectQuery(`SELECT \(NOT v\.email_gate_verified OR \$2\)`).
+		WithArgs(shareToken, verified, domain).
+		WillReturnRows(pgxmock.NewRows([]string{"allowed"}).AddRow(allowed))
+}
+
 func TestWatchAPI_EmailGateEnabled_NoCookie_IsDenied(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	if err != nil {
@@ -97,8 +107,9 @@ func TestWatchAPI_EmailGateEnabled_ValidCookie_IsAllowed(t *testing.T) {
 	mock.ExpectQuery(`SELECT v.id, v.title, v.duration, v.file_key`).
 		WithArgs(shareToken).
 		WillReturnRows(watchAPIRow("video-001", nil, true, &expiresAt))
+	expectEmailGateCheck(mock, shareToken, false, "example.com", true)
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/watch/"+shareToken, nil)
@@ -111,6 +122,35 @@ func TestWatchAPI_EmailGateEnabled_ValidCookie_IsAllowed(t *testing.T) {
 	time.Sleep(50 * time.Millisecond)
 }
 
+func TestWatchAPI_EmailGate_StaleCookie_IsDenied(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	handler := NewHandler(mock, &mockStorage{downloadURL: "https://s3.example.com/video"}, testBaseURL, 0, 0, 0, 0, testHMACSecret, false)
+	shareToken := "abc123defghi"
+	expiresAt := time.Now().Add(7 * 24 * time.Hour)
+
+	// The owner has since turned on verification or dropped the domain.
+	mock.ExpectQuery(`SELECT v.id, v.title, v.duration, v.file_key`).
+		WithArgs(shareToken).
+		WillReturnRows(watchAPIRow("video-001", nil, true, &expiresAt))
+	expectEmailGateCheck(mock, shareToken, false, "example.com", false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/watch/"+shareToken, nil)
+	req.AddCookie(gateCookie(t, shareToken, "viewer@example.com"))
+	rec := serveWatchAPI(handler, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a cookie the gate no longer accepts, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
 func TestWatchAPI_EmailGateDisabled_IsAllowed(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	if err != nil {
@@ -126,7 +166,7 @@ func TestWatchAPI_EmailGateDisabled_IsAllowed(t *testing.T) {
 		WithArgs(shareToken).
 		WillReturnRows(watchAPIRow("video-001", nil, false, &expiresAt))
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	rec := serveWatchAPI(handler, httptest.NewRequest(http.MethodGet, "/api/watch/"+shareToken, nil))
@@ -148,10 +188,10 @@ func TestWatchDownload_EmailGateEnabled_NoCookie_IsDenied(t *testing.T) {
 	shareToken := "abc123defghi"
 	expiresAt := time.Now().Add(7 * 24 * time.Hour)
 
-	mock.ExpectQuery(`SELECT title, file_key, share_expires_at, share_password, content_type, download_enabled`).
+	mock.ExpectQuery(`SELECT v.title, v.file_key, v.share_expires_at, v.share_password, v.content_type, v.download_enabled, v.email_gate_enabled`).
 		WithArgs(shareToken).
-		WillReturnRows(pgxmock.NewRows([]string{"title", "file_key", "share_expires_at", "share_password", "content_type", "download_enabled", "email_gate_enabled"}).
-			AddRow("Demo Recording", "recordings/user-1/abc.webm", &expiresAt, (*string)(nil), "video/webm", true, true))
+		WillReturnRows(pgxmock.NewRows([]string{"title", "file_key", "share_expires_at", "share_password", "content_type", "download_enabled", "email_gate_enabled", "visibility"}).
+			AddRow("Demo Recording", "recordings/user-1/abc.webm", &expiresAt, (*string)(nil), "video/webm", true, true, "public"))
 
 	r := chi.NewRouter()
 	r.Get("/api/watch/{shareToken}/download", handler.WatchDownload)
@@ -181,8 +221,8 @@ func TestWatchThumbnail_PasswordProtected_NoCookie_IsDenied(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT v.thumbnail_key, v.share_expires_at`).
 		WithArgs(shareToken).
-		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled"}).
-			AddRow(&thumbKey, &expiresAt, &hashed, false))
+		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled", "visibility"}).
+			AddRow(&thumbKey, &expiresAt, &hashed, false, "public"))
 
 	rec := serveWatchThumbnail(handler, httptest.NewRequest(http.MethodGet, "/api/watch/"+shareToken+"/thumbnail", nil))
 
@@ -205,8 +245,8 @@ func TestWatchThumbnail_EmailGateEnabled_NoCookie_IsDenied(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT v.thumbnail_key, v.share_expires_at`).
 		WithArgs(shareToken).
-		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled"}).
-			AddRow(&thumbKey, &expiresAt, (*string)(nil), true))
+		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled", "visibility"}).
+			AddRow(&thumbKey, &expiresAt, (*string)(nil), true, "public"))
 
 	rec := serveWatchThumbnail(handler, httptest.NewRequest(http.MethodGet, "/api/watch/"+shareToken+"/thumbnail", nil))
 
@@ -230,8 +270,8 @@ func TestOEmbed_PasswordProtected_IsDenied(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT v.title, v.duration, u.name, v.created_at, v.share_expires_at, v.thumbnail_key`).
 		WithArgs(shareToken).
-		WillReturnRows(pgxmock.NewRows([]string{"title", "duration", "name", "created_at", "share_expires_at", "thumbnail_key", "share_password", "email_gate_enabled"}).
-			AddRow("Secret Recording", 180, "Alex Neamtu", createdAt, &expiresAt, stringPtr("thumbnails/user-1/abc.jpg"), &hashed, false))
+		WillReturnRows(pgxmock.NewRows([]string{"title", "duration", "name", "created_at", "share_expires_at", "thumbnail_key", "share_password", "email_gate_enabled", "visibility"}).
+			AddRow("Secret Recording", 180, "Alex Neamtu", createdAt, &expiresAt, stringPtr("thumbnails/user-1/abc.jpg"), &hashed, false, "public"))
 
 	r := chi.NewRouter()
 	r.Get("/api/videos/{shareToken}/oembed", handler.OEmbed)
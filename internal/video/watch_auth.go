@@ -13,6 +13,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/sendrec/sendrec/internal/httputil"
+	"github.com/sendrec/sendrec/internal/webhook"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -92,7 +93,7 @@ func (h *Handler) enforceWatchAccess(w http.ResponseWriter, r *http.Request, sha
 	}
 
 	if emailGateEnabled {
-		if _, ok := hasValidEmailGateCookie(r, h.hmacSecret, shareToken); !ok {
+		if !h.passesVideoEmailGate(r, shareToken) {
 			httputil.WriteError(w, http.StatusForbidden, "email required")
 			return false
 		}
@@ -154,24 +155,39 @@ func emailGateCookieName(shareToken string) string {
 	return "eg_" + prefix
 }
 
-func signEmailGateCookie(hmacSecret, shareToken, email string) string {
+// signEmailGateCookie signs the email a viewer gave the gate. verified
+// records whether they proved it with a code or magic link, so a gate that
+// later turns verification on can tell the difference.
+func signEmailGateCookie(hmacSecret, shareToken, email string, verified bool) string {
+	mode := "self"
+	if verified {
+		mode = "verified"
+	}
 	mac := hmac.New(sha256.New, deriveCookieKey(hmacSecret, "email-gate-cookie"))
-	mac.Write([]byte(shareToken + "|" + email))
+	mac.Write([]byte(shareToken + "|" + email + "|" + mode))
 	sig := hex.EncodeToString(mac.Sum(nil))
-	return email + "|" + sig
+	return email + "|" + mode + "|" + sig
 }
 
-func verifyEmailGateCookie(hmacSecret, shareToken, cookieValue string) (string, bool) {
-	parts := strings.SplitN(cookieValue, "|", 2)
-	if len(parts) != 2 {
-		return "", false
+func verifyEmailGateCookie(hmacSecret, shareToken, cookieValue string) (email string, verified bool, ok bool) {
+	i := strings.LastIndex(cookieValue, "|")
+	if i < 0 {
+		return "", false, false
+	}
+	j := strings.LastIndex(cookieValue[:i], "|")
+	if j < 0 {
+		return "", false, false
+	}
+	email, mode := cookieValue[:j], cookieValue[j+1:i]
+	if mode != "self" && mode != "verified" {
+		return "", false, false
 	}
-	email := parts[0]
-	expected := signEmailGateCookie(hmacSecret, shareToken, email)
+	verified = mode == "verified"
+	expected := signEmailGateCookie(hmacSecret, shareToken, email, verified)
 	if !hmac.Equal([]byte(expected), []byte(cookieValue)) {
-		return "", false
+		return "", false, false
 	}
-	return email, true
+	return email, verified, true
 }
 
 func setEmailGateCookie(w http.ResponseWriter, shareToken, value string, secure bool) {
@@ -187,15 +203,44 @@ func setEmailGateCookie(w http.ResponseWriter, shareToken, value string, secure
 }
 
 func hasValidEmailGateCookie(r *http.Request, hmacSecret, shareToken string) (string, bool) {
+	email, _, ok := emailGateCookieClaims(r, hmacSecret, shareToken)
+	return email, ok
+}
+
+func emailGateCookieClaims(r *http.Request, hmacSecret, shareToken string) (string, bool, bool) {
 	cookie, err := r.Cookie(emailGateCookieName(shareToken))
 	if err != nil {
-		return "", false
+		return "", false, false
 	}
 	return verifyEmailGateCookie(hmacSecret, shareToken, cookie.Value)
 }
 
+// passesVideoEmailGate checks a viewer's email-gate cookie against the
+// video's current gate: a cookie issued before verification was turned on,
+// or for a domain that has since been removed, no longer admits them.
+func (h *Handler) passesVideoEmailGate(r *http.Request, shareToken string) bool {
+	email, verified, ok := emailGateCookieClaims(r, h.hmacSecret, shareToken)
+	if !ok {
+		return false
+	}
+	var allowed bool
+	err := h.db.QueryRow(r.Context(),
+		`SELECT (NOT v.email_gate_verified OR $2)
+		        AND (NOT EXISTS (SELECT 1 FROM video_email_gate_domains d WHERE d.video_id = v.id)
+		             OR EXISTS (SELECT 1 FROM video_email_gate_domains d WHERE d.video_id = v.id AND d.domain = $3))
+		 FROM videos v WHERE v.share_token = $1`,
+		shareToken, verified, emailDomain(email),
+	).Scan(&allowed)
+	if err != nil {
+		slog.Error("email-gate: failed to check gate cookie", "share_token", shareToken, "error", err)
+		return false
+	}
+	return allowed
+}
+
 type identifyViewerRequest struct {
-	Email string `json:"email"`
+	Email  string         `json:"email"`
+	Fields map[string]any `json:"fields"`
 }
 
 func (h *Handler) IdentifyViewer(w http.ResponseWriter, r *http.Request) {
@@ -213,33 +258,85 @@ func (h *Handler) IdentifyViewer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var videoID string
+	var videoID, title, fieldsJSON string
+	var verified, domainAllowed bool
 	err := h.db.QueryRow(r.Context(),
-		`SELECT v.id FROM videos v WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
-		shareToken,
-	).Scan(&videoID)
+		`SELECT v.id, v.title, v.email_gate_verified,
+		        NOT EXISTS (SELECT 1 FROM video_email_gate_domains d WHERE d.video_id = v.id)
+		        OR EXISTS (SELECT 1 FROM video_email_gate_domains d WHERE d.video_id = v.id AND d.domain = $2),
+		        v.email_gate_fields
+		 FROM videos v WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
+		shareToken, emailDomain(req.Email),
+	).Scan(&videoID, &title, &verified, &domainAllowed, &fieldsJSON)
 	if err != nil {
 		httputil.WriteError(w, http.StatusNotFound, "video not found")
 		return
 	}
 
-	ip := httputil.ClientIP(r)
-	hash := viewerHash(ip, r.UserAgent())
+	if !domainAllowed {
+		httputil.WriteError(w, http.StatusForbidden, "this email domain is not allowed for this video")
+		return
+	}
+
+	responses, msg := validateGateResponses(decodeGateFields(fieldsJSON), req.Fields)
+	if msg != "" {
+		httputil.WriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if verified {
+		h.startViewerVerification(w, r, videoID, title, shareToken, strings.ToLower(req.Email), responses)
+		return
+	}
+
+	h.recordViewerIdentityAsync(r, videoID, req.Email, responses)
+
+	sig := signEmailGateCookie(h.hmacSecret, shareToken, req.Email, false)
+	setEmailGateCookie(w, shareToken, sig, h.secureCookies)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// recordViewerIdentityAsync stores who passed the email gate, merging any
+// lead-form answers into earlier ones, and announces them with a
+// viewer.identified webhook.
+func (h *Handler) recordViewerIdentityAsync(r *http.Request, videoID, email string, responses map[string]any) {
+	hash, _ := h.viewerHashFor(r.Context(), r, h.analyticsPrivacy(r.Context(), videoID))
+	if responses == nil {
+		responses = map[string]any{}
+	}
+	responsesJSON, _ := json.Marshal(responses)
 
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 		if _, err := h.db.Exec(ctx,
-			`INSERT INTO video_viewers (video_id, email, viewer_hash) VALUES ($1, $2, $3)
-			 ON CONFLICT (video_id, email) DO NOTHING`,
-			videoID, req.Email, hash,
+			`INSERT INTO video_viewers (video_id, email, viewer_hash, form_responses) VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (video_id, email) DO UPDATE SET form_responses = video_viewers.form_responses || EXCLUDED.form_responses`,
+			videoID, email, hash, string(responsesJSON),
 		); err != nil {
 			slog.Error("watch-auth: failed to record viewer identity", "video_id", videoID, "error", err)
+			return
+		}
+		if h.webhookClient == nil {
+			return
+		}
+		var ownerID, title string
+		if err := h.db.QueryRow(ctx,
+			`SELECT user_id, title FROM videos WHERE id = $1`, videoID,
+		).Scan(&ownerID, &title); err != nil {
+			return
 		}
+		h.dispatchWebhook(ownerID, webhook.Event{
+			Name:      "viewer.identified",
+			Timestamp: time.Now().UTC(),
+			Data: map[string]any{
+				"videoId":    videoID,
+				"title":      title,
+				"email":      email,
+				"fields":     responses,
+				"viewerHash": hash,
+			},
+		})
 	}()
-
-	sig := signEmailGateCookie(h.hmacSecret, shareToken, req.Email)
-	setEmailGateCookie(w, shareToken, sig, h.secureCookies)
-
-	w.WriteHeader(http.StatusOK)
 }
@@ -93,6 +93,8 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
     <link rel="icon" type="image/png" sizes="32x32" href="data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAACAAAAAgCAYAAABzenr0AAAABGdBTUEAALGPC/xhBQAAACBjSFJNAAB6JgAAgIQAAPoAAACA6AAAdTAAAOpgAAA6mAAAF3CculE8AAAAeGVYSWZNTQAqAAAACAAEARoABQAAAAEAAAA+ARsABQAAAAEAAABGASgAAwAAAAEAAgAAh2kABAAAAAEAAABOAAAAAAAAAEgAAAABAAAASAAAAAEAA6ABAAMAAAABAAEAAKACAAQAAAABAAAAIKADAAQAAAABAAAAIAAAAACfCVbEAAAACXBIWXMAAAsTAAALEwEAmpwYAAAEa0lEQVRYCe1VW2icRRQ+Z+af/xK1kdQlvlhatWC7tamKoIilVrFSjdhoIqZtQBERn7RKXwQJvigUqy8iIQ9KU4Msrq0+eHlJUChiIWLTpFHrgxAkIiE2t+a/zMzxzCYb/t3ECyj4spNsZnbmzPm+850zJwCN0VCgocD/rAD+V/i3fFoqxHHc6ilPo68nR/f1LP4T3/+aQLF88nbjyaNkabew1AKIBqSYJKKSDOUb4w90zfwVkb8lsP2TwSKCuMuQ3miF6P+xvXu66nBb+cRhUOodkN4VNssA7cqJFICBAkrTc5jajonHDl6s3qmf/5TAztKJLZmvXrOCHhZSRSIIwC7MHZt45NBR52Rn+eQdqSeHgCACY4AjB4684p/sMhPh+0BJ8m1zCPd8s//QXD24+758o+6k+NFAWxqqIQjU42ApsmkKZn7BmT+7/cP3is48ldALSkbE4OSA3ceaKbL6EipZ8WiTFDCKbp2N4bnKxjp/1hDYderdqw2I91nWzXYpYaccnJCAygPZvOEqQnFgx8eDrUS4G1LNgXOCUGhp9ItKYdHzqA20KaNSFTjKKjade4Z7vXXwYc1mTN5TGAXFCjirip4HmGVjwsCItXZOePgBoS4KGUTgnDMQ6fTr8Y7Dx1cAfucgXoo17kOJV7Iqbvv6+ZkbCjxPrdisTjUEOkslOYZpFxkLSMTOfUCTDXKJPZN/VjeVB/bKQHD62UYwS8KJVY+8iEnMA1EKwglMTkUv9mlZkrwhr2tS8IMXb7QEN6JmAk52qxcFyVfy4O6+QNziao5/XX7cPOmW1SGF9zSnrAWI/VR+aAaWwnWfY40CRkOIEgJXxS63/LZnm8Xib1XH1dkibHJr6/LP4bHl9zvKAw9SGB7kgi0Ywr3uZTiC7jlCbL4af7TLVfGaUaOAyOQ8a7/AQbFyjoQozKb+zflbe4aHPY5ql0uTG6S5Dsj+zGQewih8Ajz/PkZmXiw9p4CyLGYt38r7yK8rKq5ucEq3nR74HP3wfuInxE0GUOtzYLInizYavQBzzaSil8nzjjjgivxkLjUtia1JqAtGqrPcAbnwHDizkDLFLDky3tHz9ipG3aJGAaelsNjvCrCSYa5yfo5tVnhnRlU6YlX4HTA4OHCHEfggLHwx0t09PdbRM4HW9EluPq4qBHFsxsSC9Gd1mDVfawnwUeH8T6etTk9hU1DhQDpjJIgkE0Epr3PgyPJLfp6QJNOCst6qRyHEMZskU64pkUuhUhsI1KvV8/Xm2hSsWBRLpRbrJwOciv3cVMDyx4W80nQYXPFe9gvZrIflHco75n9Oz0MYvkkpNzH2zqQMarr3fEf3l3m76nqNAu5gvKtrRqTBAUySF7gYL7Ajjd5ye2VlfzU67Rdpdnc9uLsrF22/TZZGXed0BMCTksC+fltfX5M7rx/rKpA3urN0PJoPrt3K+WwliZelsRdHO3rWPM38He6Em43ftEnHS8BpI5FpGYTXnB1pb7+ct2usGwo0FGgo4BT4A0kx06ZKzSjiAAAAAElFTkSuQmCC">
     <title>{{.Title}} — {{.Branding.CompanyName}}</title>
     <link rel="canonical" href="{{.BaseURL}}/watch/{{.ShareToken}}">
+    <link rel="alternate" type="application/json+oembed" href="{{.BaseURL}}/api/oembed?url={{.BaseURL}}/watch/{{.ShareToken}}&format=json" title="{{.Title}}">
+    <link rel="alternate" type="text/xml+oembed" href="{{.BaseURL}}/api/oembed?url={{.BaseURL}}/watch/{{.ShareToken}}&format=xml" title="{{.Title}}">
     <meta name="description" content="{{.Description}}">
     <meta property="og:title" content="{{.Title}}">
     <meta property="og:type" content="video.other">
@@ -152,6 +154,8 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
             --player-accent: var(--brand-accent, #00b67a);
         }
 ` + playerCSS + `
+` + timedCTACSS + `
+` + videoQuestionCSS + `
         .video-title {
             margin-top: 1rem;
             font-size: 24px;
@@ -300,6 +304,63 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
             white-space: pre-wrap;
             word-break: break-word;
         }
+        .comment.comment-reply {
+            margin-left: 1.5rem;
+            border-left: 2px solid var(--brand-accent);
+        }
+        .comment-resolved-badge {
+            background: #16a34a;
+            color: #fff;
+            font-size: 0.6875rem;
+            font-weight: 600;
+            padding: 0.125rem 0.375rem;
+            border-radius: 4px;
+        }
+        .comment-edited {
+            font-style: italic;
+        }
+        .comment-mention {
+            color: var(--brand-accent);
+            font-weight: 600;
+        }
+        .comment.comment-linked {
+            background: rgba(148, 163, 184, 0.12);
+            border-radius: 6px;
+        }
+        .comment-actions {
+            display: flex;
+            gap: 0.75rem;
+            margin-top: 0.375rem;
+        }
+        .comment-action {
+            background: none;
+            border: none;
+            padding: 0;
+            font-size: 0.8125rem;
+            color: #94a3b8;
+            cursor: pointer;
+        }
+        .comment-action:hover { color: #e2e8f0; }
+        .comment-edit-input {
+            width: 100%;
+            min-height: 60px;
+            margin-top: 0.375rem;
+            padding: 0.5rem;
+            border-radius: 6px;
+            border: 1px solid #334155;
+            background: var(--brand-bg);
+            color: #e2e8f0;
+            font: inherit;
+        }
+        .comment-replying {
+            display: none;
+            align-items: center;
+            gap: 0.5rem;
+            margin-bottom: 0.5rem;
+            font-size: 0.8125rem;
+            color: #94a3b8;
+        }
+        .comment-replying.active { display: flex; }
         .comment-form {
             margin-top: 1rem;
         }
@@ -364,6 +425,12 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
             margin-bottom: 0.5rem;
             display: none;
         }
+        .comment-notice {
+            color: #94a3b8;
+            font-size: 0.8125rem;
+            margin-bottom: 0.5rem;
+            display: none;
+        }
         .no-comments {
             color: #64748b;
             font-size: 0.875rem;
@@ -531,6 +598,91 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
         .timestamp-toggle-remove:hover svg {
             stroke: #ef4444;
         }
+        .annotation-layer {
+            position: absolute;
+            z-index: 1;
+            pointer-events: none;
+            overflow: visible;
+        }
+        .annotation-layer.drawing {
+            z-index: 4;
+            pointer-events: auto;
+            cursor: crosshair;
+            touch-action: none;
+        }
+        .annotation-tools {
+            display: none;
+            align-items: center;
+            gap: 0.25rem;
+            margin: 0 0 0.5rem 0.5rem;
+        }
+        .annotation-tools.active {
+            display: inline-flex;
+        }
+        .annotation-tool {
+            background: rgba(100, 116, 139, 0.15);
+            color: #cbd5e1;
+            border: none;
+            border-radius: 6px;
+            padding: 0.25rem 0.5rem;
+            font-size: 0.8125rem;
+            cursor: pointer;
+        }
+        .annotation-tool:hover {
+            background: rgba(100, 116, 139, 0.3);
+        }
+        .annotation-tool.active {
+            background: rgba(0, 182, 122, 0.15);
+            color: var(--brand-accent);
+        }
+        .comment-annotation-badge {
+            cursor: pointer;
+            font-size: 0.75rem;
+        }
+        .video-reply-panel {
+            display: none;
+            margin-bottom: 0.5rem;
+            padding: 0.75rem;
+            border: 1px solid #334155;
+            border-radius: 8px;
+        }
+        .video-reply-panel.active {
+            display: block;
+        }
+        .video-reply-preview {
+            display: none;
+            width: 100%;
+            max-height: 240px;
+            background: #000;
+            border-radius: 6px;
+            margin-bottom: 0.5rem;
+        }
+        .video-reply-preview.visible {
+            display: block;
+        }
+        .video-reply-controls {
+            display: flex;
+            flex-wrap: wrap;
+            align-items: center;
+            gap: 0.5rem;
+        }
+        .video-reply-timer {
+            color: #94a3b8;
+            font-size: 0.8125rem;
+            font-variant-numeric: tabular-nums;
+        }
+        .video-reply-file {
+            display: none;
+        }
+        .comment-video-reply {
+            display: block;
+            width: 100%;
+            max-width: 360px;
+            max-height: 240px;
+            margin-top: 0.375rem;
+            background: #000;
+            border-radius: 6px;
+        }
         .emoji-picker-wrapper {
             position: relative;
             display: inline-block;
@@ -807,6 +959,9 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                 {{if .TranscriptURL}}<track kind="subtitles" src="{{.TranscriptURL}}" srclang="en" label="Subtitles" default>{{end}}
                 Your browser does not support video playback.
             </video>
+            {{if ne .CommentMode "disabled"}}<svg class="annotation-layer" id="annotation-layer" aria-hidden="true"></svg>{{end}}
+            {{if .TimedCTAs}}` + timedCTAHTML + `{{end}}
+            {{if .Questions}}` + videoQuestionHTML + `{{end}}
 ` + playerControlsHTML + `
         </div>
 ` + safariWarningHTML + `
@@ -932,6 +1087,11 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
             <div id="comments-list"></div>
             <div class="comment-form" id="comment-form">
                 <p class="comment-error" id="comment-error"></p>
+                <p class="comment-notice" id="comment-notice"></p>
+                <div class="comment-replying" id="comment-replying">
+                    <span id="comment-replying-text"></span>
+                    <button type="button" class="comment-action" id="comment-replying-cancel">Cancel</button>
+                </div>
                 {{if or (eq .CommentMode "name_required") (eq .CommentMode "name_email_required")}}
                 <div class="form-row">
                     <input type="text" id="comment-name" placeholder="Your name" maxlength="200">
@@ -946,6 +1106,36 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                     <input type="text" class="timestamp-edit-input" id="timestamp-edit-input" placeholder="0:00">
                     <span class="timestamp-toggle-remove hidden" id="timestamp-toggle-remove"><svg viewBox="0 0 10 10"><line x1="2" y1="2" x2="8" y2="8"/><line x1="8" y1="2" x2="2" y2="8"/></svg></span>
                 </span>
+                <span class="timestamp-toggle" id="annotation-toggle">
+                    <span>&#x270F;&#xFE0F;</span>
+                    <span id="annotation-toggle-text">Draw on video</span>
+                </span>
+                {{if .VideoReplies}}
+                <span class="timestamp-toggle" id="video-reply-toggle">
+                    <span>&#x1F3A5;</span>
+                    <span>Reply with video</span>
+                </span>
+                {{end}}
+                <span class="annotation-tools" id="annotation-tools">
+                    <button type="button" class="annotation-tool active" data-tool="rect" title="Rectangle">&#x25AD;</button>
+                    <button type="button" class="annotation-tool" data-tool="arrow" title="Arrow">&#x2197;</button>
+                    <button type="button" class="annotation-tool" data-tool="path" title="Freehand">&#x3030;</button>
+                    <button type="button" class="annotation-tool" id="annotation-undo">Undo</button>
+                    <button type="button" class="annotation-tool" id="annotation-clear">Clear</button>
+                </span>
+                {{if .VideoReplies}}
+                <div class="video-reply-panel" id="video-reply-panel">
+                    <video class="video-reply-preview" id="video-reply-preview" playsinline></video>
+                    <div class="video-reply-controls">
+                        <button type="button" class="annotation-tool" id="video-reply-record">Record</button>
+                        <button type="button" class="annotation-tool" id="video-reply-stop" disabled>Stop</button>
+                        <label class="annotation-tool" for="video-reply-file">Upload a clip</label>
+                        <input type="file" class="video-reply-file" id="video-reply-file" accept="video/webm,video/mp4">
+                        <span class="video-reply-timer" id="video-reply-timer"></span>
+                        <button type="button" class="comment-submit" id="video-reply-send" disabled>Send video reply</button>
+                    </div>
+                </div>
+                {{end}}
                 <textarea id="comment-body" placeholder="Write a comment..." maxlength="5000"></textarea>
                 <div class="comment-form-actions">
                     <div class="flex-center">
@@ -966,6 +1156,7 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
             var listEl = document.getElementById('comments-list');
             var headerEl = document.getElementById('comments-header');
             var errorEl = document.getElementById('comment-error');
+            var noticeEl = document.getElementById('comment-notice');
             var submitBtn = document.getElementById('comment-submit');
             var bodyEl = document.getElementById('comment-body');
             var nameEl = document.getElementById('comment-name');
@@ -983,6 +1174,18 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
             var emojiTrigger = document.getElementById('emoji-trigger');
             var emojiGrid = document.getElementById('emoji-grid');
             var reactionErrorEl = document.getElementById('reaction-error');
+            var replyingEl = document.getElementById('comment-replying');
+            var replyingTextEl = document.getElementById('comment-replying-text');
+            var replyTo = null;
+            var annotationLayer = document.getElementById('annotation-layer');
+            var annotationToggle = document.getElementById('annotation-toggle');
+            var annotationToggleText = document.getElementById('annotation-toggle-text');
+            var annotationTools = document.getElementById('annotation-tools');
+            var annotationTool = 'rect';
+            var annotationColor = '#ef4444';
+            var draftShapes = [];
+            var activeShape = null;
+            var drawing = false;
 
             function getAuthToken() {
                 try { return localStorage.getItem('token') || ''; } catch(e) { return ''; }
@@ -1111,6 +1314,24 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                 return reactionEmojis.indexOf(text.trim()) !== -1;
             }
 
+            function renderCommentBody(c) {
+                var html = escapeHtml(c.body);
+                (c.mentions || []).forEach(function(m) {
+                    var handle = m.handle.replace(/[.\-]/g, '\\$&');
+                    var re = new RegExp('(^|[^A-Za-z0-9._-])@(' + handle + ')(?![A-Za-z0-9_-])', 'gi');
+                    html = html.replace(re, function(_, lead, text) {
+                        return lead + '<span class="comment-mention" title="' + escapeHtml(m.name) + '">@' + text + '</span>';
+                    });
+                });
+                return html;
+            }
+
+            function renderVideoReply(c) {
+                if (!c.videoReply || !c.videoReply.videoUrl) return '';
+                var poster = c.videoReply.thumbnailUrl ? ' poster="' + escapeHtml(c.videoReply.thumbnailUrl) + '"' : '';
+                return '<video class="comment-video-reply" controls preload="none" playsinline src="' + escapeHtml(c.videoReply.videoUrl) + '"' + poster + '></video>';
+            }
+
             function renderComment(c) {
                 var authorName = c.authorName || 'Anonymous';
                 if (isReactionEmoji(c.body)) {
@@ -1130,19 +1351,105 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                 if (c.videoTimestamp != null) {
                     badges += ' <span class="comment-timestamp" data-ts="' + c.videoTimestamp + '">' + formatTimestamp(c.videoTimestamp) + '</span>';
                 }
+                if (c.annotation && c.videoTimestamp != null) {
+                    badges += ' <span class="comment-annotation-badge" data-ts="' + c.videoTimestamp + '" title="Show drawing on the video">&#x270F;&#xFE0F;</span>';
+                }
                 if (c.isOwner) badges += ' <span class="comment-owner-badge">Owner</span>';
                 if (c.isPrivate) badges += ' <span class="comment-private-badge">Private</span>';
-                return '<div class="comment" id="comment-' + c.id + '">' +
+                if (c.resolved) badges += ' <span class="comment-resolved-badge">Resolved</span>';
+                var edited = c.editedAt ? ' <span class="comment-edited">(edited)</span>' : '';
+                var actions = '<button type="button" class="comment-action comment-reply-btn" data-id="' + c.id + '" data-author="' + escapeHtml(authorName) + '">Reply</button>';
+                if (c.canEdit) actions += '<button type="button" class="comment-action comment-edit-btn" data-id="' + c.id + '">Edit</button>';
+                return '<div class="comment' + (c.parentId ? ' comment-reply' : '') + '" id="comment-' + c.id + '">' +
                     '<div class="comment-meta">' +
                         '<span class="comment-author">' + escapeHtml(authorName) + '</span>' +
                         badges +
-                        '<span>\u00b7 ' + timeAgo(c.createdAt) + '</span>' +
+                        '<span>\u00b7 ' + timeAgo(c.createdAt) + edited + '</span>' +
                     '</div>' +
-                    '<div class="comment-body">' + escapeHtml(c.body) + '</div>' +
+                    '<div class="comment-body">' + renderCommentBody(c) + '</div>' +
+                    renderVideoReply(c) +
+                    '<div class="comment-actions">' + actions + '</div>' +
                 '</div>';
             }
 
+            function setReplyTo(id, author) {
+                replyTo = id;
+                if (!replyingEl) return;
+                if (id) {
+                    replyingTextEl.textContent = 'Replying to ' + author;
+                    replyingEl.classList.add('active');
+                    bodyEl.focus();
+                } else {
+                    replyingEl.classList.remove('active');
+                }
+            }
+
+            document.getElementById('comment-replying-cancel').addEventListener('click', function() {
+                setReplyTo(null);
+            });
+
+            function startCommentEdit(commentEl, id) {
+                var bodyDiv = commentEl.querySelector('.comment-body');
+                if (!bodyDiv || commentEl.querySelector('.comment-edit-input')) return;
+                var input = document.createElement('textarea');
+                input.className = 'comment-edit-input';
+                input.maxLength = 5000;
+                input.value = bodyDiv.textContent;
+                bodyDiv.style.display = 'none';
+                bodyDiv.insertAdjacentElement('afterend', input);
+                input.focus();
+                function finish(save) {
+                    var text = input.value.trim();
+                    if (!save || !text || text === bodyDiv.textContent) {
+                        input.remove();
+                        bodyDiv.style.display = '';
+                        return;
+                    }
+                    var headers = {'Content-Type': 'application/json'};
+                    if (token) headers['Authorization'] = 'Bearer ' + token;
+                    fetch('/api/watch/' + shareToken + '/comments/' + id, {
+                        method: 'PATCH',
+                        headers: headers,
+                        body: JSON.stringify({body: text})
+                    }).then(function(r) {
+                        if (!r.ok) throw new Error('Could not edit comment');
+                        return r.json();
+                    }).then(function(updated) {
+                        if (updated.status === 'pending') {
+                            noticeEl.textContent = 'Your edited comment will show again once it has been approved.';
+                            noticeEl.style.display = 'block';
+                        }
+                        loadComments();
+                    }).catch(function(err) {
+                        errorEl.textContent = err.message; errorEl.style.display = 'block';
+                        input.remove();
+                        bodyDiv.style.display = '';
+                    });
+                }
+                input.addEventListener('keydown', function(e) {
+                    if (e.key === 'Enter' && !e.shiftKey) { e.preventDefault(); finish(true); }
+                    if (e.key === 'Escape') { e.preventDefault(); finish(false); }
+                });
+                input.addEventListener('blur', function() { finish(true); });
+            }
+
             listEl.addEventListener('click', function(e) {
+                var replyBtn = e.target.closest('.comment-reply-btn');
+                if (replyBtn) {
+                    setReplyTo(replyBtn.getAttribute('data-id'), replyBtn.getAttribute('data-author'));
+                    return;
+                }
+                var editBtn = e.target.closest('.comment-edit-btn');
+                if (editBtn) {
+                    startCommentEdit(editBtn.closest('.comment'), editBtn.getAttribute('data-id'));
+                    return;
+                }
+                var annotationEl = e.target.closest('.comment-annotation-badge');
+                if (annotationEl) {
+                    player.pause();
+                    player.currentTime = parseFloat(annotationEl.getAttribute('data-ts'));
+                    return;
+                }
                 var tsEl = e.target.closest('.comment-timestamp');
                 if (tsEl) {
                     player.currentTime = parseFloat(tsEl.getAttribute('data-ts'));
@@ -1175,9 +1482,22 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                             lastComments = data.comments;
                             renderMarkers(data.comments);
                         }
+                        revealLinkedComment();
                     });
             }
 
+            // Links from mention notifications end in #comment-<id>; bring
+            // that comment into view once, on the first load.
+            var linkedCommentShown = false;
+            function revealLinkedComment() {
+                if (linkedCommentShown || location.hash.indexOf('#comment-') !== 0) return;
+                linkedCommentShown = true;
+                var el = document.getElementById(location.hash.slice(1));
+                if (!el) return;
+                el.classList.add('comment-linked');
+                el.scrollIntoView({block: 'center'});
+            }
+
             loadComments();
 
             var reactionBar = document.getElementById('reaction-bar');
@@ -1258,6 +1578,8 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
 
             function deactivateTimestamp() {
                 capturedTimestamp = null;
+                draftShapes = [];
+                setDrawing(false);
                 timestampToggle.classList.remove('active');
                 timestampToggleText.textContent = 'Add timestamp';
                 timestampToggleText.style.display = '';
@@ -1314,6 +1636,169 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                 commitEdit();
             });
 
+            // Drawings are stored in coordinates normalized to the video
+            // frame, so the layer is sized to the letterboxed picture rather
+            // than the whole player.
+            var annotationShowSeconds = 3;
+            var maxAnnotationShapes = 20;
+            var maxPathPoints = 500;
+            var svgNS = 'http://www.w3.org/2000/svg';
+
+            function videoFrame() {
+                var container = annotationLayer.parentNode;
+                var cw = container.clientWidth, ch = container.clientHeight;
+                var vw = player.videoWidth || 16, vh = player.videoHeight || 9;
+                var scale = Math.min(cw / vw, ch / vh);
+                var w = vw * scale, h = vh * scale;
+                return {left: (cw - w) / 2, top: (ch - h) / 2, width: w, height: h};
+            }
+
+            function svgEl(name, attrs) {
+                var el = document.createElementNS(svgNS, name);
+                Object.keys(attrs).forEach(function(k) { el.setAttribute(k, attrs[k]); });
+                return el;
+            }
+
+            function drawShapes(shapes) {
+                var f = videoFrame();
+                annotationLayer.style.left = f.left + 'px';
+                annotationLayer.style.top = f.top + 'px';
+                annotationLayer.style.width = f.width + 'px';
+                annotationLayer.style.height = f.height + 'px';
+                annotationLayer.setAttribute('viewBox', '0 0 ' + f.width + ' ' + f.height);
+                while (annotationLayer.firstChild) annotationLayer.removeChild(annotationLayer.firstChild);
+                shapes.forEach(function(s) {
+                    var color = s.color || annotationColor;
+                    var pts = s.points.map(function(p) { return [p[0] * f.width, p[1] * f.height]; });
+                    var stroke = {fill: 'none', stroke: color, 'stroke-width': 3, 'stroke-linecap': 'round', 'stroke-linejoin': 'round'};
+                    if (s.type === 'rect' && pts.length === 2) {
+                        annotationLayer.appendChild(svgEl('rect', Object.assign({
+                            x: Math.min(pts[0][0], pts[1][0]), y: Math.min(pts[0][1], pts[1][1]),
+                            width: Math.abs(pts[1][0] - pts[0][0]), height: Math.abs(pts[1][1] - pts[0][1])
+                        }, stroke)));
+                    } else if (s.type === 'arrow' && pts.length === 2) {
+                        var angle = Math.atan2(pts[1][1] - pts[0][1], pts[1][0] - pts[0][0]);
+                        var head = [pts[1],
+                            [pts[1][0] - 14 * Math.cos(angle - 0.45), pts[1][1] - 14 * Math.sin(angle - 0.45)],
+                            [pts[1][0] - 14 * Math.cos(angle + 0.45), pts[1][1] - 14 * Math.sin(angle + 0.45)]];
+                        annotationLayer.appendChild(svgEl('line', Object.assign({x1: pts[0][0], y1: pts[0][1], x2: pts[1][0], y2: pts[1][1]}, stroke)));
+                        annotationLayer.appendChild(svgEl('polygon', {points: head.join(' '), fill: color}));
+                    } else if (s.type === 'path') {
+                        annotationLayer.appendChild(svgEl('polyline', Object.assign({points: pts.join(' ')}, stroke)));
+                    }
+                });
+            }
+
+            function updateAnnotationLayer() {
+                if (!annotationLayer) return;
+                if (drawing) { drawShapes(draftShapes); return; }
+                var t = player.currentTime;
+                var shapes = [];
+                (lastComments || []).forEach(function(c) {
+                    if (!c.annotation || c.videoTimestamp == null) return;
+                    if (t >= c.videoTimestamp && t < c.videoTimestamp + annotationShowSeconds) {
+                        shapes = shapes.concat(c.annotation.shapes);
+                    }
+                });
+                drawShapes(shapes);
+            }
+
+            function updateAnnotationToggle() {
+                if (!annotationToggle) return;
+                if (drawing) annotationToggleText.textContent = 'Done drawing';
+                else if (draftShapes.length) annotationToggleText.textContent = 'Drawing (' + draftShapes.length + ')';
+                else annotationToggleText.textContent = 'Draw on video';
+                annotationToggle.classList.toggle('active', drawing || draftShapes.length > 0);
+            }
+
+            function setDrawing(on) {
+                if (!annotationLayer) return;
+                drawing = on;
+                activeShape = null;
+                annotationLayer.classList.toggle('drawing', on);
+                annotationTools.classList.toggle('active', on);
+                updateAnnotationToggle();
+                updateAnnotationLayer();
+            }
+
+            function framePoint(e) {
+                var rect = annotationLayer.getBoundingClientRect();
+                var x = Math.min(Math.max((e.clientX - rect.left) / rect.width, 0), 1);
+                var y = Math.min(Math.max((e.clientY - rect.top) / rect.height, 0), 1);
+                return [Math.round(x * 10000) / 10000, Math.round(y * 10000) / 10000];
+            }
+
+            if (annotationLayer) {
+                annotationToggle.addEventListener('click', function() {
+                    if (drawing) { setDrawing(false); return; }
+                    player.pause();
+                    if (capturedTimestamp === null) setTimestamp(player.currentTime);
+                    else player.currentTime = capturedTimestamp;
+                    setDrawing(true);
+                });
+
+                annotationTools.addEventListener('click', function(e) {
+                    var btn = e.target.closest('.annotation-tool');
+                    if (!btn) return;
+                    if (btn.id === 'annotation-undo') {
+                        draftShapes.pop();
+                    } else if (btn.id === 'annotation-clear') {
+                        draftShapes = [];
+                    } else {
+                        annotationTool = btn.getAttribute('data-tool');
+                        annotationTools.querySelectorAll('[data-tool]').forEach(function(b) {
+                            b.classList.toggle('active', b === btn);
+                        });
+                    }
+                    updateAnnotationToggle();
+                    updateAnnotationLayer();
+                });
+
+                annotationLayer.addEventListener('pointerdown', function(e) {
+                    if (!drawing || draftShapes.length >= maxAnnotationShapes) return;
+                    e.preventDefault();
+                    annotationLayer.setPointerCapture(e.pointerId);
+                    var p = framePoint(e);
+                    activeShape = {type: annotationTool, color: annotationColor, points: annotationTool === 'path' ? [p] : [p, p]};
+                    draftShapes.push(activeShape);
+                    drawShapes(draftShapes);
+                });
+
+                annotationLayer.addEventListener('pointermove', function(e) {
+                    if (!activeShape) return;
+                    var p = framePoint(e);
+                    var pts = activeShape.points;
+                    if (activeShape.type === 'path') {
+                        var last = pts[pts.length - 1];
+                        if (pts.length >= maxPathPoints || Math.abs(p[0] - last[0]) + Math.abs(p[1] - last[1]) < 0.004) return;
+                        pts.push(p);
+                    } else {
+                        pts[1] = p;
+                    }
+                    drawShapes(draftShapes);
+                });
+
+                function finishShape() {
+                    if (!activeShape) return;
+                    var pts = activeShape.points;
+                    var tooSmall = pts.length < 2 ||
+                        (activeShape.type !== 'path' && Math.abs(pts[1][0] - pts[0][0]) + Math.abs(pts[1][1] - pts[0][1]) < 0.01);
+                    if (tooSmall) draftShapes.pop();
+                    activeShape = null;
+                    updateAnnotationToggle();
+                    drawShapes(draftShapes);
+                }
+                annotationLayer.addEventListener('pointerup', finishShape);
+                annotationLayer.addEventListener('pointercancel', finishShape);
+                annotationLayer.addEventListener('click', function(e) { e.stopPropagation(); });
+
+                player.addEventListener('timeupdate', updateAnnotationLayer);
+                player.addEventListener('seeked', updateAnnotationLayer);
+                player.addEventListener('loadedmetadata', updateAnnotationLayer);
+                window.addEventListener('resize', updateAnnotationLayer);
+                document.addEventListener('fullscreenchange', updateAnnotationLayer);
+            }
+
             var emojiCategories = {
                 'Smileys': ['\uD83D\uDE00','\uD83D\uDE03','\uD83D\uDE04','\uD83D\uDE01','\uD83D\uDE06','\uD83D\uDE05','\uD83E\uDD23','\uD83D\uDE02','\uD83D\uDE42','\uD83D\uDE09','\uD83D\uDE0A','\uD83D\uDE07','\uD83D\uDE0D','\uD83E\uDD29','\uD83D\uDE18','\uD83D\uDE0B','\uD83D\uDE1C','\uD83E\uDD17','\uD83E\uDD14','\uD83D\uDE10','\uD83D\uDE11','\uD83D\uDE36'],
                 'Hands': ['\uD83D\uDC4D','\uD83D\uDC4E','\uD83D\uDC4F','\uD83D\uDE4C','\uD83E\uDD1D','\u270C\uFE0F','\uD83E\uDD1E','\uD83E\uDD1F','\uD83D\uDC4B','\uD83D\uDD90\uFE0F','\u270B','\uD83D\uDC4A'],
@@ -1377,24 +1862,34 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                 var isPrivate = privateEl ? privateEl.checked : false;
                 submitBtn.disabled = true;
                 errorEl.style.display = 'none';
+                noticeEl.style.display = 'none';
                 var headers = {'Content-Type': 'application/json'};
                 if (token) headers['Authorization'] = 'Bearer ' + token;
                 fetch('/api/watch/' + shareToken + '/comments', {
                     method: 'POST',
                     headers: headers,
-                    body: JSON.stringify({authorName: authorName, authorEmail: authorEmail, body: body, isPrivate: isPrivate, videoTimestamp: capturedTimestamp})
+                    body: JSON.stringify({authorName: authorName, authorEmail: authorEmail, body: body, isPrivate: isPrivate, videoTimestamp: capturedTimestamp, parentId: replyTo, annotation: draftShapes.length ? {shapes: draftShapes} : null})
                 }).then(function(r) {
                     if (!r.ok) return r.json().then(function(d) { throw new Error(d.error || 'Could not post comment'); });
                     return r.json();
                 }).then(function(comment) {
-                    listEl.querySelector('.no-comments') && listEl.querySelector('.no-comments').remove();
-                    listEl.insertAdjacentHTML('beforeend', renderComment(comment));
-                    var count = listEl.querySelectorAll('.comment').length;
-                    headerEl.textContent = 'Comments (' + count + ')';
+                    if (comment.status === 'pending') {
+                        noticeEl.textContent = 'Thanks! Your comment will appear once it has been approved.';
+                        noticeEl.style.display = 'block';
+                        setReplyTo(null);
+                    } else if (comment.parentId) {
+                        setReplyTo(null);
+                        loadComments();
+                    } else {
+                        listEl.querySelector('.no-comments') && listEl.querySelector('.no-comments').remove();
+                        listEl.insertAdjacentHTML('beforeend', renderComment(comment));
+                        var count = listEl.querySelectorAll('.comment').length;
+                        headerEl.textContent = 'Comments (' + count + ')';
+                    }
                     bodyEl.value = '';
                     if (privateEl) privateEl.checked = false;
                     deactivateTimestamp();
-                    if (lastComments) {
+                    if (lastComments && comment.status !== 'pending') {
                         lastComments.push(comment);
                         renderMarkers(lastComments);
                     }
@@ -1403,6 +1898,222 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                     errorEl.textContent = err.message; errorEl.style.display = 'block'; submitBtn.disabled = false;
                 });
             });
+
+            var videoReplyToggle = document.getElementById('video-reply-toggle');
+            if (videoReplyToggle) {
+                var videoReplyMaxSecs = {{.VideoReplyMaxSecs}};
+                var vrPanel = document.getElementById('video-reply-panel');
+                var vrPreview = document.getElementById('video-reply-preview');
+                var vrRecordBtn = document.getElementById('video-reply-record');
+                var vrStopBtn = document.getElementById('video-reply-stop');
+                var vrFileInput = document.getElementById('video-reply-file');
+                var vrTimer = document.getElementById('video-reply-timer');
+                var vrSendBtn = document.getElementById('video-reply-send');
+                var vrStream = null;
+                var vrRecorder = null;
+                var vrTimerId = null;
+                var vrPreviewURL = null;
+                var vrBlob = null;
+                var vrDuration = 0;
+
+                function vrShowError(message) {
+                    errorEl.textContent = message;
+                    errorEl.style.display = 'block';
+                }
+
+                function vrLimitText(seconds) {
+                    return videoReplyMaxSecs > 0 ? formatTimestamp(seconds) + ' / ' + formatTimestamp(videoReplyMaxSecs) : formatTimestamp(seconds);
+                }
+
+                function vrStopStream() {
+                    if (vrStream) {
+                        vrStream.getTracks().forEach(function(t) { t.stop(); });
+                        vrStream = null;
+                    }
+                    if (vrTimerId) { clearInterval(vrTimerId); vrTimerId = null; }
+                }
+
+                function vrSetClip(blob, duration) {
+                    vrBlob = blob;
+                    vrDuration = duration;
+                    if (vrPreviewURL) URL.revokeObjectURL(vrPreviewURL);
+                    vrPreviewURL = URL.createObjectURL(blob);
+                    vrPreview.srcObject = null;
+                    vrPreview.muted = false;
+                    vrPreview.controls = true;
+                    vrPreview.src = vrPreviewURL;
+                    vrPreview.classList.add('visible');
+                    vrTimer.textContent = vrLimitText(duration);
+                    vrSendBtn.disabled = false;
+                }
+
+                function vrReset() {
+                    if (vrRecorder && vrRecorder.state === 'recording') {
+                        vrRecorder.onstop = null;
+                        vrRecorder.stop();
+                    }
+                    vrRecorder = null;
+                    vrStopStream();
+                    if (vrPreviewURL) { URL.revokeObjectURL(vrPreviewURL); vrPreviewURL = null; }
+                    vrPreview.removeAttribute('src');
+                    vrPreview.srcObject = null;
+                    vrPreview.classList.remove('visible');
+                    vrBlob = null;
+                    vrDuration = 0;
+                    vrFileInput.value = '';
+                    vrTimer.textContent = '';
+                    vrRecordBtn.disabled = false;
+                    vrStopBtn.disabled = true;
+                    vrSendBtn.disabled = true;
+                    vrSendBtn.textContent = 'Send video reply';
+                }
+
+                videoReplyToggle.addEventListener('click', function() {
+                    var open = !vrPanel.classList.contains('active');
+                    vrPanel.classList.toggle('active', open);
+                    videoReplyToggle.classList.toggle('active', open);
+                    if (!open) vrReset();
+                });
+
+                vrRecordBtn.addEventListener('click', function() {
+                    if (!navigator.mediaDevices || !window.MediaRecorder) {
+                        vrShowError('Recording is not supported in this browser. Upload a clip instead.');
+                        return;
+                    }
+                    errorEl.style.display = 'none';
+                    vrReset();
+                    navigator.mediaDevices.getUserMedia({video: true, audio: true}).then(function(stream) {
+                        vrStream = stream;
+                        var mimeType = MediaRecorder.isTypeSupported('video/webm') ? 'video/webm' : 'video/mp4';
+                        var chunks = [];
+                        var startedAt = Date.now();
+                        vrRecorder = new MediaRecorder(stream, {mimeType: mimeType});
+                        vrRecorder.ondataavailable = function(e) { if (e.data && e.data.size) chunks.push(e.data); };
+                        vrRecorder.onstop = function() {
+                            var seconds = Math.max(1, Math.round((Date.now() - startedAt) / 1000));
+                            if (videoReplyMaxSecs > 0 && seconds > videoReplyMaxSecs) seconds = videoReplyMaxSecs;
+                            vrStopStream();
+                            vrRecordBtn.disabled = false;
+                            vrStopBtn.disabled = true;
+                            vrSetClip(new Blob(chunks, {type: mimeType}), seconds);
+                        };
+                        vrPreview.srcObject = stream;
+                        vrPreview.muted = true;
+                        vrPreview.controls = false;
+                        vrPreview.classList.add('visible');
+                        vrPreview.play().catch(function() {});
+                        vrRecorder.start(1000);
+                        vrRecordBtn.disabled = true;
+                        vrStopBtn.disabled = false;
+                        vrTimer.textContent = vrLimitText(0);
+                        vrTimerId = setInterval(function() {
+                            var elapsed = Math.floor((Date.now() - startedAt) / 1000);
+                            vrTimer.textContent = vrLimitText(elapsed);
+                            if (videoReplyMaxSecs > 0 && elapsed >= videoReplyMaxSecs && vrRecorder.state === 'recording') {
+                                vrRecorder.stop();
+                            }
+                        }, 250);
+                    }).catch(function() {
+                        vrShowError('Could not access your camera or microphone.');
+                    });
+                });
+
+                vrStopBtn.addEventListener('click', function() {
+                    if (vrRecorder && vrRecorder.state === 'recording') vrRecorder.stop();
+                });
+
+                vrFileInput.addEventListener('change', function() {
+                    var file = vrFileInput.files && vrFileInput.files[0];
+                    if (!file) return;
+                    errorEl.style.display = 'none';
+                    if (file.type !== 'video/webm' && file.type !== 'video/mp4') {
+                        vrShowError('Only WebM and MP4 videos can be sent.');
+                        vrFileInput.value = '';
+                        return;
+                    }
+                    var probe = document.createElement('video');
+                    var probeURL = URL.createObjectURL(file);
+                    probe.preload = 'metadata';
+                    probe.addEventListener('loadedmetadata', function() {
+                        URL.revokeObjectURL(probeURL);
+                        var seconds = Math.ceil(probe.duration);
+                        if (!isFinite(seconds) || seconds < 1) {
+                            vrShowError('Could not read the length of this video.');
+                            return;
+                        }
+                        if (videoReplyMaxSecs > 0 && seconds > videoReplyMaxSecs) {
+                            vrShowError('Video replies can be at most ' + formatTimestamp(videoReplyMaxSecs) + ' long.');
+                            return;
+                        }
+                        vrSetClip(file, seconds);
+                    });
+                    probe.addEventListener('error', function() {
+                        URL.revokeObjectURL(probeURL);
+                        vrShowError('Could not read this video.');
+                    });
+                    probe.src = probeURL;
+                });
+
+                vrSendBtn.addEventListener('click', function() {
+                    if (!vrBlob) return;
+                    var authorName = nameEl ? nameEl.value.trim() : '';
+                    var authorEmail = emailEl ? emailEl.value.trim() : '';
+                    if ((commentMode === 'name_required' || commentMode === 'name_email_required') && !authorName) {
+                        vrShowError('Name is required.'); return;
+                    }
+                    if (commentMode === 'name_email_required' && !authorEmail) {
+                        vrShowError('Email is required.'); return;
+                    }
+                    var contentType = (vrBlob.type || 'video/webm').split(';')[0];
+                    var headers = {'Content-Type': 'application/json'};
+                    if (token) headers['Authorization'] = 'Bearer ' + token;
+                    errorEl.style.display = 'none';
+                    noticeEl.style.display = 'none';
+                    vrSendBtn.disabled = true;
+                    vrRecordBtn.disabled = true;
+                    vrSendBtn.textContent = 'Uploading...';
+                    fetch('/api/watch/' + shareToken + '/video-replies', {
+                        method: 'POST',
+                        headers: headers,
+                        body: JSON.stringify({authorName: authorName, authorEmail: authorEmail, body: bodyEl.value.trim(), duration: vrDuration, fileSize: vrBlob.size, contentType: contentType})
+                    }).then(function(r) {
+                        if (!r.ok) return r.json().then(function(d) { throw new Error(d.error || 'Could not send video reply'); });
+                        return r.json();
+                    }).then(function(created) {
+                        return fetch(created.uploadUrl, {
+                            method: 'PUT',
+                            headers: {'Content-Type': contentType},
+                            body: vrBlob
+                        }).then(function(r) {
+                            if (!r.ok) throw new Error('Upload failed');
+                            return fetch('/api/watch/' + shareToken + '/video-replies/' + created.commentId + '/complete', {
+                                method: 'POST',
+                                headers: headers
+                            });
+                        });
+                    }).then(function(r) {
+                        if (!r.ok) return r.json().then(function(d) { throw new Error(d.error || 'Could not send video reply'); });
+                        return r.json();
+                    }).then(function(comment) {
+                        if (comment.status === 'pending') {
+                            noticeEl.textContent = 'Thanks! Your video reply will appear once it has been approved.';
+                        } else {
+                            noticeEl.textContent = 'Thanks! Your video reply is being processed and will play here shortly.';
+                            loadComments();
+                        }
+                        noticeEl.style.display = 'block';
+                        bodyEl.value = '';
+                        vrPanel.classList.remove('active');
+                        videoReplyToggle.classList.remove('active');
+                        vrReset();
+                    }).catch(function(err) {
+                        vrShowError(err.message);
+                        vrSendBtn.disabled = false;
+                        vrRecordBtn.disabled = false;
+                        vrSendBtn.textContent = 'Send video reply';
+                    });
+                });
+            }
         })();
         </script>
         {{end}}
@@ -1573,6 +2284,22 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
             }
         })();
         {{end}}
+        {{if .TimedCTAs}}
+        (function() {
+            var player = document.getElementById('player');
+            var timedCTAs = {{.TimedCTAsJSON}};
+            var timedCTAClickURL = '/api/watch/{{.ShareToken}}/cta-click';
+` + timedCTAJS + `
+        })();
+        {{end}}
+        {{if .Questions}}
+        (function() {
+            var player = document.getElementById('player');
+            var videoQuestions = {{.QuestionsJSON}};
+            var questionAnswerURL = '/api/watch/{{.ShareToken}}/answers';
+` + videoQuestionJS + `
+        })();
+        {{end}}
         (function() {
             var player = document.getElementById('player');
             if (!player) return;
@@ -1594,6 +2321,17 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                 }
             });
         })();
+        (function() {
+            var player = document.getElementById('player');
+            if (!player) return;
+            var start = parseFloat(new URLSearchParams(location.search).get('t'));
+            if (!(start > 0)) return;
+            function seekToStart() {
+                if (player.duration && start < player.duration) player.currentTime = start;
+            }
+            if (player.readyState >= 1) seekToStart();
+            else player.addEventListener('loadedmetadata', seekToStart, { once: true });
+        })();
         (function() {
             var player = document.getElementById('player');
             if (!player) return;
@@ -1601,10 +2339,41 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
             var reported = {};
             var pending = [];
             var lastSeg = -1;
+            var ranges = [];
+            var rangeStart = -1;
+            var rangeEnd = -1;
+            var sessionId = window.crypto && crypto.randomUUID
+                ? crypto.randomUUID()
+                : Date.now().toString(36) + Math.random().toString(36).slice(2);
+            var sessionStart = Date.now();
+            var events = [];
+            var lastPos = 0;
+            var seekFrom = -1;
+            function logEvent(type, position, extra) {
+                if (events.length >= 200) return;
+                var e = { type: type, t: Date.now() - sessionStart, position: position };
+                for (var k in extra) e[k] = extra[k];
+                events.push(e);
+            }
+            function closeRange() {
+                if (rangeStart >= 0 && rangeEnd > rangeStart) {
+                    ranges.push([rangeStart, rangeEnd]);
+                    logEvent('segment', rangeEnd, { from: rangeStart });
+                }
+                rangeStart = -1;
+            }
             function flush() {
-                if (pending.length === 0) return;
-                var data = JSON.stringify({ segments: pending });
+                var resumeAt = !player.paused && rangeStart >= 0 ? rangeEnd : -1;
+                closeRange();
+                if (resumeAt >= 0) {
+                    rangeStart = resumeAt;
+                    rangeEnd = resumeAt;
+                }
+                if (pending.length === 0 && ranges.length === 0 && events.length === 0) return;
+                var data = JSON.stringify({ segments: pending, ranges: ranges, sessionId: sessionId, events: events });
                 pending = [];
+                ranges = [];
+                events = [];
                 if (navigator.sendBeacon) {
                     navigator.sendBeacon('/api/watch/{{.ShareToken}}/segments',
                         new Blob([data], { type: 'application/json' }));
@@ -1616,8 +2385,33 @@ var watchPageTemplate = template.Must(template.New("watch").Funcs(watchFuncs).Pa
                     }).catch(function() {});
                 }
             }
+            player.addEventListener('seeking', function() {
+                if (seekFrom < 0) seekFrom = lastPos;
+                closeRange();
+            });
+            player.addEventListener('seeked', function() {
+                if (seekFrom < 0) return;
+                logEvent('seek', player.currentTime, { from: seekFrom });
+                seekFrom = -1;
+                lastPos = player.currentTime;
+            });
+            player.addEventListener('play', function() { logEvent('play', player.currentTime); });
+            player.addEventListener('pause', function() {
+                if (!player.ended) logEvent('pause', player.currentTime);
+            });
+            player.addEventListener('ended', function() { logEvent('ended', player.currentTime); });
+            player.addEventListener('ratechange', function() {
+                logEvent('ratechange', player.currentTime, { rate: player.playbackRate });
+            });
             player.addEventListener('timeupdate', function() {
                 if (!player.duration || player.duration <= 0) return;
+                var t = player.currentTime;
+                if (seekFrom < 0) lastPos = t;
+                if (rangeStart < 0 || t < rangeEnd || t - rangeEnd > 2) {
+                    closeRange();
+                    rangeStart = t;
+                }
+                rangeEnd = t;
                 var seg = Math.min(Math.floor((player.currentTime / player.duration) * SEGMENTS), SEGMENTS - 1);
                 if (reported[seg]) return;
                 if (lastSeg >= 0 && Math.abs(seg - lastSeg) > 1) {
@@ -1774,6 +2568,10 @@ type watchPageData struct {
 	Summary            string
 	Chapters           []Chapter
 	ChaptersJSON       template.JS
+	TimedCTAs          []videoCTA
+	TimedCTAsJSON      template.JS
+	Questions          []playerQuestion
+	QuestionsJSON      template.JS
 	SummaryStatus      string
 	Description        string
 	Duration           int
@@ -1781,6 +2579,8 @@ type watchPageData struct {
 	JSONLD             template.JS
 	SubscriptionPlan   string
 	VideoStatus        string
+	VideoReplies       bool
+	VideoReplyMaxSecs  int
 }
 
 type expiredPageData struct {
@@ -1898,6 +2698,7 @@ type emailGatePageData struct {
 	Title      string
 	ShareToken string
 	Nonce      string
+	GateFields []gateField
 }
 
 var emailGatePageTemplate = template.Must(template.New("emailgate").Parse(`<!DOCTYPE html>
@@ -1933,7 +2734,7 @@ var emailGatePageTemplate = template.Must(template.New("emailgate").Parse(`<!DOC
         h1 { font-size: 1.5rem; font-weight: 700; margin-bottom: 0.75rem; }
         p { color: #94a3b8; margin-bottom: 1.5rem; font-size: 0.875rem; }
         .error { color: #ef4444; font-size: 0.875rem; margin-bottom: 1rem; display: none; }
-        input[type="email"] {
+        input[type="email"], input[type="text"] {
             width: 100%;
             padding: 0.75rem 1rem;
             border-radius: 8px;
@@ -1945,7 +2746,7 @@ var emailGatePageTemplate = template.Must(template.New("emailgate").Parse(`<!DOC
             outline: none;
             font-family: inherit;
         }
-        input[type="email"]:focus { border-color: #00b67a; }
+        input[type="email"]:focus, input[type="text"]:focus { border-color: #00b67a; }
         input[type="email"]::placeholder { color: #94a3b8; }
         button {
             width: 100%;
@@ -1961,7 +2762,7 @@ var emailGatePageTemplate = template.Must(template.New("emailgate").Parse(`<!DOC
         }
         button:hover { opacity: 0.9; }
         button:disabled { opacity: 0.5; cursor: not-allowed; }
-        button:focus-visible { outline: 2px solid #00b67a; outline-offset: 2px; }
+        button:focus-visible { outline: 2px solid #00b67a; outline-offset: 2px; }` + gateFieldsCSS + `
     </style>
 </head>
 <body>
@@ -1974,22 +2775,44 @@ var emailGatePageTemplate = template.Must(template.New("emailgate").Parse(`<!DOC
         <p class="error" id="error-msg"></p>
         <form id="email-gate-form">
             <input type="email" id="email-input" placeholder="you@example.com" required maxlength="320" autofocus>
+            ` + gateFieldsHTML + `
+            <input type="text" id="code-input" placeholder="6-digit code" inputmode="numeric" autocomplete="one-time-code" maxlength="6" hidden>
             <button type="submit" id="submit-btn">Watch Video</button>
         </form>
     </div>
-    <script nonce="{{.Nonce}}">
+    <script nonce="{{.Nonce}}">` + gateFieldsJS + `
+        var awaitingCode = false;
         document.getElementById('email-gate-form').addEventListener('submit', function(e) {
             e.preventDefault();
             var btn = document.getElementById('submit-btn');
             var errEl = document.getElementById('error-msg');
-            var email = document.getElementById('email-input').value;
+            var emailEl = document.getElementById('email-input');
+            var codeEl = document.getElementById('code-input');
+            var email = emailEl.value;
             btn.disabled = true;
             errEl.style.display = 'none';
-            fetch('/api/watch/{{.ShareToken}}/identify', {
+            var url = awaitingCode ? '/api/watch/{{.ShareToken}}/identify/verify' : '/api/watch/{{.ShareToken}}/identify';
+            var payload = awaitingCode ? {email: email, code: codeEl.value} : {email: email, fields: collectGateFields()};
+            fetch(url, {
                 method: 'POST',
                 headers: {'Content-Type': 'application/json'},
-                body: JSON.stringify({email: email})
+                body: JSON.stringify(payload)
             }).then(function(r) {
+                if (r.status === 202) {
+                    awaitingCode = true;
+                    emailEl.hidden = true;
+                    document.getElementById('gate-fields').hidden = true;
+                    codeEl.hidden = false;
+                    codeEl.required = true;
+                    codeEl.focus();
+                    btn.textContent = 'Verify';
+                    btn.disabled = false;
+                    errEl.textContent = 'We sent a code to ' + email + '. Enter it below or use the link in the email.';
+                    errEl.style.color = '#94a3b8';
+                    errEl.style.display = 'block';
+                    return;
+                }
+                errEl.style.color = '';
                 if (r.ok) { window.location.reload(); }
                 else { return r.json().then(function(d) { errEl.textContent = d.error || 'Something went wrong'; errEl.style.display = 'block'; btn.disabled = false; }); }
             }).catch(function() {
@@ -2019,7 +2842,11 @@ func injectScriptNonce(scriptTag, nonce string) template.HTML {
 }
 
 func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
-	shareToken := chi.URLParam(r, "shareToken")
+	shareToken, renamed := h.resolveShareRef(r, false)
+	if renamed != "" {
+		redirectRenamedSlug(w, r, "/watch/"+renamed)
+		return
+	}
 
 	var title string
 	var fileKey string
@@ -2050,6 +2877,10 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 	var duration int
 	var subscriptionPlan string
 	var status string
+	var visibility string
+	var videoRepliesEnabled bool
+	var timedCTAsJSON string
+	var questionsJSON string
 
 	err := h.db.QueryRow(r.Context(),
 		`SELECT v.id, v.title, v.file_key, u.name, v.created_at, v.share_expires_at, v.thumbnail_key, v.share_password, v.comment_mode,
@@ -2062,12 +2893,17 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 		        v.summary, v.chapters, v.summary_status, v.duration,
 		        u.subscription_plan,
 		        v.status,
-		        v.organization_id
+		        v.organization_id,
+		        COALESCE(v.visibility, f.visibility, 'public'),
+		        v.video_replies_enabled,
+		        `+videoCTAsJSONColumn+`,
+		        `+videoQuestionsJSONColumn+`
 		 FROM videos v
 		 JOIN users u ON u.id = v.user_id
 		 LEFT JOIN user_branding ub ON ub.user_id = v.user_id AND ub.organization_id IS NULL
 		 LEFT JOIN user_branding ob ON ob.organization_id = v.organization_id
-		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
+		 LEFT JOIN folders f ON f.id = v.folder_id
+		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing') AND v.embed_signing_secret IS NULL`,
 		shareToken,
 	).Scan(&videoID, &title, &fileKey, &creator, &createdAt, &shareExpiresAt, &thumbnailKey, &sharePassword, &commentMode,
 		&transcriptKey, &transcriptJSON, &transcriptStatus,
@@ -2078,9 +2914,17 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 		&downloadEnabled,
 		&ctaText, &ctaUrl, &emailGateEnabled,
 		&summaryText, &chaptersJSON, &summaryStatus, &duration, &subscriptionPlan, &status,
-		&videoOrgID)
+		&videoOrgID, &visibility, &videoRepliesEnabled, &timedCTAsJSON, &questionsJSON)
 	if err != nil {
 		nonce := httputil.NonceFromContext(r.Context())
+		if h.embedOnly(r.Context(), shareToken) {
+			renderRestrictedPage(w, http.StatusForbidden, restrictedPageData{
+				Nonce:   nonce,
+				Heading: "This video can only be watched where it is embedded",
+				Message: "Its owner only allows playback on their own site. Open the page you found it on to watch.",
+			})
+			return
+		}
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		w.WriteHeader(http.StatusNotFound)
 		if err := notFoundPageTemplate.Execute(w, notFoundPageData{Nonce: nonce}); err != nil {
@@ -2123,6 +2967,15 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	switch h.decideVisibility(r, shareToken, visibility) {
+	case watchVisibilitySignIn:
+		http.Redirect(w, r, h.watchSignInURL(r.Context(), videoOrgID, "/watch/"+shareToken), http.StatusFound)
+		return
+	case watchVisibilityDenied:
+		renderAccessDeniedPage(w, nonce)
+		return
+	}
+
 	if sharePassword != nil {
 		if !hasValidWatchCookie(r, h.hmacSecret, shareToken, *sharePassword) {
 			w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -2139,12 +2992,13 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if emailGateEnabled {
-		if _, ok := hasValidEmailGateCookie(r, h.hmacSecret, shareToken); !ok {
+		if !h.passesVideoEmailGate(r, shareToken) {
 			w.Header().Set("Content-Type", "text/html; charset=utf-8")
 			if err := emailGatePageTemplate.Execute(w, emailGatePageData{
 				Title:      title,
 				ShareToken: shareToken,
 				Nonce:      nonce,
+				GateFields: h.loadVideoGateFields(r.Context(), videoID),
 			}); err != nil {
 				slog.Error("watch-page: failed to render email gate page", "error", err)
 			}
@@ -2173,7 +3027,7 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 			Nonce:            nonce,
 			Branding:         branding,
 			ShareToken:       shareToken,
-			BaseURL:          h.baseURL,
+			BaseURL:          h.publicBaseURL(r),
 			Segments:         make([]TranscriptSegment, 0),
 			Chapters:         make([]Chapter, 0),
 			AnalyticsScript:  injectScriptNonce(h.analyticsScript, nonce),
@@ -2217,6 +3071,10 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 		_ = json.Unmarshal([]byte(*chaptersJSON), &chapterList)
 	}
 	chaptersJSONBytes, _ := json.Marshal(chapterList)
+	timedCTAs := decodeVideoCTAs(timedCTAsJSON)
+	timedCTAsJSONBytes, _ := json.Marshal(timedCTAs)
+	questions := decodePlayerQuestions(questionsJSON)
+	questionsJSONBytes, _ := json.Marshal(questions)
 
 	description := summaryStr
 	if description == "" {
@@ -2233,7 +3091,7 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonLD := buildVideoObjectJSONLD(title, description, h.baseURL, shareToken, createdAt, duration, downloadEnabled, thumbnailKey != nil)
+	jsonLD := buildVideoObjectJSONLD(title, description, h.publicBaseURL(r), shareToken, createdAt, duration, downloadEnabled, thumbnailKey != nil)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := watchPageTemplate.Execute(w, watchPageData{
@@ -2250,7 +3108,7 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 		TranscriptURL:      transcriptURL,
 		TranscriptStatus:   transcriptStatus,
 		Segments:           segments,
-		BaseURL:            h.baseURL,
+		BaseURL:            h.publicBaseURL(r),
 		ContentType:        contentType,
 		Branding:           branding,
 		AnalyticsScript:    injectScriptNonce(h.analyticsScript, nonce),
@@ -2263,6 +3121,10 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 		Summary:            summaryStr,
 		Chapters:           chapterList,
 		ChaptersJSON:       template.JS(chaptersJSONBytes),
+		TimedCTAs:          timedCTAs,
+		TimedCTAsJSON:      template.JS(timedCTAsJSONBytes),
+		Questions:          questions,
+		QuestionsJSON:      template.JS(questionsJSONBytes),
 		SummaryStatus:      summaryStatus,
 		Description:        description,
 		Duration:           duration,
@@ -2270,6 +3132,8 @@ func (h *Handler) WatchPage(w http.ResponseWriter, r *http.Request) {
 		JSONLD:             template.JS(jsonLD),
 		SubscriptionPlan:   subscriptionPlan,
 		VideoStatus:        status,
+		VideoReplies:       videoRepliesEnabled && commentMode != "disabled",
+		VideoReplyMaxSecs:  h.videoReplyMaxSeconds,
 	}); err != nil {
 		slog.Error("watch-page: failed to render watch page", "error", err)
 	}
@@ -2282,13 +3146,16 @@ func (h *Handler) WatchThumbnail(w http.ResponseWriter, r *http.Request) {
 	var shareExpiresAt *time.Time
 	var sharePassword *string
 	var emailGateEnabled bool
+	var visibility string
 
 	err := h.db.QueryRow(r.Context(),
-		`SELECT v.thumbnail_key, v.share_expires_at, v.share_password, v.email_gate_enabled
+		`SELECT v.thumbnail_key, v.share_expires_at, v.share_password, v.email_gate_enabled,
+		        COALESCE(v.visibility, f.visibility, 'public')
 		 FROM videos v
+		 LEFT JOIN folders f ON f.id = v.folder_id
 		 WHERE v.share_token = $1 AND v.status IN ('ready', 'processing')`,
 		shareToken,
-	).Scan(&thumbnailKey, &shareExpiresAt, &sharePassword, &emailGateEnabled)
+	).Scan(&thumbnailKey, &shareExpiresAt, &sharePassword, &emailGateEnabled, &visibility)
 	if err != nil {
 		http.NotFound(w, r)
 		return
@@ -2301,6 +3168,9 @@ func (h *Handler) WatchThumbnail(w http.ResponseWriter, r *http.Request) {
 
 	// The thumbnail of a screen recording is itself sensitive, so it may not
 	// outrun the password/email gate protecting the video (SR-08).
+	if !h.enforceVisibility(w, r, shareToken, visibility) {
+		return
+	}
 	if !h.enforceWatchAccess(w, r, shareToken, sharePassword, emailGateEnabled) {
 		return
 	}
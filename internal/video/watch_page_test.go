@@ -30,6 +30,10 @@ var watchPageColumns = []string{
 	"subscription_plan",
 	"status",
 	"organization_id",
+	"visibility",
+	"video_replies_enabled",
+	"timed_ctas",
+	"questions",
 }
 
 func watchPageRequest(shareToken string) *http.Request {
@@ -48,7 +52,7 @@ func serveWatchPage(handler *Handler, req *http.Request) *httptest.ResponseRecor
 
 func expectViewRecording(mock pgxmock.PgxPoolIface, videoID string) {
 	mock.ExpectExec(`INSERT INTO video_views`).
-		WithArgs(videoID, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs(videoID, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 }
 
@@ -122,6 +126,10 @@ func TestWatchPage_Expired_Returns410(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 
 	rec := serveWatchPage(handler, watchPageRequest(shareToken))
@@ -168,6 +176,10 @@ func TestWatchPage_Success_RendersVideoPlayer(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -235,6 +247,10 @@ func TestWatchPage_Success_RendersSpeedButtons(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -296,6 +312,10 @@ func TestWatchPage_WithThumbnail_RendersPosterAndOGImage(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -345,6 +365,10 @@ func TestWatchPage_WithoutThumbnail_NoPosterOrOGImage(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -394,6 +418,10 @@ func TestWatchPage_CommentsEnabled_RendersCommentForm(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -475,6 +503,10 @@ func TestWatchPage_CommentsDisabled_NoCommentForm(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -527,6 +559,10 @@ func TestWatchPage_NameRequired_RendersNameField(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -576,6 +612,10 @@ func TestWatchPage_NameEmailRequired_RendersBothFields(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -632,6 +672,10 @@ func TestWatchPage_TranscriptReady_RendersSegments(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -694,6 +738,10 @@ func TestWatchPage_TranscriptPending_ShowsQueueMessage(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -740,6 +788,10 @@ func TestWatchPage_TranscriptProcessing_ShowsProgressMessage(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -786,6 +838,10 @@ func TestWatchPage_TranscriptFailed_ShowsFailedMessage(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -832,6 +888,10 @@ func TestWatchPage_StorageError_Returns500(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -874,6 +934,10 @@ func TestWatchPage_PasswordProtected_NoCookie_ShowsPasswordForm(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 
 	rec := serveWatchPage(handler, watchPageRequest(shareToken))
@@ -928,6 +992,10 @@ func TestWatchPage_PasswordProtected_ValidCookie_ShowsPlayer(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -984,6 +1052,10 @@ func TestWatchPage_OGMetaTags(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1038,6 +1110,10 @@ func TestWatchPage_CrossOriginAttribute(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1084,6 +1160,10 @@ func TestWatchPage_PlaysInlineAttribute(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1130,6 +1210,10 @@ func TestWatchPage_CSPNonce(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1181,6 +1265,10 @@ func TestWatchPage_TitleInHTMLTitle(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1227,6 +1315,10 @@ func TestWatchPage_AutoplayScript(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1274,6 +1366,10 @@ func TestWatchPage_BrandingLogoLinksToSendrec(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1348,6 +1444,10 @@ func TestWatchPage_RecordsView(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1453,6 +1553,10 @@ func TestWatchPage_AnalyticsScriptRendered(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1504,6 +1608,10 @@ func TestWatchPage_NoAnalyticsWhenEmpty(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1551,6 +1659,10 @@ func TestWatchPage_ResponsiveCSS(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1600,6 +1712,10 @@ func TestWatchPage_SafariWebMWarningElement(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1649,6 +1765,10 @@ func TestWatchPage_DownloadEnabled_ShowsButton(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1698,6 +1818,10 @@ func TestWatchPage_DownloadDisabled_HidesButton(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1756,6 +1880,10 @@ func TestWatchPage_CustomCSS_Injected(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1804,6 +1932,10 @@ func TestWatchPage_NoCustomCSS(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -1851,6 +1983,10 @@ func TestWatchPage_NeverExpires(t *testing.T) {
 				"free",
 				"ready",
 				(*string)(nil),
+				"public",
+				false,
+				"[]",
+				"[]",
 			),
 		)
 	expectViewRecording(mock, "vid-1")
@@ -1901,6 +2037,10 @@ func TestWatchPage_RendersCtaCard(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "video-001")
 
@@ -1952,6 +2092,10 @@ func TestWatchPage_NoCtaWhenNotSet(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "video-001")
 
@@ -1997,6 +2141,10 @@ func TestWatchPage_MilestoneTrackingScript(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "video-001")
 
@@ -2047,6 +2195,10 @@ func TestWatchPage_EmailGate_ShowsForm(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 
 	rec := serveWatchPage(handler, watchPageRequest(shareToken))
@@ -2111,6 +2263,10 @@ func TestWatchPage_SummaryTab(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2180,6 +2336,10 @@ func TestWatchPage_ChaptersBar(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2238,6 +2398,10 @@ func TestWatchPage_NoChaptersBar_WhenEmpty(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2285,6 +2449,10 @@ func TestWatchPage_OGDescription_WithSummary(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2334,6 +2502,10 @@ func TestWatchPage_OGDescription_FallbackToCreator(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2388,6 +2560,10 @@ func TestWatchPage_TwitterCardTags(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2445,6 +2621,10 @@ func TestWatchPage_OGImageUsesProxyURL(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2503,6 +2683,10 @@ func TestWatchPage_OGUrl(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2546,8 +2730,8 @@ func TestWatchThumbnail_Redirects(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT v.thumbnail_key, v.share_expires_at`).
 		WithArgs("validtoken12").
-		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled"}).
-			AddRow(&thumbKey, &expiresAt, (*string)(nil), false))
+		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled", "visibility"}).
+			AddRow(&thumbKey, &expiresAt, (*string)(nil), false, "public"))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/watch/validtoken12/thumbnail", nil)
 	rec := serveWatchThumbnail(handler, req)
@@ -2601,8 +2785,8 @@ func TestWatchThumbnail_NoThumbnail(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT v.thumbnail_key, v.share_expires_at`).
 		WithArgs("nothumbtoken").
-		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled"}).
-			AddRow((*string)(nil), &expiresAt, (*string)(nil), false))
+		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled", "visibility"}).
+			AddRow((*string)(nil), &expiresAt, (*string)(nil), false, "public"))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/watch/nothumbtoken/thumbnail", nil)
 	rec := serveWatchThumbnail(handler, req)
@@ -2629,8 +2813,8 @@ func TestWatchThumbnail_Expired(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT v.thumbnail_key, v.share_expires_at`).
 		WithArgs("expiredtoken").
-		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled"}).
-			AddRow(&thumbKey, &expiredAt, (*string)(nil), false))
+		WillReturnRows(pgxmock.NewRows([]string{"thumbnail_key", "share_expires_at", "share_password", "email_gate_enabled", "visibility"}).
+			AddRow(&thumbKey, &expiredAt, (*string)(nil), false, "public"))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/watch/expiredtoken/thumbnail", nil)
 	rec := serveWatchThumbnail(handler, req)
@@ -2699,6 +2883,10 @@ func TestWatchPage_JSONLDVideoObject(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2756,6 +2944,10 @@ func TestWatchPage_JSONLDVideoObject_WithDownload(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2802,6 +2994,10 @@ func TestWatchPage_JSONLDVideoObject_NoDownload(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2847,6 +3043,10 @@ func TestWatchPage_CanonicalURL(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2893,6 +3093,10 @@ func TestWatchPage_MetaDescription(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -2938,6 +3142,10 @@ func TestWatchPageFreeUserAlwaysShowsSendRecBadge(t *testing.T) {
 			"free",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "video-id")
 
@@ -2989,6 +3197,10 @@ func TestWatchPageProUserCanRemoveSendRecBadge(t *testing.T) {
 			"pro",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "video-id")
 
@@ -3037,6 +3249,10 @@ func TestWatchPageBusinessUserCanRemoveSendRecBadge(t *testing.T) {
 			"business",
 			"ready",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "video-id")
 
@@ -3084,6 +3300,10 @@ func TestWatchPage_ProcessingStatus_ShowsProcessingState(t *testing.T) {
 			"free",
 			"processing",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-1")
 
@@ -3130,6 +3350,10 @@ func TestWatchPage_ProcessingStatus_HasMetaRefresh(t *testing.T) {
 			"free",
 			"processing",
 			(*string)(nil),
+			"public",
+			false,
+			"[]",
+			"[]",
 		))
 	expectViewRecording(mock, "vid-2")
 
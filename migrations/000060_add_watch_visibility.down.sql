@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS folder_allowed_viewers;
+DROP TABLE IF EXISTS video_allowed_viewers;
+ALTER TABLE folders DROP COLUMN visibility;
+ALTER TABLE videos DROP COLUMN visibility;
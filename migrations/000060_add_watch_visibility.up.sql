@@ -0,0 +1,19 @@
+-- NULL on a video means "inherit from the folder"; folders default to public.
+ALTER TABLE videos ADD COLUMN visibility TEXT
+  CHECK (visibility IN ('public', 'org', 'users'));
+ALTER TABLE folders ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public'
+  CHECK (visibility IN ('public', 'org', 'users'));
+
+CREATE TABLE video_allowed_viewers (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (video_id, email)
+);
+
+CREATE TABLE folder_allowed_viewers (
+    folder_id UUID NOT NULL REFERENCES folders(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (folder_id, email)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS viewer_email_verifications;
+DROP TABLE IF EXISTS video_email_gate_domains;
+ALTER TABLE videos DROP COLUMN IF EXISTS email_gate_verified;
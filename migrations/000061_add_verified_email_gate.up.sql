@@ -0,0 +1,20 @@
+ALTER TABLE videos ADD COLUMN email_gate_verified BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE video_email_gate_domains (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    domain TEXT NOT NULL,
+    PRIMARY KEY (video_id, domain)
+);
+
+CREATE TABLE viewer_email_verifications (
+    token_hash  TEXT PRIMARY KEY,
+    video_id    UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    email       TEXT NOT NULL,
+    code_hash   TEXT NOT NULL,
+    attempts    INTEGER NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_viewer_email_verifications_video_email ON viewer_email_verifications(video_id, email, created_at DESC);
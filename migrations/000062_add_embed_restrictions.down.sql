@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS video_embed_domains;
+ALTER TABLE videos DROP COLUMN IF EXISTS embed_signing_secret;
@@ -0,0 +1,7 @@
+ALTER TABLE videos ADD COLUMN embed_signing_secret TEXT;
+
+CREATE TABLE video_embed_domains (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    domain TEXT NOT NULL,
+    PRIMARY KEY (video_id, domain)
+);
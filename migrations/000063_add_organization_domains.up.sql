@@ -0,0 +1,7 @@
+CREATE TABLE organization_domains (
+    organization_id    UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    hostname           TEXT NOT NULL UNIQUE,
+    verification_token TEXT NOT NULL,
+    verified_at        TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
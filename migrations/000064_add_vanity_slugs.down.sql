@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS playlist_slugs;
+DROP TABLE IF EXISTS video_slugs;
+ALTER TABLE playlists DROP COLUMN IF EXISTS slug;
+ALTER TABLE videos DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,22 @@
+ALTER TABLE videos ADD COLUMN slug TEXT;
+ALTER TABLE playlists ADD COLUMN slug TEXT;
+
+-- Every slug a video or playlist has held, keyed per organization. The
+-- current one is mirrored on the owning row; the rest resolve as redirects.
+CREATE TABLE video_slugs (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    slug TEXT NOT NULL,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, slug)
+);
+CREATE INDEX idx_video_slugs_video_id ON video_slugs(video_id);
+
+CREATE TABLE playlist_slugs (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    slug TEXT NOT NULL,
+    playlist_id UUID NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, slug)
+);
+CREATE INDEX idx_playlist_slugs_playlist_id ON playlist_slugs(playlist_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_video_comments_parent_id;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS author_key;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS resolved_at;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS edited_at;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,8 @@
+ALTER TABLE video_comments ADD COLUMN parent_id UUID REFERENCES video_comments(id) ON DELETE CASCADE;
+ALTER TABLE video_comments ADD COLUMN edited_at TIMESTAMPTZ;
+ALTER TABLE video_comments ADD COLUMN resolved_at TIMESTAMPTZ;
+-- Random per-viewer key from the signed comment-author cookie; lets an
+-- anonymous commenter edit what they wrote.
+ALTER TABLE video_comments ADD COLUMN author_key TEXT;
+
+CREATE INDEX idx_video_comments_parent_id ON video_comments(parent_id) WHERE parent_id IS NOT NULL;
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS comment_bans;
+
+-- Without a status column, held and rejected comments would go public.
+DELETE FROM video_comments WHERE status <> 'approved';
+
+DROP INDEX IF EXISTS idx_video_comments_moderation;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS moderated_by;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS spam_reasons;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS spam_score;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS status;
+
+UPDATE videos SET comment_mode = 'anonymous' WHERE comment_mode = 'moderated';
+ALTER TABLE videos DROP CONSTRAINT videos_comment_mode_check;
+ALTER TABLE videos ADD CONSTRAINT videos_comment_mode_check
+  CHECK (comment_mode IN ('disabled', 'anonymous', 'name_required', 'name_email_required'));
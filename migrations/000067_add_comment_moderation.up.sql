@@ -0,0 +1,32 @@
+ALTER TABLE videos DROP CONSTRAINT videos_comment_mode_check;
+ALTER TABLE videos ADD CONSTRAINT videos_comment_mode_check
+  CHECK (comment_mode IN ('disabled', 'anonymous', 'name_required', 'name_email_required', 'moderated'));
+
+ALTER TABLE video_comments ADD COLUMN status TEXT NOT NULL DEFAULT 'approved'
+  CHECK (status IN ('pending', 'approved', 'rejected'));
+ALTER TABLE video_comments ADD COLUMN spam_score DOUBLE PRECISION;
+ALTER TABLE video_comments ADD COLUMN spam_reasons TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE video_comments ADD COLUMN moderated_at TIMESTAMPTZ;
+ALTER TABLE video_comments ADD COLUMN moderated_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_video_comments_moderation ON video_comments(video_id, created_at) WHERE status <> 'approved';
+
+-- Authors barred from commenting: across an organization's videos, or across
+-- one user's personal videos when organization_id is NULL. An author is
+-- matched by account, email or anonymous author key.
+CREATE TABLE comment_bans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    owner_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    banned_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    author_email TEXT,
+    author_key TEXT,
+    author_name TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (organization_id IS NOT NULL OR owner_id IS NOT NULL),
+    CHECK (banned_user_id IS NOT NULL OR author_email IS NOT NULL OR author_key IS NOT NULL)
+);
+
+CREATE INDEX idx_comment_bans_organization_id ON comment_bans(organization_id) WHERE organization_id IS NOT NULL;
+CREATE INDEX idx_comment_bans_owner_id ON comment_bans(owner_id) WHERE organization_id IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE video_comments DROP COLUMN IF EXISTS annotation;
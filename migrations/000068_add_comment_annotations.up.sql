@@ -0,0 +1 @@
+ALTER TABLE video_comments ADD COLUMN annotation JSONB;
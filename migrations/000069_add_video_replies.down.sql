@@ -0,0 +1,9 @@
+DELETE FROM video_comments WHERE status = 'uploading';
+ALTER TABLE video_comments DROP CONSTRAINT video_comments_status_check;
+ALTER TABLE video_comments ADD CONSTRAINT video_comments_status_check
+  CHECK (status IN ('pending', 'approved', 'rejected'));
+ALTER TABLE video_comments DROP COLUMN IF EXISTS reply_video_id;
+
+DROP INDEX IF EXISTS idx_videos_parent_video_id;
+ALTER TABLE videos DROP COLUMN IF EXISTS parent_video_id;
+ALTER TABLE videos DROP COLUMN IF EXISTS video_replies_enabled;
@@ -0,0 +1,13 @@
+ALTER TABLE videos ADD COLUMN video_replies_enabled BOOLEAN NOT NULL DEFAULT false;
+
+-- A viewer's video reply is stored as a recording owned by the parent video's
+-- owner, so it goes through the same processing pipeline.
+ALTER TABLE videos ADD COLUMN parent_video_id UUID REFERENCES videos(id) ON DELETE CASCADE;
+CREATE INDEX idx_videos_parent_video_id ON videos(parent_video_id) WHERE parent_video_id IS NOT NULL;
+
+ALTER TABLE video_comments ADD COLUMN reply_video_id UUID REFERENCES videos(id) ON DELETE CASCADE;
+
+-- 'uploading' holds a video reply's comment until its clip has been uploaded.
+ALTER TABLE video_comments DROP CONSTRAINT video_comments_status_check;
+ALTER TABLE video_comments ADD CONSTRAINT video_comments_status_check
+  CHECK (status IN ('uploading', 'pending', 'approved', 'rejected'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_cta_clicks_cta_id;
+ALTER TABLE cta_clicks DROP COLUMN IF EXISTS cta_id;
+DROP TABLE IF EXISTS video_ctas;
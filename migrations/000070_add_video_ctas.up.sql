@@ -0,0 +1,26 @@
+-- Timed calls to action: buttons pinned to a corner of the player and
+-- hotspots over a region of the frame, each visible during a time window.
+CREATE TABLE video_ctas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL DEFAULT 'button' CHECK (kind IN ('button', 'hotspot')),
+    text TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL,
+    start_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    end_seconds DOUBLE PRECISION,
+    position TEXT NOT NULL DEFAULT 'bottom-right'
+        CHECK (position IN ('top-left', 'top-right', 'bottom-left', 'bottom-right', 'center')),
+    style TEXT NOT NULL DEFAULT 'primary' CHECK (style IN ('primary', 'secondary', 'outline')),
+    -- Hotspot region as fractions of the video frame.
+    region_x DOUBLE PRECISION,
+    region_y DOUBLE PRECISION,
+    region_width DOUBLE PRECISION,
+    region_height DOUBLE PRECISION,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_video_ctas_video_id ON video_ctas(video_id, start_seconds);
+
+ALTER TABLE cta_clicks ADD COLUMN cta_id UUID REFERENCES video_ctas(id) ON DELETE SET NULL;
+CREATE INDEX idx_cta_clicks_cta_id ON cta_clicks(cta_id) WHERE cta_id IS NOT NULL;
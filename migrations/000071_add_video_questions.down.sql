@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS question_answers;
+DROP TABLE IF EXISTS video_questions;
@@ -0,0 +1,31 @@
+-- Knowledge checks: the player pauses at time_seconds and asks a
+-- multiple-choice question. A NULL correct_option makes it an ungraded poll.
+CREATE TABLE video_questions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    time_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    prompt TEXT NOT NULL,
+    options TEXT[] NOT NULL,
+    correct_option INTEGER CHECK (correct_option >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_video_questions_video_id ON video_questions(video_id, time_seconds);
+
+-- viewer_key is the email-gate email when known, otherwise the viewer hash,
+-- so each viewer's first answer is the one that is scored.
+CREATE TABLE question_answers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    question_id UUID NOT NULL REFERENCES video_questions(id) ON DELETE CASCADE,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    viewer_key TEXT NOT NULL,
+    viewer_hash TEXT NOT NULL,
+    email TEXT,
+    option_index INTEGER NOT NULL,
+    is_correct BOOLEAN,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (question_id, viewer_key)
+);
+
+CREATE INDEX idx_question_answers_video_id ON question_answers(video_id, created_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS playlist_viewers;
+ALTER TABLE viewer_email_verifications DROP COLUMN IF EXISTS form_responses;
+ALTER TABLE video_viewers DROP COLUMN IF EXISTS form_responses;
+ALTER TABLE playlists DROP COLUMN IF EXISTS email_gate_fields;
+ALTER TABLE videos DROP COLUMN IF EXISTS email_gate_fields;
@@ -0,0 +1,15 @@
+ALTER TABLE videos ADD COLUMN email_gate_fields JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE playlists ADD COLUMN email_gate_fields JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE video_viewers ADD COLUMN form_responses JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE viewer_email_verifications ADD COLUMN form_responses JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE playlist_viewers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    playlist_id UUID NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    viewer_hash TEXT NOT NULL,
+    form_responses JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE(playlist_id, email)
+);
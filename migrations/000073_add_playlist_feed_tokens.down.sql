@@ -0,0 +1 @@
+DROP TABLE IF EXISTS playlist_feed_tokens;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_cta_clicks_created_at;
+DROP INDEX IF EXISTS idx_view_milestones_created_at;
+DROP INDEX IF EXISTS idx_video_views_video_created;
+DROP INDEX IF EXISTS idx_video_views_created_at;
+DROP TABLE IF EXISTS analytics_rollup_state;
+DROP TABLE IF EXISTS video_viewer_first_seen;
+DROP TABLE IF EXISTS video_daily_stats;
@@ -0,0 +1,39 @@
+CREATE TABLE video_daily_stats (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    unique_viewers INTEGER NOT NULL DEFAULT 0,
+    new_viewers INTEGER NOT NULL DEFAULT 0,
+    watch_seconds BIGINT NOT NULL DEFAULT 0,
+    milestone_25 INTEGER NOT NULL DEFAULT 0,
+    milestone_50 INTEGER NOT NULL DEFAULT 0,
+    milestone_75 INTEGER NOT NULL DEFAULT 0,
+    milestone_100 INTEGER NOT NULL DEFAULT 0,
+    cta_clicks INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (video_id, day)
+);
+
+CREATE INDEX idx_video_daily_stats_day ON video_daily_stats(day);
+
+CREATE TABLE video_viewer_first_seen (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    viewer_hash TEXT NOT NULL,
+    first_seen_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (video_id, viewer_hash)
+);
+
+CREATE INDEX idx_video_viewer_first_seen_seen ON video_viewer_first_seen(video_id, first_seen_at);
+
+CREATE TABLE analytics_rollup_state (
+    name TEXT PRIMARY KEY,
+    watermark TIMESTAMPTZ NOT NULL
+);
+
+-- Rows recorded before this migration are picked up by `sendrec backfill-analytics`.
+INSERT INTO analytics_rollup_state (name, watermark) VALUES ('daily', now());
+
+CREATE INDEX idx_video_views_created_at ON video_views(created_at);
+CREATE INDEX idx_video_views_video_created ON video_views(video_id, created_at);
+CREATE INDEX idx_view_milestones_created_at ON view_milestones(created_at);
+CREATE INDEX idx_cta_clicks_created_at ON cta_clicks(created_at);
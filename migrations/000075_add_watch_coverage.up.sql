@@ -0,0 +1,14 @@
+CREATE TABLE watch_coverage (
+    id BIGSERIAL PRIMARY KEY,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    viewer_hash TEXT NOT NULL,
+    start_second INTEGER NOT NULL,
+    end_second INTEGER NOT NULL,
+    plays INTEGER NOT NULL DEFAULT 1,
+    compacted BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (start_second >= 0 AND end_second > start_second AND plays > 0)
+);
+
+CREATE INDEX idx_watch_coverage_video_viewer ON watch_coverage(video_id, viewer_hash);
+CREATE INDEX idx_watch_coverage_pending ON watch_coverage(video_id, viewer_hash) WHERE NOT compacted;
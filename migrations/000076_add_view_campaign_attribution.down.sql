@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_video_views_campaign;
+
+ALTER TABLE video_views
+    DROP COLUMN IF EXISTS ref,
+    DROP COLUMN IF EXISTS utm_content,
+    DROP COLUMN IF EXISTS utm_term,
+    DROP COLUMN IF EXISTS utm_campaign,
+    DROP COLUMN IF EXISTS utm_medium,
+    DROP COLUMN IF EXISTS utm_source;
@@ -0,0 +1,9 @@
+ALTER TABLE video_views
+    ADD COLUMN utm_source TEXT,
+    ADD COLUMN utm_medium TEXT,
+    ADD COLUMN utm_campaign TEXT,
+    ADD COLUMN utm_term TEXT,
+    ADD COLUMN utm_content TEXT,
+    ADD COLUMN ref TEXT;
+
+CREATE INDEX idx_video_views_campaign ON video_views(video_id, utm_campaign) WHERE utm_campaign IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_video_views_confirmed_at;
+DROP INDEX IF EXISTS idx_video_views_unconfirmed;
+
+ALTER TABLE video_views
+    DROP COLUMN IF EXISTS confirmed_at,
+    DROP COLUMN IF EXISTS bot_reason,
+    DROP COLUMN IF EXISTS is_bot;
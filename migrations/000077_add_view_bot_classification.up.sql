@@ -0,0 +1,9 @@
+ALTER TABLE video_views
+    ADD COLUMN is_bot BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN bot_reason TEXT,
+    ADD COLUMN confirmed_at TIMESTAMPTZ;
+
+CREATE INDEX idx_video_views_unconfirmed ON video_views (video_id, viewer_hash)
+    WHERE bot_reason = 'no_playback';
+CREATE INDEX idx_video_views_confirmed_at ON video_views (confirmed_at)
+    WHERE confirmed_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS analytics_salts;
+
+ALTER TABLE organizations
+    DROP COLUMN IF EXISTS analytics_privacy_mode;
@@ -0,0 +1,8 @@
+ALTER TABLE organizations
+    ADD COLUMN analytics_privacy_mode BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE analytics_salts (
+    day DATE PRIMARY KEY,
+    salt BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,16 @@
+CREATE TABLE session_events (
+    id BIGSERIAL PRIMARY KEY,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    session_id TEXT NOT NULL,
+    viewer_hash TEXT NOT NULL,
+    event TEXT NOT NULL CHECK (event IN ('play', 'pause', 'seek', 'ratechange', 'segment', 'ended')),
+    -- Milliseconds since the player page loaded, as measured by the player.
+    offset_ms INTEGER NOT NULL CHECK (offset_ms >= 0),
+    position REAL NOT NULL,
+    from_position REAL,
+    playback_rate REAL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_session_events_session ON session_events(video_id, session_id, offset_ms);
+CREATE INDEX idx_session_events_video_created ON session_events(video_id, created_at DESC);
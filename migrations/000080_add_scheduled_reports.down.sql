@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scheduled_report_recipients;
+DROP TABLE IF EXISTS scheduled_reports;
@@ -0,0 +1,30 @@
+CREATE TABLE scheduled_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    frequency TEXT NOT NULL CHECK (frequency IN ('weekly', 'monthly')),
+    -- At most one of folder_id and tag_id narrows the report; with neither it
+    -- covers the whole personal library or organization.
+    folder_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+    tag_id UUID REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (folder_id IS NULL OR tag_id IS NULL)
+);
+CREATE INDEX idx_scheduled_reports_user_id ON scheduled_reports(user_id);
+CREATE INDEX idx_scheduled_reports_organization_id ON scheduled_reports(organization_id);
+
+CREATE TABLE scheduled_report_recipients (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    report_id UUID NOT NULL REFERENCES scheduled_reports(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL CHECK (channel IN ('email', 'slack')),
+    email TEXT,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    next_send_at TIMESTAMPTZ NOT NULL,
+    last_sent_at TIMESTAMPTZ,
+    CHECK ((channel = 'email') = (email IS NOT NULL))
+);
+CREATE UNIQUE INDEX idx_scheduled_report_recipients_unique
+    ON scheduled_report_recipients(report_id, channel, lower(COALESCE(email, '')));
+CREATE INDEX idx_scheduled_report_recipients_next_send ON scheduled_report_recipients(next_send_at);
@@ -0,0 +1,13 @@
+-- One row per viewer per video per UTC day they watched, so unique viewers
+-- over any range are a distinct count instead of a sum of daily counts.
+-- progress is the share of the video (in percent) the viewer newly reached
+-- that day, from their playback milestones.
+CREATE TABLE video_viewer_days (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    viewer_hash TEXT NOT NULL,
+    progress SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (video_id, day, viewer_hash)
+);
+
+CREATE INDEX idx_video_viewer_days_day ON video_viewer_days(day);
@@ -0,0 +1 @@
+ALTER TABLE scheduled_report_recipients DROP COLUMN IF EXISTS claimed_until;
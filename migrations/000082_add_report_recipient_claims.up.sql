@@ -0,0 +1,3 @@
+-- While a worker builds and sends a report it leases the recipient until
+-- claimed_until; next_send_at only advances once the report is delivered.
+ALTER TABLE scheduled_report_recipients ADD COLUMN claimed_until TIMESTAMPTZ;
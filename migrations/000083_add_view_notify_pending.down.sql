@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_video_views_notify_pending;
+CREATE INDEX IF NOT EXISTS idx_video_views_unconfirmed ON video_views (video_id, viewer_hash)
+    WHERE bot_reason = 'no_playback';
+ALTER TABLE video_views DROP COLUMN IF EXISTS notify_pending;
@@ -0,0 +1,9 @@
+-- A view's owner notification waits for the viewer's first playback beacon,
+-- so link scanners that load the page but never play are not announced.
+ALTER TABLE video_views ADD COLUMN notify_pending BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE video_views SET notify_pending = true WHERE bot_reason = 'no_playback';
+
+DROP INDEX IF EXISTS idx_video_views_unconfirmed;
+CREATE INDEX idx_video_views_notify_pending ON video_views (video_id, viewer_hash)
+    WHERE notify_pending;
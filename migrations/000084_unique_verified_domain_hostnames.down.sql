@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_organization_domains_verified_hostname;
+
+-- Keep the verified claim, or the oldest one, for each hostname.
+DELETE FROM organization_domains a
+USING organization_domains b
+WHERE a.hostname = b.hostname AND a.organization_id <> b.organization_id
+  AND a.verified_at IS NULL
+  AND (b.verified_at IS NOT NULL OR a.created_at > b.created_at
+       OR (a.created_at = b.created_at AND a.organization_id > b.organization_id));
+
+ALTER TABLE organization_domains ADD CONSTRAINT organization_domains_hostname_key UNIQUE (hostname);
@@ -0,0 +1,6 @@
+-- A hostname belongs to a workspace only once it has proved control over DNS.
+-- Unverified claims may overlap, so naming a hostname can't squat it.
+ALTER TABLE organization_domains DROP CONSTRAINT IF EXISTS organization_domains_hostname_key;
+
+CREATE UNIQUE INDEX idx_organization_domains_verified_hostname ON organization_domains (hostname)
+    WHERE verified_at IS NOT NULL;
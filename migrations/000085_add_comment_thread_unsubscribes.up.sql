@@ -0,0 +1,6 @@
+CREATE TABLE comment_thread_unsubscribes (
+    comment_id UUID NOT NULL REFERENCES video_comments(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (comment_id, email)
+);
@@ -17,6 +17,7 @@ import { VideoDetail } from "./pages/VideoDetail";
 import { Playlists } from "./pages/Playlists";
 import { PlaylistDetail } from "./pages/PlaylistDetail";
 import { OrgSettings } from "./pages/OrgSettings";
+import { OrgAnalytics } from "./pages/OrgAnalytics";
 import { AcceptInvite } from "./pages/AcceptInvite";
 import { useTheme } from "./hooks/useTheme";
 
@@ -135,6 +136,14 @@ export function App() {
           </ProtectedRoute>
         }
       />
+      <Route
+        path="/organizations/:id/analytics"
+        element={
+          <ProtectedRoute>
+            <OrgAnalytics />
+          </ProtectedRoute>
+        }
+      />
       <Route path="*" element={<NotFound />} />
     </Routes>
   );
@@ -98,6 +98,29 @@ async function apiFetch<T>(
   return (await response.json()) as T;
 }
 
+// apiStream opens a long-lived response, such as a server-sent events stream,
+// with the same auth and workspace headers as apiFetch.
+async function apiStream(path: string, signal: AbortSignal): Promise<Response> {
+  const headers = new Headers({ Accept: "text/event-stream" });
+  const orgId = getCurrentOrgId();
+  if (orgId) {
+    headers.set("X-Organization-Id", orgId);
+  }
+  if (accessToken) {
+    headers.set("Authorization", `Bearer ${accessToken}`);
+  }
+
+  let response = await fetch(path, { headers, signal });
+  if (response.status === 401 && accessToken && (await tryRefreshToken())) {
+    headers.set("Authorization", `Bearer ${accessToken}`);
+    response = await fetch(path, { headers, signal });
+  }
+  if (!response.ok || !response.body) {
+    throw new ApiError(response.status, response.statusText);
+  }
+  return response;
+}
+
 async function tryRefreshToken(): Promise<boolean> {
   try {
     if (!refreshPromise) {
@@ -113,4 +136,4 @@ async function tryRefreshToken(): Promise<boolean> {
   }
 }
 
-export { ApiError, setAccessToken, getAccessToken, apiFetch, tryRefreshToken };
+export { ApiError, setAccessToken, getAccessToken, apiFetch, apiStream, tryRefreshToken };
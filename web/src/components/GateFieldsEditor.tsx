@@ -0,0 +1,125 @@
+import { useEffect, useState } from "react";
+import type { GateField, GateFieldType } from "../types/video";
+
+interface GateFieldsEditorProps {
+  fields: GateField[];
+  onSave: (fields: GateField[]) => Promise<void>;
+}
+
+const MAX_FIELDS = 10;
+
+const inputStyle = {
+  padding: "6px 8px",
+  background: "var(--color-bg)",
+  border: "1px solid var(--color-border)",
+  borderRadius: 6,
+  color: "var(--color-text)",
+  fontSize: 13,
+};
+
+// GateFieldsEditor edits the extra lead-capture inputs shown below the email
+// address on a video or playlist email gate.
+export function GateFieldsEditor({ fields, onSave }: GateFieldsEditorProps) {
+  const [draft, setDraft] = useState<GateField[]>(fields);
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    setDraft(fields);
+  }, [fields]);
+
+  function update(index: number, changes: Partial<GateField>) {
+    setDraft((prev) => prev.map((f, i) => (i === index ? { ...f, ...changes } : f)));
+  }
+
+  function addField() {
+    setDraft((prev) => [...prev, { key: "", type: "text", label: "", required: false }]);
+  }
+
+  async function save() {
+    setSaving(true);
+    try {
+      await onSave(
+        draft.map((f) => ({
+          ...f,
+          options: f.type === "select" ? (f.options ?? []).filter((o) => o.trim() !== "") : undefined,
+        })),
+      );
+    } finally {
+      setSaving(false);
+    }
+  }
+
+  const dirty = JSON.stringify(draft) !== JSON.stringify(fields);
+
+  return (
+    <div style={{ display: "flex", flexDirection: "column", gap: 8, marginTop: 8 }}>
+      <span style={{ fontSize: 13, color: "var(--color-text-secondary)" }}>
+        Email is always asked for. Add fields to collect more about each viewer.
+      </span>
+      {draft.map((field, i) => (
+        <div key={i} style={{ display: "flex", gap: 8, alignItems: "center", flexWrap: "wrap" }}>
+          <input
+            type="text"
+            placeholder="Label (e.g. Company)"
+            value={field.label}
+            onChange={(e) => update(i, { label: e.target.value })}
+            maxLength={100}
+            aria-label={`Field ${i + 1} label`}
+            style={{ ...inputStyle, flex: 1, minWidth: 160 }}
+          />
+          <select
+            value={field.type}
+            onChange={(e) => update(i, { type: e.target.value as GateFieldType })}
+            aria-label={`Field ${i + 1} type`}
+            style={inputStyle}
+          >
+            <option value="text">Text</option>
+            <option value="textarea">Long text</option>
+            <option value="select">Dropdown</option>
+            <option value="checkbox">Checkbox</option>
+          </select>
+          <label style={{ fontSize: 13, display: "flex", gap: 4, alignItems: "center" }}>
+            <input
+              type="checkbox"
+              checked={field.required}
+              onChange={(e) => update(i, { required: e.target.checked })}
+            />
+            Required
+          </label>
+          <button
+            onClick={() => setDraft((prev) => prev.filter((_, j) => j !== i))}
+            className="detail-btn detail-btn--danger"
+          >
+            Remove
+          </button>
+          {field.type === "select" && (
+            <input
+              type="text"
+              placeholder="Options, comma separated"
+              value={(field.options ?? []).join(", ")}
+              onChange={(e) => update(i, { options: e.target.value.split(",").map((o) => o.trimStart()) })}
+              aria-label={`Field ${i + 1} options`}
+              style={{ ...inputStyle, width: "100%" }}
+            />
+          )}
+        </div>
+      ))}
+      <div style={{ display: "flex", gap: 8 }}>
+        {draft.length < MAX_FIELDS && (
+          <button onClick={addField} className="detail-btn">
+            Add field
+          </button>
+        )}
+        {dirty && (
+          <button
+            onClick={save}
+            disabled={saving || draft.some((f) => !f.label.trim())}
+            className="detail-btn detail-btn--accent"
+          >
+            {saving ? "Saving..." : "Save fields"}
+          </button>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -333,6 +333,25 @@ describe("Layout", () => {
     expect(orgSettingsLink).toHaveAttribute("href", "/organizations/org-1/settings");
   });
 
+  it("shows Workspace Analytics link for org admins", () => {
+    mockUseOrganization.mockReturnValue({
+      orgs: [
+        { id: "org-1", name: "Acme Corp", slug: "acme", subscriptionPlan: "free", role: "admin", memberCount: 3 },
+      ],
+      selectedOrg: { id: "org-1", name: "Acme Corp", slug: "acme", subscriptionPlan: "free", role: "admin", memberCount: 3 },
+      selectedOrgId: "org-1",
+      switchOrg: mockSwitchOrg,
+      createOrg: mockCreateOrg,
+      refreshOrgs: mockRefreshOrgs,
+      loading: false,
+    });
+    renderLayout("/organizations/org-1/analytics");
+    const link = screen.getByRole("link", { name: "Workspace Analytics" });
+    expect(link).toHaveAttribute("href", "/organizations/org-1/analytics");
+    expect(link).toHaveClass("nav-link--active");
+    expect(screen.getByRole("link", { name: "Analytics" })).not.toHaveClass("nav-link--active");
+  });
+
   it("hides Workspace Settings link when no org is selected", () => {
     renderLayout();
     expect(screen.queryByRole("link", { name: "Workspace Settings" })).not.toBeInTheDocument();
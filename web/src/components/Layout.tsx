@@ -46,7 +46,9 @@ export function Layout({ children }: LayoutProps) {
 
   function isActive(path: string): boolean {
     if (path === "/analytics") {
-      return location.pathname === "/analytics" || location.pathname.endsWith("/analytics");
+      return location.pathname.startsWith("/videos/")
+        ? location.pathname.endsWith("/analytics")
+        : location.pathname === "/analytics";
     }
     return location.pathname === path;
   }
@@ -295,6 +297,16 @@ export function Layout({ children }: LayoutProps) {
             </Link>
           )}
 
+          {selectedOrg && (selectedOrg.role === "owner" || selectedOrg.role === "admin") && (
+            <Link
+              to={`/organizations/${selectedOrgId}/analytics`}
+              className={`nav-link${isActive(`/organizations/${selectedOrgId}/analytics`) ? " nav-link--active" : ""}`}
+              onClick={handleNavClick}
+            >
+              Workspace Analytics
+            </Link>
+          )}
+
           <button
             className="nav-theme-toggle"
             onClick={toggleTheme}
@@ -6,12 +6,33 @@ import { Analytics } from "./Analytics";
 import { expectNoA11yViolations } from "../test-utils/a11y";
 
 const mockApiFetch = vi.fn();
+const mockApiStream = vi.fn();
 
 vi.mock("../api/client", () => ({
+  ApiError: class ApiError extends Error {
+    status = 0;
+  },
   apiFetch: (...args: unknown[]) => mockApiFetch(...args),
+  apiStream: (...args: unknown[]) => mockApiStream(...args),
   getAccessToken: () => "test-token",
 }));
 
+function liveStream(...events: Record<string, unknown>[]) {
+  const chunks = events.map((e) =>
+    new TextEncoder().encode(`event: ${e.type}\ndata: ${JSON.stringify(e)}\n\n`),
+  );
+  return {
+    body: {
+      getReader: () => ({
+        read: () => {
+          const value = chunks.shift();
+          return value ? Promise.resolve({ value, done: false }) : new Promise(() => {});
+        },
+      }),
+    },
+  };
+}
+
 function makeVideoAnalyticsData(overrides: Record<string, unknown> = {}) {
   return {
     summary: {
@@ -68,6 +89,8 @@ function makeVideoAnalyticsData(overrides: Record<string, unknown> = {}) {
       { name: "Mobile", percentage: 15 },
       { name: "Tablet", percentage: 5 },
     ],
+    retention: overrides.retention ?? null,
+    campaigns: overrides.campaigns ?? [],
   };
 }
 
@@ -91,6 +114,7 @@ function makeDashboardData(overrides: Record<string, unknown> = {}) {
       { id: "v1", title: "Intro Video", views: 120, uniqueViews: 80, thumbnailUrl: "/api/watch/abc/thumbnail", completion: 75 },
       { id: "v2", title: "Demo Recording", views: 90, uniqueViews: 60, thumbnailUrl: "", completion: 55 },
     ],
+    campaigns: overrides.campaigns ?? [],
   };
 }
 
@@ -119,6 +143,8 @@ function renderDashboard() {
 describe("Analytics", () => {
   beforeEach(() => {
     mockApiFetch.mockReset();
+    mockApiStream.mockReset();
+    mockApiStream.mockReturnValue(new Promise(() => {}));
   });
 
   afterEach(() => {
@@ -309,6 +335,33 @@ describe("Analytics", () => {
       expect(segments.length).toBe(50);
     });
 
+    it("renders the retention curve instead of the heatmap when coverage exists", async () => {
+      mockApiFetch.mockResolvedValueOnce(makeVideoAnalyticsData({
+        retention: {
+          viewers: 4,
+          duration: 120,
+          bucketSeconds: 1,
+          points: [
+            { second: 0, viewers: 4, retention: 100, rewatches: 0 },
+            { second: 60, viewers: 2, retention: 50, rewatches: 1 },
+          ],
+          hotspots: [{ start: 60, end: 70, rewatches: 1, chapter: "Demo" }],
+          dropOffs: [{ second: 30, lost: 2, percentage: 50, chapter: "Intro" }],
+          chapters: [
+            { title: "Intro", start: 0, end: 60, startRetention: 100, endRetention: 50, rewatches: 0 },
+            { title: "Demo", start: 60, end: 120, startRetention: 50, endRetention: 50, rewatches: 1 },
+          ],
+        },
+      }));
+      const { container } = renderVideoAnalytics();
+
+      await waitFor(() => {
+        expect(screen.getByText("Audience Retention")).toBeInTheDocument();
+      });
+      expect(screen.getByText("0:30 (Intro): 50% of viewers left")).toBeInTheDocument();
+      expect(container.querySelectorAll(".heatmap-segment").length).toBe(0);
+    });
+
     it("does not crash when heatmap is null", async () => {
       mockApiFetch.mockResolvedValueOnce(makeVideoAnalyticsData({
         heatmap: null,
@@ -507,9 +560,147 @@ describe("Analytics", () => {
 
       expect(screen.getByText("Export CSV")).toBeInTheDocument();
     });
+
+    it("opens a viewer session timeline", async () => {
+      const user = userEvent.setup();
+      mockApiFetch.mockImplementation((path: string) => {
+        if (path === "/api/videos/v1/analytics/sessions") {
+          return Promise.resolve({
+            sessions: [
+              { sessionId: "s1", viewerHash: "h1", email: "ada@example.com", startedAt: "2026-02-10T10:00:00Z", lastEventAt: "2026-02-10T10:02:00Z", watchedSeconds: 95, seeks: 2, pauses: 1, completed: true },
+            ],
+          });
+        }
+        if (path === "/api/videos/v1/analytics/sessions/s1") {
+          return Promise.resolve({
+            sessionId: "s1", viewerHash: "h1", email: "ada@example.com", startedAt: "2026-02-10T10:00:00Z",
+            videoDuration: 120, watchedSeconds: 95, rewatchedSeconds: 10,
+            events: [
+              { type: "play", offsetMs: 0, position: 0, at: "2026-02-10T10:00:00Z" },
+              { type: "seek", offsetMs: 30000, position: 70, from: 30, at: "2026-02-10T10:00:30Z" },
+              { type: "cta_click", offsetMs: 60000, position: null, ctaText: "Book a demo", at: "2026-02-10T10:01:00Z" },
+            ],
+            watched: [{ start: 0, end: 30, plays: 1 }, { start: 70, end: 120, plays: 1 }],
+            skipped: [{ start: 30, end: 70 }],
+            seeks: [{ from: 30, to: 70, offsetMs: 30000, direction: "forward" }],
+            pauses: [],
+          });
+        }
+        return Promise.resolve(makeVideoAnalyticsData());
+      });
+      renderVideoAnalytics();
+
+      await user.click(await screen.findByText("ada@example.com"));
+
+      expect(await screen.findByText("Jumped from 0:30 to 1:10")).toBeInTheDocument();
+      expect(screen.getByText("Clicked “Book a demo”")).toBeInTheDocument();
+      expect(screen.getByRole("img", { name: "Watched, rewatched and skipped parts of the video" })).toBeInTheDocument();
+    });
   });
 
   describe("Dashboard view", () => {
+    it("shows live presence and refreshes when events arrive", async () => {
+      mockApiFetch.mockResolvedValue(makeDashboardData());
+      mockApiStream.mockResolvedValueOnce(
+        liveStream(
+          { type: "presence", videoId: "v1", watching: 2, at: "2026-02-10T10:00:00Z" },
+          { type: "presence", videoId: "v2", watching: 1, at: "2026-02-10T10:00:00Z" },
+          { type: "view", videoId: "v1", title: "Demo", at: "2026-02-10T10:00:01Z" },
+        ),
+      );
+      renderDashboard();
+
+      expect(await screen.findByText("3 watching now")).toBeInTheDocument();
+      expect(mockApiStream).toHaveBeenCalledWith("/api/analytics/dashboard/live", expect.any(AbortSignal));
+      await waitFor(() => {
+        expect(mockApiFetch).toHaveBeenCalledTimes(2);
+      }, { timeout: 3000 });
+    });
+
+    it("creates a scheduled report from the dashboard", async () => {
+      const user = userEvent.setup();
+      mockApiFetch.mockImplementation((path: string, init?: RequestInit) => {
+        if (path === "/api/analytics/reports" && init?.method === "POST") {
+          return Promise.resolve({ id: "r1", ...JSON.parse(init.body as string), createdAt: "2026-03-02T10:00:00Z" });
+        }
+        if (path === "/api/analytics/reports") return Promise.resolve([]);
+        if (path === "/api/folders") {
+          return Promise.resolve([{ id: "f1", name: "Sales", position: 0, videoCount: 3, createdAt: "2026-01-01T00:00:00Z" }]);
+        }
+        if (path === "/api/tags") return Promise.resolve([]);
+        return Promise.resolve(makeDashboardData());
+      });
+      renderDashboard();
+
+      await user.click(await screen.findByRole("button", { name: "Manage" }));
+      await user.type(screen.getByLabelText("Report name"), "Sales weekly");
+      await user.selectOptions(screen.getByLabelText("Videos"), "folder:f1");
+      await user.type(screen.getByLabelText("Email recipients"), "boss@example.com");
+      await user.click(screen.getByRole("checkbox", { name: "Also post to Slack" }));
+      await user.click(screen.getByRole("button", { name: "Create report" }));
+
+      const post = mockApiFetch.mock.calls.find(([, init]) => init?.method === "POST");
+      expect(JSON.parse(post![1].body)).toMatchObject({
+        name: "Sales weekly",
+        frequency: "weekly",
+        folderId: "f1",
+        tagId: null,
+        recipients: [{ channel: "email", email: "boss@example.com" }, { channel: "slack" }],
+      });
+      expect(await screen.findByText("Sales weekly")).toBeInTheDocument();
+      expect(screen.getByRole("button", { name: "Delete report Sales weekly" })).toBeInTheDocument();
+    });
+
+    it("refetches with bot traffic when the toggle is on", async () => {
+      const user = userEvent.setup();
+      mockApiFetch.mockResolvedValueOnce(makeDashboardData({ summary: { botViews: 17 } }));
+      renderDashboard();
+
+      await waitFor(() => {
+        expect(screen.getByText("17 bot views")).toBeInTheDocument();
+      });
+
+      mockApiFetch.mockResolvedValueOnce(makeDashboardData());
+      await user.click(screen.getByRole("checkbox", { name: "Show bot traffic" }));
+
+      await waitFor(() => {
+        expect(mockApiFetch).toHaveBeenCalledWith("/api/analytics/dashboard?range=7d&include_bots=true");
+      });
+    });
+
+    it("filters by a campaign row and clears the filter", async () => {
+      const user = userEvent.setup();
+      const campaigns = [
+        { source: "newsletter", medium: "email", campaign: "launch", term: "", content: "", ref: "", views: 40, uniqueViews: 31, percentage: 11.4 },
+        { source: "", medium: "", campaign: "", term: "", content: "", ref: "alice", views: 12, uniqueViews: 9, percentage: 3.4 },
+      ];
+      mockApiFetch.mockResolvedValueOnce(makeDashboardData({ campaigns }));
+      renderDashboard();
+
+      await waitFor(() => {
+        expect(screen.getByText("newsletter / email / launch")).toBeInTheDocument();
+      });
+      expect(screen.getByText("ref: alice")).toBeInTheDocument();
+
+      mockApiFetch.mockResolvedValueOnce(makeDashboardData({ campaigns: [campaigns[0]] }));
+      await user.click(screen.getByText("newsletter / email / launch"));
+
+      await waitFor(() => {
+        expect(mockApiFetch).toHaveBeenCalledWith(
+          "/api/analytics/dashboard?range=7d&utm_source=newsletter&utm_medium=email&utm_campaign=launch",
+        );
+      });
+      expect(await screen.findByText("Campaign: newsletter / email / launch")).toBeInTheDocument();
+
+      mockApiFetch.mockResolvedValueOnce(makeDashboardData({ campaigns }));
+      await user.click(screen.getByRole("button", { name: "Clear" }));
+
+      await waitFor(() => {
+        expect(screen.queryByText("Campaign: newsletter / email / launch")).not.toBeInTheDocument();
+      });
+      expect(mockApiFetch).toHaveBeenLastCalledWith("/api/analytics/dashboard?range=7d");
+    });
+
     it("renders dashboard stat cards", async () => {
       mockApiFetch.mockResolvedValueOnce(makeDashboardData());
       renderDashboard();
@@ -0,0 +1,53 @@
+import type { CampaignFilter, CampaignStat } from "./types";
+import { campaignLabel } from "./types";
+
+export function CampaignTable({
+  campaigns,
+  onSelect,
+}: {
+  campaigns: CampaignStat[];
+  onSelect: (filter: CampaignFilter) => void;
+}) {
+  return (
+    <div className="card" style={{ marginBottom: 16 }}>
+      <div className="card-header">
+        <h3 className="card-title" style={{ margin: 0 }}>Campaigns</h3>
+        <span className="card-subtitle">Select a row to filter</span>
+      </div>
+      {campaigns.map((c) => {
+        const label = campaignLabel(c);
+        return (
+          <button
+            key={label}
+            type="button"
+            className="referrer-row campaign-row"
+            onClick={() =>
+              onSelect({
+                source: c.source,
+                medium: c.medium,
+                campaign: c.campaign,
+                term: c.term,
+                content: c.content,
+                ref: c.ref,
+              })
+            }
+          >
+            <span className="referrer-label" title={label}>
+              {label}
+            </span>
+            <div className="referrer-bar-track">
+              <div
+                className="referrer-bar-fill"
+                style={{
+                  width: `${c.percentage}%`,
+                  minWidth: c.views > 0 ? 2 : 0,
+                }}
+              />
+            </div>
+            <span className="referrer-pct">{c.views}</span>
+          </button>
+        );
+      })}
+    </div>
+  );
+}
@@ -1,16 +1,29 @@
 import { Link } from "react-router-dom";
-import type { DashboardData, Range } from "./types";
-import { RANGE_SUBTITLES, formatWatchTime } from "./types";
+import type { CampaignFilter, DashboardData, Range } from "./types";
+import { RANGE_SUBTITLES, formatBotViews, formatWatchTime } from "./types";
 import { StatCard } from "./StatCard";
 import { CssBarChart } from "./CssBarChart";
+import { CampaignTable } from "./CampaignTable";
 
-export function DashboardView({ data, range }: { data: DashboardData; range: Range }) {
+export function DashboardView({
+  data,
+  range,
+  onSelectCampaign,
+}: {
+  data: DashboardData;
+  range: Range;
+  onSelectCampaign: (filter: CampaignFilter) => void;
+}) {
   const hasViews = data.summary.totalViews > 0;
 
   return (
     <>
       <div className="analytics-stats">
-        <StatCard label="Total Views" value={data.summary.totalViews} />
+        <StatCard
+          label="Total Views"
+          value={data.summary.totalViews}
+          sub={formatBotViews(data.summary.botViews)}
+        />
         <StatCard
           label="Unique Viewers"
           value={data.summary.uniqueViews}
@@ -98,6 +111,10 @@ export function DashboardView({ data, range }: { data: DashboardData; range: Ran
         </div>
       )}
 
+      {hasViews && data.campaigns && data.campaigns.length > 0 && (
+        <CampaignTable campaigns={data.campaigns} onSelect={onSelectCampaign} />
+      )}
+
       {hasViews && data.daily.length > 0 && (
         <div className="card">
           <div className="card-header">
@@ -0,0 +1,104 @@
+import { formatDuration } from "../../utils/format";
+import type { RetentionCurveData } from "./types";
+
+const WIDTH = 600;
+const HEIGHT = 160;
+
+// RetentionCurve plots the share of viewers still watching at each point of
+// the video, with chapter boundaries, drop-offs and rewatched spans marked.
+export function RetentionCurve({ retention }: { retention: RetentionCurveData }) {
+  const { duration, points } = retention;
+  if (points.length === 0 || duration <= 0) return null;
+
+  const x = (second: number) => (second / duration) * WIDTH;
+  const y = (pct: number) => HEIGHT - (pct / 100) * HEIGHT;
+  const line = points
+    .map((p) => `${x(p.second).toFixed(1)},${y(p.retention).toFixed(1)}`)
+    .join(" ");
+
+  return (
+    <div className="retention-curve">
+      <svg
+        viewBox={`0 0 ${WIDTH} ${HEIGHT}`}
+        preserveAspectRatio="none"
+        className="retention-curve-chart"
+        role="img"
+        aria-label="Audience retention curve"
+      >
+        {retention.hotspots.map((h) => (
+          <rect
+            key={`hot-${h.start}`}
+            x={x(h.start)}
+            y={0}
+            width={Math.max(x(h.end) - x(h.start), 1)}
+            height={HEIGHT}
+            className="retention-curve-hotspot"
+          />
+        ))}
+        {retention.chapters.slice(1).map((c) => (
+          <line
+            key={`ch-${c.start}`}
+            x1={x(c.start)}
+            x2={x(c.start)}
+            y1={0}
+            y2={HEIGHT}
+            className="retention-curve-chapter"
+          />
+        ))}
+        <polyline points={line} className="retention-curve-line" fill="none" />
+      </svg>
+      <div className="heatmap-labels">
+        <span className="heatmap-label">0:00</span>
+        <span className="heatmap-label">{formatDuration(duration / 2)}</span>
+        <span className="heatmap-label">{formatDuration(duration)}</span>
+      </div>
+
+      {retention.dropOffs.length > 0 && (
+        <div className="retention-curve-notes">
+          <strong>Biggest drop-offs</strong>
+          {retention.dropOffs.map((d) => (
+            <div key={`drop-${d.second}`}>
+              {formatDuration(d.second)}
+              {d.chapter ? ` (${d.chapter})` : ""}: {d.percentage}% of viewers left
+            </div>
+          ))}
+        </div>
+      )}
+
+      {retention.hotspots.length > 0 && (
+        <div className="retention-curve-notes">
+          <strong>Most rewatched</strong>
+          {retention.hotspots.map((h) => (
+            <div key={`rw-${h.start}`}>
+              {formatDuration(h.start)}–{formatDuration(h.end)}
+              {h.chapter ? ` (${h.chapter})` : ""}: {h.rewatches} rewatches
+            </div>
+          ))}
+        </div>
+      )}
+
+      {retention.chapters.length > 0 && (
+        <table className="retention-curve-chapters">
+          <thead>
+            <tr>
+              <th>Chapter</th>
+              <th>Starts</th>
+              <th>Retention</th>
+            </tr>
+          </thead>
+          <tbody>
+            {retention.chapters.map((c) => (
+              <tr key={`row-${c.start}`}>
+                <td>{c.title}</td>
+                <td>{formatDuration(c.start)}</td>
+                <td>
+                  {c.startRetention}% → {c.endRetention}%
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      )}
+    </div>
+  );
+}
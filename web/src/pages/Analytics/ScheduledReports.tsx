@@ -0,0 +1,193 @@
+import { type FormEvent, useState } from "react";
+import { apiFetch } from "../../api/client";
+import type { Folder, Tag } from "../../types/video";
+import type { ReportRecipient, ScheduledReport } from "./types";
+
+function browserTimezone(): string {
+  try {
+    return Intl.DateTimeFormat().resolvedOptions().timeZone || "UTC";
+  } catch {
+    return "UTC";
+  }
+}
+
+function describeScope(report: ScheduledReport, folders: Folder[], tags: Tag[]): string {
+  if (report.folderId) {
+    return `Folder: ${folders.find((f) => f.id === report.folderId)?.name ?? "unknown"}`;
+  }
+  if (report.tagId) {
+    return `Tag: ${tags.find((t) => t.id === report.tagId)?.name ?? "unknown"}`;
+  }
+  return "All videos";
+}
+
+function describeRecipients(recipients: ReportRecipient[]): string {
+  return recipients.map((r) => (r.channel === "slack" ? "Slack" : r.email)).join(", ");
+}
+
+// ScheduledReports manages the weekly and monthly analytics reports sent by
+// email and Slack. It loads nothing until the panel is opened.
+export function ScheduledReports() {
+  const [open, setOpen] = useState(false);
+  const [reports, setReports] = useState<ScheduledReport[]>([]);
+  const [folders, setFolders] = useState<Folder[]>([]);
+  const [tags, setTags] = useState<Tag[]>([]);
+  const [name, setName] = useState("");
+  const [frequency, setFrequency] = useState<"weekly" | "monthly">("weekly");
+  const [scope, setScope] = useState("");
+  const [emails, setEmails] = useState("");
+  const [slack, setSlack] = useState(false);
+  const [error, setError] = useState("");
+
+  async function load() {
+    try {
+      const [reportsData, foldersData, tagsData] = await Promise.all([
+        apiFetch<ScheduledReport[]>("/api/analytics/reports"),
+        apiFetch<Folder[]>("/api/folders"),
+        apiFetch<Tag[]>("/api/tags"),
+      ]);
+      setReports(reportsData ?? []);
+      setFolders(foldersData ?? []);
+      setTags(tagsData ?? []);
+    } catch {
+      setReports([]);
+    }
+  }
+
+  function toggle() {
+    if (!open) load();
+    setOpen(!open);
+  }
+
+  async function handleCreate(e: FormEvent) {
+    e.preventDefault();
+    setError("");
+    const timezone = browserTimezone();
+    const recipients: ReportRecipient[] = emails
+      .split(/[,\s]+/)
+      .filter(Boolean)
+      .map((email) => ({ channel: "email", email, timezone }));
+    if (slack) recipients.push({ channel: "slack", timezone });
+
+    const [kind, scopeId] = scope.split(":");
+    try {
+      const created = await apiFetch<ScheduledReport>("/api/analytics/reports", {
+        method: "POST",
+        body: JSON.stringify({
+          name: name.trim(),
+          frequency,
+          folderId: kind === "folder" ? scopeId : null,
+          tagId: kind === "tag" ? scopeId : null,
+          recipients,
+        }),
+      });
+      if (created) setReports((prev) => [...prev, created]);
+      setName("");
+      setEmails("");
+      setSlack(false);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to create report");
+    }
+  }
+
+  async function handleDelete(id: string) {
+    try {
+      await apiFetch(`/api/analytics/reports/${id}`, { method: "DELETE" });
+      setReports((prev) => prev.filter((r) => r.id !== id));
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to delete report");
+    }
+  }
+
+  return (
+    <div className="card" style={{ marginBottom: 16 }}>
+      <div className="card-header">
+        <h3 className="card-title" style={{ margin: 0 }}>Scheduled Reports</h3>
+        <button type="button" className="btn-export" aria-expanded={open} onClick={toggle}>
+          {open ? "Hide" : "Manage"}
+        </button>
+      </div>
+
+      {open && (
+        <>
+          {reports.length > 0 && (
+            <ul className="report-list">
+              {reports.map((r) => (
+                <li key={r.id} className="report-row">
+                  <div>
+                    <strong>{r.name}</strong>
+                    <div className="card-subtitle">
+                      {r.frequency === "weekly" ? "Weekly, Mondays" : "Monthly, on the 1st"} at 09:00
+                      {` · ${describeScope(r, folders, tags)} · ${describeRecipients(r.recipients)}`}
+                    </div>
+                  </div>
+                  <button
+                    type="button"
+                    className="detail-btn detail-btn--danger"
+                    aria-label={`Delete report ${r.name}`}
+                    onClick={() => handleDelete(r.id)}
+                  >
+                    Delete
+                  </button>
+                </li>
+              ))}
+            </ul>
+          )}
+
+          <form className="report-form" onSubmit={handleCreate}>
+            <label className="form-field">
+              <span className="form-label">Report name</span>
+              <input
+                className="form-input"
+                value={name}
+                maxLength={100}
+                onChange={(e) => setName(e.target.value)}
+                required
+              />
+            </label>
+            <label className="form-field">
+              <span className="form-label">Frequency</span>
+              <select
+                className="form-input"
+                value={frequency}
+                onChange={(e) => setFrequency(e.target.value as "weekly" | "monthly")}
+              >
+                <option value="weekly">Weekly</option>
+                <option value="monthly">Monthly</option>
+              </select>
+            </label>
+            <label className="form-field">
+              <span className="form-label">Videos</span>
+              <select className="form-input" value={scope} onChange={(e) => setScope(e.target.value)}>
+                <option value="">All videos</option>
+                {folders.map((f) => (
+                  <option key={f.id} value={`folder:${f.id}`}>Folder: {f.name}</option>
+                ))}
+                {tags.map((t) => (
+                  <option key={t.id} value={`tag:${t.id}`}>Tag: {t.name}</option>
+                ))}
+              </select>
+            </label>
+            <label className="form-field">
+              <span className="form-label">Email recipients</span>
+              <input
+                className="form-input"
+                value={emails}
+                placeholder="alice@example.com, bob@example.com"
+                onChange={(e) => setEmails(e.target.value)}
+              />
+            </label>
+            <label className="report-slack">
+              <input type="checkbox" checked={slack} onChange={(e) => setSlack(e.target.checked)} />
+              Also post to Slack
+            </label>
+            {error && <p className="report-error" role="alert">{error}</p>}
+            <button type="submit" className="detail-btn detail-btn--accent">
+              Create report
+            </button>
+          </form>
+        </>
+      )}
+    </div>
+  );
+}
@@ -0,0 +1,188 @@
+import { useEffect, useState } from "react";
+import { apiFetch } from "../../api/client";
+import { formatDuration } from "../../utils/format";
+import type { SessionEvent, SessionSummary, SessionTimelineData } from "./types";
+import { formatWatchTime } from "./types";
+
+const WIDTH = 600;
+const HEIGHT = 24;
+
+function describeEvent(e: SessionEvent): string {
+  const at = e.position !== null ? formatDuration(e.position) : "";
+  switch (e.type) {
+    case "play":
+      return `Played from ${at}`;
+    case "pause":
+      return `Paused at ${at}`;
+    case "seek":
+      return `Jumped from ${formatDuration(e.from ?? 0)} to ${at}`;
+    case "ratechange":
+      return `Speed set to ${e.rate}x at ${at}`;
+    case "segment":
+      return `Watched ${formatDuration(e.from ?? 0)}–${at}`;
+    case "ended":
+      return "Finished the video";
+    case "cta_click":
+      return `Clicked ${e.ctaText ? `“${e.ctaText}”` : "the CTA"}`;
+  }
+}
+
+function formatOffset(ms: number): string {
+  return `+${formatDuration(ms / 1000)}`;
+}
+
+// SessionTimeline lists recent playback sessions and, for the selected one,
+// draws what the viewer watched, rewatched and skipped over the seek bar.
+export function SessionTimeline({ videoId }: { videoId: string }) {
+  const [sessions, setSessions] = useState<SessionSummary[]>([]);
+  const [selected, setSelected] = useState<SessionTimelineData | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    (async () => {
+      try {
+        const result = await apiFetch<{ sessions: SessionSummary[] }>(
+          `/api/videos/${videoId}/analytics/sessions`,
+        );
+        if (!cancelled) setSessions(result?.sessions ?? []);
+      } catch {
+        if (!cancelled) setSessions([]);
+      }
+    })();
+    return () => {
+      cancelled = true;
+    };
+  }, [videoId]);
+
+  async function openSession(sessionId: string) {
+    if (selected?.sessionId === sessionId) {
+      setSelected(null);
+      return;
+    }
+    try {
+      const result = await apiFetch<SessionTimelineData>(
+        `/api/videos/${videoId}/analytics/sessions/${sessionId}`,
+      );
+      if (result) setSelected(result);
+    } catch {
+      setSelected(null);
+    }
+  }
+
+  if (sessions.length === 0) return null;
+
+  return (
+    <div className="card" style={{ marginBottom: 16 }}>
+      <h3 className="card-title">Viewer Sessions</h3>
+      <table className="viewers-table">
+        <thead>
+          <tr>
+            <th>Viewer</th>
+            <th data-align="right">Watched</th>
+            <th data-align="right">Seeks</th>
+            <th data-align="right">Pauses</th>
+            <th>Started</th>
+          </tr>
+        </thead>
+        <tbody>
+          {sessions.map((s) => (
+            <tr
+              key={s.sessionId}
+              className="session-row"
+              data-selected={selected?.sessionId === s.sessionId}
+            >
+              <td>
+                <button
+                  type="button"
+                  className="session-open"
+                  aria-expanded={selected?.sessionId === s.sessionId}
+                  onClick={() => openSession(s.sessionId)}
+                >
+                  {s.email ?? <span className="viewer-anonymous">Anonymous</span>}
+                </button>
+                {s.completed && <span className="session-completed"> · finished</span>}
+              </td>
+              <td data-align="right">{formatWatchTime(s.watchedSeconds)}</td>
+              <td data-align="right">{s.seeks}</td>
+              <td data-align="right">{s.pauses}</td>
+              <td>{new Date(s.startedAt).toLocaleString("en-GB")}</td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+
+      {selected && <SessionDetail session={selected} />}
+    </div>
+  );
+}
+
+function SessionDetail({ session }: { session: SessionTimelineData }) {
+  const duration = session.videoDuration;
+  const x = (second: number) => (duration > 0 ? (second / duration) * WIDTH : 0);
+
+  return (
+    <div className="session-detail">
+      <div className="card-subtitle">
+        {formatWatchTime(session.watchedSeconds)} watched
+        {session.rewatchedSeconds > 0 && ` · ${formatWatchTime(session.rewatchedSeconds)} rewatched`}
+        {` · ${session.seeks.length} seeks`}
+      </div>
+      {duration > 0 && (
+        <svg
+          viewBox={`0 0 ${WIDTH} ${HEIGHT}`}
+          preserveAspectRatio="none"
+          className="session-track"
+          role="img"
+          aria-label="Watched, rewatched and skipped parts of the video"
+        >
+          {session.skipped.map((s) => (
+            <rect
+              key={`skip-${s.start}`}
+              x={x(s.start)}
+              y={0}
+              width={x(s.end) - x(s.start)}
+              height={HEIGHT}
+              className="session-track-skipped"
+            />
+          ))}
+          {session.watched.map((s) => (
+            <rect
+              key={`watch-${s.start}`}
+              x={x(s.start)}
+              y={0}
+              width={Math.max(x(s.end) - x(s.start), 1)}
+              height={HEIGHT}
+              className={(s.plays ?? 1) > 1 ? "session-track-rewatched" : "session-track-watched"}
+            />
+          ))}
+          {session.pauses.map((p) => (
+            <line
+              key={`pause-${p.offsetMs}`}
+              x1={x(p.position)}
+              x2={x(p.position)}
+              y1={0}
+              y2={HEIGHT}
+              className="session-track-pause"
+            />
+          ))}
+        </svg>
+      )}
+      {duration > 0 && (
+        <div className="heatmap-labels">
+          <span className="heatmap-label">0:00</span>
+          <span className="heatmap-label">{formatDuration(duration)}</span>
+        </div>
+      )}
+      <ol className="session-events">
+        {session.events
+          .filter((e) => e.type !== "segment")
+          .map((e, i) => (
+            <li key={`${e.type}-${e.offsetMs}-${i}`} data-type={e.type}>
+              <span className="session-event-offset">{formatOffset(e.offsetMs)}</span>
+              <span>{describeEvent(e)}</span>
+            </li>
+          ))}
+      </ol>
+    </div>
+  );
+}
@@ -1,11 +1,21 @@
 import { formatChartDate } from "../../utils/format";
-import type { AnalyticsData, Range, SortColumn, SortDirection } from "./types";
-import { RANGE_SUBTITLES } from "./types";
+import type {
+  AnalyticsData,
+  CampaignFilter,
+  Range,
+  SortColumn,
+  SortDirection,
+} from "./types";
+import { RANGE_SUBTITLES, formatBotViews } from "./types";
 import { StatCard } from "./StatCard";
 import { CssBarChart } from "./CssBarChart";
 import { ViewerTable } from "./ViewerTable";
+import { RetentionCurve } from "./RetentionCurve";
+import { CampaignTable } from "./CampaignTable";
+import { SessionTimeline } from "./SessionTimeline";
 
 export function VideoAnalyticsView({
+  videoId,
   data,
   range,
   sortColumn,
@@ -14,7 +24,9 @@ export function VideoAnalyticsView({
   onSort,
   onShowMore,
   sortIndicator,
+  onSelectCampaign,
 }: {
+  videoId: string;
   data: AnalyticsData;
   range: Range;
   sortColumn: SortColumn;
@@ -23,6 +35,7 @@ export function VideoAnalyticsView({
   onSort: (column: SortColumn) => void;
   onShowMore: () => void;
   sortIndicator: (column: SortColumn) => string;
+  onSelectCampaign: (filter: CampaignFilter) => void;
 }) {
   const hasViews = data.summary.totalViews > 0;
   const trends = range !== "all" ? data.trends : null;
@@ -34,6 +47,7 @@ export function VideoAnalyticsView({
           label="Total Views"
           value={data.summary.totalViews}
           trend={trends?.views ?? null}
+          sub={formatBotViews(data.summary.botViews)}
         />
         <StatCard
           label="Unique Views"
@@ -95,7 +109,19 @@ export function VideoAnalyticsView({
         </div>
       )}
 
-      {hasViews && data.heatmap && data.heatmap.length > 0 && (
+      {hasViews && data.retention && (
+        <div className="card" style={{ marginBottom: 16 }}>
+          <div className="card-header">
+            <h3 className="card-title" style={{ margin: 0 }}>Audience Retention</h3>
+            <span className="card-subtitle">
+              {data.retention.viewers} viewers · {data.retention.bucketSeconds}s resolution
+            </span>
+          </div>
+          <RetentionCurve retention={data.retention} />
+        </div>
+      )}
+
+      {hasViews && !data.retention && data.heatmap && data.heatmap.length > 0 && (
         <div className="card" style={{ marginBottom: 16 }}>
           <div className="card-header">
             <h3 className="card-title" style={{ margin: 0 }}>Viewer Retention</h3>
@@ -195,6 +221,66 @@ export function VideoAnalyticsView({
         </div>
       )}
 
+      {hasViews && data.campaigns && data.campaigns.length > 0 && (
+        <CampaignTable campaigns={data.campaigns} onSelect={onSelectCampaign} />
+      )}
+
+      {data.ctas && data.ctas.length > 0 && (
+        <div className="card" style={{ marginBottom: 16 }}>
+          <h3 className="card-title">Timed CTAs</h3>
+          {data.ctas.map((c) => (
+            <div key={c.id} className="breakdown-row">
+              <span className="breakdown-name">
+                {c.text || (c.kind === "hotspot" ? "Hotspot" : "Button")}
+              </span>
+              <span className="breakdown-pct">
+                {c.clicks} clicks · {c.uniqueClicks} unique
+              </span>
+            </div>
+          ))}
+        </div>
+      )}
+
+      {data.questions && data.questions.length > 0 && (
+        <div className="card" style={{ marginBottom: 16 }}>
+          <h3 className="card-title">Quiz &amp; polls</h3>
+          {data.questions.map((q) => (
+            <div key={q.id} style={{ marginBottom: 12 }}>
+              <div className="breakdown-row">
+                <span className="breakdown-name">{q.prompt}</span>
+                <span className="breakdown-pct">
+                  {q.responses} responses
+                  {q.correctOption !== null && q.responses > 0 &&
+                    ` · ${Math.round((q.correctResponses / q.responses) * 100)}% correct`}
+                </span>
+              </div>
+              {q.options.map((option, i) => (
+                <div key={i} className="breakdown-row">
+                  <span className="breakdown-name" style={{ paddingLeft: 12 }}>
+                    {option}
+                    {q.correctOption === i && " ✓"}
+                  </span>
+                  <span className="breakdown-pct">{q.optionCounts[i] ?? 0}</span>
+                </div>
+              ))}
+            </div>
+          ))}
+          {data.quizScores && data.quizScores.some((s) => s.graded > 0) && (
+            <>
+              <h3 className="card-title" style={{ marginTop: 8 }}>Quiz scores</h3>
+              {data.quizScores.filter((s) => s.graded > 0).map((s) => (
+                <div key={s.email || s.viewerHash} className="breakdown-row">
+                  <span className="breakdown-name">{s.email || "Anonymous viewer"}</span>
+                  <span className="breakdown-pct">
+                    {s.correct}/{s.graded} · {s.score.toFixed(0)}%
+                  </span>
+                </div>
+              ))}
+            </>
+          )}
+        </div>
+      )}
+
       {hasViews && data.viewers.length > 0 && (
         <ViewerTable
           viewers={data.viewers}
@@ -207,6 +293,8 @@ export function VideoAnalyticsView({
         />
       )}
 
+      {hasViews && <SessionTimeline videoId={videoId} />}
+
       {hasViews &&
         (data.browsers.length > 0 || data.devices.length > 0) && (
           <div className="card" style={{ marginBottom: 16 }}>
@@ -1,11 +1,31 @@
-import { useCallback, useEffect, useState } from "react";
+import { useCallback, useEffect, useRef, useState } from "react";
 import { Link, useParams, useNavigate } from "react-router-dom";
 import { apiFetch, getAccessToken } from "../../api/client";
-import type { View, Range, SortColumn, SortDirection, AnalyticsData, DashboardData } from "./types";
-import { RANGES, RANGE_LABELS } from "./types";
+import type {
+  View,
+  Range,
+  SortColumn,
+  SortDirection,
+  AnalyticsData,
+  DashboardData,
+  CampaignFilter,
+} from "./types";
+import {
+  RANGES,
+  RANGE_LABELS,
+  campaignLabel,
+  campaignQuery,
+  formatWatching,
+} from "./types";
 import { SkeletonLoading } from "./SkeletonLoading";
 import { VideoAnalyticsView } from "./VideoAnalyticsView";
 import { DashboardView } from "./DashboardView";
+import { ScheduledReports } from "./ScheduledReports";
+import { useLiveAnalytics } from "./useLiveAnalytics";
+
+// Live events arrive in bursts (a view is soon followed by milestones), so
+// refetching waits for the burst to settle.
+const LIVE_REFRESH_DELAY_MS = 2000;
 
 export function Analytics() {
   const { id } = useParams<{ id: string }>();
@@ -13,6 +33,10 @@ export function Analytics() {
 
   const [view, setView] = useState<View>(id ? "video" : "dashboard");
   const [range, setRange] = useState<Range>("7d");
+  const [campaignFilter, setCampaignFilter] = useState<CampaignFilter | null>(
+    null,
+  );
+  const [includeBots, setIncludeBots] = useState(false);
 
   const [videoData, setVideoData] = useState<AnalyticsData | null>(null);
   const [dashboardData, setDashboardData] = useState<DashboardData | null>(
@@ -31,47 +55,79 @@ export function Analytics() {
   }, [id]);
 
   const fetchData = useCallback(
-    async (currentView: View, currentRange: Range) => {
-      setLoading(true);
-      setError(false);
+    async (
+      currentView: View,
+      currentRange: Range,
+      currentFilter: CampaignFilter | null,
+      currentIncludeBots: boolean,
+      silent = false,
+    ) => {
+      if (!silent) {
+        setLoading(true);
+        setError(false);
+      }
+      const query = `range=${currentRange}${campaignQuery(currentFilter)}${currentIncludeBots ? "&include_bots=true" : ""}`;
       try {
         if (currentView === "video" && id) {
           const result = await apiFetch<AnalyticsData>(
-            `/api/videos/${id}/analytics?range=${currentRange}`,
+            `/api/videos/${id}/analytics?${query}`,
           );
           if (result) {
             setVideoData(result);
-          } else {
+          } else if (!silent) {
             setError(true);
           }
         } else {
           const result = await apiFetch<DashboardData>(
-            `/api/analytics/dashboard?range=${currentRange}`,
+            `/api/analytics/dashboard?${query}`,
           );
           if (result) {
             setDashboardData(result);
-          } else {
+          } else if (!silent) {
             setError(true);
           }
         }
       } catch {
-        setError(true);
+        if (!silent) setError(true);
       } finally {
-        setLoading(false);
+        if (!silent) setLoading(false);
       }
     },
     [id],
   );
 
   useEffect(() => {
-    fetchData(view, range);
-  }, [view, range, fetchData]);
+    fetchData(view, range, campaignFilter, includeBots);
+  }, [view, range, campaignFilter, includeBots, fetchData]);
+
+  const refreshTimer = useRef<ReturnType<typeof setTimeout> | undefined>(
+    undefined,
+  );
+  const handleLiveEvent = useCallback(() => {
+    clearTimeout(refreshTimer.current);
+    refreshTimer.current = setTimeout(
+      () => fetchData(view, range, campaignFilter, includeBots, true),
+      LIVE_REFRESH_DELAY_MS,
+    );
+  }, [view, range, campaignFilter, includeBots, fetchData]);
+
+  useEffect(() => () => clearTimeout(refreshTimer.current), []);
+
+  const live = useLiveAnalytics(
+    view === "video" && id
+      ? `/api/videos/${id}/analytics/live`
+      : view === "dashboard"
+        ? "/api/analytics/dashboard/live"
+        : null,
+    handleLiveEvent,
+  );
 
   function handleViewToggle(newView: View) {
     if (newView === view) return;
     if (newView === "video" && !id) return;
 
     setView(newView);
+    setCampaignFilter(null);
     setVisibleViewerCount(7);
     setSortColumn("date");
     setSortDirection("desc");
@@ -96,10 +152,11 @@ export function Analytics() {
   }
 
   async function handleExport() {
+    const query = `range=${range}${campaignQuery(campaignFilter)}${includeBots ? "&include_bots=true" : ""}`;
     const url =
       view === "video"
-        ? `/api/videos/${id}/analytics/export?range=${range}`
-        : `/api/analytics/dashboard/export?range=${range}`;
+        ? `/api/videos/${id}/analytics/export?${query}`
+        : `/api/analytics/dashboard/export?${query}`;
     const token = getAccessToken();
     try {
       const res = await fetch(url, {
@@ -164,6 +221,12 @@ export function Analytics() {
               Dashboard
             </button>
           </div>
+          {live.connected && (
+            <span className="live-indicator" role="status">
+              <span className="live-indicator-dot" aria-hidden="true" />
+              {formatWatching(live.watching)}
+            </span>
+          )}
         </div>
         <div style={{ display: "flex", alignItems: "center", gap: 8 }}>
           <div className="range-pills">
@@ -177,14 +240,32 @@ export function Analytics() {
               </button>
             ))}
           </div>
+          <label className="bot-toggle">
+            <input
+              type="checkbox"
+              checked={includeBots}
+              onChange={(e) => setIncludeBots(e.target.checked)}
+            />
+            Show bot traffic
+          </label>
           <button className="btn-export" onClick={handleExport}>
             Export CSV
           </button>
         </div>
       </div>
 
-      {view === "video" && videoData ? (
+      {campaignFilter && (
+        <div className="campaign-filter">
+          <span>Campaign: {campaignLabel(campaignFilter)}</span>
+          <button type="button" onClick={() => setCampaignFilter(null)}>
+            Clear
+          </button>
+        </div>
+      )}
+
+      {view === "video" && id && videoData ? (
         <VideoAnalyticsView
+          videoId={id}
           data={videoData}
           range={range}
           sortColumn={sortColumn}
@@ -195,9 +276,17 @@ export function Analytics() {
             setVisibleViewerCount((prev) => prev + 10)
           }
           sortIndicator={sortIndicator}
+          onSelectCampaign={setCampaignFilter}
         />
       ) : view === "dashboard" && dashboardData ? (
-        <DashboardView data={dashboardData} range={range} />
+        <>
+          <DashboardView
+            data={dashboardData}
+            range={range}
+            onSelectCampaign={setCampaignFilter}
+          />
+          <ScheduledReports />
+        </>
       ) : (
         <div className="page-container page-container--centered">
           <p style={{ color: "var(--color-text-secondary)", fontSize: 16 }}>
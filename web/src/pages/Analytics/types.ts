@@ -12,6 +12,7 @@ export interface AnalyticsSummary {
   peakDayViews: number;
   totalCtaClicks: number;
   ctaClickRate: number;
+  botViews?: number;
 }
 
 export interface DailyViews {
@@ -66,6 +67,132 @@ export interface DeviceStat {
   percentage: number;
 }
 
+export interface CtaClickStat {
+  id: string;
+  kind: "button" | "hotspot";
+  text: string;
+  clicks: number;
+  uniqueClicks: number;
+}
+
+export interface QuestionResult {
+  id: string;
+  prompt: string;
+  options: string[];
+  correctOption: number | null;
+  responses: number;
+  optionCounts: number[];
+  correctResponses: number;
+}
+
+export interface QuizScore {
+  email: string;
+  viewerHash: string;
+  answered: number;
+  graded: number;
+  correct: number;
+  score: number;
+}
+
+export interface RetentionPoint {
+  second: number;
+  viewers: number;
+  retention: number;
+  rewatches: number;
+}
+
+export interface RetentionHotspot {
+  start: number;
+  end: number;
+  rewatches: number;
+  chapter?: string;
+}
+
+export interface RetentionDropOff {
+  second: number;
+  lost: number;
+  percentage: number;
+  chapter?: string;
+}
+
+export interface ChapterRetention {
+  title: string;
+  start: number;
+  end: number;
+  startRetention: number;
+  endRetention: number;
+  rewatches: number;
+}
+
+export interface RetentionCurveData {
+  viewers: number;
+  duration: number;
+  bucketSeconds: number;
+  points: RetentionPoint[];
+  hotspots: RetentionHotspot[];
+  dropOffs: RetentionDropOff[];
+  chapters: ChapterRetention[];
+}
+
+export interface SessionSummary {
+  sessionId: string;
+  viewerHash: string;
+  email: string | null;
+  startedAt: string;
+  lastEventAt: string;
+  watchedSeconds: number;
+  seeks: number;
+  pauses: number;
+  completed: boolean;
+}
+
+export interface SessionSpan {
+  start: number;
+  end: number;
+  plays?: number;
+}
+
+export interface SessionEvent {
+  type: "play" | "pause" | "seek" | "ratechange" | "segment" | "ended" | "cta_click";
+  offsetMs: number;
+  position: number | null;
+  from?: number;
+  rate?: number;
+  ctaId?: string;
+  ctaText?: string;
+  at: string;
+}
+
+export interface SessionTimelineData {
+  sessionId: string;
+  viewerHash: string;
+  email: string | null;
+  startedAt: string;
+  videoDuration: number;
+  watchedSeconds: number;
+  rewatchedSeconds: number;
+  events: SessionEvent[];
+  watched: SessionSpan[];
+  skipped: SessionSpan[];
+  seeks: { from: number; to: number; offsetMs: number; direction: "forward" | "back" }[];
+  pauses: { position: number; offsetMs: number; durationMs: number | null }[];
+}
+
+export interface CampaignFilter {
+  source: string;
+  medium: string;
+  campaign: string;
+  term: string;
+  content: string;
+  ref: string;
+}
+
+export interface CampaignStat extends CampaignFilter {
+  views: number;
+  uniqueViews: number;
+  percentage: number;
+}
+
 export interface AnalyticsData {
   summary: AnalyticsSummary;
   daily: DailyViews[];
@@ -76,6 +203,12 @@ export interface AnalyticsData {
   referrers: Referrer[];
   browsers: BrowserStat[];
   devices: DeviceStat[];
+  ctas?: CtaClickStat[];
+  questions?: QuestionResult[];
+  quizScores?: QuizScore[];
+  retention?: RetentionCurveData | null;
+  campaigns?: CampaignStat[];
+  filter?: CampaignFilter | null;
 }
 
 export interface DashboardSummary {
@@ -85,6 +218,7 @@ export interface DashboardSummary {
   totalVideos: number;
   totalWatchTimeSeconds: number;
   avgCompletion: number;
+  botViews?: number;
 }
 
 export interface DashboardTopVideo {
@@ -100,6 +234,17 @@ export interface DashboardData {
   summary: DashboardSummary;
   daily: DailyViews[];
   topVideos: DashboardTopVideo[];
+  campaigns?: CampaignStat[];
+  filter?: CampaignFilter | null;
+}
+
+export interface LiveEvent {
+  type: "view" | "milestone" | "cta_click" | "comment" | "presence";
+  videoId: string;
+  title?: string;
+  watching?: number;
+  data?: Record<string, unknown>;
+  at: string;
 }
 
 export const RANGES: Range[] = ["7d", "30d", "90d", "all"];
@@ -118,6 +263,15 @@ export const RANGE_SUBTITLES: Record<Range, string> = {
   all: "All time",
 };
 
+export function formatWatching(watching: number): string {
+  return `${watching} watching now`;
+}
+
+export function formatBotViews(botViews?: number): string | undefined {
+  if (!botViews) return undefined;
+  return `${botViews} bot ${botViews === 1 ? "view" : "views"}`;
+}
+
 export function formatWatchTime(seconds: number): string {
   if (seconds < 60) return `${seconds}s`;
   const minutes = Math.floor(seconds / 60);
@@ -175,3 +329,42 @@ export function sortViewers(
     return direction === "asc" ? comparison : -comparison;
   });
 }
+
+const CAMPAIGN_PARAMS: [keyof CampaignFilter, string][] = [
+  ["source", "utm_source"],
+  ["medium", "utm_medium"],
+  ["campaign", "utm_campaign"],
+  ["term", "utm_term"],
+  ["content", "utm_content"],
+  ["ref", "ref"],
+];
+
+export function campaignQuery(filter: CampaignFilter | null): string {
+  if (!filter) return "";
+  return CAMPAIGN_PARAMS.filter(([key]) => filter[key])
+    .map(([key, param]) => `&${param}=${encodeURIComponent(filter[key])}`)
+    .join("");
+}
+
+export function campaignLabel(c: CampaignFilter): string {
+  const parts = [c.source, c.medium, c.campaign, c.term, c.content].filter(Boolean);
+  if (c.ref) parts.push(`ref: ${c.ref}`);
+  return parts.join(" / ");
+}
+
+export interface ReportRecipient {
+  channel: "email" | "slack";
+  email?: string;
+  timezone: string;
+  nextSendAt?: string;
+}
+
+export interface ScheduledReport {
+  id: string;
+  name: string;
+  frequency: "weekly" | "monthly";
+  folderId: string | null;
+  tagId: string | null;
+  recipients: ReportRecipient[];
+  createdAt: string;
+}
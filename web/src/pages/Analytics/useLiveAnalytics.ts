@@ -0,0 +1,82 @@
+import { useEffect, useRef, useState } from "react";
+import { ApiError, apiStream } from "../../api/client";
+import type { LiveEvent } from "./types";
+
+const RECONNECT_DELAY_MS = 5000;
+
+// useLiveAnalytics follows a live analytics stream, keeping a count of viewers
+// currently watching and passing every other event to onEvent. It reconnects
+// after dropped connections but gives up on client errors and when live
+// analytics are disabled on the server.
+export function useLiveAnalytics(
+  path: string | null,
+  onEvent: (event: LiveEvent) => void,
+): { connected: boolean; watching: number } {
+  const [connected, setConnected] = useState(false);
+  const [watching, setWatching] = useState<Record<string, number>>({});
+  const onEventRef = useRef(onEvent);
+
+  useEffect(() => {
+    onEventRef.current = onEvent;
+  }, [onEvent]);
+
+  useEffect(() => {
+    if (!path) return;
+    const streamPath = path;
+    const controller = new AbortController();
+    let retry: ReturnType<typeof setTimeout> | undefined;
+
+    async function connect() {
+      try {
+        const response = await apiStream(streamPath, controller.signal);
+        setWatching({});
+        setConnected(true);
+        const reader = response.body!.getReader();
+        const decoder = new TextDecoder();
+        let buffer = "";
+        for (;;) {
+          const { value, done } = await reader.read();
+          if (done) break;
+          buffer += decoder.decode(value, { stream: true });
+          let boundary = buffer.indexOf("\n\n");
+          while (boundary >= 0) {
+            const block = buffer.slice(0, boundary);
+            buffer = buffer.slice(boundary + 2);
+            boundary = buffer.indexOf("\n\n");
+            const data = block
+              .split("\n")
+              .filter((line) => line.startsWith("data: "))
+              .map((line) => line.slice(6))
+              .join("\n");
+            if (!data) continue;
+            const event = JSON.parse(data) as LiveEvent;
+            if (event.type === "presence") {
+              setWatching((prev) => ({ ...prev, [event.videoId]: event.watching ?? 0 }));
+            } else {
+              onEventRef.current(event);
+            }
+          }
+        }
+      } catch (err) {
+        if (controller.signal.aborted) return;
+        if (err instanceof ApiError && ((err.status >= 400 && err.status < 500) || err.status === 503)) {
+          setConnected(false);
+          return;
+        }
+      }
+      if (controller.signal.aborted) return;
+      setConnected(false);
+      retry = setTimeout(connect, RECONNECT_DELAY_MS);
+    }
+
+    connect();
+    return () => {
+      controller.abort();
+      clearTimeout(retry);
+      setConnected(false);
+    };
+  }, [path]);
+
+  const total = Object.values(watching).reduce((sum, n) => sum + n, 0);
+  return { connected, watching: total };
+}
@@ -18,6 +18,15 @@ export function Login() {
   const [ssoError, setSsoError] = useState("");
   const [ssoEnforcement, setSsoEnforcement] = useState<SsoEnforcement | null>(null);
 
+  function goToRedirect(redirect: string | null) {
+    // Watch and embed pages are rendered by the server, not the SPA router.
+    if (redirect && /^\/(watch|embed)\//.test(redirect)) {
+      window.location.assign(redirect);
+      return;
+    }
+    navigate(redirect || "/");
+  }
+
   useEffect(() => {
     fetch("/api/health")
       .then((res) => res.json())
@@ -49,8 +58,7 @@ export function Login() {
     if (token) {
       setAccessToken(token);
       window.history.replaceState({}, "", window.location.pathname);
-      const redirect = params.get("redirect");
-      navigate(redirect || "/");
+      goToRedirect(params.get("redirect"));
       return;
     }
 
@@ -79,8 +87,7 @@ export function Login() {
 
       if (result) {
         setAccessToken(result.accessToken);
-        const redirect = searchParams.get("redirect");
-        navigate(redirect || "/");
+        goToRedirect(searchParams.get("redirect"));
       }
     } catch (err) {
       if (err instanceof ApiError && err.status === 403 && err.message === "email_not_verified") {
@@ -0,0 +1,104 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+import { render, screen, waitFor } from "@testing-library/react";
+import userEvent from "@testing-library/user-event";
+import { MemoryRouter, Route, Routes } from "react-router-dom";
+import { OrgAnalytics } from "./OrgAnalytics";
+
+const mockNavigate = vi.fn();
+vi.mock("react-router-dom", async () => {
+  const actual = await vi.importActual("react-router-dom");
+  return { ...actual, useNavigate: () => mockNavigate };
+});
+
+const mockApiFetch = vi.fn();
+vi.mock("../api/client", () => ({
+  apiFetch: (...args: unknown[]) => mockApiFetch(...args),
+  getAccessToken: () => "token",
+}));
+
+const mockUseOrganization = vi.fn();
+vi.mock("../hooks/useOrganization", () => ({
+  useOrganization: () => mockUseOrganization(),
+}));
+
+function orgState(role: string) {
+  return {
+    orgs: [{ id: "org-1", name: "Acme Corp", slug: "acme", subscriptionPlan: "free", role, memberCount: 2 }],
+    selectedOrgId: "org-1",
+    loading: false,
+  };
+}
+
+const analytics = {
+  summary: {
+    totalViews: 150, uniqueViews: 100, totalWatchTimeSeconds: 4200, totalVideos: 5,
+    newVideos: 2, members: 2, activeMembers: 1, completionRate: 45, ctaClicks: 6,
+  },
+  members: [
+    { id: "user-1", name: "Alice", email: "alice@example.com", role: "owner", videos: 4, newVideos: 2, views: 120, uniqueViews: 80, watchTimeSeconds: 3600, completionRate: 50, ctaClicks: 6 },
+    { id: "user-2", name: "Bob", email: "bob@example.com", role: "member", videos: 1, newVideos: 0, views: 30, uniqueViews: 20, watchTimeSeconds: 600, completionRate: 25, ctaClicks: 0 },
+  ],
+  folders: [
+    { id: "folder-1", name: "Sales", videos: 3, newVideos: 1, views: 100, uniqueViews: 60, watchTimeSeconds: 3000, completionRate: 50, ctaClicks: 6 },
+  ],
+  tags: [],
+  adoption: [{ weekStart: "2026-03-02", userId: "user-1", videosCreated: 2 }],
+  topVideos: [
+    { id: "vid-1", title: "Quarterly update", ownerName: "Alice", views: 90, uniqueViews: 60, completionRate: 50, ctaClicks: 4 },
+  ],
+};
+
+function renderPage() {
+  return render(
+    <MemoryRouter initialEntries={["/organizations/org-1/analytics"]}>
+      <Routes>
+        <Route path="/organizations/:id/analytics" element={<OrgAnalytics />} />
+      </Routes>
+    </MemoryRouter>,
+  );
+}
+
+describe("OrgAnalytics", () => {
+  beforeEach(() => {
+    mockApiFetch.mockReset();
+    mockNavigate.mockReset();
+  });
+
+  it("shows member, folder, adoption and top video breakdowns", async () => {
+    mockUseOrganization.mockReturnValue(orgState("admin"));
+    mockApiFetch.mockResolvedValue(analytics);
+    renderPage();
+
+    expect(await screen.findByText("Quarterly update")).toBeInTheDocument();
+    expect(mockApiFetch).toHaveBeenCalledWith("/api/analytics/org?range=30d");
+    expect(screen.getByText("1 / 2")).toBeInTheDocument();
+    expect(screen.getByText("Bob")).toBeInTheDocument();
+    expect(screen.getByText("Sales")).toBeInTheDocument();
+    expect(screen.getByText("Alice (2)")).toBeInTheDocument();
+    expect(screen.queryByRole("heading", { name: "Tags" })).not.toBeInTheDocument();
+  });
+
+  it("refetches when the range changes", async () => {
+    const user = userEvent.setup();
+    mockUseOrganization.mockReturnValue(orgState("owner"));
+    mockApiFetch.mockResolvedValue(analytics);
+    renderPage();
+
+    await screen.findByText("Quarterly update");
+    await user.click(screen.getByRole("button", { name: "7d" }));
+
+    await waitFor(() => {
+      expect(mockApiFetch).toHaveBeenCalledWith("/api/analytics/org?range=7d");
+    });
+  });
+
+  it("redirects members who are not owners or admins", async () => {
+    mockUseOrganization.mockReturnValue(orgState("member"));
+    renderPage();
+
+    await waitFor(() => {
+      expect(mockNavigate).toHaveBeenCalledWith("/", { replace: true });
+    });
+    expect(mockApiFetch).not.toHaveBeenCalled();
+  });
+});
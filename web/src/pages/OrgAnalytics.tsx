@@ -0,0 +1,254 @@
+import { useEffect, useState } from "react";
+import { useNavigate, useParams } from "react-router-dom";
+import { apiFetch, getAccessToken } from "../api/client";
+import { useOrganization } from "../hooks/useOrganization";
+import { StatCard } from "./Analytics/StatCard";
+import type { Range } from "./Analytics/types";
+import { RANGES, RANGE_LABELS, formatWatchTime } from "./Analytics/types";
+
+interface OrgBreakdownRow {
+  id: string;
+  name: string;
+  email?: string;
+  role?: string;
+  videos: number;
+  newVideos: number;
+  views: number;
+  uniqueViews: number;
+  watchTimeSeconds: number;
+  completionRate: number;
+  ctaClicks: number;
+}
+
+interface OrgAnalyticsData {
+  summary: {
+    totalViews: number;
+    uniqueViews: number;
+    totalWatchTimeSeconds: number;
+    totalVideos: number;
+    newVideos: number;
+    members: number;
+    activeMembers: number;
+    completionRate: number;
+    ctaClicks: number;
+  };
+  members: OrgBreakdownRow[];
+  folders: OrgBreakdownRow[];
+  tags: OrgBreakdownRow[];
+  adoption: { weekStart: string; userId: string; videosCreated: number }[];
+  topVideos: {
+    id: string;
+    title: string;
+    ownerName: string;
+    views: number;
+    uniqueViews: number;
+    completionRate: number;
+    ctaClicks: number;
+  }[];
+}
+
+function BreakdownTable({ title, label, rows }: { title: string; label: string; rows: OrgBreakdownRow[] }) {
+  if (rows.length === 0) return null;
+  return (
+    <div className="card" style={{ marginBottom: 16 }}>
+      <h3 className="card-title">{title}</h3>
+      <table className="viewers-table">
+        <thead>
+          <tr>
+            <th>{label}</th>
+            <th data-align="right">Videos</th>
+            <th data-align="right">New</th>
+            <th data-align="right">Views</th>
+            <th data-align="right">Unique</th>
+            <th data-align="right">Watch Time</th>
+            <th data-align="right">Completion</th>
+            <th data-align="right">CTA Clicks</th>
+          </tr>
+        </thead>
+        <tbody>
+          {rows.map((row) => (
+            <tr key={row.id}>
+              <td>
+                {row.name}
+                {row.role && <span className="org-analytics-role"> · {row.role}</span>}
+              </td>
+              <td data-align="right">{row.videos}</td>
+              <td data-align="right">{row.newVideos}</td>
+              <td data-align="right">{row.views}</td>
+              <td data-align="right">{row.uniqueViews}</td>
+              <td data-align="right">{formatWatchTime(row.watchTimeSeconds)}</td>
+              <td data-align="right">{Math.round(row.completionRate)}%</td>
+              <td data-align="right">{row.ctaClicks}</td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+    </div>
+  );
+}
+
+// OrgAnalytics is the workspace-wide analytics page for owners and admins.
+export function OrgAnalytics() {
+  const { id: orgId } = useParams<{ id: string }>();
+  const navigate = useNavigate();
+  const { orgs, selectedOrgId, loading: orgsLoading } = useOrganization();
+  const role = orgs.find((o) => o.id === orgId)?.role;
+  const canView = role === "owner" || role === "admin";
+
+  const [range, setRange] = useState<Range>("30d");
+  const [data, setData] = useState<OrgAnalyticsData | null>(null);
+  const [error, setError] = useState("");
+
+  useEffect(() => {
+    if (orgsLoading) return;
+    if (!canView || selectedOrgId !== orgId) {
+      navigate("/", { replace: true });
+    }
+  }, [orgsLoading, canView, selectedOrgId, orgId, navigate]);
+
+  useEffect(() => {
+    if (!canView || selectedOrgId !== orgId) return;
+    let cancelled = false;
+    (async () => {
+      try {
+        const result = await apiFetch<OrgAnalyticsData>(`/api/analytics/org?range=${range}`);
+        if (!cancelled) {
+          setData(result ?? null);
+          setError("");
+        }
+      } catch (err) {
+        if (!cancelled) setError(err instanceof Error ? err.message : "Failed to load analytics");
+      }
+    })();
+    return () => {
+      cancelled = true;
+    };
+  }, [canView, selectedOrgId, orgId, range]);
+
+  async function handleExport() {
+    const token = getAccessToken();
+    const headers: Record<string, string> = {};
+    if (token) headers.Authorization = `Bearer ${token}`;
+    if (orgId) headers["X-Organization-Id"] = orgId;
+    try {
+      const res = await fetch(`/api/analytics/org/export?range=${range}`, { headers });
+      if (!res.ok) return;
+      const blob = await res.blob();
+      const anchor = document.createElement("a");
+      anchor.href = URL.createObjectURL(blob);
+      anchor.download = "workspace-analytics.csv";
+      anchor.click();
+      setTimeout(() => URL.revokeObjectURL(anchor.href), 1000);
+    } catch {
+      // Export failed silently
+    }
+  }
+
+  const memberNames = new Map((data?.members ?? []).map((m) => [m.id, m.name]));
+  const weeks = new Map<string, { weekStart: string; videos: number; members: string[] }>();
+  for (const a of data?.adoption ?? []) {
+    const week = weeks.get(a.weekStart) ?? { weekStart: a.weekStart, videos: 0, members: [] };
+    week.videos += a.videosCreated;
+    week.members.push(`${memberNames.get(a.userId) ?? "Former member"} (${a.videosCreated})`);
+    weeks.set(a.weekStart, week);
+  }
+
+  return (
+    <div className="page-container">
+      <div className="analytics-header">
+        <h1 style={{ color: "var(--color-text)", fontSize: 24, margin: 0 }}>Workspace Analytics</h1>
+        <div style={{ display: "flex", alignItems: "center", gap: 8 }}>
+          <div className="range-pills">
+            {RANGES.map((r) => (
+              <button
+                key={r}
+                className={`range-pill${range === r ? " range-pill--active" : ""}`}
+                onClick={() => setRange(r)}
+              >
+                {RANGE_LABELS[r]}
+              </button>
+            ))}
+          </div>
+          <button className="btn-export" onClick={handleExport}>
+            Export CSV
+          </button>
+        </div>
+      </div>
+
+      {error && <p className="status-message status-message--error">{error}</p>}
+
+      {data && (
+        <>
+          <div className="analytics-stats">
+            <StatCard label="Total Views" value={data.summary.totalViews} />
+            <StatCard label="Unique Viewers" value={data.summary.uniqueViews} />
+            <StatCard label="Watch Time" value={formatWatchTime(data.summary.totalWatchTimeSeconds)} />
+            <StatCard label="Completion" value={`${Math.round(data.summary.completionRate)}%`} />
+            <StatCard label="CTA Clicks" value={data.summary.ctaClicks} />
+            <StatCard
+              label="Active Members"
+              value={`${data.summary.activeMembers} / ${data.summary.members}`}
+              sub={`${data.summary.newVideos} new videos`}
+            />
+          </div>
+
+          {data.topVideos.length > 0 && (
+            <div className="card" style={{ marginBottom: 16 }}>
+              <h3 className="card-title">Most Watched</h3>
+              <table className="viewers-table">
+                <thead>
+                  <tr>
+                    <th>Video</th>
+                    <th>Owner</th>
+                    <th data-align="right">Views</th>
+                    <th data-align="right">Unique</th>
+                    <th data-align="right">Completion</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {data.topVideos.map((v) => (
+                    <tr key={v.id}>
+                      <td>{v.title}</td>
+                      <td>{v.ownerName}</td>
+                      <td data-align="right">{v.views}</td>
+                      <td data-align="right">{v.uniqueViews}</td>
+                      <td data-align="right">{Math.round(v.completionRate)}%</td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+            </div>
+          )}
+
+          <BreakdownTable title="Members" label="Member" rows={data.members} />
+          <BreakdownTable title="Folders" label="Folder" rows={data.folders} />
+          <BreakdownTable title="Tags" label="Tag" rows={data.tags} />
+
+          {weeks.size > 0 && (
+            <div className="card" style={{ marginBottom: 16 }}>
+              <h3 className="card-title">Adoption</h3>
+              <table className="viewers-table">
+                <thead>
+                  <tr>
+                    <th>Week of</th>
+                    <th data-align="right">Videos Created</th>
+                    <th>Members</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {[...weeks.values()].map((w) => (
+                    <tr key={w.weekStart}>
+                      <td>{new Date(`${w.weekStart}T00:00:00Z`).toLocaleDateString("en-GB", { timeZone: "UTC" })}</td>
+                      <td data-align="right">{w.videos}</td>
+                      <td>{w.members.join(", ")}</td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+            </div>
+          )}
+        </>
+      )}
+    </div>
+  );
+}
@@ -349,6 +349,31 @@ describe("OrgSettings", () => {
     });
   });
 
+  it("toggling analytics privacy calls PATCH /api/organizations/:id", async () => {
+    const user = userEvent.setup();
+    mockApiFetch
+      .mockResolvedValueOnce({ ...mockOrg, analyticsPrivacyMode: false })
+      .mockResolvedValueOnce([ownerMember, regularMember])
+      .mockResolvedValueOnce([])
+      .mockRejectedValueOnce(new Error("Not Found")) // billing
+      .mockResolvedValueOnce({ message: "Settings updated" }); // PATCH response
+    renderOrgSettings();
+
+    await waitFor(() => {
+      expect(screen.getByLabelText("Privacy-preserving analytics")).not.toBeChecked();
+    });
+
+    await user.click(screen.getByLabelText("Privacy-preserving analytics"));
+
+    await waitFor(() => {
+      expect(mockApiFetch).toHaveBeenCalledWith("/api/organizations/org-1", expect.objectContaining({
+        method: "PATCH",
+        body: JSON.stringify({ analyticsPrivacyMode: true }),
+      }));
+    });
+    expect(screen.getByLabelText("Privacy-preserving analytics")).toBeChecked();
+  });
+
   it("renders SSO card for business plan admin", async () => {
     mockUseOrganization.mockReturnValue({
       orgs: [{ id: "org-1", name: "Acme Corp", slug: "acme-corp", subscriptionPlan: "business", role: "admin", memberCount: 3 }],
@@ -71,6 +71,18 @@ export function GeneralSection({
     }
   }
 
+  async function handlePrivacyModeChange(enabled: boolean) {
+    setOrg((prev) => prev ? { ...prev, analyticsPrivacyMode: enabled } : prev);
+    try {
+      await apiFetch(`/api/organizations/${orgId}`, {
+        method: "PATCH",
+        body: JSON.stringify({ analyticsPrivacyMode: enabled }),
+      });
+    } catch {
+      setOrg((prev) => prev ? { ...prev, analyticsPrivacyMode: !enabled } : prev);
+    }
+  }
+
   function handleDeleteOrg() {
     setConfirmDialog({
       message: "Are you sure you want to delete this workspace? This action cannot be undone. All workspace data will be permanently deleted.",
@@ -168,6 +180,33 @@ export function GeneralSection({
         </div>
       )}
 
+      {canManage && (
+        <div className="card settings-section">
+          <h2>Analytics Privacy</h2>
+          <p className="card-description">
+            Count views without following viewers from one day to the next. Viewer IDs reset daily,
+            cities are not stored, and Do Not Track and Global Privacy Control are honored.
+          </p>
+          <div className="form-field" style={{ flexDirection: "row", alignItems: "center", gap: 8 }}>
+            <input
+              id="org-analytics-privacy"
+              type="checkbox"
+              checked={org.analyticsPrivacyMode ?? false}
+              onChange={(e) => handlePrivacyModeChange(e.target.checked)}
+              style={{ width: "auto" }}
+            />
+            <label htmlFor="org-analytics-privacy" className="form-label" style={{ margin: 0 }}>
+              Privacy-preserving analytics
+            </label>
+          </div>
+          {org.analyticsPrivacyMode && (
+            <p className="form-hint">
+              Unique and returning viewers are counted per day, so totals over longer ranges include repeat visitors.
+            </p>
+          )}
+        </div>
+      )}
+
       {isOwner && (
         <div className="card settings-section card--danger">
           <h2 style={{ color: "var(--color-error)" }}>Danger Zone</h2>
@@ -0,0 +1,126 @@
+import { useEffect, useState } from "react";
+import { apiFetch } from "../../api/client";
+import { copyToClipboard } from "../../utils/clipboard";
+
+interface FeedToken {
+  id: string;
+  label: string;
+  createdAt: string;
+  lastUsedAt: string | null;
+}
+
+interface CreatedFeedToken extends FeedToken {
+  feedUrl: string;
+}
+
+interface PlaylistFeedSubscribersProps {
+  playlistId: string;
+  showToast: (message: string) => void;
+}
+
+// PlaylistFeedSubscribers manages the per-subscriber tokens a gated playlist's
+// podcast feed requires. A token's feed URL is only shown right after it is
+// created.
+export function PlaylistFeedSubscribers({ playlistId, showToast }: PlaylistFeedSubscribersProps) {
+  const [tokens, setTokens] = useState<FeedToken[]>([]);
+  const [label, setLabel] = useState("");
+  const [created, setCreated] = useState<CreatedFeedToken | null>(null);
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    apiFetch<FeedToken[]>(`/api/playlists/${playlistId}/feed-tokens`)
+      .then((result) => setTokens(result ?? []))
+      .catch(() => setTokens([]));
+  }, [playlistId]);
+
+  async function addSubscriber() {
+    setSaving(true);
+    try {
+      const result = await apiFetch<CreatedFeedToken>(`/api/playlists/${playlistId}/feed-tokens`, {
+        method: "POST",
+        body: JSON.stringify({ label: label.trim() }),
+      });
+      if (result) {
+        setTokens((prev) => [...prev, result]);
+        setCreated(result);
+      }
+      setLabel("");
+    } catch (err) {
+      showToast(err instanceof Error ? err.message : "Failed to add subscriber");
+    } finally {
+      setSaving(false);
+    }
+  }
+
+  async function revoke(tokenId: string) {
+    try {
+      await apiFetch(`/api/playlists/${playlistId}/feed-tokens/${tokenId}`, { method: "DELETE" });
+      setTokens((prev) => prev.filter((t) => t.id !== tokenId));
+      if (created?.id === tokenId) setCreated(null);
+      showToast("Subscriber removed");
+    } catch (err) {
+      showToast(err instanceof Error ? err.message : "Failed to remove subscriber");
+    }
+  }
+
+  return (
+    <div style={{ display: "flex", flexDirection: "column", gap: 8, marginTop: 8 }}>
+      <span style={{ fontSize: 13, color: "var(--color-text-secondary)" }}>
+        This playlist is protected, so each podcast subscriber needs their own feed link.
+      </span>
+      {tokens.map((token) => (
+        <div key={token.id} style={{ display: "flex", gap: 8, alignItems: "center", fontSize: 13 }}>
+          <span style={{ flex: 1 }}>{token.label}</span>
+          <span style={{ color: "var(--color-text-secondary)" }}>
+            {token.lastUsedAt ? `Last fetched ${new Date(token.lastUsedAt).toLocaleDateString()}` : "Never fetched"}
+          </span>
+          <button onClick={() => revoke(token.id)} className="detail-btn detail-btn--danger">
+            Revoke
+          </button>
+        </div>
+      ))}
+      {created && (
+        <div style={{ display: "flex", gap: 8, alignItems: "center", fontSize: 13 }}>
+          <span style={{ flex: 1, minWidth: 0, overflow: "hidden", textOverflow: "ellipsis" }}>
+            Feed link for {created.label} (shown once): {created.feedUrl}
+          </span>
+          <button
+            onClick={() => {
+              copyToClipboard(created.feedUrl);
+              showToast("Feed link copied");
+            }}
+            className="detail-btn"
+          >
+            Copy
+          </button>
+        </div>
+      )}
+      <div style={{ display: "flex", gap: 8 }}>
+        <input
+          type="text"
+          placeholder="Subscriber name"
+          value={label}
+          onChange={(e) => setLabel(e.target.value)}
+          maxLength={200}
+          aria-label="Subscriber name"
+          style={{
+            flex: 1,
+            padding: "6px 10px",
+            fontSize: 13,
+            background: "var(--color-bg)",
+            border: "1px solid var(--color-border)",
+            borderRadius: 4,
+            color: "var(--color-text)",
+          }}
+        />
+        <button
+          onClick={addSubscriber}
+          disabled={saving || !label.trim()}
+          className="detail-btn detail-btn--accent"
+        >
+          {saving ? "Adding..." : "Add subscriber"}
+        </button>
+      </div>
+    </div>
+  );
+}
@@ -1,15 +1,22 @@
 import { apiFetch } from "../../api/client";
 import { copyToClipboard } from "../../utils/clipboard";
 import { ConfirmDialogState } from "../../components/ConfirmDialog";
+import { GateFieldsEditor } from "../../components/GateFieldsEditor";
+import { PlaylistFeedSubscribers } from "./PlaylistFeedSubscribers";
+import type { GateField } from "../../types/video";
 
 interface PlaylistSharingData {
   isShared: boolean;
   shareToken?: string;
   shareUrl?: string;
+  feedUrl?: string;
   hasPassword: boolean;
   requireEmail: boolean;
+  gateFields?: GateField[];
 }
 
+const noGateFields: GateField[] = [];
+
 interface PlaylistSharingProps {
   playlistId: string;
   playlist: PlaylistSharingData;
@@ -53,6 +60,19 @@ export function PlaylistSharing({
     onPlaylistUpdate({ requireEmail: newValue });
   }
 
+  async function saveGateFields(fields: GateField[]) {
+    try {
+      await apiFetch(`/api/playlists/${playlistId}`, {
+        method: "PATCH",
+        body: JSON.stringify({ gateFields: fields }),
+      });
+      await onPlaylistRefresh();
+      showToast("Gate form saved");
+    } catch (err) {
+      showToast(err instanceof Error ? err.message : "Failed to save gate form");
+    }
+  }
+
   function setSharePassword() {
     setPromptDialog({
       title: "Enter a password for this playlist:",
@@ -204,6 +224,48 @@ export function PlaylistSharing({
               {playlist.requireEmail ? "Enabled" : "Disabled"}
             </button>
           </div>
+          {playlist.requireEmail && (
+            <GateFieldsEditor fields={playlist.gateFields ?? noGateFields} onSave={saveGateFields} />
+          )}
+
+          {playlist.feedUrl && (
+            <div className="detail-setting-row">
+              <span className="detail-setting-label">Podcast feed</span>
+              {playlist.hasPassword || playlist.requireEmail ? (
+                <PlaylistFeedSubscribers playlistId={playlistId} showToast={showToast} />
+              ) : (
+                <div
+                  style={{ display: "flex", gap: 8, flex: 1, minWidth: 0 }}
+                >
+                  <input
+                    type="text"
+                    readOnly
+                    value={playlist.feedUrl}
+                    aria-label="Podcast feed URL"
+                    style={{
+                      flex: 1,
+                      minWidth: 0,
+                      padding: "6px 10px",
+                      fontSize: 13,
+                      background: "var(--color-bg)",
+                      border: "1px solid var(--color-border)",
+                      borderRadius: 4,
+                      color: "var(--color-text)",
+                    }}
+                  />
+                  <button
+                    onClick={() => {
+                      copyToClipboard(playlist.feedUrl!);
+                      showToast("Feed link copied");
+                    }}
+                    className="detail-btn"
+                  >
+                    Copy feed
+                  </button>
+                </div>
+              )}
+            </div>
+          )}
         </>
       )}
     </div>
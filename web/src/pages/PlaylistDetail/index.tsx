@@ -8,6 +8,7 @@ import { useToast } from "../../hooks/useToast";
 import { PlaylistVideos } from "./PlaylistVideos";
 import type { PlaylistVideo } from "./PlaylistVideos";
 import { PlaylistSharing } from "./PlaylistSharing";
+import type { GateField } from "../../types/video";
 
 interface PlaylistData {
   id: string;
@@ -16,8 +17,10 @@ interface PlaylistData {
   isShared: boolean;
   shareToken?: string;
   shareUrl?: string;
+  feedUrl?: string;
   hasPassword: boolean;
   requireEmail: boolean;
+  gateFields?: GateField[];
   position: number;
   videoCount: number;
   videos: PlaylistVideo[];
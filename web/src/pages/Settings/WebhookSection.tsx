@@ -267,6 +267,7 @@ export function WebhookSection({
           <li><code>video.transcription.ready</code> — Transcription completed</li>
           <li><code>video.summary.ready</code> — AI summary completed</li>
           <li><code>video.cta.clicked</code> — A CTA button was clicked</li>
+          <li><code>viewer.identified</code> — A viewer passed an email gate, with their form answers</li>
           <li><code>test</code> — Test event from Settings</li>
         </ul>
       </details>
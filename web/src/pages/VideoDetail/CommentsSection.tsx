@@ -8,6 +8,19 @@ interface Comment {
   isOwner: boolean;
   createdAt: string;
   videoTimestamp: number | null;
+  parentId: string | null;
+  editedAt: string | null;
+  resolved: boolean;
+  mentions?: { handle: string; name: string }[];
+  annotation?: {
+    shapes: { type: "rect" | "arrow" | "path"; color?: string; points: [number, number][] }[];
+  };
+  videoReply?: {
+    id: string;
+    duration: number;
+    videoUrl?: string;
+    thumbnailUrl?: string;
+  };
 }
 
 function getInitials(name: string): string {
@@ -36,12 +49,14 @@ interface CommentsSectionProps {
   comments: Comment[];
   isViewer: boolean;
   onDeleteComment: (commentId: string) => void;
+  onToggleResolved: (commentId: string, resolved: boolean) => void;
 }
 
 export function CommentsSection({
   comments,
   isViewer,
   onDeleteComment,
+  onToggleResolved,
 }: CommentsSectionProps) {
   return (
     <div className="video-detail-section">
@@ -54,7 +69,10 @@ export function CommentsSection({
         </p>
       ) : (
         comments.map((comment) => (
-          <div key={comment.id} className="comment-item">
+          <div
+            key={comment.id}
+            className={comment.parentId ? "comment-item comment-reply" : "comment-item"}
+          >
             <div className="comment-avatar">
               {getInitials(comment.authorName)}
             </div>
@@ -74,9 +92,43 @@ export function CommentsSection({
                 {comment.isPrivate && (
                   <span className="comment-private">Private</span>
                 )}
+                {comment.editedAt && (
+                  <span className="comment-private">Edited</span>
+                )}
+                {comment.resolved && (
+                  <span className="comment-resolved">Resolved</span>
+                )}
+                {comment.annotation && (
+                  <span className="comment-private" title="Open the watch page to see the drawing">
+                    Drawing
+                  </span>
+                )}
               </div>
               <div className="comment-body">{comment.body}</div>
+              {comment.videoReply?.videoUrl && (
+                <video
+                  className="comment-video-reply"
+                  src={comment.videoReply.videoUrl}
+                  poster={comment.videoReply.thumbnailUrl}
+                  controls
+                  preload="none"
+                  style={{ display: "block", width: "100%", maxWidth: 360, marginTop: 6, borderRadius: 6, background: "#000" }}
+                />
+              )}
+              {comment.mentions && comment.mentions.length > 0 && (
+                <div className="comment-mentions">
+                  Mentioned {comment.mentions.map((m) => m.name).join(", ")}
+                </div>
+              )}
             </div>
+            {!isViewer && !comment.parentId && (
+              <button
+                className="comment-delete"
+                onClick={() => onToggleResolved(comment.id, !comment.resolved)}
+              >
+                {comment.resolved ? "Reopen" : "Resolve"}
+              </button>
+            )}
             {!isViewer && (
               <button
                 className="comment-delete"
@@ -0,0 +1,252 @@
+import { useEffect, useState } from "react";
+import { apiFetch } from "../../api/client";
+import { useToast } from "../../hooks/useToast";
+import { Toast } from "../../components/Toast";
+import type { VideoQuestion } from "../../types/video";
+import { formatDuration } from "../../utils/format";
+
+interface QuestionsSectionProps {
+  videoId: string;
+  duration: number;
+}
+
+interface QuestionForm {
+  time: string;
+  prompt: string;
+  options: string[];
+  // Index into options, or -1 for an ungraded poll.
+  correct: number;
+}
+
+const emptyForm: QuestionForm = {
+  time: "0",
+  prompt: "",
+  options: ["", ""],
+  correct: -1,
+};
+
+const inputStyle = {
+  padding: "8px 10px",
+  background: "var(--color-bg)",
+  border: "1px solid var(--color-border)",
+  borderRadius: 6,
+  color: "var(--color-text)",
+  fontSize: 13,
+};
+
+function formFromQuestion(q: VideoQuestion): QuestionForm {
+  return {
+    time: String(q.timeSeconds),
+    prompt: q.prompt,
+    options: [...q.options],
+    correct: q.correctOption ?? -1,
+  };
+}
+
+function requestFromForm(form: QuestionForm) {
+  return {
+    timeSeconds: Number(form.time) || 0,
+    prompt: form.prompt,
+    options: form.options,
+    correctOption: form.correct < 0 ? null : form.correct,
+  };
+}
+
+export function QuestionsSection({ videoId, duration }: QuestionsSectionProps) {
+  const toast = useToast();
+  const [questions, setQuestions] = useState<VideoQuestion[]>([]);
+  const [editingId, setEditingId] = useState<string | null>(null);
+  const [formOpen, setFormOpen] = useState(false);
+  const [form, setForm] = useState<QuestionForm>(emptyForm);
+
+  useEffect(() => {
+    apiFetch<VideoQuestion[]>(`/api/videos/${videoId}/questions`)
+      .then((result) => setQuestions(result ?? []))
+      .catch(() => setQuestions([]));
+  }, [videoId]);
+
+  function openForm(q: VideoQuestion | null) {
+    setEditingId(q?.id ?? null);
+    setForm(q ? formFromQuestion(q) : emptyForm);
+    setFormOpen(true);
+  }
+
+  function setOption(index: number, value: string) {
+    setForm((prev) => ({
+      ...prev,
+      options: prev.options.map((o, i) => (i === index ? value : o)),
+    }));
+  }
+
+  function removeOption(index: number) {
+    setForm((prev) => ({
+      ...prev,
+      options: prev.options.filter((_, i) => i !== index),
+      correct: prev.correct === index ? -1 : prev.correct > index ? prev.correct - 1 : prev.correct,
+    }));
+  }
+
+  async function save() {
+    try {
+      const body = JSON.stringify(requestFromForm(form));
+      const byTime = (a: VideoQuestion, b: VideoQuestion) => a.timeSeconds - b.timeSeconds;
+      if (editingId) {
+        const saved = await apiFetch<VideoQuestion>(`/api/videos/${videoId}/questions/${editingId}`, {
+          method: "PUT",
+          body,
+        });
+        if (saved) {
+          setQuestions((prev) => prev.map((q) => (q.id === saved.id ? saved : q)).sort(byTime));
+        }
+      } else {
+        const saved = await apiFetch<VideoQuestion>(`/api/videos/${videoId}/questions`, {
+          method: "POST",
+          body,
+        });
+        if (saved) {
+          setQuestions((prev) => [...prev, saved].sort(byTime));
+        }
+      }
+      setFormOpen(false);
+      toast.show("Question saved");
+    } catch (err) {
+      toast.show(err instanceof Error ? err.message : "Failed to save question");
+    }
+  }
+
+  async function remove(id: string) {
+    try {
+      await apiFetch(`/api/videos/${videoId}/questions/${id}`, { method: "DELETE" });
+      setQuestions((prev) => prev.filter((q) => q.id !== id));
+      if (editingId === id) setFormOpen(false);
+      toast.show("Question removed");
+    } catch (err) {
+      toast.show(err instanceof Error ? err.message : "Failed to remove question");
+    }
+  }
+
+  const canSave = form.prompt.trim() !== "" && form.options.every((o) => o.trim() !== "");
+
+  return (
+    <>
+      <div className="detail-setting-row">
+        <span className="detail-setting-label">Questions</span>
+        <div className="detail-setting-value">
+          <span>{questions.length === 0 ? "None" : `${questions.length} added`}</span>
+          <button onClick={() => openForm(null)} className="detail-btn">
+            Add question
+          </button>
+        </div>
+      </div>
+
+      {questions.map((q) => (
+        <div key={q.id} className="detail-setting-row">
+          <span className="detail-setting-label">
+            {q.correctOption === null ? "Poll" : "Quiz"} · {formatDuration(q.timeSeconds)}
+          </span>
+          <div className="detail-setting-value">
+            <span>{q.prompt}</span>
+            <button onClick={() => openForm(q)} className="detail-btn">
+              Edit
+            </button>
+            <button onClick={() => remove(q.id)} className="detail-btn detail-btn--danger">
+              Remove
+            </button>
+          </div>
+        </div>
+      ))}
+
+      {formOpen && (
+        <div
+          style={{
+            padding: 12,
+            background: "var(--color-surface)",
+            borderRadius: 8,
+            border: "1px solid var(--color-border)",
+            marginTop: 8,
+            display: "flex",
+            flexDirection: "column",
+            gap: 8,
+          }}
+        >
+          <textarea
+            placeholder="Question (e.g. Which plan includes SSO?)"
+            value={form.prompt}
+            onChange={(e) => setForm((prev) => ({ ...prev, prompt: e.target.value }))}
+            maxLength={500}
+            rows={2}
+            aria-label="Question prompt"
+            style={{ ...inputStyle, resize: "vertical" }}
+          />
+          {form.options.map((option, i) => (
+            <div key={i} style={{ display: "flex", gap: 8, alignItems: "center" }}>
+              <input
+                type="radio"
+                name="correct-option"
+                checked={form.correct === i}
+                onChange={() => setForm((prev) => ({ ...prev, correct: i }))}
+                aria-label={`Mark option ${i + 1} as correct`}
+                title="Correct answer"
+              />
+              <input
+                type="text"
+                placeholder={`Option ${i + 1}`}
+                value={option}
+                onChange={(e) => setOption(i, e.target.value)}
+                maxLength={200}
+                aria-label={`Option ${i + 1}`}
+                style={{ ...inputStyle, flex: 1 }}
+              />
+              {form.options.length > 2 && (
+                <button onClick={() => removeOption(i)} className="detail-btn" aria-label={`Remove option ${i + 1}`}>
+                  ×
+                </button>
+              )}
+            </div>
+          ))}
+          <div style={{ display: "flex", gap: 8, alignItems: "center" }}>
+            {form.options.length < 6 && (
+              <button
+                onClick={() => setForm((prev) => ({ ...prev, options: [...prev.options, ""] }))}
+                className="detail-btn"
+              >
+                Add option
+              </button>
+            )}
+            {form.correct >= 0 && (
+              <button onClick={() => setForm((prev) => ({ ...prev, correct: -1 }))} className="detail-btn">
+                Make it a poll
+              </button>
+            )}
+            <span style={{ fontSize: 13, color: "var(--color-text-secondary)" }}>
+              {form.correct >= 0 ? "Graded quiz question" : "Poll — select an option to grade it"}
+            </span>
+          </div>
+          <div style={{ display: "flex", gap: 8, alignItems: "center" }}>
+            <span style={{ fontSize: 13 }}>Pause at</span>
+            <input
+              type="number"
+              min={0}
+              max={duration || undefined}
+              step="0.1"
+              value={form.time}
+              onChange={(e) => setForm((prev) => ({ ...prev, time: e.target.value }))}
+              aria-label="Pause at (seconds)"
+              style={{ ...inputStyle, width: 110 }}
+            />
+            <span style={{ fontSize: 13, color: "var(--color-text-secondary)" }}>seconds</span>
+          </div>
+          <div style={{ display: "flex", gap: 8 }}>
+            <button onClick={save} disabled={!canSave} className="detail-btn detail-btn--accent">
+              Save
+            </button>
+            <button onClick={() => setFormOpen(false)} className="detail-btn">
+              Cancel
+            </button>
+          </div>
+        </div>
+      )}
+      <Toast message={toast.message} />
+    </>
+  );
+}
@@ -1,10 +1,11 @@
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import { apiFetch } from "../../api/client";
 import { useToast } from "../../hooks/useToast";
 import { Toast } from "../../components/Toast";
 import { PromptDialog } from "../../components/PromptDialog";
 import { ConfirmDialog, ConfirmDialogState } from "../../components/ConfirmDialog";
-import type { Video } from "../../types/video";
+import type { GateField, Video } from "../../types/video";
+import { GateFieldsEditor } from "../../components/GateFieldsEditor";
 import { expiryLabel } from "../../utils/format";
 import { copyToClipboard } from "../../utils/clipboard";
 import { LimitsResponse } from "../../types/limits";
@@ -57,6 +58,15 @@ export function SharingSection({
     submitLabel?: string;
   } | null>(null);
 
+  const [gateFields, setGateFields] = useState<GateField[]>([]);
+
+  useEffect(() => {
+    if (!video.emailGateEnabled || isViewer) return;
+    apiFetch<{ fields: GateField[] }>(`/api/videos/${video.id}/email-gate`)
+      .then((result) => setGateFields(result?.fields ?? []))
+      .catch(() => setGateFields([]));
+  }, [video.id, video.emailGateEnabled, isViewer]);
+
   const expiry = expiryLabel(video.shareExpiresAt);
   const embedSnippet = `<iframe src="${window.location.origin}/embed/${video.shareToken}" width="640" height="360" frameborder="0" allowfullscreen></iframe>`;
 
@@ -81,6 +91,17 @@ export function SharingSection({
     );
   }
 
+  async function toggleVideoReplies() {
+    const newValue = !video.videoRepliesEnabled;
+    await apiFetch(`/api/videos/${video.id}/video-replies`, {
+      method: "PUT",
+      body: JSON.stringify({ enabled: newValue }),
+    });
+    onVideoUpdate((prev) =>
+      prev ? { ...prev, videoRepliesEnabled: newValue } : prev,
+    );
+  }
+
   async function toggleEmailGate() {
     const newValue = !video.emailGateEnabled;
     await apiFetch(`/api/videos/${video.id}/email-gate`, {
@@ -92,6 +113,20 @@ export function SharingSection({
     );
   }
 
+  async function saveGateFields(fields: GateField[]) {
+    try {
+      await apiFetch(`/api/videos/${video.id}/email-gate`, {
+        method: "PUT",
+        body: JSON.stringify({ enabled: true, fields }),
+      });
+      const saved = await apiFetch<{ fields: GateField[] }>(`/api/videos/${video.id}/email-gate`);
+      setGateFields(saved?.fields ?? fields);
+      toast.show("Gate form saved");
+    } catch (err) {
+      toast.show(err instanceof Error ? err.message : "Failed to save gate form");
+    }
+  }
+
   async function toggleLinkExpiry() {
     const neverExpires = video.shareExpiresAt !== null;
     await apiFetch(`/api/videos/${video.id}/link-expiry`, {
@@ -394,6 +429,9 @@ export function SharingSection({
                 {video.emailGateEnabled ? "Enabled" : "Disabled"}
               </button>
             </div>
+            {video.emailGateEnabled && (
+              <GateFieldsEditor fields={gateFields} onSave={saveGateFields} />
+            )}
 
             <div className="detail-setting-row">
               <span className="detail-setting-label">Comments</span>
@@ -418,9 +456,22 @@ export function SharingSection({
                 <option value="anonymous">Anonymous</option>
                 <option value="name_required">Name required</option>
                 <option value="name_email_required">Name + email</option>
+                <option value="moderated">Moderated</option>
               </select>
             </div>
 
+            {video.commentMode !== "disabled" && (
+              <div className="detail-setting-row">
+                <span className="detail-setting-label">Video replies</span>
+                <button
+                  onClick={toggleVideoReplies}
+                  className={`detail-toggle${video.videoRepliesEnabled ? " detail-toggle--active" : ""}`}
+                >
+                  {video.videoRepliesEnabled ? "Enabled" : "Disabled"}
+                </button>
+              </div>
+            )}
+
             <div className="detail-setting-row">
               <span className="detail-setting-label">Thumbnail</span>
               <div className="detail-setting-value">
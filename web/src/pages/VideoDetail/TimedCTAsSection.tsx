@@ -0,0 +1,304 @@
+import { useEffect, useState } from "react";
+import { apiFetch } from "../../api/client";
+import { useToast } from "../../hooks/useToast";
+import { Toast } from "../../components/Toast";
+import type { VideoCTA, VideoCTAKind, VideoCTAPosition, VideoCTAStyle } from "../../types/video";
+import { formatDuration } from "../../utils/format";
+
+interface TimedCTAsSectionProps {
+  videoId: string;
+}
+
+interface CTAForm {
+  kind: VideoCTAKind;
+  text: string;
+  url: string;
+  start: string;
+  end: string;
+  position: VideoCTAPosition;
+  style: VideoCTAStyle;
+  // Hotspot region in percent of the frame, as typed by the user.
+  x: string;
+  y: string;
+  width: string;
+  height: string;
+}
+
+const emptyForm: CTAForm = {
+  kind: "button",
+  text: "",
+  url: "https://",
+  start: "0",
+  end: "",
+  position: "bottom-right",
+  style: "primary",
+  x: "40",
+  y: "40",
+  width: "20",
+  height: "20",
+};
+
+const inputStyle = {
+  padding: "8px 10px",
+  background: "var(--color-bg)",
+  border: "1px solid var(--color-border)",
+  borderRadius: 6,
+  color: "var(--color-text)",
+  fontSize: 13,
+};
+
+function formFromCTA(cta: VideoCTA): CTAForm {
+  return {
+    kind: cta.kind,
+    text: cta.text,
+    url: cta.url,
+    start: String(cta.startSeconds),
+    end: cta.endSeconds === null ? "" : String(cta.endSeconds),
+    position: cta.position,
+    style: cta.style,
+    x: String(Math.round((cta.region?.x ?? 0.4) * 100)),
+    y: String(Math.round((cta.region?.y ?? 0.4) * 100)),
+    width: String(Math.round((cta.region?.width ?? 0.2) * 100)),
+    height: String(Math.round((cta.region?.height ?? 0.2) * 100)),
+  };
+}
+
+function requestFromForm(form: CTAForm) {
+  return {
+    kind: form.kind,
+    text: form.text,
+    url: form.url,
+    startSeconds: Number(form.start) || 0,
+    endSeconds: form.end.trim() === "" ? null : Number(form.end),
+    position: form.position,
+    style: form.style,
+    region:
+      form.kind === "hotspot"
+        ? {
+            x: Number(form.x) / 100,
+            y: Number(form.y) / 100,
+            width: Number(form.width) / 100,
+            height: Number(form.height) / 100,
+          }
+        : null,
+  };
+}
+
+export function TimedCTAsSection({ videoId }: TimedCTAsSectionProps) {
+  const toast = useToast();
+  const [ctas, setCtas] = useState<VideoCTA[]>([]);
+  const [editingId, setEditingId] = useState<string | null>(null);
+  const [formOpen, setFormOpen] = useState(false);
+  const [form, setForm] = useState<CTAForm>(emptyForm);
+
+  useEffect(() => {
+    apiFetch<VideoCTA[]>(`/api/videos/${videoId}/ctas`)
+      .then((result) => setCtas(result ?? []))
+      .catch(() => setCtas([]));
+  }, [videoId]);
+
+  function update<K extends keyof CTAForm>(key: K, value: CTAForm[K]) {
+    setForm((prev) => ({ ...prev, [key]: value }));
+  }
+
+  function openForm(cta: VideoCTA | null) {
+    setEditingId(cta?.id ?? null);
+    setForm(cta ? formFromCTA(cta) : emptyForm);
+    setFormOpen(true);
+  }
+
+  async function save() {
+    try {
+      const body = JSON.stringify(requestFromForm(form));
+      if (editingId) {
+        const saved = await apiFetch<VideoCTA>(`/api/videos/${videoId}/ctas/${editingId}`, {
+          method: "PUT",
+          body,
+        });
+        if (saved) {
+          setCtas((prev) =>
+            prev.map((c) => (c.id === saved.id ? saved : c)).sort((a, b) => a.startSeconds - b.startSeconds),
+          );
+        }
+      } else {
+        const saved = await apiFetch<VideoCTA>(`/api/videos/${videoId}/ctas`, {
+          method: "POST",
+          body,
+        });
+        if (saved) {
+          setCtas((prev) => [...prev, saved].sort((a, b) => a.startSeconds - b.startSeconds));
+        }
+      }
+      setFormOpen(false);
+      toast.show("Timed CTA saved");
+    } catch (err) {
+      toast.show(err instanceof Error ? err.message : "Failed to save timed CTA");
+    }
+  }
+
+  async function remove(id: string) {
+    try {
+      await apiFetch(`/api/videos/${videoId}/ctas/${id}`, { method: "DELETE" });
+      setCtas((prev) => prev.filter((c) => c.id !== id));
+      if (editingId === id) setFormOpen(false);
+      toast.show("Timed CTA removed");
+    } catch (err) {
+      toast.show(err instanceof Error ? err.message : "Failed to remove timed CTA");
+    }
+  }
+
+  return (
+    <>
+      <div className="detail-setting-row">
+        <span className="detail-setting-label">Timed CTAs</span>
+        <div className="detail-setting-value">
+          <span>{ctas.length === 0 ? "None" : `${ctas.length} active`}</span>
+          <button onClick={() => openForm(null)} className="detail-btn">
+            Add timed CTA
+          </button>
+        </div>
+      </div>
+
+      {ctas.map((cta) => (
+        <div key={cta.id} className="detail-setting-row">
+          <span className="detail-setting-label">
+            {cta.kind === "hotspot" ? "Hotspot" : "Button"} · {formatDuration(cta.startSeconds)}
+            {cta.endSeconds !== null ? `–${formatDuration(cta.endSeconds)}` : "+"}
+          </span>
+          <div className="detail-setting-value">
+            <span>{cta.text || cta.url}</span>
+            <button onClick={() => openForm(cta)} className="detail-btn">
+              Edit
+            </button>
+            <button onClick={() => remove(cta.id)} className="detail-btn detail-btn--danger">
+              Remove
+            </button>
+          </div>
+        </div>
+      ))}
+
+      {formOpen && (
+        <div
+          style={{
+            padding: 12,
+            background: "var(--color-surface)",
+            borderRadius: 8,
+            border: "1px solid var(--color-border)",
+            marginTop: 8,
+            display: "flex",
+            flexDirection: "column",
+            gap: 8,
+          }}
+        >
+          <div style={{ display: "flex", gap: 8 }}>
+            <select
+              value={form.kind}
+              onChange={(e) => update("kind", e.target.value as VideoCTAKind)}
+              aria-label="CTA type"
+              style={inputStyle}
+            >
+              <option value="button">Button</option>
+              <option value="hotspot">Hotspot</option>
+            </select>
+            <select
+              value={form.style}
+              onChange={(e) => update("style", e.target.value as VideoCTAStyle)}
+              aria-label="CTA style"
+              style={inputStyle}
+            >
+              <option value="primary">Primary</option>
+              <option value="secondary">Secondary</option>
+              <option value="outline">Outline</option>
+            </select>
+            {form.kind === "button" && (
+              <select
+                value={form.position}
+                onChange={(e) => update("position", e.target.value as VideoCTAPosition)}
+                aria-label="CTA position"
+                style={inputStyle}
+              >
+                <option value="top-left">Top left</option>
+                <option value="top-right">Top right</option>
+                <option value="center">Center</option>
+                <option value="bottom-left">Bottom left</option>
+                <option value="bottom-right">Bottom right</option>
+              </select>
+            )}
+          </div>
+          <input
+            type="text"
+            placeholder={form.kind === "hotspot" ? "Label (optional)" : "Button text (e.g. Book a demo)"}
+            value={form.text}
+            onChange={(e) => update("text", e.target.value)}
+            maxLength={100}
+            aria-label="Timed CTA text"
+            style={inputStyle}
+          />
+          <input
+            type="url"
+            placeholder="URL (e.g. https://example.com/demo)"
+            value={form.url}
+            onChange={(e) => update("url", e.target.value)}
+            maxLength={2000}
+            aria-label="Timed CTA URL"
+            style={inputStyle}
+          />
+          <div style={{ display: "flex", gap: 8, alignItems: "center" }}>
+            <input
+              type="number"
+              min={0}
+              step="0.1"
+              value={form.start}
+              onChange={(e) => update("start", e.target.value)}
+              aria-label="Show from (seconds)"
+              style={{ ...inputStyle, width: 110 }}
+            />
+            <span style={{ fontSize: 13 }}>to</span>
+            <input
+              type="number"
+              min={0}
+              step="0.1"
+              placeholder="End of video"
+              value={form.end}
+              onChange={(e) => update("end", e.target.value)}
+              aria-label="Show until (seconds)"
+              style={{ ...inputStyle, width: 110 }}
+            />
+            <span style={{ fontSize: 13, color: "var(--color-text-secondary)" }}>seconds</span>
+          </div>
+          {form.kind === "hotspot" && (
+            <div style={{ display: "flex", gap: 8, alignItems: "center" }}>
+              {(["x", "y", "width", "height"] as const).map((key) => (
+                <input
+                  key={key}
+                  type="number"
+                  min={0}
+                  max={100}
+                  value={form[key]}
+                  onChange={(e) => update(key, e.target.value)}
+                  aria-label={`Hotspot ${key} (% of frame)`}
+                  title={`${key} (% of frame)`}
+                  style={{ ...inputStyle, width: 80 }}
+                />
+              ))}
+              <span style={{ fontSize: 13, color: "var(--color-text-secondary)" }}>% of frame</span>
+            </div>
+          )}
+          <div style={{ display: "flex", gap: 8 }}>
+            <button
+              onClick={save}
+              disabled={!form.url.trim() || (form.kind === "button" && !form.text.trim())}
+              className="detail-btn detail-btn--accent"
+            >
+              Save
+            </button>
+            <button onClick={() => setFormOpen(false)} className="detail-btn">
+              Cancel
+            </button>
+          </div>
+        </div>
+      )}
+      <Toast message={toast.message} />
+    </>
+  );
+}
@@ -17,6 +17,8 @@ import { copyToClipboard } from "../../utils/clipboard";
 import { SharingSection } from "./SharingSection";
 import { TranscriptSection } from "./TranscriptSection";
 import { CommentsSection } from "./CommentsSection";
+import { TimedCTAsSection } from "./TimedCTAsSection";
+import { QuestionsSection } from "./QuestionsSection";
 
 interface PlaylistInfo {
   id: string;
@@ -37,6 +39,13 @@ interface Comment {
   isOwner: boolean;
   createdAt: string;
   videoTimestamp: number | null;
+  parentId: string | null;
+  editedAt: string | null;
+  resolved: boolean;
+  mentions?: { handle: string; name: string }[];
+  annotation?: {
+    shapes: { type: "rect" | "arrow" | "path"; color?: string; points: [number, number][] }[];
+  };
 }
 
 interface CommentsResponse {
@@ -362,6 +371,19 @@ export function VideoDetail() {
     }
   }
 
+  async function handleToggleResolved(commentId: string, resolved: boolean) {
+    if (!video) return;
+    try {
+      await apiFetch(`/api/videos/${video.id}/comments/${commentId}/resolved`, {
+        method: "PUT",
+        body: JSON.stringify({ resolved }),
+      });
+      setComments((prev) => prev.map((c) => (c.id === commentId ? { ...c, resolved } : c)));
+    } catch {
+      // ignore
+    }
+  }
+
   if (loading) {
     return (
       <div className="page-container">
@@ -1077,6 +1099,13 @@ export function VideoDetail() {
             </div>
           </div>
         )}
+
+        <TimedCTAsSection videoId={video.id} />
+      </div>}
+
+      {!isViewer && <div className="video-detail-section">
+        <h2 className="video-detail-section-title">Quiz &amp; Polls</h2>
+        <QuestionsSection videoId={video.id} duration={video.duration} />
       </div>}
 
       {/* Comments */}
@@ -1084,6 +1113,7 @@ export function VideoDetail() {
         comments={comments}
         isViewer={isViewer}
         onDeleteComment={handleDeleteComment}
+        onToggleResolved={handleToggleResolved}
       />
 
       {/* Danger Zone */}
@@ -33,6 +33,7 @@ export interface Video {
   transcriptionLanguage?: string | null;
   noiseReduction?: boolean;
   pinned: boolean;
+  videoRepliesEnabled?: boolean;
   tags: VideoTag[];
   playlists?: { id: string; title: string }[];
 }
@@ -52,3 +53,37 @@ export interface Tag {
   videoCount: number;
   createdAt: string;
 }
+
+export type VideoCTAKind = "button" | "hotspot";
+export type VideoCTAPosition = "top-left" | "top-right" | "bottom-left" | "bottom-right" | "center";
+export type VideoCTAStyle = "primary" | "secondary" | "outline";
+
+export interface VideoCTA {
+  id: string;
+  kind: VideoCTAKind;
+  text: string;
+  url: string;
+  startSeconds: number;
+  endSeconds: number | null;
+  position: VideoCTAPosition;
+  style: VideoCTAStyle;
+  region: { x: number; y: number; width: number; height: number } | null;
+}
+
+export interface VideoQuestion {
+  id: string;
+  timeSeconds: number;
+  prompt: string;
+  options: string[];
+  correctOption: number | null;
+}
+
+export type GateFieldType = "text" | "textarea" | "select" | "checkbox";
+
+export interface GateField {
+  key: string;
+  type: GateFieldType;
+  label: string;
+  required: boolean;
+  options?: string[];
+}